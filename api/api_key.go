@@ -0,0 +1,203 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyResponse struct {
+	ID         int64        `json:"id"`
+	Owner      string       `json:"owner"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Scopes     []string     `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+func newAPIKeyResponse(apiKey db.ApiKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         apiKey.ID,
+		Owner:      apiKey.Owner,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		CreatedAt:  apiKey.CreatedAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+	}
+}
+
+// createAPIKeyResponse is the only time the plain key and its signing
+// secret are returned
+type createAPIKeyResponse struct {
+	Key           string         `json:"key"`
+	SigningSecret string         `json:"signing_secret"`
+	APIKey        apiKeyResponse `json:"api_key"`
+}
+
+type createAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,required"`
+}
+
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	for _, scope := range req.Scopes {
+		if !isGrantableScope(payload.Role, scope) {
+			err := fmt.Errorf("scope %s cannot be granted to an api key of a %s", scope, payload.Role)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	generated, err := token.NewAPIKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateApiKeyParams{
+		Owner:     payload.Username,
+		Name:      req.Name,
+		Prefix:    generated.Prefix,
+		HashedKey: generated.HashedKey,
+		Scopes:    req.Scopes,
+	}
+
+	apiKey, err := server.store.CreateApiKey(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createAPIKeyResponse{
+		Key:           generated.Key,
+		SigningSecret: server.apiSigner.SigningSecret(generated.HashedKey),
+		APIKey:        newAPIKeyResponse(apiKey),
+	})
+}
+
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	apiKeys, err := server.store.ListApiKeys(ctx, payload.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]apiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		rsp = append(rsp, newAPIKeyResponse(apiKey))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type apiKeyURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) rotateAPIKey(ctx *gin.Context) {
+	apiKey, ok := server.ownedAPIKey(ctx)
+	if !ok {
+		return
+	}
+
+	generated, err := token.NewAPIKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.RotateApiKeyParams{
+		ID:        apiKey.ID,
+		Prefix:    generated.Prefix,
+		HashedKey: generated.HashedKey,
+	}
+
+	apiKey, err = server.store.RotateApiKey(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("api key is revoked")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createAPIKeyResponse{
+		Key:           generated.Key,
+		SigningSecret: server.apiSigner.SigningSecret(generated.HashedKey),
+		APIKey:        newAPIKeyResponse(apiKey),
+	})
+}
+
+func (server *Server) revokeAPIKey(ctx *gin.Context) {
+	apiKey, ok := server.ownedAPIKey(ctx)
+	if !ok {
+		return
+	}
+
+	apiKey, err := server.store.RevokeApiKey(ctx, apiKey.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("api key is already revoked")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAPIKeyResponse(apiKey))
+}
+
+// ownedAPIKey loads the api key in the uri and checks it belongs to the authenticated user
+func (server *Server) ownedAPIKey(ctx *gin.Context) (db.ApiKey, bool) {
+	var uri apiKeyURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.ApiKey{}, false
+	}
+
+	apiKey, err := server.store.GetApiKey(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return apiKey, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return apiKey, false
+	}
+
+	if !authorizeOwner(ctx, apiKey.Owner, permissionWriteAny) {
+		return apiKey, false
+	}
+
+	return apiKey, true
+}
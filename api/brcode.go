@@ -0,0 +1,281 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"picpay_simplificado/brcode"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	brCodeFormatPayload = "payload"
+	brCodeFormatPNG     = "png"
+
+	// brCodePNGScale is the size in pixels of each module of the QR code
+	brCodePNGScale = 8
+)
+
+type brCodeResponse struct {
+	Payload string `json:"payload"`
+}
+
+type walletBRCodeRequest struct {
+	Amount      int64  `form:"amount" binding:"min=0"`
+	TxID        string `form:"txid" binding:"omitempty,max=25,alphanum"`
+	Description string `form:"description" binding:"max=40"`
+	Format      string `form:"format" binding:"omitempty,oneof=payload png"`
+}
+
+// getWalletBRCode returns a static BR Code paying into the wallet, keyed by
+// the CPF or CNPJ of its owner
+func (server *Server) getWalletBRCode(ctx *gin.Context) {
+	var uri getWalletRequest
+	var req walletBRCodeRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	wallet, err := server.store.GetWallet(ctx, uri.Id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, wallet.Owner, permissionReadAny) {
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, wallet.Owner)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	currency, ok := util.CurrencyNumericCode(wallet.Currency)
+	if !ok {
+		err := fmt.Errorf("unsupported currency %s", wallet.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.respondBRCode(ctx, req.Format, brcode.Payload{
+		PointOfInitiation: brcode.Static,
+		Key:               user.CpfCnpj,
+		Description:       req.Description,
+		Currency:          currency,
+		Amount:            req.Amount,
+		MerchantName:      user.FullName,
+		MerchantCity:      server.config.BRCodeMerchantCity,
+		TxID:              req.TxID,
+	})
+}
+
+type brCodeFormatRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=payload png"`
+}
+
+// getPaymentRequestBRCode returns a dynamic BR Code whose location points to
+// the payment request
+func (server *Server) getPaymentRequestBRCode(ctx *gin.Context) {
+	var req brCodeFormatRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, ok := server.visiblePaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if !authorizeOwner(ctx, request.Requester, permissionReadAny) {
+		return
+	}
+
+	if request.Status != db.PaymentRequestPending {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrPaymentRequestNotPending))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, request.Requester)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	currency, ok := util.CurrencyNumericCode(request.Currency)
+	if !ok {
+		err := fmt.Errorf("unsupported currency %s", request.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.respondBRCode(ctx, req.Format, brcode.Payload{
+		PointOfInitiation: brcode.Dynamic,
+		URL:               server.config.BRCodeLocationURL + strconv.FormatInt(request.ID, 10),
+		Currency:          currency,
+		Amount:            request.Amount,
+		MerchantName:      user.FullName,
+		MerchantCity:      server.config.BRCodeMerchantCity,
+	})
+}
+
+// respondBRCode writes the encoded payload, either as JSON or as a PNG image
+func (server *Server) respondBRCode(ctx *gin.Context, format string, payload brcode.Payload) {
+	code, err := brcode.Encode(payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if format != brCodeFormatPNG {
+		ctx.JSON(http.StatusOK, brCodeResponse{Payload: code})
+		return
+	}
+
+	symbol, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// a negative size scales each module instead of fixing the image size
+	image, err := symbol.PNG(-brCodePNGScale)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "image/png", image)
+}
+
+type brCodeTransferRequest struct {
+	Payload      string `json:"payload" binding:"required"`
+	FromWalletID int64  `json:"from_wallet_id" binding:"required,min=1"`
+	Amount       int64  `json:"amount" binding:"min=0"`
+}
+
+// createBRCodeTransfer pays a scanned BR Code. Dynamic payloads pay the
+// payment request they point to, static ones transfer to the wallet of the
+// key owner in the payload currency
+func (server *Server) createBRCodeTransfer(ctx *gin.Context) {
+	var req brCodeTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := brcode.Decode(req.Payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, ok := util.CurrencyFromNumericCode(payload.Currency)
+	if !ok {
+		err := fmt.Errorf("unsupported currency %s", payload.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if payload.Amount > 0 && req.Amount > 0 && payload.Amount != req.Amount {
+		err := errors.New("amount doesn't match the payload amount")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if payload.IsDynamic() {
+		server.payBRCodeLocation(ctx, payload, currency, req.FromWalletID)
+		return
+	}
+
+	amount := payload.Amount
+	if amount == 0 {
+		amount = req.Amount
+	}
+	if amount == 0 {
+		err := errors.New("amount is required when the payload has none")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByPixKey(ctx, payload.Key)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toWallet, err := server.store.GetWalletByOwnerAndCurrency(ctx, db.GetWalletByOwnerAndCurrencyParams{
+		Owner:    user.Username,
+		Currency: currency,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.transfer(ctx, req.FromWalletID, toWallet.ID, amount, currency)
+}
+
+// payBRCodeLocation pays the payment request a dynamic payload points to.
+// Only locations issued by this server are accepted
+func (server *Server) payBRCodeLocation(ctx *gin.Context, payload brcode.Payload, currency string, fromWalletID int64) {
+	location, found := strings.CutPrefix(payload.URL, server.config.BRCodeLocationURL)
+	id, err := strconv.ParseInt(location, 10, 64)
+	if !found || server.config.BRCodeLocationURL == "" || err != nil {
+		err := errors.New("payload location is not a payment request")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if request.Currency != currency || (payload.Amount > 0 && payload.Amount != request.Amount) {
+		err := errors.New("payload doesn't match the payment request")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.payPaymentRequest(ctx, request, fromWalletID)
+}
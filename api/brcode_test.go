@@ -0,0 +1,375 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"picpay_simplificado/brcode"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWalletBRCodeAPI(t *testing.T) {
+	user, _, err := randomUser(t)
+	require.NoError(t, err)
+
+	wallet := randomWallet()
+	wallet.Owner = user.Username
+	wallet.Currency = util.BRL
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			query:     "?amount=1050&txid=ORDER42",
+			setupAuth: authAs(user.Username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().GetUser(gomock.Any(), user.Username).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				payload := decodeBRCodeResponse(t, recorder)
+				require.False(t, payload.IsDynamic())
+				require.Equal(t, user.CpfCnpj, payload.Key)
+				require.Equal(t, "986", payload.Currency)
+				require.Equal(t, int64(1050), payload.Amount)
+				require.Equal(t, "ORDER42", payload.TxID)
+			},
+		},
+		{
+			name:      "PNG",
+			query:     "?format=png",
+			setupAuth: authAs(user.Username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().GetUser(gomock.Any(), user.Username).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+				require.True(t, bytes.HasPrefix(recorder.Body.Bytes(), []byte("\x89PNG")))
+			},
+		},
+		{
+			name:      "NotOwner",
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			setupAuth: authAs(user.Username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(db.Wallet{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidTxID",
+			query:     "?txid=not-valid",
+			setupAuth: authAs(user.Username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/wallets/%d/qr%s", wallet.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPaymentRequestBRCodeAPI(t *testing.T) {
+	request := randomPaymentRequest(util.BRL)
+	requester := db.User{Username: request.Requester, FullName: "Padaria São João"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+	store.EXPECT().GetUser(gomock.Any(), request.Requester).Times(1).Return(requester, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/payment-requests/%d/qr", request.ID)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	authAs(request.Requester, util.CustomerRole)(t, req, server.tokenMaker)
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	payload := decodeBRCodeResponse(t, recorder)
+	require.True(t, payload.IsDynamic())
+	require.Equal(t, fmt.Sprintf("%s%d", server.config.BRCodeLocationURL, request.ID), payload.URL)
+	require.Equal(t, request.Amount, payload.Amount)
+	require.Equal(t, "Padaria Sao Joao", payload.MerchantName)
+
+	// paid requests can't be charged again
+	request.Status = db.PaymentRequestAccepted
+	store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+
+	recorder = httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCreateBRCodeTransferAPI(t *testing.T) {
+	merchant, _, err := randomUser(t)
+	require.NoError(t, err)
+
+	from := randomWallet()
+	from.Currency = util.BRL
+
+	to := randomWallet()
+	to.ID = from.ID + 100
+	to.Owner = merchant.Username
+	to.Currency = util.BRL
+
+	request := randomPaymentRequest(util.BRL)
+	request.ToWalletID = to.ID
+
+	static := func(amount int64) string {
+		code, err := brcode.Encode(brcode.Payload{
+			Key:          merchant.CpfCnpj,
+			Currency:     "986",
+			Amount:       amount,
+			MerchantName: merchant.FullName,
+			MerchantCity: "SAO PAULO",
+		})
+		require.NoError(t, err)
+		return code
+	}
+
+	dynamic := func(location string, amount int64) string {
+		code, err := brcode.Encode(brcode.Payload{
+			PointOfInitiation: brcode.Dynamic,
+			URL:               location,
+			Currency:          "986",
+			Amount:            amount,
+			MerchantName:      merchant.FullName,
+			MerchantCity:      "SAO PAULO",
+		})
+		require.NoError(t, err)
+		return code
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Static",
+			body: gin.H{"payload": static(1500), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByPixKey(gomock.Any(), merchant.CpfCnpj).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					GetWalletByOwnerAndCurrency(gomock.Any(), db.GetWalletByOwnerAndCurrencyParams{Owner: merchant.Username, Currency: util.BRL}).
+					Times(1).
+					Return(to, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().GetWallet(gomock.Any(), to.ID).Times(1).Return(to, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TrasferTxParms) (db.TrasferTxResult, error) {
+						require.Equal(t, from.ID, arg.FromWalletID)
+						require.Equal(t, to.ID, arg.ToWalletID)
+						require.Equal(t, int64(1500), arg.Amount)
+						return db.TrasferTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "StaticWithoutAmount",
+			body: gin.H{"payload": static(0), "from_wallet_id": from.ID, "amount": 700},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByPixKey(gomock.Any(), merchant.CpfCnpj).Times(1).Return(merchant, nil)
+				store.EXPECT().GetWalletByOwnerAndCurrency(gomock.Any(), gomock.Any()).Times(1).Return(to, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().GetWallet(gomock.Any(), to.ID).Times(1).Return(to, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TrasferTxParms) (db.TrasferTxResult, error) {
+						require.Equal(t, int64(700), arg.Amount)
+						return db.TrasferTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingAmount",
+			body: gin.H{"payload": static(0), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AmountMismatch",
+			body: gin.H{"payload": static(1500), "from_wallet_id": from.ID, "amount": 1000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownKey",
+			body: gin.H{"payload": static(1500), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByPixKey(gomock.Any(), merchant.CpfCnpj).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCRC",
+			body: gin.H{"payload": static(1500)[:len(static(1500))-4] + "0000", "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByPixKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Dynamic",
+			body: gin.H{"payload": dynamic(fmt.Sprintf("pix.example.com/qr/v2/%d", request.ID), request.Amount), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AcceptPaymentRequestTxParams) (db.AcceptPaymentRequestTxResult, error) {
+						require.Equal(t, request.ID, arg.ID)
+						require.Equal(t, from.Owner, arg.Payer)
+						require.Equal(t, from.ID, arg.FromWalletID)
+						return db.AcceptPaymentRequestTxResult{PaymentRequest: request}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DynamicStale",
+			body: gin.H{"payload": dynamic(fmt.Sprintf("pix.example.com/qr/v2/%d", request.ID), request.Amount+1), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ForeignLocation",
+			body: gin.H{"payload": dynamic("pix.other-bank.com/v2/cobv/abc", 0), "from_wallet_id": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/qr", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			authAs(from.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func decodeBRCodeResponse(t *testing.T, recorder *httptest.ResponseRecorder) brcode.Payload {
+	var response brCodeResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+	payload, err := brcode.Decode(response.Payload)
+	require.NoError(t, err)
+
+	return payload
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cashback returns the params granting cashback on payments made at now, or
+// nil when no marketing owner is configured
+func (server *Server) cashback(now time.Time) *db.CashbackParams {
+	return db.NewCashbackParams(server.config.CashbackFundingOwner, now)
+}
+
+type createCashbackCampaignRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Currency string `json:"currency" binding:"required,currency"`
+	// Rate is in basis points of each qualifying payment
+	Rate             int64     `json:"rate" binding:"required,min=1,max=10000"`
+	MaxCashback      int64     `json:"max_cashback" binding:"min=0"`
+	MinAmount        int64     `json:"min_amount" binding:"min=0"`
+	MerchantCategory string    `json:"merchant_category" binding:"max=50"`
+	StartsAt         time.Time `json:"starts_at"`
+	EndsAt           time.Time `json:"ends_at" binding:"required"`
+	Budget           int64     `json:"budget" binding:"required,min=1"`
+	PerUserLimit     int64     `json:"per_user_limit" binding:"min=0"`
+	SettleDays       int32     `json:"settle_days" binding:"min=0,max=90"`
+}
+
+// createCashbackCampaign starts giving back part of the payments to
+// merchants that match the campaign, from the marketing wallet
+func (server *Server) createCashbackCampaign(ctx *gin.Context) {
+	var req createCashbackCampaignRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.StartsAt.IsZero() {
+		req.StartsAt = time.Now()
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		err := errors.New("ends_at must be after starts_at")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	campaign, err := server.store.CreateCashbackCampaign(ctx, db.CreateCashbackCampaignParams{
+		Name:             req.Name,
+		Currency:         req.Currency,
+		Rate:             req.Rate,
+		MaxCashback:      req.MaxCashback,
+		MinAmount:        req.MinAmount,
+		MerchantCategory: req.MerchantCategory,
+		StartsAt:         req.StartsAt,
+		EndsAt:           req.EndsAt,
+		Budget:           req.Budget,
+		PerUserLimit:     req.PerUserLimit,
+		SettleDays:       req.SettleDays,
+		CreatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+type listCashbackCampaignsRequest struct {
+	ActiveOnly bool  `form:"active_only"`
+	PageID     int32 `form:"page_id" binding:"required,min=1"`
+	PageSize   int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listCashbackCampaigns lists the campaigns, or only those running now
+func (server *Server) listCashbackCampaigns(ctx *gin.Context) {
+	var req listCashbackCampaignsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	campaigns, err := server.store.ListCashbackCampaigns(ctx, db.ListCashbackCampaignsParams{
+		ActiveOnly: req.ActiveOnly,
+		Now:        time.Now(),
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaigns)
+}
+
+type cashbackCampaignURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getCashbackCampaign(ctx *gin.Context) {
+	var uri cashbackCampaignURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	campaign, err := server.store.GetCashbackCampaign(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+// deactivateCashbackCampaign stops granting cashback, what was granted is
+// still paid
+func (server *Server) deactivateCashbackCampaign(ctx *gin.Context) {
+	var uri cashbackCampaignURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	campaign, err := server.store.DeactivateCashbackCampaign(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+type listCashbackRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listCashback lists the cashback granted to the payments of the wallet
+func (server *Server) listCashback(ctx *gin.Context) {
+	var req listCashbackRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	grants, err := server.store.ListWalletCashbackGrants(ctx, db.ListWalletCashbackGrantsParams{
+		WalletID: req.WalletID,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, grants)
+}
+
+type merchantCategoryURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+type updateMerchantCategoryRequest struct {
+	MerchantCategory string `json:"merchant_category" binding:"max=50"`
+}
+
+// updateMerchantCategory sets the category campaigns match merchants by
+func (server *Server) updateMerchantCategory(ctx *gin.Context) {
+	var uri merchantCategoryURI
+	var req updateMerchantCategoryRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserMerchantCategory(ctx, db.UpdateUserMerchantCategoryParams{
+		Username:         uri.Username,
+		MerchantCategory: req.MerchantCategory,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCashbackCampaignAPI(t *testing.T) {
+	startsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	endsAt := startsAt.Add(30 * 24 * time.Hour)
+
+	body := func(changes gin.H) gin.H {
+		body := gin.H{
+			"name":              "groceries",
+			"currency":          util.BRL,
+			"rate":              500,
+			"max_cashback":      2000,
+			"merchant_category": "groceries",
+			"starts_at":         startsAt,
+			"ends_at":           endsAt,
+			"budget":            100000,
+			"per_user_limit":    5000,
+		}
+		for key, value := range changes {
+			body[key] = value
+		}
+		return body
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body(nil),
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCashbackCampaign(gomock.Any(), gomock.Eq(db.CreateCashbackCampaignParams{
+						Name:             "groceries",
+						Currency:         util.BRL,
+						Rate:             500,
+						MaxCashback:      2000,
+						MerchantCategory: "groceries",
+						StartsAt:         startsAt,
+						EndsAt:           endsAt,
+						Budget:           100000,
+						PerUserLimit:     5000,
+						CreatedBy:        "admin",
+					})).
+					Times(1).
+					Return(db.CashbackCampaign{ID: 1, Name: "groceries", Active: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "EndsBeforeStart",
+			body:      body(gin.H{"ends_at": startsAt.Add(-time.Hour)}),
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCashbackCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidRate",
+			body:      body(gin.H{"rate": 20000}),
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCashbackCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NoBudget",
+			body:      body(gin.H{"budget": 0}),
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCashbackCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantCreates",
+			body:      body(nil),
+			setupAuth: authAs(util.RandomString(7), util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCashbackCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/cashback-campaigns", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeactivateCashbackCampaignAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeactivateCashbackCampaign(gomock.Any(), int64(7)).
+					Times(1).
+					Return(db.CashbackCampaign{ID: 7}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeactivateCashbackCampaign(gomock.Any(), int64(7)).
+					Times(1).
+					Return(db.CashbackCampaign{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerDeactivates",
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeactivateCashbackCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/cashback-campaigns/7/deactivate", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCashbackAPI(t *testing.T) {
+	wallet := randomWallet()
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			query:     fmt.Sprintf("wallet_id=%d&page_id=1&page_size=5", wallet.ID),
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().
+					ListWalletCashbackGrants(gomock.Any(), gomock.Eq(db.ListWalletCashbackGrantsParams{
+						WalletID: wallet.ID,
+						Limit:    5,
+						Offset:   0,
+					})).
+					Times(1).
+					Return([]db.CashbackGrant{{ID: 1, WalletID: wallet.ID, Amount: 50, Status: db.CashbackPaid}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var grants []db.CashbackGrant
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &grants))
+				require.Len(t, grants, 1)
+				require.Equal(t, int64(50), grants[0].Amount)
+			},
+		},
+		{
+			name:      "NotOwner",
+			query:     fmt.Sprintf("wallet_id=%d&page_id=1&page_size=5", wallet.ID),
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().ListWalletCashbackGrants(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/cashback?"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
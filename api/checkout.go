@@ -0,0 +1,540 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	checkoutSessionTokenPrefix = "cs_"
+	paymentLinkTokenPrefix     = "pl_"
+)
+
+var errPaymentLinkInactive = errors.New("payment link is no longer active")
+
+// checkoutSessionResponse is the session along with the URL customers pay it at
+type checkoutSessionResponse struct {
+	db.CheckoutSession
+	URL string `json:"url"`
+}
+
+func (server *Server) newCheckoutSessionResponse(session db.CheckoutSession) checkoutSessionResponse {
+	return checkoutSessionResponse{
+		CheckoutSession: session,
+		URL:             server.config.CheckoutURL + session.Token,
+	}
+}
+
+// paymentLinkResponse is the link along with the URL customers open it at
+type paymentLinkResponse struct {
+	db.PaymentLink
+	URL string `json:"url"`
+}
+
+func (server *Server) newPaymentLinkResponse(link db.PaymentLink) paymentLinkResponse {
+	return paymentLinkResponse{
+		PaymentLink: link,
+		URL:         server.config.PaymentLinkURL + link.Token,
+	}
+}
+
+type createCheckoutSessionRequest struct {
+	WalletID    int64             `json:"wallet_id" binding:"required,min=1"`
+	Amount      int64             `json:"amount" binding:"required,gt=0"`
+	Currency    string            `json:"currency" binding:"required,currency"`
+	Description string            `json:"description" binding:"max=140"`
+	SuccessUrl  string            `json:"success_url" binding:"omitempty,url"`
+	CancelUrl   string            `json:"cancel_url" binding:"omitempty,url"`
+	Metadata    map[string]string `json:"metadata" binding:"max=20"`
+	// ExpiresAt defaults to the configured session TTL from now
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createCheckoutSession lets a merchant charge a fixed amount once into one
+// of their wallets, through the URL in the response
+func (server *Server) createCheckoutSession(ctx *gin.Context) {
+	var req createCheckoutSessionRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = now.Add(server.config.CheckoutSessionTTL)
+	}
+
+	if !req.ExpiresAt.After(now) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateMerchantWallet(ctx, req.WalletID, req.Currency) {
+		return
+	}
+
+	checkoutToken, err := newCheckoutToken(checkoutSessionTokenPrefix)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	session, err := server.store.CreateCheckoutSession(ctx, db.CreateCheckoutSessionParams{
+		Token:            checkoutToken,
+		MerchantWalletID: req.WalletID,
+		Amount:           req.Amount,
+		Currency:         req.Currency,
+		Description:      req.Description,
+		SuccessUrl:       req.SuccessUrl,
+		CancelUrl:        req.CancelUrl,
+		Metadata:         checkoutMetadata(req.Metadata),
+		ExpiresAt:        req.ExpiresAt,
+		CreatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newCheckoutSessionResponse(session))
+}
+
+type listCheckoutRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listCheckoutSessions returns the sessions paid into the wallet, newest first
+func (server *Server) listCheckoutSessions(ctx *gin.Context) {
+	var req listCheckoutRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	sessions, err := server.store.ListWalletCheckoutSessions(ctx, db.ListWalletCheckoutSessionsParams{
+		MerchantWalletID: req.WalletID,
+		Limit:            req.PageSize,
+		Offset:           (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	responses := make([]checkoutSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = server.newCheckoutSessionResponse(session)
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+type checkoutTokenURI struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+// getCheckoutSession shows the session to whoever holds its token, which is
+// what customers open before paying
+func (server *Server) getCheckoutSession(ctx *gin.Context) {
+	var uri checkoutTokenURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.GetCheckoutSessionByToken(ctx, uri.Token)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newCheckoutSessionResponse(session))
+}
+
+type payCheckoutSessionRequest struct {
+	FromWalletID int64 `json:"from_wallet_id" binding:"required,min=1"`
+}
+
+// payCheckoutSession pays the session from a wallet of the authenticated
+// user. A session is only ever paid once
+func (server *Server) payCheckoutSession(ctx *gin.Context) {
+	var uri checkoutTokenURI
+	var req payCheckoutSessionRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.GetCheckoutSessionByToken(ctx, uri.Token)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.FromWalletID == session.MerchantWalletID {
+		err := errors.New("from wallet must not be the merchant wallet")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromWallet, valid := server.validateWallet(ctx, req.FromWalletID, session.Currency)
+	if !valid {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+
+	result, err := server.store.PayCheckoutSessionTx(ctx, db.PayCheckoutSessionTxParams{
+		Token:        session.Token,
+		Payer:        payload.Username,
+		FromWalletID: req.FromWalletID,
+		Now:          now,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk:     server.riskEngine,
+		Fees:     server.fees(now),
+		Cashback: server.cashback(now),
+	})
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrCheckoutSessionNotOpen) || errors.Is(err, db.ErrCheckoutSessionExpired) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Review != nil {
+		// the session is processing until the review is decided
+		ctx.JSON(http.StatusAccepted, result)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type createPaymentLinkRequest struct {
+	WalletID int64 `json:"wallet_id" binding:"required,min=1"`
+	// Amount is charged by every payment, customers choose how much to pay
+	// between MinAmount and MaxAmount when it is 0
+	Amount      int64             `json:"amount" binding:"min=0"`
+	MinAmount   int64             `json:"min_amount" binding:"min=0"`
+	MaxAmount   int64             `json:"max_amount" binding:"min=0"`
+	Currency    string            `json:"currency" binding:"required,currency"`
+	Description string            `json:"description" binding:"max=140"`
+	SuccessUrl  string            `json:"success_url" binding:"omitempty,url"`
+	CancelUrl   string            `json:"cancel_url" binding:"omitempty,url"`
+	Metadata    map[string]string `json:"metadata" binding:"max=20"`
+}
+
+// createPaymentLink creates a link customers can pay the merchant through
+// as many times as they like, each payment in its own checkout session
+func (server *Server) createPaymentLink(ctx *gin.Context) {
+	var req createPaymentLinkRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Amount > 0 && (req.MinAmount > 0 || req.MaxAmount > 0) {
+		err := errors.New("min_amount and max_amount are only allowed when customers choose the amount")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.MaxAmount > 0 && req.MaxAmount < req.MinAmount {
+		err := errors.New("max_amount must not be less than min_amount")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateMerchantWallet(ctx, req.WalletID, req.Currency) {
+		return
+	}
+
+	linkToken, err := newCheckoutToken(paymentLinkTokenPrefix)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	link, err := server.store.CreatePaymentLink(ctx, db.CreatePaymentLinkParams{
+		Token:            linkToken,
+		MerchantWalletID: req.WalletID,
+		Amount:           req.Amount,
+		MinAmount:        req.MinAmount,
+		MaxAmount:        req.MaxAmount,
+		Currency:         req.Currency,
+		Description:      req.Description,
+		SuccessUrl:       req.SuccessUrl,
+		CancelUrl:        req.CancelUrl,
+		Metadata:         checkoutMetadata(req.Metadata),
+		CreatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPaymentLinkResponse(link))
+}
+
+// listPaymentLinks returns the links paid into the wallet, newest first
+func (server *Server) listPaymentLinks(ctx *gin.Context) {
+	var req listCheckoutRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	links, err := server.store.ListWalletPaymentLinks(ctx, db.ListWalletPaymentLinksParams{
+		MerchantWalletID: req.WalletID,
+		Limit:            req.PageSize,
+		Offset:           (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	responses := make([]paymentLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = server.newPaymentLinkResponse(link)
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+func (server *Server) getPaymentLink(ctx *gin.Context) {
+	link, ok := server.paymentLinkFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPaymentLinkResponse(link))
+}
+
+type createPaymentLinkSessionRequest struct {
+	// Amount is only taken when customers choose the amount of the link
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// createPaymentLinkSession opens a checkout session for one payment through
+// the link, which the customer then pays like any other session
+func (server *Server) createPaymentLinkSession(ctx *gin.Context) {
+	var req createPaymentLinkSessionRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	link, ok := server.paymentLinkFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if !link.Active {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errPaymentLinkInactive))
+		return
+	}
+
+	amount := link.Amount
+	if amount == 0 {
+		if req.Amount <= 0 || req.Amount < link.MinAmount || (link.MaxAmount > 0 && req.Amount > link.MaxAmount) {
+			err := errors.New("amount is outside the range allowed by the payment link")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		amount = req.Amount
+	} else if req.Amount != 0 && req.Amount != link.Amount {
+		err := errors.New("amount is fixed by the payment link")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	checkoutToken, err := newCheckoutToken(checkoutSessionTokenPrefix)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	session, err := server.store.CreateCheckoutSession(ctx, db.CreateCheckoutSessionParams{
+		Token:            checkoutToken,
+		MerchantWalletID: link.MerchantWalletID,
+		PaymentLinkID:    sql.NullInt64{Int64: link.ID, Valid: true},
+		Amount:           amount,
+		Currency:         link.Currency,
+		Description:      link.Description,
+		SuccessUrl:       link.SuccessUrl,
+		CancelUrl:        link.CancelUrl,
+		Metadata:         link.Metadata,
+		ExpiresAt:        time.Now().Add(server.config.CheckoutSessionTTL),
+		CreatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newCheckoutSessionResponse(session))
+}
+
+// deactivatePaymentLink stops the link from opening new sessions, on behalf
+// of the merchant or an admin. Sessions already open can still be paid
+func (server *Server) deactivatePaymentLink(ctx *gin.Context) {
+	link, ok := server.paymentLinkFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	merchant, err := server.store.GetWallet(ctx, link.MerchantWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, merchant.Owner, permissionWriteAny) {
+		return
+	}
+
+	link, err = server.store.DeactivatePaymentLink(ctx, link.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPaymentLinkResponse(link))
+}
+
+func (server *Server) paymentLinkFromURI(ctx *gin.Context) (db.PaymentLink, bool) {
+	var uri checkoutTokenURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.PaymentLink{}, false
+	}
+
+	link, err := server.store.GetPaymentLinkByToken(ctx, uri.Token)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return link, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return link, false
+	}
+
+	return link, true
+}
+
+// validateMerchantWallet checks the wallet can be paid into in the currency
+// and belongs to the authenticated user
+func (server *Server) validateMerchantWallet(ctx *gin.Context, walletID int64, currency string) bool {
+	wallet, valid := server.validateWallet(ctx, walletID, currency)
+	if !valid {
+		return false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if wallet.Owner != payload.Username {
+		err := errors.New("wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+
+	return true
+}
+
+// newCheckoutToken returns a random token, hard to guess since holding it is
+// enough to see and pay what it points to
+func newCheckoutToken(prefix string) (string, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return prefix + hex.EncodeToString(token), nil
+}
+
+// checkoutMetadata is the metadata merchants attach to sessions and links,
+// stored as a JSON object
+func checkoutMetadata(metadata map[string]string) json.RawMessage {
+	if len(metadata) == 0 {
+		return json.RawMessage("{}")
+	}
+
+	// a map of strings always marshals
+	data, _ := json.Marshal(metadata)
+	return data
+}
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCheckoutSessionAPI(t *testing.T) {
+	merchant := randomWallet()
+
+	body := gin.H{
+		"wallet_id":   merchant.ID,
+		"amount":      2500,
+		"currency":    merchant.Currency,
+		"success_url": "https://shop.example.com/thanks",
+		"metadata":    gin.H{"order_id": "42"},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateCheckoutSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCheckoutSessionParams) (db.CheckoutSession, error) {
+						require.True(t, strings.HasPrefix(arg.Token, checkoutSessionTokenPrefix))
+						require.Equal(t, merchant.ID, arg.MerchantWalletID)
+						require.Equal(t, int64(2500), arg.Amount)
+						require.JSONEq(t, `{"order_id":"42"}`, string(arg.Metadata))
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Minute)
+						return db.CheckoutSession{Token: arg.Token, Status: db.CheckoutOpen}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := io.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var response checkoutSessionResponse
+				require.NoError(t, json.Unmarshal(data, &response))
+				require.Equal(t, "https://pay.example.com/checkout/"+response.Token, response.URL)
+			},
+		},
+		{
+			name:      "NotOwner",
+			body:      body,
+			setupAuth: authAs("someone", util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "ExpiredAlready",
+			body:      gin.H{"wallet_id": merchant.ID, "amount": 2500, "currency": merchant.Currency, "expires_at": time.Now().Add(-time.Hour)},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidSuccessURL",
+			body:      gin.H{"wallet_id": merchant.ID, "amount": 2500, "currency": merchant.Currency, "success_url": "thanks"},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/checkout-sessions", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayCheckoutSessionAPI(t *testing.T) {
+	customer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = customer.ID + 100
+	merchant.Currency = customer.Currency
+
+	session := db.CheckoutSession{
+		ID:               util.RandomInt(1, 1000),
+		Token:            checkoutSessionTokenPrefix + util.RandomString(48),
+		MerchantWalletID: merchant.ID,
+		Amount:           2500,
+		Currency:         customer.Currency,
+		Status:           db.CheckoutOpen,
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		fromWalletID  int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "OK",
+			fromWalletID: customer.ID,
+			setupAuth:    authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(session, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().
+					PayCheckoutSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.PayCheckoutSessionTxParams) (db.PayCheckoutSessionTxResult, error) {
+						require.Equal(t, session.Token, arg.Token)
+						require.Equal(t, customer.Owner, arg.Payer)
+						require.Equal(t, customer.ID, arg.FromWalletID)
+						require.NotNil(t, arg.Limits)
+						return db.PayCheckoutSessionTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:         "AlreadyPaid",
+			fromWalletID: customer.ID,
+			setupAuth:    authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(session, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().
+					PayCheckoutSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayCheckoutSessionTxResult{}, db.ErrCheckoutSessionNotOpen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "HeldForReview",
+			fromWalletID: customer.ID,
+			setupAuth:    authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(session, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().
+					PayCheckoutSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayCheckoutSessionTxResult{TrasferTxResult: db.TrasferTxResult{Review: &db.TransferReview{ID: 1}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:         "NotWalletOwner",
+			fromWalletID: customer.ID,
+			setupAuth:    authAs("someone", util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(session, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().PayCheckoutSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:         "MerchantWallet",
+			fromWalletID: merchant.ID,
+			setupAuth:    authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(session, nil)
+				store.EXPECT().PayCheckoutSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "NotFound",
+			fromWalletID: customer.ID,
+			setupAuth:    authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCheckoutSessionByToken(gomock.Any(), session.Token).Times(1).Return(db.CheckoutSession{}, sql.ErrNoRows)
+				store.EXPECT().PayCheckoutSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"from_wallet_id": tc.fromWalletID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/checkout/%s/pay", session.Token)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreatePaymentLinkSessionAPI(t *testing.T) {
+	customer := util.RandomString(10)
+
+	fixed := db.PaymentLink{
+		ID:               util.RandomInt(1, 1000),
+		Token:            paymentLinkTokenPrefix + util.RandomString(48),
+		MerchantWalletID: util.RandomInt(1, 1000),
+		Amount:           5000,
+		Currency:         util.RandomCurrency(),
+		Metadata:         json.RawMessage(`{}`),
+		Active:           true,
+	}
+
+	open := fixed
+	open.Amount = 0
+	open.MinAmount = 1000
+	open.MaxAmount = 9000
+
+	inactive := fixed
+	inactive.Active = false
+
+	testCases := []struct {
+		name          string
+		link          db.PaymentLink
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore, link db.PaymentLink)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FixedAmount",
+			link: fixed,
+			buildStubs: func(store *mockdb.MockStore, link db.PaymentLink) {
+				store.EXPECT().GetPaymentLinkByToken(gomock.Any(), link.Token).Times(1).Return(link, nil)
+				store.EXPECT().
+					CreateCheckoutSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCheckoutSessionParams) (db.CheckoutSession, error) {
+						require.Equal(t, link.ID, arg.PaymentLinkID.Int64)
+						require.Equal(t, link.MerchantWalletID, arg.MerchantWalletID)
+						require.Equal(t, int64(5000), arg.Amount)
+						require.Equal(t, customer, arg.CreatedBy)
+						return db.CheckoutSession{Token: arg.Token}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ChosenAmount",
+			link:   open,
+			amount: 4200,
+			buildStubs: func(store *mockdb.MockStore, link db.PaymentLink) {
+				store.EXPECT().GetPaymentLinkByToken(gomock.Any(), link.Token).Times(1).Return(link, nil)
+				store.EXPECT().
+					CreateCheckoutSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCheckoutSessionParams) (db.CheckoutSession, error) {
+						require.Equal(t, int64(4200), arg.Amount)
+						return db.CheckoutSession{Token: arg.Token}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ChosenAmountTooHigh",
+			link:   open,
+			amount: 10000,
+			buildStubs: func(store *mockdb.MockStore, link db.PaymentLink) {
+				store.EXPECT().GetPaymentLinkByToken(gomock.Any(), link.Token).Times(1).Return(link, nil)
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "DifferentFixedAmount",
+			link:   fixed,
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore, link db.PaymentLink) {
+				store.EXPECT().GetPaymentLinkByToken(gomock.Any(), link.Token).Times(1).Return(link, nil)
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Inactive",
+			link: inactive,
+			buildStubs: func(store *mockdb.MockStore, link db.PaymentLink) {
+				store.EXPECT().GetPaymentLinkByToken(gomock.Any(), link.Token).Times(1).Return(link, nil)
+				store.EXPECT().CreateCheckoutSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tc.link)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"amount": tc.amount})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/payment-links/%s/sessions", tc.link.Token)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			authAs(customer, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"picpay_simplificado/blob"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	blobBackendLocal = "local"
+
+	// defaultEvidenceMaxSize is used when DISPUTE_EVIDENCE_MAX_SIZE isn't set
+	defaultEvidenceMaxSize = 5 << 20
+	// defaultDisputeRateWindow is how far back the dispute rate looks by default
+	defaultDisputeRateWindow = 90 * 24 * time.Hour
+)
+
+func (server *Server) setupBlobs() error {
+	switch server.config.BlobBackend {
+	case "", blobBackendLocal:
+		blobs, err := blob.NewLocalStore(server.config.BlobLocalDir)
+		if err != nil {
+			return err
+		}
+		server.blobs = blobs
+	default:
+		return fmt.Errorf("unsupported blob backend %q", server.config.BlobBackend)
+	}
+
+	return nil
+}
+
+type openDisputeRequest struct {
+	Amount int64  `json:"amount" binding:"required,gt=0"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// openDispute lets the payer of a transfer dispute it with the merchant that
+// received it, within the dispute window
+func (server *Server) openDispute(ctx *gin.Context) {
+	var uri transferURI
+	var req openDisputeRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payer, err := server.store.GetWallet(ctx, transfer.FromWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != payer.Owner {
+		err := errors.New("only the payer can dispute the transfer")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if window := server.config.DisputeOpenWindow; window > 0 && time.Since(transfer.CreatedAt) > window {
+		err := errors.New("transfer can no longer be disputed")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dispute, err := server.store.OpenDisputeTx(ctx, db.OpenDisputeTxParams{
+		TransferID: transfer.ID,
+		OpenedBy:   payload.Username,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		RespondBy:  time.Now().Add(server.config.DisputeResponseWindow),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrDisputeExceedsTransfer) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dispute)
+}
+
+type listDisputesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listDisputes returns the disputes the user opened or received, newest first
+func (server *Server) listDisputes(ctx *gin.Context) {
+	var req listDisputesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	disputes, err := server.store.ListUserDisputes(ctx, db.ListUserDisputesParams{
+		Username:   payload.Username,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, disputes)
+}
+
+type listDisputeQueueRequest struct {
+	Status   string `form:"status" binding:"required,oneof=open under_review payer_won merchant_won"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listDisputeQueue returns the disputes in a status, oldest first, so
+// analysts can work through the ones under review
+func (server *Server) listDisputeQueue(ctx *gin.Context) {
+	var req listDisputeQueueRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	disputes, err := server.store.ListDisputesByStatus(ctx, db.ListDisputesByStatusParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, disputes)
+}
+
+type disputeURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getDispute(ctx *gin.Context) {
+	dispute, ok := server.disputeFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dispute)
+}
+
+// uploadDisputeEvidence attaches a file to a dispute that wasn't decided yet,
+// either party can upload
+func (server *Server) uploadDisputeEvidence(ctx *gin.Context) {
+	dispute, ok := server.disputeFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != dispute.OpenedBy && payload.Username != dispute.Merchant {
+		err := errors.New("only the parties can upload evidence")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if dispute.Status != db.DisputeOpen && dispute.Status != db.DisputeUnderReview {
+		err := errors.New("dispute was already decided")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	maxSize := server.config.DisputeEvidenceMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultEvidenceMaxSize
+	}
+
+	if file.Size > maxSize {
+		err := fmt.Errorf("evidence must be at most %d bytes", maxSize)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer reader.Close()
+
+	key, err := blob.NewKey("disputes/" + strconv.FormatInt(dispute.ID, 10))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	size, err := server.blobs.Put(ctx, key, reader)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	evidence, err := server.store.CreateDisputeEvidence(ctx, db.CreateDisputeEvidenceParams{
+		DisputeID:   dispute.ID,
+		UploadedBy:  payload.Username,
+		FileName:    file.Filename,
+		ContentType: contentType,
+		Size:        size,
+		BlobKey:     key,
+	})
+
+	if err != nil {
+		server.blobs.Delete(ctx, key)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evidence)
+}
+
+func (server *Server) listDisputeEvidence(ctx *gin.Context) {
+	dispute, ok := server.disputeFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	evidence, err := server.store.ListDisputeEvidence(ctx, dispute.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evidence)
+}
+
+type disputeEvidenceURI struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	EvidenceID int64 `uri:"evidence_id" binding:"required,min=1"`
+}
+
+// downloadDisputeEvidence streams an evidence file back as an attachment
+func (server *Server) downloadDisputeEvidence(ctx *gin.Context) {
+	var uri disputeEvidenceURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dispute, ok := server.disputeFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	evidence, err := server.store.GetDisputeEvidence(ctx, db.GetDisputeEvidenceParams{
+		ID:        uri.EvidenceID,
+		DisputeID: dispute.ID,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reader, err := server.blobs.Open(ctx, evidence.BlobKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer reader.Close()
+
+	ctx.DataFromReader(http.StatusOK, evidence.Size, evidence.ContentType, reader, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": evidence.FileName}),
+	})
+}
+
+type respondDisputeRequest struct {
+	Response string `json:"response" binding:"required,max=2000"`
+}
+
+// respondDispute records the merchant side of the dispute and sends it to
+// review, only the merchant can respond and only before the deadline
+func (server *Server) respondDispute(ctx *gin.Context) {
+	var req respondDisputeRequest
+
+	dispute, ok := server.disputeFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != dispute.Merchant {
+		err := errors.New("only the merchant can respond to the dispute")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	dispute, err := server.store.RespondDisputeTx(ctx, db.RespondDisputeTxParams{
+		ID:       dispute.ID,
+		Response: req.Response,
+		Now:      time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrDisputeNotOpen) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dispute)
+}
+
+type decideDisputeRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=payer merchant"`
+	Note    string `json:"note" binding:"required,max=500"`
+}
+
+// decideDispute lets an analyst close a dispute under review. Deciding for
+// the payer reverses the disputed amount from the merchant
+func (server *Server) decideDispute(ctx *gin.Context) {
+	var uri disputeURI
+	var req decideDisputeRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.DecideDisputeTx(ctx, db.DecideDisputeTxParams{
+		ID:              uri.ID,
+		PayerWins:       req.Outcome == "payer",
+		DecidedBy:       payload.Username,
+		Note:            req.Note,
+		NegativeReserve: server.config.DisputeNegativeReserve,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrDisputeNotUnderReview) || errors.Is(err, db.ErrReserveExceeded) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type merchantURI struct {
+	Username string `uri:"id" binding:"required,min=1"`
+}
+
+type disputeRateRequest struct {
+	Since time.Time `form:"since" time_format:"2006-01-02"`
+}
+
+// getMerchantDisputeRate compares the disputes opened against a merchant to
+// the transfers it received since a day, the last 90 days by default
+func (server *Server) getMerchantDisputeRate(ctx *gin.Context) {
+	var uri merchantURI
+	var req disputeRateRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionDisputesRead) {
+		return
+	}
+
+	if req.Since.IsZero() {
+		req.Since = time.Now().Add(-defaultDisputeRateWindow)
+	}
+
+	rate, err := server.store.MerchantDisputeRate(ctx, uri.Username, req.Since)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rate)
+}
+
+// disputeFromURI reads the dispute, letting only its parties and analysts through
+func (server *Server) disputeFromURI(ctx *gin.Context) (db.Dispute, bool) {
+	var uri disputeURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Dispute{}, false
+	}
+
+	dispute, err := server.store.GetDispute(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return dispute, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return dispute, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != dispute.Merchant && !authorizeOwner(ctx, dispute.OpenedBy, permissionDisputesRead) {
+		return dispute, false
+	}
+
+	return dispute, true
+}
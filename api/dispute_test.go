@@ -0,0 +1,362 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDisputeAPI(t *testing.T) {
+	payer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = payer.ID + 100
+	transfer := db.Transfer{ID: util.RandomInt(1, 1000), FromWalletID: payer.ID, ToWalletID: merchant.ID, Amount: 800, CreatedAt: time.Now()}
+	oldTransfer := transfer
+	oldTransfer.CreatedAt = time.Now().Add(-100 * 24 * time.Hour)
+
+	body := gin.H{"amount": 800, "reason": "product never delivered"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(payer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), transfer.ID).Times(1).Return(transfer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), payer.ID).Times(1).Return(payer, nil)
+				store.EXPECT().
+					OpenDisputeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.OpenDisputeTxParams) (db.Dispute, error) {
+						require.Equal(t, transfer.ID, arg.TransferID)
+						require.Equal(t, payer.Owner, arg.OpenedBy)
+						require.Equal(t, int64(800), arg.Amount)
+						require.WithinDuration(t, time.Now().Add(7*24*time.Hour), arg.RespondBy, time.Minute)
+						return db.Dispute{ID: 1, TransferID: transfer.ID, Status: db.DisputeOpen}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantOpens",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), transfer.ID).Times(1).Return(transfer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), payer.ID).Times(1).Return(payer, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "WindowClosed",
+			body:      body,
+			setupAuth: authAs(payer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), transfer.ID).Times(1).Return(oldTransfer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), payer.ID).Times(1).Return(payer, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "ExceedsTransfer",
+			body:      gin.H{"amount": 900, "reason": "charged twice"},
+			setupAuth: authAs(payer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), transfer.ID).Times(1).Return(transfer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), payer.ID).Times(1).Return(payer, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{}, db.ErrDisputeExceedsTransfer)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MissingReason",
+			body:      gin.H{"amount": 800},
+			setupAuth: authAs(payer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/transfers/%d/disputes", transfer.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisputeActionsAPI(t *testing.T) {
+	dispute := db.Dispute{
+		ID:        util.RandomInt(1, 1000),
+		OpenedBy:  util.RandomString(7),
+		Merchant:  util.RandomString(7),
+		Amount:    800,
+		Status:    db.DisputeOpen,
+		RespondBy: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		action        string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "MerchantResponds",
+			action:    "respond",
+			body:      gin.H{"response": "tracking code shows it was delivered"},
+			setupAuth: authAs(dispute.Merchant, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), dispute.ID).Times(1).Return(dispute, nil)
+				store.EXPECT().
+					RespondDisputeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RespondDisputeTxParams) (db.Dispute, error) {
+						require.Equal(t, dispute.ID, arg.ID)
+						require.Equal(t, "tracking code shows it was delivered", arg.Response)
+						return db.Dispute{ID: dispute.ID, Status: db.DisputeUnderReview}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "PayerResponds",
+			action:    "respond",
+			body:      gin.H{"response": "me again"},
+			setupAuth: authAs(dispute.OpenedBy, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), dispute.ID).Times(1).Return(dispute, nil)
+				store.EXPECT().RespondDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "RespondOverdue",
+			action:    "respond",
+			body:      gin.H{"response": "late"},
+			setupAuth: authAs(dispute.Merchant, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), dispute.ID).Times(1).Return(dispute, nil)
+				store.EXPECT().RespondDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{}, db.ErrDisputeNotOpen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "SupportDecidesForPayer",
+			action:    "decide",
+			body:      gin.H{"outcome": "payer", "note": "no proof of delivery"},
+			setupAuth: authAs("analyst", util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideDisputeTx(gomock.Any(), gomock.Eq(db.DecideDisputeTxParams{
+						ID:              dispute.ID,
+						PayerWins:       true,
+						DecidedBy:       "analyst",
+						Note:            "no proof of delivery",
+						NegativeReserve: 1000,
+					})).
+					Times(1).
+					Return(db.DecideDisputeTxResult{Dispute: db.Dispute{ID: dispute.ID, Status: db.DisputePayerWon}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "DecideReserveExceeded",
+			action:    "decide",
+			body:      gin.H{"outcome": "payer", "note": "no proof of delivery"},
+			setupAuth: authAs("analyst", util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideDisputeTxResult{}, db.ErrReserveExceeded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantDecides",
+			action:    "decide",
+			body:      gin.H{"outcome": "merchant", "note": "obviously"},
+			setupAuth: authAs(dispute.Merchant, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/disputes/%d/%s", dispute.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisputeEvidenceAPI(t *testing.T) {
+	dispute := db.Dispute{
+		ID:       util.RandomInt(1, 1000),
+		OpenedBy: util.RandomString(7),
+		Merchant: util.RandomString(7),
+		Status:   db.DisputeOpen,
+	}
+	content := []byte("%PDF-1.4 receipt")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	var evidence db.DisputeEvidence
+	store.EXPECT().GetDispute(gomock.Any(), dispute.ID).Times(2).Return(dispute, nil)
+	store.EXPECT().
+		CreateDisputeEvidence(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateDisputeEvidenceParams) (db.DisputeEvidence, error) {
+			require.Equal(t, dispute.OpenedBy, arg.UploadedBy)
+			require.Equal(t, "receipt.pdf", arg.FileName)
+			require.Equal(t, int64(len(content)), arg.Size)
+			evidence = db.DisputeEvidence{
+				ID:          1,
+				DisputeID:   arg.DisputeID,
+				FileName:    arg.FileName,
+				ContentType: arg.ContentType,
+				Size:        arg.Size,
+				BlobKey:     arg.BlobKey,
+			}
+			return evidence, nil
+		})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "receipt.pdf")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/disputes/%d/evidence", dispute.ID), body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	authAs(dispute.OpenedBy, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	store.EXPECT().
+		GetDisputeEvidence(gomock.Any(), gomock.Eq(db.GetDisputeEvidenceParams{ID: 1, DisputeID: dispute.ID})).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ db.GetDisputeEvidenceParams) (db.DisputeEvidence, error) {
+			return evidence, nil
+		})
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("/disputes/%d/evidence/1", dispute.ID), nil)
+	require.NoError(t, err)
+
+	authAs(dispute.Merchant, util.MerchantRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Disposition"), "receipt.pdf")
+
+	downloaded, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("/disputes/%d/evidence/1", dispute.ID), nil)
+	require.NoError(t, err)
+
+	store.EXPECT().GetDispute(gomock.Any(), dispute.ID).Times(1).Return(dispute, nil)
+	store.EXPECT().GetDisputeEvidence(gomock.Any(), gomock.Any()).Times(0)
+
+	authAs(util.RandomString(7), util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
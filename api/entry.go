@@ -31,14 +31,20 @@ func (server *Server) getEntry(ctx *gin.Context) {
 		return
 	}
 
+	if !server.authorizeWallet(ctx, entry.WalletID, permissionReadAny) {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, entry)
 }
 
 type createEntryRequest struct {
-	WalletID int64 `json:"wallet_id" binding:"required"`
-	Amount   int64 `json:"amount" binding:"required"`
+	WalletID    int64  `json:"wallet_id" binding:"required"`
+	Amount      int64  `json:"amount" binding:"required"`
+	Description string `json:"description" binding:"max=140"`
 }
 
+// createEntry deposits or withdraws money through the bank settlement account
 func (server *Server) createEntry(ctx *gin.Context) {
 	var req createEntryRequest
 
@@ -47,19 +53,25 @@ func (server *Server) createEntry(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreateEntryParams{
-		WalletID: req.WalletID,
-		Amount:   req.Amount,
+	arg := db.AdjustWalletTxParams{
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		Account:     db.AccountBankSettlement,
+		Description: req.Description,
 	}
 
-	entry, err := server.store.CreateEntry(ctx, arg)
+	result, err := server.store.AdjustWalletTx(ctx, arg)
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entry)
+	ctx.JSON(http.StatusOK, result.Entry)
 }
 
 type listEntriesRequest struct {
@@ -73,6 +85,11 @@ func (server *Server) listEntries(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
 	}
 
 	arg := db.ListEntriesParams{
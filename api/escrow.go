@@ -0,0 +1,321 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createEscrowRequest struct {
+	FromWalletID int64  `json:"from_wallet_id" binding:"required,min=1"`
+	ToWalletID   int64  `json:"to_wallet_id" binding:"required,min=1"`
+	Amount       int64  `json:"amount" binding:"required,gt=0"`
+	Currency     string `json:"currency" binding:"required,currency"`
+	Description  string `json:"description" binding:"max=140"`
+}
+
+// createEscrow pays the seller through escrow, the amount leaves the buyer
+// now and reaches the seller once the buyer confirms or the escrow times out
+func (server *Server) createEscrow(ctx *gin.Context) {
+	var req createEscrowRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.FromWalletID == req.ToWalletID {
+		err := errors.New("seller wallet must not be the buyer wallet")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateTransferWallets(ctx, req.FromWalletID, req.ToWalletID, req.Currency) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	now := time.Now()
+
+	result, err := server.store.CreateEscrowTx(ctx, db.CreateEscrowTxParams{
+		BuyerWalletID:  req.FromWalletID,
+		SellerWalletID: req.ToWalletID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		ReleaseAt:      now.Add(server.config.EscrowReleaseAfter),
+		CreatedBy:      payload.Username,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk: server.riskEngine,
+	})
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listEscrowsRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listEscrows returns the escrows the wallet bought or sold through, newest first
+func (server *Server) listEscrows(ctx *gin.Context) {
+	var req listEscrowsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	wallet, err := server.store.GetWallet(ctx, req.WalletID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, wallet.Owner, permissionReadAny) {
+		return
+	}
+
+	escrows, err := server.store.ListWalletEscrows(ctx, db.ListWalletEscrowsParams{
+		WalletID:   wallet.ID,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, escrows)
+}
+
+type escrowURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// escrowParties is the escrow along with who bought and who sold
+type escrowParties struct {
+	escrow db.Escrow
+	buyer  string
+	seller string
+}
+
+func (server *Server) getEscrow(ctx *gin.Context) {
+	parties, ok := server.escrowFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.seller && !authorizeOwner(ctx, parties.buyer, permissionReadAny) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parties.escrow)
+}
+
+// confirmEscrow releases the escrow to the seller, only the buyer can confirm
+func (server *Server) confirmEscrow(ctx *gin.Context) {
+	parties, ok := server.escrowFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.buyer {
+		err := errors.New("only the buyer can confirm the escrow")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ConfirmEscrowTx(ctx, parties.escrow.ID)
+	server.escrowResponse(ctx, result, err)
+}
+
+// agreeEscrowRefund records that a party agrees to refund the buyer, the
+// escrow is refunded once both parties agreed
+func (server *Server) agreeEscrowRefund(ctx *gin.Context) {
+	parties, ok := server.escrowFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.buyer && payload.Username != parties.seller {
+		err := errors.New("escrow doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AgreeEscrowRefundTx(ctx, db.AgreeEscrowRefundTxParams{
+		ID:    parties.escrow.ID,
+		Buyer: payload.Username == parties.buyer,
+	})
+	server.escrowResponse(ctx, result, err)
+}
+
+type disputeEscrowRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// disputeEscrow keeps the escrow from being released on timeout until the
+// parties settle it or an admin resolves it
+func (server *Server) disputeEscrow(ctx *gin.Context) {
+	var req disputeEscrowRequest
+
+	parties, ok := server.escrowFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.DisputeEscrowTxParams{
+		ID:          parties.escrow.ID,
+		DisputedBy:  payload.Username,
+		Reason:      req.Reason,
+		NotifyOwner: parties.seller,
+	}
+
+	switch payload.Username {
+	case parties.buyer:
+	case parties.seller:
+		arg.NotifyOwner = parties.buyer
+	default:
+		err := errors.New("escrow doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	escrow, err := server.store.DisputeEscrowTx(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, db.ErrEscrowSettled) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, escrow)
+}
+
+type resolveEscrowRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=release refund"`
+	Note    string `json:"note" binding:"required,max=500"`
+}
+
+// resolveEscrow lets an admin release or refund an escrow the parties
+// couldn't settle
+func (server *Server) resolveEscrow(ctx *gin.Context) {
+	var uri escrowURI
+	var req resolveEscrowRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.ResolveEscrowTx(ctx, db.ResolveEscrowTxParams{
+		ID:        uri.ID,
+		Release:   req.Outcome == "release",
+		DecidedBy: payload.Username,
+		Note:      req.Note,
+	})
+	server.escrowResponse(ctx, result, err)
+}
+
+func (server *Server) escrowResponse(ctx *gin.Context, result db.EscrowTxResult, err error) {
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrEscrowSettled) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// escrowFromURI reads the escrow and the owners of both of its wallets
+func (server *Server) escrowFromURI(ctx *gin.Context) (escrowParties, bool) {
+	var uri escrowURI
+	var parties escrowParties
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return parties, false
+	}
+
+	escrow, err := server.store.GetEscrow(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return parties, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	buyer, err := server.store.GetWallet(ctx, escrow.BuyerWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	seller, err := server.store.GetWallet(ctx, escrow.SellerWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	return escrowParties{escrow: escrow, buyer: buyer.Owner, seller: seller.Owner}, true
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEscrowAPI(t *testing.T) {
+	buyer := randomWallet()
+	seller := randomWallet()
+	seller.ID = buyer.ID + 100
+	seller.Currency = buyer.Currency
+
+	body := gin.H{"from_wallet_id": buyer.ID, "to_wallet_id": seller.ID, "amount": 500, "currency": buyer.Currency, "description": "used bike"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), buyer.ID).Times(1).Return(buyer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), seller.ID).Times(1).Return(seller, nil)
+				store.EXPECT().
+					CreateEscrowTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateEscrowTxParams) (db.CreateEscrowTxResult, error) {
+						require.Equal(t, buyer.ID, arg.BuyerWalletID)
+						require.Equal(t, seller.ID, arg.SellerWalletID)
+						require.Equal(t, int64(500), arg.Amount)
+						require.Equal(t, buyer.Owner, arg.CreatedBy)
+						require.WithinDuration(t, time.Now().Add(24*time.Hour), arg.ReleaseAt, time.Minute)
+						require.NotNil(t, arg.Limits)
+						require.NotNil(t, arg.Risk)
+						return db.CreateEscrowTxResult{Escrow: db.Escrow{ID: 1, Status: db.EscrowHeld}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "SameWallet",
+			body:      gin.H{"from_wallet_id": buyer.ID, "to_wallet_id": buyer.ID, "amount": 500, "currency": buyer.Currency},
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscrowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InsufficientFunds",
+			body:      body,
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), buyer.ID).Times(1).Return(buyer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), seller.ID).Times(1).Return(seller, nil)
+				store.EXPECT().CreateEscrowTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateEscrowTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "LimitExceeded",
+			body:      body,
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), buyer.ID).Times(1).Return(buyer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), seller.ID).Times(1).Return(seller, nil)
+				store.EXPECT().
+					CreateEscrowTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateEscrowTxResult{}, &db.LimitExceededError{Limit: util.LimitDaily, Max: 100})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Blocked",
+			body:      body,
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), buyer.ID).Times(1).Return(buyer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), seller.ID).Times(1).Return(seller, nil)
+				store.EXPECT().CreateEscrowTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateEscrowTxResult{}, db.ErrTransferBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/escrows", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEscrowActionsAPI(t *testing.T) {
+	buyer := randomWallet()
+	seller := randomWallet()
+	seller.ID = buyer.ID + 100
+	escrow := db.Escrow{ID: util.RandomInt(1, 1000), BuyerWalletID: buyer.ID, SellerWalletID: seller.ID, Amount: 500, Status: db.EscrowHeld}
+
+	stubEscrow := func(store *mockdb.MockStore) {
+		store.EXPECT().GetEscrow(gomock.Any(), escrow.ID).Times(1).Return(escrow, nil)
+		store.EXPECT().GetWallet(gomock.Any(), buyer.ID).Times(1).Return(buyer, nil)
+		store.EXPECT().GetWallet(gomock.Any(), seller.ID).Times(1).Return(seller, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		action        string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "BuyerConfirms",
+			action:    "confirm",
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().ConfirmEscrowTx(gomock.Any(), escrow.ID).Times(1).Return(db.EscrowTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "SellerConfirms",
+			action:    "confirm",
+			setupAuth: authAs(seller.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().ConfirmEscrowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "ConfirmSettled",
+			action:    "confirm",
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().ConfirmEscrowTx(gomock.Any(), escrow.ID).Times(1).Return(db.EscrowTxResult{}, db.ErrEscrowSettled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "SellerAgreesRefund",
+			action:    "refund",
+			setupAuth: authAs(seller.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().
+					AgreeEscrowRefundTx(gomock.Any(), gomock.Eq(db.AgreeEscrowRefundTxParams{ID: escrow.ID, Buyer: false})).
+					Times(1).
+					Return(db.EscrowTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "SellerDisputes",
+			action:    "dispute",
+			body:      gin.H{"reason": "buyer says the item never arrived but it did"},
+			setupAuth: authAs(seller.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().
+					DisputeEscrowTx(gomock.Any(), gomock.Eq(db.DisputeEscrowTxParams{
+						ID:          escrow.ID,
+						DisputedBy:  seller.Owner,
+						Reason:      "buyer says the item never arrived but it did",
+						NotifyOwner: buyer.Owner,
+					})).
+					Times(1).
+					Return(db.Escrow{ID: escrow.ID, Status: db.EscrowDisputed}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "StrangerDisputes",
+			action:    "dispute",
+			body:      gin.H{"reason": "nosy"},
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubEscrow(store)
+				store.EXPECT().DisputeEscrowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "AdminResolves",
+			action:    "resolve",
+			body:      gin.H{"outcome": "refund", "note": "item not as described"},
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResolveEscrowTx(gomock.Any(), gomock.Eq(db.ResolveEscrowTxParams{
+						ID:        escrow.ID,
+						Release:   false,
+						DecidedBy: "admin",
+						Note:      "item not as described",
+					})).
+					Times(1).
+					Return(db.EscrowTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "BuyerResolves",
+			action:    "resolve",
+			body:      gin.H{"outcome": "refund", "note": "mine"},
+			setupAuth: authAs(buyer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResolveEscrowTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/escrows/%d/%s", escrow.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fees returns the params charging fees on transfers made at now, or nil
+// when no revenue owner is configured
+func (server *Server) fees(now time.Time) *db.FeeParams {
+	return db.NewFeeParams(server.config.PlatformRevenueOwner, now)
+}
+
+type createFeeScheduleRequest struct {
+	Operation     string         `json:"operation" binding:"required,fee_operation"`
+	UserType      string         `json:"user_type" binding:"required,oneof=customer merchant"`
+	Currency      string         `json:"currency" binding:"required,currency"`
+	Kind          string         `json:"kind" binding:"required,oneof=fixed percentage tiered"`
+	FixedAmount   int64          `json:"fixed_amount" binding:"min=0"`
+	BasisPoints   int64          `json:"basis_points" binding:"min=0,max=10000"`
+	MinFee        int64          `json:"min_fee" binding:"min=0"`
+	MaxFee        int64          `json:"max_fee" binding:"min=0"`
+	Tiers         []util.FeeTier `json:"tiers" binding:"max=10"`
+	EffectiveFrom time.Time      `json:"effective_from"`
+}
+
+func (server *Server) createFeeSchedule(ctx *gin.Context) {
+	var req createFeeScheduleRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+
+	// schedules in effect are never changed, so past fees can be explained
+	if req.EffectiveFrom.IsZero() {
+		req.EffectiveFrom = now
+	} else if req.EffectiveFrom.Before(now) {
+		err := errors.New("effective_from must not be in the past")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rule := util.FeeRule{
+		Kind:        req.Kind,
+		FixedAmount: req.FixedAmount,
+		BasisPoints: req.BasisPoints,
+		Tiers:       req.Tiers,
+		MinFee:      req.MinFee,
+		MaxFee:      req.MaxFee,
+	}
+	if err := rule.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if rule.Tiers == nil {
+		rule.Tiers = []util.FeeTier{}
+	}
+
+	tiers, err := json.Marshal(rule.Tiers)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	schedule, err := server.store.CreateFeeSchedule(ctx, db.CreateFeeScheduleParams{
+		Operation:     req.Operation,
+		UserType:      req.UserType,
+		Currency:      req.Currency,
+		Kind:          req.Kind,
+		FixedAmount:   req.FixedAmount,
+		BasisPoints:   req.BasisPoints,
+		MinFee:        req.MinFee,
+		MaxFee:        req.MaxFee,
+		Tiers:         tiers,
+		EffectiveFrom: req.EffectiveFrom,
+		CreatedBy:     payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+type listFeeSchedulesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listFeeSchedules(ctx *gin.Context) {
+	var req listFeeSchedulesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	schedules, err := server.store.ListFeeSchedules(ctx, db.ListFeeSchedulesParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedules)
+}
+
+type feeScheduleURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteFeeSchedule withdraws a schedule that isn't in effect yet
+func (server *Server) deleteFeeSchedule(ctx *gin.Context) {
+	var uri feeScheduleURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deleted, err := server.store.DeleteFutureFeeSchedule(ctx, db.DeleteFutureFeeScheduleParams{
+		ID:  uri.ID,
+		Now: time.Now(),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if deleted == 0 {
+		_, err = server.store.GetFeeSchedule(ctx, uri.ID)
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		err = errors.New("fee schedule is already in effect")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("Fee schedule deleted"))
+}
+
+func (server *Server) quoteTransfer(ctx *gin.Context) {
+	var req transferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateTransferWallets(ctx, req.FromWalletID, req.ToWalletID, req.Currency) {
+		return
+	}
+
+	fees := server.fees(time.Now())
+	if fees == nil {
+		ctx.JSON(http.StatusOK, db.QuoteTransferFeesResult{
+			Amount:     req.Amount,
+			Fees:       []db.FeeQuote{},
+			TotalDebit: req.Amount,
+			NetCredit:  req.Amount,
+		})
+		return
+	}
+
+	quote, err := server.store.QuoteTransferFees(ctx, db.QuoteTransferFeesParams{
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		FeeParams:    *fees,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+type quoteFeeRequest struct {
+	Operation string `form:"operation" binding:"required,fee_operation"`
+	UserType  string `form:"user_type" binding:"required,oneof=customer merchant"`
+	Currency  string `form:"currency" binding:"required,currency"`
+	Amount    int64  `form:"amount" binding:"required,gt=0"`
+}
+
+type quoteFeeResponse struct {
+	Operation  string `json:"operation"`
+	UserType   string `json:"user_type"`
+	Currency   string `json:"currency"`
+	Amount     int64  `json:"amount"`
+	Fee        int64  `json:"fee"`
+	ScheduleID int64  `json:"schedule_id,omitempty"`
+}
+
+// quoteFee returns the fee of any operation under the schedule in effect
+func (server *Server) quoteFee(ctx *gin.Context) {
+	var req quoteFeeRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rsp := quoteFeeResponse{
+		Operation: req.Operation,
+		UserType:  req.UserType,
+		Currency:  req.Currency,
+		Amount:    req.Amount,
+	}
+
+	if server.config.PlatformRevenueOwner == "" {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	schedule, err := server.store.GetActiveFeeSchedule(ctx, db.GetActiveFeeScheduleParams{
+		Operation: req.Operation,
+		UserType:  req.UserType,
+		Currency:  req.Currency,
+		Now:       time.Now(),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, rsp)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rule, err := schedule.Rule()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Fee = rule.Compute(req.Amount)
+	rsp.ScheduleID = schedule.ID
+
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,435 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFeeScheduleAPI(t *testing.T) {
+	admin := util.RandomString(6)
+	effectiveFrom := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"operation":      util.FeeMerchantReceipt,
+				"user_type":      util.FeeUserMerchant,
+				"currency":       util.BRL,
+				"kind":           util.FeeTiered,
+				"max_fee":        1000,
+				"tiers":          []gin.H{{"up_to": 10000, "fixed_amount": 50}, {"basis_points": 150}},
+				"effective_from": effectiveFrom,
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateFeeScheduleParams) (db.FeeSchedule, error) {
+						require.Equal(t, util.FeeMerchantReceipt, arg.Operation)
+						require.Equal(t, util.FeeTiered, arg.Kind)
+						require.Equal(t, admin, arg.CreatedBy)
+						require.True(t, effectiveFrom.Equal(arg.EffectiveFrom))
+						require.JSONEq(t, `[{"up_to":10000,"fixed_amount":50,"basis_points":0},{"fixed_amount":0,"basis_points":150}]`, string(arg.Tiers))
+						return db.FeeSchedule{ID: 1, Operation: arg.Operation, Tiers: arg.Tiers}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EffectiveNow",
+			body: gin.H{
+				"operation":    util.FeeTransfer,
+				"user_type":    util.FeeUserCustomer,
+				"currency":     util.USD,
+				"kind":         util.FeeFixed,
+				"fixed_amount": 100,
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateFeeScheduleParams) (db.FeeSchedule, error) {
+						require.WithinDuration(t, time.Now(), arg.EffectiveFrom, time.Second)
+						require.JSONEq(t, `[]`, string(arg.Tiers))
+						return db.FeeSchedule{ID: 1}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PastEffectiveFrom",
+			body: gin.H{
+				"operation":      util.FeeTransfer,
+				"user_type":      util.FeeUserCustomer,
+				"currency":       util.USD,
+				"kind":           util.FeeFixed,
+				"fixed_amount":   100,
+				"effective_from": time.Now().Add(-time.Hour),
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRule",
+			body: gin.H{
+				"operation": util.FeeTransfer,
+				"user_type": util.FeeUserCustomer,
+				"currency":  util.USD,
+				"kind":      util.FeeTiered,
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnchargedOperation",
+			body: gin.H{
+				"operation":    "fx_conversion",
+				"user_type":    util.FeeUserCustomer,
+				"currency":     util.USD,
+				"kind":         util.FeeFixed,
+				"fixed_amount": 100,
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidOperation",
+			body: gin.H{
+				"operation":    "deposit",
+				"user_type":    util.FeeUserCustomer,
+				"currency":     util.USD,
+				"kind":         util.FeeFixed,
+				"fixed_amount": 100,
+			},
+			setupAuth: authAs(admin, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: gin.H{
+				"operation":    util.FeeTransfer,
+				"user_type":    util.FeeUserCustomer,
+				"currency":     util.USD,
+				"kind":         util.FeeFixed,
+				"fixed_amount": 100,
+			},
+			setupAuth: authAs(admin, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/fee-schedules", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteFeeScheduleAPI(t *testing.T) {
+	admin := util.RandomString(6)
+	schedule := db.FeeSchedule{ID: util.RandomInt(1, 100)}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFutureFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().GetFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyInEffect",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFutureFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetFeeSchedule(gomock.Any(), schedule.ID).Times(1).Return(schedule, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFutureFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetFeeSchedule(gomock.Any(), schedule.ID).Times(1).Return(db.FeeSchedule{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/fee-schedules/%d", schedule.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			authAs(admin, util.AdminRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestQuoteTransferAPI(t *testing.T) {
+	from := randomWallet()
+	to := randomWallet()
+	to.ID = from.ID + 100
+	to.Currency = from.Currency
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().GetWallet(gomock.Any(), to.ID).Times(1).Return(to, nil)
+				store.EXPECT().
+					QuoteTransferFees(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.QuoteTransferFeesParams) (db.QuoteTransferFeesResult, error) {
+						require.Equal(t, from.ID, arg.FromWalletID)
+						require.Equal(t, to.ID, arg.ToWalletID)
+						require.Equal(t, "platform", arg.RevenueOwner)
+						return db.QuoteTransferFeesResult{
+							Amount:     arg.Amount,
+							Fees:       []db.FeeQuote{{Operation: util.FeeTransfer, WalletID: from.ID, Amount: 10}},
+							TotalDebit: arg.Amount + 10,
+							NetCredit:  arg.Amount,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var quote db.QuoteTransferFeesResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &quote))
+				require.Len(t, quote.Fees, 1)
+				require.Equal(t, int64(110), quote.TotalDebit)
+			},
+		},
+		{
+			name:      "NotOwner",
+			setupAuth: authAs(to.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().QuoteTransferFees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/quote", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestQuoteFeeAPI(t *testing.T) {
+	user := util.RandomString(6)
+	schedule := db.FeeSchedule{
+		ID:          util.RandomInt(1, 100),
+		Operation:   util.FeeTransfer,
+		UserType:    util.FeeUserCustomer,
+		Currency:    util.BRL,
+		Kind:        util.FeePercentage,
+		BasisPoints: 100,
+		MinFee:      50,
+		Tiers:       json.RawMessage(`[]`),
+	}
+
+	testCases := []struct {
+		name          string
+		operation     string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			operation: util.FeeTransfer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(schedule, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp quoteFeeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(100), rsp.Fee)
+				require.Equal(t, schedule.ID, rsp.ScheduleID)
+			},
+		},
+		{
+			name:      "NoSchedule",
+			operation: util.FeeMerchantReceipt,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(db.FeeSchedule{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp quoteFeeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Zero(t, rsp.Fee)
+			},
+		},
+		{
+			name:      "UnchargedOperation",
+			operation: "withdrawal",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidOperation",
+			operation: "deposit",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/fees/quote?operation=%s&user_type=customer&currency=BRL&amount=10000", tc.operation)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			authAs(user, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createHoldRequest struct {
+	WalletID         int64     `json:"wallet_id" binding:"required,min=1"`
+	MerchantWalletID int64     `json:"merchant_wallet_id" binding:"required,min=1"`
+	Amount           int64     `json:"amount" binding:"required,gt=0"`
+	Currency         string    `json:"currency" binding:"required,currency"`
+	ExpiresAt        time.Time `json:"expires_at" binding:"required"`
+}
+
+// createHold authorizes the merchant to capture up to the amount from the
+// wallet of the authenticated user until the hold expires
+func (server *Server) createHold(ctx *gin.Context) {
+	var req createHoldRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !req.ExpiresAt.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.WalletID == req.MerchantWalletID {
+		err := errors.New("merchant wallet must not be the held wallet")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateTransferWallets(ctx, req.WalletID, req.MerchantWalletID, req.Currency) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.CreateHoldTx(ctx, db.CreateHoldTxParams{
+		WalletID:         req.WalletID,
+		MerchantWalletID: req.MerchantWalletID,
+		Amount:           req.Amount,
+		CreatedBy:        payload.Username,
+		ExpiresAt:        req.ExpiresAt,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listHoldsRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listHolds returns the holds placed on the wallet or for it, newest first
+func (server *Server) listHolds(ctx *gin.Context) {
+	var req listHoldsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	wallet, err := server.store.GetWallet(ctx, req.WalletID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, wallet.Owner, permissionReadAny) {
+		return
+	}
+
+	holds, err := server.store.ListWalletHolds(ctx, db.ListWalletHoldsParams{
+		WalletID: wallet.ID,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, holds)
+}
+
+type holdURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getHold(ctx *gin.Context) {
+	hold, ok := server.holdFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if !server.authorizeHold(ctx, permissionReadAny, hold.WalletID, hold.MerchantWalletID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hold)
+}
+
+type captureHoldRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// captureHold pays the merchant all or part of the hold, the rest is released
+func (server *Server) captureHold(ctx *gin.Context) {
+	var req captureHoldRequest
+
+	hold, ok := server.holdFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeHold(ctx, permissionWriteAny, hold.MerchantWalletID) {
+		return
+	}
+
+	now := time.Now()
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		ID:     hold.ID,
+		Amount: req.Amount,
+		Now:    now,
+		Fees:   server.fees(now),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotAuthorized) || errors.Is(err, db.ErrHoldExpired) ||
+			errors.Is(err, db.ErrCaptureExceedsHold) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// voidHold releases the whole hold without paying the merchant
+func (server *Server) voidHold(ctx *gin.Context) {
+	hold, ok := server.holdFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if !server.authorizeHold(ctx, permissionWriteAny, hold.MerchantWalletID) {
+		return
+	}
+
+	hold, err := server.store.VoidHoldTx(ctx, hold.ID)
+
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotAuthorized) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hold)
+}
+
+func (server *Server) holdFromURI(ctx *gin.Context) (db.Hold, bool) {
+	var uri holdURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Hold{}, false
+	}
+
+	hold, err := server.store.GetHold(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return hold, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return hold, false
+	}
+
+	return hold, true
+}
+
+// authorizeHold reports whether the authenticated user owns one of the
+// wallets, or has the permission to act on any hold
+func (server *Server) authorizeHold(ctx *gin.Context, anyPermission string, walletIDs ...int64) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if authorized(payload, anyPermission) {
+		return true
+	}
+
+	for _, walletID := range walletIDs {
+		wallet, err := server.store.GetWallet(ctx, walletID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return false
+		}
+
+		if wallet.Owner == payload.Username {
+			return true
+		}
+	}
+
+	err := errors.New("hold doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+	return false
+}
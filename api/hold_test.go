@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHoldAPI(t *testing.T) {
+	wallet := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = wallet.ID + 100
+	merchant.Currency = wallet.Currency
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"wallet_id": wallet.ID, "merchant_wallet_id": merchant.ID, "amount": 500, "currency": wallet.Currency, "expires_at": expiresAt},
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateHoldTx(gomock.Any(), gomock.Eq(db.CreateHoldTxParams{
+						WalletID:         wallet.ID,
+						MerchantWalletID: merchant.ID,
+						Amount:           500,
+						CreatedBy:        wallet.Owner,
+						ExpiresAt:        expiresAt,
+					})).
+					Times(1).
+					Return(db.CreateHoldTxResult{Hold: db.Hold{ID: 1, Amount: 500, Status: db.HoldAuthorized}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.CreateHoldTxResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.HoldAuthorized, got.Hold.Status)
+			},
+		},
+		{
+			name:      "Expired",
+			body:      gin.H{"wallet_id": wallet.ID, "merchant_wallet_id": merchant.ID, "amount": 500, "currency": wallet.Currency, "expires_at": time.Now().Add(-time.Hour)},
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotOwner",
+			body:      gin.H{"wallet_id": wallet.ID, "merchant_wallet_id": merchant.ID, "amount": 500, "currency": wallet.Currency, "expires_at": expiresAt},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InsufficientFunds",
+			body:      gin.H{"wallet_id": wallet.ID, "merchant_wallet_id": merchant.ID, "amount": 500, "currency": wallet.Currency, "expires_at": expiresAt},
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateHoldTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/holds", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCaptureHoldAPI(t *testing.T) {
+	merchant := randomWallet()
+	hold := db.Hold{
+		ID:               util.RandomInt(1, 1000),
+		WalletID:         merchant.ID + 100,
+		MerchantWalletID: merchant.ID,
+		Amount:           500,
+		Status:           db.HoldAuthorized,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"amount": 300},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), hold.ID).Times(1).Return(hold, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CaptureHoldTxParams) (db.CaptureHoldTxResult, error) {
+						require.Equal(t, hold.ID, arg.ID)
+						require.Equal(t, int64(300), arg.Amount)
+						require.NotNil(t, arg.Fees)
+						return db.CaptureHoldTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Customer",
+			body:      gin.H{"amount": 300},
+			setupAuth: authAs(merchant.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "OtherMerchant",
+			body:      gin.H{"amount": 300},
+			setupAuth: authAs(util.RandomString(7), util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), hold.ID).Times(1).Return(hold, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "ExceedsHold",
+			body:      gin.H{"amount": 600},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), hold.ID).Times(1).Return(hold, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrCaptureExceedsHold)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "Expired",
+			body:      gin.H{"amount": 300},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), hold.ID).Times(1).Return(hold, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldExpired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/holds/%d/capture", hold.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVoidHoldAPI(t *testing.T) {
+	merchant := randomWallet()
+	hold := db.Hold{ID: util.RandomInt(1, 1000), WalletID: merchant.ID + 100, MerchantWalletID: merchant.ID, Amount: 500}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetHold(gomock.Any(), hold.ID).Times(2).Return(hold, nil)
+	store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(2).Return(merchant, nil)
+	gomock.InOrder(
+		store.EXPECT().VoidHoldTx(gomock.Any(), hold.ID).Times(1).Return(db.Hold{ID: hold.ID, Status: db.HoldVoided}, nil),
+		store.EXPECT().VoidHoldTx(gomock.Any(), hold.ID).Times(1).Return(db.Hold{}, db.ErrHoldNotAuthorized),
+	)
+
+	server := newTestServer(t, store)
+	url := fmt.Sprintf("/holds/%d/void", hold.ID)
+
+	for _, code := range []int{http.StatusOK, http.StatusBadRequest} {
+		request, err := http.NewRequest(http.MethodPost, url, nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		authAs(merchant.Owner, util.MerchantRole)(t, request, server.tokenMaker)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, code, recorder.Code)
+	}
+}
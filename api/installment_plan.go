@@ -0,0 +1,341 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createInstallmentPlanRequest struct {
+	FromWalletID int64  `json:"from_wallet_id" binding:"required,min=1"`
+	ToWalletID   int64  `json:"to_wallet_id" binding:"required,min=1"`
+	Amount       int64  `json:"amount" binding:"required,gt=0"`
+	Currency     string `json:"currency" binding:"required,currency"`
+	Installments int32  `json:"installments" binding:"required,min=2,max=24"`
+	// InterestRate is in basis points of the outstanding amount per month
+	InterestRate int64 `json:"interest_rate" binding:"min=0,max=1000"`
+	// Funded pays the merchant the whole amount now, from the platform. Only
+	// merchants that opted in to funding can be paid this way
+	Funded      bool   `json:"funded"`
+	Description string `json:"description" binding:"max=140"`
+}
+
+// createInstallmentPlan buys from the merchant in monthly installments taken
+// from the customer wallet as they fall due
+func (server *Server) createInstallmentPlan(ctx *gin.Context) {
+	var req createInstallmentPlanRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.FromWalletID == req.ToWalletID {
+		err := errors.New("merchant wallet must not be the customer wallet")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Amount < int64(req.Installments) {
+		err := errors.New("amount is too small for the number of installments")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Funded && server.config.InstallmentFundingOwner == "" {
+		err := errors.New("installment plans can't be funded upfront")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateTransferWallets(ctx, req.FromWalletID, req.ToWalletID, req.Currency) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateInstallmentPlanTxParams{
+		CustomerWalletID: req.FromWalletID,
+		MerchantWalletID: req.ToWalletID,
+		Amount:           req.Amount,
+		Count:            req.Installments,
+		InterestRate:     req.InterestRate,
+		Description:      req.Description,
+		CreatedBy:        payload.Username,
+		Now:              time.Now(),
+		Location:         server.limitSchedule.Location,
+	}
+	if req.Funded {
+		arg.FundingOwner = server.config.InstallmentFundingOwner
+	}
+
+	result, err := server.store.CreateInstallmentPlanTx(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, db.ErrFundingWalletNotFound) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrWalletFrozen) || errors.Is(err, db.ErrInstallmentFundingNotAllowed) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type installmentFundingURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+// optInInstallmentFunding lets the platform fund the installment plans paying
+// the merchant upfront. Merchants opt in for themselves, admins for anyone
+// with the merchant role
+func (server *Server) optInInstallmentFunding(ctx *gin.Context) {
+	var uri installmentFundingURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionWriteAny) {
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, uri.Username)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.HasMerchantRole() {
+		err := errors.New("only merchants can have their installment plans funded")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	optIn, err := server.store.UpsertInstallmentFundingOptIn(ctx, db.UpsertInstallmentFundingOptInParams{
+		Owner:     user.Username,
+		OptedInBy: payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, optIn)
+}
+
+// optOutInstallmentFunding stops funding new plans paying the merchant, the
+// plans already funded go on as they are
+func (server *Server) optOutInstallmentFunding(ctx *gin.Context) {
+	var uri installmentFundingURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionWriteAny) {
+		return
+	}
+
+	err := server.store.DeleteInstallmentFundingOptIn(ctx, uri.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"owner": uri.Username})
+}
+
+type listInstallmentPlansRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listInstallmentPlans returns the plans the wallet buys or sells through, newest first
+func (server *Server) listInstallmentPlans(ctx *gin.Context) {
+	var req listInstallmentPlansRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	plans, err := server.store.ListWalletInstallmentPlans(ctx, db.ListWalletInstallmentPlansParams{
+		WalletID:   req.WalletID,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plans)
+}
+
+type installmentPlanURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// installmentPlanParties is the plan along with its customer and merchant
+type installmentPlanParties struct {
+	plan     db.InstallmentPlan
+	customer string
+	merchant string
+}
+
+type installmentPlanResponse struct {
+	Plan         db.InstallmentPlan `json:"plan"`
+	Installments []db.Installment   `json:"installments"`
+}
+
+func (server *Server) getInstallmentPlan(ctx *gin.Context) {
+	parties, ok := server.installmentPlanFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.merchant && !authorizeOwner(ctx, parties.customer, permissionReadAny) {
+		return
+	}
+
+	installments, err := server.store.ListPlanInstallments(ctx, parties.plan.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, installmentPlanResponse{Plan: parties.plan, Installments: installments})
+}
+
+// prepayInstallmentPlan pays off the plan at once, the installments not due
+// yet are paid without their interest. Only the customer can prepay
+func (server *Server) prepayInstallmentPlan(ctx *gin.Context) {
+	parties, ok := server.installmentPlanFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.customer {
+		err := errors.New("only the customer can prepay the installment plan")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.PrepayInstallmentPlanTx(ctx, db.PrepayInstallmentPlanTxParams{
+		ID:  parties.plan.ID,
+		Now: time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrInstallmentPlanClosed) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrWalletFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// cancelInstallmentPlan stops charging the installments left, on behalf of
+// the merchant or an admin
+func (server *Server) cancelInstallmentPlan(ctx *gin.Context) {
+	parties, ok := server.installmentPlanFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if !authorizeOwner(ctx, parties.merchant, permissionWriteAny) {
+		return
+	}
+
+	result, err := server.store.CancelInstallmentPlanTx(ctx, parties.plan.ID)
+
+	if err != nil {
+		if errors.Is(err, db.ErrInstallmentPlanClosed) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrWalletFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// installmentPlanFromURI reads the plan and the owners of its customer and
+// merchant wallets
+func (server *Server) installmentPlanFromURI(ctx *gin.Context) (installmentPlanParties, bool) {
+	var uri installmentPlanURI
+	var parties installmentPlanParties
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return parties, false
+	}
+
+	plan, err := server.store.GetInstallmentPlan(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return parties, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	customer, err := server.store.GetWallet(ctx, plan.CustomerWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	merchant, err := server.store.GetWallet(ctx, plan.MerchantWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	return installmentPlanParties{plan: plan, customer: customer.Owner, merchant: merchant.Owner}, true
+}
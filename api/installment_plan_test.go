@@ -0,0 +1,415 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInstallmentPlanAPI(t *testing.T) {
+	customer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = customer.ID + 100
+	merchant.Currency = customer.Currency
+	ownWallet := randomWallet()
+	ownWallet.ID = customer.ID + 200
+	ownWallet.Owner = customer.Owner
+	ownWallet.Currency = customer.Currency
+
+	body := gin.H{
+		"from_wallet_id": customer.ID,
+		"to_wallet_id":   merchant.ID,
+		"amount":         120000,
+		"currency":       customer.Currency,
+		"installments":   12,
+		"interest_rate":  199,
+		"funded":         true,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateInstallmentPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateInstallmentPlanTxParams) (db.CreateInstallmentPlanTxResult, error) {
+						require.Equal(t, customer.ID, arg.CustomerWalletID)
+						require.Equal(t, merchant.ID, arg.MerchantWalletID)
+						require.Equal(t, int64(120000), arg.Amount)
+						require.Equal(t, int32(12), arg.Count)
+						require.Equal(t, int64(199), arg.InterestRate)
+						require.Equal(t, "platform", arg.FundingOwner)
+						require.Equal(t, customer.Owner, arg.CreatedBy)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Minute)
+						return db.CreateInstallmentPlanTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "TooManyInstallments",
+			body:      gin.H{"from_wallet_id": customer.ID, "to_wallet_id": merchant.ID, "amount": 120000, "currency": customer.Currency, "installments": 48},
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInstallmentPlanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotCustomer",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().CreateInstallmentPlanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoFundingWallet",
+			body:      body,
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateInstallmentPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateInstallmentPlanTxResult{}, db.ErrFundingWalletNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "FundsOwnWallet",
+			body:      gin.H{"from_wallet_id": customer.ID, "to_wallet_id": ownWallet.ID, "amount": 120000, "currency": customer.Currency, "installments": 12, "funded": true},
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().GetWallet(gomock.Any(), ownWallet.ID).Times(1).Return(ownWallet, nil)
+				store.EXPECT().
+					CreateInstallmentPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateInstallmentPlanTxResult{}, db.ErrInstallmentFundingNotAllowed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/installment-plans", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestInstallmentPlanActionsAPI(t *testing.T) {
+	customer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = customer.ID + 100
+	plan := db.InstallmentPlan{ID: util.RandomInt(1, 1000), CustomerWalletID: customer.ID, MerchantWalletID: merchant.ID, Status: db.InstallmentPlanActive}
+
+	stubPlan := func(store *mockdb.MockStore) {
+		store.EXPECT().GetInstallmentPlan(gomock.Any(), plan.ID).Times(1).Return(plan, nil)
+		store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+		store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "CustomerPrepays",
+			action:    "prepay",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().
+					PrepayInstallmentPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.PrepayInstallmentPlanTxParams) (db.PrepayInstallmentPlanTxResult, error) {
+						require.Equal(t, plan.ID, arg.ID)
+						return db.PrepayInstallmentPlanTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantPrepays",
+			action:    "prepay",
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().PrepayInstallmentPlanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "PrepayInsufficientFunds",
+			action:    "prepay",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().
+					PrepayInstallmentPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PrepayInstallmentPlanTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantCancels",
+			action:    "cancel",
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().CancelInstallmentPlanTx(gomock.Any(), plan.ID).Times(1).Return(db.CancelInstallmentPlanTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerCancels",
+			action:    "cancel",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().CancelInstallmentPlanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "CancelClosed",
+			action:    "cancel",
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubPlan(store)
+				store.EXPECT().
+					CancelInstallmentPlanTx(gomock.Any(), plan.ID).
+					Times(1).
+					Return(db.CancelInstallmentPlanTxResult{}, db.ErrInstallmentPlanClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/installment-plans/%d/%s", plan.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestInstallmentFundingOptInAPI(t *testing.T) {
+	merchant, _, err := randomUser(t)
+	require.NoError(t, err)
+	merchant.Role = util.MerchantRole
+
+	customer, _, err := randomUser(t)
+	require.NoError(t, err)
+	customer.Role = util.CustomerRole
+
+	testCases := []struct {
+		name          string
+		method        string
+		username      string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "MerchantOptsIn",
+			method:    http.MethodPut,
+			username:  merchant.Username,
+			setupAuth: authAs(merchant.Username, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), merchant.Username).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					UpsertInstallmentFundingOptIn(gomock.Any(), gomock.Eq(db.UpsertInstallmentFundingOptInParams{
+						Owner:     merchant.Username,
+						OptedInBy: merchant.Username,
+					})).
+					Times(1).
+					Return(db.InstallmentFundingOptIn{Owner: merchant.Username, OptedInBy: merchant.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "AdminOptsIn",
+			method:    http.MethodPut,
+			username:  merchant.Username,
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), merchant.Username).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					UpsertInstallmentFundingOptIn(gomock.Any(), gomock.Eq(db.UpsertInstallmentFundingOptInParams{
+						Owner:     merchant.Username,
+						OptedInBy: "admin",
+					})).
+					Times(1).
+					Return(db.InstallmentFundingOptIn{Owner: merchant.Username, OptedInBy: "admin"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "AdminOptsInCustomer",
+			method:    http.MethodPut,
+			username:  customer.Username,
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), customer.Username).Times(1).Return(customer, nil)
+				store.EXPECT().UpsertInstallmentFundingOptIn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerOptsIn",
+			method:    http.MethodPut,
+			username:  customer.Username,
+			setupAuth: authAs(customer.Username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertInstallmentFundingOptIn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "OtherMerchantOptsIn",
+			method:    http.MethodPut,
+			username:  merchant.Username,
+			setupAuth: authAs("other", util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertInstallmentFundingOptIn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantOptsOut",
+			method:    http.MethodDelete,
+			username:  merchant.Username,
+			setupAuth: authAs(merchant.Username, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteInstallmentFundingOptIn(gomock.Any(), merchant.Username).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/users/%s/installment-funding", tc.username)
+			request, err := http.NewRequest(tc.method, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
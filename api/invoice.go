@@ -0,0 +1,502 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"html/template"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	invoiceFormatJSON = "json"
+	invoiceFormatHTML = "html"
+)
+
+type invoiceItemRequest struct {
+	Description string `json:"description" binding:"required,max=140"`
+	Quantity    int64  `json:"quantity" binding:"required,gt=0"`
+	UnitAmount  int64  `json:"unit_amount" binding:"required,gt=0"`
+	Discount    int64  `json:"discount" binding:"min=0"`
+	TaxName     string `json:"tax_name" binding:"max=40"`
+	// TaxRate is in basis points of the discounted amount
+	TaxRate int64 `json:"tax_rate" binding:"min=0,max=10000"`
+}
+
+type invoiceRequest struct {
+	WalletID int64                `json:"wallet_id" binding:"required,min=1"`
+	Customer string               `json:"customer" binding:"required"`
+	Currency string               `json:"currency" binding:"required,currency"`
+	Memo     string               `json:"memo" binding:"max=500"`
+	DueAt    time.Time            `json:"due_at" binding:"required"`
+	Items    []invoiceItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+func (req invoiceRequest) items() []db.InvoiceItemParams {
+	items := make([]db.InvoiceItemParams, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = db.InvoiceItemParams{
+			Description: item.Description,
+			InvoiceLine: util.InvoiceLine{
+				Quantity:   item.Quantity,
+				UnitAmount: item.UnitAmount,
+				Discount:   item.Discount,
+				TaxName:    item.TaxName,
+				TaxRate:    item.TaxRate,
+			},
+		}
+	}
+	return items
+}
+
+// createInvoice drafts an invoice from the merchant to a customer, paid into
+// one of the merchant wallets once finalized
+func (server *Server) createInvoice(ctx *gin.Context) {
+	var req invoiceRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateInvoiceRequest(ctx, req) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.CreateInvoiceTx(ctx, db.CreateInvoiceTxParams{
+		Issuer:           payload.Username,
+		MerchantWalletID: req.WalletID,
+		Customer:         req.Customer,
+		Currency:         req.Currency,
+		Memo:             req.Memo,
+		DueAt:            req.DueAt,
+		Items:            req.items(),
+	})
+
+	if err != nil {
+		respondInvoiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// updateInvoice replaces the contents of a draft invoice
+func (server *Server) updateInvoice(ctx *gin.Context) {
+	var req invoiceRequest
+
+	invoice, ok := server.issuedInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateInvoiceRequest(ctx, req) {
+		return
+	}
+
+	result, err := server.store.UpdateInvoiceTx(ctx, db.UpdateInvoiceTxParams{
+		ID:               invoice.ID,
+		MerchantWalletID: req.WalletID,
+		Customer:         req.Customer,
+		Currency:         req.Currency,
+		Memo:             req.Memo,
+		DueAt:            req.DueAt,
+		Items:            req.items(),
+	})
+
+	if err != nil {
+		respondInvoiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// finalizeInvoice numbers a draft invoice and sends it to the customer
+func (server *Server) finalizeInvoice(ctx *gin.Context) {
+	invoice, ok := server.issuedInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.FinalizeInvoiceTx(ctx, db.FinalizeInvoiceTxParams{
+		ID:  invoice.ID,
+		Now: time.Now(),
+	})
+
+	if err != nil {
+		respondInvoiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// voidInvoice cancels an unpaid invoice, on behalf of its issuer or an admin
+func (server *Server) voidInvoice(ctx *gin.Context) {
+	invoice, ok := server.invoiceFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	if !authorizeOwner(ctx, invoice.Issuer, permissionWriteAny) {
+		return
+	}
+
+	invoice, err := server.store.VoidInvoiceTx(ctx, db.VoidInvoiceTxParams{
+		ID:  invoice.ID,
+		Now: time.Now(),
+	})
+
+	if err != nil {
+		respondInvoiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invoice)
+}
+
+type payInvoiceRequest struct {
+	FromWalletID int64 `json:"from_wallet_id" binding:"required,min=1"`
+}
+
+// payInvoice pays the total of the invoice from a wallet of its customer
+func (server *Server) payInvoice(ctx *gin.Context) {
+	var req payInvoiceRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	invoice, ok := server.invoiceFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != invoice.Customer {
+		err := errors.New("only the customer can pay the invoice")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	fromWallet, valid := server.validateWallet(ctx, req.FromWalletID, invoice.Currency)
+	if !valid {
+		return
+	}
+
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+
+	result, err := server.store.PayInvoiceTx(ctx, db.PayInvoiceTxParams{
+		ID:           invoice.ID,
+		FromWalletID: req.FromWalletID,
+		Now:          now,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk:     server.riskEngine,
+		Fees:     server.fees(now),
+		Cashback: server.cashback(now),
+	})
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		respondInvoiceError(ctx, err)
+		return
+	}
+
+	if result.Review != nil {
+		// the amount is held until the review is decided
+		ctx.JSON(http.StatusAccepted, result)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type getInvoiceRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json html"`
+}
+
+// getInvoice returns the invoice with its items and tax breakdown, either as
+// JSON or as an HTML document ready to print
+func (server *Server) getInvoice(ctx *gin.Context) {
+	var req getInvoiceRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	invoice, ok := server.invoiceFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	// drafts are only shown to the customer once finalized
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != invoice.Customer || invoice.Status == db.InvoiceDraft {
+		if !authorizeOwner(ctx, invoice.Issuer, permissionReadAny) {
+			return
+		}
+	}
+
+	items, err := server.store.ListInvoiceItems(ctx, invoice.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result := db.InvoiceTxResult{
+		Invoice: invoice,
+		Items:   items,
+		Taxes:   db.InvoiceTaxes(items),
+	}
+
+	if req.Format != invoiceFormatHTML {
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+
+	var document bytes.Buffer
+	if err := invoiceTemplate.Execute(&document, result); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", document.Bytes())
+}
+
+type listInvoicesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listOutgoingInvoices returns the invoices issued by the authenticated user,
+// drafts included, newest first
+func (server *Server) listOutgoingInvoices(ctx *gin.Context) {
+	var req listInvoicesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	invoices, err := server.store.ListOutgoingInvoices(ctx, db.ListOutgoingInvoicesParams{
+		Issuer: payload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invoices)
+}
+
+// listIncomingInvoices returns the finalized invoices sent to the
+// authenticated user, newest first
+func (server *Server) listIncomingInvoices(ctx *gin.Context) {
+	var req listInvoicesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	invoices, err := server.store.ListIncomingInvoices(ctx, db.ListIncomingInvoicesParams{
+		Customer: payload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invoices)
+}
+
+type invoiceURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) invoiceFromURI(ctx *gin.Context) (db.Invoice, bool) {
+	var uri invoiceURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Invoice{}, false
+	}
+
+	invoice, err := server.store.GetInvoice(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return invoice, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return invoice, false
+	}
+
+	return invoice, true
+}
+
+// issuedInvoice reads the invoice, which only its issuer may edit or finalize
+func (server *Server) issuedInvoice(ctx *gin.Context) (db.Invoice, bool) {
+	invoice, ok := server.invoiceFromURI(ctx)
+	if !ok {
+		return invoice, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != invoice.Issuer {
+		err := errors.New("only the issuer can change the invoice")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return invoice, false
+	}
+
+	return invoice, true
+}
+
+// validateInvoiceRequest checks the invoice is paid into a wallet of the
+// authenticated user by someone else, at a later date
+func (server *Server) validateInvoiceRequest(ctx *gin.Context, req invoiceRequest) bool {
+	if !req.DueAt.After(time.Now()) {
+		err := errors.New("due_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.Customer == payload.Username {
+		err := errors.New("customer must not be the issuer")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
+	}
+
+	return server.validateMerchantWallet(ctx, req.WalletID, req.Currency)
+}
+
+// respondInvoiceError writes the response for an error of the invoice transactions
+func respondInvoiceError(ctx *gin.Context, err error) {
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "foreign_key_violation" {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	switch {
+	case errors.Is(err, util.ErrInvalidInvoice),
+		errors.Is(err, db.ErrInvoiceNotDraft),
+		errors.Is(err, db.ErrInvoiceNotOpen),
+		errors.Is(err, db.ErrInvoiceClosed),
+		errors.Is(err, db.ErrInvoicePaymentUnderReview),
+		errors.Is(err, db.ErrInsufficientFunds):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, db.ErrWalletFrozen):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// invoiceNumber is the number printed on the invoice, drafts have none yet
+func invoiceNumber(invoice db.Invoice) string {
+	if !invoice.Number.Valid {
+		return "Draft"
+	}
+	return util.FormatInvoiceNumber(invoice.Number.Int64)
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"number": invoiceNumber,
+	"amount": util.FormatAmount,
+	// basis points are hundredths of a percent, printed like cents
+	"percent": util.FormatAmount,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{number .Invoice}}</title>
+</head>
+<body>
+<h1>Invoice {{number .Invoice}}</h1>
+<dl>
+<dt>Status</dt><dd>{{.Invoice.Status}}</dd>
+<dt>From</dt><dd>{{.Invoice.Issuer}}</dd>
+<dt>To</dt><dd>{{.Invoice.Customer}}</dd>
+{{- if .Invoice.FinalizedAt.Valid}}
+<dt>Issued</dt><dd>{{date .Invoice.FinalizedAt.Time}}</dd>
+{{- end}}
+<dt>Due</dt><dd>{{date .Invoice.DueAt}}</dd>
+{{- if .Invoice.PaidAt.Valid}}
+<dt>Paid</dt><dd>{{date .Invoice.PaidAt.Time}}</dd>
+{{- end}}
+</dl>
+{{- with .Invoice.Memo}}
+<p>{{.}}</p>
+{{- end}}
+<table>
+<thead>
+<tr><th>Description</th><th>Quantity</th><th>Unit amount</th><th>Discount</th><th>Tax</th><th>Amount</th></tr>
+</thead>
+<tbody>
+{{- range .Items}}
+<tr><td>{{.Description}}</td><td>{{.Quantity}}</td><td>{{amount .UnitAmount}}</td><td>{{amount .Discount}}</td><td>{{amount .Tax}}</td><td>{{amount .Amount}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<table>
+<tr><th>Subtotal</th><td>{{amount .Invoice.Subtotal}}</td></tr>
+<tr><th>Discount</th><td>-{{amount .Invoice.Discount}}</td></tr>
+{{- range .Taxes}}
+<tr><th>{{.Name}} {{percent .Rate}}% of {{amount .Base}}</th><td>{{amount .Amount}}</td></tr>
+{{- end}}
+<tr><th>Total</th><td>{{amount .Invoice.Total}} {{.Invoice.Currency}}</td></tr>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,590 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInvoiceAPI(t *testing.T) {
+	merchant := randomWallet()
+	customer := util.RandomString(10)
+	dueAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	body := gin.H{
+		"wallet_id": merchant.ID,
+		"customer":  customer,
+		"currency":  merchant.Currency,
+		"memo":      "May consulting",
+		"due_at":    dueAt,
+		"items": []gin.H{
+			{"description": "Consulting hours", "quantity": 10, "unit_amount": 15000, "tax_name": "ISS", "tax_rate": 500},
+			{"description": "Travel", "quantity": 1, "unit_amount": 8000, "discount": 1000},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateInvoiceTxParams) (db.InvoiceTxResult, error) {
+						require.Equal(t, merchant.Owner, arg.Issuer)
+						require.Equal(t, merchant.ID, arg.MerchantWalletID)
+						require.Equal(t, customer, arg.Customer)
+						require.True(t, dueAt.Equal(arg.DueAt))
+						require.Len(t, arg.Items, 2)
+						require.Equal(t, "Consulting hours", arg.Items[0].Description)
+						require.Equal(t, int64(500), arg.Items[0].TaxRate)
+						require.Equal(t, int64(1000), arg.Items[1].Discount)
+						return db.InvoiceTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CustomerIsIssuer",
+			body: gin.H{
+				"wallet_id": merchant.ID,
+				"customer":  merchant.Owner,
+				"currency":  merchant.Currency,
+				"due_at":    dueAt,
+				"items":     body["items"],
+			},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoItems",
+			body: gin.H{
+				"wallet_id": merchant.ID,
+				"customer":  customer,
+				"currency":  merchant.Currency,
+				"due_at":    dueAt,
+				"items":     []gin.H{},
+			},
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotWalletOwner",
+			body:      body,
+			setupAuth: authAs(util.RandomString(10), util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().CreateInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidItems",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InvoiceTxResult{}, fmt.Errorf("%w: discount of item 2 exceeds its amount", util.ErrInvalidInvoice))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerNotFound",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+				store.EXPECT().
+					CreateInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InvoiceTxResult{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/invoices", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetInvoiceAPI(t *testing.T) {
+	invoice := randomInvoice()
+	invoice.Status = db.InvoiceOpen
+	invoice.Number = sql.NullInt64{Int64: 42, Valid: true}
+	invoice.FinalizedAt = sql.NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Valid: true}
+
+	draft := invoice
+	draft.Status = db.InvoiceDraft
+	draft.Number = sql.NullInt64{}
+	draft.FinalizedAt = sql.NullTime{}
+
+	items := []db.InvoiceItem{
+		{InvoiceID: invoice.ID, Position: 1, Description: "Consulting <hours>", Quantity: 10, UnitAmount: 15000, TaxName: "ISS", TaxRate: 500, Tax: 7500, Amount: 157500},
+		{InvoiceID: invoice.ID, Position: 2, Description: "Travel", Quantity: 1, UnitAmount: 8000, Discount: 1000, Amount: 7000},
+	}
+
+	testCases := []struct {
+		name          string
+		invoice       db.Invoice
+		format        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, invoice db.Invoice)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "CustomerJSON",
+			invoice:   invoice,
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().ListInvoiceItems(gomock.Any(), invoice.ID).Times(1).Return(items, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.InvoiceTxResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+				require.Equal(t, invoice.ID, result.Invoice.ID)
+				require.Len(t, result.Items, 2)
+				require.Equal(t, []util.TaxLine{{Name: "ISS", Rate: 500, Base: 150000, Amount: 7500}}, result.Taxes)
+			},
+		},
+		{
+			name:      "IssuerHTML",
+			invoice:   invoice,
+			format:    "html",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().ListInvoiceItems(gomock.Any(), invoice.ID).Times(1).Return(items, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+				page := recorder.Body.String()
+				require.Contains(t, page, "<title>Invoice INV-000042</title>")
+				require.Contains(t, page, "<dt>Issued</dt><dd>2024-05-01</dd>")
+				require.Contains(t, page, "Consulting &lt;hours&gt;")
+				require.Contains(t, page, "<td>150.00</td><td>0.00</td><td>75.00</td><td>1575.00</td>")
+				require.Contains(t, page, "<th>ISS 5.00% of 1500.00</th><td>75.00</td>")
+				require.Contains(t, page, fmt.Sprintf("<th>Total</th><td>%s %s</td>", util.FormatAmount(invoice.Total), invoice.Currency))
+			},
+		},
+		{
+			name:      "UnsupportedFormat",
+			invoice:   invoice,
+			format:    "pdf",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "DraftHiddenFromCustomer",
+			invoice:   draft,
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().ListInvoiceItems(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Stranger",
+			invoice:   invoice,
+			setupAuth: authAs(util.RandomString(10), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().ListInvoiceItems(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Support",
+			invoice:   draft,
+			setupAuth: authAs(util.RandomString(10), util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().ListInvoiceItems(gomock.Any(), invoice.ID).Times(1).Return(items, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			invoice:   invoice,
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore, invoice db.Invoice) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(db.Invoice{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tc.invoice)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/invoices/%d", tc.invoice.ID)
+			if tc.format != "" {
+				url += "?format=" + tc.format
+			}
+
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayInvoiceAPI(t *testing.T) {
+	invoice := randomInvoice()
+	invoice.Status = db.InvoiceOverdue
+
+	fromWallet := randomWallet()
+	fromWallet.Owner = invoice.Customer
+	fromWallet.Currency = invoice.Currency
+
+	stubInvoice := func(store *mockdb.MockStore) {
+		store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubInvoice(store)
+				store.EXPECT().GetWallet(gomock.Any(), fromWallet.ID).Times(1).Return(fromWallet, nil)
+				store.EXPECT().
+					PayInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.PayInvoiceTxParams) (db.PayInvoiceTxResult, error) {
+						require.Equal(t, invoice.ID, arg.ID)
+						require.Equal(t, fromWallet.ID, arg.FromWalletID)
+						require.NotNil(t, arg.Limits)
+						return db.PayInvoiceTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "UnderReview",
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubInvoice(store)
+				store.EXPECT().GetWallet(gomock.Any(), fromWallet.ID).Times(1).Return(fromWallet, nil)
+				store.EXPECT().
+					PayInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayInvoiceTxResult{TrasferTxResult: db.TrasferTxResult{Review: &db.TransferReview{ID: 1}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:      "IssuerPays",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubInvoice(store)
+				store.EXPECT().PayInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "AlreadyPaid",
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubInvoice(store)
+				store.EXPECT().GetWallet(gomock.Any(), fromWallet.ID).Times(1).Return(fromWallet, nil)
+				store.EXPECT().
+					PayInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayInvoiceTxResult{}, db.ErrInvoiceNotOpen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "Blocked",
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubInvoice(store)
+				store.EXPECT().GetWallet(gomock.Any(), fromWallet.ID).Times(1).Return(fromWallet, nil)
+				store.EXPECT().
+					PayInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayInvoiceTxResult{}, db.ErrTransferBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"from_wallet_id": fromWallet.ID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/invoices/%d/pay", invoice.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestInvoiceLifecycleAPI(t *testing.T) {
+	invoice := randomInvoice()
+
+	testCases := []struct {
+		name          string
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "IssuerFinalizes",
+			action:    "finalize",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().
+					FinalizeInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.FinalizeInvoiceTxParams) (db.InvoiceTxResult, error) {
+						require.Equal(t, invoice.ID, arg.ID)
+						return db.InvoiceTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "FinalizeFinalized",
+			action:    "finalize",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().
+					FinalizeInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InvoiceTxResult{}, db.ErrInvoiceNotDraft)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "AdminFinalizes",
+			action:    "finalize",
+			setupAuth: authAs(util.RandomString(10), util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().FinalizeInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "AdminVoids",
+			action:    "void",
+			setupAuth: authAs(util.RandomString(10), util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().
+					VoidInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(invoice, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerVoids",
+			action:    "void",
+			setupAuth: authAs(invoice.Customer, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().VoidInvoiceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "VoidUnderReview",
+			action:    "void",
+			setupAuth: authAs(invoice.Issuer, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), invoice.ID).Times(1).Return(invoice, nil)
+				store.EXPECT().
+					VoidInvoiceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Invoice{}, db.ErrInvoicePaymentUnderReview)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/invoices/%d/%s", invoice.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomInvoice() db.Invoice {
+	return db.Invoice{
+		ID:               util.RandomInt(1, 1000),
+		Issuer:           util.RandomString(10),
+		MerchantWalletID: util.RandomInt(1, 100),
+		Customer:         util.RandomString(10),
+		Currency:         util.RandomCurrency(),
+		DueAt:            time.Now().Add(7 * 24 * time.Hour),
+		Status:           db.InvoiceDraft,
+		Subtotal:         158000,
+		Discount:         1000,
+		Tax:              7500,
+		Total:            164500,
+	}
+}
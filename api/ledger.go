@@ -0,0 +1,91 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listLedgerAccounts returns the system accounts of every currency, the
+// accounts of the wallets are found through the wallets
+func (server *Server) listLedgerAccounts(ctx *gin.Context) {
+	accounts, err := server.store.ListSystemLedgerAccounts(ctx)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+func (server *Server) getTrialBalance(ctx *gin.Context) {
+	report, err := server.store.TrialBalance(ctx)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+type verifyChainRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"omitempty,min=1"`
+}
+
+// chainVerifyBatchSize is how many rows the verification reads at once
+const chainVerifyBatchSize = 1000
+
+// verifyChain walks the entry chain and reports the first broken link,
+// checkpoints must be signed with the key of this server
+func (server *Server) verifyChain(ctx *gin.Context) {
+	var req verifyChainRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.VerifyChain(ctx, db.VerifyChainParams{
+		WalletID:  req.WalletID,
+		PublicKey: server.config.ChainKey().Public().(ed25519.PublicKey),
+		BatchSize: chainVerifyBatchSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listChainCheckpointsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listChainCheckpoints returns the newest checkpoints first
+func (server *Server) listChainCheckpoints(ctx *gin.Context) {
+	var req listChainCheckpointsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	checkpoints, err := server.store.ListRecentChainCheckpoints(ctx, db.ListRecentChainCheckpointsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, checkpoints)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTrialBalanceAPI(t *testing.T) {
+	user := util.RandomString(6)
+	report := db.TrialBalanceResult{
+		Currencies: []db.TrialBalanceCurrency{{Currency: util.BRL, Debits: 100, Credits: 100}},
+		Balanced:   true,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(user, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TrialBalance(gomock.Any()).Times(1).Return(report, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.TrialBalanceResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, report, got)
+			},
+		},
+		{
+			name:      "Customer",
+			setupAuth: authAs(user, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TrialBalance(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/ledger/trial-balance", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifyChainAPI(t *testing.T) {
+	user := util.RandomString(6)
+	broken := db.VerifyChainResult{
+		EntriesChecked: 3,
+		Break:          &db.ChainBreak{WalletID: 7, EntryID: 12, Reason: "hash doesn't match the entry"},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(user, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyChain(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.VerifyChainParams) (db.VerifyChainResult, error) {
+						require.Zero(t, arg.WalletID)
+						require.NotNil(t, arg.PublicKey)
+						return db.VerifyChainResult{Valid: true, EntriesChecked: 10}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.VerifyChainResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.True(t, got.Valid)
+			},
+		},
+		{
+			name:      "BrokenWallet",
+			query:     "?wallet_id=7",
+			setupAuth: authAs(user, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyChain(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.VerifyChainParams) (db.VerifyChainResult, error) {
+						require.Equal(t, int64(7), arg.WalletID)
+						return broken, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.VerifyChainResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, broken, got)
+			},
+		},
+		{
+			name:      "InvalidWalletID",
+			query:     "?wallet_id=-1",
+			setupAuth: authAs(user, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyChain(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "Customer",
+			setupAuth: authAs(user, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyChain(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/ledger/chain/verify"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
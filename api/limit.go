@@ -0,0 +1,255 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type userLimitsURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+type transferLimitsResponse struct {
+	Owner      string              `json:"owner"`
+	Limits     util.TransferLimits `json:"limits"`
+	Overridden bool                `json:"overridden"`
+}
+
+func (server *Server) getTransferLimits(ctx *gin.Context) {
+	var uri userLimitsURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionReadAny) {
+		return
+	}
+
+	rsp := transferLimitsResponse{
+		Owner:  uri.Username,
+		Limits: server.transferLimits,
+	}
+
+	override, err := server.store.GetTransferLimits(ctx, uri.Username)
+
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err == nil {
+		rsp.Limits = db.NewTransferLimits(override)
+		rsp.Overridden = true
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type updateTransferLimitsRequest struct {
+	PerTransfer      int64 `json:"per_transfer" binding:"min=0"`
+	Daily            int64 `json:"daily" binding:"min=0"`
+	Monthly          int64 `json:"monthly" binding:"min=0"`
+	DailyCount       int64 `json:"daily_count" binding:"min=0"`
+	NightPerTransfer int64 `json:"night_per_transfer" binding:"min=0"`
+	Nightly          int64 `json:"nightly" binding:"min=0"`
+}
+
+// updateTransferLimits overrides every limit of a user, skipping the cooling off period
+func (server *Server) updateTransferLimits(ctx *gin.Context) {
+	var uri userLimitsURI
+	var req updateTransferLimitsRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.UpsertTransferLimitsParams{
+		Owner:            uri.Username,
+		PerTransfer:      req.PerTransfer,
+		Daily:            req.Daily,
+		Monthly:          req.Monthly,
+		DailyCount:       req.DailyCount,
+		NightPerTransfer: req.NightPerTransfer,
+		Nightly:          req.Nightly,
+		UpdatedBy:        payload.Username,
+	}
+
+	override, err := server.store.UpsertTransferLimits(ctx, arg)
+
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferLimitsResponse{
+		Owner:      override.Owner,
+		Limits:     db.NewTransferLimits(override),
+		Overridden: true,
+	})
+}
+
+// resetTransferLimits drops the override so the user goes back to the defaults
+func (server *Server) resetTransferLimits(ctx *gin.Context) {
+	var uri userLimitsURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteTransferLimits(ctx, uri.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferLimitsResponse{
+		Owner:  uri.Username,
+		Limits: server.transferLimits,
+	})
+}
+
+type createLimitRequestRequest struct {
+	LimitName string `json:"limit_name" binding:"required,limit_name"`
+	Value     int64  `json:"value" binding:"min=0"`
+}
+
+func (server *Server) createLimitRequest(ctx *gin.Context) {
+	var req createLimitRequestRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.RequestLimitChangeTxParams{
+		Owner:      payload.Username,
+		LimitName:  req.LimitName,
+		Value:      req.Value,
+		Defaults:   server.transferLimits,
+		CoolingOff: server.config.LimitIncreaseCoolingOff,
+		Now:        time.Now(),
+	}
+
+	request, err := server.store.RequestLimitChangeTx(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+type listLimitRequestsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listLimitRequests(ctx *gin.Context) {
+	var req listLimitRequestsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.ListLimitIncreaseRequestsParams{
+		Owner:  payload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	requests, err := server.store.ListLimitIncreaseRequests(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+type limitRequestURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) cancelLimitRequest(ctx *gin.Context) {
+	server.decideLimitRequest(ctx, db.LimitIncreaseCancelled, permissionWriteAny)
+}
+
+func (server *Server) rejectLimitRequest(ctx *gin.Context) {
+	server.decideLimitRequest(ctx, db.LimitIncreaseRejected, permissionLimitsWrite)
+}
+
+// decideLimitRequest ends a pending request before its cooling off period is over
+func (server *Server) decideLimitRequest(ctx *gin.Context, status string, anyPermission string) {
+	var uri limitRequestURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, err := server.store.GetLimitIncreaseRequest(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, request.Owner, anyPermission) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	request, err = server.store.DecideLimitIncreaseRequest(ctx, db.DecideLimitIncreaseRequestParams{
+		ID:        request.ID,
+		Status:    status,
+		DecidedBy: payload.Username,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("limit request is no longer pending")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,333 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransferLimitsAPI(t *testing.T) {
+	username := util.RandomString(6)
+	override := db.TransferLimit{
+		Owner:       username,
+		PerTransfer: 100,
+		Daily:       500,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Defaults",
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferLimits(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.TransferLimit{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp transferLimitsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.False(t, rsp.Overridden)
+				require.Equal(t, int64(1000), rsp.Limits.PerTransfer)
+			},
+		},
+		{
+			name:      "Overridden",
+			setupAuth: authAs(util.RandomString(6), util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferLimits(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(override, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp transferLimitsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Overridden)
+				require.Equal(t, db.NewTransferLimits(override), rsp.Limits)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			setupAuth: authAs(util.RandomString(6), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferLimits(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.transferLimits = util.TransferLimits{PerTransfer: 1000}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/users/%s/limits", username), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateLimitRequestAPI(t *testing.T) {
+	username := util.RandomString(6)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"limit_name": util.LimitNightly, "value": 200000},
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RequestLimitChangeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RequestLimitChangeTxParams) (db.LimitIncreaseRequest, error) {
+						require.Equal(t, username, arg.Owner)
+						require.Equal(t, util.LimitNightly, arg.LimitName)
+						require.Equal(t, int64(200000), arg.Value)
+						require.Equal(t, 24*time.Hour, arg.CoolingOff)
+						return db.LimitIncreaseRequest{ID: 1, Owner: username, Status: db.LimitIncreasePending}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidLimitName",
+			body:      gin.H{"limit_name": "weekly", "value": 200000},
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RequestLimitChangeTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NegativeValue",
+			body:      gin.H{"limit_name": util.LimitDaily, "value": -1},
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RequestLimitChangeTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "SupportCannotRequest",
+			body:      gin.H{"limit_name": util.LimitDaily, "value": 100},
+			setupAuth: authAs(username, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RequestLimitChangeTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.LimitIncreaseCoolingOff = 24 * time.Hour
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/limit-requests", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCancelLimitRequestAPI(t *testing.T) {
+	username := util.RandomString(6)
+	request := db.LimitIncreaseRequest{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     username,
+		LimitName: util.LimitDaily,
+		Status:    db.LimitIncreasePending,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLimitIncreaseRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().
+					DecideLimitIncreaseRequest(gomock.Any(), gomock.Eq(db.DecideLimitIncreaseRequestParams{
+						ID:        request.ID,
+						Status:    db.LimitIncreaseCancelled,
+						DecidedBy: username,
+					})).
+					Times(1).
+					Return(request, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotPending",
+			setupAuth: authAs(username, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLimitIncreaseRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().
+					DecideLimitIncreaseRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LimitIncreaseRequest{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			setupAuth: authAs(util.RandomString(6), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLimitIncreaseRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().DecideLimitIncreaseRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/limit-requests/%d/cancel", request.ID)
+			httpRequest, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, httpRequest, server.tokenMaker)
+			server.router.ServeHTTP(recorder, httpRequest)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferLimitExceeded(t *testing.T) {
+	from := randomWallet()
+	to := randomWallet()
+	to.ID = from.ID + 100
+	to.Currency = from.Currency
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+	store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TrasferTxParms) (db.TrasferTxResult, error) {
+			require.NotNil(t, arg.Limits)
+			require.Equal(t, int64(50), arg.Limits.Defaults.PerTransfer)
+			return db.TrasferTxResult{}, &db.LimitExceededError{Limit: util.LimitPerTransfer, Max: 50}
+		})
+
+	server := newTestServer(t, store)
+	server.transferLimits = util.TransferLimits{PerTransfer: 50}
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_wallet_id": from.ID,
+		"to_wallet_id":   to.ID,
+		"amount":         100,
+		"currency":       from.Currency,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	authAs(from.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "per_transfer")
+}
@@ -2,11 +2,43 @@ package api
 
 import (
 	"os"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/risk"
+	"picpay_simplificado/stream"
+	"picpay_simplificado/util"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
 )
 
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:       util.RandomString(32),
+		APIKeySigningKey:        util.RandomString(32),
+		AccessTokenDuration:     time.Minute,
+		BRCodeMerchantCity:      "SAO PAULO",
+		BRCodeLocationURL:       "pix.example.com/qr/v2/",
+		PlatformRevenueOwner:    "platform",
+		EscrowReleaseAfter:      24 * time.Hour,
+		BlobLocalDir:            t.TempDir(),
+		DisputeOpenWindow:       90 * 24 * time.Hour,
+		DisputeResponseWindow:   7 * 24 * time.Hour,
+		DisputeNegativeReserve:  1000,
+		InstallmentFundingOwner: "platform",
+		CheckoutURL:             "https://pay.example.com/checkout/",
+		PaymentLinkURL:          "https://pay.example.com/links/",
+		CheckoutSessionTTL:      time.Hour,
+		CashbackFundingOwner:    "marketing",
+	}
+
+	server, err := NewServer(config, store, stream.NewBroker(), risk.NewEngine())
+	require.NoError(t, err)
+
+	return server
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
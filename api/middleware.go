@@ -0,0 +1,222 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+
+	apiKeyHeaderKey    = "X-Api-Key"
+	timestampHeaderKey = "X-Timestamp"
+	nonceHeaderKey     = "X-Nonce"
+	signatureHeaderKey = "X-Signature"
+
+	// signatureMaxSkew is how old or how far in the future a signed request may be
+	signatureMaxSkew = 5 * time.Minute
+)
+
+var errInvalidSignature = errors.New("request signature is invalid")
+
+// authMiddleware authenticates the request and stores the payload in the context.
+// It accepts user tokens and API keys as bearer credentials, or requests signed
+// with an API key
+func authMiddleware(tokenMaker token.Maker, apiSigner *token.APIKeySigner, store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var payload *token.Payload
+		var err error
+
+		if len(ctx.GetHeader(apiKeyHeaderKey)) > 0 {
+			payload, err = verifySignedRequest(ctx, apiSigner, store)
+		} else {
+			payload, err = verifyBearer(ctx, tokenMaker, store)
+		}
+
+		if err != nil {
+			status := http.StatusUnauthorized
+			if !errors.Is(err, token.ErrInvalidToken) && !errors.Is(err, token.ErrExpiredToken) && !errors.Is(err, errInvalidSignature) {
+				status = http.StatusInternalServerError
+			}
+			ctx.AbortWithStatusJSON(status, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
+
+func verifyBearer(ctx *gin.Context, tokenMaker token.Maker, store db.Store) (*token.Payload, error) {
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+	if len(authorizationHeader) == 0 {
+		return nil, fmt.Errorf("%w: authorization header is not provided", token.ErrInvalidToken)
+	}
+
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: invalid authorization header format", token.ErrInvalidToken)
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		return nil, fmt.Errorf("%w: unsupported authorization type %s", token.ErrInvalidToken, authorizationType)
+	}
+
+	if strings.HasPrefix(fields[1], token.APIKeyPrefix) {
+		prefix, err := token.ParseAPIKey(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return apiKeyPayload(ctx, store, prefix, func(apiKey db.ApiKey) bool {
+			return token.HashAPIKey(fields[1]) == apiKey.HashedKey
+		})
+	}
+
+	return tokenMaker.VerifyToken(fields[1])
+}
+
+// verifySignedRequest checks an HMAC-SHA256 signature made with the signing
+// secret of the API key over the method, path, timestamp, nonce and body.
+// A nonce can only be used once while its timestamp is inside the accepted window
+func verifySignedRequest(ctx *gin.Context, apiSigner *token.APIKeySigner, store db.Store) (*token.Payload, error) {
+	timestamp := ctx.GetHeader(timestampHeaderKey)
+	nonce := ctx.GetHeader(nonceHeaderKey)
+	signature := ctx.GetHeader(signatureHeaderKey)
+
+	if timestamp == "" || nonce == "" || signature == "" {
+		return nil, fmt.Errorf("%w: timestamp, nonce and signature headers are required", errInvalidSignature)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp", errInvalidSignature)
+	}
+
+	signedAt := time.Unix(unix, 0)
+	if time.Since(signedAt) > signatureMaxSkew || time.Until(signedAt) > signatureMaxSkew {
+		return nil, fmt.Errorf("%w: timestamp is outside the accepted window", errInvalidSignature)
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	payload, err := apiKeyPayload(ctx, store, ctx.GetHeader(apiKeyHeaderKey), func(apiKey db.ApiKey) bool {
+		return token.VerifyRequestSignature(apiSigner.SigningSecret(apiKey.HashedKey), signature, ctx.Request.Method, ctx.Request.URL.RequestURI(), timestamp, nonce, body)
+	})
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidToken) {
+			return nil, fmt.Errorf("%w: %v", errInvalidSignature, err)
+		}
+		return nil, err
+	}
+
+	apiKeyID := payload.APIKeyID
+
+	err = store.DeleteExpiredApiKeyNonces(ctx, db.DeleteExpiredApiKeyNoncesParams{
+		ApiKeyID:  apiKeyID,
+		CreatedAt: time.Now().Add(-2 * signatureMaxSkew),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := store.CreateApiKeyNonce(ctx, db.CreateApiKeyNonceParams{
+		ApiKeyID: apiKeyID,
+		Nonce:    nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if inserted == 0 {
+		return nil, fmt.Errorf("%w: nonce was already used", errInvalidSignature)
+	}
+
+	return payload, nil
+}
+
+// apiKeyPayload loads an active API key by its prefix and builds a payload
+// carrying the owner's role restricted to the key scopes
+func apiKeyPayload(ctx *gin.Context, store db.Store, prefix string, verify func(db.ApiKey) bool) (*token.Payload, error) {
+	apiKey, err := store.GetApiKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, token.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if apiKey.RevokedAt.Valid || !verify(apiKey) {
+		return nil, token.ErrInvalidToken
+	}
+
+	user, err := store.GetUser(ctx, apiKey.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.UpdateApiKeyLastUsed(ctx, apiKey.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &token.Payload{
+		ID:       apiKey.Prefix,
+		Username: user.Username,
+		Role:     user.Role,
+		Scopes:   apiKey.Scopes,
+		APIKeyID: apiKey.ID,
+		IssuedAt: apiKey.CreatedAt,
+	}
+
+	return payload, nil
+}
+
+// requirePermissions aborts the request unless the authenticated role has every permission
+func requirePermissions(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		for _, permission := range permissions {
+			if !authorized(payload, permission) {
+				err := fmt.Errorf("role %s is not allowed to %s", payload.Role, permission)
+				ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// authorizeOwner checks the authenticated user owns the resource, or that its
+// role is allowed to act on other users' data through anyPermission
+func authorizeOwner(ctx *gin.Context, owner string, anyPermission string) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if payload.Username == owner || authorized(payload, anyPermission) {
+		return true
+	}
+
+	err := errors.New("resource doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+	return false
+}
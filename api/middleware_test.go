@@ -0,0 +1,403 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func addAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	authorizationType string,
+	username string,
+	role string,
+	duration time.Duration,
+) {
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	username := util.RandomString(6)
+
+	testCases := []struct {
+		name          string
+		permission    string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			permission: permissionWalletsRead,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.CustomerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "NoAuthorization",
+			permission: permissionWalletsRead,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "UnsupportedAuthorization",
+			permission: permissionWalletsRead,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "unsupported", username, util.CustomerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "InvalidAuthorizationFormat",
+			permission: permissionWalletsRead,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "", username, util.CustomerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "ExpiredToken",
+			permission: permissionWalletsRead,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.CustomerRole, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "CustomerCannotList",
+			permission: permissionWalletsList,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.CustomerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "SupportCanList",
+			permission: permissionWalletsList,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.SupportRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "SupportCannotRefund",
+			permission: permissionRefundsWrite,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.SupportRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "AdminCanRefund",
+			permission: permissionRefundsWrite,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.apiSigner, server.store),
+				requirePermissions(tc.permission),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func signRequest(request *http.Request, apiKey db.ApiKey, secret string, body []byte, signedAt time.Time, nonce string) {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := token.SignRequest(secret, request.Method, request.URL.RequestURI(), timestamp, nonce, body)
+
+	request.Header.Set(apiKeyHeaderKey, apiKey.Prefix)
+	request.Header.Set(timestampHeaderKey, timestamp)
+	request.Header.Set(nonceHeaderKey, nonce)
+	request.Header.Set(signatureHeaderKey, signature)
+}
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	user, _, err := randomUser(t)
+	require.NoError(t, err)
+	user.Role = util.MerchantRole
+
+	generated, err := token.NewAPIKey()
+	require.NoError(t, err)
+
+	apiKey := db.ApiKey{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     user.Username,
+		Name:      util.RandomString(6),
+		Prefix:    generated.Prefix,
+		HashedKey: generated.HashedKey,
+		Scopes:    []string{permissionTransfersRead},
+	}
+
+	revokedKey := apiKey
+	revokedKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	body := []byte(`{"amount":100}`)
+
+	validKeyStubs := func(store *mockdb.MockStore, key db.ApiKey) {
+		store.EXPECT().
+			GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+			Times(1).
+			Return(key, nil)
+		store.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.Username)).
+			Times(1).
+			Return(user, nil)
+		store.EXPECT().
+			UpdateApiKeyLastUsed(gomock.Any(), gomock.Eq(key.ID)).
+			Times(1)
+	}
+
+	testCases := []struct {
+		name          string
+		permission    string
+		setupAuth     func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "BearerKeyOK",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+generated.Key)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				validKeyStubs(store, apiKey)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "BearerKeyOutOfScope",
+			permission: permissionTransfersWrite,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+generated.Key)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				validKeyStubs(store, apiKey)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "BearerKeyRevoked",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+generated.Key)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).
+					Times(1).
+					Return(revokedKey, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "BearerKeyWrongSecret",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+generated.Key+"x")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).
+					Times(1).
+					Return(apiKey, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "SignedOK",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				signRequest(request, apiKey, apiSigner.SigningSecret(apiKey.HashedKey), body, time.Now(), "nonce-1")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				validKeyStubs(store, apiKey)
+				store.EXPECT().
+					DeleteExpiredApiKeyNonces(gomock.Any(), gomock.Any()).
+					Times(1)
+				store.EXPECT().
+					CreateApiKeyNonce(gomock.Any(), gomock.Eq(db.CreateApiKeyNonceParams{ApiKeyID: apiKey.ID, Nonce: "nonce-1"})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "SignedReplay",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				signRequest(request, apiKey, apiSigner.SigningSecret(apiKey.HashedKey), body, time.Now(), "nonce-1")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				validKeyStubs(store, apiKey)
+				store.EXPECT().
+					DeleteExpiredApiKeyNonces(gomock.Any(), gomock.Any()).
+					Times(1)
+				store.EXPECT().
+					CreateApiKeyNonce(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "SignedStaleTimestamp",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				signRequest(request, apiKey, apiSigner.SigningSecret(apiKey.HashedKey), body, time.Now().Add(-time.Hour), "nonce-2")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "SignedWithHashedKey",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				signRequest(request, apiKey, apiKey.HashedKey, body, time.Now(), "nonce-4")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					CreateApiKeyNonce(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "SignedTamperedBody",
+			permission: permissionTransfersRead,
+			setupAuth: func(t *testing.T, request *http.Request, apiSigner *token.APIKeySigner) {
+				signRequest(request, apiKey, apiSigner.SigningSecret(apiKey.HashedKey), []byte(`{"amount":1}`), time.Now(), "nonce-3")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					CreateApiKeyNonce(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			authPath := "/auth"
+			server.router.POST(
+				authPath,
+				authMiddleware(server.tokenMaker, server.apiSigner, server.store),
+				requirePermissions(tc.permission),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, authPath, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.apiSigner)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type createPaymentRequestRequest struct {
+	ToWalletID  int64     `json:"to_wallet_id" binding:"required,min=1"`
+	Payer       string    `json:"payer"`
+	Amount      int64     `json:"amount" binding:"required,gt=0"`
+	Currency    string    `json:"currency" binding:"required,currency"`
+	Description string    `json:"description" binding:"max=140"`
+	ExpiresAt   time.Time `json:"expires_at" binding:"required"`
+}
+
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !req.ExpiresAt.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	toWallet, valid := server.validateWallet(ctx, req.ToWalletID, req.Currency)
+	if !valid {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if toWallet.Owner != payload.Username {
+		err := errors.New("to wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if req.Payer == payload.Username {
+		err := errors.New("payer must not be the requester")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePaymentRequestParams{
+		Requester:   payload.Username,
+		ToWalletID:  req.ToWalletID,
+		Payer:       sql.NullString{String: req.Payer, Valid: req.Payer != ""},
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	request, err := server.store.CreatePaymentRequest(ctx, arg)
+
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+type paymentRequestURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getPaymentRequest(ctx *gin.Context) {
+	request, ok := server.visiblePaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+type listPaymentRequestsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listOutgoingPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListOutgoingPaymentRequests(ctx, db.ListOutgoingPaymentRequestsParams{
+		Requester: payload.Username,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+func (server *Server) listIncomingPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListIncomingPaymentRequests(ctx, db.ListIncomingPaymentRequestsParams{
+		Payer:  sql.NullString{String: payload.Username, Valid: true},
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+type acceptPaymentRequestRequest struct {
+	FromWalletID int64 `json:"from_wallet_id" binding:"required,min=1"`
+}
+
+func (server *Server) acceptPaymentRequest(ctx *gin.Context) {
+	var req acceptPaymentRequestRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, ok := server.visiblePaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	server.payPaymentRequest(ctx, request, req.FromWalletID)
+}
+
+// payPaymentRequest pays the request from the wallet of the authenticated
+// user, writing the result as the response
+func (server *Server) payPaymentRequest(ctx *gin.Context, request db.PaymentRequest, fromWalletID int64) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !canPayPaymentRequest(payload, request) {
+		err := errors.New("payment request can't be paid by the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	fromWallet, valid := server.validateWallet(ctx, fromWalletID, request.Currency)
+	if !valid {
+		return
+	}
+
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+
+	result, err := server.store.AcceptPaymentRequestTx(ctx, db.AcceptPaymentRequestTxParams{
+		ID:           request.ID,
+		Payer:        payload.Username,
+		FromWalletID: fromWalletID,
+		Now:          now,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk:     server.riskEngine,
+		Fees:     server.fees(now),
+		Cashback: server.cashback(now),
+	})
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrPaymentRequestNotPending) || errors.Is(err, db.ErrPaymentRequestExpired) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Review != nil {
+		// the amount is held until the review is decided
+		ctx.JSON(http.StatusAccepted, result)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) declinePaymentRequest(ctx *gin.Context) {
+	request, ok := server.visiblePaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	// open requests are ignored instead of declined
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Payer.String != payload.Username {
+		err := errors.New("only the payer can decline the payment request")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	server.decidePaymentRequest(ctx, request, db.PaymentRequestDeclined)
+}
+
+func (server *Server) cancelPaymentRequest(ctx *gin.Context) {
+	request, ok := server.visiblePaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if !authorizeOwner(ctx, request.Requester, permissionWriteAny) {
+		return
+	}
+
+	server.decidePaymentRequest(ctx, request, db.PaymentRequestCancelled)
+}
+
+func (server *Server) decidePaymentRequest(ctx *gin.Context, request db.PaymentRequest, status string) {
+	request, err := server.store.DecidePaymentRequestTx(ctx, db.DecidePaymentRequestTxParams{
+		ID:     request.ID,
+		Status: status,
+		Now:    time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrPaymentRequestNotPending) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// visiblePaymentRequest loads the payment request in the URI. Open requests
+// can be paid by anyone, so they are visible to every user
+func (server *Server) visiblePaymentRequest(ctx *gin.Context) (db.PaymentRequest, bool) {
+	var uri paymentRequestURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.PaymentRequest{}, false
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return request, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return request, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !request.Payer.Valid || request.Payer.String == payload.Username {
+		return request, true
+	}
+
+	return request, authorizeOwner(ctx, request.Requester, permissionReadAny)
+}
+
+// canPayPaymentRequest reports whether the user may pay the request, which
+// is never the requester
+func canPayPaymentRequest(payload *token.Payload, request db.PaymentRequest) bool {
+	if payload.Username == request.Requester {
+		return false
+	}
+
+	return !request.Payer.Valid || request.Payer.String == payload.Username
+}
@@ -0,0 +1,308 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptPaymentRequestAPI(t *testing.T) {
+	from := randomWallet()
+	request := randomPaymentRequest(from.Currency)
+	request.Payer = sql.NullString{String: from.Owner, Valid: true}
+
+	open := request
+	open.Payer = sql.NullString{}
+
+	testCases := []struct {
+		name          string
+		request       db.PaymentRequest
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			request:   request,
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AcceptPaymentRequestTxParams) (db.AcceptPaymentRequestTxResult, error) {
+						require.Equal(t, request.ID, arg.ID)
+						require.Equal(t, from.Owner, arg.Payer)
+						require.Equal(t, from.ID, arg.FromWalletID)
+						require.NotNil(t, arg.Limits)
+						require.NotNil(t, arg.Risk)
+						require.NotNil(t, arg.Fees)
+						return db.AcceptPaymentRequestTxResult{PaymentRequest: request}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "OpenRequest",
+			request:   open,
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), open.ID).Times(1).Return(open, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptPaymentRequestTxResult{
+						TrasferTxResult: db.TrasferTxResult{Review: &db.TransferReview{ID: 1}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:      "NotThePayer",
+			request:   request,
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "RequesterCantPay",
+			request:   open,
+			setupAuth: authAs(open.Requester, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), open.ID).Times(1).Return(open, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Expired",
+			request:   request,
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptPaymentRequestTxResult{}, db.ErrPaymentRequestExpired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "Blocked",
+			request:   request,
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptPaymentRequestTxResult{}, db.ErrTransferBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"from_wallet_id": from.ID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/payment-requests/%d/accept", tc.request.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDecidePaymentRequestAPI(t *testing.T) {
+	request := randomPaymentRequest(util.RandomCurrency())
+	request.Payer = sql.NullString{String: util.RandomString(6), Valid: true}
+
+	testCases := []struct {
+		name          string
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Decline",
+			action:    "decline",
+			setupAuth: authAs(request.Payer.String, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().
+					DecidePaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DecidePaymentRequestTxParams) (db.PaymentRequest, error) {
+						require.Equal(t, db.PaymentRequestDeclined, arg.Status)
+						return request, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "RequesterCantDecline",
+			action:    "decline",
+			setupAuth: authAs(request.Requester, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().DecidePaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Cancel",
+			action:    "cancel",
+			setupAuth: authAs(request.Requester, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().
+					DecidePaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PaymentRequest{}, db.ErrPaymentRequestNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "PayerCantCancel",
+			action:    "cancel",
+			setupAuth: authAs(request.Payer.String, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().DecidePaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "HiddenFromOthers",
+			action:    "cancel",
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), request.ID).Times(1).Return(request, nil)
+				store.EXPECT().DecidePaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payment-requests/%d/%s", request.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListIncomingPaymentRequestsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payer := util.RandomString(6)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListIncomingPaymentRequests(gomock.Any(), gomock.Eq(db.ListIncomingPaymentRequestsParams{
+			Payer:  sql.NullString{String: payer, Valid: true},
+			Limit:  5,
+			Offset: 0,
+		})).
+		Times(1).
+		Return([]db.PaymentRequest{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/payment-requests/incoming?page_id=1&page_size=5", nil)
+	require.NoError(t, err)
+
+	authAs(payer, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func randomPaymentRequest(currency string) db.PaymentRequest {
+	return db.PaymentRequest{
+		ID:         util.RandomInt(1, 1000),
+		Requester:  util.RandomString(8),
+		ToWalletID: util.RandomInt(101, 200),
+		Amount:     util.RandomMoney(),
+		Currency:   currency,
+		Status:     db.PaymentRequestPending,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+}
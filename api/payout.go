@@ -0,0 +1,471 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	maxPayoutRows         = 1000
+	maxPayoutReferenceLen = 140
+)
+
+// requiredPayoutCSVColumns must be in the header of an uploaded batch, in any
+// order, with an optional reference column. Amounts are in cents
+var requiredPayoutCSVColumns = []string{"recipient_key", "amount", "currency"}
+
+type payoutRowRequest struct {
+	// Row is the number of the row in the upload, starting from 1
+	Row          int    `json:"-"`
+	RecipientKey string `json:"recipient_key"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	Reference    string `json:"reference"`
+}
+
+type createPayoutBatchRequest struct {
+	FromWalletID int64              `json:"from_wallet_id" form:"from_wallet_id" binding:"required,min=1"`
+	Description  string             `json:"description" form:"description" binding:"max=140"`
+	Rows         []payoutRowRequest `json:"rows" form:"-"`
+}
+
+// payoutRowError is why a row of an uploaded batch is invalid, numbered from 1
+type payoutRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// createPayoutBatch queues a batch uploaded as JSON or as a CSV file in a
+// multipart form. Every row is checked before the batch is queued, and the
+// batch is refused with the errors of every invalid row
+func (server *Server) createPayoutBatch(ctx *gin.Context) {
+	var req createPayoutBatchRequest
+	var rowErrors []payoutRowError
+
+	if ctx.ContentType() == binding.MIMEMultipartPOSTForm {
+		if err := ctx.ShouldBind(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		defer reader.Close()
+
+		req.Rows, rowErrors, err = parsePayoutCSV(reader)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	} else {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		for i := range req.Rows {
+			req.Rows[i].Row = i + 1
+		}
+	}
+
+	if len(req.Rows)+len(rowErrors) == 0 {
+		err := errors.New("payout batch has no rows")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if len(req.Rows)+len(rowErrors) > maxPayoutRows {
+		err := fmt.Errorf("payout batch has more than %d rows", maxPayoutRows)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromWallet, err := server.store.GetWallet(ctx, req.FromWalletID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if fromWallet.IsFrozen {
+		err = fmt.Errorf("wallet [%d] is frozen", fromWallet.ID)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	rows := make([]db.CreatePayoutRowParams, 0, len(req.Rows))
+	recipients := make(map[string]db.Wallet)
+	var total int64
+
+	for _, row := range req.Rows {
+		toWallet, problem, err := server.resolvePayoutRow(ctx, fromWallet, row, recipients)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if problem != "" {
+			rowErrors = append(rowErrors, payoutRowError{Row: row.Row, Error: problem})
+			continue
+		}
+
+		total += row.Amount
+		rows = append(rows, db.CreatePayoutRowParams{
+			RecipientKey: row.RecipientKey,
+			ToWalletID:   toWallet.ID,
+			Amount:       row.Amount,
+			Currency:     row.Currency,
+			Reference:    row.Reference,
+		})
+	}
+
+	if len(rowErrors) > 0 {
+		sort.Slice(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "payout batch has invalid rows",
+			"rows":  rowErrors,
+		})
+		return
+	}
+
+	if total > fromWallet.AvailableBalance() {
+		err := fmt.Errorf("batch total %d exceeds the wallet available balance %d", total, fromWallet.AvailableBalance())
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreatePayoutBatchTx(ctx, db.CreatePayoutBatchTxParams{
+		Owner:        payload.Username,
+		FromWalletID: fromWallet.ID,
+		Currency:     fromWallet.Currency,
+		Description:  req.Description,
+		Rows:         rows,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Batch)
+}
+
+// resolvePayoutRow finds the wallet the row pays into. Problems with the
+// row are returned as a message, errors are failures to look it up
+func (server *Server) resolvePayoutRow(ctx *gin.Context, fromWallet db.Wallet, row payoutRowRequest, recipients map[string]db.Wallet) (db.Wallet, string, error) {
+	switch {
+	case row.RecipientKey == "":
+		return db.Wallet{}, "recipient_key is required", nil
+	case row.Amount <= 0:
+		return db.Wallet{}, "amount must be positive", nil
+	case row.Currency != fromWallet.Currency:
+		return db.Wallet{}, fmt.Sprintf("currency must be %s, the wallet currency", fromWallet.Currency), nil
+	case len(row.Reference) > maxPayoutReferenceLen:
+		return db.Wallet{}, fmt.Sprintf("reference longer than %d characters", maxPayoutReferenceLen), nil
+	}
+
+	toWallet, ok := recipients[row.RecipientKey]
+	if !ok {
+		user, err := server.store.GetUserByPixKey(ctx, row.RecipientKey)
+		if err == sql.ErrNoRows {
+			return toWallet, "recipient key not found", nil
+		}
+		if err != nil {
+			return toWallet, "", err
+		}
+
+		toWallet, err = server.store.GetWalletByOwnerAndCurrency(ctx, db.GetWalletByOwnerAndCurrencyParams{
+			Owner:    user.Username,
+			Currency: fromWallet.Currency,
+		})
+		if err == sql.ErrNoRows {
+			return toWallet, fmt.Sprintf("recipient has no %s wallet", fromWallet.Currency), nil
+		}
+		if err != nil {
+			return toWallet, "", err
+		}
+
+		recipients[row.RecipientKey] = toWallet
+	}
+
+	if toWallet.ID == fromWallet.ID {
+		return toWallet, "recipient is the paying wallet", nil
+	}
+	if toWallet.IsFrozen {
+		return toWallet, "recipient wallet is frozen", nil
+	}
+
+	return toWallet, "", nil
+}
+
+// parsePayoutCSV reads the rows of an uploaded batch. Rows that can't be
+// parsed are returned as row errors, a file without the required columns
+// is an error
+func parsePayoutCSV(r io.Reader) ([]payoutRowRequest, []payoutRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read the CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredPayoutCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %s column", name)
+		}
+	}
+
+	var rows []payoutRowRequest
+	var rowErrors []payoutRowError
+
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		amount, err := strconv.ParseInt(field("amount"), 10, 64)
+		if err != nil {
+			rowErrors = append(rowErrors, payoutRowError{Row: number, Error: "amount must be a whole number of cents"})
+			continue
+		}
+
+		rows = append(rows, payoutRowRequest{
+			Row:          number,
+			RecipientKey: field("recipient_key"),
+			Amount:       amount,
+			Currency:     strings.ToUpper(field("currency")),
+			Reference:    field("reference"),
+		})
+	}
+
+	return rows, rowErrors, nil
+}
+
+type payoutBatchURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listPayoutBatchesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listPayoutBatches(ctx *gin.Context) {
+	var req listPayoutBatchesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	batches, err := server.store.ListPayoutBatches(ctx, db.ListPayoutBatchesParams{
+		Owner:  payload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, batches)
+}
+
+func (server *Server) getPayoutBatch(ctx *gin.Context) {
+	batch, ok := server.authorizedPayoutBatch(ctx, permissionReadAny)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, batch)
+}
+
+func (server *Server) listPayoutRows(ctx *gin.Context) {
+	var req listPayoutBatchesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	batch, ok := server.authorizedPayoutBatch(ctx, permissionReadAny)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListPayoutRows(ctx, db.ListPayoutRowsParams{
+		BatchID: batch.ID,
+		Limit:   req.PageSize,
+		Offset:  (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rows)
+}
+
+// getPayoutReport downloads the outcome of every row of the batch as CSV
+func (server *Server) getPayoutReport(ctx *gin.Context) {
+	batch, ok := server.authorizedPayoutBatch(ctx, permissionReadAny)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListAllPayoutRows(ctx, batch.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"row", "recipient_key", "amount", "currency", "reference", "status", "transfer_id", "error", "processed_at"})
+	for _, row := range rows {
+		var transferID, processedAt string
+		if row.TransferID.Valid {
+			transferID = strconv.FormatInt(row.TransferID.Int64, 10)
+		}
+		if row.ProcessedAt.Valid {
+			processedAt = row.ProcessedAt.Time.Format(time.RFC3339)
+		}
+
+		writer.Write([]string{
+			strconv.Itoa(int(row.RowNumber)),
+			row.RecipientKey,
+			strconv.FormatInt(row.Amount, 10),
+			row.Currency,
+			row.Reference,
+			row.Status,
+			transferID,
+			row.Error,
+			processedAt,
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="payout-batch-%d.csv"`, batch.ID))
+	ctx.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+func (server *Server) pausePayoutBatch(ctx *gin.Context) {
+	server.updatePayoutBatch(ctx, db.PayoutBatchPaused)
+}
+
+func (server *Server) resumePayoutBatch(ctx *gin.Context) {
+	server.updatePayoutBatch(ctx, db.PayoutBatchProcessing)
+}
+
+func (server *Server) cancelPayoutBatch(ctx *gin.Context) {
+	server.updatePayoutBatch(ctx, db.PayoutBatchCancelled)
+}
+
+func (server *Server) updatePayoutBatch(ctx *gin.Context, status string) {
+	batch, ok := server.authorizedPayoutBatch(ctx, permissionWriteAny)
+	if !ok {
+		return
+	}
+
+	batch, err := server.store.UpdatePayoutBatchTx(ctx, db.UpdatePayoutBatchTxParams{
+		ID:     batch.ID,
+		Status: status,
+		Now:    time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrPayoutBatchFinished) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, batch)
+}
+
+// authorizedPayoutBatch loads the batch in the URI when it belongs to the
+// authenticated user
+func (server *Server) authorizedPayoutBatch(ctx *gin.Context, anyPermission string) (db.PayoutBatch, bool) {
+	var uri payoutBatchURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.PayoutBatch{}, false
+	}
+
+	batch, err := server.store.GetPayoutBatch(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return batch, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return batch, false
+	}
+
+	return batch, authorizeOwner(ctx, batch.Owner, anyPermission)
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePayoutBatchAPI(t *testing.T) {
+	from := randomWallet()
+	from.Currency = util.BRL
+	from.Balance = 10000
+
+	supplier := db.User{Username: util.RandomString(8), CpfCnpj: util.RandomCpfCnpj()}
+	supplierWallet := db.Wallet{ID: from.ID + 100, Owner: supplier.Username, Currency: util.BRL}
+
+	employee := db.User{Username: util.RandomString(8), Email: "employee@example.com"}
+	employeeWallet := db.Wallet{ID: from.ID + 200, Owner: employee.Username, Currency: util.BRL}
+
+	resolveRecipients := func(store *mockdb.MockStore) {
+		store.EXPECT().GetUserByPixKey(gomock.Any(), supplier.CpfCnpj).Times(1).Return(supplier, nil)
+		store.EXPECT().
+			GetWalletByOwnerAndCurrency(gomock.Any(), db.GetWalletByOwnerAndCurrencyParams{Owner: supplier.Username, Currency: util.BRL}).
+			Times(1).
+			Return(supplierWallet, nil)
+		store.EXPECT().GetUserByPixKey(gomock.Any(), employee.Email).Times(1).Return(employee, nil)
+		store.EXPECT().
+			GetWalletByOwnerAndCurrency(gomock.Any(), db.GetWalletByOwnerAndCurrencyParams{Owner: employee.Username, Currency: util.BRL}).
+			Times(1).
+			Return(employeeWallet, nil)
+	}
+
+	jsonBody := func(rows ...gin.H) func(t *testing.T) (string, *bytes.Buffer) {
+		return func(t *testing.T) (string, *bytes.Buffer) {
+			data, err := json.Marshal(gin.H{"from_wallet_id": from.ID, "description": "payroll", "rows": rows})
+			require.NoError(t, err)
+			return "application/json", bytes.NewBuffer(data)
+		}
+	}
+
+	csvBody := func(content string) func(t *testing.T) (string, *bytes.Buffer) {
+		return func(t *testing.T) (string, *bytes.Buffer) {
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			require.NoError(t, writer.WriteField("from_wallet_id", fmt.Sprint(from.ID)))
+
+			file, err := writer.CreateFormFile("file", "payouts.csv")
+			require.NoError(t, err)
+			_, err = file.Write([]byte(content))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			return writer.FormDataContentType(), body
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		body          func(t *testing.T) (string, *bytes.Buffer)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "JSON",
+			body: jsonBody(
+				gin.H{"recipient_key": supplier.CpfCnpj, "amount": 3000, "currency": util.BRL, "reference": "invoice 1"},
+				gin.H{"recipient_key": employee.Email, "amount": 2000, "currency": util.BRL},
+				gin.H{"recipient_key": supplier.CpfCnpj, "amount": 1000, "currency": util.BRL, "reference": "invoice 2"},
+			),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				resolveRecipients(store)
+				store.EXPECT().
+					CreatePayoutBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePayoutBatchTxParams) (db.CreatePayoutBatchTxResult, error) {
+						require.Equal(t, from.Owner, arg.Owner)
+						require.Equal(t, from.ID, arg.FromWalletID)
+						require.Equal(t, util.BRL, arg.Currency)
+						require.Equal(t, "payroll", arg.Description)
+						require.Len(t, arg.Rows, 3)
+						require.Equal(t, supplierWallet.ID, arg.Rows[0].ToWalletID)
+						require.Equal(t, employeeWallet.ID, arg.Rows[1].ToWalletID)
+						require.Equal(t, "invoice 2", arg.Rows[2].Reference)
+						return db.CreatePayoutBatchTxResult{Batch: db.PayoutBatch{ID: 1, RowCount: 3}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CSV",
+			body: csvBody(fmt.Sprintf("amount,recipient_key,currency,reference\n3000,%s,brl,invoice 1\n2000,%s,BRL,\n", supplier.CpfCnpj, employee.Email)),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				resolveRecipients(store)
+				store.EXPECT().
+					CreatePayoutBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePayoutBatchTxParams) (db.CreatePayoutBatchTxResult, error) {
+						require.Len(t, arg.Rows, 2)
+						require.Equal(t, int64(3000), arg.Rows[0].Amount)
+						require.Equal(t, util.BRL, arg.Rows[0].Currency)
+						require.Equal(t, "invoice 1", arg.Rows[0].Reference)
+						return db.CreatePayoutBatchTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRows",
+			body: csvBody(fmt.Sprintf("recipient_key,amount,currency\nunknown,100,BRL\n%s,1.50,BRL\n%s,100,USD\n", supplier.CpfCnpj, supplier.CpfCnpj)),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().GetUserByPixKey(gomock.Any(), "unknown").Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePayoutBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var response struct {
+					Rows []payoutRowError `json:"rows"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Rows, 3)
+				for i, rowError := range response.Rows {
+					require.Equal(t, i+1, rowError.Row)
+				}
+			},
+		},
+		{
+			name: "MissingColumn",
+			body: csvBody("recipient_key,currency\nabc,BRL\n"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ExceedsBalance",
+			body: jsonBody(
+				gin.H{"recipient_key": supplier.CpfCnpj, "amount": 8000, "currency": util.BRL},
+				gin.H{"recipient_key": employee.Email, "amount": 2001, "currency": util.BRL},
+			),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				resolveRecipients(store)
+				store.EXPECT().CreatePayoutBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoRows",
+			body: jsonBody(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			contentType, body := tc.body(t)
+			request, err := http.NewRequest(http.MethodPost, "/payout-batches", body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			authAs(from.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdatePayoutBatchAPI(t *testing.T) {
+	batch := db.PayoutBatch{ID: util.RandomInt(1, 1000), Owner: util.RandomString(8), Status: db.PayoutBatchProcessing}
+
+	testCases := []struct {
+		name          string
+		action        string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Pause",
+			action:   "pause",
+			username: batch.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPayoutBatch(gomock.Any(), batch.ID).Times(1).Return(batch, nil)
+				store.EXPECT().
+					UpdatePayoutBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdatePayoutBatchTxParams) (db.PayoutBatch, error) {
+						require.Equal(t, batch.ID, arg.ID)
+						require.Equal(t, db.PayoutBatchPaused, arg.Status)
+						return batch, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Resume",
+			action:   "resume",
+			username: batch.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPayoutBatch(gomock.Any(), batch.ID).Times(1).Return(batch, nil)
+				store.EXPECT().
+					UpdatePayoutBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdatePayoutBatchTxParams) (db.PayoutBatch, error) {
+						require.Equal(t, db.PayoutBatchProcessing, arg.Status)
+						return batch, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "CancelFinished",
+			action:   "cancel",
+			username: batch.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPayoutBatch(gomock.Any(), batch.ID).Times(1).Return(batch, nil)
+				store.EXPECT().UpdatePayoutBatchTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PayoutBatch{}, db.ErrPayoutBatchFinished)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			action:   "cancel",
+			username: util.RandomString(7),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPayoutBatch(gomock.Any(), batch.ID).Times(1).Return(batch, nil)
+				store.EXPECT().UpdatePayoutBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payout-batches/%d/%s", batch.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			authAs(tc.username, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPayoutReportAPI(t *testing.T) {
+	batch := db.PayoutBatch{ID: util.RandomInt(1, 1000), Owner: util.RandomString(8)}
+	rows := []db.PayoutRow{
+		{RowNumber: 1, RecipientKey: "a@example.com", Amount: 100, Currency: util.BRL, Status: db.PayoutRowSucceeded, TransferID: sql.NullInt64{Int64: 7, Valid: true}},
+		{RowNumber: 2, RecipientKey: "b@example.com", Amount: 200, Currency: util.BRL, Reference: "bonus, march", Status: db.PayoutRowFailed, Error: "wallet is frozen"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPayoutBatch(gomock.Any(), batch.ID).Times(1).Return(batch, nil)
+	store.EXPECT().ListAllPayoutRows(gomock.Any(), batch.ID).Times(1).Return(rows, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/payout-batches/%d/report", batch.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	authAs(batch.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "1,a@example.com,100,BRL,,succeeded,7,,", lines[1])
+	require.Equal(t, `2,b@example.com,200,BRL,"bonus, march",failed,,wallet is frozen,`, lines[2])
+}
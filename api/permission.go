@@ -0,0 +1,165 @@
+package api
+
+import (
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+)
+
+// Permissions are named after the resource and the action performed on it
+const (
+	permissionUsersRead         = "users:read"
+	permissionUsersWrite        = "users:write"
+	permissionUsersList         = "users:list"
+	permissionUsersRole         = "users:role"
+	permissionWalletsRead       = "wallets:read"
+	permissionWalletsWrite      = "wallets:write"
+	permissionWalletsList       = "wallets:list"
+	permissionWalletsAdjust     = "wallets:adjust"
+	permissionWalletsFreeze     = "wallets:freeze"
+	permissionEntriesRead       = "entries:read"
+	permissionTransfersRead     = "transfers:read"
+	permissionTransfersWrite    = "transfers:write"
+	permissionRefundsWrite      = "refunds:write"
+	permissionAPIKeysWrite      = "api_keys:write"
+	permissionWebhooksWrite     = "webhooks:write"
+	permissionLimitsRequest     = "limits:request"
+	permissionLimitsWrite       = "limits:write"
+	permissionReviewsRead       = "reviews:read"
+	permissionReviewsWrite      = "reviews:write"
+	permissionNotificationsRead = "notifications:read"
+	permissionFeesRead          = "fees:read"
+	permissionFeesWrite         = "fees:write"
+	permissionLedgerRead        = "ledger:read"
+	permissionHoldsWrite        = "holds:write"
+	permissionEscrowsResolve    = "escrows:resolve"
+	permissionDisputesRead      = "disputes:read"
+	permissionDisputesWrite     = "disputes:write"
+	permissionSettlementsWrite  = "settlements:write"
+	permissionReservesWrite     = "reserves:write"
+	permissionCampaignsWrite    = "campaigns:write"
+	permissionInstallmentsFund  = "installments:fund"
+
+	// permissionReadAny and permissionWriteAny allow acting on data owned by other users
+	permissionReadAny  = "any:read"
+	permissionWriteAny = "any:write"
+)
+
+var customerPermissions = []string{
+	permissionUsersRead,
+	permissionUsersWrite,
+	permissionWalletsRead,
+	permissionWalletsWrite,
+	permissionEntriesRead,
+	permissionTransfersRead,
+	permissionTransfersWrite,
+	permissionLimitsRequest,
+	permissionNotificationsRead,
+}
+
+var merchantPermissions = append([]string{
+	permissionAPIKeysWrite,
+	permissionWebhooksWrite,
+	permissionHoldsWrite,
+	permissionInstallmentsFund,
+}, customerPermissions...)
+
+var supportPermissions = []string{
+	permissionUsersRead,
+	permissionUsersList,
+	permissionWalletsRead,
+	permissionWalletsList,
+	permissionEntriesRead,
+	permissionTransfersRead,
+	permissionReviewsRead,
+	permissionReviewsWrite,
+	permissionFeesRead,
+	permissionLedgerRead,
+	permissionDisputesRead,
+	permissionDisputesWrite,
+	permissionReadAny,
+}
+
+var adminPermissions = []string{
+	permissionUsersRead,
+	permissionUsersWrite,
+	permissionUsersList,
+	permissionUsersRole,
+	permissionWalletsRead,
+	permissionWalletsWrite,
+	permissionWalletsList,
+	permissionWalletsAdjust,
+	permissionWalletsFreeze,
+	permissionEntriesRead,
+	permissionTransfersRead,
+	permissionTransfersWrite,
+	permissionRefundsWrite,
+	permissionAPIKeysWrite,
+	permissionWebhooksWrite,
+	permissionLimitsRequest,
+	permissionLimitsWrite,
+	permissionReviewsRead,
+	permissionReviewsWrite,
+	permissionNotificationsRead,
+	permissionFeesRead,
+	permissionFeesWrite,
+	permissionLedgerRead,
+	permissionHoldsWrite,
+	permissionEscrowsResolve,
+	permissionDisputesRead,
+	permissionDisputesWrite,
+	permissionSettlementsWrite,
+	permissionReservesWrite,
+	permissionCampaignsWrite,
+	permissionInstallmentsFund,
+	permissionReadAny,
+	permissionWriteAny,
+}
+
+var rolePermissions = map[string][]string{
+	util.CustomerRole: customerPermissions,
+	util.MerchantRole: merchantPermissions,
+	util.SupportRole:  supportPermissions,
+	util.AdminRole:    adminPermissions,
+}
+
+// hasPermission reports whether the role was granted the permission
+func hasPermission(role string, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorized reports whether the payload role has the permission and, for
+// API keys, whether the key was scoped to it
+func authorized(payload *token.Payload, permission string) bool {
+	if !hasPermission(payload.Role, permission) {
+		return false
+	}
+
+	if payload.APIKeyID == 0 {
+		return true
+	}
+
+	for _, scope := range payload.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isGrantableScope reports whether an API key owned by the role may be scoped
+// to the permission. Keys never manage other keys nor reach other users' data
+func isGrantableScope(role string, scope string) bool {
+	switch scope {
+	case permissionAPIKeysWrite, permissionReadAny, permissionWriteAny:
+		return false
+	}
+
+	return hasPermission(role, scope)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"picpay_simplificado/ratelimit"
+	"picpay_simplificado/token"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+
+	rateLimitBackendMemory   = "memory"
+	rateLimitBackendPostgres = "postgres"
+)
+
+var errRateLimited = errors.New("too many requests")
+
+// rateLimits holds the limit of each route group
+type rateLimits struct {
+	Default   ratelimit.Limit
+	Login     ratelimit.Limit
+	Transfers ratelimit.Limit
+	// Auth is counted per client IP before the credentials are checked
+	Auth ratelimit.Limit
+}
+
+func (server *Server) setupRateLimits() error {
+	switch server.config.RateLimitBackend {
+	case "", rateLimitBackendMemory:
+		server.limiter = ratelimit.NewMemoryLimiter()
+	case rateLimitBackendPostgres:
+		server.limiter = ratelimit.NewPostgresLimiter(server.store)
+	default:
+		return fmt.Errorf("unsupported rate limit backend %q", server.config.RateLimitBackend)
+	}
+
+	for _, group := range []struct {
+		value string
+		limit *ratelimit.Limit
+	}{
+		{server.config.RateLimitDefault, &server.rateLimits.Default},
+		{server.config.RateLimitLogin, &server.rateLimits.Login},
+		{server.config.RateLimitTransfers, &server.rateLimits.Transfers},
+		{server.config.RateLimitAuth, &server.rateLimits.Auth},
+	} {
+		if group.value == "" {
+			continue
+		}
+
+		limit, err := ratelimit.ParseLimit(group.value)
+		if err != nil {
+			return err
+		}
+		*group.limit = limit
+	}
+
+	return nil
+}
+
+// rateLimitMiddleware limits the requests of a route group. Authenticated
+// requests are counted per API key or per user, the others per client IP.
+// A zero limit turns limiting off
+func rateLimitMiddleware(limiter ratelimit.Limiter, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if limit.IsZero() {
+			ctx.Next()
+			return
+		}
+
+		result, err := limiter.Allow(ctx, group+":"+rateLimitKey(ctx), limit)
+		if err != nil {
+			// a broken backend must not take the whole API down
+			log.Println("cannot check rate limit:", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header(rateLimitLimitHeader, strconv.Itoa(result.Limit))
+		ctx.Header(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		ctx.Header(rateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			ctx.Header(retryAfterHeader, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(errRateLimited))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func rateLimitKey(ctx *gin.Context) string {
+	value, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		return "ip:" + ctx.ClientIP()
+	}
+
+	payload := value.(*token.Payload)
+	if payload.APIKeyID != 0 {
+		return "key:" + strconv.FormatInt(payload.APIKeyID, 10)
+	}
+
+	return "user:" + payload.Username
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
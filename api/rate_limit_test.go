@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/risk"
+	"picpay_simplificado/stream"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedTestServer(t *testing.T, store db.Store, config util.Config) *Server {
+	config.TokenSymmetricKey = util.RandomString(32)
+	config.APIKeySigningKey = util.RandomString(32)
+	config.AccessTokenDuration = time.Minute
+	config.BlobLocalDir = t.TempDir()
+
+	server, err := NewServer(config, store, stream.NewBroker(), risk.NewEngine())
+	require.NoError(t, err)
+
+	return server
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(db.User{}, sql.ErrNoRows)
+
+	server := newRateLimitedTestServer(t, store, util.Config{RateLimitLogin: "2/1m"})
+
+	login := func(remoteAddr string) *httptest.ResponseRecorder {
+		data, err := json.Marshal(gin.H{"username": util.RandomString(6), "password": "secret"})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+		require.NoError(t, err)
+		request.RemoteAddr = remoteAddr
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := login("10.0.0.1:1234")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get(rateLimitLimitHeader))
+	require.Equal(t, "1", recorder.Header().Get(rateLimitRemainingHeader))
+
+	recorder = login("10.0.0.1:1234")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get(rateLimitRemainingHeader))
+	require.Equal(t, "60", recorder.Header().Get(rateLimitResetHeader))
+
+	recorder = login("10.0.0.1:1234")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "30", recorder.Header().Get(retryAfterHeader))
+
+	// other clients have their own bucket
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.User{}, sql.ErrNoRows)
+
+	recorder = login("10.0.0.2:1234")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRateLimitIgnoresUntrustedForwardedFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(db.User{}, sql.ErrNoRows)
+
+	server := newRateLimitedTestServer(t, store, util.Config{
+		RateLimitLogin: "1/1m",
+		TrustedProxies: []string{"10.0.0.100"},
+	})
+
+	login := func(remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		data, err := json.Marshal(gin.H{"username": util.RandomString(6), "password": "secret"})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+		require.NoError(t, err)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// a client cannot dodge its bucket by spoofing the header
+	require.Equal(t, http.StatusUnauthorized, login("10.0.0.1:1234", "1.1.1.1").Code)
+	require.Equal(t, http.StatusTooManyRequests, login("10.0.0.1:1234", "2.2.2.2").Code)
+
+	// the header is honored when it comes from a trusted proxy
+	require.Equal(t, http.StatusUnauthorized, login("10.0.0.100:1234", "3.3.3.3").Code)
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	wallet := randomWallet()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetWallet(gomock.Any(), gomock.Eq(wallet.ID)).
+		Times(2).
+		Return(wallet, nil)
+
+	server := newRateLimitedTestServer(t, store, util.Config{RateLimitDefault: "1/1m"})
+
+	getWallet := func(username string) *httptest.ResponseRecorder {
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/wallets/%d", wallet.ID), nil)
+		require.NoError(t, err)
+		authAs(username, util.SupportRole)(t, request, server.tokenMaker)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusOK, getWallet("alice").Code)
+	require.Equal(t, http.StatusTooManyRequests, getWallet("alice").Code)
+	require.Equal(t, http.StatusOK, getWallet("bob").Code)
+}
+
+func TestRateLimitBadCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newRateLimitedTestServer(t, mockdb.NewMockStore(ctrl), util.Config{RateLimitAuth: "2/1m"})
+
+	getWallet := func(remoteAddr string) *httptest.ResponseRecorder {
+		request, err := http.NewRequest(http.MethodGet, "/wallets/1", nil)
+		require.NoError(t, err)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set(authorizationHeaderKey, "Bearer invalid-token")
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusUnauthorized, getWallet("10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusUnauthorized, getWallet("10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusTooManyRequests, getWallet("10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusUnauthorized, getWallet("10.0.0.2:1234").Code)
+}
+
+func TestRateLimitBackendError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.RateLimitBucket{}, errors.New("connection refused"))
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.User{}, sql.ErrNoRows)
+
+	server := newRateLimitedTestServer(t, store, util.Config{
+		RateLimitBackend: rateLimitBackendPostgres,
+		RateLimitLogin:   "1/1m",
+	})
+
+	data, err := json.Marshal(gin.H{"username": util.RandomString(6), "password": "secret"})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestInvalidRateLimitConfig(t *testing.T) {
+	_, err := NewServer(util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		APIKeySigningKey:  util.RandomString(32),
+		RateLimitLogin:    "five per minute",
+	}, mockdb.NewMockStore(gomock.NewController(t)), stream.NewBroker(), risk.NewEngine())
+	require.Error(t, err)
+
+	_, err = NewServer(util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		APIKeySigningKey:  util.RandomString(32),
+		RateLimitBackend:  "redis",
+	}, mockdb.NewMockStore(gomock.NewController(t)), stream.NewBroker(), risk.NewEngine())
+	require.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type settlementPlanURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+// getSettlementPlan returns the plan of the user, users on no plan settle
+// right away
+func (server *Server) getSettlementPlan(ctx *gin.Context) {
+	var uri settlementPlanURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionReadAny) {
+		return
+	}
+
+	plan, err := server.store.GetSettlementPlan(ctx, uri.Username)
+
+	if err == sql.ErrNoRows {
+		plan = db.SettlementPlan{Owner: uri.Username}
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
+
+type updateSettlementPlanRequest struct {
+	SettleDays int32 `json:"settle_days" binding:"oneof=0 1 30"`
+	// AnticipationRate is in basis points per 30 days
+	AnticipationRate int64 `json:"anticipation_rate" binding:"min=0,max=10000"`
+}
+
+// updateSettlementPlan puts a merchant on a D+0, D+1 or D+30 plan. Only
+// transfers received from then on follow the new plan
+func (server *Server) updateSettlementPlan(ctx *gin.Context) {
+	var uri settlementPlanURI
+	var req updateSettlementPlanRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	plan, err := server.store.UpsertSettlementPlan(ctx, db.UpsertSettlementPlanParams{
+		Owner:            uri.Username,
+		SettleDays:       req.SettleDays,
+		AnticipationRate: req.AnticipationRate,
+		UpdatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
+
+type listReceivablesRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+}
+
+// listReceivables returns the pending receivables of the wallet added up by
+// the date they settle on
+func (server *Server) listReceivables(ctx *gin.Context) {
+	var req listReceivablesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	schedule, err := server.store.ListReceivableSchedule(ctx, req.WalletID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+type anticipationRequest struct {
+	WalletID int64 `json:"wallet_id" form:"wallet_id" binding:"required,min=1"`
+	// Until is the last settlement date anticipated
+	Until string `json:"until" form:"until" binding:"required,datetime=2006-01-02"`
+}
+
+// params returns the store params anticipating the request at now.
+// Settlement dates start at midnight UTC, so the date is parsed in UTC
+func (req anticipationRequest) params(now time.Time) db.AnticipationParams {
+	until, _ := time.Parse("2006-01-02", req.Until)
+
+	return db.AnticipationParams{
+		WalletID: req.WalletID,
+		Until:    until,
+		Now:      now,
+	}
+}
+
+// quoteAnticipation returns what anticipating the receivables settling up to
+// a date would make available and cost
+func (server *Server) quoteAnticipation(ctx *gin.Context) {
+	var req anticipationRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	quote, err := server.store.QuoteAnticipation(ctx, req.params(time.Now()))
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// anticipateReceivables settles the receivables up to a date right away, for
+// the anticipation fee of the plan of the wallet owner
+func (server *Server) anticipateReceivables(ctx *gin.Context) {
+	var req anticipationRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionWriteAny) {
+		return
+	}
+
+	result, err := server.store.AnticipateReceivablesTx(ctx, db.AnticipateReceivablesTxParams{
+		AnticipationParams: req.params(time.Now()),
+		RevenueOwner:       server.config.PlatformRevenueOwner,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrNoReceivables) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
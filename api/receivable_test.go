@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSettlementPlanAPI(t *testing.T) {
+	merchant := util.RandomString(7)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"settle_days": 30, "anticipation_rate": 250},
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertSettlementPlan(gomock.Any(), gomock.Eq(db.UpsertSettlementPlanParams{
+						Owner:            merchant,
+						SettleDays:       30,
+						AnticipationRate: 250,
+						UpdatedBy:        "admin",
+					})).
+					Times(1).
+					Return(db.SettlementPlan{Owner: merchant, SettleDays: 30, AnticipationRate: 250}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "UnsupportedDays",
+			body:      gin.H{"settle_days": 2},
+			setupAuth: authAs("admin", util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertSettlementPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantUpdates",
+			body:      gin.H{"settle_days": 0},
+			setupAuth: authAs(merchant, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertSettlementPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/users/%s/settlement-plan", merchant)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAnticipateReceivablesAPI(t *testing.T) {
+	wallet := randomWallet()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"wallet_id": wallet.ID, "until": "2024-06-30"},
+			setupAuth: authAs(wallet.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().
+					AnticipateReceivablesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AnticipateReceivablesTxParams) (db.AnticipateReceivablesTxResult, error) {
+						require.Equal(t, wallet.ID, arg.WalletID)
+						require.Equal(t, time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), arg.Until)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Minute)
+						require.Equal(t, "platform", arg.RevenueOwner)
+						return db.AnticipateReceivablesTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NoReceivables",
+			body:      gin.H{"wallet_id": wallet.ID, "until": "2024-06-30"},
+			setupAuth: authAs(wallet.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().
+					AnticipateReceivablesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AnticipateReceivablesTxResult{}, db.ErrNoReceivables)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidDate",
+			body:      gin.H{"wallet_id": wallet.ID, "until": "30/06/2024"},
+			setupAuth: authAs(wallet.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AnticipateReceivablesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotOwner",
+			body:      gin.H{"wallet_id": wallet.ID, "until": "2024-06-30"},
+			setupAuth: authAs(util.RandomString(7), util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), wallet.ID).Times(1).Return(wallet, nil)
+				store.EXPECT().AnticipateReceivablesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/receivables/anticipate", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
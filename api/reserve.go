@@ -0,0 +1,137 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type reserveRuleURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+func (server *Server) getReserveRule(ctx *gin.Context) {
+	var uri reserveRuleURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, uri.Username, permissionReadAny) {
+		return
+	}
+
+	rule, err := server.store.GetReserveRule(ctx, uri.Username)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+type updateReserveRuleRequest struct {
+	// Rate is in basis points of each payment received
+	Rate     int64 `json:"rate" binding:"required,min=1,max=10000"`
+	HoldDays int32 `json:"hold_days" binding:"required,min=1,max=365"`
+}
+
+// updateReserveRule withholds part of what a merchant receives from now on
+func (server *Server) updateReserveRule(ctx *gin.Context) {
+	var uri reserveRuleURI
+	var req updateReserveRuleRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	rule, err := server.store.UpsertReserveRule(ctx, db.UpsertReserveRuleParams{
+		Owner:     uri.Username,
+		Rate:      req.Rate,
+		HoldDays:  req.HoldDays,
+		UpdatedBy: payload.Username,
+	})
+
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// deleteReserveRule stops withholding, the reserves already held are still
+// released on their own dates
+func (server *Server) deleteReserveRule(ctx *gin.Context) {
+	var uri reserveRuleURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteReserveRule(ctx, uri.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"owner": uri.Username})
+}
+
+type reserveStatementRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// getReserveStatement returns how much of the wallet is withheld, was
+// released or covered chargebacks, with a page of its reserves
+func (server *Server) getReserveStatement(ctx *gin.Context) {
+	var req reserveStatementRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	statement, err := server.store.ReserveStatement(ctx, db.ReserveStatementParams{
+		WalletID: req.WalletID,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statement)
+}
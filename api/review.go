@@ -0,0 +1,165 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type listTransferReviewsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listTransferReviews(ctx *gin.Context) {
+	var req listTransferReviewsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Status == "" {
+		req.Status = db.ReviewPending
+	}
+
+	arg := db.ListTransferReviewsParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	reviews, err := server.store.ListTransferReviews(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reviews)
+}
+
+type transferReviewURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.GetTransferReview(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, review)
+}
+
+type decideTransferReviewRequest struct {
+	Note string `json:"note"`
+}
+
+func (server *Server) approveTransferReview(ctx *gin.Context) {
+	server.decideTransferReview(ctx, true)
+}
+
+func (server *Server) rejectTransferReview(ctx *gin.Context) {
+	server.decideTransferReview(ctx, false)
+}
+
+// decideTransferReview releases the amount held for review, to the
+// recipient when approved or back to the sender when rejected
+func (server *Server) decideTransferReview(ctx *gin.Context, approve bool) {
+	var uri transferReviewURI
+	var req decideTransferReviewRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	now := time.Now()
+
+	result, err := server.store.DecideReviewTx(ctx, db.DecideReviewTxParams{
+		ReviewID:   uri.ID,
+		Approve:    approve,
+		ReviewedBy: payload.Username,
+		Note:       req.Note,
+		Fees:       server.fees(now),
+		Cashback:   server.cashback(now),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrReviewNotPending) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listRiskDecisionsRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listRiskDecisions(ctx *gin.Context) {
+	var req listRiskDecisionsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListRiskDecisionsParams{
+		FromWalletID: req.WalletID,
+		Limit:        req.PageSize,
+		Offset:       (req.PageID - 1) * req.PageSize,
+	}
+
+	decisions, err := server.store.ListRiskDecisions(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, decisions)
+}
+
+func (server *Server) getRiskRules(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.riskEngine.Rules())
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideTransferReviewAPI(t *testing.T) {
+	review := randomTransferReview()
+	analyst := util.RandomString(6)
+
+	testCases := []struct {
+		name          string
+		reviewID      int64
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Approve",
+			reviewID:  review.ID,
+			action:    "approve",
+			setupAuth: authAs(analyst, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DecideReviewTxParams) (db.DecideReviewTxResult, error) {
+						require.Equal(t, review.ID, arg.ReviewID)
+						require.True(t, arg.Approve)
+						require.Equal(t, analyst, arg.ReviewedBy)
+						require.Equal(t, "looks fine", arg.Note)
+						require.NotNil(t, arg.Fees)
+						require.NotNil(t, arg.Cashback)
+						return db.DecideReviewTxResult{Review: review}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Reject",
+			reviewID:  review.ID,
+			action:    "reject",
+			setupAuth: authAs(analyst, util.AdminRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DecideReviewTxParams) (db.DecideReviewTxResult, error) {
+						require.Equal(t, review.ID, arg.ReviewID)
+						require.False(t, arg.Approve)
+						require.Equal(t, analyst, arg.ReviewedBy)
+						return db.DecideReviewTxResult{Review: review}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerForbidden",
+			reviewID:  review.ID,
+			action:    "approve",
+			setupAuth: authAs(analyst, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NotPending",
+			reviewID:  review.ID,
+			action:    "approve",
+			setupAuth: authAs(analyst, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideReviewTxResult{}, db.ErrReviewNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			reviewID:  review.ID,
+			action:    "reject",
+			setupAuth: authAs(analyst, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideReviewTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			reviewID:  0,
+			action:    "approve",
+			setupAuth: authAs(analyst, util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideReviewTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"note": "looks fine"})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/reviews/%d/%s", tc.reviewID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTransferReviewsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reviews := []db.TransferReview{randomTransferReview(), randomTransferReview()}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListTransferReviews(gomock.Any(), gomock.Eq(db.ListTransferReviewsParams{
+			Status: db.ReviewPending,
+			Limit:  5,
+			Offset: 5,
+		})).
+		Times(1).
+		Return(reviews, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/reviews?page_id=2&page_size=5", nil)
+	require.NoError(t, err)
+
+	authAs(util.RandomString(6), util.SupportRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []db.TransferReview
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Len(t, got, 2)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/reviews?status=held&page_id=1&page_size=5", nil)
+	require.NoError(t, err)
+
+	authAs(util.RandomString(6), util.SupportRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCreateTransferRisk(t *testing.T) {
+	from := randomWallet()
+	to := randomWallet()
+	to.ID = from.ID + 100
+	to.Currency = from.Currency
+
+	testCases := []struct {
+		name   string
+		result db.TrasferTxResult
+		err    error
+		code   int
+	}{
+		{
+			name:   "Allowed",
+			result: db.TrasferTxResult{RiskDecision: &db.RiskDecision{Outcome: db.RiskAllow}},
+			code:   http.StatusOK,
+		},
+		{
+			name: "Review",
+			result: db.TrasferTxResult{
+				RiskDecision: &db.RiskDecision{Outcome: db.RiskReview},
+				Review:       &db.TransferReview{ID: 1, Status: db.ReviewPending},
+			},
+			code: http.StatusAccepted,
+		},
+		{
+			name: "Blocked",
+			err:  db.ErrTransferBlocked,
+			code: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+			store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+
+			server := newTestServer(t, store)
+
+			store.EXPECT().
+				TransferTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.TrasferTxParms) (db.TrasferTxResult, error) {
+					require.Equal(t, server.riskEngine, arg.Risk)
+					return tc.result, tc.err
+				})
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			authAs(from.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+		})
+	}
+}
+
+func randomTransferReview() db.TransferReview {
+	return db.TransferReview{
+		ID:           util.RandomInt(1, 1000),
+		DecisionID:   util.RandomInt(1, 1000),
+		FromWalletID: util.RandomInt(1, 100),
+		ToWalletID:   util.RandomInt(101, 200),
+		Amount:       util.RandomMoney(),
+		Status:       db.ReviewPending,
+	}
+}
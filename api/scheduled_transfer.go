@@ -0,0 +1,258 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createScheduledTransferRequest struct {
+	transferRequest
+	Frequency  string     `json:"frequency" binding:"required,frequency"`
+	DayOfMonth int32      `json:"day_of_month" binding:"min=0,max=31"`
+	StartAt    time.Time  `json:"start_at" binding:"required"`
+	EndAt      *time.Time `json:"end_at"`
+}
+
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !req.StartAt.After(time.Now()) {
+		err := errors.New("start_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(req.StartAt) {
+		err := errors.New("end_at must not be before start_at")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromWallet, valid := server.validateWallet(ctx, req.FromWalletID, req.Currency)
+	if !valid {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if _, valid := server.validateWallet(ctx, req.ToWalletID, req.Currency); !valid {
+		return
+	}
+
+	// monthly transfers repeat on the day of the first one unless told otherwise
+	dayOfMonth := int32(0)
+	if req.Frequency == util.FrequencyMonthly {
+		dayOfMonth = req.DayOfMonth
+		if dayOfMonth == 0 {
+			dayOfMonth = int32(req.StartAt.In(server.limitSchedule.Location).Day())
+		}
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		Owner:        payload.Username,
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Frequency:    req.Frequency,
+		DayOfMonth:   dayOfMonth,
+		NextRunAt:    req.StartAt,
+		EndAt:        nullTime(req.EndAt),
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type scheduledTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	scheduled, ok := server.ownedScheduledTransfer(ctx, permissionReadAny)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type listScheduledTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var req listScheduledTransfersRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.ListScheduledTransfersParams{
+		Owner:  payload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	scheduled, err := server.store.ListScheduledTransfers(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type updateScheduledTransferRequest struct {
+	Amount int64      `json:"amount" binding:"required,gt=0"`
+	EndAt  *time.Time `json:"end_at"`
+}
+
+func (server *Server) updateScheduledTransfer(ctx *gin.Context) {
+	var req updateScheduledTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, ok := server.ownedScheduledTransfer(ctx, permissionWriteAny)
+	if !ok {
+		return
+	}
+
+	if req.EndAt != nil && req.EndAt.Before(scheduled.NextRunAt) {
+		err := errors.New("end_at must not be before the next run")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, err := server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduled.ID,
+		Amount: req.Amount,
+		EndAt:  nullTime(req.EndAt),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("scheduled transfer is no longer active")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+func (server *Server) cancelScheduledTransfer(ctx *gin.Context) {
+	scheduled, ok := server.ownedScheduledTransfer(ctx, permissionWriteAny)
+	if !ok {
+		return
+	}
+
+	scheduled, err := server.store.CancelScheduledTransfer(ctx, scheduled.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.New("scheduled transfer is no longer active")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type listScheduledTransferRunsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listScheduledTransferRuns(ctx *gin.Context) {
+	var req listScheduledTransferRunsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, ok := server.ownedScheduledTransfer(ctx, permissionReadAny)
+	if !ok {
+		return
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               req.PageSize,
+		Offset:              (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// ownedScheduledTransfer loads the scheduled transfer in the URI and checks
+// the authenticated user may act on it
+func (server *Server) ownedScheduledTransfer(ctx *gin.Context, anyPermission string) (db.ScheduledTransfer, bool) {
+	var uri scheduledTransferURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.ScheduledTransfer{}, false
+	}
+
+	scheduled, err := server.store.GetScheduledTransfer(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return scheduled, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduled, false
+	}
+
+	return scheduled, authorizeOwner(ctx, scheduled.Owner, anyPermission)
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
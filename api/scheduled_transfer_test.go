@@ -0,0 +1,295 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduledTransferAPI(t *testing.T) {
+	from := randomWallet()
+	to := randomWallet()
+	to.ID = from.ID + 100
+	to.Currency = from.Currency
+
+	startAt := time.Date(time.Now().Year()+1, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Monthly",
+			body: gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+				"frequency":      util.FrequencyMonthly,
+				"start_at":       startAt,
+			},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+
+				arg := db.CreateScheduledTransferParams{
+					Owner:        from.Owner,
+					FromWalletID: from.ID,
+					ToWalletID:   to.ID,
+					Amount:       100,
+					Frequency:    util.FrequencyMonthly,
+					DayOfMonth:   31,
+					NextRunAt:    startAt,
+				}
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ScheduledTransfer{ID: 1, Owner: from.Owner}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WeeklyIgnoresDayOfMonth",
+			body: gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+				"frequency":      util.FrequencyWeekly,
+				"day_of_month":   5,
+				"start_at":       startAt,
+				"end_at":         startAt.AddDate(0, 2, 0),
+			},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+
+				arg := db.CreateScheduledTransferParams{
+					Owner:        from.Owner,
+					FromWalletID: from.ID,
+					ToWalletID:   to.ID,
+					Amount:       100,
+					Frequency:    util.FrequencyWeekly,
+					NextRunAt:    startAt,
+					EndAt:        sql.NullTime{Time: startAt.AddDate(0, 2, 0), Valid: true},
+				}
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ScheduledTransfer{ID: 1, Owner: from.Owner}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "StartInThePast",
+			body: gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+				"frequency":      util.FrequencyOnce,
+				"start_at":       time.Now().Add(-time.Minute),
+			},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidFrequency",
+			body: gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+				"frequency":      "daily",
+				"start_at":       startAt,
+			},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotTheOwner",
+			body: gin.H{
+				"from_wallet_id": from.ID,
+				"to_wallet_id":   to.ID,
+				"amount":         100,
+				"currency":       from.Currency,
+				"frequency":      util.FrequencyOnce,
+				"start_at":       startAt,
+			},
+			setupAuth: authAs(util.RandomString(6), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/scheduled-transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCancelScheduledTransferAPI(t *testing.T) {
+	scheduled := db.ScheduledTransfer{
+		ID:     util.RandomInt(1, 1000),
+		Owner:  util.RandomString(6),
+		Status: db.ScheduledTransferActive,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: authAs(scheduled.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(scheduled, nil)
+
+				cancelled := scheduled
+				cancelled.Status = db.ScheduledTransferCancelled
+				store.EXPECT().CancelScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(cancelled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), db.ScheduledTransferCancelled)
+			},
+		},
+		{
+			name:      "NotActive",
+			setupAuth: authAs(scheduled.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(scheduled, nil)
+				store.EXPECT().CancelScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(scheduled, nil)
+				store.EXPECT().CancelScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			setupAuth: authAs(scheduled.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), scheduled.ID).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/scheduled-transfers/%d", scheduled.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReadNotificationAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := util.RandomString(6)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: 7, Owner: owner})).
+		Times(1).
+		Return(db.Notification{}, sql.ErrNoRows)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/notifications/7/read", nil)
+	require.NoError(t, err)
+
+	authAs(owner, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -1,7 +1,14 @@
 package api
 
 import (
+	"fmt"
+	"picpay_simplificado/blob"
 	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/ratelimit"
+	"picpay_simplificado/risk"
+	"picpay_simplificado/stream"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -10,44 +17,284 @@ import (
 
 // Server serves HTTP for requests
 type Server struct {
-	store  db.Store
-	router *gin.Engine
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	apiSigner  *token.APIKeySigner
+	broker     *stream.Broker
+	riskEngine *risk.Engine
+	limiter    ratelimit.Limiter
+	rateLimits rateLimits
+	blobs      blob.Store
+	router     *gin.Engine
+
+	transferLimits util.TransferLimits
+	limitSchedule  util.LimitSchedule
 }
 
 // NewServer creates a new HTTP server and setup routing
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
+func NewServer(config util.Config, store db.Store, broker *stream.Broker, riskEngine *risk.Engine) (*Server, error) {
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	apiSigner, err := token.NewAPIKeySigner(config.APIKeySigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create api key signer: %w", err)
+	}
+
+	limitSchedule, err := config.LimitSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load limit schedule: %w", err)
+	}
+
+	server := &Server{
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		apiSigner:      apiSigner,
+		broker:         broker,
+		riskEngine:     riskEngine,
+		transferLimits: config.TransferLimits(),
+		limitSchedule:  limitSchedule,
+	}
+	if err := server.setupRateLimits(); err != nil {
+		return nil, fmt.Errorf("cannot setup rate limits: %w", err)
+	}
+	if err := server.setupBlobs(); err != nil {
+		return nil, fmt.Errorf("cannot setup blob store: %w", err)
+	}
+
 	router := gin.Default()
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("cannot set trusted proxies: %w", err)
+	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("role", validRole)
+		v.RegisterValidation("event_type", validEventType)
+		v.RegisterValidation("limit_name", validLimitName)
+		v.RegisterValidation("frequency", validFrequency)
+		v.RegisterValidation("fee_operation", validFeeOperation)
 	}
 
+	defaultLimit := rateLimitMiddleware(server.limiter, "default", server.rateLimits.Default)
+	transfersLimit := rateLimitMiddleware(server.limiter, "transfers", server.rateLimits.Transfers)
+
+	//public
+	router.POST("/users", defaultLimit, server.createUser)
+	router.POST("/users/login", rateLimitMiddleware(server.limiter, "login", server.rateLimits.Login), server.loginUser)
+
+	// authLimit runs before authentication, so it counts every request per
+	// client IP and also throttles callers guessing credentials
+	authLimit := rateLimitMiddleware(server.limiter, "auth", server.rateLimits.Auth)
+	authRoutes := router.Group("/").Use(authLimit, authMiddleware(server.tokenMaker, server.apiSigner, server.store), defaultLimit)
+
 	//wallets
-	router.POST("/wallets", server.createWallet)
-	router.GET("/wallets/:id", server.getWallet)
-	router.GET("/wallets", server.listWallets)
-	router.PUT("/wallets", server.updateWallet)
-	router.DELETE("/wallets/:id", server.deleteWallet)
+	authRoutes.POST("/wallets", requirePermissions(permissionWalletsWrite), server.createWallet)
+	authRoutes.GET("/wallets/:id", requirePermissions(permissionWalletsRead), server.getWallet)
+	authRoutes.GET("/wallets", requirePermissions(permissionWalletsList), server.listWallets)
+	authRoutes.PUT("/wallets", requirePermissions(permissionWalletsAdjust), server.updateWallet)
+	authRoutes.DELETE("/wallets/:id", requirePermissions(permissionWalletsWrite), server.deleteWallet)
+	authRoutes.POST("/wallets/:id/freeze", requirePermissions(permissionWalletsFreeze), server.freezeWallet)
+	authRoutes.POST("/wallets/:id/unfreeze", requirePermissions(permissionWalletsFreeze), server.unfreezeWallet)
+	authRoutes.GET("/wallets/:id/events", requirePermissions(permissionWalletsRead), server.streamWalletEvents)
+	authRoutes.GET("/wallets/:id/qr", requirePermissions(permissionWalletsRead), server.getWalletBRCode)
 
 	//users
-	router.GET("/users/:id", server.getUser)
-	router.POST("/users", server.createUser)
-	router.DELETE("/users/:id", server.deleteUser)
-	router.GET("/users", server.listUsers)
-	router.PUT("/users", server.updateUser)
+	authRoutes.GET("/users/:id", requirePermissions(permissionUsersRead), server.getUser)
+	authRoutes.DELETE("/users/:id", requirePermissions(permissionUsersWrite), server.deleteUser)
+	authRoutes.GET("/users", requirePermissions(permissionUsersList), server.listUsers)
+	authRoutes.PUT("/users", requirePermissions(permissionUsersWrite), server.updateUser)
+	authRoutes.PUT("/users/:id/role", requirePermissions(permissionUsersRole), server.updateUserRole)
+	authRoutes.GET("/users/:id/limits", requirePermissions(permissionUsersRead), server.getTransferLimits)
+	authRoutes.PUT("/users/:id/limits", requirePermissions(permissionLimitsWrite), server.updateTransferLimits)
+	authRoutes.DELETE("/users/:id/limits", requirePermissions(permissionLimitsWrite), server.resetTransferLimits)
+
+	//limit requests
+	authRoutes.POST("/limit-requests", requirePermissions(permissionLimitsRequest), server.createLimitRequest)
+	authRoutes.GET("/limit-requests", requirePermissions(permissionLimitsRequest), server.listLimitRequests)
+	authRoutes.POST("/limit-requests/:id/cancel", requirePermissions(permissionLimitsRequest), server.cancelLimitRequest)
+	authRoutes.POST("/limit-requests/:id/reject", requirePermissions(permissionLimitsWrite), server.rejectLimitRequest)
 
 	//entries
-	router.GET("/entries/:id", server.getEntry)
-	router.POST("/entries", server.createEntry)
-	router.GET("/entries", server.listEntries)
+	authRoutes.GET("/entries/:id", requirePermissions(permissionEntriesRead), server.getEntry)
+	authRoutes.POST("/entries", requirePermissions(permissionWalletsAdjust), server.createEntry)
+	authRoutes.GET("/entries", requirePermissions(permissionEntriesRead), server.listEntries)
 
 	//transfer
-	router.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers", transfersLimit, requirePermissions(permissionTransfersWrite), server.createTransfer)
+	authRoutes.POST("/transfers/quote", requirePermissions(permissionTransfersRead), server.quoteTransfer)
+	authRoutes.POST("/transfers/qr", transfersLimit, requirePermissions(permissionTransfersWrite), server.createBRCodeTransfer)
+	authRoutes.POST("/transfers/:id/refunds", transfersLimit, requirePermissions(permissionRefundsWrite), server.createRefund)
+	authRoutes.GET("/transfers/:id/refunds", transfersLimit, requirePermissions(permissionTransfersRead), server.listRefunds)
+
+	//split transfers
+	authRoutes.POST("/split-transfers", transfersLimit, requirePermissions(permissionTransfersWrite), server.createSplitTransfer)
+	authRoutes.GET("/split-transfers/:id", requirePermissions(permissionTransfersRead), server.getSplitTransfer)
+
+	//payout batches
+	authRoutes.POST("/payout-batches", transfersLimit, requirePermissions(permissionTransfersWrite), server.createPayoutBatch)
+	authRoutes.GET("/payout-batches", requirePermissions(permissionTransfersRead), server.listPayoutBatches)
+	authRoutes.GET("/payout-batches/:id", requirePermissions(permissionTransfersRead), server.getPayoutBatch)
+	authRoutes.GET("/payout-batches/:id/rows", requirePermissions(permissionTransfersRead), server.listPayoutRows)
+	authRoutes.GET("/payout-batches/:id/report", requirePermissions(permissionTransfersRead), server.getPayoutReport)
+	authRoutes.POST("/payout-batches/:id/pause", requirePermissions(permissionTransfersWrite), server.pausePayoutBatch)
+	authRoutes.POST("/payout-batches/:id/resume", requirePermissions(permissionTransfersWrite), server.resumePayoutBatch)
+	authRoutes.POST("/payout-batches/:id/cancel", requirePermissions(permissionTransfersWrite), server.cancelPayoutBatch)
+
+	//scheduled transfers
+	authRoutes.POST("/scheduled-transfers", requirePermissions(permissionTransfersWrite), server.createScheduledTransfer)
+	authRoutes.GET("/scheduled-transfers", requirePermissions(permissionTransfersRead), server.listScheduledTransfers)
+	authRoutes.GET("/scheduled-transfers/:id", requirePermissions(permissionTransfersRead), server.getScheduledTransfer)
+	authRoutes.PUT("/scheduled-transfers/:id", requirePermissions(permissionTransfersWrite), server.updateScheduledTransfer)
+	authRoutes.DELETE("/scheduled-transfers/:id", requirePermissions(permissionTransfersWrite), server.cancelScheduledTransfer)
+	authRoutes.GET("/scheduled-transfers/:id/runs", requirePermissions(permissionTransfersRead), server.listScheduledTransferRuns)
+
+	//payment requests
+	authRoutes.POST("/payment-requests", requirePermissions(permissionTransfersWrite), server.createPaymentRequest)
+	authRoutes.GET("/payment-requests/outgoing", requirePermissions(permissionTransfersRead), server.listOutgoingPaymentRequests)
+	authRoutes.GET("/payment-requests/incoming", requirePermissions(permissionTransfersRead), server.listIncomingPaymentRequests)
+	authRoutes.GET("/payment-requests/:id", requirePermissions(permissionTransfersRead), server.getPaymentRequest)
+	authRoutes.GET("/payment-requests/:id/qr", requirePermissions(permissionTransfersRead), server.getPaymentRequestBRCode)
+	authRoutes.POST("/payment-requests/:id/accept", transfersLimit, requirePermissions(permissionTransfersWrite), server.acceptPaymentRequest)
+	authRoutes.POST("/payment-requests/:id/decline", requirePermissions(permissionTransfersWrite), server.declinePaymentRequest)
+	authRoutes.POST("/payment-requests/:id/cancel", requirePermissions(permissionTransfersWrite), server.cancelPaymentRequest)
+
+	//holds
+	authRoutes.POST("/holds", transfersLimit, requirePermissions(permissionTransfersWrite), server.createHold)
+	authRoutes.GET("/holds", requirePermissions(permissionTransfersRead), server.listHolds)
+	authRoutes.GET("/holds/:id", requirePermissions(permissionTransfersRead), server.getHold)
+	authRoutes.POST("/holds/:id/capture", transfersLimit, requirePermissions(permissionHoldsWrite), server.captureHold)
+	authRoutes.POST("/holds/:id/void", requirePermissions(permissionHoldsWrite), server.voidHold)
+
+	//escrows
+	authRoutes.POST("/escrows", transfersLimit, requirePermissions(permissionTransfersWrite), server.createEscrow)
+	authRoutes.GET("/escrows", requirePermissions(permissionTransfersRead), server.listEscrows)
+	authRoutes.GET("/escrows/:id", requirePermissions(permissionTransfersRead), server.getEscrow)
+	authRoutes.POST("/escrows/:id/confirm", requirePermissions(permissionTransfersWrite), server.confirmEscrow)
+	authRoutes.POST("/escrows/:id/refund", requirePermissions(permissionTransfersWrite), server.agreeEscrowRefund)
+	authRoutes.POST("/escrows/:id/dispute", requirePermissions(permissionTransfersWrite), server.disputeEscrow)
+	authRoutes.POST("/escrows/:id/resolve", requirePermissions(permissionEscrowsResolve), server.resolveEscrow)
+
+	//disputes
+	authRoutes.POST("/transfers/:id/disputes", requirePermissions(permissionTransfersWrite), server.openDispute)
+	authRoutes.GET("/disputes", requirePermissions(permissionTransfersRead), server.listDisputes)
+	authRoutes.GET("/disputes/queue", requirePermissions(permissionDisputesRead), server.listDisputeQueue)
+	authRoutes.GET("/disputes/:id", requirePermissions(permissionTransfersRead), server.getDispute)
+	authRoutes.POST("/disputes/:id/evidence", requirePermissions(permissionTransfersWrite), server.uploadDisputeEvidence)
+	authRoutes.GET("/disputes/:id/evidence", requirePermissions(permissionTransfersRead), server.listDisputeEvidence)
+	authRoutes.GET("/disputes/:id/evidence/:evidence_id", requirePermissions(permissionTransfersRead), server.downloadDisputeEvidence)
+	authRoutes.POST("/disputes/:id/respond", requirePermissions(permissionTransfersWrite), server.respondDispute)
+	authRoutes.POST("/disputes/:id/decide", requirePermissions(permissionDisputesWrite), server.decideDispute)
+	authRoutes.GET("/users/:id/dispute-rate", requirePermissions(permissionTransfersRead), server.getMerchantDisputeRate)
+
+	//receivables
+	authRoutes.GET("/users/:id/settlement-plan", requirePermissions(permissionUsersRead), server.getSettlementPlan)
+	authRoutes.PUT("/users/:id/settlement-plan", requirePermissions(permissionSettlementsWrite), server.updateSettlementPlan)
+	authRoutes.GET("/receivables", requirePermissions(permissionTransfersRead), server.listReceivables)
+	authRoutes.GET("/receivables/anticipation", requirePermissions(permissionTransfersRead), server.quoteAnticipation)
+	authRoutes.POST("/receivables/anticipate", requirePermissions(permissionTransfersWrite), server.anticipateReceivables)
+
+	//reserves
+	authRoutes.GET("/users/:id/reserve-rule", requirePermissions(permissionUsersRead), server.getReserveRule)
+	authRoutes.PUT("/users/:id/reserve-rule", requirePermissions(permissionReservesWrite), server.updateReserveRule)
+	authRoutes.DELETE("/users/:id/reserve-rule", requirePermissions(permissionReservesWrite), server.deleteReserveRule)
+	authRoutes.GET("/reserves", requirePermissions(permissionTransfersRead), server.getReserveStatement)
+
+	//installments
+	authRoutes.POST("/installment-plans", transfersLimit, requirePermissions(permissionTransfersWrite), server.createInstallmentPlan)
+	authRoutes.GET("/installment-plans", requirePermissions(permissionTransfersRead), server.listInstallmentPlans)
+	authRoutes.GET("/installment-plans/:id", requirePermissions(permissionTransfersRead), server.getInstallmentPlan)
+	authRoutes.POST("/installment-plans/:id/prepay", transfersLimit, requirePermissions(permissionTransfersWrite), server.prepayInstallmentPlan)
+	authRoutes.POST("/installment-plans/:id/cancel", requirePermissions(permissionTransfersWrite), server.cancelInstallmentPlan)
+	authRoutes.PUT("/users/:id/installment-funding", requirePermissions(permissionInstallmentsFund), server.optInInstallmentFunding)
+	authRoutes.DELETE("/users/:id/installment-funding", requirePermissions(permissionInstallmentsFund), server.optOutInstallmentFunding)
+
+	//checkout
+	authRoutes.POST("/checkout-sessions", requirePermissions(permissionTransfersWrite), server.createCheckoutSession)
+	authRoutes.GET("/checkout-sessions", requirePermissions(permissionTransfersRead), server.listCheckoutSessions)
+	authRoutes.GET("/checkout/:token", requirePermissions(permissionTransfersRead), server.getCheckoutSession)
+	authRoutes.POST("/checkout/:token/pay", transfersLimit, requirePermissions(permissionTransfersWrite), server.payCheckoutSession)
+	authRoutes.POST("/payment-links", requirePermissions(permissionTransfersWrite), server.createPaymentLink)
+	authRoutes.GET("/payment-links", requirePermissions(permissionTransfersRead), server.listPaymentLinks)
+	authRoutes.GET("/payment-links/:token", requirePermissions(permissionTransfersRead), server.getPaymentLink)
+	authRoutes.POST("/payment-links/:token/sessions", requirePermissions(permissionTransfersWrite), server.createPaymentLinkSession)
+	authRoutes.POST("/payment-links/:token/deactivate", requirePermissions(permissionTransfersWrite), server.deactivatePaymentLink)
+
+	//subscriptions
+	authRoutes.POST("/subscription-plans", requirePermissions(permissionTransfersWrite), server.createSubscriptionPlan)
+	authRoutes.GET("/subscription-plans", requirePermissions(permissionTransfersRead), server.listSubscriptionPlans)
+	authRoutes.GET("/subscription-plans/:id", requirePermissions(permissionTransfersRead), server.getSubscriptionPlan)
+	authRoutes.POST("/subscription-plans/:id/deactivate", requirePermissions(permissionTransfersWrite), server.deactivateSubscriptionPlan)
+	authRoutes.POST("/subscriptions", transfersLimit, requirePermissions(permissionTransfersWrite), server.createSubscription)
+	authRoutes.GET("/subscriptions", requirePermissions(permissionTransfersRead), server.listSubscriptions)
+	authRoutes.GET("/subscriptions/:id", requirePermissions(permissionTransfersRead), server.getSubscription)
+	authRoutes.POST("/subscriptions/:id/plan", transfersLimit, requirePermissions(permissionTransfersWrite), server.changeSubscriptionPlan)
+	authRoutes.POST("/subscriptions/:id/cancel", requirePermissions(permissionTransfersWrite), server.cancelSubscription)
+
+	//invoices
+	authRoutes.POST("/invoices", requirePermissions(permissionTransfersWrite), server.createInvoice)
+	authRoutes.GET("/invoices/outgoing", requirePermissions(permissionTransfersRead), server.listOutgoingInvoices)
+	authRoutes.GET("/invoices/incoming", requirePermissions(permissionTransfersRead), server.listIncomingInvoices)
+	authRoutes.GET("/invoices/:id", requirePermissions(permissionTransfersRead), server.getInvoice)
+	authRoutes.PUT("/invoices/:id", requirePermissions(permissionTransfersWrite), server.updateInvoice)
+	authRoutes.POST("/invoices/:id/finalize", requirePermissions(permissionTransfersWrite), server.finalizeInvoice)
+	authRoutes.POST("/invoices/:id/void", requirePermissions(permissionTransfersWrite), server.voidInvoice)
+	authRoutes.POST("/invoices/:id/pay", transfersLimit, requirePermissions(permissionTransfersWrite), server.payInvoice)
+
+	//cashback
+	authRoutes.POST("/cashback-campaigns", requirePermissions(permissionCampaignsWrite), server.createCashbackCampaign)
+	authRoutes.GET("/cashback-campaigns", requirePermissions(permissionTransfersRead), server.listCashbackCampaigns)
+	authRoutes.GET("/cashback-campaigns/:id", requirePermissions(permissionTransfersRead), server.getCashbackCampaign)
+	authRoutes.POST("/cashback-campaigns/:id/deactivate", requirePermissions(permissionCampaignsWrite), server.deactivateCashbackCampaign)
+	authRoutes.PUT("/users/:id/merchant-category", requirePermissions(permissionCampaignsWrite), server.updateMerchantCategory)
+	authRoutes.GET("/cashback", requirePermissions(permissionTransfersRead), server.listCashback)
+
+	//notifications
+	authRoutes.GET("/notifications", requirePermissions(permissionNotificationsRead), server.listNotifications)
+	authRoutes.POST("/notifications/:id/read", requirePermissions(permissionNotificationsRead), server.readNotification)
+
+	//fees
+	authRoutes.GET("/fees/quote", requirePermissions(permissionTransfersRead), server.quoteFee)
+	authRoutes.POST("/fee-schedules", requirePermissions(permissionFeesWrite), server.createFeeSchedule)
+	authRoutes.GET("/fee-schedules", requirePermissions(permissionFeesRead), server.listFeeSchedules)
+	authRoutes.DELETE("/fee-schedules/:id", requirePermissions(permissionFeesWrite), server.deleteFeeSchedule)
+
+	//ledger
+	authRoutes.GET("/ledger/accounts", requirePermissions(permissionLedgerRead), server.listLedgerAccounts)
+	authRoutes.GET("/ledger/trial-balance", requirePermissions(permissionLedgerRead), server.getTrialBalance)
+	authRoutes.GET("/ledger/chain/verify", requirePermissions(permissionLedgerRead), server.verifyChain)
+	authRoutes.GET("/ledger/checkpoints", requirePermissions(permissionLedgerRead), server.listChainCheckpoints)
+
+	//risk
+	authRoutes.GET("/reviews", requirePermissions(permissionReviewsRead), server.listTransferReviews)
+	authRoutes.GET("/reviews/:id", requirePermissions(permissionReviewsRead), server.getTransferReview)
+	authRoutes.POST("/reviews/:id/approve", requirePermissions(permissionReviewsWrite), server.approveTransferReview)
+	authRoutes.POST("/reviews/:id/reject", requirePermissions(permissionReviewsWrite), server.rejectTransferReview)
+	authRoutes.GET("/risk/decisions", requirePermissions(permissionReviewsRead), server.listRiskDecisions)
+	authRoutes.GET("/risk/rules", requirePermissions(permissionReviewsRead), server.getRiskRules)
+
+	//api keys
+	authRoutes.POST("/api-keys", requirePermissions(permissionAPIKeysWrite), server.createAPIKey)
+	authRoutes.GET("/api-keys", requirePermissions(permissionAPIKeysWrite), server.listAPIKeys)
+	authRoutes.POST("/api-keys/:id/rotate", requirePermissions(permissionAPIKeysWrite), server.rotateAPIKey)
+	authRoutes.DELETE("/api-keys/:id", requirePermissions(permissionAPIKeysWrite), server.revokeAPIKey)
+
+	//webhooks
+	authRoutes.POST("/webhooks", requirePermissions(permissionWebhooksWrite), server.createWebhookEndpoint)
+	authRoutes.GET("/webhooks", requirePermissions(permissionWebhooksWrite), server.listWebhookEndpoints)
+	authRoutes.DELETE("/webhooks/:id", requirePermissions(permissionWebhooksWrite), server.deleteWebhookEndpoint)
+	authRoutes.POST("/webhooks/:id/enable", requirePermissions(permissionWebhooksWrite), server.enableWebhookEndpoint)
+	authRoutes.GET("/webhooks/:id/deliveries", requirePermissions(permissionWebhooksWrite), server.listWebhookDeliveries)
+	authRoutes.GET("/webhook-deliveries/:id/attempts", requirePermissions(permissionWebhooksWrite), server.listWebhookDeliveryAttempts)
+	authRoutes.POST("/webhook-deliveries/:id/redeliver", requirePermissions(permissionWebhooksWrite), server.redeliverWebhook)
 
 	//add routes to router
 	server.router = router
-	return server
+	return server, nil
 }
 
 // StartServer runs the HTTP server on a specif address
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type splitRecipientRequest struct {
+	WalletID    int64 `json:"wallet_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"min=0"`
+	BasisPoints int64 `json:"basis_points" binding:"min=0,max=10000"`
+}
+
+type splitTransferRequest struct {
+	FromWalletID int64                   `json:"from_wallet_id" binding:"required,min=1"`
+	Amount       int64                   `json:"amount" binding:"required,gt=0"`
+	Currency     string                  `json:"currency" binding:"required,currency"`
+	Description  string                  `json:"description" binding:"max=140"`
+	Recipients   []splitRecipientRequest `json:"recipients" binding:"required,min=2,max=10,dive"`
+}
+
+func (server *Server) createSplitTransfer(ctx *gin.Context) {
+	var req splitTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromWallet, valid := server.validateWallet(ctx, req.FromWalletID, req.Currency)
+	if !valid {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	recipients := make([]db.SplitRecipient, len(req.Recipients))
+	for i, recipient := range req.Recipients {
+		recipients[i] = db.SplitRecipient{
+			WalletID: recipient.WalletID,
+			SplitShare: util.SplitShare{
+				Amount:      recipient.Amount,
+				BasisPoints: recipient.BasisPoints,
+			},
+		}
+	}
+
+	now := time.Now()
+
+	result, err := server.store.SplitTransferTx(ctx, db.SplitTransferTxParams{
+		FromWalletID: req.FromWalletID,
+		Amount:       req.Amount,
+		Description:  req.Description,
+		Recipients:   recipients,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk: server.riskEngine,
+		Fees: server.fees(now),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) || errors.Is(err, db.ErrWalletFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, util.ErrInvalidSplit) || errors.Is(err, db.ErrCurrencyMismatch) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type splitTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type splitTransferResponse struct {
+	SplitTransfer db.SplitTransfer `json:"split_transfer"`
+	Transfers     []db.Transfer    `json:"transfers"`
+}
+
+func (server *Server) getSplitTransfer(ctx *gin.Context) {
+	var uri splitTransferURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	split, err := server.store.GetSplitTransfer(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	fromWallet, err := server.store.GetWallet(ctx, split.FromWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, fromWallet.Owner, permissionReadAny) {
+		return
+	}
+
+	transfers, err := server.store.ListSplitTransferTransfers(ctx, sql.NullInt64{Int64: split.ID, Valid: true})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, splitTransferResponse{
+		SplitTransfer: split,
+		Transfers:     transfers,
+	})
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSplitTransferAPI(t *testing.T) {
+	from := randomWallet()
+	seller := from.ID + 100
+	platform := from.ID + 200
+
+	recipients := []gin.H{
+		{"wallet_id": seller, "basis_points": 9000},
+		{"wallet_id": platform, "basis_points": 1000},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					SplitTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SplitTransferTxParams) (db.SplitTransferTxResult, error) {
+						require.Equal(t, from.ID, arg.FromWalletID)
+						require.Equal(t, int64(1000), arg.Amount)
+						require.Equal(t, []db.SplitRecipient{
+							{WalletID: seller, SplitShare: util.SplitShare{BasisPoints: 9000}},
+							{WalletID: platform, SplitShare: util.SplitShare{BasisPoints: 1000}},
+						}, arg.Recipients)
+						require.NotNil(t, arg.Limits)
+						require.NotNil(t, arg.Risk)
+						return db.SplitTransferTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "OneRecipient",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients[:1]},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SplitTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotOwner",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients},
+			setupAuth: authAs(util.RandomString(7), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().SplitTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidSplit",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().
+					SplitTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SplitTransferTxResult{}, fmt.Errorf("%w: percentages must add up to 100%%", util.ErrInvalidSplit))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "RecipientNotFound",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().SplitTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SplitTransferTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "Blocked",
+			body:      gin.H{"from_wallet_id": from.ID, "amount": 1000, "currency": from.Currency, "recipients": recipients},
+			setupAuth: authAs(from.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(1).Return(from, nil)
+				store.EXPECT().SplitTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SplitTransferTxResult{}, db.ErrTransferBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/split-transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetSplitTransferAPI(t *testing.T) {
+	from := randomWallet()
+	split := db.SplitTransfer{ID: util.RandomInt(1, 1000), FromWalletID: from.ID, Amount: 10}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSplitTransfer(gomock.Any(), split.ID).Times(2).Return(split, nil)
+	store.EXPECT().GetWallet(gomock.Any(), from.ID).Times(2).Return(from, nil)
+	store.EXPECT().
+		ListSplitTransferTransfers(gomock.Any(), sql.NullInt64{Int64: split.ID, Valid: true}).
+		Times(1).
+		Return([]db.Transfer{{ID: 1}, {ID: 2}}, nil)
+
+	server := newTestServer(t, store)
+
+	url := fmt.Sprintf("/split-transfers/%d", split.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	authAs(from.Owner, util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response splitTransferResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Transfers, 2)
+
+	request, err = http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	authAs(util.RandomString(7), util.CustomerRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
@@ -0,0 +1,425 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type createSubscriptionPlanRequest struct {
+	WalletID  int64  `json:"wallet_id" binding:"required,min=1"`
+	Name      string `json:"name" binding:"required,max=80"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Currency  string `json:"currency" binding:"required,currency"`
+	Frequency string `json:"frequency" binding:"required,oneof=weekly monthly"`
+	TrialDays int32  `json:"trial_days" binding:"min=0,max=365"`
+}
+
+// createSubscriptionPlan offers customers to pay into the merchant wallet
+// every week or month
+func (server *Server) createSubscriptionPlan(ctx *gin.Context) {
+	var req createSubscriptionPlanRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validateMerchantWallet(ctx, req.WalletID, req.Currency) {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	plan, err := server.store.CreateSubscriptionPlan(ctx, db.CreateSubscriptionPlanParams{
+		MerchantWalletID: req.WalletID,
+		Name:             req.Name,
+		Amount:           req.Amount,
+		Currency:         req.Currency,
+		Frequency:        req.Frequency,
+		TrialDays:        req.TrialDays,
+		CreatedBy:        payload.Username,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
+
+type listSubscriptionsRequest struct {
+	WalletID int64 `form:"wallet_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listSubscriptionPlans returns the plans paid into the wallet, newest first
+func (server *Server) listSubscriptionPlans(ctx *gin.Context) {
+	var req listSubscriptionsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	plans, err := server.store.ListWalletSubscriptionPlans(ctx, db.ListWalletSubscriptionPlansParams{
+		MerchantWalletID: req.WalletID,
+		Limit:            req.PageSize,
+		Offset:           (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plans)
+}
+
+type subscriptionURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getSubscriptionPlan shows the plan to any user, who may subscribe to it
+func (server *Server) getSubscriptionPlan(ctx *gin.Context) {
+	plan, ok := server.subscriptionPlanFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
+
+// deactivateSubscriptionPlan stops new customers from subscribing to the
+// plan, on behalf of the merchant or an admin. Subscribers keep being charged
+func (server *Server) deactivateSubscriptionPlan(ctx *gin.Context) {
+	plan, ok := server.subscriptionPlanFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	merchant, err := server.store.GetWallet(ctx, plan.MerchantWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, merchant.Owner, permissionWriteAny) {
+		return
+	}
+
+	plan, err = server.store.DeactivateSubscriptionPlan(ctx, plan.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
+
+func (server *Server) subscriptionPlanFromURI(ctx *gin.Context) (db.SubscriptionPlan, bool) {
+	var uri subscriptionURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.SubscriptionPlan{}, false
+	}
+
+	plan, err := server.store.GetSubscriptionPlan(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return plan, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return plan, false
+	}
+
+	return plan, true
+}
+
+type createSubscriptionRequest struct {
+	PlanID   int64 `json:"plan_id" binding:"required,min=1"`
+	WalletID int64 `json:"wallet_id" binding:"required,min=1"`
+	// ApproveMandate is the customer allowing the merchant to charge the
+	// wallet every period until the subscription is cancelled
+	ApproveMandate bool `json:"approve_mandate" binding:"required"`
+}
+
+// createSubscription subscribes a wallet of the authenticated user to the
+// plan, the first period is charged at once unless the plan has a trial
+func (server *Server) createSubscription(ctx *gin.Context) {
+	var req createSubscriptionRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	plan, err := server.store.GetSubscriptionPlan(ctx, req.PlanID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.WalletID == plan.MerchantWalletID {
+		err := errors.New("wallet must not be the merchant wallet")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	wallet, valid := server.validateWallet(ctx, req.WalletID, plan.Currency)
+	if !valid {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if wallet.Owner != payload.Username {
+		err := errors.New("wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreateSubscriptionTx(ctx, db.CreateSubscriptionTxParams{
+		PlanID:           plan.ID,
+		CustomerWalletID: wallet.ID,
+		ApprovedBy:       payload.Username,
+		Now:              time.Now(),
+		Location:         server.limitSchedule.Location,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrSubscriptionPlanInactive) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrWalletFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if pqError, ok := err.(*pq.Error); ok && pqError.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// listSubscriptions returns the subscriptions the wallet pays or is paid
+// through, newest first
+func (server *Server) listSubscriptions(ctx *gin.Context) {
+	var req listSubscriptionsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeWallet(ctx, req.WalletID, permissionReadAny) {
+		return
+	}
+
+	subscriptions, err := server.store.ListWalletSubscriptions(ctx, db.ListWalletSubscriptionsParams{
+		WalletID:   req.WalletID,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// subscriptionParties is the subscription along with its customer and merchant
+type subscriptionParties struct {
+	subscription db.Subscription
+	customer     string
+	merchant     string
+}
+
+type subscriptionResponse struct {
+	Subscription db.Subscription         `json:"subscription"`
+	Charges      []db.SubscriptionCharge `json:"charges"`
+}
+
+func (server *Server) getSubscription(ctx *gin.Context) {
+	parties, ok := server.subscriptionFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.merchant && !authorizeOwner(ctx, parties.customer, permissionReadAny) {
+		return
+	}
+
+	charges, err := server.store.ListSubscriptionCharges(ctx, parties.subscription.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptionResponse{Subscription: parties.subscription, Charges: charges})
+}
+
+type changeSubscriptionPlanRequest struct {
+	PlanID int64 `json:"plan_id" binding:"required,min=1"`
+}
+
+// changeSubscriptionPlan moves the subscription to another plan of the
+// merchant, prorating the current period. Only the customer who approved the
+// mandate can change the amount it is charged
+func (server *Server) changeSubscriptionPlan(ctx *gin.Context) {
+	var req changeSubscriptionPlanRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	parties, ok := server.subscriptionFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username != parties.customer {
+		err := errors.New("only the customer can change the subscription plan")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ChangeSubscriptionPlanTx(ctx, db.ChangeSubscriptionPlanTxParams{
+		ID:     parties.subscription.ID,
+		PlanID: req.PlanID,
+		Now:    time.Now(),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrSubscriptionClosed) || errors.Is(err, db.ErrSubscriptionPastDue) ||
+			errors.Is(err, db.ErrSubscriptionPlanInactive) || errors.Is(err, db.ErrSubscriptionPlanMismatch) ||
+			errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrWalletFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type cancelSubscriptionRequest struct {
+	AtPeriodEnd bool `form:"at_period_end"`
+}
+
+// cancelSubscription stops charging the subscription, on behalf of the
+// customer, the merchant or an admin
+func (server *Server) cancelSubscription(ctx *gin.Context) {
+	var req cancelSubscriptionRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	parties, ok := server.subscriptionFromURI(ctx)
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	byCustomer := payload.Username == parties.customer
+	if !byCustomer && !authorizeOwner(ctx, parties.merchant, permissionWriteAny) {
+		return
+	}
+
+	subscription, err := server.store.CancelSubscriptionTx(ctx, db.CancelSubscriptionTxParams{
+		ID:          parties.subscription.ID,
+		AtPeriodEnd: req.AtPeriodEnd,
+		ByMerchant:  !byCustomer,
+		Now:         time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrSubscriptionClosed) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// subscriptionFromURI reads the subscription and the owners of its customer
+// and merchant wallets
+func (server *Server) subscriptionFromURI(ctx *gin.Context) (subscriptionParties, bool) {
+	var uri subscriptionURI
+	var parties subscriptionParties
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return parties, false
+	}
+
+	subscription, err := server.store.GetSubscription(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return parties, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	customer, err := server.store.GetWallet(ctx, subscription.CustomerWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	merchant, err := server.store.GetWallet(ctx, subscription.MerchantWalletID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return parties, false
+	}
+
+	return subscriptionParties{subscription: subscription, customer: customer.Owner, merchant: merchant.Owner}, true
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSubscriptionAPI(t *testing.T) {
+	customer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = customer.ID + 100
+
+	plan := db.SubscriptionPlan{
+		ID:               util.RandomInt(1, 1000),
+		MerchantWalletID: merchant.ID,
+		Amount:           2990,
+		Currency:         customer.Currency,
+		Frequency:        util.FrequencyMonthly,
+		Active:           true,
+	}
+
+	body := gin.H{
+		"plan_id":         plan.ID,
+		"wallet_id":       customer.ID,
+		"approve_mandate": true,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			body:      body,
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSubscriptionPlan(gomock.Any(), plan.ID).Times(1).Return(plan, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().
+					CreateSubscriptionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSubscriptionTxParams) (db.CreateSubscriptionTxResult, error) {
+						require.Equal(t, plan.ID, arg.PlanID)
+						require.Equal(t, customer.ID, arg.CustomerWalletID)
+						require.Equal(t, customer.Owner, arg.ApprovedBy)
+						require.NotNil(t, arg.Location)
+						return db.CreateSubscriptionTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "MandateNotApproved",
+			body:      gin.H{"plan_id": plan.ID, "wallet_id": customer.ID},
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateSubscriptionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NotWalletOwner",
+			body:      body,
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSubscriptionPlan(gomock.Any(), plan.ID).Times(1).Return(plan, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().CreateSubscriptionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "PlanNotFound",
+			body:      body,
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSubscriptionPlan(gomock.Any(), plan.ID).Times(1).Return(db.SubscriptionPlan{}, sql.ErrNoRows)
+				store.EXPECT().CreateSubscriptionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InsufficientFunds",
+			body:      body,
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSubscriptionPlan(gomock.Any(), plan.ID).Times(1).Return(plan, nil)
+				store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+				store.EXPECT().
+					CreateSubscriptionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateSubscriptionTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/subscriptions", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSubscriptionActionsAPI(t *testing.T) {
+	customer := randomWallet()
+	merchant := randomWallet()
+	merchant.ID = customer.ID + 100
+	subscription := db.Subscription{
+		ID:               util.RandomInt(1, 1000),
+		PlanID:           util.RandomInt(1, 1000),
+		CustomerWalletID: customer.ID,
+		MerchantWalletID: merchant.ID,
+		Status:           db.SubscriptionActive,
+	}
+	newPlanID := subscription.PlanID + 1
+
+	stubSubscription := func(store *mockdb.MockStore) {
+		store.EXPECT().GetSubscription(gomock.Any(), subscription.ID).Times(1).Return(subscription, nil)
+		store.EXPECT().GetWallet(gomock.Any(), customer.ID).Times(1).Return(customer, nil)
+		store.EXPECT().GetWallet(gomock.Any(), merchant.ID).Times(1).Return(merchant, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "CustomerChangesPlan",
+			action:    "plan",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().
+					ChangeSubscriptionPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ChangeSubscriptionPlanTxParams) (db.ChangeSubscriptionPlanTxResult, error) {
+						require.Equal(t, subscription.ID, arg.ID)
+						require.Equal(t, newPlanID, arg.PlanID)
+						return db.ChangeSubscriptionPlanTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantChangesPlan",
+			action:    "plan",
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().ChangeSubscriptionPlanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "ChangeToOtherMerchantPlan",
+			action:    "plan",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().
+					ChangeSubscriptionPlanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeSubscriptionPlanTxResult{}, db.ErrSubscriptionPlanMismatch)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "CustomerCancelsAtPeriodEnd",
+			action:    "cancel?at_period_end=true",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().
+					CancelSubscriptionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CancelSubscriptionTxParams) (db.Subscription, error) {
+						require.True(t, arg.AtPeriodEnd)
+						require.False(t, arg.ByMerchant)
+						return subscription, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "MerchantCancels",
+			action:    "cancel",
+			setupAuth: authAs(merchant.Owner, util.MerchantRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().
+					CancelSubscriptionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CancelSubscriptionTxParams) (db.Subscription, error) {
+						require.False(t, arg.AtPeriodEnd)
+						require.True(t, arg.ByMerchant)
+						return subscription, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "StrangerCancels",
+			action:    "cancel",
+			setupAuth: authAs("someone", util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().CancelSubscriptionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "CancelCancelled",
+			action:    "cancel",
+			setupAuth: authAs(customer.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				stubSubscription(store)
+				store.EXPECT().
+					CancelSubscriptionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Subscription{}, db.ErrSubscriptionClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"plan_id": newPlanID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/subscriptions/%d/%s", subscription.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
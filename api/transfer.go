@@ -2,9 +2,12 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,47 +27,207 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	if !server.validateWallet(ctx, req.FromWalletID, req.Currency) {
-		return
-	}
+	server.transfer(ctx, req.FromWalletID, req.ToWalletID, req.Amount, req.Currency)
+}
 
-	if !server.validateWallet(ctx, req.ToWalletID, req.Currency) {
+// transfer validates both wallets and moves the amount between them,
+// writing the result as the response
+func (server *Server) transfer(ctx *gin.Context, fromWalletID, toWalletID, amount int64, currency string) {
+	if !server.validateTransferWallets(ctx, fromWalletID, toWalletID, currency) {
 		return
 	}
 
+	now := time.Now()
+
 	arg := db.TrasferTxParms{
-		FromWalletID: req.FromWalletID,
-		ToWalletID:   req.ToWalletID,
-		Amount:       req.Amount,
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       amount,
+		Limits: &db.TransferLimitsParams{
+			Defaults: server.transferLimits,
+			Schedule: server.limitSchedule,
+			Now:      now,
+		},
+		Risk:     server.riskEngine,
+		Fees:     server.fees(now),
+		Cashback: server.cashback(now),
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 
 	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) || errors.Is(err, db.ErrTransferBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	if result.Review != nil {
+		// the amount is held until the review is decided
+		ctx.JSON(http.StatusAccepted, result)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, result)
 }
 
-func (server *Server) validateWallet(ctx *gin.Context, walletID int64, currency string) bool {
+// validateTransferWallets checks both wallets can be used in a transfer
+// sent by the authenticated user
+func (server *Server) validateTransferWallets(ctx *gin.Context, fromWalletID, toWalletID int64, currency string) bool {
+	fromWallet, valid := server.validateWallet(ctx, fromWalletID, currency)
+	if !valid {
+		return false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromWallet.Owner != payload.Username {
+		err := errors.New("from wallet doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+
+	_, valid = server.validateWallet(ctx, toWalletID, currency)
+	return valid
+}
+
+func (server *Server) validateWallet(ctx *gin.Context, walletID int64, currency string) (db.Wallet, bool) {
 	wallet, err := server.store.GetWallet(ctx, walletID)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return false
+			return wallet, false
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return false
+		return wallet, false
 	}
 
 	if wallet.Currency != currency {
 		err = fmt.Errorf("wallet [%d] currency mismarch: %s vs %s", walletID, wallet.Currency, currency)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return false
+		return wallet, false
+	}
+
+	if wallet.IsFrozen {
+		err = fmt.Errorf("wallet [%d] is frozen", walletID)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return wallet, false
+	}
+
+	return wallet, true
+}
+
+type transferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type createRefundRequest struct {
+	Amount int64  `json:"amount" binding:"required,gt=0"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (server *Server) createRefund(ctx *gin.Context) {
+	var uri transferURI
+	var req createRefundRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.RefundTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		CreatedBy:  payload.Username,
+	}
+
+	result, err := server.store.RefundTx(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if err == db.ErrRefundExceedsTransfer || err == db.ErrRefundDisputed {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) listRefunds(ctx *gin.Context) {
+	var uri transferURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.authorizeTransfer(ctx, transfer, permissionReadAny) {
+		return
+	}
+
+	refunds, err := server.store.ListRefunds(ctx, transfer.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, refunds)
+}
+
+// authorizeTransfer lets the owners of either side of the transfer through
+func (server *Server) authorizeTransfer(ctx *gin.Context, transfer db.Transfer, anyPermission string) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if authorized(payload, anyPermission) {
+		return true
+	}
+
+	for _, walletID := range []int64{transfer.FromWalletID, transfer.ToWalletID} {
+		wallet, err := server.store.GetWallet(ctx, walletID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return false
+		}
+
+		if wallet.Owner == payload.Username {
+			return true
+		}
 	}
 
-	return true
+	err := errors.New("transfer doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+	return false
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
 	"picpay_simplificado/util"
 	"time"
 
@@ -24,6 +25,10 @@ func (server *Server) getUser(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeOwner(ctx, req.Username, permissionReadAny) {
+		return
+	}
+
 	user, err := server.store.GetUser(ctx, req.Username)
 
 	if err != nil {
@@ -35,7 +40,7 @@ func (server *Server) getUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
 type createUserRequest struct {
@@ -43,14 +48,33 @@ type createUserRequest struct {
 	FullName string `json:"full_name" binding:"required"`
 	CpfCnpj  string `json:"cpf_cnpj" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required, min=6"`
+	Password string `json:"password" binding:"required,min=6"`
 }
 
-type createUserResponse struct {
-	Username string `json:"username"`
-	FullName string `json:"full_name"`
-	CpfCnpj  string `json:"cpf_cnpj"`
-	Email    string `json:"email"`
+type userResponse struct {
+	Username          string       `json:"username"`
+	FullName          string       `json:"full_name"`
+	CpfCnpj           string       `json:"cpf_cnpj"`
+	Email             string       `json:"email"`
+	Role              string       `json:"role"`
+	IsMerchant        sql.NullBool `json:"is_merchant"`
+	MerchantCategory  string       `json:"merchant_category"`
+	PasswordChangedAt time.Time    `json:"password_changed_at"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+func newUserResponse(user db.User) userResponse {
+	return userResponse{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		CpfCnpj:           user.CpfCnpj,
+		Email:             user.Email,
+		Role:              user.Role,
+		IsMerchant:        user.IsMerchant,
+		MerchantCategory:  user.MerchantCategory,
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreatedAt:         user.CreatedAt,
+	}
 }
 
 func (server *Server) createUser(ctx *gin.Context) {
@@ -68,6 +92,7 @@ func (server *Server) createUser(ctx *gin.Context) {
 	}
 
 	arg := db.CreateUserParams{
+		Username:       req.Username,
 		FullName:       req.FullName,
 		CpfCnpj:        req.CpfCnpj,
 		Email:          req.Email,
@@ -87,11 +112,64 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	rsp := createUserResponse{
-		Username: user.Username,
-		FullName: user.FullName,
-		CpfCnpj:  user.CpfCnpj,
-		Email:    user.Email,
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// errInvalidCredentials is the answer to both an unknown username and a wrong
+// password, so logins can't be used to find out which usernames exist
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// unknownUserPasswordHash is checked against when the username doesn't exist,
+// so it takes as long to answer as a wrong password
+const unknownUserPasswordHash = "$2a$10$a/2lbyLKnth5k3nyURYlPO9d86Rmjf3efUSLdoZe3FtGV1iZDoX2K"
+
+type loginUserRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type loginUserResponse struct {
+	AccessToken          string       `json:"access_token"`
+	AccessTokenExpiresAt time.Time    `json:"access_token_expires_at"`
+	User                 userResponse `json:"user"`
+}
+
+func (server *Server) loginUser(ctx *gin.Context) {
+	var req loginUserRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Username)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			util.CheckPassword(req.Password, unknownUserPasswordHash)
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = util.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		return
+	}
+
+	accessToken, payload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := loginUserResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: payload.ExpiredAt,
+		User:                 newUserResponse(user),
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
@@ -109,6 +187,10 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeOwner(ctx, req.Username, permissionWriteAny) {
+		return
+	}
+
 	_, err := server.store.GetUser(ctx, req.Username)
 
 	if err != nil && err == sql.ErrNoRows {
@@ -136,6 +218,7 @@ func (server *Server) listUsers(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
 	arg := db.ListUsersParams{
@@ -150,7 +233,12 @@ func (server *Server) listUsers(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, users)
+	rsp := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		rsp = append(rsp, newUserResponse(user))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type updateUserRequest struct {
@@ -176,6 +264,10 @@ func (server *Server) updateUser(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeOwner(ctx, req.Username, permissionWriteAny) {
+		return
+	}
+
 	user, err := server.store.GetUser(ctx, req.Username)
 
 	if err != nil {
@@ -229,5 +321,51 @@ func (server *Server) updateUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type updateUserRoleURI struct {
+	Username string `uri:"id" binding:"required"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,role"`
+}
+
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	var uri updateUserRoleURI
+	var req updateUserRoleRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Username == uri.Username {
+		err := errors.New("users cannot change their own role")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
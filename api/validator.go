@@ -14,3 +14,48 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 
 	return false
 }
+
+var validRole validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if role, ok := fieldLevel.Field().Interface().(string); ok {
+
+		return util.IsSupportedRole(role)
+	}
+
+	return false
+}
+
+var validEventType validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if eventType, ok := fieldLevel.Field().Interface().(string); ok {
+
+		return util.IsSupportedEventType(eventType)
+	}
+
+	return false
+}
+
+var validFrequency validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if frequency, ok := fieldLevel.Field().Interface().(string); ok {
+
+		return util.IsSupportedFrequency(frequency)
+	}
+
+	return false
+}
+
+var validFeeOperation validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if operation, ok := fieldLevel.Field().Interface().(string); ok {
+
+		return util.IsSupportedFeeOperation(operation)
+	}
+
+	return false
+}
+
+var validLimitName validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if name, ok := fieldLevel.Field().Interface().(string); ok {
+
+		return util.IsSupportedLimit(name)
+	}
+
+	return false
+}
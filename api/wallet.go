@@ -22,13 +22,16 @@ func (server *Server) createWallet(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreateWalletParams{
+	if !authorizeOwner(ctx, req.Owner, permissionWriteAny) {
+		return
+	}
+
+	arg := db.CreateWalletTxParams{
 		Owner:    req.Owner,
-		Balance:  0,
 		Currency: req.Currency,
 	}
 
-	wallet, err := server.store.CreateWallet(ctx, arg)
+	wallet, err := server.store.CreateWalletTx(ctx, arg)
 
 	if err != nil {
 		if pqError, ok := err.(*pq.Error); ok {
@@ -68,6 +71,10 @@ func (server *Server) getWallet(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeOwner(ctx, wallet.Owner, permissionReadAny) {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, wallet)
 }
 
@@ -81,6 +88,7 @@ func (server *Server) listWallets(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
 	arg := db.ListWalletsParams{
@@ -110,7 +118,7 @@ func (server *Server) deleteWallet(ctx *gin.Context) {
 		return
 	}
 
-	err := server.store.DeleteWallet(ctx, req.Id)
+	wallet, err := server.store.GetWallet(ctx, req.Id)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -121,14 +129,28 @@ func (server *Server) deleteWallet(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeOwner(ctx, wallet.Owner, permissionWriteAny) {
+		return
+	}
+
+	err = server.store.DeleteWallet(ctx, req.Id)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	ctx.JSON(http.StatusOK, successResponse("Wallet deleted"))
 }
 
 type updateWalletRequest struct {
-	ID      int64 `json:"id" binding:"required,min=1"`
-	Balance int64 `json:"balance" binding:"required"`
+	ID          int64  `json:"id" binding:"required,min=1"`
+	Balance     int64  `json:"balance" binding:"required"`
+	Description string `json:"description" binding:"max=140"`
 }
 
+// updateWallet corrects a wallet balance, the difference is posted against
+// the suspense account until it is explained
 func (server *Server) updateWallet(ctx *gin.Context) {
 	var req updateWalletRequest
 
@@ -137,12 +159,54 @@ func (server *Server) updateWallet(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.UpdateWalletParams{
-		ID:      req.ID,
-		Balance: req.Balance,
+	arg := db.AdjustWalletTxParams{
+		WalletID:    req.ID,
+		Amount:      req.Balance,
+		SetBalance:  true,
+		Account:     db.AccountSuspense,
+		Description: req.Description,
+	}
+
+	result, err := server.store.AdjustWalletTx(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Wallet)
+}
+
+type freezeWalletRequest struct {
+	Id int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) freezeWallet(ctx *gin.Context) {
+	server.setWalletFrozen(ctx, true)
+}
+
+func (server *Server) unfreezeWallet(ctx *gin.Context) {
+	server.setWalletFrozen(ctx, false)
+}
+
+func (server *Server) setWalletFrozen(ctx *gin.Context, frozen bool) {
+	var req freezeWalletRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.FreezeWalletTxParams{
+		WalletID: req.Id,
+		Frozen:   frozen,
 	}
 
-	wallet, err := server.store.UpdateWallet(ctx, arg)
+	wallet, err := server.store.FreezeWalletTx(ctx, arg)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -155,3 +219,19 @@ func (server *Server) updateWallet(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, wallet)
 }
+
+// authorizeWallet loads the wallet and checks the authenticated user may act on it
+func (server *Server) authorizeWallet(ctx *gin.Context, walletID int64, anyPermission string) bool {
+	wallet, err := server.store.GetWallet(ctx, walletID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	return authorizeOwner(ctx, wallet.Owner, anyPermission)
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/stream"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	walletEventEntry   = "entry"
+	walletEventBalance = "balance"
+
+	walletStreamBatchSize = 100
+	walletStreamHeartbeat = 15 * time.Second
+)
+
+type walletStreamRequest struct {
+	Id int64 `uri:"id" binding:"required,min=1"`
+}
+
+// walletStreamQuery lets clients that cannot set headers, like browser
+// WebSockets, resume with a query parameter instead of Last-Event-ID
+type walletStreamQuery struct {
+	LastEventID string `form:"last_event_id"`
+}
+
+type walletBalanceEvent struct {
+	WalletID int64  `json:"wallet_id"`
+	Balance  int64  `json:"balance"`
+	Currency string `json:"currency"`
+}
+
+// streamWalletEvents pushes new entries and balance changes of a wallet over
+// Server-Sent Events, or over a WebSocket when the client asks for an upgrade.
+// Entries use their id as event id, so a reconnecting client sends the last
+// one it saw and receives everything it missed
+func (server *Server) streamWalletEvents(ctx *gin.Context) {
+	var req walletStreamRequest
+	var query walletStreamQuery
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.LastEventID
+	}
+
+	var afterID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid last event id: %s", lastEventID)))
+			return
+		}
+		afterID = id
+	}
+
+	wallet, err := server.store.GetWallet(ctx, req.Id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !authorizeOwner(ctx, wallet.Owner, permissionReadAny) {
+		return
+	}
+
+	// subscribe before reading the current state so nothing committed in
+	// between is missed
+	changes, unsubscribe := server.broker.Subscribe(wallet.ID)
+	defer unsubscribe()
+
+	if lastEventID == "" {
+		afterID, err = server.store.GetLastEntryID(ctx, wallet.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	var writer stream.Writer
+	var closed <-chan struct{}
+
+	if stream.IsWebSocketUpgrade(ctx.Request) {
+		ws, err := stream.UpgradeWebSocket(ctx.Writer, ctx.Request, server.config.StreamAllowedOrigins)
+		if err != nil {
+			if errors.Is(err, stream.ErrOriginNotAllowed) {
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		defer func() {
+			if err := ws.Close(); err != nil {
+				log.Println("cannot close wallet websocket:", err)
+			}
+		}()
+
+		writer = ws
+		closed = ws.Closed()
+	} else {
+		sse, err := stream.NewSSEWriter(ctx.Writer)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		writer = sse
+		closed = ctx.Request.Context().Done()
+	}
+
+	walletStream := &walletStream{
+		store:    server.store,
+		writer:   writer,
+		walletID: wallet.ID,
+		afterID:  afterID,
+	}
+
+	heartbeat := time.NewTicker(walletStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	if err := walletStream.sync(ctx); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if err := writer.WriteHeartbeat(); err != nil {
+				return
+			}
+		case <-changes:
+			if err := walletStream.sync(ctx); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// walletStream tracks what a single client has already received
+type walletStream struct {
+	store       db.Store
+	writer      stream.Writer
+	walletID    int64
+	afterID     int64
+	balance     int64
+	balanceSent bool
+}
+
+// sync sends the entries created since the last one sent and the balance if it changed
+func (walletStream *walletStream) sync(ctx *gin.Context) error {
+	for {
+		entries, err := walletStream.store.ListEntriesAfter(ctx, db.ListEntriesAfterParams{
+			WalletID:  walletStream.walletID,
+			AfterID:   walletStream.afterID,
+			BatchSize: walletStreamBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			err := walletStream.writer.WriteEvent(stream.Event{
+				ID:   strconv.FormatInt(entry.ID, 10),
+				Type: walletEventEntry,
+				Data: entry,
+			})
+			if err != nil {
+				return err
+			}
+			walletStream.afterID = entry.ID
+		}
+
+		if len(entries) < walletStreamBatchSize {
+			break
+		}
+	}
+
+	wallet, err := walletStream.store.GetWallet(ctx, walletStream.walletID)
+	if err != nil {
+		return err
+	}
+
+	if walletStream.balanceSent && wallet.Balance == walletStream.balance {
+		return nil
+	}
+
+	err = walletStream.writer.WriteEvent(stream.Event{
+		Type: walletEventBalance,
+		Data: walletBalanceEvent{
+			WalletID: wallet.ID,
+			Balance:  wallet.Balance,
+			Currency: wallet.Currency,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	walletStream.balance = wallet.Balance
+	walletStream.balanceSent = true
+	return nil
+}
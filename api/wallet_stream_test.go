@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamWalletEventsAPI(t *testing.T) {
+	wallet := randomWallet()
+	entries := []db.Entry{
+		{ID: 6, WalletID: wallet.ID, Amount: util.RandomMoney()},
+		{ID: 9, WalletID: wallet.ID, Amount: -util.RandomMoney()},
+	}
+
+	testCases := []struct {
+		name          string
+		setupRequest  func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ResumeFromLastEventID",
+			setupRequest: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				authAs(wallet.Owner, util.CustomerRole)(t, request, tokenMaker)
+				request.Header.Set("Last-Event-ID", "5")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(wallet.ID)).Times(2).Return(wallet, nil)
+				store.EXPECT().GetLastEntryID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					ListEntriesAfter(gomock.Any(), gomock.Eq(db.ListEntriesAfterParams{
+						WalletID:  wallet.ID,
+						AfterID:   5,
+						BatchSize: walletStreamBatchSize,
+					})).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+
+				body := recorder.Body.String()
+				for _, entry := range entries {
+					require.Contains(t, body, fmt.Sprintf("id: %d\nevent: entry\n", entry.ID))
+				}
+				require.Contains(t, body, fmt.Sprintf("event: balance\ndata: {\"wallet_id\":%d,\"balance\":%d", wallet.ID, wallet.Balance))
+			},
+		},
+		{
+			name: "StartFromLatestEntry",
+			setupRequest: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				authAs(wallet.Owner, util.CustomerRole)(t, request, tokenMaker)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(wallet.ID)).Times(2).Return(wallet, nil)
+				store.EXPECT().GetLastEntryID(gomock.Any(), gomock.Eq(wallet.ID)).Times(1).Return(int64(9), nil)
+				store.EXPECT().
+					ListEntriesAfter(gomock.Any(), gomock.Eq(db.ListEntriesAfterParams{
+						WalletID:  wallet.ID,
+						AfterID:   9,
+						BatchSize: walletStreamBatchSize,
+					})).
+					Times(1).
+					Return([]db.Entry{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "event: entry")
+				require.Contains(t, recorder.Body.String(), "event: balance")
+			},
+		},
+		{
+			name: "InvalidLastEventID",
+			setupRequest: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				authAs(wallet.Owner, util.CustomerRole)(t, request, tokenMaker)
+				request.Header.Set("Last-Event-ID", "abc")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			setupRequest: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				authAs(util.RandomString(6), util.CustomerRole)(t, request, tokenMaker)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWallet(gomock.Any(), gomock.Eq(wallet.ID)).Times(1).Return(wallet, nil)
+				store.EXPECT().ListEntriesAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			// the stream only ends when the client goes away
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			url := fmt.Sprintf("/wallets/%d/events", wallet.ID)
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupRequest(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
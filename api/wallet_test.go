@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,9 +11,12 @@ import (
 	"net/http/httptest"
 	mockdb "picpay_simplificado/db/mock"
 	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
 	"picpay_simplificado/util"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -23,12 +27,14 @@ func TestGetWalletAPI(t *testing.T) {
 	testCases := []struct {
 		name          string
 		walletID      int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name:     "OK",
-			walletID: wallet.ID,
+			name:      "OK",
+			walletID:  wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetWallet(gomock.Any(), wallet.ID).
@@ -41,8 +47,51 @@ func TestGetWalletAPI(t *testing.T) {
 			},
 		},
 		{
-			name:     "NotFound",
-			walletID: wallet.ID,
+			name:      "SupportCanRead",
+			walletID:  wallet.ID,
+			setupAuth: authAs(util.RandomString(6), util.SupportRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), wallet.ID).
+					Times(1).
+					Return(wallet, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchWallet(t, recorder.Body, wallet)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			walletID:  wallet.ID,
+			setupAuth: authAs(util.RandomString(6), util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), wallet.ID).
+					Times(1).
+					Return(wallet, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			walletID:  wallet.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			walletID:  wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetWallet(gomock.Any(), wallet.ID).
@@ -54,8 +103,9 @@ func TestGetWalletAPI(t *testing.T) {
 			},
 		},
 		{
-			name:     "InternalError",
-			walletID: wallet.ID,
+			name:      "InternalError",
+			walletID:  wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetWallet(gomock.Any(), wallet.ID).
@@ -67,8 +117,9 @@ func TestGetWalletAPI(t *testing.T) {
 			},
 		},
 		{
-			name:     "InvalidID",
-			walletID: 0,
+			name:      "InvalidID",
+			walletID:  0,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetWallet(gomock.Any(), gomock.Any()).
@@ -90,7 +141,7 @@ func TestGetWalletAPI(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/wallets/%d", tc.walletID)
@@ -98,6 +149,8 @@ func TestGetWalletAPI(t *testing.T) {
 
 			require.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
+
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(t, recorder)
 		})
@@ -110,13 +163,19 @@ func TestDeleteWalletAPI(t *testing.T) {
 	testCases := []struct {
 		name          string
 		Owner         int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name:  "OK",
-			Owner: wallet.ID,
+			name:      "OK",
+			Owner:     wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), wallet.ID).
+					Times(1).
+					Return(wallet, nil)
 				store.EXPECT().
 					DeleteWallet(gomock.Any(), wallet.ID).
 					Times(1)
@@ -126,22 +185,48 @@ func TestDeleteWalletAPI(t *testing.T) {
 			},
 		},
 		{
-			name:  "NotFound",
-			Owner: wallet.ID,
+			name:      "UnauthorizedUser",
+			Owner:     wallet.ID,
+			setupAuth: authAs(util.RandomString(6), util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteWallet(gomock.Any(), wallet.ID).
+					GetWallet(gomock.Any(), wallet.ID).
 					Times(1).
-					Return(sql.ErrNoRows)
+					Return(wallet, nil)
+				store.EXPECT().
+					DeleteWallet(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			Owner:     wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), wallet.ID).
+					Times(1).
+					Return(db.Wallet{}, sql.ErrNoRows)
+				store.EXPECT().
+					DeleteWallet(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
 		{
-			name:  "InternalError",
-			Owner: wallet.ID,
+			name:      "InternalError",
+			Owner:     wallet.ID,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWallet(gomock.Any(), wallet.ID).
+					Times(1).
+					Return(wallet, nil)
 				store.EXPECT().
 					DeleteWallet(gomock.Any(), wallet.ID).
 					Times(1).
@@ -152,8 +237,9 @@ func TestDeleteWalletAPI(t *testing.T) {
 			},
 		},
 		{
-			name:  "InvalidID",
-			Owner: 0,
+			name:      "InvalidID",
+			Owner:     0,
+			setupAuth: authAs(wallet.Owner, util.CustomerRole),
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					DeleteWallet(gomock.Any(), gomock.Any()).
@@ -175,7 +261,7 @@ func TestDeleteWalletAPI(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/wallets/%d", tc.Owner)
@@ -183,12 +269,47 @@ func TestDeleteWalletAPI(t *testing.T) {
 
 			require.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(t, recorder)
 		})
 	}
 }
 
+func TestUpdateWalletAPI(t *testing.T) {
+	wallet := randomWallet()
+	admin := util.RandomString(6)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		AdjustWalletTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.AdjustWalletTxParams) (db.AdjustWalletTxResult, error) {
+			require.Equal(t, wallet.ID, arg.WalletID)
+			require.True(t, arg.SetBalance)
+			require.Equal(t, db.AccountSuspense, arg.Account)
+			wallet.Balance = arg.Amount
+			return db.AdjustWalletTxResult{Wallet: wallet}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"id": wallet.ID, "balance": 1234})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPut, "/wallets", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	authAs(admin, util.AdminRole)(t, request, server.tokenMaker)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchWallet(t, recorder.Body, wallet)
+}
+
 func randomWallet() db.Wallet {
 	return db.Wallet{
 		ID:       util.RandomInt(1, 100),
@@ -198,6 +319,12 @@ func randomWallet() db.Wallet {
 	}
 }
 
+func authAs(username string, role string) func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+	return func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+	}
+}
+
 func requireBodyMatchWallet(t *testing.T, body *bytes.Buffer, wallet db.Wallet) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
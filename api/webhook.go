@@ -0,0 +1,265 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/token"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+type webhookEndpointResponse struct {
+	ID                  int64        `json:"id"`
+	Owner               string       `json:"owner"`
+	Url                 string       `json:"url"`
+	EventTypes          []string     `json:"event_types"`
+	Enabled             bool         `json:"enabled"`
+	ConsecutiveFailures int32        `json:"consecutive_failures"`
+	DisabledAt          sql.NullTime `json:"disabled_at"`
+	CreatedAt           time.Time    `json:"created_at"`
+}
+
+func newWebhookEndpointResponse(endpoint db.WebhookEndpoint) webhookEndpointResponse {
+	return webhookEndpointResponse{
+		ID:                  endpoint.ID,
+		Owner:               endpoint.Owner,
+		Url:                 endpoint.Url,
+		EventTypes:          endpoint.EventTypes,
+		Enabled:             endpoint.Enabled,
+		ConsecutiveFailures: endpoint.ConsecutiveFailures,
+		DisabledAt:          endpoint.DisabledAt,
+		CreatedAt:           endpoint.CreatedAt,
+	}
+}
+
+// createWebhookEndpointResponse is the only time the signing secret is returned
+type createWebhookEndpointResponse struct {
+	Secret   string                  `json:"secret"`
+	Endpoint webhookEndpointResponse `json:"endpoint"`
+}
+
+type createWebhookEndpointRequest struct {
+	Url        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,event_type"`
+}
+
+func (server *Server) createWebhookEndpoint(ctx *gin.Context) {
+	var req createWebhookEndpointRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateWebhookEndpointParams{
+		Owner:      payload.Username,
+		Url:        req.Url,
+		Secret:     webhookSecretPrefix + hex.EncodeToString(secret),
+		EventTypes: req.EventTypes,
+	}
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createWebhookEndpointResponse{
+		Secret:   endpoint.Secret,
+		Endpoint: newWebhookEndpointResponse(endpoint),
+	})
+}
+
+func (server *Server) listWebhookEndpoints(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, payload.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]webhookEndpointResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		rsp = append(rsp, newWebhookEndpointResponse(endpoint))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteWebhookEndpoint(ctx *gin.Context) {
+	endpoint, ok := server.ownedWebhookEndpoint(ctx)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeleteWebhookEndpoint(ctx, endpoint.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("Webhook deleted"))
+}
+
+func (server *Server) enableWebhookEndpoint(ctx *gin.Context) {
+	endpoint, ok := server.ownedWebhookEndpoint(ctx)
+	if !ok {
+		return
+	}
+
+	endpoint, err := server.store.EnableWebhookEndpoint(ctx, endpoint.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookEndpointResponse(endpoint))
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var req listWebhookDeliveriesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endpoint, ok := server.ownedWebhookEndpoint(ctx)
+	if !ok {
+		return
+	}
+
+	arg := db.ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      req.PageSize,
+		Offset:     (req.PageID - 1) * req.PageSize,
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (server *Server) listWebhookDeliveryAttempts(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	attempts, err := server.store.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attempts)
+}
+
+// redeliverWebhook queues the delivery to be sent again as soon as possible
+func (server *Server) redeliverWebhook(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := server.store.RedeliverWebhookDelivery(ctx, delivery.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// ownedWebhookEndpoint loads the endpoint in the uri and checks it belongs to the authenticated user
+func (server *Server) ownedWebhookEndpoint(ctx *gin.Context) (db.WebhookEndpoint, bool) {
+	var uri webhookURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookEndpoint{}, false
+	}
+
+	return server.getOwnedWebhookEndpoint(ctx, uri.ID)
+}
+
+func (server *Server) getOwnedWebhookEndpoint(ctx *gin.Context, endpointID int64) (db.WebhookEndpoint, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, endpointID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return endpoint, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return endpoint, false
+	}
+
+	if !authorizeOwner(ctx, endpoint.Owner, permissionWriteAny) {
+		return endpoint, false
+	}
+
+	return endpoint, true
+}
+
+// ownedWebhookDelivery loads the delivery in the uri and checks its endpoint belongs to the authenticated user
+func (server *Server) ownedWebhookDelivery(ctx *gin.Context) (db.WebhookDelivery, bool) {
+	var uri webhookURI
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookDelivery{}, false
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, uri.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return delivery, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return delivery, false
+	}
+
+	if _, ok := server.getOwnedWebhookEndpoint(ctx, delivery.EndpointID); !ok {
+		return delivery, false
+	}
+
+	return delivery, true
+}
@@ -0,0 +1,46 @@
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when opening a key that was never stored
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for keys that could escape the store
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps opaque content under slash separated keys
+type Store interface {
+	// Put stores the content under key, replacing what was there, and
+	// returns how many bytes were written
+	Put(ctx context.Context, key string, content io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewKey returns a random key under prefix
+func NewKey(prefix string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return path.Join(prefix, hex.EncodeToString(buf)), nil
+}
+
+// validKey rejects empty, absolute and parent relative keys
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+
+	return path.Clean(key) == key && key != "." && !strings.HasPrefix(key, "../") && key != ".."
+}
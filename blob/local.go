@@ -0,0 +1,92 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps blobs as files under a directory. Each instance only
+// sees its own disk, so every instance must share the directory
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a new LocalStore, creating the directory if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put writes the content to a temporary file first, so a failed write never
+// leaves a partial blob behind
+func (store *LocalStore) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	name, err := store.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		file.Close()
+		return written, err
+	}
+
+	if err := file.Close(); err != nil {
+		return written, err
+	}
+
+	return written, os.Rename(file.Name(), name)
+}
+
+func (store *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (store *LocalStore) Delete(ctx context.Context, key string) error {
+	name, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (store *LocalStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(store.dir, filepath.FromSlash(key)), nil
+}
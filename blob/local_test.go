@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	key, err := NewKey("disputes/1")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, "disputes/1/"))
+
+	written, err := store.Put(context.Background(), key, strings.NewReader("receipt"))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), written)
+
+	reader, err := store.Open(context.Background(), key)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "receipt", string(content))
+
+	require.NoError(t, store.Delete(context.Background(), key))
+
+	_, err = store.Open(context.Background(), key)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, store.Delete(context.Background(), key), ErrNotFound)
+}
+
+func TestLocalStoreInvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", ".", "..", "../escape", "a/../../escape", "/etc/passwd", "a//b", `a\b`} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("x"))
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
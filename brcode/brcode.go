@@ -0,0 +1,283 @@
+// Package brcode encodes and decodes BR Codes, the Pix payloads that follow
+// the EMV QR Code specification for merchant presented payments
+package brcode
+
+import (
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	idPayloadFormat       = "00"
+	idPointOfInitiation   = "01"
+	idMerchantAccount     = "26"
+	idMerchantCategory    = "52"
+	idTransactionCurrency = "53"
+	idTransactionAmount   = "54"
+	idCountryCode         = "58"
+	idMerchantName        = "59"
+	idMerchantCity        = "60"
+	idAdditionalData      = "62"
+	idCRC                 = "63"
+
+	idGUI         = "00"
+	idKey         = "01"
+	idDescription = "02"
+	idURL         = "25"
+
+	idTxID = "05"
+
+	// the merchant account templates other arrangements may use
+	firstMerchantAccount = 26
+	lastMerchantAccount  = 51
+)
+
+const (
+	// GUI identifies the Pix arrangement in the merchant account template
+	GUI = "br.gov.bcb.pix"
+
+	// Static payloads can be paid many times, with any amount when it's missing
+	Static = "11"
+	// Dynamic payloads point to a location with the details of a single charge
+	Dynamic = "12"
+
+	// NoTxID is the transaction ID of payloads not tied to a charge
+	NoTxID = "***"
+
+	payloadFormat = "01"
+	maxNameLen    = 25
+	maxCityLen    = 15
+	maxTxIDLen    = 25
+)
+
+var (
+	// ErrInvalidCRC is returned when the checksum doesn't match the payload
+	ErrInvalidCRC = errors.New("invalid payload CRC")
+	// ErrNotPix is returned when no merchant account carries the Pix GUI
+	ErrNotPix = errors.New("payload is not a Pix BR Code")
+)
+
+// Payload is the information carried by a BR Code
+type Payload struct {
+	PointOfInitiation string `json:"point_of_initiation"`
+	Key               string `json:"key,omitempty"`
+	Description       string `json:"description,omitempty"`
+	URL               string `json:"url,omitempty"`
+	MerchantCategory  string `json:"merchant_category"`
+	// Currency is the ISO 4217 numeric code
+	Currency     string `json:"currency"`
+	Amount       int64  `json:"amount,omitempty"`
+	CountryCode  string `json:"country_code"`
+	MerchantName string `json:"merchant_name"`
+	MerchantCity string `json:"merchant_city"`
+	TxID         string `json:"txid"`
+}
+
+// IsDynamic reports whether the payload points to a charge location
+func (payload Payload) IsDynamic() bool {
+	return payload.PointOfInitiation == Dynamic || payload.URL != ""
+}
+
+// Encode returns the payload as a BR Code string, ending with its CRC.
+// Names and cities are folded to ASCII and cut to the sizes readers accept
+func Encode(payload Payload) (string, error) {
+	if payload.Key == "" && payload.URL == "" {
+		return "", errors.New("payload needs a key or a URL")
+	}
+	if payload.Amount < 0 {
+		return "", errors.New("amount must not be negative")
+	}
+
+	var account strings.Builder
+	writeField(&account, idGUI, GUI)
+	if payload.Key != "" {
+		writeField(&account, idKey, payload.Key)
+	}
+	if payload.Description != "" {
+		writeField(&account, idDescription, payload.Description)
+	}
+	if payload.URL != "" {
+		writeField(&account, idURL, payload.URL)
+	}
+
+	txID := payload.TxID
+	if txID == "" {
+		txID = NoTxID
+	}
+	if len(txID) > maxTxIDLen {
+		return "", fmt.Errorf("txid longer than %d characters", maxTxIDLen)
+	}
+
+	var additional strings.Builder
+	writeField(&additional, idTxID, txID)
+
+	var sb strings.Builder
+	writeField(&sb, idPayloadFormat, payloadFormat)
+	if payload.PointOfInitiation != "" {
+		writeField(&sb, idPointOfInitiation, payload.PointOfInitiation)
+	}
+	if account.Len() > 99 {
+		return "", errors.New("merchant account information too long")
+	}
+	writeField(&sb, idMerchantAccount, account.String())
+	writeField(&sb, idMerchantCategory, defaultString(payload.MerchantCategory, "0000"))
+	writeField(&sb, idTransactionCurrency, payload.Currency)
+	if payload.Amount > 0 {
+		writeField(&sb, idTransactionAmount, util.FormatAmount(payload.Amount))
+	}
+	writeField(&sb, idCountryCode, defaultString(payload.CountryCode, "BR"))
+	writeField(&sb, idMerchantName, fold(payload.MerchantName, maxNameLen))
+	writeField(&sb, idMerchantCity, fold(payload.MerchantCity, maxCityLen))
+	writeField(&sb, idAdditionalData, additional.String())
+
+	sb.WriteString(idCRC + "04")
+	fmt.Fprintf(&sb, "%04X", CRC16([]byte(sb.String())))
+
+	return sb.String(), nil
+}
+
+// Decode checks the CRC of a BR Code and returns its payload
+func Decode(code string) (Payload, error) {
+	var payload Payload
+
+	code = strings.TrimSpace(code)
+	if len(code) < 8 || code[len(code)-8:len(code)-4] != idCRC+"04" {
+		return payload, fmt.Errorf("%w: missing CRC", errMalformed)
+	}
+
+	crc, err := strconv.ParseUint(code[len(code)-4:], 16, 16)
+	if err != nil {
+		return payload, fmt.Errorf("%w: missing CRC", errMalformed)
+	}
+	if uint16(crc) != CRC16([]byte(code[:len(code)-4])) {
+		return payload, ErrInvalidCRC
+	}
+
+	fields, err := parseFields(code[:len(code)-8])
+	if err != nil {
+		return payload, err
+	}
+
+	pix := false
+	for _, f := range fields {
+		switch f.id {
+		case idPayloadFormat:
+			if f.value != payloadFormat {
+				return payload, fmt.Errorf("%w: unsupported payload format %s", errMalformed, f.value)
+			}
+		case idPointOfInitiation:
+			payload.PointOfInitiation = f.value
+		case idMerchantCategory:
+			payload.MerchantCategory = f.value
+		case idTransactionCurrency:
+			payload.Currency = f.value
+		case idTransactionAmount:
+			payload.Amount, err = ParseAmount(f.value)
+			if err != nil {
+				return payload, err
+			}
+		case idCountryCode:
+			payload.CountryCode = f.value
+		case idMerchantName:
+			payload.MerchantName = f.value
+		case idMerchantCity:
+			payload.MerchantCity = f.value
+		case idAdditionalData:
+			additional, err := parseFields(f.value)
+			if err != nil {
+				return payload, err
+			}
+			for _, a := range additional {
+				if a.id == idTxID {
+					payload.TxID = a.value
+				}
+			}
+		default:
+			id, _ := strconv.Atoi(f.id)
+			if id < firstMerchantAccount || id > lastMerchantAccount || pix {
+				continue
+			}
+
+			account, err := parseFields(f.value)
+			if err != nil {
+				return payload, err
+			}
+			if len(account) == 0 || account[0].id != idGUI || !strings.EqualFold(account[0].value, GUI) {
+				continue
+			}
+
+			pix = true
+			for _, a := range account[1:] {
+				switch a.id {
+				case idKey:
+					payload.Key = a.value
+				case idDescription:
+					payload.Description = a.value
+				case idURL:
+					payload.URL = a.value
+				}
+			}
+		}
+	}
+
+	if !pix {
+		return payload, ErrNotPix
+	}
+	if payload.Key == "" && payload.URL == "" {
+		return payload, fmt.Errorf("%w: missing key", errMalformed)
+	}
+	if payload.Currency == "" || payload.MerchantName == "" || payload.MerchantCity == "" {
+		return payload, fmt.Errorf("%w: missing merchant information", errMalformed)
+	}
+
+	return payload, nil
+}
+
+// ParseAmount parses field 54 into cents
+func ParseAmount(value string) (int64, error) {
+	units, cents, found := strings.Cut(value, ".")
+	if len(cents) > 2 || (found && cents == "") {
+		return 0, fmt.Errorf("%w: invalid amount %s", errMalformed, value)
+	}
+	cents += strings.Repeat("0", 2-len(cents))
+
+	amount, err := strconv.ParseInt(units+cents, 10, 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("%w: invalid amount %s", errMalformed, value)
+	}
+
+	return amount, nil
+}
+
+// fold removes the accents and any character outside printable ASCII,
+// cutting the result to size
+func fold(s string, size int) string {
+	var sb strings.Builder
+
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) || r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	result := strings.TrimSpace(sb.String())
+	if len(result) > size {
+		result = strings.TrimSpace(result[:size])
+	}
+
+	return result
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
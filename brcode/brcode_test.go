@@ -0,0 +1,140 @@
+package brcode
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const example = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	require.Equal(t, uint16(0x29B1), CRC16([]byte("123456789")))
+}
+
+func TestDecode(t *testing.T) {
+	payload, err := Decode(example)
+	require.NoError(t, err)
+
+	require.Equal(t, "123e4567-e12b-12d1-a456-426655440000", payload.Key)
+	require.Equal(t, "0000", payload.MerchantCategory)
+	require.Equal(t, "986", payload.Currency)
+	require.Zero(t, payload.Amount)
+	require.Equal(t, "BR", payload.CountryCode)
+	require.Equal(t, "Fulano de Tal", payload.MerchantName)
+	require.Equal(t, "BRASILIA", payload.MerchantCity)
+	require.Equal(t, NoTxID, payload.TxID)
+	require.False(t, payload.IsDynamic())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		code string
+		err  error
+	}{
+		{
+			name: "WrongCRC",
+			code: example[:len(example)-4] + "1D3E",
+			err:  ErrInvalidCRC,
+		},
+		{
+			name: "Tampered",
+			code: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tol6008BRASILIA62070503***63041D3D",
+			err:  ErrInvalidCRC,
+		},
+		{
+			name: "MissingCRC",
+			code: example[:len(example)-8],
+			err:  errMalformed,
+		},
+		{
+			name: "Truncated",
+			code: withCRC("000201265800"),
+			err:  errMalformed,
+		},
+		{
+			name: "NotPix",
+			code: withCRC("0002012625001312345678901230104abcd5204000053039865802BR5904ACME6005Natal62070503***"),
+			err:  ErrNotPix,
+		},
+		{
+			name: "MissingMerchant",
+			code: withCRC("00020126180014br.gov.bcb.pix5204000053039865802BR"),
+			err:  errMalformed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.code)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	code, err := Encode(Payload{
+		Key:          "123e4567-e12b-12d1-a456-426655440000",
+		Currency:     "986",
+		MerchantName: "Fulano de Tal",
+		MerchantCity: "BRASILIA",
+	})
+	require.NoError(t, err)
+	require.Equal(t, example, code)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	payload := Payload{
+		PointOfInitiation: Dynamic,
+		URL:               "pix.example.com/qr/v2/42",
+		Description:       "Pedido 42",
+		MerchantCategory:  "5812",
+		Currency:          "986",
+		Amount:            12345,
+		CountryCode:       "BR",
+		MerchantName:      "Padaria São João da Esquina do Bairro",
+		MerchantCity:      "São José dos Campos",
+		TxID:              "PEDIDO42",
+	}
+
+	code, err := Encode(payload)
+	require.NoError(t, err)
+
+	decoded, err := Decode(code)
+	require.NoError(t, err)
+	require.True(t, decoded.IsDynamic())
+	require.Equal(t, payload.URL, decoded.URL)
+	require.Equal(t, payload.Description, decoded.Description)
+	require.Equal(t, payload.Amount, decoded.Amount)
+	require.Equal(t, payload.TxID, decoded.TxID)
+	require.Equal(t, "Padaria Sao Joao da Esqui", decoded.MerchantName)
+	require.Equal(t, "Sao Jose dos Ca", decoded.MerchantCity)
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	_, err := Encode(Payload{Currency: "986", MerchantName: "A", MerchantCity: "B"})
+	require.Error(t, err)
+
+	_, err = Encode(Payload{Key: "a", TxID: "12345678901234567890123456"})
+	require.Error(t, err)
+}
+
+func TestAmount(t *testing.T) {
+	for value, amount := range map[string]int64{"1": 100, "1.5": 150, "1.50": 150, "0.01": 1, "10.99": 1099} {
+		parsed, err := ParseAmount(value)
+		require.NoError(t, err)
+		require.Equal(t, amount, parsed)
+	}
+
+	for _, value := range []string{"", "1.", "1.234", "-1.00", "0.00", "a.bc"} {
+		_, err := ParseAmount(value)
+		require.Error(t, err, value)
+	}
+}
+
+func withCRC(data string) string {
+	data += "6304"
+	return data + fmt.Sprintf("%04X", CRC16([]byte(data)))
+}
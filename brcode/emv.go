@@ -0,0 +1,42 @@
+package brcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// field is a data object of the EMV format: a two digit ID, a two digit
+// length and the value
+type field struct {
+	id    string
+	value string
+}
+
+func writeField(sb *strings.Builder, id string, value string) {
+	fmt.Fprintf(sb, "%s%02d%s", id, len(value), value)
+}
+
+var errMalformed = errors.New("malformed payload")
+
+// parseFields splits a string in its data objects
+func parseFields(data string) ([]field, error) {
+	var fields []field
+
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errMalformed
+		}
+
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || length > len(data)-4 {
+			return nil, fmt.Errorf("%w: invalid length for field %s", errMalformed, data[:2])
+		}
+
+		fields = append(fields, field{id: data[:2], value: data[4 : 4+length]})
+		data = data[4+length:]
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE "wallets" DROP COLUMN "is_frozen";
+
+ALTER TABLE "users" DROP COLUMN "role";
@@ -0,0 +1,25 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "wallets" ADD COLUMN "is_frozen" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "refunds" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "refund_transfer_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "refunds" ("transfer_id");
+
+COMMENT ON COLUMN "users"."role" IS 'customer, merchant, support or admin';
+
+COMMENT ON COLUMN "refunds"."amount" IS 'must be positive';
+
+ALTER TABLE "refunds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "refunds" ADD FOREIGN KEY ("refund_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "refunds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
@@ -0,0 +1,26 @@
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "prefix" varchar UNIQUE NOT NULL,
+  "hashed_key" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_used_at" timestamptz,
+  "revoked_at" timestamptz
+);
+
+CREATE TABLE "api_key_nonces" (
+  "api_key_id" bigint NOT NULL,
+  "nonce" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("api_key_id", "nonce")
+);
+
+CREATE INDEX ON "api_keys" ("owner");
+
+COMMENT ON COLUMN "api_keys"."hashed_key" IS 'hex encoded sha256 of the key, also used as the HMAC signing secret';
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "api_key_nonces" ADD FOREIGN KEY ("api_key_id") REFERENCES "api_keys" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS "wallets_notify_wallet_event" ON "wallets";
+DROP TRIGGER IF EXISTS "entries_notify_wallet_event" ON "entries";
+DROP FUNCTION IF EXISTS "notify_wallet_event";
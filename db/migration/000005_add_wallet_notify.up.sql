@@ -0,0 +1,20 @@
+CREATE FUNCTION "notify_wallet_event"() RETURNS trigger AS $$
+BEGIN
+  IF TG_TABLE_NAME = 'entries' THEN
+    PERFORM pg_notify('wallet_events', NEW."wallet_id"::text);
+  ELSE
+    PERFORM pg_notify('wallet_events', NEW."id"::text);
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entries_notify_wallet_event"
+AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION "notify_wallet_event"();
+
+CREATE TRIGGER "wallets_notify_wallet_event"
+AFTER UPDATE OF "balance" ON "wallets"
+FOR EACH ROW
+WHEN (OLD."balance" IS DISTINCT FROM NEW."balance")
+EXECUTE FUNCTION "notify_wallet_event"();
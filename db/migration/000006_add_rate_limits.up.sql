@@ -0,0 +1,12 @@
+CREATE UNLOGGED TABLE "rate_limit_buckets" (
+  "key" varchar PRIMARY KEY,
+  "tokens" double precision NOT NULL,
+  "allowed" boolean NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "rate_limit_buckets" ("updated_at");
+
+COMMENT ON COLUMN "rate_limit_buckets"."tokens" IS 'tokens left after the last request';
+
+COMMENT ON COLUMN "rate_limit_buckets"."allowed" IS 'whether the last request was allowed';
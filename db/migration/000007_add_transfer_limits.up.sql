@@ -0,0 +1,39 @@
+CREATE TABLE "transfer_limits" (
+  "owner" varchar PRIMARY KEY,
+  "per_transfer" bigint NOT NULL,
+  "daily" bigint NOT NULL,
+  "monthly" bigint NOT NULL,
+  "daily_count" bigint NOT NULL,
+  "night_per_transfer" bigint NOT NULL,
+  "nightly" bigint NOT NULL,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "limit_increase_requests" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "limit_name" varchar NOT NULL,
+  "requested_value" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "effective_at" timestamptz NOT NULL,
+  "decided_by" varchar NOT NULL DEFAULT '',
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "limit_increase_requests" ("owner");
+
+CREATE INDEX ON "limit_increase_requests" ("status", "effective_at");
+
+CREATE INDEX ON "transfers" ("from_wallet_id", "created_at");
+
+COMMENT ON COLUMN "transfer_limits"."per_transfer" IS 'overrides the default limits, zero means unlimited';
+
+COMMENT ON COLUMN "limit_increase_requests"."status" IS 'pending, applied, rejected or cancelled';
+
+COMMENT ON COLUMN "limit_increase_requests"."effective_at" IS 'end of the cooling off period';
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "limit_increase_requests" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
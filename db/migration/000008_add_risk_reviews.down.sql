@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS transfers_to_wallet_id_from_wallet_id_idx;
+DROP TABLE IF EXISTS transfer_reviews;
+DROP TABLE IF EXISTS risk_decisions;
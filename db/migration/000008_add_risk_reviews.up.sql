@@ -0,0 +1,56 @@
+CREATE TABLE "risk_decisions" (
+  "id" bigserial PRIMARY KEY,
+  "from_wallet_id" bigint NOT NULL,
+  "to_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "outcome" varchar NOT NULL,
+  "score" int NOT NULL,
+  "rules" varchar[] NOT NULL,
+  "rules_version" varchar NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "decision_id" bigint NOT NULL,
+  "from_wallet_id" bigint NOT NULL,
+  "to_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "reviewed_by" varchar NOT NULL DEFAULT '',
+  "note" varchar NOT NULL DEFAULT '',
+  "reviewed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "risk_decisions" ("from_wallet_id");
+
+CREATE INDEX ON "transfer_reviews" ("status");
+
+CREATE UNIQUE INDEX ON "transfer_reviews" ("decision_id");
+
+CREATE INDEX ON "transfers" ("to_wallet_id", "from_wallet_id");
+
+COMMENT ON COLUMN "risk_decisions"."outcome" IS 'allow, review or block';
+
+COMMENT ON COLUMN "risk_decisions"."rules" IS 'names of the rules that matched';
+
+COMMENT ON COLUMN "transfer_reviews"."status" IS 'pending, approved or rejected';
+
+COMMENT ON COLUMN "transfer_reviews"."amount" IS 'held from the sender until the review is decided';
+
+ALTER TABLE "risk_decisions" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "risk_decisions" ADD FOREIGN KEY ("to_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "risk_decisions" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("decision_id") REFERENCES "risk_decisions" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("to_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
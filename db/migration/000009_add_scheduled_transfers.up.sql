@@ -0,0 +1,70 @@
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_wallet_id" bigint NOT NULL,
+  "to_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "frequency" varchar NOT NULL,
+  "day_of_month" int NOT NULL DEFAULT 0,
+  "next_run_at" timestamptz NOT NULL,
+  "retry_at" timestamptz,
+  "end_at" timestamptz,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "attempt" int NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "message" varchar NOT NULL,
+  "data" jsonb NOT NULL,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for", "attempt");
+
+CREATE INDEX ON "notifications" ("owner", "id");
+
+COMMENT ON COLUMN "scheduled_transfers"."frequency" IS 'once, weekly or monthly';
+
+COMMENT ON COLUMN "scheduled_transfers"."day_of_month" IS 'monthly runs fall on the last day of shorter months';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'next occurrence, kept while it is retried';
+
+COMMENT ON COLUMN "scheduled_transfers"."retry_at" IS 'when the failed occurrence is tried again';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, completed, failed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded, review or failed';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
@@ -0,0 +1,2 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "payment_request_id";
+DROP TABLE IF EXISTS payment_requests;
@@ -0,0 +1,49 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "to_wallet_id" bigint NOT NULL,
+  "payer" varchar,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "expires_at" timestamptz NOT NULL,
+  "paid_by" varchar,
+  "from_wallet_id" bigint,
+  "transfer_id" bigint,
+  "review_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfers" ADD COLUMN "payment_request_id" bigint;
+
+CREATE INDEX ON "payment_requests" ("requester");
+
+CREATE INDEX ON "payment_requests" ("payer");
+
+CREATE UNIQUE INDEX ON "payment_requests" ("transfer_id");
+
+CREATE UNIQUE INDEX ON "transfers" ("payment_request_id");
+
+COMMENT ON COLUMN "payment_requests"."payer" IS 'anyone may pay the request when null';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending, accepted, declined, cancelled, expired or rejected by risk review';
+
+COMMENT ON COLUMN "payment_requests"."review_id" IS 'set while the payment is held for risk review';
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("paid_by") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("to_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("payment_request_id") REFERENCES "payment_requests" ("id");
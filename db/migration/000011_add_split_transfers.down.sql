@@ -0,0 +1,2 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "split_transfer_id";
+DROP TABLE IF EXISTS split_transfers;
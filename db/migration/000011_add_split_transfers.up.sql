@@ -0,0 +1,19 @@
+CREATE TABLE "split_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "from_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfers" ADD COLUMN "split_transfer_id" bigint;
+
+CREATE INDEX ON "split_transfers" ("from_wallet_id");
+
+CREATE INDEX ON "transfers" ("split_transfer_id");
+
+COMMENT ON COLUMN "split_transfers"."amount" IS 'sum of the transfers of the split';
+
+ALTER TABLE "split_transfers" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("split_transfer_id") REFERENCES "split_transfers" ("id");
@@ -0,0 +1,57 @@
+CREATE TABLE "payout_batches" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_wallet_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'queued',
+  "row_count" int NOT NULL,
+  "total_amount" bigint NOT NULL,
+  "succeeded_count" int NOT NULL DEFAULT 0,
+  "succeeded_amount" bigint NOT NULL DEFAULT 0,
+  "review_count" int NOT NULL DEFAULT 0,
+  "failed_count" int NOT NULL DEFAULT 0,
+  "cancelled_count" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
+
+CREATE TABLE "payout_rows" (
+  "id" bigserial PRIMARY KEY,
+  "batch_id" bigint NOT NULL,
+  "row_number" int NOT NULL,
+  "recipient_key" varchar NOT NULL,
+  "to_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "reference" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "processed_at" timestamptz
+);
+
+CREATE INDEX ON "payout_batches" ("owner");
+
+CREATE INDEX ON "payout_batches" ("status");
+
+CREATE UNIQUE INDEX ON "payout_rows" ("batch_id", "row_number");
+
+CREATE INDEX ON "payout_rows" ("batch_id", "status");
+
+COMMENT ON COLUMN "payout_batches"."status" IS 'queued, processing, paused, cancelled or completed';
+
+COMMENT ON COLUMN "payout_rows"."status" IS 'pending, succeeded, review, failed or cancelled';
+
+COMMENT ON COLUMN "payout_rows"."to_wallet_id" IS 'resolved from the recipient key when the batch is uploaded';
+
+ALTER TABLE "payout_batches" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payout_batches" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "payout_rows" ADD FOREIGN KEY ("batch_id") REFERENCES "payout_batches" ("id");
+
+ALTER TABLE "payout_rows" ADD FOREIGN KEY ("to_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "payout_rows" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,4 @@
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "fee_schedule_id";
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "kind";
+DROP TABLE IF EXISTS fee_schedules;
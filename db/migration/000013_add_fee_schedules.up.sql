@@ -0,0 +1,39 @@
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "operation" varchar NOT NULL,
+  "user_type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "fixed_amount" bigint NOT NULL DEFAULT 0,
+  "basis_points" bigint NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint NOT NULL DEFAULT 0,
+  "tiers" jsonb NOT NULL DEFAULT '[]',
+  "effective_from" timestamptz NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "entries" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'standard';
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+ALTER TABLE "entries" ADD COLUMN "fee_schedule_id" bigint;
+
+CREATE INDEX ON "fee_schedules" ("operation", "user_type", "currency", "effective_from");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "fee_schedules"."basis_points" IS 'percentage of the amount, 100 is 1%';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'zero means the fee is not capped';
+
+COMMENT ON COLUMN "fee_schedules"."effective_from" IS 'replaces the previous schedule of the operation from then on';
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer a fee entry was charged for';
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("fee_schedule_id") REFERENCES "fee_schedules" ("id");
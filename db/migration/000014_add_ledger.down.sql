@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journals;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,80 @@
+CREATE TABLE "ledger_accounts" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "wallet_id" bigint UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "journals" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "postings" (
+  "id" bigserial PRIMARY KEY,
+  "journal_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "ledger_accounts" ("code", "currency");
+
+CREATE INDEX ON "journals" ("transfer_id");
+
+CREATE INDEX ON "postings" ("journal_id");
+
+CREATE INDEX ON "postings" ("account_id");
+
+COMMENT ON COLUMN "ledger_accounts"."type" IS 'asset, liability, equity, revenue or expense';
+
+COMMENT ON COLUMN "ledger_accounts"."wallet_id" IS 'set on the liability account of a wallet';
+
+COMMENT ON COLUMN "postings"."amount" IS 'debits are positive and credits negative, the postings of a journal add up to zero';
+
+ALTER TABLE "ledger_accounts" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "journals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("account_id") REFERENCES "ledger_accounts" ("id");
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency")
+SELECT account.code, account.name, account.type, currency.code
+FROM (VALUES
+  ('bank_settlement', 'Bank settlement', 'asset'),
+  ('fee_revenue', 'Fee revenue', 'revenue'),
+  ('fx_position', 'FX position', 'asset'),
+  ('suspense', 'Suspense', 'liability')
+) AS account (code, name, type)
+CROSS JOIN (VALUES ('USD'), ('BRL'), ('EUR')) AS currency (code);
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency", "wallet_id")
+SELECT 'wallet:' || id, 'Wallet ' || id, 'liability', currency, id
+FROM "wallets";
+
+WITH "opening" AS (
+  INSERT INTO "journals" ("kind", "description")
+  VALUES ('opening', 'balances of the wallets before the ledger')
+  RETURNING id
+)
+INSERT INTO "postings" ("journal_id", "account_id", "amount")
+SELECT "opening".id, "ledger_accounts".id, -"wallets".balance
+FROM "opening", "wallets"
+JOIN "ledger_accounts" ON "ledger_accounts".wallet_id = "wallets".id
+WHERE "wallets".balance <> 0
+UNION ALL
+SELECT "opening".id, "ledger_accounts".id, "totals".balance
+FROM "opening", (
+  SELECT currency, SUM(balance) AS balance FROM "wallets"
+  GROUP BY currency
+  HAVING SUM(balance) <> 0
+) AS "totals"
+JOIN "ledger_accounts" ON "ledger_accounts".code = 'suspense' AND "ledger_accounts".currency = "totals".currency;
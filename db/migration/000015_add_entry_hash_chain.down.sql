@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS entries_wallet_id_id_idx;
+DROP TABLE IF EXISTS chain_checkpoints;
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "hash";
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "prev_hash";
@@ -0,0 +1,54 @@
+ALTER TABLE "entries" ADD COLUMN "prev_hash" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "hash" varchar NOT NULL DEFAULT '';
+
+CREATE TABLE "chain_checkpoints" (
+  "id" bigserial PRIMARY KEY,
+  "from_entry_id" bigint NOT NULL,
+  "to_entry_id" bigint NOT NULL,
+  "entry_count" bigint NOT NULL,
+  "prev_digest" varchar NOT NULL,
+  "digest" varchar NOT NULL,
+  "public_key" varchar NOT NULL,
+  "signature" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "chain_checkpoints" ("from_entry_id");
+
+CREATE UNIQUE INDEX ON "chain_checkpoints" ("to_entry_id");
+
+CREATE INDEX ON "entries" ("wallet_id", "id");
+
+COMMENT ON COLUMN "entries"."prev_hash" IS 'hash of the previous entry of the wallet, empty for the first one';
+
+COMMENT ON COLUMN "entries"."hash" IS 'sha256 over the entry and prev_hash';
+
+COMMENT ON COLUMN "chain_checkpoints"."from_entry_id" IS 'entries after this id, up to to_entry_id, are covered';
+
+COMMENT ON COLUMN "chain_checkpoints"."digest" IS 'sha256 over prev_digest and the hashes of the covered entries';
+
+COMMENT ON COLUMN "chain_checkpoints"."signature" IS 'ed25519 signature of the digest';
+
+WITH RECURSIVE "ordered" AS (
+  SELECT *, row_number() OVER (PARTITION BY wallet_id ORDER BY id) AS position
+  FROM "entries"
+), "chain" AS (
+  SELECT id, wallet_id, position, ''::varchar AS prev_hash,
+    encode(sha256(convert_to(concat_ws('|', 'entry/v1', id, wallet_id, amount, kind,
+      COALESCE(transfer_id::text, ''), COALESCE(fee_schedule_id::text, ''),
+      round(extract(epoch FROM created_at) * 1000000)::bigint, ''), 'UTF8')), 'hex')::varchar AS hash
+  FROM "ordered"
+  WHERE position = 1
+  UNION ALL
+  SELECT o.id, o.wallet_id, o.position, c.hash,
+    encode(sha256(convert_to(concat_ws('|', 'entry/v1', o.id, o.wallet_id, o.amount, o.kind,
+      COALESCE(o.transfer_id::text, ''), COALESCE(o.fee_schedule_id::text, ''),
+      round(extract(epoch FROM o.created_at) * 1000000)::bigint, c.hash), 'UTF8')), 'hex')::varchar
+  FROM "ordered" o
+  JOIN "chain" c ON c.wallet_id = o.wallet_id AND o.position = c.position + 1
+)
+UPDATE "entries"
+SET prev_hash = "chain".prev_hash, hash = "chain".hash
+FROM "chain"
+WHERE "entries".id = "chain".id;
@@ -0,0 +1,35 @@
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "wallet_id" bigint NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'authorized',
+  "transfer_id" bigint,
+  "created_by" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "wallets" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "holds" ("wallet_id");
+
+CREATE INDEX ON "holds" ("merchant_wallet_id");
+
+CREATE INDEX ON "holds" ("status", "expires_at");
+
+COMMENT ON COLUMN "holds"."status" IS 'authorized, captured, voided or expired';
+
+COMMENT ON COLUMN "holds"."captured_amount" IS 'at most amount, the rest is released on capture';
+
+COMMENT ON COLUMN "wallets"."held_balance" IS 'sum of the authorized holds, the available balance is balance - held_balance';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
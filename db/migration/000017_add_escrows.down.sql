@@ -0,0 +1,2 @@
+-- the escrow ledger accounts are kept, their postings belong to balanced journals
+DROP TABLE IF EXISTS escrows;
@@ -0,0 +1,51 @@
+CREATE TABLE "escrows" (
+  "id" bigserial PRIMARY KEY,
+  "buyer_wallet_id" bigint NOT NULL,
+  "seller_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'held',
+  "release_at" timestamptz NOT NULL,
+  "buyer_agreed_refund" boolean NOT NULL DEFAULT false,
+  "seller_agreed_refund" boolean NOT NULL DEFAULT false,
+  "disputed_by" varchar,
+  "dispute_reason" varchar NOT NULL DEFAULT '',
+  "disputed_at" timestamptz,
+  "transfer_id" bigint,
+  "decided_by" varchar,
+  "note" varchar NOT NULL DEFAULT '',
+  "decided_at" timestamptz,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "escrows" ("buyer_wallet_id");
+
+CREATE INDEX ON "escrows" ("seller_wallet_id");
+
+CREATE INDEX ON "escrows" ("status", "release_at");
+
+COMMENT ON COLUMN "escrows"."status" IS 'held, disputed, released or refunded';
+
+COMMENT ON COLUMN "escrows"."release_at" IS 'held escrows are released to the seller from then on, disputed ones wait for an admin';
+
+COMMENT ON COLUMN "escrows"."transfer_id" IS 'transfer to the seller, set once released';
+
+COMMENT ON COLUMN "escrows"."decided_by" IS 'admin who resolved the dispute, null when the parties settled it';
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("buyer_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("seller_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("disputed_by") REFERENCES "users" ("username");
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "escrows" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency")
+SELECT 'escrow', 'Escrow', 'liability', currency.code
+FROM (VALUES ('USD'), ('BRL'), ('EUR')) AS currency (code)
+ON CONFLICT DO NOTHING;
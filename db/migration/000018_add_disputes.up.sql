@@ -0,0 +1,62 @@
+CREATE TABLE "disputes" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "opened_by" varchar NOT NULL,
+  "merchant" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'open',
+  "respond_by" timestamptz NOT NULL,
+  "merchant_response" varchar NOT NULL DEFAULT '',
+  "responded_at" timestamptz,
+  "decided_by" varchar,
+  "decision_note" varchar NOT NULL DEFAULT '',
+  "decided_at" timestamptz,
+  "reversal_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "dispute_evidence" (
+  "id" bigserial PRIMARY KEY,
+  "dispute_id" bigint NOT NULL,
+  "uploaded_by" varchar NOT NULL,
+  "file_name" varchar NOT NULL,
+  "content_type" varchar NOT NULL,
+  "size" bigint NOT NULL,
+  "blob_key" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "disputes" ("transfer_id");
+
+CREATE INDEX ON "disputes" ("merchant", "created_at");
+
+CREATE INDEX ON "disputes" ("status", "respond_by");
+
+CREATE INDEX ON "dispute_evidence" ("dispute_id");
+
+COMMENT ON COLUMN "disputes"."opened_by" IS 'owner of the wallet that paid the transfer';
+
+COMMENT ON COLUMN "disputes"."merchant" IS 'owner of the wallet that received the transfer';
+
+COMMENT ON COLUMN "disputes"."status" IS 'open, under_review, payer_won or merchant_won';
+
+COMMENT ON COLUMN "disputes"."respond_by" IS 'open disputes go to review without a response from then on';
+
+COMMENT ON COLUMN "disputes"."reversal_transfer_id" IS 'transfer giving the amount back to the payer, set when the payer won';
+
+COMMENT ON COLUMN "dispute_evidence"."blob_key" IS 'where the file is kept in the blob store';
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("opened_by") REFERENCES "users" ("username");
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("merchant") REFERENCES "users" ("username");
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("reversal_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "dispute_evidence" ADD FOREIGN KEY ("dispute_id") REFERENCES "disputes" ("id");
+
+ALTER TABLE "dispute_evidence" ADD FOREIGN KEY ("uploaded_by") REFERENCES "users" ("username");
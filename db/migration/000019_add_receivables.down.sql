@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS receivables;
+DROP TABLE IF EXISTS settlement_plans;
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "receivable_balance";
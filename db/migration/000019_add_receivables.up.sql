@@ -0,0 +1,45 @@
+CREATE TABLE "settlement_plans" (
+  "owner" varchar PRIMARY KEY,
+  "settle_days" int NOT NULL DEFAULT 0,
+  "anticipation_rate" bigint NOT NULL DEFAULT 0,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "receivables" (
+  "id" bigserial PRIMARY KEY,
+  "wallet_id" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "settle_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "anticipation_fee" bigint NOT NULL DEFAULT 0,
+  "settled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "wallets" ADD COLUMN "receivable_balance" bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "receivables" ("wallet_id", "status", "settle_at");
+
+CREATE INDEX ON "receivables" ("status", "settle_at");
+
+COMMENT ON COLUMN "settlement_plans"."settle_days" IS 'days after a transfer its receivable settles, 0 settles right away';
+
+COMMENT ON COLUMN "settlement_plans"."anticipation_rate" IS 'basis points charged per 30 days a receivable is settled early';
+
+COMMENT ON COLUMN "receivables"."settle_at" IS 'pending receivables become available from then on';
+
+COMMENT ON COLUMN "receivables"."status" IS 'pending, settled or anticipated';
+
+COMMENT ON COLUMN "receivables"."anticipation_fee" IS 'charged when the receivable was anticipated';
+
+COMMENT ON COLUMN "wallets"."receivable_balance" IS 'sum of the pending receivables, which are part of the balance but not available';
+
+ALTER TABLE "settlement_plans" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "settlement_plans" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "receivables" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "receivables" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
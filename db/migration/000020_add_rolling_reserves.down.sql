@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reserves;
+DROP TABLE IF EXISTS reserve_rules;
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "reserve_balance";
@@ -0,0 +1,43 @@
+CREATE TABLE "reserve_rules" (
+  "owner" varchar PRIMARY KEY,
+  "rate" bigint NOT NULL,
+  "hold_days" int NOT NULL,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "reserves" (
+  "id" bigserial PRIMARY KEY,
+  "wallet_id" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "used_amount" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'held',
+  "release_at" timestamptz NOT NULL,
+  "released_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "wallets" ADD COLUMN "reserve_balance" bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "reserves" ("wallet_id", "status", "release_at");
+
+CREATE INDEX ON "reserves" ("status", "release_at");
+
+COMMENT ON COLUMN "reserve_rules"."rate" IS 'basis points of each payment received that are withheld';
+
+COMMENT ON COLUMN "reserve_rules"."hold_days" IS 'days a withheld amount stays in the reserve';
+
+COMMENT ON COLUMN "reserves"."used_amount" IS 'taken from the reserve to cover chargebacks, never released';
+
+COMMENT ON COLUMN "reserves"."status" IS 'held or released';
+
+COMMENT ON COLUMN "wallets"."reserve_balance" IS 'sum of what is left of the held reserves, which is part of the balance but not available';
+
+ALTER TABLE "reserve_rules" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "reserve_rules" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "reserves" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "reserves" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
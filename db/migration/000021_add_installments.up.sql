@@ -0,0 +1,70 @@
+CREATE TABLE "installment_plans" (
+  "id" bigserial PRIMARY KEY,
+  "customer_wallet_id" bigint NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "interest_rate" bigint NOT NULL DEFAULT 0,
+  "total_amount" bigint NOT NULL,
+  "installment_count" int NOT NULL,
+  "funding_wallet_id" bigint,
+  "funding_transfer_id" bigint,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "installments" (
+  "id" bigserial PRIMARY KEY,
+  "plan_id" bigint NOT NULL,
+  "number" int NOT NULL,
+  "amount" bigint NOT NULL,
+  "principal" bigint NOT NULL,
+  "due_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "retry_at" timestamptz,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "paid_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "paid_at" timestamptz
+);
+
+CREATE INDEX ON "installment_plans" ("customer_wallet_id");
+
+CREATE INDEX ON "installment_plans" ("merchant_wallet_id");
+
+CREATE UNIQUE INDEX ON "installments" ("plan_id", "number");
+
+CREATE INDEX ON "installments" ("status", "due_at");
+
+COMMENT ON COLUMN "installment_plans"."amount" IS 'price of the purchase, before interest';
+
+COMMENT ON COLUMN "installment_plans"."interest_rate" IS 'basis points of the outstanding amount charged every month';
+
+COMMENT ON COLUMN "installment_plans"."funding_wallet_id" IS 'platform wallet that paid the merchant upfront and collects the installments, null when the merchant collects them';
+
+COMMENT ON COLUMN "installment_plans"."status" IS 'active, delinquent, completed or cancelled';
+
+COMMENT ON COLUMN "installments"."principal" IS 'part of the amount that pays down the purchase, the rest is interest';
+
+COMMENT ON COLUMN "installments"."status" IS 'pending, paid, overdue or cancelled';
+
+COMMENT ON COLUMN "installments"."retry_at" IS 'when the installment is tried again after the customer could not cover it';
+
+COMMENT ON COLUMN "installments"."paid_amount" IS 'less than the amount when the installment was prepaid without its interest';
+
+ALTER TABLE "installment_plans" ADD FOREIGN KEY ("customer_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "installment_plans" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "installment_plans" ADD FOREIGN KEY ("funding_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "installment_plans" ADD FOREIGN KEY ("funding_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "installment_plans" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "installments" ADD FOREIGN KEY ("plan_id") REFERENCES "installment_plans" ("id");
+
+ALTER TABLE "installments" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,76 @@
+CREATE TABLE "payment_links" (
+  "id" bigserial PRIMARY KEY,
+  "token" varchar NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "max_amount" bigint NOT NULL DEFAULT 0,
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "success_url" varchar NOT NULL DEFAULT '',
+  "cancel_url" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}',
+  "active" boolean NOT NULL DEFAULT true,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "checkout_sessions" (
+  "id" bigserial PRIMARY KEY,
+  "token" varchar NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "payment_link_id" bigint,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "success_url" varchar NOT NULL DEFAULT '',
+  "cancel_url" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'open',
+  "expires_at" timestamptz NOT NULL,
+  "paid_by" varchar,
+  "from_wallet_id" bigint,
+  "transfer_id" bigint,
+  "review_id" bigint,
+  "paid_at" timestamptz,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "payment_links" ("token");
+
+CREATE INDEX ON "payment_links" ("merchant_wallet_id");
+
+CREATE UNIQUE INDEX ON "checkout_sessions" ("token");
+
+CREATE INDEX ON "checkout_sessions" ("merchant_wallet_id");
+
+CREATE INDEX ON "checkout_sessions" ("status", "expires_at");
+
+CREATE UNIQUE INDEX ON "checkout_sessions" ("transfer_id");
+
+COMMENT ON COLUMN "payment_links"."amount" IS 'charged by every session of the link, the customer chooses the amount when 0';
+
+COMMENT ON COLUMN "payment_links"."max_amount" IS 'highest amount a customer may choose, no limit when 0';
+
+COMMENT ON COLUMN "checkout_sessions"."status" IS 'open, processing while held for risk review, paid or expired';
+
+COMMENT ON COLUMN "checkout_sessions"."review_id" IS 'set while the payment is held for risk review';
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("payment_link_id") REFERENCES "payment_links" ("id");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("paid_by") REFERENCES "users" ("username");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
+
+ALTER TABLE "checkout_sessions" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
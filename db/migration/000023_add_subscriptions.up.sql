@@ -0,0 +1,94 @@
+CREATE TABLE "subscription_plans" (
+  "id" bigserial PRIMARY KEY,
+  "merchant_wallet_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "frequency" varchar NOT NULL,
+  "trial_days" int NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "plan_id" bigint NOT NULL,
+  "customer_wallet_id" bigint NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "status" varchar NOT NULL,
+  "mandate_approved_by" varchar NOT NULL,
+  "mandate_approved_at" timestamptz NOT NULL,
+  "day_of_month" int NOT NULL,
+  "current_period_start" timestamptz NOT NULL,
+  "current_period_end" timestamptz NOT NULL,
+  "trial_ends_at" timestamptz,
+  "credit" bigint NOT NULL DEFAULT 0,
+  "attempts" int NOT NULL DEFAULT 0,
+  "retry_at" timestamptz,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "cancel_at_period_end" boolean NOT NULL DEFAULT false,
+  "cancelled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "subscription_charges" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "plan_id" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "period_start" timestamptz NOT NULL,
+  "period_end" timestamptz NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "subscription_plans" ("merchant_wallet_id");
+
+CREATE INDEX ON "subscriptions" ("customer_wallet_id");
+
+CREATE INDEX ON "subscriptions" ("merchant_wallet_id");
+
+CREATE INDEX ON "subscriptions" ("status", "current_period_end");
+
+CREATE UNIQUE INDEX ON "subscriptions" ("plan_id", "customer_wallet_id") WHERE "status" <> 'cancelled';
+
+CREATE INDEX ON "subscription_charges" ("subscription_id");
+
+COMMENT ON COLUMN "subscription_plans"."frequency" IS 'weekly or monthly';
+
+COMMENT ON COLUMN "subscriptions"."status" IS 'trialing, active, past_due or cancelled';
+
+COMMENT ON COLUMN "subscriptions"."mandate_approved_by" IS 'customer who allowed the merchant to charge the wallet every period';
+
+COMMENT ON COLUMN "subscriptions"."day_of_month" IS 'monthly periods end on this day, or on the last day of shorter months';
+
+COMMENT ON COLUMN "subscriptions"."current_period_end" IS 'when the next period is charged';
+
+COMMENT ON COLUMN "subscriptions"."credit" IS 'unused amount left by plan changes, taken off the next charges';
+
+COMMENT ON COLUMN "subscriptions"."retry_at" IS 'when the charge is tried again after the customer could not cover it';
+
+COMMENT ON COLUMN "subscription_charges"."reason" IS 'period or proration';
+
+COMMENT ON COLUMN "subscription_charges"."transfer_id" IS 'null when the credit covered the whole charge';
+
+ALTER TABLE "subscription_plans" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "subscription_plans" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "subscriptions" ADD FOREIGN KEY ("plan_id") REFERENCES "subscription_plans" ("id");
+
+ALTER TABLE "subscriptions" ADD FOREIGN KEY ("customer_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "subscriptions" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "subscriptions" ADD FOREIGN KEY ("mandate_approved_by") REFERENCES "users" ("username");
+
+ALTER TABLE "subscription_charges" ADD FOREIGN KEY ("subscription_id") REFERENCES "subscriptions" ("id");
+
+ALTER TABLE "subscription_charges" ADD FOREIGN KEY ("plan_id") REFERENCES "subscription_plans" ("id");
+
+ALTER TABLE "subscription_charges" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS transfers DROP COLUMN IF EXISTS invoice_id;
+DROP TABLE IF EXISTS invoice_sequences;
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
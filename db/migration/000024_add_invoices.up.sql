@@ -0,0 +1,96 @@
+CREATE TABLE "invoices" (
+  "id" bigserial PRIMARY KEY,
+  "issuer" varchar NOT NULL,
+  "merchant_wallet_id" bigint NOT NULL,
+  "customer" varchar NOT NULL,
+  "number" bigint,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "due_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'draft',
+  "subtotal" bigint NOT NULL DEFAULT 0,
+  "discount" bigint NOT NULL DEFAULT 0,
+  "tax" bigint NOT NULL DEFAULT 0,
+  "total" bigint NOT NULL DEFAULT 0,
+  "review_id" bigint,
+  "from_wallet_id" bigint,
+  "transfer_id" bigint,
+  "finalized_at" timestamptz,
+  "paid_at" timestamptz,
+  "voided_at" timestamptz,
+  "reminded_at" timestamptz,
+  "reminder_count" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "invoice_items" (
+  "id" bigserial PRIMARY KEY,
+  "invoice_id" bigint NOT NULL,
+  "position" int NOT NULL,
+  "description" varchar NOT NULL,
+  "quantity" bigint NOT NULL,
+  "unit_amount" bigint NOT NULL,
+  "discount" bigint NOT NULL DEFAULT 0,
+  "tax_name" varchar NOT NULL DEFAULT '',
+  "tax_rate" bigint NOT NULL DEFAULT 0,
+  "tax" bigint NOT NULL DEFAULT 0,
+  "amount" bigint NOT NULL
+);
+
+CREATE TABLE "invoice_sequences" (
+  "issuer" varchar PRIMARY KEY,
+  "last_number" bigint NOT NULL
+);
+
+ALTER TABLE "transfers" ADD COLUMN "invoice_id" bigint;
+
+CREATE INDEX ON "invoices" ("issuer");
+
+CREATE INDEX ON "invoices" ("customer");
+
+CREATE INDEX ON "invoices" ("status", "due_at");
+
+CREATE UNIQUE INDEX ON "invoices" ("issuer", "number");
+
+CREATE UNIQUE INDEX ON "invoices" ("transfer_id");
+
+CREATE UNIQUE INDEX ON "invoice_items" ("invoice_id", "position");
+
+CREATE INDEX ON "transfers" ("invoice_id");
+
+COMMENT ON COLUMN "invoices"."issuer" IS 'merchant the invoice is numbered for';
+
+COMMENT ON COLUMN "invoices"."number" IS 'sequential for the issuer, set when the invoice is finalized';
+
+COMMENT ON COLUMN "invoices"."status" IS 'draft, open, paid, void or overdue';
+
+COMMENT ON COLUMN "invoices"."discount" IS 'sum of the item discounts, taken off the subtotal before taxes';
+
+COMMENT ON COLUMN "invoices"."review_id" IS 'set while the payment is held for risk review';
+
+COMMENT ON COLUMN "invoice_items"."discount" IS 'taken off quantity times unit amount before the tax';
+
+COMMENT ON COLUMN "invoice_items"."tax_rate" IS 'basis points of the discounted amount';
+
+COMMENT ON COLUMN "invoice_items"."amount" IS 'discounted amount plus tax';
+
+COMMENT ON COLUMN "invoice_sequences"."last_number" IS 'number of the last invoice finalized by the issuer';
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("issuer") REFERENCES "users" ("username");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("merchant_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("customer") REFERENCES "users" ("username");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("from_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "invoice_items" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "invoice_sequences" ADD FOREIGN KEY ("issuer") REFERENCES "users" ("username");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id");
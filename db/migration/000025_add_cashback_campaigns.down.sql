@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cashback_grants;
+DROP TABLE IF EXISTS cashback_campaigns;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS merchant_category;
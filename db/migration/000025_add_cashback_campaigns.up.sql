@@ -0,0 +1,84 @@
+ALTER TABLE "users" ADD COLUMN "merchant_category" varchar NOT NULL DEFAULT '';
+
+CREATE TABLE "cashback_campaigns" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "rate" bigint NOT NULL,
+  "max_cashback" bigint NOT NULL DEFAULT 0,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "merchant_category" varchar NOT NULL DEFAULT '',
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "budget" bigint NOT NULL,
+  "spent" bigint NOT NULL DEFAULT 0,
+  "per_user_limit" bigint NOT NULL DEFAULT 0,
+  "settle_days" int NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "cashback_grants" (
+  "id" bigserial PRIMARY KEY,
+  "campaign_id" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "wallet_id" bigint NOT NULL,
+  "owner" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "clawed_back" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "settle_at" timestamptz NOT NULL,
+  "funding_wallet_id" bigint,
+  "credit_transfer_id" bigint,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "paid_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "cashback_campaigns" ("currency", "active", "ends_at");
+
+CREATE UNIQUE INDEX ON "cashback_grants" ("transfer_id");
+
+CREATE INDEX ON "cashback_grants" ("campaign_id", "owner");
+
+CREATE INDEX ON "cashback_grants" ("wallet_id");
+
+CREATE INDEX ON "cashback_grants" ("status", "settle_at");
+
+COMMENT ON COLUMN "users"."merchant_category" IS 'what the merchant sells, campaigns can be limited to a category';
+
+COMMENT ON COLUMN "cashback_campaigns"."rate" IS 'basis points of the payment given back';
+
+COMMENT ON COLUMN "cashback_campaigns"."max_cashback" IS 'cap of the cashback of one payment, zero meaning no cap';
+
+COMMENT ON COLUMN "cashback_campaigns"."merchant_category" IS 'category of the merchants paid, empty for any merchant';
+
+COMMENT ON COLUMN "cashback_campaigns"."spent" IS 'sum of the cashback granted and not clawed back, never above the budget';
+
+COMMENT ON COLUMN "cashback_campaigns"."per_user_limit" IS 'cashback one user can get from the campaign, zero meaning no limit';
+
+COMMENT ON COLUMN "cashback_campaigns"."settle_days" IS 'days before the cashback is paid, zero paying it with the payment';
+
+COMMENT ON COLUMN "cashback_grants"."transfer_id" IS 'payment the cashback was granted for';
+
+COMMENT ON COLUMN "cashback_grants"."clawed_back" IS 'part of the amount taken back after refunds of the payment';
+
+COMMENT ON COLUMN "cashback_grants"."status" IS 'pending, paid or clawed_back';
+
+COMMENT ON COLUMN "cashback_grants"."funding_wallet_id" IS 'marketing wallet the cashback was paid from, and clawed back to';
+
+ALTER TABLE "cashback_campaigns" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("campaign_id") REFERENCES "cashback_campaigns" ("id");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("funding_wallet_id") REFERENCES "wallets" ("id");
+
+ALTER TABLE "cashback_grants" ADD FOREIGN KEY ("credit_transfer_id") REFERENCES "transfers" ("id");
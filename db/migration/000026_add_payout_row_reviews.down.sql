@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS payout_rows DROP COLUMN IF EXISTS review_id;
@@ -0,0 +1,7 @@
+ALTER TABLE "payout_rows" ADD COLUMN "review_id" bigint;
+
+CREATE UNIQUE INDEX ON "payout_rows" ("review_id");
+
+COMMENT ON COLUMN "payout_rows"."review_id" IS 'set when the payout was held for risk review';
+
+ALTER TABLE "payout_rows" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
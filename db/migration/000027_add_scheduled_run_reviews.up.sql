@@ -0,0 +1,7 @@
+ALTER TABLE "scheduled_transfer_runs" ADD COLUMN "review_id" bigint;
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("review_id");
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."review_id" IS 'set when the run was held for risk review';
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS installment_funding_opt_ins;
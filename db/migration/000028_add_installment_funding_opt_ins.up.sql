@@ -0,0 +1,11 @@
+CREATE TABLE "installment_funding_opt_ins" (
+  "owner" varchar PRIMARY KEY,
+  "opted_in_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "installment_funding_opt_ins" IS 'merchants whose installment plans the platform may fund upfront';
+
+ALTER TABLE "installment_funding_opt_ins" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "installment_funding_opt_ins" ADD FOREIGN KEY ("opted_in_by") REFERENCES "users" ("username");
@@ -6,8 +6,10 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	db "picpay_simplificado/db/sqlc"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,257 +37,4880 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptPaymentRequestTx mocks base method.
+func (m *MockStore) AcceptPaymentRequestTx(arg0 context.Context, arg1 db.AcceptPaymentRequestTxParams) (db.AcceptPaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AcceptPaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequestTx indicates an expected call of AcceptPaymentRequestTx.
+func (mr *MockStoreMockRecorder) AcceptPaymentRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequestTx", reflect.TypeOf((*MockStore)(nil).AcceptPaymentRequestTx), arg0, arg1)
+}
+
+// AddCashbackCampaignSpent mocks base method.
+func (m *MockStore) AddCashbackCampaignSpent(arg0 context.Context, arg1 db.AddCashbackCampaignSpentParams) (db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCashbackCampaignSpent", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCashbackCampaignSpent indicates an expected call of AddCashbackCampaignSpent.
+func (mr *MockStoreMockRecorder) AddCashbackCampaignSpent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCashbackCampaignSpent", reflect.TypeOf((*MockStore)(nil).AddCashbackCampaignSpent), arg0, arg1)
+}
+
 // AddWalletBalance mocks base method.
 func (m *MockStore) AddWalletBalance(arg0 context.Context, arg1 db.AddWalletBalanceParams) (db.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddWalletBalance", arg0, arg1)
-	ret0, _ := ret[0].(db.Wallet)
+	ret := m.ctrl.Call(m, "AddWalletBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWalletBalance indicates an expected call of AddWalletBalance.
+func (mr *MockStoreMockRecorder) AddWalletBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletBalance", reflect.TypeOf((*MockStore)(nil).AddWalletBalance), arg0, arg1)
+}
+
+// AddWalletHeldBalance mocks base method.
+func (m *MockStore) AddWalletHeldBalance(arg0 context.Context, arg1 db.AddWalletHeldBalanceParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWalletHeldBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWalletHeldBalance indicates an expected call of AddWalletHeldBalance.
+func (mr *MockStoreMockRecorder) AddWalletHeldBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletHeldBalance", reflect.TypeOf((*MockStore)(nil).AddWalletHeldBalance), arg0, arg1)
+}
+
+// AddWalletReceivableBalance mocks base method.
+func (m *MockStore) AddWalletReceivableBalance(arg0 context.Context, arg1 db.AddWalletReceivableBalanceParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWalletReceivableBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWalletReceivableBalance indicates an expected call of AddWalletReceivableBalance.
+func (mr *MockStoreMockRecorder) AddWalletReceivableBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletReceivableBalance", reflect.TypeOf((*MockStore)(nil).AddWalletReceivableBalance), arg0, arg1)
+}
+
+// AddWalletReserveBalance mocks base method.
+func (m *MockStore) AddWalletReserveBalance(arg0 context.Context, arg1 db.AddWalletReserveBalanceParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWalletReserveBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWalletReserveBalance indicates an expected call of AddWalletReserveBalance.
+func (mr *MockStoreMockRecorder) AddWalletReserveBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletReserveBalance", reflect.TypeOf((*MockStore)(nil).AddWalletReserveBalance), arg0, arg1)
+}
+
+// AdjustWalletTx mocks base method.
+func (m *MockStore) AdjustWalletTx(arg0 context.Context, arg1 db.AdjustWalletTxParams) (db.AdjustWalletTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustWalletTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdjustWalletTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustWalletTx indicates an expected call of AdjustWalletTx.
+func (mr *MockStoreMockRecorder) AdjustWalletTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustWalletTx", reflect.TypeOf((*MockStore)(nil).AdjustWalletTx), arg0, arg1)
+}
+
+// AdvanceScheduledTransfer mocks base method.
+func (m *MockStore) AdvanceScheduledTransfer(arg0 context.Context, arg1 db.AdvanceScheduledTransferParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceScheduledTransfer indicates an expected call of AdvanceScheduledTransfer.
+func (mr *MockStoreMockRecorder) AdvanceScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceScheduledTransfer", reflect.TypeOf((*MockStore)(nil).AdvanceScheduledTransfer), arg0, arg1)
+}
+
+// AgreeEscrowRefund mocks base method.
+func (m *MockStore) AgreeEscrowRefund(arg0 context.Context, arg1 db.AgreeEscrowRefundParams) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AgreeEscrowRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AgreeEscrowRefund indicates an expected call of AgreeEscrowRefund.
+func (mr *MockStoreMockRecorder) AgreeEscrowRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AgreeEscrowRefund", reflect.TypeOf((*MockStore)(nil).AgreeEscrowRefund), arg0, arg1)
+}
+
+// AgreeEscrowRefundTx mocks base method.
+func (m *MockStore) AgreeEscrowRefundTx(arg0 context.Context, arg1 db.AgreeEscrowRefundTxParams) (db.EscrowTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AgreeEscrowRefundTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AgreeEscrowRefundTx indicates an expected call of AgreeEscrowRefundTx.
+func (mr *MockStoreMockRecorder) AgreeEscrowRefundTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AgreeEscrowRefundTx", reflect.TypeOf((*MockStore)(nil).AgreeEscrowRefundTx), arg0, arg1)
+}
+
+// AnticipateReceivable mocks base method.
+func (m *MockStore) AnticipateReceivable(arg0 context.Context, arg1 db.AnticipateReceivableParams) (db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnticipateReceivable", arg0, arg1)
+	ret0, _ := ret[0].(db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnticipateReceivable indicates an expected call of AnticipateReceivable.
+func (mr *MockStoreMockRecorder) AnticipateReceivable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnticipateReceivable", reflect.TypeOf((*MockStore)(nil).AnticipateReceivable), arg0, arg1)
+}
+
+// AnticipateReceivablesTx mocks base method.
+func (m *MockStore) AnticipateReceivablesTx(arg0 context.Context, arg1 db.AnticipateReceivablesTxParams) (db.AnticipateReceivablesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnticipateReceivablesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AnticipateReceivablesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnticipateReceivablesTx indicates an expected call of AnticipateReceivablesTx.
+func (mr *MockStoreMockRecorder) AnticipateReceivablesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnticipateReceivablesTx", reflect.TypeOf((*MockStore)(nil).AnticipateReceivablesTx), arg0, arg1)
+}
+
+// ApplyLimitIncreasesTx mocks base method.
+func (m *MockStore) ApplyLimitIncreasesTx(arg0 context.Context, arg1 db.ApplyLimitIncreasesTxParams) ([]db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyLimitIncreasesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyLimitIncreasesTx indicates an expected call of ApplyLimitIncreasesTx.
+func (mr *MockStoreMockRecorder) ApplyLimitIncreasesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyLimitIncreasesTx", reflect.TypeOf((*MockStore)(nil).ApplyLimitIncreasesTx), arg0, arg1)
+}
+
+// CancelInstallmentPlanTx mocks base method.
+func (m *MockStore) CancelInstallmentPlanTx(arg0 context.Context, arg1 int64) (db.CancelInstallmentPlanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInstallmentPlanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CancelInstallmentPlanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelInstallmentPlanTx indicates an expected call of CancelInstallmentPlanTx.
+func (mr *MockStoreMockRecorder) CancelInstallmentPlanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInstallmentPlanTx", reflect.TypeOf((*MockStore)(nil).CancelInstallmentPlanTx), arg0, arg1)
+}
+
+// CancelOpenInstallments mocks base method.
+func (m *MockStore) CancelOpenInstallments(arg0 context.Context, arg1 int64) ([]db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOpenInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOpenInstallments indicates an expected call of CancelOpenInstallments.
+func (mr *MockStoreMockRecorder) CancelOpenInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOpenInstallments", reflect.TypeOf((*MockStore)(nil).CancelOpenInstallments), arg0, arg1)
+}
+
+// CancelPayoutBatch mocks base method.
+func (m *MockStore) CancelPayoutBatch(arg0 context.Context, arg1 db.CancelPayoutBatchParams) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPayoutBatch", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPayoutBatch indicates an expected call of CancelPayoutBatch.
+func (mr *MockStoreMockRecorder) CancelPayoutBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPayoutBatch", reflect.TypeOf((*MockStore)(nil).CancelPayoutBatch), arg0, arg1)
+}
+
+// CancelPendingPayoutRows mocks base method.
+func (m *MockStore) CancelPendingPayoutRows(arg0 context.Context, arg1 db.CancelPendingPayoutRowsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingPayoutRows", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPendingPayoutRows indicates an expected call of CancelPendingPayoutRows.
+func (mr *MockStoreMockRecorder) CancelPendingPayoutRows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingPayoutRows", reflect.TypeOf((*MockStore)(nil).CancelPendingPayoutRows), arg0, arg1)
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockStore) CancelScheduledTransfer(arg0 context.Context, arg1 int64) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockStoreMockRecorder) CancelScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockStore)(nil).CancelScheduledTransfer), arg0, arg1)
+}
+
+// CancelSubscription mocks base method.
+func (m *MockStore) CancelSubscription(arg0 context.Context, arg1 db.CancelSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSubscription indicates an expected call of CancelSubscription.
+func (mr *MockStoreMockRecorder) CancelSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSubscription", reflect.TypeOf((*MockStore)(nil).CancelSubscription), arg0, arg1)
+}
+
+// CancelSubscriptionTx mocks base method.
+func (m *MockStore) CancelSubscriptionTx(arg0 context.Context, arg1 db.CancelSubscriptionTxParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSubscriptionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSubscriptionTx indicates an expected call of CancelSubscriptionTx.
+func (mr *MockStoreMockRecorder) CancelSubscriptionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSubscriptionTx", reflect.TypeOf((*MockStore)(nil).CancelSubscriptionTx), arg0, arg1)
+}
+
+// CaptureHoldTx mocks base method.
+func (m *MockStore) CaptureHoldTx(arg0 context.Context, arg1 db.CaptureHoldTxParams) (db.CaptureHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CaptureHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHoldTx indicates an expected call of CaptureHoldTx.
+func (mr *MockStoreMockRecorder) CaptureHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHoldTx", reflect.TypeOf((*MockStore)(nil).CaptureHoldTx), arg0, arg1)
+}
+
+// ChangeSubscriptionPlan mocks base method.
+func (m *MockStore) ChangeSubscriptionPlan(arg0 context.Context, arg1 db.ChangeSubscriptionPlanParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeSubscriptionPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeSubscriptionPlan indicates an expected call of ChangeSubscriptionPlan.
+func (mr *MockStoreMockRecorder) ChangeSubscriptionPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeSubscriptionPlan", reflect.TypeOf((*MockStore)(nil).ChangeSubscriptionPlan), arg0, arg1)
+}
+
+// ChangeSubscriptionPlanTx mocks base method.
+func (m *MockStore) ChangeSubscriptionPlanTx(arg0 context.Context, arg1 db.ChangeSubscriptionPlanTxParams) (db.ChangeSubscriptionPlanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeSubscriptionPlanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChangeSubscriptionPlanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeSubscriptionPlanTx indicates an expected call of ChangeSubscriptionPlanTx.
+func (mr *MockStoreMockRecorder) ChangeSubscriptionPlanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeSubscriptionPlanTx", reflect.TypeOf((*MockStore)(nil).ChangeSubscriptionPlanTx), arg0, arg1)
+}
+
+// ClaimDueCashbackGrant mocks base method.
+func (m *MockStore) ClaimDueCashbackGrant(arg0 context.Context, arg1 time.Time) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueCashbackGrant indicates an expected call of ClaimDueCashbackGrant.
+func (mr *MockStoreMockRecorder) ClaimDueCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueCashbackGrant", reflect.TypeOf((*MockStore)(nil).ClaimDueCashbackGrant), arg0, arg1)
+}
+
+// ClaimDueEscrows mocks base method.
+func (m *MockStore) ClaimDueEscrows(arg0 context.Context, arg1 db.ClaimDueEscrowsParams) ([]db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueEscrows", arg0, arg1)
+	ret0, _ := ret[0].([]db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueEscrows indicates an expected call of ClaimDueEscrows.
+func (mr *MockStoreMockRecorder) ClaimDueEscrows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueEscrows", reflect.TypeOf((*MockStore)(nil).ClaimDueEscrows), arg0, arg1)
+}
+
+// ClaimDueInstallment mocks base method.
+func (m *MockStore) ClaimDueInstallment(arg0 context.Context, arg1 time.Time) (db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueInstallment indicates an expected call of ClaimDueInstallment.
+func (mr *MockStoreMockRecorder) ClaimDueInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueInstallment", reflect.TypeOf((*MockStore)(nil).ClaimDueInstallment), arg0, arg1)
+}
+
+// ClaimDueLimitIncreaseRequests mocks base method.
+func (m *MockStore) ClaimDueLimitIncreaseRequests(arg0 context.Context, arg1 int32) ([]db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueLimitIncreaseRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueLimitIncreaseRequests indicates an expected call of ClaimDueLimitIncreaseRequests.
+func (mr *MockStoreMockRecorder) ClaimDueLimitIncreaseRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueLimitIncreaseRequests", reflect.TypeOf((*MockStore)(nil).ClaimDueLimitIncreaseRequests), arg0, arg1)
+}
+
+// ClaimDueReceivables mocks base method.
+func (m *MockStore) ClaimDueReceivables(arg0 context.Context, arg1 db.ClaimDueReceivablesParams) ([]db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReceivables", arg0, arg1)
+	ret0, _ := ret[0].([]db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReceivables indicates an expected call of ClaimDueReceivables.
+func (mr *MockStoreMockRecorder) ClaimDueReceivables(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReceivables", reflect.TypeOf((*MockStore)(nil).ClaimDueReceivables), arg0, arg1)
+}
+
+// ClaimDueReserves mocks base method.
+func (m *MockStore) ClaimDueReserves(arg0 context.Context, arg1 db.ClaimDueReservesParams) ([]db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReserves", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReserves indicates an expected call of ClaimDueReserves.
+func (mr *MockStoreMockRecorder) ClaimDueReserves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReserves", reflect.TypeOf((*MockStore)(nil).ClaimDueReserves), arg0, arg1)
+}
+
+// ClaimDueScheduledTransfer mocks base method.
+func (m *MockStore) ClaimDueScheduledTransfer(arg0 context.Context, arg1 time.Time) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueScheduledTransfer indicates an expected call of ClaimDueScheduledTransfer.
+func (mr *MockStoreMockRecorder) ClaimDueScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueScheduledTransfer", reflect.TypeOf((*MockStore)(nil).ClaimDueScheduledTransfer), arg0, arg1)
+}
+
+// ClaimDueSubscription mocks base method.
+func (m *MockStore) ClaimDueSubscription(arg0 context.Context, arg1 time.Time) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSubscription indicates an expected call of ClaimDueSubscription.
+func (mr *MockStoreMockRecorder) ClaimDueSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSubscription", reflect.TypeOf((*MockStore)(nil).ClaimDueSubscription), arg0, arg1)
+}
+
+// ClaimExpiredHolds mocks base method.
+func (m *MockStore) ClaimExpiredHolds(arg0 context.Context, arg1 db.ClaimExpiredHoldsParams) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpiredHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpiredHolds indicates an expected call of ClaimExpiredHolds.
+func (mr *MockStoreMockRecorder) ClaimExpiredHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpiredHolds", reflect.TypeOf((*MockStore)(nil).ClaimExpiredHolds), arg0, arg1)
+}
+
+// ClaimOverdueDisputes mocks base method.
+func (m *MockStore) ClaimOverdueDisputes(arg0 context.Context, arg1 db.ClaimOverdueDisputesParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOverdueDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOverdueDisputes indicates an expected call of ClaimOverdueDisputes.
+func (mr *MockStoreMockRecorder) ClaimOverdueDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOverdueDisputes", reflect.TypeOf((*MockStore)(nil).ClaimOverdueDisputes), arg0, arg1)
+}
+
+// ClaimPayoutBatch mocks base method.
+func (m *MockStore) ClaimPayoutBatch(arg0 context.Context) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPayoutBatch", arg0)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPayoutBatch indicates an expected call of ClaimPayoutBatch.
+func (mr *MockStoreMockRecorder) ClaimPayoutBatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPayoutBatch", reflect.TypeOf((*MockStore)(nil).ClaimPayoutBatch), arg0)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 db.ClaimWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// ClawBackCashbackGrant mocks base method.
+func (m *MockStore) ClawBackCashbackGrant(arg0 context.Context, arg1 db.ClawBackCashbackGrantParams) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClawBackCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClawBackCashbackGrant indicates an expected call of ClawBackCashbackGrant.
+func (mr *MockStoreMockRecorder) ClawBackCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClawBackCashbackGrant", reflect.TypeOf((*MockStore)(nil).ClawBackCashbackGrant), arg0, arg1)
+}
+
+// CollectInstallmentTx mocks base method.
+func (m *MockStore) CollectInstallmentTx(arg0 context.Context, arg1 db.CollectInstallmentTxParams) (db.CollectInstallmentTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectInstallmentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CollectInstallmentTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectInstallmentTx indicates an expected call of CollectInstallmentTx.
+func (mr *MockStoreMockRecorder) CollectInstallmentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectInstallmentTx", reflect.TypeOf((*MockStore)(nil).CollectInstallmentTx), arg0, arg1)
+}
+
+// CompletePayoutBatch mocks base method.
+func (m *MockStore) CompletePayoutBatch(arg0 context.Context, arg1 db.CompletePayoutBatchParams) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompletePayoutBatch", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompletePayoutBatch indicates an expected call of CompletePayoutBatch.
+func (mr *MockStoreMockRecorder) CompletePayoutBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletePayoutBatch", reflect.TypeOf((*MockStore)(nil).CompletePayoutBatch), arg0, arg1)
+}
+
+// ConfirmEscrowTx mocks base method.
+func (m *MockStore) ConfirmEscrowTx(arg0 context.Context, arg1 int64) (db.EscrowTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEscrowTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEscrowTx indicates an expected call of ConfirmEscrowTx.
+func (mr *MockStoreMockRecorder) ConfirmEscrowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEscrowTx", reflect.TypeOf((*MockStore)(nil).ConfirmEscrowTx), arg0, arg1)
+}
+
+// CountLateInstallments mocks base method.
+func (m *MockStore) CountLateInstallments(arg0 context.Context, arg1 int64) (db.CountLateInstallmentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLateInstallments", arg0, arg1)
+	ret0, _ := ret[0].(db.CountLateInstallmentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLateInstallments indicates an expected call of CountLateInstallments.
+func (mr *MockStoreMockRecorder) CountLateInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLateInstallments", reflect.TypeOf((*MockStore)(nil).CountLateInstallments), arg0, arg1)
+}
+
+// CountMerchantTransfersSince mocks base method.
+func (m *MockStore) CountMerchantTransfersSince(arg0 context.Context, arg1 db.CountMerchantTransfersSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMerchantTransfersSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMerchantTransfersSince indicates an expected call of CountMerchantTransfersSince.
+func (mr *MockStoreMockRecorder) CountMerchantTransfersSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMerchantTransfersSince", reflect.TypeOf((*MockStore)(nil).CountMerchantTransfersSince), arg0, arg1)
+}
+
+// CountOtherRecipients mocks base method.
+func (m *MockStore) CountOtherRecipients(arg0 context.Context, arg1 db.CountOtherRecipientsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOtherRecipients", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOtherRecipients indicates an expected call of CountOtherRecipients.
+func (mr *MockStoreMockRecorder) CountOtherRecipients(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOtherRecipients", reflect.TypeOf((*MockStore)(nil).CountOtherRecipients), arg0, arg1)
+}
+
+// CountRoundTransfers mocks base method.
+func (m *MockStore) CountRoundTransfers(arg0 context.Context, arg1 db.CountRoundTransfersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRoundTransfers", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRoundTransfers indicates an expected call of CountRoundTransfers.
+func (mr *MockStoreMockRecorder) CountRoundTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRoundTransfers", reflect.TypeOf((*MockStore)(nil).CountRoundTransfers), arg0, arg1)
+}
+
+// CountTransfersBetween mocks base method.
+func (m *MockStore) CountTransfersBetween(arg0 context.Context, arg1 db.CountTransfersBetweenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersBetween", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersBetween indicates an expected call of CountTransfersBetween.
+func (mr *MockStoreMockRecorder) CountTransfersBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersBetween", reflect.TypeOf((*MockStore)(nil).CountTransfersBetween), arg0, arg1)
+}
+
+// CountUnbalancedJournals mocks base method.
+func (m *MockStore) CountUnbalancedJournals(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnbalancedJournals", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnbalancedJournals indicates an expected call of CountUnbalancedJournals.
+func (mr *MockStoreMockRecorder) CountUnbalancedJournals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnbalancedJournals", reflect.TypeOf((*MockStore)(nil).CountUnbalancedJournals), arg0)
+}
+
+// CountWalletLedgerMismatches mocks base method.
+func (m *MockStore) CountWalletLedgerMismatches(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWalletLedgerMismatches", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWalletLedgerMismatches indicates an expected call of CountWalletLedgerMismatches.
+func (mr *MockStoreMockRecorder) CountWalletLedgerMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWalletLedgerMismatches", reflect.TypeOf((*MockStore)(nil).CountWalletLedgerMismatches), arg0)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockStore) CreateApiKey(arg0 context.Context, arg1 db.CreateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockStoreMockRecorder) CreateApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockStore)(nil).CreateApiKey), arg0, arg1)
+}
+
+// CreateApiKeyNonce mocks base method.
+func (m *MockStore) CreateApiKeyNonce(arg0 context.Context, arg1 db.CreateApiKeyNonceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKeyNonce", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKeyNonce indicates an expected call of CreateApiKeyNonce.
+func (mr *MockStoreMockRecorder) CreateApiKeyNonce(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKeyNonce", reflect.TypeOf((*MockStore)(nil).CreateApiKeyNonce), arg0, arg1)
+}
+
+// CreateCashbackCampaign mocks base method.
+func (m *MockStore) CreateCashbackCampaign(arg0 context.Context, arg1 db.CreateCashbackCampaignParams) (db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCashbackCampaign", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCashbackCampaign indicates an expected call of CreateCashbackCampaign.
+func (mr *MockStoreMockRecorder) CreateCashbackCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCashbackCampaign", reflect.TypeOf((*MockStore)(nil).CreateCashbackCampaign), arg0, arg1)
+}
+
+// CreateCashbackGrant mocks base method.
+func (m *MockStore) CreateCashbackGrant(arg0 context.Context, arg1 db.CreateCashbackGrantParams) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCashbackGrant indicates an expected call of CreateCashbackGrant.
+func (mr *MockStoreMockRecorder) CreateCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCashbackGrant", reflect.TypeOf((*MockStore)(nil).CreateCashbackGrant), arg0, arg1)
+}
+
+// CreateChainCheckpoint mocks base method.
+func (m *MockStore) CreateChainCheckpoint(arg0 context.Context, arg1 db.CreateChainCheckpointParams) (db.ChainCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChainCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.ChainCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChainCheckpoint indicates an expected call of CreateChainCheckpoint.
+func (mr *MockStoreMockRecorder) CreateChainCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChainCheckpoint", reflect.TypeOf((*MockStore)(nil).CreateChainCheckpoint), arg0, arg1)
+}
+
+// CreateChainCheckpointTx mocks base method.
+func (m *MockStore) CreateChainCheckpointTx(arg0 context.Context, arg1 db.CreateChainCheckpointTxParams) (db.ChainCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChainCheckpointTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChainCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChainCheckpointTx indicates an expected call of CreateChainCheckpointTx.
+func (mr *MockStoreMockRecorder) CreateChainCheckpointTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChainCheckpointTx", reflect.TypeOf((*MockStore)(nil).CreateChainCheckpointTx), arg0, arg1)
+}
+
+// CreateCheckoutSession mocks base method.
+func (m *MockStore) CreateCheckoutSession(arg0 context.Context, arg1 db.CreateCheckoutSessionParams) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckoutSession", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCheckoutSession indicates an expected call of CreateCheckoutSession.
+func (mr *MockStoreMockRecorder) CreateCheckoutSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckoutSession", reflect.TypeOf((*MockStore)(nil).CreateCheckoutSession), arg0, arg1)
+}
+
+// CreateDispute mocks base method.
+func (m *MockStore) CreateDispute(arg0 context.Context, arg1 db.CreateDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDispute indicates an expected call of CreateDispute.
+func (mr *MockStoreMockRecorder) CreateDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDispute", reflect.TypeOf((*MockStore)(nil).CreateDispute), arg0, arg1)
+}
+
+// CreateDisputeEvidence mocks base method.
+func (m *MockStore) CreateDisputeEvidence(arg0 context.Context, arg1 db.CreateDisputeEvidenceParams) (db.DisputeEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDisputeEvidence", arg0, arg1)
+	ret0, _ := ret[0].(db.DisputeEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDisputeEvidence indicates an expected call of CreateDisputeEvidence.
+func (mr *MockStoreMockRecorder) CreateDisputeEvidence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDisputeEvidence", reflect.TypeOf((*MockStore)(nil).CreateDisputeEvidence), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateEscrow mocks base method.
+func (m *MockStore) CreateEscrow(arg0 context.Context, arg1 db.CreateEscrowParams) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscrow", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEscrow indicates an expected call of CreateEscrow.
+func (mr *MockStoreMockRecorder) CreateEscrow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrow", reflect.TypeOf((*MockStore)(nil).CreateEscrow), arg0, arg1)
+}
+
+// CreateEscrowTx mocks base method.
+func (m *MockStore) CreateEscrowTx(arg0 context.Context, arg1 db.CreateEscrowTxParams) (db.CreateEscrowTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscrowTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateEscrowTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEscrowTx indicates an expected call of CreateEscrowTx.
+func (mr *MockStoreMockRecorder) CreateEscrowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrowTx", reflect.TypeOf((*MockStore)(nil).CreateEscrowTx), arg0, arg1)
+}
+
+// CreateFeeEntry mocks base method.
+func (m *MockStore) CreateFeeEntry(arg0 context.Context, arg1 db.CreateFeeEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeeEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeeEntry indicates an expected call of CreateFeeEntry.
+func (mr *MockStoreMockRecorder) CreateFeeEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeeEntry", reflect.TypeOf((*MockStore)(nil).CreateFeeEntry), arg0, arg1)
+}
+
+// CreateFeeSchedule mocks base method.
+func (m *MockStore) CreateFeeSchedule(arg0 context.Context, arg1 db.CreateFeeScheduleParams) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeeSchedule", arg0, arg1)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeeSchedule indicates an expected call of CreateFeeSchedule.
+func (mr *MockStoreMockRecorder) CreateFeeSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeeSchedule", reflect.TypeOf((*MockStore)(nil).CreateFeeSchedule), arg0, arg1)
+}
+
+// CreateHold mocks base method.
+func (m *MockStore) CreateHold(arg0 context.Context, arg1 db.CreateHoldParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockStoreMockRecorder) CreateHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockStore)(nil).CreateHold), arg0, arg1)
+}
+
+// CreateHoldTx mocks base method.
+func (m *MockStore) CreateHoldTx(arg0 context.Context, arg1 db.CreateHoldTxParams) (db.CreateHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHoldTx indicates an expected call of CreateHoldTx.
+func (mr *MockStoreMockRecorder) CreateHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHoldTx", reflect.TypeOf((*MockStore)(nil).CreateHoldTx), arg0, arg1)
+}
+
+// CreateInstallment mocks base method.
+func (m *MockStore) CreateInstallment(arg0 context.Context, arg1 db.CreateInstallmentParams) (db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstallment indicates an expected call of CreateInstallment.
+func (mr *MockStoreMockRecorder) CreateInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallment", reflect.TypeOf((*MockStore)(nil).CreateInstallment), arg0, arg1)
+}
+
+// CreateInstallmentPlan mocks base method.
+func (m *MockStore) CreateInstallmentPlan(arg0 context.Context, arg1 db.CreateInstallmentPlanParams) (db.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstallmentPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstallmentPlan indicates an expected call of CreateInstallmentPlan.
+func (mr *MockStoreMockRecorder) CreateInstallmentPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallmentPlan", reflect.TypeOf((*MockStore)(nil).CreateInstallmentPlan), arg0, arg1)
+}
+
+// CreateInstallmentPlanTx mocks base method.
+func (m *MockStore) CreateInstallmentPlanTx(arg0 context.Context, arg1 db.CreateInstallmentPlanTxParams) (db.CreateInstallmentPlanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstallmentPlanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateInstallmentPlanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstallmentPlanTx indicates an expected call of CreateInstallmentPlanTx.
+func (mr *MockStoreMockRecorder) CreateInstallmentPlanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallmentPlanTx", reflect.TypeOf((*MockStore)(nil).CreateInstallmentPlanTx), arg0, arg1)
+}
+
+// CreateInvoice mocks base method.
+func (m *MockStore) CreateInvoice(arg0 context.Context, arg1 db.CreateInvoiceParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvoice indicates an expected call of CreateInvoice.
+func (mr *MockStoreMockRecorder) CreateInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoice", reflect.TypeOf((*MockStore)(nil).CreateInvoice), arg0, arg1)
+}
+
+// CreateInvoiceItem mocks base method.
+func (m *MockStore) CreateInvoiceItem(arg0 context.Context, arg1 db.CreateInvoiceItemParams) (db.InvoiceItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvoiceItem", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoiceItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvoiceItem indicates an expected call of CreateInvoiceItem.
+func (mr *MockStoreMockRecorder) CreateInvoiceItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoiceItem", reflect.TypeOf((*MockStore)(nil).CreateInvoiceItem), arg0, arg1)
+}
+
+// CreateInvoiceTx mocks base method.
+func (m *MockStore) CreateInvoiceTx(arg0 context.Context, arg1 db.CreateInvoiceTxParams) (db.InvoiceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvoiceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoiceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvoiceTx indicates an expected call of CreateInvoiceTx.
+func (mr *MockStoreMockRecorder) CreateInvoiceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoiceTx", reflect.TypeOf((*MockStore)(nil).CreateInvoiceTx), arg0, arg1)
+}
+
+// CreateJournal mocks base method.
+func (m *MockStore) CreateJournal(arg0 context.Context, arg1 db.CreateJournalParams) (db.Journal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.Journal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJournal indicates an expected call of CreateJournal.
+func (mr *MockStoreMockRecorder) CreateJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournal", reflect.TypeOf((*MockStore)(nil).CreateJournal), arg0, arg1)
+}
+
+// CreateLedgerAccount mocks base method.
+func (m *MockStore) CreateLedgerAccount(arg0 context.Context, arg1 db.CreateLedgerAccountParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLedgerAccount indicates an expected call of CreateLedgerAccount.
+func (mr *MockStoreMockRecorder) CreateLedgerAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerAccount", reflect.TypeOf((*MockStore)(nil).CreateLedgerAccount), arg0, arg1)
+}
+
+// CreateLimitIncreaseRequest mocks base method.
+func (m *MockStore) CreateLimitIncreaseRequest(arg0 context.Context, arg1 db.CreateLimitIncreaseRequestParams) (db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLimitIncreaseRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLimitIncreaseRequest indicates an expected call of CreateLimitIncreaseRequest.
+func (mr *MockStoreMockRecorder) CreateLimitIncreaseRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLimitIncreaseRequest", reflect.TypeOf((*MockStore)(nil).CreateLimitIncreaseRequest), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreatePaymentLink mocks base method.
+func (m *MockStore) CreatePaymentLink(arg0 context.Context, arg1 db.CreatePaymentLinkParams) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentLink", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentLink indicates an expected call of CreatePaymentLink.
+func (mr *MockStoreMockRecorder) CreatePaymentLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentLink", reflect.TypeOf((*MockStore)(nil).CreatePaymentLink), arg0, arg1)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockStore) CreatePaymentRequest(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockStoreMockRecorder) CreatePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequest), arg0, arg1)
+}
+
+// CreatePayoutBatch mocks base method.
+func (m *MockStore) CreatePayoutBatch(arg0 context.Context, arg1 db.CreatePayoutBatchParams) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayoutBatch", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayoutBatch indicates an expected call of CreatePayoutBatch.
+func (mr *MockStoreMockRecorder) CreatePayoutBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayoutBatch", reflect.TypeOf((*MockStore)(nil).CreatePayoutBatch), arg0, arg1)
+}
+
+// CreatePayoutBatchTx mocks base method.
+func (m *MockStore) CreatePayoutBatchTx(arg0 context.Context, arg1 db.CreatePayoutBatchTxParams) (db.CreatePayoutBatchTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayoutBatchTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreatePayoutBatchTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayoutBatchTx indicates an expected call of CreatePayoutBatchTx.
+func (mr *MockStoreMockRecorder) CreatePayoutBatchTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayoutBatchTx", reflect.TypeOf((*MockStore)(nil).CreatePayoutBatchTx), arg0, arg1)
+}
+
+// CreatePayoutRow mocks base method.
+func (m *MockStore) CreatePayoutRow(arg0 context.Context, arg1 db.CreatePayoutRowParams) (db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayoutRow", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayoutRow indicates an expected call of CreatePayoutRow.
+func (mr *MockStoreMockRecorder) CreatePayoutRow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayoutRow", reflect.TypeOf((*MockStore)(nil).CreatePayoutRow), arg0, arg1)
+}
+
+// CreatePosting mocks base method.
+func (m *MockStore) CreatePosting(arg0 context.Context, arg1 db.CreatePostingParams) (db.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePosting", arg0, arg1)
+	ret0, _ := ret[0].(db.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePosting indicates an expected call of CreatePosting.
+func (mr *MockStoreMockRecorder) CreatePosting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePosting", reflect.TypeOf((*MockStore)(nil).CreatePosting), arg0, arg1)
+}
+
+// CreateReceivable mocks base method.
+func (m *MockStore) CreateReceivable(arg0 context.Context, arg1 db.CreateReceivableParams) (db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReceivable", arg0, arg1)
+	ret0, _ := ret[0].(db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReceivable indicates an expected call of CreateReceivable.
+func (mr *MockStoreMockRecorder) CreateReceivable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReceivable", reflect.TypeOf((*MockStore)(nil).CreateReceivable), arg0, arg1)
+}
+
+// CreateRefund mocks base method.
+func (m *MockStore) CreateRefund(arg0 context.Context, arg1 db.CreateRefundParams) (db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockStoreMockRecorder) CreateRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockStore)(nil).CreateRefund), arg0, arg1)
+}
+
+// CreateReserve mocks base method.
+func (m *MockStore) CreateReserve(arg0 context.Context, arg1 db.CreateReserveParams) (db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReserve", arg0, arg1)
+	ret0, _ := ret[0].(db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReserve indicates an expected call of CreateReserve.
+func (mr *MockStoreMockRecorder) CreateReserve(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReserve", reflect.TypeOf((*MockStore)(nil).CreateReserve), arg0, arg1)
+}
+
+// CreateRiskDecision mocks base method.
+func (m *MockStore) CreateRiskDecision(arg0 context.Context, arg1 db.CreateRiskDecisionParams) (db.RiskDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRiskDecision", arg0, arg1)
+	ret0, _ := ret[0].(db.RiskDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRiskDecision indicates an expected call of CreateRiskDecision.
+func (mr *MockStoreMockRecorder) CreateRiskDecision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRiskDecision", reflect.TypeOf((*MockStore)(nil).CreateRiskDecision), arg0, arg1)
+}
+
+// CreateScheduledTransfer mocks base method.
+func (m *MockStore) CreateScheduledTransfer(arg0 context.Context, arg1 db.CreateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransfer indicates an expected call of CreateScheduledTransfer.
+func (mr *MockStoreMockRecorder) CreateScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransfer", reflect.TypeOf((*MockStore)(nil).CreateScheduledTransfer), arg0, arg1)
+}
+
+// CreateScheduledTransferRun mocks base method.
+func (m *MockStore) CreateScheduledTransferRun(arg0 context.Context, arg1 db.CreateScheduledTransferRunParams) (db.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransferRun", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransferRun indicates an expected call of CreateScheduledTransferRun.
+func (mr *MockStoreMockRecorder) CreateScheduledTransferRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransferRun", reflect.TypeOf((*MockStore)(nil).CreateScheduledTransferRun), arg0, arg1)
+}
+
+// CreateSplitTransfer mocks base method.
+func (m *MockStore) CreateSplitTransfer(arg0 context.Context, arg1 db.CreateSplitTransferParams) (db.SplitTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSplitTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.SplitTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSplitTransfer indicates an expected call of CreateSplitTransfer.
+func (mr *MockStoreMockRecorder) CreateSplitTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSplitTransfer", reflect.TypeOf((*MockStore)(nil).CreateSplitTransfer), arg0, arg1)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockStore) CreateSubscription(arg0 context.Context, arg1 db.CreateSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockStoreMockRecorder) CreateSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockStore)(nil).CreateSubscription), arg0, arg1)
+}
+
+// CreateSubscriptionCharge mocks base method.
+func (m *MockStore) CreateSubscriptionCharge(arg0 context.Context, arg1 db.CreateSubscriptionChargeParams) (db.SubscriptionCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscriptionCharge", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscriptionCharge indicates an expected call of CreateSubscriptionCharge.
+func (mr *MockStoreMockRecorder) CreateSubscriptionCharge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscriptionCharge", reflect.TypeOf((*MockStore)(nil).CreateSubscriptionCharge), arg0, arg1)
+}
+
+// CreateSubscriptionPlan mocks base method.
+func (m *MockStore) CreateSubscriptionPlan(arg0 context.Context, arg1 db.CreateSubscriptionPlanParams) (db.SubscriptionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscriptionPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscriptionPlan indicates an expected call of CreateSubscriptionPlan.
+func (mr *MockStoreMockRecorder) CreateSubscriptionPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscriptionPlan", reflect.TypeOf((*MockStore)(nil).CreateSubscriptionPlan), arg0, arg1)
+}
+
+// CreateSubscriptionTx mocks base method.
+func (m *MockStore) CreateSubscriptionTx(arg0 context.Context, arg1 db.CreateSubscriptionTxParams) (db.CreateSubscriptionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscriptionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateSubscriptionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscriptionTx indicates an expected call of CreateSubscriptionTx.
+func (mr *MockStoreMockRecorder) CreateSubscriptionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscriptionTx", reflect.TypeOf((*MockStore)(nil).CreateSubscriptionTx), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferReview mocks base method.
+func (m *MockStore) CreateTransferReview(arg0 context.Context, arg1 db.CreateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReview indicates an expected call of CreateTransferReview.
+func (mr *MockStoreMockRecorder) CreateTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReview", reflect.TypeOf((*MockStore)(nil).CreateTransferReview), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWallet mocks base method.
+func (m *MockStore) CreateWallet(arg0 context.Context, arg1 db.CreateWalletParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockStoreMockRecorder) CreateWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockStore)(nil).CreateWallet), arg0, arg1)
+}
+
+// CreateWalletTx mocks base method.
+func (m *MockStore) CreateWalletTx(arg0 context.Context, arg1 db.CreateWalletTxParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWalletTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWalletTx indicates an expected call of CreateWalletTx.
+func (mr *MockStoreMockRecorder) CreateWalletTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWalletTx", reflect.TypeOf((*MockStore)(nil).CreateWalletTx), arg0, arg1)
+}
+
+// CreateWebhookDeliveries mocks base method.
+func (m *MockStore) CreateWebhookDeliveries(arg0 context.Context, arg1 db.CreateWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeliveries indicates an expected call of CreateWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveries), arg0, arg1)
+}
+
+// CreateWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) CreateWebhookDeliveryAttempt(arg0 context.Context, arg1 db.CreateWebhookDeliveryAttemptParams) (db.WebhookDeliveryAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveryAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDeliveryAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeliveryAttempt indicates an expected call of CreateWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveryAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveryAttempt), arg0, arg1)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(arg0 context.Context, arg1 db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), arg0, arg1)
+}
+
+// CreateWebhookEvent mocks base method.
+func (m *MockStore) CreateWebhookEvent(arg0 context.Context, arg1 db.CreateWebhookEventParams) (db.WebhookEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEvent indicates an expected call of CreateWebhookEvent.
+func (mr *MockStoreMockRecorder) CreateWebhookEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEvent", reflect.TypeOf((*MockStore)(nil).CreateWebhookEvent), arg0, arg1)
+}
+
+// DeactivateCashbackCampaign mocks base method.
+func (m *MockStore) DeactivateCashbackCampaign(arg0 context.Context, arg1 int64) (db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateCashbackCampaign", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateCashbackCampaign indicates an expected call of DeactivateCashbackCampaign.
+func (mr *MockStoreMockRecorder) DeactivateCashbackCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateCashbackCampaign", reflect.TypeOf((*MockStore)(nil).DeactivateCashbackCampaign), arg0, arg1)
+}
+
+// DeactivatePaymentLink mocks base method.
+func (m *MockStore) DeactivatePaymentLink(arg0 context.Context, arg1 int64) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivatePaymentLink", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivatePaymentLink indicates an expected call of DeactivatePaymentLink.
+func (mr *MockStoreMockRecorder) DeactivatePaymentLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivatePaymentLink", reflect.TypeOf((*MockStore)(nil).DeactivatePaymentLink), arg0, arg1)
+}
+
+// DeactivateSubscriptionPlan mocks base method.
+func (m *MockStore) DeactivateSubscriptionPlan(arg0 context.Context, arg1 int64) (db.SubscriptionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateSubscriptionPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateSubscriptionPlan indicates an expected call of DeactivateSubscriptionPlan.
+func (mr *MockStoreMockRecorder) DeactivateSubscriptionPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateSubscriptionPlan", reflect.TypeOf((*MockStore)(nil).DeactivateSubscriptionPlan), arg0, arg1)
+}
+
+// DecideDispute mocks base method.
+func (m *MockStore) DecideDispute(arg0 context.Context, arg1 db.DecideDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideDispute indicates an expected call of DecideDispute.
+func (mr *MockStoreMockRecorder) DecideDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideDispute", reflect.TypeOf((*MockStore)(nil).DecideDispute), arg0, arg1)
+}
+
+// DecideDisputeTx mocks base method.
+func (m *MockStore) DecideDisputeTx(arg0 context.Context, arg1 db.DecideDisputeTxParams) (db.DecideDisputeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideDisputeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DecideDisputeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideDisputeTx indicates an expected call of DecideDisputeTx.
+func (mr *MockStoreMockRecorder) DecideDisputeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideDisputeTx", reflect.TypeOf((*MockStore)(nil).DecideDisputeTx), arg0, arg1)
+}
+
+// DecideLimitIncreaseRequest mocks base method.
+func (m *MockStore) DecideLimitIncreaseRequest(arg0 context.Context, arg1 db.DecideLimitIncreaseRequestParams) (db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideLimitIncreaseRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideLimitIncreaseRequest indicates an expected call of DecideLimitIncreaseRequest.
+func (mr *MockStoreMockRecorder) DecideLimitIncreaseRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideLimitIncreaseRequest", reflect.TypeOf((*MockStore)(nil).DecideLimitIncreaseRequest), arg0, arg1)
+}
+
+// DecidePaymentRequest mocks base method.
+func (m *MockStore) DecidePaymentRequest(arg0 context.Context, arg1 db.DecidePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecidePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecidePaymentRequest indicates an expected call of DecidePaymentRequest.
+func (mr *MockStoreMockRecorder) DecidePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecidePaymentRequest", reflect.TypeOf((*MockStore)(nil).DecidePaymentRequest), arg0, arg1)
+}
+
+// DecidePaymentRequestTx mocks base method.
+func (m *MockStore) DecidePaymentRequestTx(arg0 context.Context, arg1 db.DecidePaymentRequestTxParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecidePaymentRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecidePaymentRequestTx indicates an expected call of DecidePaymentRequestTx.
+func (mr *MockStoreMockRecorder) DecidePaymentRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecidePaymentRequestTx", reflect.TypeOf((*MockStore)(nil).DecidePaymentRequestTx), arg0, arg1)
+}
+
+// DecideReviewTx mocks base method.
+func (m *MockStore) DecideReviewTx(arg0 context.Context, arg1 db.DecideReviewTxParams) (db.DecideReviewTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DecideReviewTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideReviewTx indicates an expected call of DecideReviewTx.
+func (mr *MockStoreMockRecorder) DecideReviewTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideReviewTx", reflect.TypeOf((*MockStore)(nil).DecideReviewTx), arg0, arg1)
+}
+
+// DecideTransferReview mocks base method.
+func (m *MockStore) DecideTransferReview(arg0 context.Context, arg1 db.DecideTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferReview indicates an expected call of DecideTransferReview.
+func (mr *MockStoreMockRecorder) DecideTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferReview", reflect.TypeOf((*MockStore)(nil).DecideTransferReview), arg0, arg1)
+}
+
+// DeductReceivable mocks base method.
+func (m *MockStore) DeductReceivable(arg0 context.Context, arg1 db.DeductReceivableParams) (db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeductReceivable", arg0, arg1)
+	ret0, _ := ret[0].(db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeductReceivable indicates an expected call of DeductReceivable.
+func (mr *MockStoreMockRecorder) DeductReceivable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeductReceivable", reflect.TypeOf((*MockStore)(nil).DeductReceivable), arg0, arg1)
+}
+
+// DeleteExpiredApiKeyNonces mocks base method.
+func (m *MockStore) DeleteExpiredApiKeyNonces(arg0 context.Context, arg1 db.DeleteExpiredApiKeyNoncesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredApiKeyNonces", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredApiKeyNonces indicates an expected call of DeleteExpiredApiKeyNonces.
+func (mr *MockStoreMockRecorder) DeleteExpiredApiKeyNonces(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredApiKeyNonces", reflect.TypeOf((*MockStore)(nil).DeleteExpiredApiKeyNonces), arg0, arg1)
+}
+
+// DeleteFutureFeeSchedule mocks base method.
+func (m *MockStore) DeleteFutureFeeSchedule(arg0 context.Context, arg1 db.DeleteFutureFeeScheduleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFutureFeeSchedule", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFutureFeeSchedule indicates an expected call of DeleteFutureFeeSchedule.
+func (mr *MockStoreMockRecorder) DeleteFutureFeeSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFutureFeeSchedule", reflect.TypeOf((*MockStore)(nil).DeleteFutureFeeSchedule), arg0, arg1)
+}
+
+// DeleteInstallmentFundingOptIn mocks base method.
+func (m *MockStore) DeleteInstallmentFundingOptIn(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstallmentFundingOptIn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstallmentFundingOptIn indicates an expected call of DeleteInstallmentFundingOptIn.
+func (mr *MockStoreMockRecorder) DeleteInstallmentFundingOptIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstallmentFundingOptIn", reflect.TypeOf((*MockStore)(nil).DeleteInstallmentFundingOptIn), arg0, arg1)
+}
+
+// DeleteInvoiceItems mocks base method.
+func (m *MockStore) DeleteInvoiceItems(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvoiceItems", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInvoiceItems indicates an expected call of DeleteInvoiceItems.
+func (mr *MockStoreMockRecorder) DeleteInvoiceItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvoiceItems", reflect.TypeOf((*MockStore)(nil).DeleteInvoiceItems), arg0, arg1)
+}
+
+// DeleteReserveRule mocks base method.
+func (m *MockStore) DeleteReserveRule(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReserveRule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReserveRule indicates an expected call of DeleteReserveRule.
+func (mr *MockStoreMockRecorder) DeleteReserveRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReserveRule", reflect.TypeOf((*MockStore)(nil).DeleteReserveRule), arg0, arg1)
+}
+
+// DeleteSettlementPlan mocks base method.
+func (m *MockStore) DeleteSettlementPlan(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSettlementPlan", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSettlementPlan indicates an expected call of DeleteSettlementPlan.
+func (mr *MockStoreMockRecorder) DeleteSettlementPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSettlementPlan", reflect.TypeOf((*MockStore)(nil).DeleteSettlementPlan), arg0, arg1)
+}
+
+// DeleteStaleRateLimitBuckets mocks base method.
+func (m *MockStore) DeleteStaleRateLimitBuckets(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleRateLimitBuckets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleRateLimitBuckets indicates an expected call of DeleteStaleRateLimitBuckets.
+func (mr *MockStoreMockRecorder) DeleteStaleRateLimitBuckets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleRateLimitBuckets", reflect.TypeOf((*MockStore)(nil).DeleteStaleRateLimitBuckets), arg0, arg1)
+}
+
+// DeleteTransferLimits mocks base method.
+func (m *MockStore) DeleteTransferLimits(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransferLimits indicates an expected call of DeleteTransferLimits.
+func (mr *MockStoreMockRecorder) DeleteTransferLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransferLimits", reflect.TypeOf((*MockStore)(nil).DeleteTransferLimits), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteWallet mocks base method.
+func (m *MockStore) DeleteWallet(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWallet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWallet indicates an expected call of DeleteWallet.
+func (mr *MockStoreMockRecorder) DeleteWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWallet", reflect.TypeOf((*MockStore)(nil).DeleteWallet), arg0, arg1)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStore) DeleteWebhookEndpoint(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebhookEndpoint), arg0, arg1)
+}
+
+// DisableWebhookEndpoint mocks base method.
+func (m *MockStore) DisableWebhookEndpoint(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableWebhookEndpoint indicates an expected call of DisableWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DisableWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DisableWebhookEndpoint), arg0, arg1)
+}
+
+// DisputeEscrow mocks base method.
+func (m *MockStore) DisputeEscrow(arg0 context.Context, arg1 db.DisputeEscrowParams) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrow", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrow indicates an expected call of DisputeEscrow.
+func (mr *MockStoreMockRecorder) DisputeEscrow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrow", reflect.TypeOf((*MockStore)(nil).DisputeEscrow), arg0, arg1)
+}
+
+// DisputeEscrowTx mocks base method.
+func (m *MockStore) DisputeEscrowTx(arg0 context.Context, arg1 db.DisputeEscrowTxParams) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrowTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrowTx indicates an expected call of DisputeEscrowTx.
+func (mr *MockStoreMockRecorder) DisputeEscrowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrowTx", reflect.TypeOf((*MockStore)(nil).DisputeEscrowTx), arg0, arg1)
+}
+
+// EnableWebhookEndpoint mocks base method.
+func (m *MockStore) EnableWebhookEndpoint(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableWebhookEndpoint indicates an expected call of EnableWebhookEndpoint.
+func (mr *MockStoreMockRecorder) EnableWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).EnableWebhookEndpoint), arg0, arg1)
+}
+
+// EscalateOverdueDisputesTx mocks base method.
+func (m *MockStore) EscalateOverdueDisputesTx(arg0 context.Context, arg1 db.EscalateOverdueDisputesTxParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateOverdueDisputesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateOverdueDisputesTx indicates an expected call of EscalateOverdueDisputesTx.
+func (mr *MockStoreMockRecorder) EscalateOverdueDisputesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateOverdueDisputesTx", reflect.TypeOf((*MockStore)(nil).EscalateOverdueDisputesTx), arg0, arg1)
+}
+
+// ExecutePayoutRowTx mocks base method.
+func (m *MockStore) ExecutePayoutRowTx(arg0 context.Context, arg1 db.ExecutePayoutRowTxParams) (db.ExecutePayoutRowTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecutePayoutRowTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExecutePayoutRowTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecutePayoutRowTx indicates an expected call of ExecutePayoutRowTx.
+func (mr *MockStoreMockRecorder) ExecutePayoutRowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecutePayoutRowTx", reflect.TypeOf((*MockStore)(nil).ExecutePayoutRowTx), arg0, arg1)
+}
+
+// ExecuteScheduledTransferTx mocks base method.
+func (m *MockStore) ExecuteScheduledTransferTx(arg0 context.Context, arg1 db.ExecuteScheduledTransferTxParams) (db.ExecuteScheduledTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScheduledTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExecuteScheduledTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteScheduledTransferTx indicates an expected call of ExecuteScheduledTransferTx.
+func (mr *MockStoreMockRecorder) ExecuteScheduledTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScheduledTransferTx", reflect.TypeOf((*MockStore)(nil).ExecuteScheduledTransferTx), arg0, arg1)
+}
+
+// ExpireCheckoutSession mocks base method.
+func (m *MockStore) ExpireCheckoutSession(arg0 context.Context, arg1 int64) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireCheckoutSession", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireCheckoutSession indicates an expected call of ExpireCheckoutSession.
+func (mr *MockStoreMockRecorder) ExpireCheckoutSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireCheckoutSession", reflect.TypeOf((*MockStore)(nil).ExpireCheckoutSession), arg0, arg1)
+}
+
+// ExpireCheckoutSessionsTx mocks base method.
+func (m *MockStore) ExpireCheckoutSessionsTx(arg0 context.Context, arg1 db.ExpireCheckoutSessionsTxParams) ([]db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireCheckoutSessionsTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireCheckoutSessionsTx indicates an expected call of ExpireCheckoutSessionsTx.
+func (mr *MockStoreMockRecorder) ExpireCheckoutSessionsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireCheckoutSessionsTx", reflect.TypeOf((*MockStore)(nil).ExpireCheckoutSessionsTx), arg0, arg1)
+}
+
+// ExpireDueCheckoutSessions mocks base method.
+func (m *MockStore) ExpireDueCheckoutSessions(arg0 context.Context, arg1 db.ExpireDueCheckoutSessionsParams) ([]db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireDueCheckoutSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireDueCheckoutSessions indicates an expected call of ExpireDueCheckoutSessions.
+func (mr *MockStoreMockRecorder) ExpireDueCheckoutSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireDueCheckoutSessions", reflect.TypeOf((*MockStore)(nil).ExpireDueCheckoutSessions), arg0, arg1)
+}
+
+// ExpireHoldsTx mocks base method.
+func (m *MockStore) ExpireHoldsTx(arg0 context.Context, arg1 db.ExpireHoldsTxParams) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHoldsTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHoldsTx indicates an expected call of ExpireHoldsTx.
+func (mr *MockStoreMockRecorder) ExpireHoldsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHoldsTx", reflect.TypeOf((*MockStore)(nil).ExpireHoldsTx), arg0, arg1)
+}
+
+// ExpirePaymentRequest mocks base method.
+func (m *MockStore) ExpirePaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequest indicates an expected call of ExpirePaymentRequest.
+func (mr *MockStoreMockRecorder) ExpirePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequest", reflect.TypeOf((*MockStore)(nil).ExpirePaymentRequest), arg0, arg1)
+}
+
+// FailInstallment mocks base method.
+func (m *MockStore) FailInstallment(arg0 context.Context, arg1 db.FailInstallmentParams) (db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailInstallment indicates an expected call of FailInstallment.
+func (mr *MockStoreMockRecorder) FailInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailInstallment", reflect.TypeOf((*MockStore)(nil).FailInstallment), arg0, arg1)
+}
+
+// FailSubscriptionCharge mocks base method.
+func (m *MockStore) FailSubscriptionCharge(arg0 context.Context, arg1 db.FailSubscriptionChargeParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailSubscriptionCharge", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailSubscriptionCharge indicates an expected call of FailSubscriptionCharge.
+func (mr *MockStoreMockRecorder) FailSubscriptionCharge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailSubscriptionCharge", reflect.TypeOf((*MockStore)(nil).FailSubscriptionCharge), arg0, arg1)
+}
+
+// FinalizeInvoice mocks base method.
+func (m *MockStore) FinalizeInvoice(arg0 context.Context, arg1 db.FinalizeInvoiceParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizeInvoice indicates an expected call of FinalizeInvoice.
+func (mr *MockStoreMockRecorder) FinalizeInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeInvoice", reflect.TypeOf((*MockStore)(nil).FinalizeInvoice), arg0, arg1)
+}
+
+// FinalizeInvoiceTx mocks base method.
+func (m *MockStore) FinalizeInvoiceTx(arg0 context.Context, arg1 db.FinalizeInvoiceTxParams) (db.InvoiceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeInvoiceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoiceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizeInvoiceTx indicates an expected call of FinalizeInvoiceTx.
+func (mr *MockStoreMockRecorder) FinalizeInvoiceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeInvoiceTx", reflect.TypeOf((*MockStore)(nil).FinalizeInvoiceTx), arg0, arg1)
+}
+
+// FreezeWalletTx mocks base method.
+func (m *MockStore) FreezeWalletTx(arg0 context.Context, arg1 db.FreezeWalletTxParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeWalletTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeWalletTx indicates an expected call of FreezeWalletTx.
+func (mr *MockStoreMockRecorder) FreezeWalletTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeWalletTx", reflect.TypeOf((*MockStore)(nil).FreezeWalletTx), arg0, arg1)
+}
+
+// GetActiveFeeSchedule mocks base method.
+func (m *MockStore) GetActiveFeeSchedule(arg0 context.Context, arg1 db.GetActiveFeeScheduleParams) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveFeeSchedule", arg0, arg1)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveFeeSchedule indicates an expected call of GetActiveFeeSchedule.
+func (mr *MockStoreMockRecorder) GetActiveFeeSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveFeeSchedule", reflect.TypeOf((*MockStore)(nil).GetActiveFeeSchedule), arg0, arg1)
+}
+
+// GetApiKey mocks base method.
+func (m *MockStore) GetApiKey(arg0 context.Context, arg1 int64) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKey indicates an expected call of GetApiKey.
+func (mr *MockStoreMockRecorder) GetApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKey", reflect.TypeOf((*MockStore)(nil).GetApiKey), arg0, arg1)
+}
+
+// GetApiKeyByPrefix mocks base method.
+func (m *MockStore) GetApiKeyByPrefix(arg0 context.Context, arg1 string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeyByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeyByPrefix indicates an expected call of GetApiKeyByPrefix.
+func (mr *MockStoreMockRecorder) GetApiKeyByPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeyByPrefix", reflect.TypeOf((*MockStore)(nil).GetApiKeyByPrefix), arg0, arg1)
+}
+
+// GetCashbackCampaign mocks base method.
+func (m *MockStore) GetCashbackCampaign(arg0 context.Context, arg1 int64) (db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCashbackCampaign", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCashbackCampaign indicates an expected call of GetCashbackCampaign.
+func (mr *MockStoreMockRecorder) GetCashbackCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCashbackCampaign", reflect.TypeOf((*MockStore)(nil).GetCashbackCampaign), arg0, arg1)
+}
+
+// GetCashbackCampaignForUpdate mocks base method.
+func (m *MockStore) GetCashbackCampaignForUpdate(arg0 context.Context, arg1 int64) (db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCashbackCampaignForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCashbackCampaignForUpdate indicates an expected call of GetCashbackCampaignForUpdate.
+func (mr *MockStoreMockRecorder) GetCashbackCampaignForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCashbackCampaignForUpdate", reflect.TypeOf((*MockStore)(nil).GetCashbackCampaignForUpdate), arg0, arg1)
+}
+
+// GetCashbackGrant mocks base method.
+func (m *MockStore) GetCashbackGrant(arg0 context.Context, arg1 int64) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCashbackGrant indicates an expected call of GetCashbackGrant.
+func (mr *MockStoreMockRecorder) GetCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCashbackGrant", reflect.TypeOf((*MockStore)(nil).GetCashbackGrant), arg0, arg1)
+}
+
+// GetCashbackGrantByTransferForUpdate mocks base method.
+func (m *MockStore) GetCashbackGrantByTransferForUpdate(arg0 context.Context, arg1 int64) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCashbackGrantByTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCashbackGrantByTransferForUpdate indicates an expected call of GetCashbackGrantByTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetCashbackGrantByTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCashbackGrantByTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetCashbackGrantByTransferForUpdate), arg0, arg1)
+}
+
+// GetCashbackUserTotal mocks base method.
+func (m *MockStore) GetCashbackUserTotal(arg0 context.Context, arg1 db.GetCashbackUserTotalParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCashbackUserTotal", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCashbackUserTotal indicates an expected call of GetCashbackUserTotal.
+func (mr *MockStoreMockRecorder) GetCashbackUserTotal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCashbackUserTotal", reflect.TypeOf((*MockStore)(nil).GetCashbackUserTotal), arg0, arg1)
+}
+
+// GetCheckoutSession mocks base method.
+func (m *MockStore) GetCheckoutSession(arg0 context.Context, arg1 int64) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckoutSession", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckoutSession indicates an expected call of GetCheckoutSession.
+func (mr *MockStoreMockRecorder) GetCheckoutSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckoutSession", reflect.TypeOf((*MockStore)(nil).GetCheckoutSession), arg0, arg1)
+}
+
+// GetCheckoutSessionByReview mocks base method.
+func (m *MockStore) GetCheckoutSessionByReview(arg0 context.Context, arg1 sql.NullInt64) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckoutSessionByReview", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckoutSessionByReview indicates an expected call of GetCheckoutSessionByReview.
+func (mr *MockStoreMockRecorder) GetCheckoutSessionByReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckoutSessionByReview", reflect.TypeOf((*MockStore)(nil).GetCheckoutSessionByReview), arg0, arg1)
+}
+
+// GetCheckoutSessionByToken mocks base method.
+func (m *MockStore) GetCheckoutSessionByToken(arg0 context.Context, arg1 string) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckoutSessionByToken", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckoutSessionByToken indicates an expected call of GetCheckoutSessionByToken.
+func (mr *MockStoreMockRecorder) GetCheckoutSessionByToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckoutSessionByToken", reflect.TypeOf((*MockStore)(nil).GetCheckoutSessionByToken), arg0, arg1)
+}
+
+// GetCheckoutSessionByTokenForUpdate mocks base method.
+func (m *MockStore) GetCheckoutSessionByTokenForUpdate(arg0 context.Context, arg1 string) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckoutSessionByTokenForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckoutSessionByTokenForUpdate indicates an expected call of GetCheckoutSessionByTokenForUpdate.
+func (mr *MockStoreMockRecorder) GetCheckoutSessionByTokenForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckoutSessionByTokenForUpdate", reflect.TypeOf((*MockStore)(nil).GetCheckoutSessionByTokenForUpdate), arg0, arg1)
+}
+
+// GetDispute mocks base method.
+func (m *MockStore) GetDispute(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDispute indicates an expected call of GetDispute.
+func (mr *MockStoreMockRecorder) GetDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDispute", reflect.TypeOf((*MockStore)(nil).GetDispute), arg0, arg1)
+}
+
+// GetDisputeByTransfer mocks base method.
+func (m *MockStore) GetDisputeByTransfer(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeByTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeByTransfer indicates an expected call of GetDisputeByTransfer.
+func (mr *MockStoreMockRecorder) GetDisputeByTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeByTransfer", reflect.TypeOf((*MockStore)(nil).GetDisputeByTransfer), arg0, arg1)
+}
+
+// GetDisputeEvidence mocks base method.
+func (m *MockStore) GetDisputeEvidence(arg0 context.Context, arg1 db.GetDisputeEvidenceParams) (db.DisputeEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeEvidence", arg0, arg1)
+	ret0, _ := ret[0].(db.DisputeEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeEvidence indicates an expected call of GetDisputeEvidence.
+func (mr *MockStoreMockRecorder) GetDisputeEvidence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeEvidence", reflect.TypeOf((*MockStore)(nil).GetDisputeEvidence), arg0, arg1)
+}
+
+// GetDisputeForUpdate mocks base method.
+func (m *MockStore) GetDisputeForUpdate(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeForUpdate indicates an expected call of GetDisputeForUpdate.
+func (mr *MockStoreMockRecorder) GetDisputeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeForUpdate", reflect.TypeOf((*MockStore)(nil).GetDisputeForUpdate), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetEscrow mocks base method.
+func (m *MockStore) GetEscrow(arg0 context.Context, arg1 int64) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrow", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrow indicates an expected call of GetEscrow.
+func (mr *MockStoreMockRecorder) GetEscrow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrow", reflect.TypeOf((*MockStore)(nil).GetEscrow), arg0, arg1)
+}
+
+// GetEscrowForUpdate mocks base method.
+func (m *MockStore) GetEscrowForUpdate(arg0 context.Context, arg1 int64) (db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrowForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrowForUpdate indicates an expected call of GetEscrowForUpdate.
+func (mr *MockStoreMockRecorder) GetEscrowForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrowForUpdate", reflect.TypeOf((*MockStore)(nil).GetEscrowForUpdate), arg0, arg1)
+}
+
+// GetFeeSchedule mocks base method.
+func (m *MockStore) GetFeeSchedule(arg0 context.Context, arg1 int64) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeSchedule", arg0, arg1)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeSchedule indicates an expected call of GetFeeSchedule.
+func (mr *MockStoreMockRecorder) GetFeeSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeSchedule", reflect.TypeOf((*MockStore)(nil).GetFeeSchedule), arg0, arg1)
+}
+
+// GetHold mocks base method.
+func (m *MockStore) GetHold(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockStoreMockRecorder) GetHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockStore)(nil).GetHold), arg0, arg1)
+}
+
+// GetHoldForUpdate mocks base method.
+func (m *MockStore) GetHoldForUpdate(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldForUpdate indicates an expected call of GetHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetHoldForUpdate), arg0, arg1)
+}
+
+// GetInstallmentFundingOptIn mocks base method.
+func (m *MockStore) GetInstallmentFundingOptIn(arg0 context.Context, arg1 string) (db.InstallmentFundingOptIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallmentFundingOptIn", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentFundingOptIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallmentFundingOptIn indicates an expected call of GetInstallmentFundingOptIn.
+func (mr *MockStoreMockRecorder) GetInstallmentFundingOptIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallmentFundingOptIn", reflect.TypeOf((*MockStore)(nil).GetInstallmentFundingOptIn), arg0, arg1)
+}
+
+// GetInstallmentPlan mocks base method.
+func (m *MockStore) GetInstallmentPlan(arg0 context.Context, arg1 int64) (db.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallmentPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallmentPlan indicates an expected call of GetInstallmentPlan.
+func (mr *MockStoreMockRecorder) GetInstallmentPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallmentPlan", reflect.TypeOf((*MockStore)(nil).GetInstallmentPlan), arg0, arg1)
+}
+
+// GetInstallmentPlanForUpdate mocks base method.
+func (m *MockStore) GetInstallmentPlanForUpdate(arg0 context.Context, arg1 int64) (db.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallmentPlanForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallmentPlanForUpdate indicates an expected call of GetInstallmentPlanForUpdate.
+func (mr *MockStoreMockRecorder) GetInstallmentPlanForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallmentPlanForUpdate", reflect.TypeOf((*MockStore)(nil).GetInstallmentPlanForUpdate), arg0, arg1)
+}
+
+// GetInvoice mocks base method.
+func (m *MockStore) GetInvoice(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoice indicates an expected call of GetInvoice.
+func (mr *MockStoreMockRecorder) GetInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoice", reflect.TypeOf((*MockStore)(nil).GetInvoice), arg0, arg1)
+}
+
+// GetInvoiceByReview mocks base method.
+func (m *MockStore) GetInvoiceByReview(arg0 context.Context, arg1 sql.NullInt64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceByReview", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceByReview indicates an expected call of GetInvoiceByReview.
+func (mr *MockStoreMockRecorder) GetInvoiceByReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceByReview", reflect.TypeOf((*MockStore)(nil).GetInvoiceByReview), arg0, arg1)
+}
+
+// GetInvoiceForUpdate mocks base method.
+func (m *MockStore) GetInvoiceForUpdate(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceForUpdate indicates an expected call of GetInvoiceForUpdate.
+func (mr *MockStoreMockRecorder) GetInvoiceForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceForUpdate", reflect.TypeOf((*MockStore)(nil).GetInvoiceForUpdate), arg0, arg1)
+}
+
+// GetJournal mocks base method.
+func (m *MockStore) GetJournal(arg0 context.Context, arg1 int64) (db.Journal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.Journal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournal indicates an expected call of GetJournal.
+func (mr *MockStoreMockRecorder) GetJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournal", reflect.TypeOf((*MockStore)(nil).GetJournal), arg0, arg1)
+}
+
+// GetLastChainCheckpoint mocks base method.
+func (m *MockStore) GetLastChainCheckpoint(arg0 context.Context) (db.ChainCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastChainCheckpoint", arg0)
+	ret0, _ := ret[0].(db.ChainCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastChainCheckpoint indicates an expected call of GetLastChainCheckpoint.
+func (mr *MockStoreMockRecorder) GetLastChainCheckpoint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastChainCheckpoint", reflect.TypeOf((*MockStore)(nil).GetLastChainCheckpoint), arg0)
+}
+
+// GetLastEntryID mocks base method.
+func (m *MockStore) GetLastEntryID(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastEntryID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastEntryID indicates an expected call of GetLastEntryID.
+func (mr *MockStoreMockRecorder) GetLastEntryID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastEntryID", reflect.TypeOf((*MockStore)(nil).GetLastEntryID), arg0, arg1)
+}
+
+// GetLastEntryIDBefore mocks base method.
+func (m *MockStore) GetLastEntryIDBefore(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastEntryIDBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastEntryIDBefore indicates an expected call of GetLastEntryIDBefore.
+func (mr *MockStoreMockRecorder) GetLastEntryIDBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastEntryIDBefore", reflect.TypeOf((*MockStore)(nil).GetLastEntryIDBefore), arg0, arg1)
+}
+
+// GetLedgerAccountBalance mocks base method.
+func (m *MockStore) GetLedgerAccountBalance(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerAccountBalance indicates an expected call of GetLedgerAccountBalance.
+func (mr *MockStoreMockRecorder) GetLedgerAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerAccountBalance", reflect.TypeOf((*MockStore)(nil).GetLedgerAccountBalance), arg0, arg1)
+}
+
+// GetLimitIncreaseRequest mocks base method.
+func (m *MockStore) GetLimitIncreaseRequest(arg0 context.Context, arg1 int64) (db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitIncreaseRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitIncreaseRequest indicates an expected call of GetLimitIncreaseRequest.
+func (mr *MockStoreMockRecorder) GetLimitIncreaseRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitIncreaseRequest", reflect.TypeOf((*MockStore)(nil).GetLimitIncreaseRequest), arg0, arg1)
+}
+
+// GetMerchantDisputeCounts mocks base method.
+func (m *MockStore) GetMerchantDisputeCounts(arg0 context.Context, arg1 db.GetMerchantDisputeCountsParams) (db.GetMerchantDisputeCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantDisputeCounts", arg0, arg1)
+	ret0, _ := ret[0].(db.GetMerchantDisputeCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantDisputeCounts indicates an expected call of GetMerchantDisputeCounts.
+func (mr *MockStoreMockRecorder) GetMerchantDisputeCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantDisputeCounts", reflect.TypeOf((*MockStore)(nil).GetMerchantDisputeCounts), arg0, arg1)
+}
+
+// GetNextPayoutRow mocks base method.
+func (m *MockStore) GetNextPayoutRow(arg0 context.Context, arg1 int64) (db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextPayoutRow", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextPayoutRow indicates an expected call of GetNextPayoutRow.
+func (mr *MockStoreMockRecorder) GetNextPayoutRow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextPayoutRow", reflect.TypeOf((*MockStore)(nil).GetNextPayoutRow), arg0, arg1)
+}
+
+// GetOutgoingTransferTotals mocks base method.
+func (m *MockStore) GetOutgoingTransferTotals(arg0 context.Context, arg1 db.GetOutgoingTransferTotalsParams) (db.GetOutgoingTransferTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingTransferTotals", arg0, arg1)
+	ret0, _ := ret[0].(db.GetOutgoingTransferTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingTransferTotals indicates an expected call of GetOutgoingTransferTotals.
+func (mr *MockStoreMockRecorder) GetOutgoingTransferTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingTransferTotals", reflect.TypeOf((*MockStore)(nil).GetOutgoingTransferTotals), arg0, arg1)
+}
+
+// GetPaymentLinkByToken mocks base method.
+func (m *MockStore) GetPaymentLinkByToken(arg0 context.Context, arg1 string) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentLinkByToken", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentLinkByToken indicates an expected call of GetPaymentLinkByToken.
+func (mr *MockStoreMockRecorder) GetPaymentLinkByToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentLinkByToken", reflect.TypeOf((*MockStore)(nil).GetPaymentLinkByToken), arg0, arg1)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockStore) GetPaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockStoreMockRecorder) GetPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockStore)(nil).GetPaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequestByReview mocks base method.
+func (m *MockStore) GetPaymentRequestByReview(arg0 context.Context, arg1 sql.NullInt64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestByReview", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestByReview indicates an expected call of GetPaymentRequestByReview.
+func (mr *MockStoreMockRecorder) GetPaymentRequestByReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestByReview", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestByReview), arg0, arg1)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockStore) GetPaymentRequestForUpdate(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestForUpdate), arg0, arg1)
+}
+
+// GetPayoutBatch mocks base method.
+func (m *MockStore) GetPayoutBatch(arg0 context.Context, arg1 int64) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayoutBatch", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayoutBatch indicates an expected call of GetPayoutBatch.
+func (mr *MockStoreMockRecorder) GetPayoutBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayoutBatch", reflect.TypeOf((*MockStore)(nil).GetPayoutBatch), arg0, arg1)
+}
+
+// GetPayoutBatchForUpdate mocks base method.
+func (m *MockStore) GetPayoutBatchForUpdate(arg0 context.Context, arg1 int64) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayoutBatchForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayoutBatchForUpdate indicates an expected call of GetPayoutBatchForUpdate.
+func (mr *MockStoreMockRecorder) GetPayoutBatchForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayoutBatchForUpdate", reflect.TypeOf((*MockStore)(nil).GetPayoutBatchForUpdate), arg0, arg1)
+}
+
+// GetPayoutRowByReview mocks base method.
+func (m *MockStore) GetPayoutRowByReview(arg0 context.Context, arg1 sql.NullInt64) (db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayoutRowByReview", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayoutRowByReview indicates an expected call of GetPayoutRowByReview.
+func (mr *MockStoreMockRecorder) GetPayoutRowByReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayoutRowByReview", reflect.TypeOf((*MockStore)(nil).GetPayoutRowByReview), arg0, arg1)
+}
+
+// GetPreviousEntryHash mocks base method.
+func (m *MockStore) GetPreviousEntryHash(arg0 context.Context, arg1 db.GetPreviousEntryHashParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreviousEntryHash", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreviousEntryHash indicates an expected call of GetPreviousEntryHash.
+func (mr *MockStoreMockRecorder) GetPreviousEntryHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousEntryHash", reflect.TypeOf((*MockStore)(nil).GetPreviousEntryHash), arg0, arg1)
+}
+
+// GetRefund mocks base method.
+func (m *MockStore) GetRefund(arg0 context.Context, arg1 int64) (db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefund indicates an expected call of GetRefund.
+func (mr *MockStoreMockRecorder) GetRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefund", reflect.TypeOf((*MockStore)(nil).GetRefund), arg0, arg1)
+}
+
+// GetRefundedAmount mocks base method.
+func (m *MockStore) GetRefundedAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundedAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundedAmount indicates an expected call of GetRefundedAmount.
+func (mr *MockStoreMockRecorder) GetRefundedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundedAmount", reflect.TypeOf((*MockStore)(nil).GetRefundedAmount), arg0, arg1)
+}
+
+// GetReserveRule mocks base method.
+func (m *MockStore) GetReserveRule(arg0 context.Context, arg1 string) (db.ReserveRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReserveRule", arg0, arg1)
+	ret0, _ := ret[0].(db.ReserveRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReserveRule indicates an expected call of GetReserveRule.
+func (mr *MockStoreMockRecorder) GetReserveRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReserveRule", reflect.TypeOf((*MockStore)(nil).GetReserveRule), arg0, arg1)
+}
+
+// GetReserveTotals mocks base method.
+func (m *MockStore) GetReserveTotals(arg0 context.Context, arg1 int64) (db.GetReserveTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReserveTotals", arg0, arg1)
+	ret0, _ := ret[0].(db.GetReserveTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReserveTotals indicates an expected call of GetReserveTotals.
+func (mr *MockStoreMockRecorder) GetReserveTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReserveTotals", reflect.TypeOf((*MockStore)(nil).GetReserveTotals), arg0, arg1)
+}
+
+// GetScheduledTransfer mocks base method.
+func (m *MockStore) GetScheduledTransfer(arg0 context.Context, arg1 int64) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransfer indicates an expected call of GetScheduledTransfer.
+func (mr *MockStoreMockRecorder) GetScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransfer", reflect.TypeOf((*MockStore)(nil).GetScheduledTransfer), arg0, arg1)
+}
+
+// GetScheduledTransferRunByReview mocks base method.
+func (m *MockStore) GetScheduledTransferRunByReview(arg0 context.Context, arg1 sql.NullInt64) (db.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransferRunByReview", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransferRunByReview indicates an expected call of GetScheduledTransferRunByReview.
+func (mr *MockStoreMockRecorder) GetScheduledTransferRunByReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransferRunByReview", reflect.TypeOf((*MockStore)(nil).GetScheduledTransferRunByReview), arg0, arg1)
+}
+
+// GetSettlementPlan mocks base method.
+func (m *MockStore) GetSettlementPlan(arg0 context.Context, arg1 string) (db.SettlementPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettlementPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.SettlementPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettlementPlan indicates an expected call of GetSettlementPlan.
+func (mr *MockStoreMockRecorder) GetSettlementPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettlementPlan", reflect.TypeOf((*MockStore)(nil).GetSettlementPlan), arg0, arg1)
+}
+
+// GetSplitTransfer mocks base method.
+func (m *MockStore) GetSplitTransfer(arg0 context.Context, arg1 int64) (db.SplitTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSplitTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.SplitTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSplitTransfer indicates an expected call of GetSplitTransfer.
+func (mr *MockStoreMockRecorder) GetSplitTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSplitTransfer", reflect.TypeOf((*MockStore)(nil).GetSplitTransfer), arg0, arg1)
+}
+
+// GetSubscription mocks base method.
+func (m *MockStore) GetSubscription(arg0 context.Context, arg1 int64) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockStoreMockRecorder) GetSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockStore)(nil).GetSubscription), arg0, arg1)
+}
+
+// GetSubscriptionForUpdate mocks base method.
+func (m *MockStore) GetSubscriptionForUpdate(arg0 context.Context, arg1 int64) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionForUpdate indicates an expected call of GetSubscriptionForUpdate.
+func (mr *MockStoreMockRecorder) GetSubscriptionForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionForUpdate", reflect.TypeOf((*MockStore)(nil).GetSubscriptionForUpdate), arg0, arg1)
+}
+
+// GetSubscriptionPlan mocks base method.
+func (m *MockStore) GetSubscriptionPlan(arg0 context.Context, arg1 int64) (db.SubscriptionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.SubscriptionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionPlan indicates an expected call of GetSubscriptionPlan.
+func (mr *MockStoreMockRecorder) GetSubscriptionPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionPlan", reflect.TypeOf((*MockStore)(nil).GetSubscriptionPlan), arg0, arg1)
+}
+
+// GetSystemLedgerAccount mocks base method.
+func (m *MockStore) GetSystemLedgerAccount(arg0 context.Context, arg1 db.GetSystemLedgerAccountParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemLedgerAccount indicates an expected call of GetSystemLedgerAccount.
+func (mr *MockStoreMockRecorder) GetSystemLedgerAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemLedgerAccount", reflect.TypeOf((*MockStore)(nil).GetSystemLedgerAccount), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetTransferLimits mocks base method.
+func (m *MockStore) GetTransferLimits(arg0 context.Context, arg1 string) (db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferLimits indicates an expected call of GetTransferLimits.
+func (mr *MockStoreMockRecorder) GetTransferLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferLimits", reflect.TypeOf((*MockStore)(nil).GetTransferLimits), arg0, arg1)
+}
+
+// GetTransferReceivableForUpdate mocks base method.
+func (m *MockStore) GetTransferReceivableForUpdate(arg0 context.Context, arg1 db.GetTransferReceivableForUpdateParams) (db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReceivableForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReceivableForUpdate indicates an expected call of GetTransferReceivableForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferReceivableForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReceivableForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferReceivableForUpdate), arg0, arg1)
+}
+
+// GetTransferReview mocks base method.
+func (m *MockStore) GetTransferReview(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReview indicates an expected call of GetTransferReview.
+func (mr *MockStoreMockRecorder) GetTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReview", reflect.TypeOf((*MockStore)(nil).GetTransferReview), arg0, arg1)
+}
+
+// GetTransferReviewForUpdate mocks base method.
+func (m *MockStore) GetTransferReviewForUpdate(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReviewForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReviewForUpdate indicates an expected call of GetTransferReviewForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferReviewForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReviewForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferReviewForUpdate), arg0, arg1)
+}
+
+// GetTrialBalance mocks base method.
+func (m *MockStore) GetTrialBalance(arg0 context.Context) ([]db.GetTrialBalanceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrialBalance", arg0)
+	ret0, _ := ret[0].([]db.GetTrialBalanceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrialBalance indicates an expected call of GetTrialBalance.
+func (mr *MockStoreMockRecorder) GetTrialBalance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrialBalance", reflect.TypeOf((*MockStore)(nil).GetTrialBalance), arg0)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByPixKey mocks base method.
+func (m *MockStore) GetUserByPixKey(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByPixKey", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByPixKey indicates an expected call of GetUserByPixKey.
+func (mr *MockStoreMockRecorder) GetUserByPixKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByPixKey", reflect.TypeOf((*MockStore)(nil).GetUserByPixKey), arg0, arg1)
+}
+
+// GetWallet mocks base method.
+func (m *MockStore) GetWallet(arg0 context.Context, arg1 int64) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallet", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallet indicates an expected call of GetWallet.
+func (mr *MockStoreMockRecorder) GetWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockStore)(nil).GetWallet), arg0, arg1)
+}
+
+// GetWalletByOwnerAndCurrency mocks base method.
+func (m *MockStore) GetWalletByOwnerAndCurrency(arg0 context.Context, arg1 db.GetWalletByOwnerAndCurrencyParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletByOwnerAndCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletByOwnerAndCurrency indicates an expected call of GetWalletByOwnerAndCurrency.
+func (mr *MockStoreMockRecorder) GetWalletByOwnerAndCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletByOwnerAndCurrency", reflect.TypeOf((*MockStore)(nil).GetWalletByOwnerAndCurrency), arg0, arg1)
+}
+
+// GetWalletForUpdate mocks base method.
+func (m *MockStore) GetWalletForUpdate(arg0 context.Context, arg1 int64) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletForUpdate indicates an expected call of GetWalletForUpdate.
+func (mr *MockStoreMockRecorder) GetWalletForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletForUpdate", reflect.TypeOf((*MockStore)(nil).GetWalletForUpdate), arg0, arg1)
+}
+
+// GetWalletLedgerAccount mocks base method.
+func (m *MockStore) GetWalletLedgerAccount(arg0 context.Context, arg1 sql.NullInt64) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletLedgerAccount indicates an expected call of GetWalletLedgerAccount.
+func (mr *MockStoreMockRecorder) GetWalletLedgerAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletLedgerAccount", reflect.TypeOf((*MockStore)(nil).GetWalletLedgerAccount), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), arg0, arg1)
+}
+
+// GetWebhookEvent mocks base method.
+func (m *MockStore) GetWebhookEvent(arg0 context.Context, arg1 int64) (db.WebhookEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEvent indicates an expected call of GetWebhookEvent.
+func (mr *MockStoreMockRecorder) GetWebhookEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEvent", reflect.TypeOf((*MockStore)(nil).GetWebhookEvent), arg0, arg1)
+}
+
+// IncrementWebhookEndpointFailures mocks base method.
+func (m *MockStore) IncrementWebhookEndpointFailures(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementWebhookEndpointFailures", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementWebhookEndpointFailures indicates an expected call of IncrementWebhookEndpointFailures.
+func (mr *MockStoreMockRecorder) IncrementWebhookEndpointFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementWebhookEndpointFailures", reflect.TypeOf((*MockStore)(nil).IncrementWebhookEndpointFailures), arg0, arg1)
+}
+
+// ListAllPayoutRows mocks base method.
+func (m *MockStore) ListAllPayoutRows(arg0 context.Context, arg1 int64) ([]db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllPayoutRows", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllPayoutRows indicates an expected call of ListAllPayoutRows.
+func (mr *MockStoreMockRecorder) ListAllPayoutRows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllPayoutRows", reflect.TypeOf((*MockStore)(nil).ListAllPayoutRows), arg0, arg1)
+}
+
+// ListAnticipatableReceivables mocks base method.
+func (m *MockStore) ListAnticipatableReceivables(arg0 context.Context, arg1 db.ListAnticipatableReceivablesParams) ([]db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnticipatableReceivables", arg0, arg1)
+	ret0, _ := ret[0].([]db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnticipatableReceivables indicates an expected call of ListAnticipatableReceivables.
+func (mr *MockStoreMockRecorder) ListAnticipatableReceivables(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnticipatableReceivables", reflect.TypeOf((*MockStore)(nil).ListAnticipatableReceivables), arg0, arg1)
+}
+
+// ListApiKeys mocks base method.
+func (m *MockStore) ListApiKeys(arg0 context.Context, arg1 string) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApiKeys", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApiKeys indicates an expected call of ListApiKeys.
+func (mr *MockStoreMockRecorder) ListApiKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApiKeys", reflect.TypeOf((*MockStore)(nil).ListApiKeys), arg0, arg1)
+}
+
+// ListCashbackCampaigns mocks base method.
+func (m *MockStore) ListCashbackCampaigns(arg0 context.Context, arg1 db.ListCashbackCampaignsParams) ([]db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCashbackCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCashbackCampaigns indicates an expected call of ListCashbackCampaigns.
+func (mr *MockStoreMockRecorder) ListCashbackCampaigns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCashbackCampaigns", reflect.TypeOf((*MockStore)(nil).ListCashbackCampaigns), arg0, arg1)
+}
+
+// ListChainCheckpoints mocks base method.
+func (m *MockStore) ListChainCheckpoints(arg0 context.Context, arg1 db.ListChainCheckpointsParams) ([]db.ChainCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChainCheckpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChainCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChainCheckpoints indicates an expected call of ListChainCheckpoints.
+func (mr *MockStoreMockRecorder) ListChainCheckpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChainCheckpoints", reflect.TypeOf((*MockStore)(nil).ListChainCheckpoints), arg0, arg1)
+}
+
+// ListChainEntries mocks base method.
+func (m *MockStore) ListChainEntries(arg0 context.Context, arg1 db.ListChainEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChainEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChainEntries indicates an expected call of ListChainEntries.
+func (mr *MockStoreMockRecorder) ListChainEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChainEntries", reflect.TypeOf((*MockStore)(nil).ListChainEntries), arg0, arg1)
+}
+
+// ListDisputeEvidence mocks base method.
+func (m *MockStore) ListDisputeEvidence(arg0 context.Context, arg1 int64) ([]db.DisputeEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisputeEvidence", arg0, arg1)
+	ret0, _ := ret[0].([]db.DisputeEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisputeEvidence indicates an expected call of ListDisputeEvidence.
+func (mr *MockStoreMockRecorder) ListDisputeEvidence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisputeEvidence", reflect.TypeOf((*MockStore)(nil).ListDisputeEvidence), arg0, arg1)
+}
+
+// ListDisputesByStatus mocks base method.
+func (m *MockStore) ListDisputesByStatus(arg0 context.Context, arg1 db.ListDisputesByStatusParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisputesByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisputesByStatus indicates an expected call of ListDisputesByStatus.
+func (mr *MockStoreMockRecorder) ListDisputesByStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisputesByStatus", reflect.TypeOf((*MockStore)(nil).ListDisputesByStatus), arg0, arg1)
+}
+
+// ListDueReceivableWallets mocks base method.
+func (m *MockStore) ListDueReceivableWallets(arg0 context.Context, arg1 db.ListDueReceivableWalletsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueReceivableWallets", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueReceivableWallets indicates an expected call of ListDueReceivableWallets.
+func (mr *MockStoreMockRecorder) ListDueReceivableWallets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueReceivableWallets", reflect.TypeOf((*MockStore)(nil).ListDueReceivableWallets), arg0, arg1)
+}
+
+// ListDueReserveWallets mocks base method.
+func (m *MockStore) ListDueReserveWallets(arg0 context.Context, arg1 db.ListDueReserveWalletsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueReserveWallets", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueReserveWallets indicates an expected call of ListDueReserveWallets.
+func (mr *MockStoreMockRecorder) ListDueReserveWallets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueReserveWallets", reflect.TypeOf((*MockStore)(nil).ListDueReserveWallets), arg0, arg1)
+}
+
+// ListEligibleCashbackCampaigns mocks base method.
+func (m *MockStore) ListEligibleCashbackCampaigns(arg0 context.Context, arg1 db.ListEligibleCashbackCampaignsParams) ([]db.CashbackCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEligibleCashbackCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]db.CashbackCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEligibleCashbackCampaigns indicates an expected call of ListEligibleCashbackCampaigns.
+func (mr *MockStoreMockRecorder) ListEligibleCashbackCampaigns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEligibleCashbackCampaigns", reflect.TypeOf((*MockStore)(nil).ListEligibleCashbackCampaigns), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEntriesAfter mocks base method.
+func (m *MockStore) ListEntriesAfter(arg0 context.Context, arg1 db.ListEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesAfter indicates an expected call of ListEntriesAfter.
+func (mr *MockStoreMockRecorder) ListEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListEntriesAfter), arg0, arg1)
+}
+
+// ListEntriesInRange mocks base method.
+func (m *MockStore) ListEntriesInRange(arg0 context.Context, arg1 db.ListEntriesInRangeParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesInRange", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesInRange indicates an expected call of ListEntriesInRange.
+func (mr *MockStoreMockRecorder) ListEntriesInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesInRange", reflect.TypeOf((*MockStore)(nil).ListEntriesInRange), arg0, arg1)
+}
+
+// ListFeeSchedules mocks base method.
+func (m *MockStore) ListFeeSchedules(arg0 context.Context, arg1 db.ListFeeSchedulesParams) ([]db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeeSchedules", arg0, arg1)
+	ret0, _ := ret[0].([]db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeeSchedules indicates an expected call of ListFeeSchedules.
+func (mr *MockStoreMockRecorder) ListFeeSchedules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeSchedules", reflect.TypeOf((*MockStore)(nil).ListFeeSchedules), arg0, arg1)
+}
+
+// ListIncomingInvoices mocks base method.
+func (m *MockStore) ListIncomingInvoices(arg0 context.Context, arg1 db.ListIncomingInvoicesParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingInvoices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingInvoices indicates an expected call of ListIncomingInvoices.
+func (mr *MockStoreMockRecorder) ListIncomingInvoices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingInvoices", reflect.TypeOf((*MockStore)(nil).ListIncomingInvoices), arg0, arg1)
+}
+
+// ListIncomingPaymentRequests mocks base method.
+func (m *MockStore) ListIncomingPaymentRequests(arg0 context.Context, arg1 db.ListIncomingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingPaymentRequests indicates an expected call of ListIncomingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListIncomingPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListIncomingPaymentRequests), arg0, arg1)
+}
+
+// ListInvoiceItems mocks base method.
+func (m *MockStore) ListInvoiceItems(arg0 context.Context, arg1 int64) ([]db.InvoiceItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoiceItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.InvoiceItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoiceItems indicates an expected call of ListInvoiceItems.
+func (mr *MockStoreMockRecorder) ListInvoiceItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoiceItems", reflect.TypeOf((*MockStore)(nil).ListInvoiceItems), arg0, arg1)
+}
+
+// ListJournalPostings mocks base method.
+func (m *MockStore) ListJournalPostings(arg0 context.Context, arg1 int64) ([]db.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJournalPostings", arg0, arg1)
+	ret0, _ := ret[0].([]db.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJournalPostings indicates an expected call of ListJournalPostings.
+func (mr *MockStoreMockRecorder) ListJournalPostings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJournalPostings", reflect.TypeOf((*MockStore)(nil).ListJournalPostings), arg0, arg1)
+}
+
+// ListLimitIncreaseRequests mocks base method.
+func (m *MockStore) ListLimitIncreaseRequests(arg0 context.Context, arg1 db.ListLimitIncreaseRequestsParams) ([]db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimitIncreaseRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLimitIncreaseRequests indicates an expected call of ListLimitIncreaseRequests.
+func (mr *MockStoreMockRecorder) ListLimitIncreaseRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimitIncreaseRequests", reflect.TypeOf((*MockStore)(nil).ListLimitIncreaseRequests), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListOutgoingInvoices mocks base method.
+func (m *MockStore) ListOutgoingInvoices(arg0 context.Context, arg1 db.ListOutgoingInvoicesParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingInvoices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingInvoices indicates an expected call of ListOutgoingInvoices.
+func (mr *MockStoreMockRecorder) ListOutgoingInvoices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingInvoices", reflect.TypeOf((*MockStore)(nil).ListOutgoingInvoices), arg0, arg1)
+}
+
+// ListOutgoingPaymentRequests mocks base method.
+func (m *MockStore) ListOutgoingPaymentRequests(arg0 context.Context, arg1 db.ListOutgoingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingPaymentRequests indicates an expected call of ListOutgoingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListOutgoingPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListOutgoingPaymentRequests), arg0, arg1)
+}
+
+// ListPayoutBatches mocks base method.
+func (m *MockStore) ListPayoutBatches(arg0 context.Context, arg1 db.ListPayoutBatchesParams) ([]db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayoutBatches", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayoutBatches indicates an expected call of ListPayoutBatches.
+func (mr *MockStoreMockRecorder) ListPayoutBatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayoutBatches", reflect.TypeOf((*MockStore)(nil).ListPayoutBatches), arg0, arg1)
+}
+
+// ListPayoutRows mocks base method.
+func (m *MockStore) ListPayoutRows(arg0 context.Context, arg1 db.ListPayoutRowsParams) ([]db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayoutRows", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayoutRows indicates an expected call of ListPayoutRows.
+func (mr *MockStoreMockRecorder) ListPayoutRows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayoutRows", reflect.TypeOf((*MockStore)(nil).ListPayoutRows), arg0, arg1)
+}
+
+// ListPlanInstallments mocks base method.
+func (m *MockStore) ListPlanInstallments(arg0 context.Context, arg1 int64) ([]db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPlanInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPlanInstallments indicates an expected call of ListPlanInstallments.
+func (mr *MockStoreMockRecorder) ListPlanInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlanInstallments", reflect.TypeOf((*MockStore)(nil).ListPlanInstallments), arg0, arg1)
+}
+
+// ListReceivableSchedule mocks base method.
+func (m *MockStore) ListReceivableSchedule(arg0 context.Context, arg1 int64) ([]db.ListReceivableScheduleRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReceivableSchedule", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListReceivableScheduleRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReceivableSchedule indicates an expected call of ListReceivableSchedule.
+func (mr *MockStoreMockRecorder) ListReceivableSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReceivableSchedule", reflect.TypeOf((*MockStore)(nil).ListReceivableSchedule), arg0, arg1)
+}
+
+// ListRecentChainCheckpoints mocks base method.
+func (m *MockStore) ListRecentChainCheckpoints(arg0 context.Context, arg1 db.ListRecentChainCheckpointsParams) ([]db.ChainCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecentChainCheckpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChainCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecentChainCheckpoints indicates an expected call of ListRecentChainCheckpoints.
+func (mr *MockStoreMockRecorder) ListRecentChainCheckpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentChainCheckpoints", reflect.TypeOf((*MockStore)(nil).ListRecentChainCheckpoints), arg0, arg1)
+}
+
+// ListRefunds mocks base method.
+func (m *MockStore) ListRefunds(arg0 context.Context, arg1 int64) ([]db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefunds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRefunds indicates an expected call of ListRefunds.
+func (mr *MockStoreMockRecorder) ListRefunds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefunds", reflect.TypeOf((*MockStore)(nil).ListRefunds), arg0, arg1)
+}
+
+// ListRiskDecisions mocks base method.
+func (m *MockStore) ListRiskDecisions(arg0 context.Context, arg1 db.ListRiskDecisionsParams) ([]db.RiskDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRiskDecisions", arg0, arg1)
+	ret0, _ := ret[0].([]db.RiskDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRiskDecisions indicates an expected call of ListRiskDecisions.
+func (mr *MockStoreMockRecorder) ListRiskDecisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRiskDecisions", reflect.TypeOf((*MockStore)(nil).ListRiskDecisions), arg0, arg1)
+}
+
+// ListScheduledTransferRuns mocks base method.
+func (m *MockStore) ListScheduledTransferRuns(arg0 context.Context, arg1 db.ListScheduledTransferRunsParams) ([]db.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransferRuns", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransferRuns indicates an expected call of ListScheduledTransferRuns.
+func (mr *MockStoreMockRecorder) ListScheduledTransferRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransferRuns", reflect.TypeOf((*MockStore)(nil).ListScheduledTransferRuns), arg0, arg1)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockStore) ListScheduledTransfers(arg0 context.Context, arg1 db.ListScheduledTransfersParams) ([]db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockStoreMockRecorder) ListScheduledTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockStore)(nil).ListScheduledTransfers), arg0, arg1)
+}
+
+// ListSplitTransferTransfers mocks base method.
+func (m *MockStore) ListSplitTransferTransfers(arg0 context.Context, arg1 sql.NullInt64) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSplitTransferTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSplitTransferTransfers indicates an expected call of ListSplitTransferTransfers.
+func (mr *MockStoreMockRecorder) ListSplitTransferTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSplitTransferTransfers", reflect.TypeOf((*MockStore)(nil).ListSplitTransferTransfers), arg0, arg1)
+}
+
+// ListSubscriptionCharges mocks base method.
+func (m *MockStore) ListSubscriptionCharges(arg0 context.Context, arg1 int64) ([]db.SubscriptionCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionCharges", arg0, arg1)
+	ret0, _ := ret[0].([]db.SubscriptionCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionCharges indicates an expected call of ListSubscriptionCharges.
+func (mr *MockStoreMockRecorder) ListSubscriptionCharges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionCharges", reflect.TypeOf((*MockStore)(nil).ListSubscriptionCharges), arg0, arg1)
+}
+
+// ListSystemLedgerAccounts mocks base method.
+func (m *MockStore) ListSystemLedgerAccounts(arg0 context.Context) ([]db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSystemLedgerAccounts", arg0)
+	ret0, _ := ret[0].([]db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSystemLedgerAccounts indicates an expected call of ListSystemLedgerAccounts.
+func (mr *MockStoreMockRecorder) ListSystemLedgerAccounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSystemLedgerAccounts", reflect.TypeOf((*MockStore)(nil).ListSystemLedgerAccounts), arg0)
+}
+
+// ListTransferFeeEntries mocks base method.
+func (m *MockStore) ListTransferFeeEntries(arg0 context.Context, arg1 sql.NullInt64) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferFeeEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferFeeEntries indicates an expected call of ListTransferFeeEntries.
+func (mr *MockStoreMockRecorder) ListTransferFeeEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferFeeEntries", reflect.TypeOf((*MockStore)(nil).ListTransferFeeEntries), arg0, arg1)
+}
+
+// ListTransferReviews mocks base method.
+func (m *MockStore) ListTransferReviews(arg0 context.Context, arg1 db.ListTransferReviewsParams) ([]db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferReviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferReviews indicates an expected call of ListTransferReviews.
+func (mr *MockStoreMockRecorder) ListTransferReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferReviews", reflect.TypeOf((*MockStore)(nil).ListTransferReviews), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListUserDisputes mocks base method.
+func (m *MockStore) ListUserDisputes(arg0 context.Context, arg1 db.ListUserDisputesParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserDisputes indicates an expected call of ListUserDisputes.
+func (mr *MockStoreMockRecorder) ListUserDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserDisputes", reflect.TypeOf((*MockStore)(nil).ListUserDisputes), arg0, arg1)
+}
+
+// ListUsers mocks base method.
+func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
+}
+
+// ListWalletCashbackGrants mocks base method.
+func (m *MockStore) ListWalletCashbackGrants(arg0 context.Context, arg1 db.ListWalletCashbackGrantsParams) ([]db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletCashbackGrants", arg0, arg1)
+	ret0, _ := ret[0].([]db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletCashbackGrants indicates an expected call of ListWalletCashbackGrants.
+func (mr *MockStoreMockRecorder) ListWalletCashbackGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletCashbackGrants", reflect.TypeOf((*MockStore)(nil).ListWalletCashbackGrants), arg0, arg1)
+}
+
+// ListWalletCheckoutSessions mocks base method.
+func (m *MockStore) ListWalletCheckoutSessions(arg0 context.Context, arg1 db.ListWalletCheckoutSessionsParams) ([]db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletCheckoutSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletCheckoutSessions indicates an expected call of ListWalletCheckoutSessions.
+func (mr *MockStoreMockRecorder) ListWalletCheckoutSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletCheckoutSessions", reflect.TypeOf((*MockStore)(nil).ListWalletCheckoutSessions), arg0, arg1)
+}
+
+// ListWalletEscrows mocks base method.
+func (m *MockStore) ListWalletEscrows(arg0 context.Context, arg1 db.ListWalletEscrowsParams) ([]db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletEscrows", arg0, arg1)
+	ret0, _ := ret[0].([]db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletEscrows indicates an expected call of ListWalletEscrows.
+func (mr *MockStoreMockRecorder) ListWalletEscrows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletEscrows", reflect.TypeOf((*MockStore)(nil).ListWalletEscrows), arg0, arg1)
+}
+
+// ListWalletHolds mocks base method.
+func (m *MockStore) ListWalletHolds(arg0 context.Context, arg1 db.ListWalletHoldsParams) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletHolds indicates an expected call of ListWalletHolds.
+func (mr *MockStoreMockRecorder) ListWalletHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletHolds", reflect.TypeOf((*MockStore)(nil).ListWalletHolds), arg0, arg1)
+}
+
+// ListWalletInstallmentPlans mocks base method.
+func (m *MockStore) ListWalletInstallmentPlans(arg0 context.Context, arg1 db.ListWalletInstallmentPlansParams) ([]db.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletInstallmentPlans", arg0, arg1)
+	ret0, _ := ret[0].([]db.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletInstallmentPlans indicates an expected call of ListWalletInstallmentPlans.
+func (mr *MockStoreMockRecorder) ListWalletInstallmentPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletInstallmentPlans", reflect.TypeOf((*MockStore)(nil).ListWalletInstallmentPlans), arg0, arg1)
+}
+
+// ListWalletPaymentLinks mocks base method.
+func (m *MockStore) ListWalletPaymentLinks(arg0 context.Context, arg1 db.ListWalletPaymentLinksParams) ([]db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletPaymentLinks", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletPaymentLinks indicates an expected call of ListWalletPaymentLinks.
+func (mr *MockStoreMockRecorder) ListWalletPaymentLinks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletPaymentLinks", reflect.TypeOf((*MockStore)(nil).ListWalletPaymentLinks), arg0, arg1)
+}
+
+// ListWalletReserves mocks base method.
+func (m *MockStore) ListWalletReserves(arg0 context.Context, arg1 db.ListWalletReservesParams) ([]db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletReserves", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletReserves indicates an expected call of ListWalletReserves.
+func (mr *MockStoreMockRecorder) ListWalletReserves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletReserves", reflect.TypeOf((*MockStore)(nil).ListWalletReserves), arg0, arg1)
+}
+
+// ListWalletSubscriptionPlans mocks base method.
+func (m *MockStore) ListWalletSubscriptionPlans(arg0 context.Context, arg1 db.ListWalletSubscriptionPlansParams) ([]db.SubscriptionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletSubscriptionPlans", arg0, arg1)
+	ret0, _ := ret[0].([]db.SubscriptionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletSubscriptionPlans indicates an expected call of ListWalletSubscriptionPlans.
+func (mr *MockStoreMockRecorder) ListWalletSubscriptionPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletSubscriptionPlans", reflect.TypeOf((*MockStore)(nil).ListWalletSubscriptionPlans), arg0, arg1)
+}
+
+// ListWalletSubscriptions mocks base method.
+func (m *MockStore) ListWalletSubscriptions(arg0 context.Context, arg1 db.ListWalletSubscriptionsParams) ([]db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletSubscriptions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletSubscriptions indicates an expected call of ListWalletSubscriptions.
+func (mr *MockStoreMockRecorder) ListWalletSubscriptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletSubscriptions", reflect.TypeOf((*MockStore)(nil).ListWalletSubscriptions), arg0, arg1)
+}
+
+// ListWallets mocks base method.
+func (m *MockStore) ListWallets(arg0 context.Context, arg1 db.ListWalletsParams) ([]db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWallets", arg0, arg1)
+	ret0, _ := ret[0].([]db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWallets indicates an expected call of ListWallets.
+func (mr *MockStoreMockRecorder) ListWallets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWallets", reflect.TypeOf((*MockStore)(nil).ListWallets), arg0, arg1)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
+}
+
+// ListWebhookDeliveryAttempts mocks base method.
+func (m *MockStore) ListWebhookDeliveryAttempts(arg0 context.Context, arg1 int64) ([]db.WebhookDeliveryAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveryAttempts", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDeliveryAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveryAttempts indicates an expected call of ListWebhookDeliveryAttempts.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveryAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveryAttempts", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveryAttempts), arg0, arg1)
+}
+
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(arg0 context.Context, arg1 string) ([]db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), arg0, arg1)
+}
+
+// LockAnticipatableReceivables mocks base method.
+func (m *MockStore) LockAnticipatableReceivables(arg0 context.Context, arg1 db.LockAnticipatableReceivablesParams) ([]db.Receivable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAnticipatableReceivables", arg0, arg1)
+	ret0, _ := ret[0].([]db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockAnticipatableReceivables indicates an expected call of LockAnticipatableReceivables.
+func (mr *MockStoreMockRecorder) LockAnticipatableReceivables(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAnticipatableReceivables", reflect.TypeOf((*MockStore)(nil).LockAnticipatableReceivables), arg0, arg1)
+}
+
+// LockHeldReserves mocks base method.
+func (m *MockStore) LockHeldReserves(arg0 context.Context, arg1 int64) ([]db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockHeldReserves", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockHeldReserves indicates an expected call of LockHeldReserves.
+func (mr *MockStoreMockRecorder) LockHeldReserves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockHeldReserves", reflect.TypeOf((*MockStore)(nil).LockHeldReserves), arg0, arg1)
+}
+
+// LockOpenInstallments mocks base method.
+func (m *MockStore) LockOpenInstallments(arg0 context.Context, arg1 int64) ([]db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOpenInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockOpenInstallments indicates an expected call of LockOpenInstallments.
+func (mr *MockStoreMockRecorder) LockOpenInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOpenInstallments", reflect.TypeOf((*MockStore)(nil).LockOpenInstallments), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// MarkWebhookDeliverySucceeded mocks base method.
+func (m *MockStore) MarkWebhookDeliverySucceeded(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliverySucceeded", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkWebhookDeliverySucceeded indicates an expected call of MarkWebhookDeliverySucceeded.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliverySucceeded(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliverySucceeded", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliverySucceeded), arg0, arg1)
+}
+
+// MerchantDisputeRate mocks base method.
+func (m *MockStore) MerchantDisputeRate(arg0 context.Context, arg1 string, arg2 time.Time) (db.MerchantDisputeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerchantDisputeRate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(db.MerchantDisputeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerchantDisputeRate indicates an expected call of MerchantDisputeRate.
+func (mr *MockStoreMockRecorder) MerchantDisputeRate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerchantDisputeRate", reflect.TypeOf((*MockStore)(nil).MerchantDisputeRate), arg0, arg1, arg2)
+}
+
+// NextInvoiceNumber mocks base method.
+func (m *MockStore) NextInvoiceNumber(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextInvoiceNumber", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextInvoiceNumber indicates an expected call of NextInvoiceNumber.
+func (mr *MockStoreMockRecorder) NextInvoiceNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextInvoiceNumber", reflect.TypeOf((*MockStore)(nil).NextInvoiceNumber), arg0, arg1)
+}
+
+// OpenDisputeTx mocks base method.
+func (m *MockStore) OpenDisputeTx(arg0 context.Context, arg1 db.OpenDisputeTxParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDisputeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenDisputeTx indicates an expected call of OpenDisputeTx.
+func (mr *MockStoreMockRecorder) OpenDisputeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDisputeTx", reflect.TypeOf((*MockStore)(nil).OpenDisputeTx), arg0, arg1)
+}
+
+// PayCashbackGrant mocks base method.
+func (m *MockStore) PayCashbackGrant(arg0 context.Context, arg1 db.PayCashbackGrantParams) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayCashbackGrant indicates an expected call of PayCashbackGrant.
+func (mr *MockStoreMockRecorder) PayCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayCashbackGrant", reflect.TypeOf((*MockStore)(nil).PayCashbackGrant), arg0, arg1)
+}
+
+// PayCheckoutSession mocks base method.
+func (m *MockStore) PayCheckoutSession(arg0 context.Context, arg1 db.PayCheckoutSessionParams) (db.CheckoutSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayCheckoutSession", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayCheckoutSession indicates an expected call of PayCheckoutSession.
+func (mr *MockStoreMockRecorder) PayCheckoutSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayCheckoutSession", reflect.TypeOf((*MockStore)(nil).PayCheckoutSession), arg0, arg1)
+}
+
+// PayCheckoutSessionTx mocks base method.
+func (m *MockStore) PayCheckoutSessionTx(arg0 context.Context, arg1 db.PayCheckoutSessionTxParams) (db.PayCheckoutSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayCheckoutSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayCheckoutSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayCheckoutSessionTx indicates an expected call of PayCheckoutSessionTx.
+func (mr *MockStoreMockRecorder) PayCheckoutSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayCheckoutSessionTx", reflect.TypeOf((*MockStore)(nil).PayCheckoutSessionTx), arg0, arg1)
+}
+
+// PayInstallment mocks base method.
+func (m *MockStore) PayInstallment(arg0 context.Context, arg1 db.PayInstallmentParams) (db.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayInstallment indicates an expected call of PayInstallment.
+func (mr *MockStoreMockRecorder) PayInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayInstallment", reflect.TypeOf((*MockStore)(nil).PayInstallment), arg0, arg1)
+}
+
+// PayInvoice mocks base method.
+func (m *MockStore) PayInvoice(arg0 context.Context, arg1 db.PayInvoiceParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayInvoice indicates an expected call of PayInvoice.
+func (mr *MockStoreMockRecorder) PayInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayInvoice", reflect.TypeOf((*MockStore)(nil).PayInvoice), arg0, arg1)
+}
+
+// PayInvoiceTx mocks base method.
+func (m *MockStore) PayInvoiceTx(arg0 context.Context, arg1 db.PayInvoiceTxParams) (db.PayInvoiceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayInvoiceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayInvoiceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayInvoiceTx indicates an expected call of PayInvoiceTx.
+func (mr *MockStoreMockRecorder) PayInvoiceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayInvoiceTx", reflect.TypeOf((*MockStore)(nil).PayInvoiceTx), arg0, arg1)
+}
+
+// PayPaymentRequest mocks base method.
+func (m *MockStore) PayPaymentRequest(arg0 context.Context, arg1 db.PayPaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayPaymentRequest indicates an expected call of PayPaymentRequest.
+func (mr *MockStoreMockRecorder) PayPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentRequest", reflect.TypeOf((*MockStore)(nil).PayPaymentRequest), arg0, arg1)
+}
+
+// PrepayInstallmentPlanTx mocks base method.
+func (m *MockStore) PrepayInstallmentPlanTx(arg0 context.Context, arg1 db.PrepayInstallmentPlanTxParams) (db.PrepayInstallmentPlanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepayInstallmentPlanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PrepayInstallmentPlanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepayInstallmentPlanTx indicates an expected call of PrepayInstallmentPlanTx.
+func (mr *MockStoreMockRecorder) PrepayInstallmentPlanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepayInstallmentPlanTx", reflect.TypeOf((*MockStore)(nil).PrepayInstallmentPlanTx), arg0, arg1)
+}
+
+// QuoteAnticipation mocks base method.
+func (m *MockStore) QuoteAnticipation(arg0 context.Context, arg1 db.AnticipationParams) (db.AnticipationQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteAnticipation", arg0, arg1)
+	ret0, _ := ret[0].(db.AnticipationQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteAnticipation indicates an expected call of QuoteAnticipation.
+func (mr *MockStoreMockRecorder) QuoteAnticipation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteAnticipation", reflect.TypeOf((*MockStore)(nil).QuoteAnticipation), arg0, arg1)
+}
+
+// QuoteTransferFees mocks base method.
+func (m *MockStore) QuoteTransferFees(arg0 context.Context, arg1 db.QuoteTransferFeesParams) (db.QuoteTransferFeesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransferFees", arg0, arg1)
+	ret0, _ := ret[0].(db.QuoteTransferFeesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransferFees indicates an expected call of QuoteTransferFees.
+func (mr *MockStoreMockRecorder) QuoteTransferFees(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransferFees", reflect.TypeOf((*MockStore)(nil).QuoteTransferFees), arg0, arg1)
+}
+
+// RecordPayoutReviewOutcome mocks base method.
+func (m *MockStore) RecordPayoutReviewOutcome(arg0 context.Context, arg1 db.RecordPayoutReviewOutcomeParams) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayoutReviewOutcome", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPayoutReviewOutcome indicates an expected call of RecordPayoutReviewOutcome.
+func (mr *MockStoreMockRecorder) RecordPayoutReviewOutcome(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayoutReviewOutcome", reflect.TypeOf((*MockStore)(nil).RecordPayoutReviewOutcome), arg0, arg1)
+}
+
+// RecordPayoutRowOutcome mocks base method.
+func (m *MockStore) RecordPayoutRowOutcome(arg0 context.Context, arg1 db.RecordPayoutRowOutcomeParams) (db.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayoutRowOutcome", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPayoutRowOutcome indicates an expected call of RecordPayoutRowOutcome.
+func (mr *MockStoreMockRecorder) RecordPayoutRowOutcome(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayoutRowOutcome", reflect.TypeOf((*MockStore)(nil).RecordPayoutRowOutcome), arg0, arg1)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockStore) RedeliverWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockStoreMockRecorder) RedeliverWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RedeliverWebhookDelivery), arg0, arg1)
+}
+
+// RefundTx mocks base method.
+func (m *MockStore) RefundTx(arg0 context.Context, arg1 db.RefundTxParams) (db.RefundTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RefundTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTx indicates an expected call of RefundTx.
+func (mr *MockStoreMockRecorder) RefundTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTx", reflect.TypeOf((*MockStore)(nil).RefundTx), arg0, arg1)
+}
+
+// ReleaseDueEscrowsTx mocks base method.
+func (m *MockStore) ReleaseDueEscrowsTx(arg0 context.Context, arg1 db.ReleaseDueEscrowsTxParams) ([]db.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseDueEscrowsTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseDueEscrowsTx indicates an expected call of ReleaseDueEscrowsTx.
+func (mr *MockStoreMockRecorder) ReleaseDueEscrowsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseDueEscrowsTx", reflect.TypeOf((*MockStore)(nil).ReleaseDueEscrowsTx), arg0, arg1)
+}
+
+// ReleaseReservesTx mocks base method.
+func (m *MockStore) ReleaseReservesTx(arg0 context.Context, arg1 db.ReleaseReservesTxParams) ([]db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseReservesTx indicates an expected call of ReleaseReservesTx.
+func (mr *MockStoreMockRecorder) ReleaseReservesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservesTx", reflect.TypeOf((*MockStore)(nil).ReleaseReservesTx), arg0, arg1)
+}
+
+// RemindOverdueInvoices mocks base method.
+func (m *MockStore) RemindOverdueInvoices(arg0 context.Context, arg1 db.RemindOverdueInvoicesParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemindOverdueInvoices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemindOverdueInvoices indicates an expected call of RemindOverdueInvoices.
+func (mr *MockStoreMockRecorder) RemindOverdueInvoices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemindOverdueInvoices", reflect.TypeOf((*MockStore)(nil).RemindOverdueInvoices), arg0, arg1)
+}
+
+// RemindOverdueInvoicesTx mocks base method.
+func (m *MockStore) RemindOverdueInvoicesTx(arg0 context.Context, arg1 db.RemindOverdueInvoicesTxParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemindOverdueInvoicesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemindOverdueInvoicesTx indicates an expected call of RemindOverdueInvoicesTx.
+func (mr *MockStoreMockRecorder) RemindOverdueInvoicesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemindOverdueInvoicesTx", reflect.TypeOf((*MockStore)(nil).RemindOverdueInvoicesTx), arg0, arg1)
+}
+
+// RenewSubscription mocks base method.
+func (m *MockStore) RenewSubscription(arg0 context.Context, arg1 db.RenewSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewSubscription indicates an expected call of RenewSubscription.
+func (mr *MockStoreMockRecorder) RenewSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewSubscription", reflect.TypeOf((*MockStore)(nil).RenewSubscription), arg0, arg1)
+}
+
+// RenewSubscriptionTx mocks base method.
+func (m *MockStore) RenewSubscriptionTx(arg0 context.Context, arg1 db.RenewSubscriptionTxParams) (db.RenewSubscriptionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewSubscriptionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RenewSubscriptionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewSubscriptionTx indicates an expected call of RenewSubscriptionTx.
+func (mr *MockStoreMockRecorder) RenewSubscriptionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewSubscriptionTx", reflect.TypeOf((*MockStore)(nil).RenewSubscriptionTx), arg0, arg1)
+}
+
+// RequestLimitChangeTx mocks base method.
+func (m *MockStore) RequestLimitChangeTx(arg0 context.Context, arg1 db.RequestLimitChangeTxParams) (db.LimitIncreaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestLimitChangeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LimitIncreaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestLimitChangeTx indicates an expected call of RequestLimitChangeTx.
+func (mr *MockStoreMockRecorder) RequestLimitChangeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestLimitChangeTx", reflect.TypeOf((*MockStore)(nil).RequestLimitChangeTx), arg0, arg1)
+}
+
+// ReserveStatement mocks base method.
+func (m *MockStore) ReserveStatement(arg0 context.Context, arg1 db.ReserveStatementParams) (db.ReserveStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStatement", arg0, arg1)
+	ret0, _ := ret[0].(db.ReserveStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStatement indicates an expected call of ReserveStatement.
+func (mr *MockStoreMockRecorder) ReserveStatement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStatement", reflect.TypeOf((*MockStore)(nil).ReserveStatement), arg0, arg1)
+}
+
+// ResetWebhookEndpointFailures mocks base method.
+func (m *MockStore) ResetWebhookEndpointFailures(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetWebhookEndpointFailures", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetWebhookEndpointFailures indicates an expected call of ResetWebhookEndpointFailures.
+func (mr *MockStoreMockRecorder) ResetWebhookEndpointFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWebhookEndpointFailures", reflect.TypeOf((*MockStore)(nil).ResetWebhookEndpointFailures), arg0, arg1)
+}
+
+// ResolveEscrowTx mocks base method.
+func (m *MockStore) ResolveEscrowTx(arg0 context.Context, arg1 db.ResolveEscrowTxParams) (db.EscrowTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEscrowTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEscrowTx indicates an expected call of ResolveEscrowTx.
+func (mr *MockStoreMockRecorder) ResolveEscrowTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEscrowTx", reflect.TypeOf((*MockStore)(nil).ResolveEscrowTx), arg0, arg1)
+}
+
+// RespondDispute mocks base method.
+func (m *MockStore) RespondDispute(arg0 context.Context, arg1 db.RespondDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondDispute indicates an expected call of RespondDispute.
+func (mr *MockStoreMockRecorder) RespondDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondDispute", reflect.TypeOf((*MockStore)(nil).RespondDispute), arg0, arg1)
+}
+
+// RespondDisputeTx mocks base method.
+func (m *MockStore) RespondDisputeTx(arg0 context.Context, arg1 db.RespondDisputeTxParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondDisputeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondDisputeTx indicates an expected call of RespondDisputeTx.
+func (mr *MockStoreMockRecorder) RespondDisputeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondDisputeTx", reflect.TypeOf((*MockStore)(nil).RespondDisputeTx), arg0, arg1)
+}
+
+// RetryCashbackGrant mocks base method.
+func (m *MockStore) RetryCashbackGrant(arg0 context.Context, arg1 db.RetryCashbackGrantParams) (db.CashbackGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryCashbackGrant", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryCashbackGrant indicates an expected call of RetryCashbackGrant.
+func (mr *MockStoreMockRecorder) RetryCashbackGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryCashbackGrant", reflect.TypeOf((*MockStore)(nil).RetryCashbackGrant), arg0, arg1)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockStore) RevokeApiKey(arg0 context.Context, arg1 int64) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockStoreMockRecorder) RevokeApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockStore)(nil).RevokeApiKey), arg0, arg1)
+}
+
+// RotateApiKey mocks base method.
+func (m *MockStore) RotateApiKey(arg0 context.Context, arg1 db.RotateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateApiKey indicates an expected call of RotateApiKey.
+func (mr *MockStoreMockRecorder) RotateApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateApiKey", reflect.TypeOf((*MockStore)(nil).RotateApiKey), arg0, arg1)
+}
+
+// SetEntryHash mocks base method.
+func (m *MockStore) SetEntryHash(arg0 context.Context, arg1 db.SetEntryHashParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntryHash", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEntryHash indicates an expected call of SetEntryHash.
+func (mr *MockStoreMockRecorder) SetEntryHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntryHash", reflect.TypeOf((*MockStore)(nil).SetEntryHash), arg0, arg1)
+}
+
+// SetPaymentRequestTransfer mocks base method.
+func (m *MockStore) SetPaymentRequestTransfer(arg0 context.Context, arg1 db.SetPaymentRequestTransferParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPaymentRequestTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPaymentRequestTransfer indicates an expected call of SetPaymentRequestTransfer.
+func (mr *MockStoreMockRecorder) SetPaymentRequestTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaymentRequestTransfer", reflect.TypeOf((*MockStore)(nil).SetPaymentRequestTransfer), arg0, arg1)
+}
+
+// SetRiskDecisionTransfer mocks base method.
+func (m *MockStore) SetRiskDecisionTransfer(arg0 context.Context, arg1 db.SetRiskDecisionTransferParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRiskDecisionTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRiskDecisionTransfer indicates an expected call of SetRiskDecisionTransfer.
+func (mr *MockStoreMockRecorder) SetRiskDecisionTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRiskDecisionTransfer", reflect.TypeOf((*MockStore)(nil).SetRiskDecisionTransfer), arg0, arg1)
+}
+
+// SetSubscriptionCancelAtPeriodEnd mocks base method.
+func (m *MockStore) SetSubscriptionCancelAtPeriodEnd(arg0 context.Context, arg1 db.SetSubscriptionCancelAtPeriodEndParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSubscriptionCancelAtPeriodEnd", arg0, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSubscriptionCancelAtPeriodEnd indicates an expected call of SetSubscriptionCancelAtPeriodEnd.
+func (mr *MockStoreMockRecorder) SetSubscriptionCancelAtPeriodEnd(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubscriptionCancelAtPeriodEnd", reflect.TypeOf((*MockStore)(nil).SetSubscriptionCancelAtPeriodEnd), arg0, arg1)
+}
+
+// SetTransferInvoice mocks base method.
+func (m *MockStore) SetTransferInvoice(arg0 context.Context, arg1 db.SetTransferInvoiceParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransferInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddWalletBalance indicates an expected call of AddWalletBalance.
-func (mr *MockStoreMockRecorder) AddWalletBalance(arg0, arg1 interface{}) *gomock.Call {
+// SetTransferInvoice indicates an expected call of SetTransferInvoice.
+func (mr *MockStoreMockRecorder) SetTransferInvoice(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWalletBalance", reflect.TypeOf((*MockStore)(nil).AddWalletBalance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransferInvoice", reflect.TypeOf((*MockStore)(nil).SetTransferInvoice), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+// SetTransferPaymentRequest mocks base method.
+func (m *MockStore) SetTransferPaymentRequest(arg0 context.Context, arg1 db.SetTransferPaymentRequestParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "SetTransferPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+// SetTransferPaymentRequest indicates an expected call of SetTransferPaymentRequest.
+func (mr *MockStoreMockRecorder) SetTransferPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransferPaymentRequest", reflect.TypeOf((*MockStore)(nil).SetTransferPaymentRequest), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// SetTransferSplitTransfer mocks base method.
+func (m *MockStore) SetTransferSplitTransfer(arg0 context.Context, arg1 db.SetTransferSplitTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetTransferSplitTransfer", arg0, arg1)
 	ret0, _ := ret[0].(db.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// SetTransferSplitTransfer indicates an expected call of SetTransferSplitTransfer.
+func (mr *MockStoreMockRecorder) SetTransferSplitTransfer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransferSplitTransfer", reflect.TypeOf((*MockStore)(nil).SetTransferSplitTransfer), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// SettleCashbackGrantTx mocks base method.
+func (m *MockStore) SettleCashbackGrantTx(arg0 context.Context, arg1 db.SettleCashbackGrantTxParams) (db.CashbackGrant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SettleCashbackGrantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CashbackGrant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// SettleCashbackGrantTx indicates an expected call of SettleCashbackGrantTx.
+func (mr *MockStoreMockRecorder) SettleCashbackGrantTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleCashbackGrantTx", reflect.TypeOf((*MockStore)(nil).SettleCashbackGrantTx), arg0, arg1)
 }
 
-// CreateWallet mocks base method.
-func (m *MockStore) CreateWallet(arg0 context.Context, arg1 db.CreateWalletParams) (db.Wallet, error) {
+// SettleEscrow mocks base method.
+func (m *MockStore) SettleEscrow(arg0 context.Context, arg1 db.SettleEscrowParams) (db.Escrow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWallet", arg0, arg1)
-	ret0, _ := ret[0].(db.Wallet)
+	ret := m.ctrl.Call(m, "SettleEscrow", arg0, arg1)
+	ret0, _ := ret[0].(db.Escrow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateWallet indicates an expected call of CreateWallet.
-func (mr *MockStoreMockRecorder) CreateWallet(arg0, arg1 interface{}) *gomock.Call {
+// SettleEscrow indicates an expected call of SettleEscrow.
+func (mr *MockStoreMockRecorder) SettleEscrow(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockStore)(nil).CreateWallet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleEscrow", reflect.TypeOf((*MockStore)(nil).SettleEscrow), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) error {
+// SettleHold mocks base method.
+func (m *MockStore) SettleHold(arg0 context.Context, arg1 db.SettleHoldParams) (db.Hold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "SettleHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+// SettleHold indicates an expected call of SettleHold.
+func (mr *MockStoreMockRecorder) SettleHold(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleHold", reflect.TypeOf((*MockStore)(nil).SettleHold), arg0, arg1)
 }
 
-// DeleteWallet mocks base method.
-func (m *MockStore) DeleteWallet(arg0 context.Context, arg1 int64) error {
+// SettleReceivablesTx mocks base method.
+func (m *MockStore) SettleReceivablesTx(arg0 context.Context, arg1 db.SettleReceivablesTxParams) ([]db.Receivable, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWallet", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "SettleReceivablesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Receivable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteWallet indicates an expected call of DeleteWallet.
-func (mr *MockStoreMockRecorder) DeleteWallet(arg0, arg1 interface{}) *gomock.Call {
+// SettleReceivablesTx indicates an expected call of SettleReceivablesTx.
+func (mr *MockStoreMockRecorder) SettleReceivablesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWallet", reflect.TypeOf((*MockStore)(nil).DeleteWallet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleReceivablesTx", reflect.TypeOf((*MockStore)(nil).SettleReceivablesTx), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// SettleScheduledTransferRun mocks base method.
+func (m *MockStore) SettleScheduledTransferRun(arg0 context.Context, arg1 db.SettleScheduledTransferRunParams) (db.ScheduledTransferRun, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "SettleScheduledTransferRun", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransferRun)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// SettleScheduledTransferRun indicates an expected call of SettleScheduledTransferRun.
+func (mr *MockStoreMockRecorder) SettleScheduledTransferRun(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleScheduledTransferRun", reflect.TypeOf((*MockStore)(nil).SettleScheduledTransferRun), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// SplitTransferTx mocks base method.
+func (m *MockStore) SplitTransferTx(arg0 context.Context, arg1 db.SplitTransferTxParams) (db.SplitTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "SplitTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SplitTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// SplitTransferTx indicates an expected call of SplitTransferTx.
+func (mr *MockStoreMockRecorder) SplitTransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitTransferTx", reflect.TypeOf((*MockStore)(nil).SplitTransferTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// TakeRateLimitToken mocks base method.
+func (m *MockStore) TakeRateLimitToken(arg0 context.Context, arg1 db.TakeRateLimitTokenParams) (db.RateLimitBucket, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", arg0, arg1)
+	ret0, _ := ret[0].(db.RateLimitBucket)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockStoreMockRecorder) TakeRateLimitToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockStore)(nil).TakeRateLimitToken), arg0, arg1)
 }
 
-// GetWallet mocks base method.
-func (m *MockStore) GetWallet(arg0 context.Context, arg1 int64) (db.Wallet, error) {
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TrasferTxParms) (db.TrasferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWallet", arg0, arg1)
-	ret0, _ := ret[0].(db.Wallet)
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TrasferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetWallet indicates an expected call of GetWallet.
-func (mr *MockStoreMockRecorder) GetWallet(arg0, arg1 interface{}) *gomock.Call {
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockStore)(nil).GetWallet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
 }
 
-// GetWalletForUpdate mocks base method.
-func (m *MockStore) GetWalletForUpdate(arg0 context.Context, arg1 int64) (db.Wallet, error) {
+// TrialBalance mocks base method.
+func (m *MockStore) TrialBalance(arg0 context.Context) (db.TrialBalanceResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWalletForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Wallet)
+	ret := m.ctrl.Call(m, "TrialBalance", arg0)
+	ret0, _ := ret[0].(db.TrialBalanceResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetWalletForUpdate indicates an expected call of GetWalletForUpdate.
-func (mr *MockStoreMockRecorder) GetWalletForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// TrialBalance indicates an expected call of TrialBalance.
+func (mr *MockStoreMockRecorder) TrialBalance(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletForUpdate", reflect.TypeOf((*MockStore)(nil).GetWalletForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrialBalance", reflect.TypeOf((*MockStore)(nil).TrialBalance), arg0)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// UpdateApiKeyLastUsed mocks base method.
+func (m *MockStore) UpdateApiKeyLastUsed(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "UpdateApiKeyLastUsed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApiKeyLastUsed indicates an expected call of UpdateApiKeyLastUsed.
+func (mr *MockStoreMockRecorder) UpdateApiKeyLastUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApiKeyLastUsed", reflect.TypeOf((*MockStore)(nil).UpdateApiKeyLastUsed), arg0, arg1)
+}
+
+// UpdateInstallmentPlanStatus mocks base method.
+func (m *MockStore) UpdateInstallmentPlanStatus(arg0 context.Context, arg1 db.UpdateInstallmentPlanStatusParams) (db.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstallmentPlanStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentPlan)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// UpdateInstallmentPlanStatus indicates an expected call of UpdateInstallmentPlanStatus.
+func (mr *MockStoreMockRecorder) UpdateInstallmentPlanStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstallmentPlanStatus", reflect.TypeOf((*MockStore)(nil).UpdateInstallmentPlanStatus), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// UpdateInvoice mocks base method.
+func (m *MockStore) UpdateInvoice(arg0 context.Context, arg1 db.UpdateInvoiceParams) (db.Invoice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "UpdateInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// UpdateInvoice indicates an expected call of UpdateInvoice.
+func (mr *MockStoreMockRecorder) UpdateInvoice(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoice", reflect.TypeOf((*MockStore)(nil).UpdateInvoice), arg0, arg1)
 }
 
-// ListUsers mocks base method.
-func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
+// UpdateInvoiceTx mocks base method.
+func (m *MockStore) UpdateInvoiceTx(arg0 context.Context, arg1 db.UpdateInvoiceTxParams) (db.InvoiceTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
-	ret0, _ := ret[0].([]db.User)
+	ret := m.ctrl.Call(m, "UpdateInvoiceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoiceTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListUsers indicates an expected call of ListUsers.
-func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+// UpdateInvoiceTx indicates an expected call of UpdateInvoiceTx.
+func (mr *MockStoreMockRecorder) UpdateInvoiceTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoiceTx", reflect.TypeOf((*MockStore)(nil).UpdateInvoiceTx), arg0, arg1)
 }
 
-// ListWallets mocks base method.
-func (m *MockStore) ListWallets(arg0 context.Context, arg1 db.ListWalletsParams) ([]db.Wallet, error) {
+// UpdatePayoutBatchStatus mocks base method.
+func (m *MockStore) UpdatePayoutBatchStatus(arg0 context.Context, arg1 db.UpdatePayoutBatchStatusParams) (db.PayoutBatch, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListWallets", arg0, arg1)
-	ret0, _ := ret[0].([]db.Wallet)
+	ret := m.ctrl.Call(m, "UpdatePayoutBatchStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListWallets indicates an expected call of ListWallets.
-func (mr *MockStoreMockRecorder) ListWallets(arg0, arg1 interface{}) *gomock.Call {
+// UpdatePayoutBatchStatus indicates an expected call of UpdatePayoutBatchStatus.
+func (mr *MockStoreMockRecorder) UpdatePayoutBatchStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWallets", reflect.TypeOf((*MockStore)(nil).ListWallets), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayoutBatchStatus", reflect.TypeOf((*MockStore)(nil).UpdatePayoutBatchStatus), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TrasferTxParms) (db.TrasferTxResult, error) {
+// UpdatePayoutBatchTx mocks base method.
+func (m *MockStore) UpdatePayoutBatchTx(arg0 context.Context, arg1 db.UpdatePayoutBatchTxParams) (db.PayoutBatch, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TrasferTxResult)
+	ret := m.ctrl.Call(m, "UpdatePayoutBatchTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutBatch)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// UpdatePayoutBatchTx indicates an expected call of UpdatePayoutBatchTx.
+func (mr *MockStoreMockRecorder) UpdatePayoutBatchTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayoutBatchTx", reflect.TypeOf((*MockStore)(nil).UpdatePayoutBatchTx), arg0, arg1)
+}
+
+// UpdatePayoutRow mocks base method.
+func (m *MockStore) UpdatePayoutRow(arg0 context.Context, arg1 db.UpdatePayoutRowParams) (db.PayoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayoutRow", arg0, arg1)
+	ret0, _ := ret[0].(db.PayoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePayoutRow indicates an expected call of UpdatePayoutRow.
+func (mr *MockStoreMockRecorder) UpdatePayoutRow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayoutRow", reflect.TypeOf((*MockStore)(nil).UpdatePayoutRow), arg0, arg1)
+}
+
+// UpdateScheduledTransfer mocks base method.
+func (m *MockStore) UpdateScheduledTransfer(arg0 context.Context, arg1 db.UpdateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledTransfer indicates an expected call of UpdateScheduledTransfer.
+func (mr *MockStoreMockRecorder) UpdateScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledTransfer", reflect.TypeOf((*MockStore)(nil).UpdateScheduledTransfer), arg0, arg1)
 }
 
 // UpdateUser mocks base method.
@@ -303,6 +4928,36 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
 
+// UpdateUserMerchantCategory mocks base method.
+func (m *MockStore) UpdateUserMerchantCategory(arg0 context.Context, arg1 db.UpdateUserMerchantCategoryParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserMerchantCategory", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserMerchantCategory indicates an expected call of UpdateUserMerchantCategory.
+func (mr *MockStoreMockRecorder) UpdateUserMerchantCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMerchantCategory", reflect.TypeOf((*MockStore)(nil).UpdateUserMerchantCategory), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
 // UpdateWallet mocks base method.
 func (m *MockStore) UpdateWallet(arg0 context.Context, arg1 db.UpdateWalletParams) (db.Wallet, error) {
 	m.ctrl.T.Helper()
@@ -317,3 +4972,153 @@ func (mr *MockStoreMockRecorder) UpdateWallet(arg0, arg1 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWallet", reflect.TypeOf((*MockStore)(nil).UpdateWallet), arg0, arg1)
 }
+
+// UpdateWalletFrozen mocks base method.
+func (m *MockStore) UpdateWalletFrozen(arg0 context.Context, arg1 db.UpdateWalletFrozenParams) (db.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWalletFrozen", arg0, arg1)
+	ret0, _ := ret[0].(db.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWalletFrozen indicates an expected call of UpdateWalletFrozen.
+func (mr *MockStoreMockRecorder) UpdateWalletFrozen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWalletFrozen", reflect.TypeOf((*MockStore)(nil).UpdateWalletFrozen), arg0, arg1)
+}
+
+// UpsertInstallmentFundingOptIn mocks base method.
+func (m *MockStore) UpsertInstallmentFundingOptIn(arg0 context.Context, arg1 db.UpsertInstallmentFundingOptInParams) (db.InstallmentFundingOptIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertInstallmentFundingOptIn", arg0, arg1)
+	ret0, _ := ret[0].(db.InstallmentFundingOptIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertInstallmentFundingOptIn indicates an expected call of UpsertInstallmentFundingOptIn.
+func (mr *MockStoreMockRecorder) UpsertInstallmentFundingOptIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInstallmentFundingOptIn", reflect.TypeOf((*MockStore)(nil).UpsertInstallmentFundingOptIn), arg0, arg1)
+}
+
+// UpsertReserveRule mocks base method.
+func (m *MockStore) UpsertReserveRule(arg0 context.Context, arg1 db.UpsertReserveRuleParams) (db.ReserveRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReserveRule", arg0, arg1)
+	ret0, _ := ret[0].(db.ReserveRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertReserveRule indicates an expected call of UpsertReserveRule.
+func (mr *MockStoreMockRecorder) UpsertReserveRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReserveRule", reflect.TypeOf((*MockStore)(nil).UpsertReserveRule), arg0, arg1)
+}
+
+// UpsertSettlementPlan mocks base method.
+func (m *MockStore) UpsertSettlementPlan(arg0 context.Context, arg1 db.UpsertSettlementPlanParams) (db.SettlementPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertSettlementPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.SettlementPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertSettlementPlan indicates an expected call of UpsertSettlementPlan.
+func (mr *MockStoreMockRecorder) UpsertSettlementPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSettlementPlan", reflect.TypeOf((*MockStore)(nil).UpsertSettlementPlan), arg0, arg1)
+}
+
+// UpsertTransferLimits mocks base method.
+func (m *MockStore) UpsertTransferLimits(arg0 context.Context, arg1 db.UpsertTransferLimitsParams) (db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTransferLimits indicates an expected call of UpsertTransferLimits.
+func (mr *MockStoreMockRecorder) UpsertTransferLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTransferLimits", reflect.TypeOf((*MockStore)(nil).UpsertTransferLimits), arg0, arg1)
+}
+
+// UseReserve mocks base method.
+func (m *MockStore) UseReserve(arg0 context.Context, arg1 db.UseReserveParams) (db.Reserve, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseReserve", arg0, arg1)
+	ret0, _ := ret[0].(db.Reserve)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseReserve indicates an expected call of UseReserve.
+func (mr *MockStoreMockRecorder) UseReserve(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseReserve", reflect.TypeOf((*MockStore)(nil).UseReserve), arg0, arg1)
+}
+
+// VerifyChain mocks base method.
+func (m *MockStore) VerifyChain(arg0 context.Context, arg1 db.VerifyChainParams) (db.VerifyChainResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChain", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyChainResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChain indicates an expected call of VerifyChain.
+func (mr *MockStoreMockRecorder) VerifyChain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChain", reflect.TypeOf((*MockStore)(nil).VerifyChain), arg0, arg1)
+}
+
+// VoidHoldTx mocks base method.
+func (m *MockStore) VoidHoldTx(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHoldTx indicates an expected call of VoidHoldTx.
+func (mr *MockStoreMockRecorder) VoidHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHoldTx", reflect.TypeOf((*MockStore)(nil).VoidHoldTx), arg0, arg1)
+}
+
+// VoidInvoice mocks base method.
+func (m *MockStore) VoidInvoice(arg0 context.Context, arg1 db.VoidInvoiceParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidInvoice indicates an expected call of VoidInvoice.
+func (mr *MockStoreMockRecorder) VoidInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidInvoice", reflect.TypeOf((*MockStore)(nil).VoidInvoice), arg0, arg1)
+}
+
+// VoidInvoiceTx mocks base method.
+func (m *MockStore) VoidInvoiceTx(arg0 context.Context, arg1 db.VoidInvoiceTxParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidInvoiceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidInvoiceTx indicates an expected call of VoidInvoiceTx.
+func (mr *MockStoreMockRecorder) VoidInvoiceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidInvoiceTx", reflect.TypeOf((*MockStore)(nil).VoidInvoiceTx), arg0, arg1)
+}
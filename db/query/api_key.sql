@@ -0,0 +1,54 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+  owner,
+  name,
+  prefix,
+  hashed_key,
+  scopes
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetApiKey :one
+SELECT * FROM api_keys
+WHERE id = $1 LIMIT 1;
+
+-- name: GetApiKeyByPrefix :one
+SELECT * FROM api_keys
+WHERE prefix = $1 LIMIT 1;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys
+WHERE owner = $1
+ORDER BY id;
+
+-- name: RotateApiKey :one
+UPDATE api_keys
+SET 
+  prefix = $2,
+  hashed_key = $3
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: UpdateApiKeyLastUsed :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: CreateApiKeyNonce :execrows
+INSERT INTO api_key_nonces (
+  api_key_id,
+  nonce
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: DeleteExpiredApiKeyNonces :exec
+DELETE FROM api_key_nonces
+WHERE api_key_id = $1 AND created_at < $2;
@@ -0,0 +1,129 @@
+-- name: CreateCashbackCampaign :one
+INSERT INTO cashback_campaigns (
+  name,
+  currency,
+  rate,
+  max_cashback,
+  min_amount,
+  merchant_category,
+  starts_at,
+  ends_at,
+  budget,
+  per_user_limit,
+  settle_days,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: GetCashbackCampaign :one
+SELECT * FROM cashback_campaigns
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCashbackCampaignForUpdate :one
+SELECT * FROM cashback_campaigns
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListCashbackCampaigns :many
+SELECT * FROM cashback_campaigns
+WHERE NOT sqlc.arg(active_only)::boolean
+  OR (active AND ends_at > sqlc.arg(now)::timestamptz)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListEligibleCashbackCampaigns :many
+SELECT * FROM cashback_campaigns
+WHERE active
+  AND currency = sqlc.arg(currency)
+  AND starts_at <= sqlc.arg(now)::timestamptz
+  AND ends_at > sqlc.arg(now)::timestamptz
+  AND min_amount <= sqlc.arg(amount)::bigint
+  AND (merchant_category = '' OR merchant_category = sqlc.arg(merchant_category))
+  AND spent < budget
+ORDER BY rate DESC, id;
+
+-- name: DeactivateCashbackCampaign :one
+UPDATE cashback_campaigns
+SET active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: AddCashbackCampaignSpent :one
+UPDATE cashback_campaigns
+SET spent = spent + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateCashbackGrant :one
+INSERT INTO cashback_grants (
+  campaign_id,
+  transfer_id,
+  wallet_id,
+  owner,
+  amount,
+  settle_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetCashbackGrant :one
+SELECT * FROM cashback_grants
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCashbackGrantByTransferForUpdate :one
+SELECT * FROM cashback_grants
+WHERE transfer_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetCashbackUserTotal :one
+SELECT COALESCE(SUM(amount - clawed_back), 0)::bigint AS total
+FROM cashback_grants
+WHERE campaign_id = $1 AND owner = $2;
+
+-- name: ListWalletCashbackGrants :many
+SELECT * FROM cashback_grants
+WHERE wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueCashbackGrant :one
+SELECT * FROM cashback_grants
+WHERE status = 'pending' AND settle_at <= sqlc.arg(now)::timestamptz
+ORDER BY settle_at
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: PayCashbackGrant :one
+UPDATE cashback_grants
+SET
+  status = 'paid',
+  funding_wallet_id = $2,
+  credit_transfer_id = $3,
+  paid_at = $4,
+  last_error = '',
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: RetryCashbackGrant :one
+UPDATE cashback_grants
+SET
+  settle_at = $2,
+  last_error = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClawBackCashbackGrant :one
+UPDATE cashback_grants
+SET
+  clawed_back = clawed_back + sqlc.arg(amount),
+  status = CASE WHEN clawed_back + sqlc.arg(amount) >= amount THEN 'clawed_back' ELSE status END,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
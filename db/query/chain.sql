@@ -0,0 +1,59 @@
+-- name: SetEntryHash :one
+UPDATE entries
+SET prev_hash = $2, hash = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPreviousEntryHash :one
+SELECT hash FROM entries
+WHERE wallet_id = $1 AND id < $2
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListChainEntries :many
+SELECT * FROM entries
+WHERE wallet_id > sqlc.arg(after_wallet_id)
+OR (wallet_id = sqlc.arg(after_wallet_id) AND id > sqlc.arg(after_id))
+ORDER BY wallet_id, id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListEntriesInRange :many
+SELECT * FROM entries
+WHERE id > sqlc.arg(after_id) AND id <= sqlc.arg(up_to_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: GetLastEntryIDBefore :one
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM entries
+WHERE created_at < sqlc.arg(before)::timestamptz;
+
+-- name: CreateChainCheckpoint :one
+INSERT INTO chain_checkpoints (
+    from_entry_id,
+    to_entry_id,
+    entry_count,
+    prev_digest,
+    digest,
+    public_key,
+    signature
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetLastChainCheckpoint :one
+SELECT * FROM chain_checkpoints
+ORDER BY to_entry_id DESC
+LIMIT 1;
+
+-- name: ListChainCheckpoints :many
+SELECT * FROM chain_checkpoints
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListRecentChainCheckpoints :many
+SELECT * FROM chain_checkpoints
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
@@ -0,0 +1,107 @@
+-- name: CreatePaymentLink :one
+INSERT INTO payment_links (
+  token,
+  merchant_wallet_id,
+  amount,
+  min_amount,
+  max_amount,
+  currency,
+  description,
+  success_url,
+  cancel_url,
+  metadata,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetPaymentLinkByToken :one
+SELECT * FROM payment_links
+WHERE token = $1 LIMIT 1;
+
+-- name: ListWalletPaymentLinks :many
+SELECT * FROM payment_links
+WHERE merchant_wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: DeactivatePaymentLink :one
+UPDATE payment_links
+SET active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateCheckoutSession :one
+INSERT INTO checkout_sessions (
+  token,
+  merchant_wallet_id,
+  payment_link_id,
+  amount,
+  currency,
+  description,
+  success_url,
+  cancel_url,
+  metadata,
+  expires_at,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetCheckoutSession :one
+SELECT * FROM checkout_sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCheckoutSessionByToken :one
+SELECT * FROM checkout_sessions
+WHERE token = $1 LIMIT 1;
+
+-- name: GetCheckoutSessionByTokenForUpdate :one
+SELECT * FROM checkout_sessions
+WHERE token = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetCheckoutSessionByReview :one
+SELECT * FROM checkout_sessions
+WHERE review_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListWalletCheckoutSessions :many
+SELECT * FROM checkout_sessions
+WHERE merchant_wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: PayCheckoutSession :one
+UPDATE checkout_sessions
+SET
+  status = $2,
+  paid_by = $3,
+  from_wallet_id = $4,
+  transfer_id = $5,
+  review_id = $6,
+  paid_at = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: ExpireCheckoutSession :one
+UPDATE checkout_sessions
+SET status = 'expired'
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: ExpireDueCheckoutSessions :many
+UPDATE checkout_sessions
+SET status = 'expired'
+WHERE id IN (
+  SELECT id FROM checkout_sessions
+  WHERE status = 'open' AND expires_at <= sqlc.arg(now)::timestamptz
+  ORDER BY expires_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
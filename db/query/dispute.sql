@@ -0,0 +1,105 @@
+-- name: CreateDispute :one
+INSERT INTO disputes (
+  transfer_id,
+  opened_by,
+  merchant,
+  amount,
+  reason,
+  respond_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetDispute :one
+SELECT * FROM disputes
+WHERE id = $1 LIMIT 1;
+
+-- name: GetDisputeForUpdate :one
+SELECT * FROM disputes
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListDisputesByStatus :many
+SELECT * FROM disputes
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListUserDisputes :many
+SELECT * FROM disputes
+WHERE opened_by = sqlc.arg(username) OR merchant = sqlc.arg(username)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: RespondDispute :one
+UPDATE disputes
+SET
+  status = 'under_review',
+  merchant_response = $2,
+  responded_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimOverdueDisputes :many
+UPDATE disputes
+SET status = 'under_review'
+WHERE id IN (
+  SELECT id FROM disputes
+  WHERE status = 'open' AND respond_by <= sqlc.arg(now)::timestamptz
+  ORDER BY respond_by
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DecideDispute :one
+UPDATE disputes
+SET
+  status = $2,
+  decided_by = $3,
+  decision_note = $4,
+  reversal_transfer_id = $5,
+  decided_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetMerchantDisputeCounts :one
+SELECT
+  count(*)::bigint AS disputes,
+  count(*) FILTER (WHERE status = 'payer_won')::bigint AS payer_won
+FROM disputes
+WHERE merchant = sqlc.arg(merchant) AND created_at >= sqlc.arg(since)::timestamptz;
+
+-- name: CountMerchantTransfersSince :one
+SELECT count(*) FROM transfers
+JOIN wallets ON wallets.id = transfers.to_wallet_id
+WHERE wallets.owner = sqlc.arg(merchant) AND transfers.created_at >= sqlc.arg(since)::timestamptz;
+
+-- name: CreateDisputeEvidence :one
+INSERT INTO dispute_evidence (
+  dispute_id,
+  uploaded_by,
+  file_name,
+  content_type,
+  size,
+  blob_key
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetDisputeEvidence :one
+SELECT * FROM dispute_evidence
+WHERE id = $1 AND dispute_id = $2
+LIMIT 1;
+
+-- name: ListDisputeEvidence :many
+SELECT * FROM dispute_evidence
+WHERE dispute_id = $1
+ORDER BY id;
+
+-- name: GetDisputeByTransfer :one
+SELECT * FROM disputes
+WHERE transfer_id = $1 LIMIT 1;
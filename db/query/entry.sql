@@ -15,4 +15,14 @@ SELECT * FROM entries
 WHERE wallet_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListEntriesAfter :many
+SELECT * FROM entries
+WHERE wallet_id = sqlc.arg(wallet_id) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: GetLastEntryID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM entries
+WHERE wallet_id = $1;
@@ -0,0 +1,64 @@
+-- name: CreateEscrow :one
+INSERT INTO escrows (
+  buyer_wallet_id,
+  seller_wallet_id,
+  amount,
+  description,
+  release_at,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetEscrow :one
+SELECT * FROM escrows
+WHERE id = $1 LIMIT 1;
+
+-- name: GetEscrowForUpdate :one
+SELECT * FROM escrows
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListWalletEscrows :many
+SELECT * FROM escrows
+WHERE buyer_wallet_id = sqlc.arg(wallet_id) OR seller_wallet_id = sqlc.arg(wallet_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: AgreeEscrowRefund :one
+UPDATE escrows
+SET
+  buyer_agreed_refund = buyer_agreed_refund OR sqlc.arg(buyer)::boolean,
+  seller_agreed_refund = seller_agreed_refund OR sqlc.arg(seller)::boolean
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DisputeEscrow :one
+UPDATE escrows
+SET
+  status = 'disputed',
+  disputed_by = $2,
+  dispute_reason = $3,
+  disputed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SettleEscrow :one
+UPDATE escrows
+SET
+  status = $2,
+  transfer_id = $3,
+  decided_by = $4,
+  note = $5,
+  decided_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimDueEscrows :many
+SELECT * FROM escrows
+WHERE status = 'held' AND release_at <= sqlc.arg(now)::timestamptz
+ORDER BY release_at
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
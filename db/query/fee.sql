@@ -0,0 +1,58 @@
+-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (
+    operation,
+    user_type,
+    currency,
+    kind,
+    fixed_amount,
+    basis_points,
+    min_fee,
+    max_fee,
+    tiers,
+    effective_from,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE id = $1 LIMIT 1;
+
+-- name: GetActiveFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE operation = sqlc.arg(operation)
+AND user_type = sqlc.arg(user_type)
+AND currency = sqlc.arg(currency)
+AND effective_from <= sqlc.arg(now)::timestamptz
+ORDER BY effective_from DESC, id DESC
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY operation, user_type, currency, effective_from DESC, id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: DeleteFutureFeeSchedule :execrows
+DELETE FROM fee_schedules
+WHERE id = sqlc.arg(id)
+AND effective_from > sqlc.arg(now)::timestamptz;
+
+-- name: CreateFeeEntry :one
+INSERT INTO entries (
+    wallet_id,
+    amount,
+    kind,
+    transfer_id,
+    fee_schedule_id
+) VALUES (
+    $1, $2, 'fee', $3, $4
+)
+RETURNING *;
+
+-- name: ListTransferFeeEntries :many
+SELECT * FROM entries
+WHERE transfer_id = $1 AND kind = 'fee'
+ORDER BY id;
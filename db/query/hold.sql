@@ -0,0 +1,44 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  wallet_id,
+  merchant_wallet_id,
+  amount,
+  created_by,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListWalletHolds :many
+SELECT * FROM holds
+WHERE wallet_id = $1 OR merchant_wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SettleHold :one
+UPDATE holds
+SET
+  status = $2,
+  captured_amount = $3,
+  transfer_id = $4,
+  decided_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimExpiredHolds :many
+SELECT * FROM holds
+WHERE status = 'authorized' AND expires_at <= sqlc.arg(now)::timestamptz
+ORDER BY expires_at
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
@@ -0,0 +1,125 @@
+-- name: CreateInstallmentPlan :one
+INSERT INTO installment_plans (
+  customer_wallet_id,
+  merchant_wallet_id,
+  amount,
+  interest_rate,
+  total_amount,
+  installment_count,
+  funding_wallet_id,
+  funding_transfer_id,
+  description,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetInstallmentPlan :one
+SELECT * FROM installment_plans
+WHERE id = $1 LIMIT 1;
+
+-- name: GetInstallmentPlanForUpdate :one
+SELECT * FROM installment_plans
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListWalletInstallmentPlans :many
+SELECT * FROM installment_plans
+WHERE customer_wallet_id = sqlc.arg(wallet_id) OR merchant_wallet_id = sqlc.arg(wallet_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdateInstallmentPlanStatus :one
+UPDATE installment_plans
+SET
+  status = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateInstallment :one
+INSERT INTO installments (
+  plan_id,
+  number,
+  amount,
+  principal,
+  due_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListPlanInstallments :many
+SELECT * FROM installments
+WHERE plan_id = $1
+ORDER BY number;
+
+-- name: LockOpenInstallments :many
+SELECT * FROM installments
+WHERE plan_id = $1 AND status IN ('pending', 'overdue')
+ORDER BY number
+FOR UPDATE;
+
+-- name: CountLateInstallments :one
+SELECT
+  count(*) FILTER (WHERE status IN ('pending', 'overdue'))::bigint AS open,
+  count(*) FILTER (WHERE status = 'overdue' OR (status = 'pending' AND attempts > 0))::bigint AS late
+FROM installments
+WHERE plan_id = $1;
+
+-- name: ClaimDueInstallment :one
+SELECT * FROM installments
+WHERE status = 'pending' AND COALESCE(retry_at, due_at) <= sqlc.arg(now)::timestamptz
+ORDER BY due_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: PayInstallment :one
+UPDATE installments
+SET
+  status = 'paid',
+  paid_amount = $2,
+  transfer_id = $3,
+  retry_at = NULL,
+  paid_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: FailInstallment :one
+UPDATE installments
+SET
+  status = $2,
+  attempts = $3,
+  retry_at = $4,
+  last_error = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelOpenInstallments :many
+UPDATE installments
+SET
+  status = 'cancelled',
+  retry_at = NULL
+WHERE plan_id = $1 AND status IN ('pending', 'overdue')
+RETURNING *;
+
+-- name: GetInstallmentFundingOptIn :one
+SELECT * FROM installment_funding_opt_ins
+WHERE owner = $1 LIMIT 1;
+
+-- name: UpsertInstallmentFundingOptIn :one
+INSERT INTO installment_funding_opt_ins (
+  owner,
+  opted_in_by
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (owner) DO UPDATE
+SET
+  opted_in_by = EXCLUDED.opted_in_by,
+  created_at = now()
+RETURNING *;
+
+-- name: DeleteInstallmentFundingOptIn :exec
+DELETE FROM installment_funding_opt_ins WHERE owner = $1;
@@ -0,0 +1,152 @@
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+  issuer,
+  merchant_wallet_id,
+  customer,
+  currency,
+  memo,
+  due_at,
+  subtotal,
+  discount,
+  tax,
+  total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetInvoice :one
+SELECT * FROM invoices
+WHERE id = $1 LIMIT 1;
+
+-- name: GetInvoiceForUpdate :one
+SELECT * FROM invoices
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetInvoiceByReview :one
+SELECT * FROM invoices
+WHERE review_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListOutgoingInvoices :many
+SELECT * FROM invoices
+WHERE issuer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListIncomingInvoices :many
+SELECT * FROM invoices
+WHERE customer = $1 AND status <> 'draft'
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateInvoice :one
+UPDATE invoices
+SET
+  merchant_wallet_id = $2,
+  customer = $3,
+  currency = $4,
+  memo = $5,
+  due_at = $6,
+  subtotal = $7,
+  discount = $8,
+  tax = $9,
+  total = $10,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: FinalizeInvoice :one
+UPDATE invoices
+SET
+  status = 'open',
+  number = $2,
+  finalized_at = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: VoidInvoice :one
+UPDATE invoices
+SET
+  status = 'void',
+  voided_at = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: PayInvoice :one
+UPDATE invoices
+SET
+  status = $2,
+  from_wallet_id = $3,
+  review_id = $4,
+  transfer_id = $5,
+  paid_at = $6,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: RemindOverdueInvoices :many
+UPDATE invoices
+SET
+  status = 'overdue',
+  reminded_at = sqlc.arg(now)::timestamptz,
+  reminder_count = reminder_count + 1,
+  updated_at = now()
+WHERE id IN (
+  SELECT id FROM invoices
+  WHERE status IN ('open', 'overdue')
+    AND review_id IS NULL
+    AND due_at <= sqlc.arg(now)::timestamptz
+    AND (reminded_at IS NULL OR reminded_at <= sqlc.arg(remind_before)::timestamptz)
+  ORDER BY due_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CreateInvoiceItem :one
+INSERT INTO invoice_items (
+  invoice_id,
+  position,
+  description,
+  quantity,
+  unit_amount,
+  discount,
+  tax_name,
+  tax_rate,
+  tax,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: ListInvoiceItems :many
+SELECT * FROM invoice_items
+WHERE invoice_id = $1
+ORDER BY position;
+
+-- name: DeleteInvoiceItems :exec
+DELETE FROM invoice_items
+WHERE invoice_id = $1;
+
+-- name: NextInvoiceNumber :one
+INSERT INTO invoice_sequences (
+  issuer,
+  last_number
+) VALUES (
+  $1, 1
+) ON CONFLICT (issuer) DO UPDATE
+SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number;
+
+-- name: SetTransferInvoice :one
+UPDATE transfers
+SET invoice_id = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,86 @@
+-- name: CreateLedgerAccount :one
+INSERT INTO ledger_accounts (
+    code,
+    name,
+    type,
+    currency,
+    wallet_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetSystemLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE code = $1 AND currency = $2 AND wallet_id IS NULL
+LIMIT 1;
+
+-- name: GetWalletLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE wallet_id = $1
+LIMIT 1;
+
+-- name: ListSystemLedgerAccounts :many
+SELECT * FROM ledger_accounts
+WHERE wallet_id IS NULL
+ORDER BY currency, code;
+
+-- name: GetLedgerAccountBalance :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS balance FROM postings
+WHERE account_id = $1;
+
+-- name: CreateJournal :one
+INSERT INTO journals (
+    kind,
+    description,
+    transfer_id
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetJournal :one
+SELECT * FROM journals
+WHERE id = $1 LIMIT 1;
+
+-- name: CreatePosting :one
+INSERT INTO postings (
+    journal_id,
+    account_id,
+    amount
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListJournalPostings :many
+SELECT * FROM postings
+WHERE journal_id = $1
+ORDER BY id;
+
+-- name: GetTrialBalance :many
+SELECT
+    a.currency,
+    (CASE WHEN a.wallet_id IS NULL THEN a.code ELSE 'wallets' END)::varchar AS code,
+    a.type,
+    COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0)::bigint AS debits,
+    COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0)::bigint AS credits
+FROM ledger_accounts a
+LEFT JOIN postings p ON p.account_id = a.id
+GROUP BY a.currency, 2, a.type
+ORDER BY a.currency, 2;
+
+-- name: CountUnbalancedJournals :one
+SELECT COUNT(*) AS count FROM (
+    SELECT journal_id FROM postings
+    GROUP BY journal_id
+    HAVING SUM(amount) <> 0
+) AS unbalanced;
+
+-- name: CountWalletLedgerMismatches :one
+SELECT COUNT(*) AS count FROM wallets w
+JOIN ledger_accounts a ON a.wallet_id = w.id
+WHERE w.balance <> -(
+    SELECT COALESCE(SUM(p.amount), 0) FROM postings p
+    WHERE p.account_id = a.id
+);
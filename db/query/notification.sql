@@ -0,0 +1,22 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  owner,
+  type,
+  message,
+  data
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE owner = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND owner = $2
+RETURNING *;
@@ -0,0 +1,85 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+  requester,
+  to_wallet_id,
+  payer,
+  amount,
+  currency,
+  description,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetPaymentRequestByReview :one
+SELECT * FROM payment_requests
+WHERE review_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListOutgoingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE requester = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListIncomingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE payer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: DecidePaymentRequest :one
+UPDATE payment_requests
+SET 
+  status = sqlc.arg(status),
+  decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending' AND expires_at > sqlc.arg(now)::timestamptz
+RETURNING *;
+
+-- name: ExpirePaymentRequest :one
+UPDATE payment_requests
+SET 
+  status = 'expired',
+  decided_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: PayPaymentRequest :one
+UPDATE payment_requests
+SET 
+  status = 'accepted',
+  paid_by = $2,
+  from_wallet_id = $3,
+  transfer_id = $4,
+  review_id = $5,
+  decided_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetPaymentRequestTransfer :one
+UPDATE payment_requests
+SET 
+  status = $2,
+  transfer_id = $3,
+  review_id = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: SetTransferPaymentRequest :one
+UPDATE transfers
+SET payment_request_id = $2
+WHERE id = $1
+RETURNING *;
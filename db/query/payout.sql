@@ -0,0 +1,142 @@
+-- name: CreatePayoutBatch :one
+INSERT INTO payout_batches (
+    owner,
+    from_wallet_id,
+    currency,
+    description,
+    row_count,
+    total_amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetPayoutBatch :one
+SELECT * FROM payout_batches
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPayoutBatchForUpdate :one
+SELECT * FROM payout_batches
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPayoutBatches :many
+SELECT * FROM payout_batches
+WHERE owner = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimPayoutBatch :one
+SELECT * FROM payout_batches
+WHERE status IN ('queued', 'processing')
+ORDER BY id
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: UpdatePayoutBatchStatus :one
+UPDATE payout_batches
+SET
+  status = sqlc.arg(status),
+  updated_at = now()
+WHERE id = sqlc.arg(id) AND status = ANY(sqlc.arg(from_statuses)::varchar[])
+RETURNING *;
+
+-- name: RecordPayoutRowOutcome :one
+UPDATE payout_batches
+SET
+  status = 'processing',
+  succeeded_count = succeeded_count + sqlc.arg(succeeded)::int,
+  succeeded_amount = succeeded_amount + sqlc.arg(succeeded_amount)::bigint,
+  review_count = review_count + sqlc.arg(review)::int,
+  failed_count = failed_count + sqlc.arg(failed)::int,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: RecordPayoutReviewOutcome :one
+UPDATE payout_batches
+SET
+  succeeded_count = succeeded_count + sqlc.arg(succeeded)::int,
+  succeeded_amount = succeeded_amount + sqlc.arg(succeeded_amount)::bigint,
+  review_count = review_count - 1,
+  failed_count = failed_count + sqlc.arg(failed)::int,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CompletePayoutBatch :one
+UPDATE payout_batches
+SET
+  status = 'completed',
+  updated_at = now(),
+  completed_at = sqlc.arg(now)::timestamptz
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelPayoutBatch :one
+UPDATE payout_batches
+SET
+  status = 'cancelled',
+  cancelled_count = cancelled_count + sqlc.arg(cancelled)::int,
+  updated_at = now(),
+  completed_at = sqlc.arg(now)::timestamptz
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePayoutRow :one
+INSERT INTO payout_rows (
+    batch_id,
+    row_number,
+    recipient_key,
+    to_wallet_id,
+    amount,
+    currency,
+    reference
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetNextPayoutRow :one
+SELECT * FROM payout_rows
+WHERE batch_id = $1 AND status = 'pending'
+ORDER BY row_number
+LIMIT 1
+FOR UPDATE;
+
+-- name: UpdatePayoutRow :one
+UPDATE payout_rows
+SET
+  status = $2,
+  transfer_id = $3,
+  error = $4,
+  processed_at = $5,
+  review_id = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPayoutRowByReview :one
+SELECT * FROM payout_rows
+WHERE review_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: CancelPendingPayoutRows :execrows
+UPDATE payout_rows
+SET
+  status = 'cancelled',
+  processed_at = sqlc.arg(now)::timestamptz
+WHERE batch_id = sqlc.arg(batch_id) AND status = 'pending';
+
+-- name: ListPayoutRows :many
+SELECT * FROM payout_rows
+WHERE batch_id = $1
+ORDER BY row_number
+LIMIT $2
+OFFSET $3;
+
+-- name: ListAllPayoutRows :many
+SELECT * FROM payout_rows
+WHERE batch_id = $1
+ORDER BY row_number;
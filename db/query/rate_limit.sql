@@ -0,0 +1,22 @@
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets (
+  key,
+  tokens,
+  allowed
+) VALUES (
+  sqlc.arg(key), sqlc.arg(burst)::float8 - 1, true
+)
+ON CONFLICT (key) DO UPDATE
+SET
+  tokens = CASE
+    WHEN LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)) >= 1
+    THEN LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)) - 1
+    ELSE LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at))
+  END,
+  allowed = LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)) >= 1,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1;
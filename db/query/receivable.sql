@@ -0,0 +1,103 @@
+-- name: GetSettlementPlan :one
+SELECT * FROM settlement_plans
+WHERE owner = $1 LIMIT 1;
+
+-- name: UpsertSettlementPlan :one
+INSERT INTO settlement_plans (
+  owner,
+  settle_days,
+  anticipation_rate,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (owner) DO UPDATE
+SET
+  settle_days = EXCLUDED.settle_days,
+  anticipation_rate = EXCLUDED.anticipation_rate,
+  updated_by = EXCLUDED.updated_by,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteSettlementPlan :exec
+DELETE FROM settlement_plans WHERE owner = $1;
+
+-- name: CreateReceivable :one
+INSERT INTO receivables (
+  wallet_id,
+  transfer_id,
+  amount,
+  settle_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetTransferReceivableForUpdate :one
+SELECT * FROM receivables
+WHERE wallet_id = $1 AND transfer_id = $2 AND status = 'pending'
+LIMIT 1
+FOR UPDATE;
+
+-- name: DeductReceivable :one
+UPDATE receivables
+SET amount = amount - sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListReceivableSchedule :many
+SELECT
+  settle_at,
+  count(*)::bigint AS receivables,
+  sum(amount)::bigint AS amount
+FROM receivables
+WHERE wallet_id = $1 AND status = 'pending'
+GROUP BY settle_at
+ORDER BY settle_at;
+
+-- name: ListAnticipatableReceivables :many
+SELECT * FROM receivables
+WHERE wallet_id = sqlc.arg(wallet_id)
+  AND status = 'pending'
+  AND settle_at > sqlc.arg(now)::timestamptz
+  AND settle_at <= sqlc.arg(until)::timestamptz
+ORDER BY settle_at, id;
+
+-- name: LockAnticipatableReceivables :many
+SELECT * FROM receivables
+WHERE wallet_id = sqlc.arg(wallet_id)
+  AND status = 'pending'
+  AND settle_at > sqlc.arg(now)::timestamptz
+  AND settle_at <= sqlc.arg(until)::timestamptz
+ORDER BY settle_at, id
+FOR UPDATE;
+
+-- name: AnticipateReceivable :one
+UPDATE receivables
+SET
+  status = 'anticipated',
+  anticipation_fee = $2,
+  settled_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListDueReceivableWallets :many
+SELECT wallet_id FROM receivables
+WHERE status = 'pending' AND settle_at <= sqlc.arg(now)::timestamptz
+GROUP BY wallet_id
+ORDER BY min(settle_at)
+LIMIT sqlc.arg(batch_size);
+
+-- name: ClaimDueReceivables :many
+UPDATE receivables
+SET
+  status = 'settled',
+  settled_at = now()
+WHERE id IN (
+  SELECT id FROM receivables
+  WHERE status = 'pending' AND settle_at <= sqlc.arg(now)::timestamptz
+    AND wallet_id = ANY(sqlc.arg(wallet_ids)::bigint[])
+  ORDER BY settle_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
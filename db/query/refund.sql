@@ -0,0 +1,24 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+  transfer_id,
+  refund_transfer_id,
+  amount,
+  reason,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetRefund :one
+SELECT * FROM refunds
+WHERE id = $1 LIMIT 1;
+
+-- name: ListRefunds :many
+SELECT * FROM refunds
+WHERE transfer_id = $1
+ORDER BY id;
+
+-- name: GetRefundedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS refunded
+FROM refunds
+WHERE transfer_id = $1;
@@ -0,0 +1,82 @@
+-- name: GetReserveRule :one
+SELECT * FROM reserve_rules
+WHERE owner = $1 LIMIT 1;
+
+-- name: UpsertReserveRule :one
+INSERT INTO reserve_rules (
+  owner,
+  rate,
+  hold_days,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (owner) DO UPDATE
+SET
+  rate = EXCLUDED.rate,
+  hold_days = EXCLUDED.hold_days,
+  updated_by = EXCLUDED.updated_by,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteReserveRule :exec
+DELETE FROM reserve_rules WHERE owner = $1;
+
+-- name: CreateReserve :one
+INSERT INTO reserves (
+  wallet_id,
+  transfer_id,
+  amount,
+  release_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListWalletReserves :many
+SELECT * FROM reserves
+WHERE wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetReserveTotals :one
+SELECT
+  COALESCE(sum(amount - used_amount) FILTER (WHERE status = 'held'), 0)::bigint AS held,
+  COALESCE(sum(amount - used_amount) FILTER (WHERE status = 'released'), 0)::bigint AS released,
+  COALESCE(sum(used_amount), 0)::bigint AS used
+FROM reserves
+WHERE wallet_id = $1;
+
+-- name: LockHeldReserves :many
+SELECT * FROM reserves
+WHERE wallet_id = $1 AND status = 'held' AND used_amount < amount
+ORDER BY release_at, id
+FOR UPDATE;
+
+-- name: UseReserve :one
+UPDATE reserves
+SET used_amount = used_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListDueReserveWallets :many
+SELECT wallet_id FROM reserves
+WHERE status = 'held' AND release_at <= sqlc.arg(now)::timestamptz
+GROUP BY wallet_id
+ORDER BY min(release_at)
+LIMIT sqlc.arg(batch_size);
+
+-- name: ClaimDueReserves :many
+UPDATE reserves
+SET
+  status = 'released',
+  released_at = now()
+WHERE id IN (
+  SELECT id FROM reserves
+  WHERE status = 'held' AND release_at <= sqlc.arg(now)::timestamptz
+    AND wallet_id = ANY(sqlc.arg(wallet_ids)::bigint[])
+  ORDER BY release_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
@@ -0,0 +1,80 @@
+-- name: CreateRiskDecision :one
+INSERT INTO risk_decisions (
+  from_wallet_id,
+  to_wallet_id,
+  amount,
+  outcome,
+  score,
+  rules,
+  rules_version
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: SetRiskDecisionTransfer :exec
+UPDATE risk_decisions
+SET transfer_id = $2
+WHERE id = $1;
+
+-- name: ListRiskDecisions :many
+SELECT * FROM risk_decisions
+WHERE from_wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountTransfersBetween :one
+SELECT COUNT(*)::bigint AS transfer_count FROM transfers
+WHERE from_wallet_id = $1 AND to_wallet_id = $2;
+
+-- name: CountRoundTransfers :one
+SELECT COUNT(*)::bigint AS transfer_count FROM transfers
+WHERE 
+  from_wallet_id = sqlc.arg(wallet_id) AND
+  created_at >= sqlc.arg(since) AND
+  amount % sqlc.arg(multiple_of)::bigint = 0;
+
+-- name: CountOtherRecipients :one
+SELECT COUNT(DISTINCT to_wallet_id)::bigint AS recipient_count FROM transfers
+WHERE 
+  from_wallet_id = sqlc.arg(wallet_id) AND
+  to_wallet_id <> sqlc.arg(to_wallet_id) AND
+  created_at >= sqlc.arg(since);
+
+-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+  decision_id,
+  from_wallet_id,
+  to_wallet_id,
+  amount
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetTransferReview :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferReviewForUpdate :one
+SELECT * FROM transfer_reviews
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferReviews :many
+SELECT * FROM transfer_reviews
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: DecideTransferReview :one
+UPDATE transfer_reviews
+SET 
+  status = $2,
+  transfer_id = $3,
+  reviewed_by = $4,
+  note = $5,
+  reviewed_at = now()
+WHERE id = $1
+RETURNING *;
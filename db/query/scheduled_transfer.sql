@@ -0,0 +1,96 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  owner,
+  from_wallet_id,
+  to_wallet_id,
+  amount,
+  frequency,
+  day_of_month,
+  next_run_at,
+  end_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET 
+  amount = $2,
+  end_at = $3,
+  updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: CancelScheduledTransfer :one
+UPDATE scheduled_transfers
+SET 
+  status = 'cancelled',
+  retry_at = NULL,
+  updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ClaimDueScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE status = 'active' AND COALESCE(retry_at, next_run_at) <= sqlc.arg(now)::timestamptz
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: AdvanceScheduledTransfer :one
+UPDATE scheduled_transfers
+SET 
+  next_run_at = $2,
+  retry_at = $3,
+  status = $4,
+  attempts = $5,
+  last_error = $6,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+  scheduled_transfer_id,
+  scheduled_for,
+  attempt,
+  status,
+  transfer_id,
+  error,
+  review_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetScheduledTransferRunByReview :one
+SELECT * FROM scheduled_transfer_runs
+WHERE review_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: SettleScheduledTransferRun :one
+UPDATE scheduled_transfer_runs
+SET
+  status = $2,
+  transfer_id = $3,
+  error = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
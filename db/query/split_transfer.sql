@@ -0,0 +1,24 @@
+-- name: CreateSplitTransfer :one
+INSERT INTO split_transfers (
+    from_wallet_id,
+    amount,
+    description
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetSplitTransfer :one
+SELECT * FROM split_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: SetTransferSplitTransfer :one
+UPDATE transfers
+SET split_transfer_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListSplitTransferTransfers :many
+SELECT * FROM transfers
+WHERE split_transfer_id = $1
+ORDER BY id;
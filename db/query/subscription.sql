@@ -0,0 +1,140 @@
+-- name: CreateSubscriptionPlan :one
+INSERT INTO subscription_plans (
+  merchant_wallet_id,
+  name,
+  amount,
+  currency,
+  frequency,
+  trial_days,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetSubscriptionPlan :one
+SELECT * FROM subscription_plans
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWalletSubscriptionPlans :many
+SELECT * FROM subscription_plans
+WHERE merchant_wallet_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: DeactivateSubscriptionPlan :one
+UPDATE subscription_plans
+SET active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (
+  plan_id,
+  customer_wallet_id,
+  merchant_wallet_id,
+  status,
+  mandate_approved_by,
+  mandate_approved_at,
+  day_of_month,
+  current_period_start,
+  current_period_end,
+  trial_ends_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: GetSubscriptionForUpdate :one
+SELECT * FROM subscriptions
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListWalletSubscriptions :many
+SELECT * FROM subscriptions
+WHERE customer_wallet_id = sqlc.arg(wallet_id) OR merchant_wallet_id = sqlc.arg(wallet_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ClaimDueSubscription :one
+SELECT * FROM subscriptions
+WHERE status IN ('trialing', 'active', 'past_due')
+  AND COALESCE(retry_at, current_period_end) <= sqlc.arg(now)::timestamptz
+ORDER BY current_period_end
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: RenewSubscription :one
+UPDATE subscriptions
+SET
+  status = 'active',
+  current_period_start = $2,
+  current_period_end = $3,
+  credit = $4,
+  attempts = 0,
+  retry_at = NULL,
+  last_error = '',
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: FailSubscriptionCharge :one
+UPDATE subscriptions
+SET
+  status = $2,
+  attempts = $3,
+  retry_at = $4,
+  last_error = $5,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ChangeSubscriptionPlan :one
+UPDATE subscriptions
+SET
+  plan_id = $2,
+  credit = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetSubscriptionCancelAtPeriodEnd :one
+UPDATE subscriptions
+SET
+  cancel_at_period_end = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelSubscription :one
+UPDATE subscriptions
+SET
+  status = 'cancelled',
+  retry_at = NULL,
+  cancelled_at = sqlc.arg(cancelled_at),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateSubscriptionCharge :one
+INSERT INTO subscription_charges (
+  subscription_id,
+  plan_id,
+  reason,
+  amount,
+  period_start,
+  period_end,
+  transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListSubscriptionCharges :many
+SELECT * FROM subscription_charges
+WHERE subscription_id = $1
+ORDER BY id DESC;
@@ -18,4 +18,10 @@ WHERE
     to_wallet_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 
+LIMIT 1 
+FOR NO KEY UPDATE;
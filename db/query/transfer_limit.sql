@@ -0,0 +1,103 @@
+-- name: GetTransferLimits :one
+SELECT * FROM transfer_limits
+WHERE owner = $1 LIMIT 1;
+
+-- name: UpsertTransferLimits :one
+INSERT INTO transfer_limits (
+  owner,
+  per_transfer,
+  daily,
+  monthly,
+  daily_count,
+  night_per_transfer,
+  nightly,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (owner) DO UPDATE
+SET
+  per_transfer = EXCLUDED.per_transfer,
+  daily = EXCLUDED.daily,
+  monthly = EXCLUDED.monthly,
+  daily_count = EXCLUDED.daily_count,
+  night_per_transfer = EXCLUDED.night_per_transfer,
+  nightly = EXCLUDED.nightly,
+  updated_by = EXCLUDED.updated_by,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteTransferLimits :exec
+DELETE FROM transfer_limits WHERE owner = $1;
+
+-- name: GetOutgoingTransferTotals :one
+SELECT
+  COALESCE(SUM(amount), 0)::bigint AS total_amount,
+  COUNT(*)::bigint AS transfer_count
+FROM (
+  SELECT transfers.amount FROM transfers
+  WHERE
+    from_wallet_id = sqlc.arg(wallet_id) AND
+    created_at >= sqlc.arg(since) AND
+    NOT EXISTS (SELECT 1 FROM refunds WHERE refunds.refund_transfer_id = transfers.id) AND
+    NOT EXISTS (SELECT 1 FROM disputes WHERE disputes.reversal_transfer_id = transfers.id) AND
+    NOT EXISTS (SELECT 1 FROM transfer_reviews WHERE transfer_reviews.transfer_id = transfers.id) AND
+    NOT EXISTS (SELECT 1 FROM holds WHERE holds.transfer_id = transfers.id) AND
+    NOT EXISTS (SELECT 1 FROM escrows WHERE escrows.transfer_id = transfers.id)
+  UNION ALL
+  SELECT transfer_reviews.amount FROM transfer_reviews
+  WHERE
+    from_wallet_id = sqlc.arg(wallet_id) AND
+    created_at >= sqlc.arg(since) AND
+    status <> 'rejected'
+  UNION ALL
+  SELECT CASE WHEN status = 'captured' THEN captured_amount ELSE amount END FROM holds
+  WHERE
+    wallet_id = sqlc.arg(wallet_id) AND
+    created_at >= sqlc.arg(since) AND
+    status IN ('authorized', 'captured')
+  UNION ALL
+  SELECT escrows.amount FROM escrows
+  WHERE
+    buyer_wallet_id = sqlc.arg(wallet_id) AND
+    created_at >= sqlc.arg(since) AND
+    status <> 'refunded'
+) AS outgoing;
+
+-- name: CreateLimitIncreaseRequest :one
+INSERT INTO limit_increase_requests (
+  owner,
+  limit_name,
+  requested_value,
+  status,
+  effective_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetLimitIncreaseRequest :one
+SELECT * FROM limit_increase_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: ListLimitIncreaseRequests :many
+SELECT * FROM limit_increase_requests
+WHERE owner = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueLimitIncreaseRequests :many
+SELECT * FROM limit_increase_requests
+WHERE status = 'pending' AND effective_at <= now()
+ORDER BY effective_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: DecideLimitIncreaseRequest :one
+UPDATE limit_increase_requests
+SET 
+  status = $2,
+  decided_by = $3,
+  decided_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
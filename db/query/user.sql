@@ -32,4 +32,25 @@ WHERE username = $1
 RETURNING *;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE username = $1;
\ No newline at end of file
+DELETE FROM users WHERE username = $1;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET 
+    role = $2,
+    last_updated = now()
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserMerchantCategory :one
+UPDATE users
+SET 
+    merchant_category = $2,
+    last_updated = now()
+WHERE username = $1
+RETURNING *;
+
+-- name: GetUserByPixKey :one
+SELECT * FROM users
+WHERE cpf_cnpj = sqlc.arg(key) OR email = sqlc.arg(key)
+LIMIT 1;
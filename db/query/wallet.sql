@@ -37,4 +37,33 @@ WHERE id = sqlc.arg(id)
 RETURNING *;
 
 -- name: DeleteWallet :exec
-DELETE FROM wallets WHERE id = $1;
\ No newline at end of file
+DELETE FROM wallets WHERE id = $1;
+
+-- name: UpdateWalletFrozen :one
+UPDATE wallets
+SET is_frozen = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetWalletByOwnerAndCurrency :one
+SELECT * FROM wallets
+WHERE owner = $1 AND currency = $2
+LIMIT 1;
+
+-- name: AddWalletHeldBalance :one
+UPDATE wallets
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddWalletReceivableBalance :one
+UPDATE wallets
+SET receivable_balance = receivable_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddWalletReserveBalance :one
+UPDATE wallets
+SET reserve_balance = reserve_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
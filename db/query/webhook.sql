@@ -0,0 +1,139 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints WHERE id = $1;
+
+-- name: EnableWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET 
+  enabled = true,
+  consecutive_failures = 0,
+  disabled_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: DisableWebhookEndpoint :exec
+UPDATE webhook_endpoints
+SET 
+  enabled = false,
+  disabled_at = now()
+WHERE id = $1;
+
+-- name: ResetWebhookEndpointFailures :exec
+UPDATE webhook_endpoints
+SET consecutive_failures = 0
+WHERE id = $1;
+
+-- name: IncrementWebhookEndpointFailures :one
+UPDATE webhook_endpoints
+SET consecutive_failures = consecutive_failures + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookEvent :one
+INSERT INTO webhook_events (
+  owner,
+  event_type,
+  data
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetWebhookEvent :one
+SELECT * FROM webhook_events
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id
+)
+SELECT id, sqlc.arg(event_id)::bigint
+FROM webhook_endpoints
+WHERE 
+  owner = sqlc.arg(owner) AND
+  enabled AND
+  sqlc.arg(event_type)::varchar = ANY(event_types);
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkWebhookDeliverySucceeded :one
+UPDATE webhook_deliveries
+SET 
+  status = 'delivered',
+  attempts = attempts + 1,
+  last_error = '',
+  delivered_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkWebhookDeliveryFailed :one
+UPDATE webhook_deliveries
+SET 
+  status = $2,
+  attempts = attempts + 1,
+  next_attempt_at = $3,
+  last_error = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: RedeliverWebhookDelivery :one
+UPDATE webhook_deliveries
+SET 
+  status = 'pending',
+  next_attempt_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts (
+  delivery_id,
+  response_status,
+  error,
+  duration_ms
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY id;
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomApiKey(t *testing.T) ApiKey {
+	user := createRandomUser(t)
+
+	arg := CreateApiKeyParams{
+		Owner:     user.Username,
+		Name:      util.RandomString(8),
+		Prefix:    util.RandomString(12),
+		HashedKey: util.RandomString(64),
+		Scopes:    []string{"transfers:read", "transfers:write"},
+	}
+
+	apiKey, err := testQueries.CreateApiKey(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.NotZero(t, apiKey.ID)
+	require.Equal(t, arg.Owner, apiKey.Owner)
+	require.Equal(t, arg.Prefix, apiKey.Prefix)
+	require.Equal(t, arg.HashedKey, apiKey.HashedKey)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.False(t, apiKey.RevokedAt.Valid)
+
+	return apiKey
+}
+
+func TestCreateApiKey(t *testing.T) {
+	createRandomApiKey(t)
+}
+
+func TestGetApiKeyByPrefix(t *testing.T) {
+	apiKey1 := createRandomApiKey(t)
+
+	apiKey2, err := testQueries.GetApiKeyByPrefix(context.Background(), apiKey1.Prefix)
+	require.NoError(t, err)
+	require.Equal(t, apiKey1.ID, apiKey2.ID)
+	require.Equal(t, apiKey1.Scopes, apiKey2.Scopes)
+}
+
+func TestRotateAndRevokeApiKey(t *testing.T) {
+	apiKey1 := createRandomApiKey(t)
+
+	apiKey2, err := testQueries.RotateApiKey(context.Background(), RotateApiKeyParams{
+		ID:        apiKey1.ID,
+		Prefix:    util.RandomString(12),
+		HashedKey: util.RandomString(64),
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiKey1.ID, apiKey2.ID)
+	require.NotEqual(t, apiKey1.Prefix, apiKey2.Prefix)
+	require.NotEqual(t, apiKey1.HashedKey, apiKey2.HashedKey)
+
+	apiKey3, err := testQueries.RevokeApiKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+	require.True(t, apiKey3.RevokedAt.Valid)
+
+	_, err = testQueries.RevokeApiKey(context.Background(), apiKey1.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	_, err = testQueries.RotateApiKey(context.Background(), RotateApiKeyParams{
+		ID:        apiKey1.ID,
+		Prefix:    util.RandomString(12),
+		HashedKey: util.RandomString(64),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestCreateApiKeyNonce(t *testing.T) {
+	apiKey := createRandomApiKey(t)
+	arg := CreateApiKeyNonceParams{
+		ApiKeyID: apiKey.ID,
+		Nonce:    util.RandomString(16),
+	}
+
+	inserted, err := testQueries.CreateApiKeyNonce(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), inserted)
+
+	inserted, err = testQueries.CreateApiKeyNonce(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, inserted)
+
+	err = testQueries.DeleteExpiredApiKeyNonces(context.Background(), DeleteExpiredApiKeyNoncesParams{
+		ApiKeyID:  apiKey.ID,
+		CreatedAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	inserted, err = testQueries.CreateApiKeyNonce(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), inserted)
+}
@@ -0,0 +1,342 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createCashbackMerchant creates a marketing wallet holding the balance and
+// a merchant in a category of their own, so the campaigns of a test only
+// match its payments
+func createCashbackMerchant(t *testing.T, currency string, balance int64) (funding Wallet, merchant Wallet) {
+	funding = createRandomWalletIn(t, currency)
+
+	result, err := NewStore(testDB).AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   funding.ID,
+		Amount:     balance,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	merchant = createRandomMerchantWalletIn(t, currency)
+
+	_, err = testQueries.UpdateUserMerchantCategory(context.Background(), UpdateUserMerchantCategoryParams{
+		Username:         merchant.Owner,
+		MerchantCategory: util.RandomString(10),
+	})
+	require.NoError(t, err)
+
+	return result.Wallet, merchant
+}
+
+func createRandomCashbackCampaign(t *testing.T, merchant Wallet, now time.Time, budget, perUserLimit int64, settleDays int32) CashbackCampaign {
+	user, err := testQueries.GetUser(context.Background(), merchant.Owner)
+	require.NoError(t, err)
+
+	campaign, err := testQueries.CreateCashbackCampaign(context.Background(), CreateCashbackCampaignParams{
+		Name:             util.RandomString(8),
+		Currency:         merchant.Currency,
+		Rate:             500,
+		MaxCashback:      20,
+		MinAmount:        100,
+		MerchantCategory: user.MerchantCategory,
+		StartsAt:         now.Add(-time.Hour),
+		EndsAt:           now.Add(24 * time.Hour),
+		Budget:           budget,
+		PerUserLimit:     perUserLimit,
+		SettleDays:       settleDays,
+		CreatedBy:        "admin",
+	})
+	require.NoError(t, err)
+	require.True(t, campaign.Active)
+
+	return campaign
+}
+
+func payWithCashback(from, merchant, funding Wallet, amount int64, now time.Time) (TrasferTxResult, error) {
+	return NewStore(testDB).TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: from.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       amount,
+		Cashback:     NewCashbackParams(funding.Owner, now),
+	})
+}
+
+func TestTransferTxPaysCashback(t *testing.T) {
+	now := time.Now()
+	customer := createFundedWallet(t, 1000)
+	funding, merchant := createCashbackMerchant(t, customer.Currency, 1000)
+	campaign := createRandomCashbackCampaign(t, merchant, now, 1000, 30, 0)
+
+	// 5% of 400 is capped at 20
+	result, err := payWithCashback(customer, merchant, funding, 400, now)
+	require.NoError(t, err)
+	require.NotNil(t, result.Cashback)
+	require.Equal(t, CashbackPaid, result.Cashback.Status)
+	require.Equal(t, int64(20), result.Cashback.Amount)
+	require.Equal(t, int64(1000-400+20), result.FromWallet.Balance)
+
+	// the per user limit leaves 10
+	result, err = payWithCashback(customer, merchant, funding, 400, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), result.Cashback.Amount)
+
+	result, err = payWithCashback(customer, merchant, funding, 100, now)
+	require.NoError(t, err)
+	require.Nil(t, result.Cashback)
+
+	// below the minimum amount
+	other := createRandomWalletIn(t, customer.Currency)
+	result, err = payWithCashback(other, merchant, funding, 99, now)
+	require.NoError(t, err)
+	require.Nil(t, result.Cashback)
+
+	campaign, err = testQueries.GetCashbackCampaign(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(30), campaign.Spent)
+
+	updatedFunding, err := testQueries.GetWallet(context.Background(), funding.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000-30), updatedFunding.Balance)
+}
+
+func TestTransferTxNoCashbackForSelfDeclaredMerchant(t *testing.T) {
+	now := time.Now()
+	customer := createFundedWallet(t, 1000)
+	funding, merchant := createCashbackMerchant(t, customer.Currency, 1000)
+	createRandomCashbackCampaign(t, merchant, now, 1000, 0, 0)
+
+	// the owner keeps is_merchant set but loses the merchant role
+	_, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: merchant.Owner,
+		Role:     util.CustomerRole,
+	})
+	require.NoError(t, err)
+
+	result, err := payWithCashback(customer, merchant, funding, 400, now)
+	require.NoError(t, err)
+	require.Nil(t, result.Cashback)
+	require.Equal(t, int64(1000-400), result.FromWallet.Balance)
+}
+
+func TestTransferTxCashbackBudget(t *testing.T) {
+	now := time.Now()
+	currency := util.RandomCurrency()
+	funding, merchant := createCashbackMerchant(t, currency, 1000)
+	campaign := createRandomCashbackCampaign(t, merchant, now, 50, 0, 0)
+
+	n := 5
+	errs := make(chan error)
+	granted := make(chan int64, n)
+
+	for i := 0; i < n; i++ {
+		customer := createRandomWalletIn(t, currency)
+		_, err := NewStore(testDB).AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+			WalletID:   customer.ID,
+			Amount:     1000,
+			SetBalance: true,
+			Account:    AccountBankSettlement,
+		})
+		require.NoError(t, err)
+
+		go func() {
+			result, err := payWithCashback(customer, merchant, funding, 1000, now)
+			if result.Cashback != nil {
+				granted <- result.Cashback.Amount
+			} else {
+				granted <- 0
+			}
+			errs <- err
+		}()
+	}
+
+	total := int64(0)
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		total += <-granted
+	}
+	require.Equal(t, int64(50), total)
+
+	campaign, err := testQueries.GetCashbackCampaign(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Budget, campaign.Spent)
+
+	updatedFunding, err := testQueries.GetWallet(context.Background(), funding.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000-50), updatedFunding.Balance)
+}
+
+func TestSettleCashbackGrantTxAndClawBack(t *testing.T) {
+	store := NewStore(testDB)
+
+	// dates long past keep the grants of other tests out of the settlement
+	now := time.Date(1992, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	customer := createFundedWallet(t, 1000)
+	funding, merchant := createCashbackMerchant(t, customer.Currency, 1000)
+	campaign := createRandomCashbackCampaign(t, merchant, now, 1000, 0, 3)
+
+	result, err := payWithCashback(customer, merchant, funding, 200, now)
+	require.NoError(t, err)
+	require.Equal(t, CashbackPending, result.Cashback.Status)
+	require.Equal(t, int64(10), result.Cashback.Amount)
+	require.Equal(t, int64(800), result.FromWallet.Balance)
+
+	_, err = store.SettleCashbackGrantTx(context.Background(), SettleCashbackGrantTxParams{
+		Now:          now.Add(24 * time.Hour),
+		FundingOwner: funding.Owner,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	grant, err := store.SettleCashbackGrantTx(context.Background(), SettleCashbackGrantTxParams{
+		Now:          now.AddDate(0, 0, 3),
+		FundingOwner: funding.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Cashback.ID, grant.ID)
+	require.Equal(t, CashbackPaid, grant.Status)
+	require.Equal(t, funding.ID, grant.FundingWalletID.Int64)
+
+	// a half refund takes back half of the cashback
+	_, err = store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: result.Transfer.ID,
+		Amount:     100,
+		CreatedBy:  merchant.Owner,
+	})
+	require.NoError(t, err)
+
+	grant, err = store.GetCashbackGrant(context.Background(), grant.ID)
+	require.NoError(t, err)
+	require.Equal(t, CashbackPaid, grant.Status)
+	require.Equal(t, int64(5), grant.ClawedBack)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(800+10+100-5), updatedCustomer.Balance)
+
+	// the rest of the refund takes back the rest
+	_, err = store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: result.Transfer.ID,
+		Amount:     100,
+		CreatedBy:  merchant.Owner,
+	})
+	require.NoError(t, err)
+
+	grant, err = store.GetCashbackGrant(context.Background(), grant.ID)
+	require.NoError(t, err)
+	require.Equal(t, CashbackClawedBack, grant.Status)
+	require.Equal(t, grant.Amount, grant.ClawedBack)
+
+	updatedCustomer, err = store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), updatedCustomer.Balance)
+
+	updatedFunding, err := store.GetWallet(context.Background(), funding.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), updatedFunding.Balance)
+
+	campaign, err = store.GetCashbackCampaign(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	require.Zero(t, campaign.Spent)
+}
+
+func TestRefundTxClawsBackPendingCashback(t *testing.T) {
+	store := NewStore(testDB)
+	now := time.Now()
+
+	customer := createFundedWallet(t, 1000)
+	funding, merchant := createCashbackMerchant(t, customer.Currency, 1000)
+	campaign := createRandomCashbackCampaign(t, merchant, now, 1000, 0, 30)
+
+	result, err := payWithCashback(customer, merchant, funding, 300, now)
+	require.NoError(t, err)
+	require.Equal(t, CashbackPending, result.Cashback.Status)
+
+	_, err = store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: result.Transfer.ID,
+		Amount:     300,
+		CreatedBy:  merchant.Owner,
+	})
+	require.NoError(t, err)
+
+	grant, err := store.GetCashbackGrant(context.Background(), result.Cashback.ID)
+	require.NoError(t, err)
+	require.Equal(t, CashbackClawedBack, grant.Status)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), updatedCustomer.Balance)
+
+	campaign, err = store.GetCashbackCampaign(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	require.Zero(t, campaign.Spent)
+}
+
+func TestDecideDisputeTxClawsBackCashback(t *testing.T) {
+	store := NewStore(testDB)
+	now := time.Now()
+
+	customer := createFundedWallet(t, 1000)
+	funding, merchant := createCashbackMerchant(t, customer.Currency, 1000)
+	campaign := createRandomCashbackCampaign(t, merchant, now, 1000, 0, 0)
+
+	result, err := payWithCashback(customer, merchant, funding, 400, now)
+	require.NoError(t, err)
+	require.Equal(t, CashbackPaid, result.Cashback.Status)
+	require.Equal(t, int64(20), result.Cashback.Amount)
+
+	dispute, err := store.OpenDisputeTx(context.Background(), OpenDisputeTxParams{
+		TransferID: result.Transfer.ID,
+		OpenedBy:   customer.Owner,
+		Amount:     200,
+		Reason:     "half of the order never arrived",
+		RespondBy:  now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.RespondDisputeTx(context.Background(), RespondDisputeTxParams{
+		ID:       dispute.ID,
+		Response: "it was delivered",
+		Now:      now,
+	})
+	require.NoError(t, err)
+
+	_, err = store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{
+		ID:        dispute.ID,
+		PayerWins: true,
+		DecidedBy: "analyst",
+	})
+	require.NoError(t, err)
+
+	// winning half of the transfer back takes back half of the cashback
+	grant, err := store.GetCashbackGrant(context.Background(), result.Cashback.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), grant.ClawedBack)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000-400+20+200-10), updatedCustomer.Balance)
+
+	// a refund of the rest takes back the rest, not counting the dispute twice
+	_, err = store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: result.Transfer.ID,
+		Amount:     200,
+		CreatedBy:  merchant.Owner,
+	})
+	require.NoError(t, err)
+
+	grant, err = store.GetCashbackGrant(context.Background(), result.Cashback.ID)
+	require.NoError(t, err)
+	require.Equal(t, CashbackClawedBack, grant.Status)
+	require.Equal(t, grant.Amount, grant.ClawedBack)
+
+	campaign, err = store.GetCashbackCampaign(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	require.Zero(t, campaign.Spent)
+}
@@ -0,0 +1,322 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	CashbackPending    = "pending"
+	CashbackPaid       = "paid"
+	CashbackClawedBack = "clawed_back"
+)
+
+// ErrCashbackWalletNotFound is returned when cashback is paid in a currency
+// the marketing owner has no wallet in
+var ErrCashbackWalletNotFound = errors.New("cashback funding wallet not found")
+
+// CashbackParams grants the cashback of the campaigns running at Now to the
+// payments that qualify
+type CashbackParams struct {
+	Now time.Time
+	// FundingOwner owns the marketing wallets cashback is paid from, and never gets any
+	FundingOwner string
+}
+
+// NewCashbackParams returns the params granting cashback at now, or nil when
+// there is no marketing owner to pay it from
+func NewCashbackParams(fundingOwner string, now time.Time) *CashbackParams {
+	if fundingOwner == "" {
+		return nil
+	}
+
+	return &CashbackParams{
+		Now:          now,
+		FundingOwner: fundingOwner,
+	}
+}
+
+// grantCashback gives the payer of a transfer to a merchant the cashback of
+// the eligible campaign with the best rate that has budget left for them.
+// The campaign is locked while its budget is spent, so concurrent payments
+// can't take it above the budget or the limit of the payer. Campaigns with no
+// settle days pay in the same transaction, the others leave the grant to the
+// settler
+func grantCashback(ctx context.Context, q *Queries, result *TrasferTxResult, arg CashbackParams) error {
+	from, to := result.FromWallet, result.ToWallet
+
+	if from.Owner == to.Owner || from.Owner == arg.FundingOwner {
+		return nil
+	}
+
+	payer, err := q.GetUser(ctx, from.Owner)
+	if err != nil {
+		return err
+	}
+
+	merchant, err := q.GetUser(ctx, to.Owner)
+	if err != nil {
+		return err
+	}
+
+	// by role, as anyone can set is_merchant on an account of their own and pay it
+	if payer.HasMerchantRole() || !merchant.HasMerchantRole() {
+		return nil
+	}
+
+	campaigns, err := q.ListEligibleCashbackCampaigns(ctx, ListEligibleCashbackCampaignsParams{
+		Currency:         from.Currency,
+		Now:              arg.Now,
+		Amount:           result.Transfer.Amount,
+		MerchantCategory: merchant.MerchantCategory,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, campaign := range campaigns {
+		campaign, err = q.GetCashbackCampaignForUpdate(ctx, campaign.ID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := cashbackLeft(ctx, q, campaign, payer.Username, result.Transfer.Amount)
+		if err != nil {
+			return err
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		grant, err := q.CreateCashbackGrant(ctx, CreateCashbackGrantParams{
+			CampaignID: campaign.ID,
+			TransferID: result.Transfer.ID,
+			WalletID:   from.ID,
+			Owner:      payer.Username,
+			Amount:     amount,
+			SettleAt:   arg.Now.AddDate(0, 0, int(campaign.SettleDays)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddCashbackCampaignSpent(ctx, AddCashbackCampaignSpentParams{
+			ID:     campaign.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Cashback = &grant
+
+		if campaign.SettleDays > 0 {
+			return nil
+		}
+
+		var credit TrasferTxResult
+
+		grant, credit, err = settleCashbackGrant(ctx, q, grant, arg.FundingOwner, arg.Now, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		result.Cashback = &grant
+		if grant.Status == CashbackPaid {
+			result.FromWallet = credit.ToWallet
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// cashbackLeft returns the cashback of the amount the campaign can still
+// give the payer, within its budget and the per user limit
+func cashbackLeft(ctx context.Context, q *Queries, campaign CashbackCampaign, payer string, amount int64) (int64, error) {
+	if !campaign.Active {
+		return 0, nil
+	}
+
+	cashback := util.Cashback(amount, campaign.Rate, campaign.MaxCashback)
+
+	if left := campaign.Budget - campaign.Spent; cashback > left {
+		cashback = left
+	}
+
+	if campaign.PerUserLimit > 0 {
+		total, err := q.GetCashbackUserTotal(ctx, GetCashbackUserTotalParams{
+			CampaignID: campaign.ID,
+			Owner:      payer,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if left := campaign.PerUserLimit - total; cashback > left {
+			cashback = left
+		}
+	}
+
+	return cashback, nil
+}
+
+type SettleCashbackGrantTxParams struct {
+	Now          time.Time `json:"now"`
+	FundingOwner string    `json:"funding_owner"`
+	// RetryInterval is how long a grant that could not be paid waits to be tried again
+	RetryInterval time.Duration `json:"retry_interval"`
+}
+
+// SettleCashbackGrantTx pays the pending grant due first from the marketing
+// wallet. A grant that can't be paid is tried again after the retry
+// interval. sql.ErrNoRows is returned when no grant is due
+func (store *SQLStore) SettleCashbackGrantTx(ctx context.Context, arg SettleCashbackGrantTxParams) (CashbackGrant, error) {
+	var grant CashbackGrant
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		grant, err = q.ClaimDueCashbackGrant(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		grant, _, err = settleCashbackGrant(ctx, q, grant, arg.FundingOwner, arg.Now, arg.Now.Add(arg.RetryInterval))
+		return err
+	})
+
+	return grant, err
+}
+
+// settleCashbackGrant credits what is left of the grant from the marketing
+// wallet in its currency and lets the payer know. When it can't be paid, the
+// grant stays pending until retryAt
+func settleCashbackGrant(ctx context.Context, q *Queries, grant CashbackGrant, fundingOwner string, now time.Time, retryAt time.Time) (CashbackGrant, TrasferTxResult, error) {
+	credit, err := creditCashback(ctx, q, grant, fundingOwner)
+	if isTransferFailure(err) || errors.Is(err, ErrCashbackWalletNotFound) {
+		grant, err = q.RetryCashbackGrant(ctx, RetryCashbackGrantParams{
+			ID:        grant.ID,
+			SettleAt:  retryAt,
+			LastError: err.Error(),
+		})
+		return grant, credit, err
+	}
+	if err != nil {
+		return grant, credit, err
+	}
+
+	grant, err = q.PayCashbackGrant(ctx, PayCashbackGrantParams{
+		ID:               grant.ID,
+		FundingWalletID:  sql.NullInt64{Int64: credit.FromWallet.ID, Valid: true},
+		CreditTransferID: sql.NullInt64{Int64: credit.Transfer.ID, Valid: true},
+		PaidAt:           sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return grant, credit, err
+	}
+
+	message := fmt.Sprintf("You got %s %s of cashback for payment %d.",
+		util.FormatAmount(grant.Amount-grant.ClawedBack), credit.ToWallet.Currency, grant.TransferID)
+
+	err = notify(ctx, q, grant.Owner, util.NotificationCashbackCredited, message, grant)
+	return grant, credit, err
+}
+
+func creditCashback(ctx context.Context, q *Queries, grant CashbackGrant, fundingOwner string) (TrasferTxResult, error) {
+	wallet, err := q.GetWallet(ctx, grant.WalletID)
+	if err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	funding, err := q.GetWalletByOwnerAndCurrency(ctx, GetWalletByOwnerAndCurrencyParams{
+		Owner:    fundingOwner,
+		Currency: wallet.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return TrasferTxResult{}, fmt.Errorf("%w: %s", ErrCashbackWalletNotFound, wallet.Currency)
+	}
+	if err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	return checkedTransferTx(ctx, q, TrasferTxParms{
+		FromWalletID: funding.ID,
+		ToWalletID:   wallet.ID,
+		Amount:       grant.Amount - grant.ClawedBack,
+	})
+}
+
+// lockCashbackGrant locks the cashback granted for the transfer, if any.
+// Refunds lock it before the wallets, the same order the settler uses
+func lockCashbackGrant(ctx context.Context, q *Queries, transferID int64) (*CashbackGrant, error) {
+	grant, err := q.GetCashbackGrantByTransferForUpdate(ctx, transferID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+// clawBackCashback takes back the part of the cashback matching what refunds
+// and a payer-won dispute gave back of the original transfer so far. Pending cashback is just paid
+// less, paid cashback goes back to the marketing wallet it came from. What is
+// clawed back returns to the budget of the campaign
+func clawBackCashback(ctx context.Context, q *Queries, grant CashbackGrant, original Transfer, refunded int64) error {
+	target := grant.Amount
+	if refunded < original.Amount {
+		target = grant.Amount * refunded / original.Amount
+	}
+
+	amount := target - grant.ClawedBack
+	if amount <= 0 {
+		return nil
+	}
+
+	// the campaign is locked before the marketing wallet, like when granting
+	_, err := q.AddCashbackCampaignSpent(ctx, AddCashbackCampaignSpentParams{
+		ID:     grant.CampaignID,
+		Amount: -amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	if grant.Status == CashbackPaid {
+		_, err = transfer(ctx, q, TrasferTxParms{
+			FromWalletID: grant.WalletID,
+			ToWalletID:   grant.FundingWalletID.Int64,
+			Amount:       amount,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	paid := grant.Status == CashbackPaid
+
+	grant, err = q.ClawBackCashbackGrant(ctx, ClawBackCashbackGrantParams{
+		ID:     grant.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !paid {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s of the cashback for payment %d was taken back after it was refunded or charged back.",
+		util.FormatAmount(amount), grant.TransferID)
+
+	return notify(ctx, q, grant.Owner, util.NotificationCashbackClawedBack, message, grant)
+}
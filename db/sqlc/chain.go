@@ -0,0 +1,351 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EntryHash is the sha256 of the entry content and the hash of the previous
+// entry of the same wallet. The migration that added the chain computes it
+// the same way for the entries that existed before
+func EntryHash(entry Entry, prevHash string) string {
+	content := strings.Join([]string{
+		"entry/v1",
+		strconv.FormatInt(entry.ID, 10),
+		strconv.FormatInt(entry.WalletID, 10),
+		strconv.FormatInt(entry.Amount, 10),
+		entry.Kind,
+		nullInt64String(entry.TransferID),
+		nullInt64String(entry.FeeScheduleID),
+		strconv.FormatInt(entry.CreatedAt.UnixMicro(), 10),
+		prevHash,
+	}, "|")
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func nullInt64String(value sql.NullInt64) string {
+	if !value.Valid {
+		return ""
+	}
+	return strconv.FormatInt(value.Int64, 10)
+}
+
+// writeEntry writes an entry chained to the previous one of its wallet.
+// The wallet is locked before the entry is inserted, so the entries of a
+// wallet get increasing IDs in the order they are chained
+func writeEntry(ctx context.Context, q *Queries, arg CreateEntryParams) (Entry, error) {
+	if _, err := q.GetWalletForUpdate(ctx, arg.WalletID); err != nil {
+		return Entry{}, err
+	}
+
+	entry, err := q.CreateEntry(ctx, arg)
+	if err != nil {
+		return entry, err
+	}
+
+	return chainEntry(ctx, q, entry)
+}
+
+// writeFeeEntry is writeEntry for fee entries
+func writeFeeEntry(ctx context.Context, q *Queries, arg CreateFeeEntryParams) (Entry, error) {
+	if _, err := q.GetWalletForUpdate(ctx, arg.WalletID); err != nil {
+		return Entry{}, err
+	}
+
+	entry, err := q.CreateFeeEntry(ctx, arg)
+	if err != nil {
+		return entry, err
+	}
+
+	return chainEntry(ctx, q, entry)
+}
+
+func chainEntry(ctx context.Context, q *Queries, entry Entry) (Entry, error) {
+	prevHash, err := q.GetPreviousEntryHash(ctx, GetPreviousEntryHashParams{
+		WalletID: entry.WalletID,
+		ID:       entry.ID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return entry, err
+	}
+
+	return q.SetEntryHash(ctx, SetEntryHashParams{
+		ID:       entry.ID,
+		PrevHash: prevHash,
+		Hash:     EntryHash(entry, prevHash),
+	})
+}
+
+// newCheckpointDigest starts the digest of a checkpoint, chained to the
+// digest of the previous one
+func newCheckpointDigest(prevDigest string) hash.Hash {
+	digest := sha256.New()
+	digest.Write([]byte("checkpoint/v1|" + prevDigest))
+	return digest
+}
+
+func addEntryToDigest(digest hash.Hash, entry Entry) {
+	fmt.Fprintf(digest, "|%d:%s", entry.ID, entry.Hash)
+}
+
+type CreateChainCheckpointTxParams struct {
+	// Before leaves out the entries created since, whose transactions may
+	// still be running
+	Before    time.Time
+	Key       ed25519.PrivateKey
+	BatchSize int32
+}
+
+// CreateChainCheckpointTx anchors the entries created since the last
+// checkpoint, signing a digest of their hashes chained to the previous
+// checkpoint. It returns sql.ErrNoRows when there are no new entries
+func (store *SQLStore) CreateChainCheckpointTx(ctx context.Context, arg CreateChainCheckpointTxParams) (ChainCheckpoint, error) {
+	var checkpoint ChainCheckpoint
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		last, err := q.GetLastChainCheckpoint(ctx)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		upTo, err := q.GetLastEntryIDBefore(ctx, arg.Before)
+		if err != nil {
+			return err
+		}
+
+		if upTo <= last.ToEntryID {
+			return sql.ErrNoRows
+		}
+
+		digest := newCheckpointDigest(last.Digest)
+
+		count, err := walkEntriesInRange(ctx, q, last.ToEntryID, upTo, arg.BatchSize, func(entry Entry) {
+			addEntryToDigest(digest, entry)
+		})
+		if err != nil {
+			return err
+		}
+
+		sum := hex.EncodeToString(digest.Sum(nil))
+
+		// a concurrent checkpoint from the same entry fails on the unique index
+		checkpoint, err = q.CreateChainCheckpoint(ctx, CreateChainCheckpointParams{
+			FromEntryID: last.ToEntryID,
+			ToEntryID:   upTo,
+			EntryCount:  count,
+			PrevDigest:  last.Digest,
+			Digest:      sum,
+			PublicKey:   hex.EncodeToString(arg.Key.Public().(ed25519.PublicKey)),
+			Signature:   hex.EncodeToString(ed25519.Sign(arg.Key, []byte(sum))),
+		})
+		return err
+	})
+
+	return checkpoint, err
+}
+
+func walkEntriesInRange(ctx context.Context, q *Queries, afterID, upToID int64, batchSize int32, fn func(Entry)) (int64, error) {
+	var count int64
+
+	for {
+		entries, err := q.ListEntriesInRange(ctx, ListEntriesInRangeParams{
+			AfterID:   afterID,
+			UpToID:    upToID,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		for _, entry := range entries {
+			fn(entry)
+			afterID = entry.ID
+			count++
+		}
+
+		if len(entries) < int(batchSize) {
+			return count, nil
+		}
+	}
+}
+
+type VerifyChainParams struct {
+	// WalletID limits the verification to one wallet, checkpoints are only
+	// verified along with every wallet
+	WalletID int64
+	// PublicKey, when set, is the only key checkpoints may be signed with
+	PublicKey ed25519.PublicKey
+	BatchSize int32
+}
+
+// ChainBreak is the first link of the chain that doesn't hold
+type ChainBreak struct {
+	WalletID     int64  `json:"wallet_id,omitempty"`
+	EntryID      int64  `json:"entry_id,omitempty"`
+	CheckpointID int64  `json:"checkpoint_id,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+type VerifyChainResult struct {
+	Valid              bool        `json:"valid"`
+	EntriesChecked     int64       `json:"entries_checked"`
+	CheckpointsChecked int64       `json:"checkpoints_checked"`
+	Break              *ChainBreak `json:"break,omitempty"`
+}
+
+// VerifyChain walks the chain of every wallet, then the checkpoints, and
+// reports the first broken link. It reads a single snapshot
+func (store *SQLStore) VerifyChain(ctx context.Context, arg VerifyChainParams) (VerifyChainResult, error) {
+	var result VerifyChainResult
+
+	transaction, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return result, err
+	}
+	defer transaction.Rollback()
+
+	q := New(transaction)
+
+	result.Break, err = verifyEntries(ctx, q, arg, &result.EntriesChecked)
+	if err != nil || result.Break != nil || arg.WalletID != 0 {
+		result.Valid = err == nil && result.Break == nil
+		return result, err
+	}
+
+	result.Break, err = verifyCheckpoints(ctx, q, arg, &result.CheckpointsChecked)
+	if err != nil {
+		return result, err
+	}
+
+	result.Valid = result.Break == nil
+	return result, transaction.Commit()
+}
+
+func verifyEntries(ctx context.Context, q *Queries, arg VerifyChainParams, checked *int64) (*ChainBreak, error) {
+	var walletID, afterID int64
+	var prevHash string
+
+	if arg.WalletID != 0 {
+		walletID = arg.WalletID
+	}
+
+	for {
+		var entries []Entry
+		var err error
+
+		if arg.WalletID != 0 {
+			entries, err = q.ListEntriesAfter(ctx, ListEntriesAfterParams{
+				WalletID:  arg.WalletID,
+				AfterID:   afterID,
+				BatchSize: arg.BatchSize,
+			})
+		} else {
+			entries, err = q.ListChainEntries(ctx, ListChainEntriesParams{
+				AfterWalletID: walletID,
+				AfterID:       afterID,
+				BatchSize:     arg.BatchSize,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.WalletID != walletID {
+				walletID = entry.WalletID
+				prevHash = ""
+			}
+
+			if entry.PrevHash != prevHash {
+				return &ChainBreak{WalletID: walletID, EntryID: entry.ID, Reason: "prev_hash doesn't match the previous entry"}, nil
+			}
+			if EntryHash(entry, entry.PrevHash) != entry.Hash {
+				return &ChainBreak{WalletID: walletID, EntryID: entry.ID, Reason: "hash doesn't match the entry"}, nil
+			}
+
+			prevHash = entry.Hash
+			afterID = entry.ID
+			*checked++
+		}
+
+		if len(entries) < int(arg.BatchSize) {
+			return nil, nil
+		}
+	}
+}
+
+func verifyCheckpoints(ctx context.Context, q *Queries, arg VerifyChainParams, checked *int64) (*ChainBreak, error) {
+	var previous ChainCheckpoint
+
+	for {
+		checkpoints, err := q.ListChainCheckpoints(ctx, ListChainCheckpointsParams{
+			AfterID:   previous.ID,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, checkpoint := range checkpoints {
+			reason, err := verifyCheckpoint(ctx, q, arg, previous, checkpoint)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				return &ChainBreak{CheckpointID: checkpoint.ID, Reason: reason}, nil
+			}
+
+			previous = checkpoint
+			*checked++
+		}
+
+		if len(checkpoints) < int(arg.BatchSize) {
+			return nil, nil
+		}
+	}
+}
+
+// verifyCheckpoint returns why the checkpoint doesn't hold, if it doesn't
+func verifyCheckpoint(ctx context.Context, q *Queries, arg VerifyChainParams, previous, checkpoint ChainCheckpoint) (string, error) {
+	if checkpoint.FromEntryID != previous.ToEntryID || checkpoint.PrevDigest != previous.Digest {
+		return "checkpoint doesn't follow the previous one", nil
+	}
+
+	digest := newCheckpointDigest(checkpoint.PrevDigest)
+
+	count, err := walkEntriesInRange(ctx, q, checkpoint.FromEntryID, checkpoint.ToEntryID, arg.BatchSize, func(entry Entry) {
+		addEntryToDigest(digest, entry)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if count != checkpoint.EntryCount || hex.EncodeToString(digest.Sum(nil)) != checkpoint.Digest {
+		return "entries don't match the checkpoint digest", nil
+	}
+
+	publicKey, err := hex.DecodeString(checkpoint.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return "checkpoint public key is malformed", nil
+	}
+	if arg.PublicKey != nil && !arg.PublicKey.Equal(ed25519.PublicKey(publicKey)) {
+		return "checkpoint was signed with an unknown key", nil
+	}
+
+	signature, err := hex.DecodeString(checkpoint.Signature)
+	if err != nil || !ed25519.Verify(publicKey, []byte(checkpoint.Digest), signature) {
+		return "checkpoint signature is invalid", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryChain(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWallet(t)
+	to := createRandomWalletIn(t, from.Currency)
+
+	var last TrasferTxResult
+	for i := 0; i < 3; i++ {
+		result, err := store.TransferTx(context.Background(), TrasferTxParms{
+			FromWalletID: from.ID,
+			ToWalletID:   to.ID,
+			Amount:       10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, EntryHash(result.FromEntry, result.FromEntry.PrevHash), result.FromEntry.Hash)
+
+		if i > 0 {
+			require.Equal(t, last.FromEntry.Hash, result.FromEntry.PrevHash)
+			require.Equal(t, last.ToEntry.Hash, result.ToEntry.PrevHash)
+		}
+		last = result
+	}
+
+	verification, err := store.VerifyChain(context.Background(), VerifyChainParams{WalletID: from.ID, BatchSize: 2})
+	require.NoError(t, err)
+	require.True(t, verification.Valid)
+	require.GreaterOrEqual(t, verification.EntriesChecked, int64(3))
+	require.Nil(t, verification.Break)
+
+	_, err = testDB.Exec("UPDATE entries SET amount = amount - 1 WHERE id = $1", last.FromEntry.ID)
+	require.NoError(t, err)
+
+	verification, err = store.VerifyChain(context.Background(), VerifyChainParams{WalletID: from.ID, BatchSize: 2})
+	require.NoError(t, err)
+	require.False(t, verification.Valid)
+	require.NotNil(t, verification.Break)
+	require.Equal(t, from.ID, verification.Break.WalletID)
+	require.Equal(t, last.FromEntry.ID, verification.Break.EntryID)
+}
+
+func TestCreateChainCheckpointTx(t *testing.T) {
+	store := NewStore(testDB)
+	createRandomWallet(t)
+
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	checkpoint, err := store.CreateChainCheckpointTx(context.Background(), CreateChainCheckpointTxParams{
+		Before:    time.Now().Add(time.Minute),
+		Key:       key,
+		BatchSize: 100,
+	})
+	require.NoError(t, err)
+	require.Greater(t, checkpoint.ToEntryID, checkpoint.FromEntryID)
+	require.Positive(t, checkpoint.EntryCount)
+	require.Equal(t, hex.EncodeToString(key.Public().(ed25519.PublicKey)), checkpoint.PublicKey)
+
+	signature, err := hex.DecodeString(checkpoint.Signature)
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(key.Public().(ed25519.PublicKey), []byte(checkpoint.Digest), signature))
+
+	last, err := store.GetLastChainCheckpoint(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.ID, last.ID)
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCheckoutSession(t *testing.T, merchant Wallet, amount int64, expiresAt time.Time) CheckoutSession {
+	session, err := testQueries.CreateCheckoutSession(context.Background(), CreateCheckoutSessionParams{
+		Token:            "cs_" + util.RandomString(24),
+		MerchantWalletID: merchant.ID,
+		Amount:           amount,
+		Currency:         merchant.Currency,
+		Description:      "order",
+		Metadata:         json.RawMessage(`{"order_id":"42"}`),
+		ExpiresAt:        expiresAt,
+		CreatedBy:        merchant.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CheckoutOpen, session.Status)
+
+	return session
+}
+
+func TestPayCheckoutSessionTxOnce(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	session := createRandomCheckoutSession(t, merchant, 300, time.Now().Add(time.Hour))
+
+	n := 3
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.PayCheckoutSessionTx(context.Background(), PayCheckoutSessionTxParams{
+				Token:        session.Token,
+				Payer:        customer.Owner,
+				FromWalletID: customer.ID,
+				Now:          time.Now(),
+			})
+			errs <- err
+		}()
+	}
+
+	paid := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			paid++
+			continue
+		}
+		require.ErrorIs(t, err, ErrCheckoutSessionNotOpen)
+	}
+	require.Equal(t, 1, paid)
+
+	session, err := store.GetCheckoutSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutPaid, session.Status)
+	require.True(t, session.TransferID.Valid)
+	require.Equal(t, customer.Owner, session.PaidBy.String)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(700), updatedCustomer.Balance)
+}
+
+func TestExpireCheckoutSessions(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	session := createRandomCheckoutSession(t, merchant, 300, time.Now().Add(time.Minute))
+
+	_, err := store.PayCheckoutSessionTx(context.Background(), PayCheckoutSessionTxParams{
+		Token:        session.Token,
+		Payer:        customer.Owner,
+		FromWalletID: customer.ID,
+		Now:          time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrCheckoutSessionExpired)
+
+	session, err = store.GetCheckoutSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutExpired, session.Status)
+
+	due := createRandomCheckoutSession(t, merchant, 300, time.Now().Add(time.Minute))
+
+	_, err = store.ExpireCheckoutSessionsTx(context.Background(), ExpireCheckoutSessionsTxParams{
+		Now:       time.Now().Add(time.Hour),
+		BatchSize: 1000,
+	})
+	require.NoError(t, err)
+
+	due, err = store.GetCheckoutSession(context.Background(), due.ID)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutExpired, due.Status)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, customer.Balance, updatedCustomer.Balance)
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	CheckoutOpen       = "open"
+	CheckoutProcessing = "processing"
+	CheckoutPaid       = "paid"
+	CheckoutExpired    = "expired"
+)
+
+var (
+	// ErrCheckoutSessionNotOpen is returned when paying a session that was
+	// already paid, is being paid or expired
+	ErrCheckoutSessionNotOpen = errors.New("checkout session is no longer open")
+	// ErrCheckoutSessionExpired is returned when paying a session past its expiry
+	ErrCheckoutSessionExpired = errors.New("checkout session has expired")
+)
+
+type PayCheckoutSessionTxParams struct {
+	Token        string    `json:"token"`
+	Payer        string    `json:"payer"`
+	FromWalletID int64     `json:"from_wallet_id"`
+	Now          time.Time `json:"now"`
+	// Limits, Risk, Fees and Cashback are applied to the transfer like in TransferTx
+	Limits   *TransferLimitsParams `json:"-"`
+	Risk     RiskEvaluator         `json:"-"`
+	Fees     *FeeParams            `json:"-"`
+	Cashback *CashbackParams       `json:"-"`
+}
+
+type PayCheckoutSessionTxResult struct {
+	CheckoutSession CheckoutSession `json:"checkout_session"`
+	TrasferTxResult
+}
+
+// PayCheckoutSessionTx pays an open session into the merchant wallet. The
+// session is locked while it is paid, so it is paid at most once. An expired
+// session is marked as such and ErrCheckoutSessionExpired is returned. When
+// the transfer is held for review, the session is processing until the
+// review is decided
+func (store *SQLStore) PayCheckoutSessionTx(ctx context.Context, arg PayCheckoutSessionTxParams) (PayCheckoutSessionTxResult, error) {
+	var result PayCheckoutSessionTxResult
+	var failure error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		session, err := q.GetCheckoutSessionByTokenForUpdate(ctx, arg.Token)
+		if err != nil {
+			return err
+		}
+
+		if session.Status != CheckoutOpen {
+			return ErrCheckoutSessionNotOpen
+		}
+
+		if !arg.Now.Before(session.ExpiresAt) {
+			// commit the expiry so the session stops showing as open
+			result.CheckoutSession, err = q.ExpireCheckoutSession(ctx, session.ID)
+			failure = ErrCheckoutSessionExpired
+			return err
+		}
+
+		var blocked bool
+
+		result.TrasferTxResult, blocked, err = transferTx(ctx, q, TrasferTxParms{
+			FromWalletID: arg.FromWalletID,
+			ToWalletID:   session.MerchantWalletID,
+			Amount:       session.Amount,
+			Limits:       arg.Limits,
+			Risk:         arg.Risk,
+			Fees:         arg.Fees,
+			Cashback:     arg.Cashback,
+		})
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			failure = ErrTransferBlocked
+			return nil
+		}
+
+		pay := PayCheckoutSessionParams{
+			ID:           session.ID,
+			Status:       CheckoutPaid,
+			PaidBy:       sql.NullString{String: arg.Payer, Valid: true},
+			FromWalletID: sql.NullInt64{Int64: arg.FromWalletID, Valid: true},
+		}
+
+		if result.Review != nil {
+			pay.Status = CheckoutProcessing
+			pay.ReviewID = sql.NullInt64{Int64: result.Review.ID, Valid: true}
+		} else {
+			pay.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+			pay.PaidAt = sql.NullTime{Time: arg.Now, Valid: true}
+		}
+
+		result.CheckoutSession, err = q.PayCheckoutSession(ctx, pay)
+		if err != nil {
+			return err
+		}
+
+		return publishCheckoutEvent(ctx, q, result.CheckoutSession)
+	})
+
+	if err == nil {
+		err = failure
+	}
+
+	return result, err
+}
+
+// settleCheckoutSessionReview marks the session paid by a reviewed transfer
+// as paid, or opens it again when the review was rejected
+func settleCheckoutSessionReview(ctx context.Context, q *Queries, review TransferReview, transfer *Transfer) error {
+	session, err := q.GetCheckoutSessionByReview(ctx, sql.NullInt64{Int64: review.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	arg := PayCheckoutSessionParams{
+		ID:     session.ID,
+		Status: CheckoutOpen,
+	}
+
+	if transfer != nil {
+		arg.Status = CheckoutPaid
+		arg.PaidBy = session.PaidBy
+		arg.FromWalletID = session.FromWalletID
+		arg.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+		arg.PaidAt = sql.NullTime{Time: transfer.CreatedAt, Valid: true}
+	}
+
+	session, err = q.PayCheckoutSession(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	return publishCheckoutEvent(ctx, q, session)
+}
+
+type ExpireCheckoutSessionsTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// ExpireCheckoutSessionsTx expires a batch of open sessions past their
+// expiry and lets their merchants know
+func (store *SQLStore) ExpireCheckoutSessionsTx(ctx context.Context, arg ExpireCheckoutSessionsTxParams) ([]CheckoutSession, error) {
+	var sessions []CheckoutSession
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		sessions, err = q.ExpireDueCheckoutSessions(ctx, ExpireDueCheckoutSessionsParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, session := range sessions {
+			if err := publishCheckoutEvent(ctx, q, session); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sessions, err
+}
+
+// publishCheckoutEvent tells the merchant the session was paid or expired
+func publishCheckoutEvent(ctx context.Context, q *Queries, session CheckoutSession) error {
+	var eventType string
+
+	switch session.Status {
+	case CheckoutPaid:
+		eventType = util.EventCheckoutCompleted
+	case CheckoutExpired:
+		eventType = util.EventCheckoutExpired
+	default:
+		return nil
+	}
+
+	merchant, err := q.GetWallet(ctx, session.MerchantWalletID)
+	if err != nil {
+		return err
+	}
+
+	return publishEvent(ctx, q, merchant.Owner, eventType, session)
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createDisputedTransfer pays 800 from a funded payer to an empty merchant
+// and disputes the whole transfer
+func createDisputedTransfer(t *testing.T) (Dispute, Wallet, Wallet) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, payer.Currency)
+
+	_, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   merchant.ID,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	paid, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       800,
+	})
+	require.NoError(t, err)
+
+	_, err = store.OpenDisputeTx(context.Background(), OpenDisputeTxParams{
+		TransferID: paid.Transfer.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     900,
+		Reason:     "charged twice",
+		RespondBy:  time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrDisputeExceedsTransfer)
+
+	dispute, err := store.OpenDisputeTx(context.Background(), OpenDisputeTxParams{
+		TransferID: paid.Transfer.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     800,
+		Reason:     "product never delivered",
+		RespondBy:  time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, DisputeOpen, dispute.Status)
+	require.Equal(t, merchant.Owner, dispute.Merchant)
+
+	return dispute, paid.FromWallet, paid.ToWallet
+}
+
+func TestDisputePayerWinsTx(t *testing.T) {
+	store := NewStore(testDB)
+	dispute, payer, merchant := createDisputedTransfer(t)
+
+	_, err := store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{ID: dispute.ID, PayerWins: true})
+	require.ErrorIs(t, err, ErrDisputeNotUnderReview)
+
+	dispute, err = store.RespondDisputeTx(context.Background(), RespondDisputeTxParams{
+		ID:       dispute.ID,
+		Response: "it was delivered",
+		Now:      time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, DisputeUnderReview, dispute.Status)
+	require.True(t, dispute.RespondedAt.Valid)
+
+	_, err = store.RefundTx(context.Background(), RefundTxParams{TransferID: dispute.TransferID, Amount: 100, CreatedBy: "admin"})
+	require.ErrorIs(t, err, ErrRefundDisputed)
+
+	// the merchant spent 500 of the 800 meanwhile
+	_, err = store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   merchant.ID,
+		Amount:     300,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	_, err = store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{ID: dispute.ID, PayerWins: true, NegativeReserve: 499})
+	require.ErrorIs(t, err, ErrReserveExceeded)
+
+	result, err := store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{
+		ID:              dispute.ID,
+		PayerWins:       true,
+		DecidedBy:       "analyst",
+		Note:            "no proof of delivery",
+		NegativeReserve: 500,
+	})
+	require.NoError(t, err)
+	require.Equal(t, DisputePayerWon, result.Dispute.Status)
+	require.NotNil(t, result.Reversal)
+	require.Equal(t, result.Reversal.Transfer.ID, result.Dispute.ReversalTransferID.Int64)
+	require.Equal(t, int64(-500), result.Reversal.FromWallet.Balance)
+	require.Equal(t, payer.Balance+800, result.Reversal.ToWallet.Balance)
+
+	requireWalletLedgerBalance(t, result.Reversal.FromWallet)
+	requireWalletLedgerBalance(t, result.Reversal.ToWallet)
+
+	// the reversal gave back the whole transfer, nothing is left to refund
+	_, err = store.RefundTx(context.Background(), RefundTxParams{TransferID: dispute.TransferID, Amount: 1, CreatedBy: "admin"})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	rate, err := store.MerchantDisputeRate(context.Background(), merchant.Owner, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rate.Transfers)
+	require.Equal(t, int64(1), rate.Disputes)
+	require.Equal(t, int64(1), rate.PayerWon)
+	require.Equal(t, int64(10000), rate.BasisPoints)
+}
+
+func TestEscalateOverdueDisputesTx(t *testing.T) {
+	store := NewStore(testDB)
+	dispute, _, merchant := createDisputedTransfer(t)
+
+	disputes, err := store.EscalateOverdueDisputesTx(context.Background(), EscalateOverdueDisputesTxParams{
+		Now:       time.Now().Add(24 * time.Hour),
+		BatchSize: 1000,
+	})
+	require.NoError(t, err)
+
+	escalated := false
+	for _, d := range disputes {
+		require.Equal(t, DisputeUnderReview, d.Status)
+		escalated = escalated || d.ID == dispute.ID
+	}
+	require.True(t, escalated)
+
+	_, err = store.RespondDisputeTx(context.Background(), RespondDisputeTxParams{ID: dispute.ID, Response: "late", Now: time.Now()})
+	require.ErrorIs(t, err, ErrDisputeNotOpen)
+
+	result, err := store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{ID: dispute.ID, DecidedBy: "analyst", Note: "delivered"})
+	require.NoError(t, err)
+	require.Equal(t, DisputeMerchantWon, result.Dispute.Status)
+	require.Nil(t, result.Reversal)
+
+	wallet, err := store.GetWallet(context.Background(), merchant.ID)
+	require.NoError(t, err)
+	require.Equal(t, merchant.Balance, wallet.Balance)
+}
@@ -0,0 +1,322 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	DisputeOpen        = "open"
+	DisputeUnderReview = "under_review"
+	DisputePayerWon    = "payer_won"
+	DisputeMerchantWon = "merchant_won"
+)
+
+var (
+	// ErrDisputeExceedsTransfer is returned when disputing more than what is
+	// left of the transfer once refunded
+	ErrDisputeExceedsTransfer = errors.New("dispute amount exceeds the refundable amount of the transfer")
+	// ErrDisputeNotOpen is returned when responding to a dispute past its deadline or already responded
+	ErrDisputeNotOpen = errors.New("dispute is no longer open to a response")
+	// ErrDisputeNotUnderReview is returned when deciding a dispute that isn't waiting for an analyst
+	ErrDisputeNotUnderReview = errors.New("dispute is not under review")
+	// ErrReserveExceeded is returned when a reversal would take the merchant
+	// balance further below zero than its negative reserve
+	ErrReserveExceeded = errors.New("reversal exceeds the merchant negative reserve")
+	// ErrRefundDisputed is returned when refunding a transfer whose dispute isn't decided yet
+	ErrRefundDisputed = errors.New("transfer has a dispute waiting for a decision")
+)
+
+type OpenDisputeTxParams struct {
+	TransferID int64     `json:"transfer_id"`
+	OpenedBy   string    `json:"opened_by"`
+	Amount     int64     `json:"amount"`
+	Reason     string    `json:"reason"`
+	RespondBy  time.Time `json:"respond_by"`
+}
+
+// OpenDisputeTx disputes a transfer on behalf of its payer and notifies the
+// merchant, who has until RespondBy to respond
+func (store *SQLStore) OpenDisputeTx(ctx context.Context, arg OpenDisputeTxParams) (Dispute, error) {
+	var dispute Dispute
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		refunded, err := q.GetRefundedAmount(ctx, transfer.ID)
+		if err != nil {
+			return err
+		}
+
+		if arg.Amount <= 0 || refunded+arg.Amount > transfer.Amount {
+			return ErrDisputeExceedsTransfer
+		}
+
+		merchant, err := q.GetWallet(ctx, transfer.ToWalletID)
+		if err != nil {
+			return err
+		}
+
+		dispute, err = q.CreateDispute(ctx, CreateDisputeParams{
+			TransferID: transfer.ID,
+			OpenedBy:   arg.OpenedBy,
+			Merchant:   merchant.Owner,
+			Amount:     arg.Amount,
+			Reason:     arg.Reason,
+			RespondBy:  arg.RespondBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Transfer %d was disputed, respond before %s.", transfer.ID, arg.RespondBy.Format(time.RFC3339))
+		return notify(ctx, q, merchant.Owner, util.NotificationDisputeOpened, message, dispute)
+	})
+
+	return dispute, err
+}
+
+type RespondDisputeTxParams struct {
+	ID       int64     `json:"id"`
+	Response string    `json:"response"`
+	Now      time.Time `json:"now"`
+}
+
+// RespondDisputeTx records the merchant side and sends the dispute to review
+func (store *SQLStore) RespondDisputeTx(ctx context.Context, arg RespondDisputeTxParams) (Dispute, error) {
+	var dispute Dispute
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		dispute, err = q.GetDisputeForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if dispute.Status != DisputeOpen || !arg.Now.Before(dispute.RespondBy) {
+			return ErrDisputeNotOpen
+		}
+
+		dispute, err = q.RespondDispute(ctx, RespondDisputeParams{
+			ID:               dispute.ID,
+			MerchantResponse: arg.Response,
+		})
+		return err
+	})
+
+	return dispute, err
+}
+
+type EscalateOverdueDisputesTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// EscalateOverdueDisputesTx sends a batch of open disputes past their
+// deadline to review without a merchant response, letting both parties know
+func (store *SQLStore) EscalateOverdueDisputesTx(ctx context.Context, arg EscalateOverdueDisputesTxParams) ([]Dispute, error) {
+	var disputes []Dispute
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		disputes, err = q.ClaimOverdueDisputes(ctx, ClaimOverdueDisputesParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dispute := range disputes {
+			message := fmt.Sprintf("Dispute %d on transfer %d went to review without a merchant response.", dispute.ID, dispute.TransferID)
+			for _, owner := range []string{dispute.OpenedBy, dispute.Merchant} {
+				if err := notify(ctx, q, owner, util.NotificationDisputeEscalated, message, dispute); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return disputes, err
+}
+
+type DecideDisputeTxParams struct {
+	ID        int64  `json:"id"`
+	PayerWins bool   `json:"payer_wins"`
+	DecidedBy string `json:"decided_by"`
+	Note      string `json:"note"`
+	// NegativeReserve is how far below zero a reversal may take the balance of the merchant
+	NegativeReserve int64 `json:"negative_reserve"`
+}
+
+type DecideDisputeTxResult struct {
+	Dispute Dispute `json:"dispute"`
+	// Reversal is only set when the payer won
+	Reversal *TrasferTxResult `json:"reversal,omitempty"`
+}
+
+// DecideDisputeTx closes a dispute under review. When the payer wins, the
+// disputed amount is taken back from the merchant wallet, even past its
+// available balance as far as the negative reserve allows, and the cashback
+// the payer earned on it is clawed back
+func (store *SQLStore) DecideDisputeTx(ctx context.Context, arg DecideDisputeTxParams) (DecideDisputeTxResult, error) {
+	var result DecideDisputeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		dispute, err := q.GetDisputeForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if dispute.Status != DisputeUnderReview {
+			return ErrDisputeNotUnderReview
+		}
+
+		decide := DecideDisputeParams{
+			ID:           dispute.ID,
+			Status:       DisputeMerchantWon,
+			DecidedBy:    sql.NullString{String: arg.DecidedBy, Valid: true},
+			DecisionNote: arg.Note,
+		}
+
+		if arg.PayerWins {
+			reversal, err := reverseDisputedTransfer(ctx, q, dispute, arg.NegativeReserve)
+			if err != nil {
+				return err
+			}
+
+			result.Reversal = &reversal
+			decide.Status = DisputePayerWon
+			decide.ReversalTransferID = sql.NullInt64{Int64: reversal.Transfer.ID, Valid: true}
+		}
+
+		result.Dispute, err = q.DecideDispute(ctx, decide)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Dispute %d on transfer %d was decided: %s.", dispute.ID, dispute.TransferID, result.Dispute.Status)
+		for _, owner := range []string{dispute.OpenedBy, dispute.Merchant} {
+			if err := notify(ctx, q, owner, util.NotificationDisputeDecided, message, result.Dispute); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// reverseDisputedTransfer gives the disputed amount back to the payer and
+// claws back the cashback the transfer earned, like a refund of that amount
+func reverseDisputedTransfer(ctx context.Context, q *Queries, dispute Dispute, negativeReserve int64) (TrasferTxResult, error) {
+	original, err := q.GetTransfer(ctx, dispute.TransferID)
+	if err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	grant, err := lockCashbackGrant(ctx, q, original.ID)
+	if err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	merchant, err := lockWallets(ctx, q, original.ToWalletID, original.FromWalletID)
+	if err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	// the rolling reserve is there to cover chargebacks, so it is spent first
+	if merchant.AvailableBalance()+merchant.ReserveBalance-dispute.Amount < -negativeReserve {
+		return TrasferTxResult{}, ErrReserveExceeded
+	}
+
+	if _, err := useReserves(ctx, q, merchant, dispute.Amount); err != nil {
+		return TrasferTxResult{}, err
+	}
+
+	result, err := transfer(ctx, q, TrasferTxParms{
+		FromWalletID: original.ToWalletID,
+		ToWalletID:   original.FromWalletID,
+		Amount:       dispute.Amount,
+	})
+	if err != nil || grant == nil {
+		return result, err
+	}
+
+	refunded, err := q.GetRefundedAmount(ctx, original.ID)
+	if err != nil {
+		return result, err
+	}
+
+	err = clawBackCashback(ctx, q, *grant, original, refunded+dispute.Amount)
+
+	return result, err
+}
+
+// disputedAmount returns how much of the transfer its dispute gave back to
+// the payer. Refunds wait for open disputes and those under review, so the
+// amount is never given back twice
+func disputedAmount(ctx context.Context, q *Queries, transferID int64) (int64, error) {
+	dispute, err := q.GetDisputeByTransfer(ctx, transferID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch dispute.Status {
+	case DisputeOpen, DisputeUnderReview:
+		return 0, ErrRefundDisputed
+	case DisputePayerWon:
+		return dispute.Amount, nil
+	}
+
+	return 0, nil
+}
+
+type MerchantDisputeRate struct {
+	Merchant  string    `json:"merchant"`
+	Since     time.Time `json:"since"`
+	Transfers int64     `json:"transfers"`
+	Disputes  int64     `json:"disputes"`
+	PayerWon  int64     `json:"payer_won"`
+	// BasisPoints is disputes per transfers received, 100 is 1%
+	BasisPoints int64 `json:"basis_points"`
+}
+
+// MerchantDisputeRate compares the disputes opened against the merchant since
+// a time to the transfers it received meanwhile
+func (store *SQLStore) MerchantDisputeRate(ctx context.Context, merchant string, since time.Time) (MerchantDisputeRate, error) {
+	rate := MerchantDisputeRate{Merchant: merchant, Since: since}
+
+	counts, err := store.GetMerchantDisputeCounts(ctx, GetMerchantDisputeCountsParams{Merchant: merchant, Since: since})
+	if err != nil {
+		return rate, err
+	}
+
+	rate.Transfers, err = store.CountMerchantTransfersSince(ctx, CountMerchantTransfersSinceParams{Merchant: merchant, Since: since})
+	if err != nil {
+		return rate, err
+	}
+
+	rate.Disputes = counts.Disputes
+	rate.PayerWon = counts.PayerWon
+	if rate.Transfers > 0 {
+		rate.BasisPoints = rate.Disputes * 10000 / rate.Transfers
+	}
+
+	return rate, nil
+}
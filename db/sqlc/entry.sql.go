@@ -15,7 +15,7 @@ INSERT INTO entries (
     amount
 ) VALUES (
     $1, $2
-) RETURNING id, wallet_id, amount, created_at
+) RETURNING id, wallet_id, amount, created_at, kind, transfer_id, fee_schedule_id, prev_hash, hash
 `
 
 type CreateEntryParams struct {
@@ -31,12 +31,17 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 		&i.WalletID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.TransferID,
+		&i.FeeScheduleID,
+		&i.PrevHash,
+		&i.Hash,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, wallet_id, amount, created_at FROM entries
+SELECT id, wallet_id, amount, created_at, kind, transfer_id, fee_schedule_id, prev_hash, hash FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +53,29 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.WalletID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.TransferID,
+		&i.FeeScheduleID,
+		&i.PrevHash,
+		&i.Hash,
 	)
 	return i, err
 }
 
+const getLastEntryID = `-- name: GetLastEntryID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM entries
+WHERE wallet_id = $1
+`
+
+func (q *Queries) GetLastEntryID(ctx context.Context, walletID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLastEntryID, walletID)
+	var lastID int64
+	err := row.Scan(&lastID)
+	return lastID, err
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, wallet_id, amount, created_at FROM entries
+SELECT id, wallet_id, amount, created_at, kind, transfer_id, fee_schedule_id, prev_hash, hash FROM entries
 WHERE wallet_id = $1
 ORDER BY id
 LIMIT $2
@@ -80,6 +102,57 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.WalletID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.TransferID,
+			&i.FeeScheduleID,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesAfter = `-- name: ListEntriesAfter :many
+SELECT id, wallet_id, amount, created_at, kind, transfer_id, fee_schedule_id, prev_hash, hash FROM entries
+WHERE wallet_id = $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListEntriesAfterParams struct {
+	WalletID  int64 `json:"wallet_id"`
+	AfterID   int64 `json:"after_id"`
+	BatchSize int32 `json:"batch_size"`
+}
+
+func (q *Queries) ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesAfter, arg.WalletID, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.WalletID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.TransferID,
+			&i.FeeScheduleID,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomEscrow(t *testing.T, buyer, seller Wallet, releaseAt time.Time) CreateEscrowTxResult {
+	result, err := NewStore(testDB).CreateEscrowTx(context.Background(), CreateEscrowTxParams{
+		BuyerWalletID:  buyer.ID,
+		SellerWalletID: seller.ID,
+		Amount:         300,
+		Description:    "used bike",
+		ReleaseAt:      releaseAt,
+		CreatedBy:      buyer.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowHeld, result.Escrow.Status)
+	require.Equal(t, buyer.Balance-300, result.BuyerWallet.Balance)
+	require.Equal(t, int64(-300), result.BuyerEntry.Amount)
+
+	requireWalletLedgerBalance(t, result.BuyerWallet)
+
+	return result
+}
+
+func TestConfirmEscrowTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createRandomWalletIn(t, buyer.Currency)
+	escrow := createRandomEscrow(t, buyer, seller, time.Now().Add(time.Hour)).Escrow
+
+	result, err := store.ConfirmEscrowTx(context.Background(), escrow.ID)
+	require.NoError(t, err)
+	require.Equal(t, EscrowReleased, result.Escrow.Status)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.ID, result.Escrow.TransferID.Int64)
+	require.Equal(t, seller.ID, result.Wallet.ID)
+	require.Equal(t, seller.Balance+300, result.Wallet.Balance)
+
+	requireWalletLedgerBalance(t, *result.Wallet)
+
+	_, err = store.ConfirmEscrowTx(context.Background(), escrow.ID)
+	require.ErrorIs(t, err, ErrEscrowSettled)
+}
+
+func TestConfirmEscrowTxWithholdsReserve(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createMerchantWithReserve(t, buyer.Currency, 1000, 7)
+	escrow := createRandomEscrow(t, buyer, seller, time.Now().Add(time.Hour)).Escrow
+
+	// a released escrow is received like any other transfer
+	result, err := store.ConfirmEscrowTx(context.Background(), escrow.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.Balance+300, result.Wallet.Balance)
+	require.Equal(t, int64(30), result.Wallet.ReserveBalance)
+	require.Equal(t, seller.Balance+270, result.Wallet.AvailableBalance())
+
+	requireWalletLedgerBalance(t, *result.Wallet)
+}
+
+func TestAgreeEscrowRefundTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createRandomWalletIn(t, buyer.Currency)
+	escrow := createRandomEscrow(t, buyer, seller, time.Now().Add(time.Hour)).Escrow
+
+	result, err := store.AgreeEscrowRefundTx(context.Background(), AgreeEscrowRefundTxParams{ID: escrow.ID, Buyer: true})
+	require.NoError(t, err)
+	require.Equal(t, EscrowHeld, result.Escrow.Status)
+	require.True(t, result.Escrow.BuyerAgreedRefund)
+	require.Nil(t, result.Wallet)
+
+	result, err = store.AgreeEscrowRefundTx(context.Background(), AgreeEscrowRefundTxParams{ID: escrow.ID})
+	require.NoError(t, err)
+	require.Equal(t, EscrowRefunded, result.Escrow.Status)
+	require.Nil(t, result.Transfer)
+	require.Equal(t, buyer.ID, result.Wallet.ID)
+	require.Equal(t, int64(1000), result.Wallet.Balance)
+
+	requireWalletLedgerBalance(t, *result.Wallet)
+}
+
+func TestDisputeAndResolveEscrowTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createRandomWalletIn(t, buyer.Currency)
+	admin := createRandomUser(t)
+	escrow := createRandomEscrow(t, buyer, seller, time.Now().Add(-time.Minute)).Escrow
+
+	disputed, err := store.DisputeEscrowTx(context.Background(), DisputeEscrowTxParams{
+		ID:          escrow.ID,
+		DisputedBy:  buyer.Owner,
+		Reason:      "never arrived",
+		NotifyOwner: seller.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowDisputed, disputed.Status)
+	require.Equal(t, buyer.Owner, disputed.DisputedBy.String)
+
+	// disputed escrows aren't released on timeout
+	for {
+		released, err := store.ReleaseDueEscrowsTx(context.Background(), ReleaseDueEscrowsTxParams{Now: time.Now(), BatchSize: 10})
+		require.NoError(t, err)
+		for _, released := range released {
+			require.NotEqual(t, escrow.ID, released.ID)
+		}
+		if len(released) < 10 {
+			break
+		}
+	}
+
+	result, err := store.ResolveEscrowTx(context.Background(), ResolveEscrowTxParams{
+		ID:        escrow.ID,
+		Release:   true,
+		DecidedBy: admin.Username,
+		Note:      "tracking shows delivery",
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowReleased, result.Escrow.Status)
+	require.Equal(t, admin.Username, result.Escrow.DecidedBy.String)
+	require.Equal(t, seller.Balance+300, result.Wallet.Balance)
+}
+
+func TestReleaseDueEscrowsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createRandomWalletIn(t, buyer.Currency)
+	escrow := createRandomEscrow(t, buyer, seller, time.Now().Add(-time.Minute)).Escrow
+
+	for {
+		released, err := store.ReleaseDueEscrowsTx(context.Background(), ReleaseDueEscrowsTxParams{Now: time.Now(), BatchSize: 10})
+		require.NoError(t, err)
+		if len(released) < 10 {
+			break
+		}
+	}
+
+	escrow, err := store.GetEscrow(context.Background(), escrow.ID)
+	require.NoError(t, err)
+	require.Equal(t, EscrowReleased, escrow.Status)
+	require.True(t, escrow.TransferID.Valid)
+
+	seller, err = store.GetWallet(context.Background(), seller.ID)
+	require.NoError(t, err)
+	requireWalletLedgerBalance(t, seller)
+}
+
+func TestCreateEscrowTxLimitsAndRisk(t *testing.T) {
+	store := NewStore(testDB)
+
+	buyer := createFundedWallet(t, 1000)
+	seller := createRandomWalletIn(t, buyer.Currency)
+
+	arg := CreateEscrowTxParams{
+		BuyerWalletID:  buyer.ID,
+		SellerWalletID: seller.ID,
+		Amount:         300,
+		ReleaseAt:      time.Now().Add(time.Hour),
+		CreatedBy:      buyer.Owner,
+		Limits:         limitsParams(util.TransferLimits{PerTransfer: 100}),
+	}
+
+	_, err := store.CreateEscrowTx(context.Background(), arg)
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, util.LimitPerTransfer, limitErr.Limit)
+
+	// escrows can't be held for review, so a review blocks them too
+	for _, outcome := range []string{RiskReview, RiskBlock} {
+		arg.Limits = nil
+		arg.Risk = stubEvaluator{outcome: outcome}
+
+		result, err := store.CreateEscrowTx(context.Background(), arg)
+		require.ErrorIs(t, err, ErrTransferBlocked)
+		require.NotNil(t, result.RiskDecision)
+		require.Equal(t, outcome, result.RiskDecision.Outcome)
+	}
+
+	updated, err := store.GetWallet(context.Background(), buyer.ID)
+	require.NoError(t, err)
+	require.Equal(t, buyer.Balance, updated.Balance)
+
+	arg.Risk = stubEvaluator{outcome: RiskAllow}
+	result, err := store.CreateEscrowTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, RiskAllow, result.RiskDecision.Outcome)
+	require.Equal(t, buyer.Balance-300, result.BuyerWallet.Balance)
+}
+
+func TestReleaseDueEscrowsTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createFundedWallet(t, 10000)
+	wallet2 := createFundedWallet(t, 10000)
+
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		// the escrow sold by the wallet with the higher id is due first, so
+		// it is paid out before the other one
+		createRandomEscrow(t, wallet1, wallet2, time.Now().Add(-2*time.Minute))
+		createRandomEscrow(t, wallet2, wallet1, time.Now().Add(-time.Minute))
+
+		go func() {
+			_, err := store.ReleaseDueEscrowsTx(context.Background(), ReleaseDueEscrowsTxParams{Now: time.Now(), BatchSize: 100})
+			errs <- err
+		}()
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TrasferTxParms{
+				FromWalletID: wallet1.ID,
+				ToWalletID:   wallet2.ID,
+				Amount:       10,
+			})
+			errs <- err
+		}()
+
+		for j := 0; j < 2; j++ {
+			require.NoError(t, <-errs)
+		}
+
+		var err error
+		wallet1, err = store.GetWallet(context.Background(), wallet1.ID)
+		require.NoError(t, err)
+		wallet2, err = store.GetWallet(context.Background(), wallet2.ID)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,412 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	EscrowHeld     = "held"
+	EscrowDisputed = "disputed"
+	EscrowReleased = "released"
+	EscrowRefunded = "refunded"
+)
+
+// ErrEscrowSettled is returned when acting on an escrow that was already released or refunded
+var ErrEscrowSettled = errors.New("escrow was already settled")
+
+type CreateEscrowTxParams struct {
+	BuyerWalletID  int64     `json:"buyer_wallet_id"`
+	SellerWalletID int64     `json:"seller_wallet_id"`
+	Amount         int64     `json:"amount"`
+	Description    string    `json:"description"`
+	ReleaseAt      time.Time `json:"release_at"`
+	CreatedBy      string    `json:"created_by"`
+	// Limits are checked against the buyer when set
+	Limits *TransferLimitsParams `json:"-"`
+	// Risk evaluates the payment to the seller when set
+	Risk RiskEvaluator `json:"-"`
+}
+
+type CreateEscrowTxResult struct {
+	Escrow      Escrow `json:"escrow"`
+	BuyerWallet Wallet `json:"buyer_wallet"`
+	BuyerEntry  Entry  `json:"buyer_entry"`
+	// RiskDecision is only set when the payment was evaluated
+	RiskDecision *RiskDecision `json:"risk_decision,omitempty"`
+}
+
+// CreateEscrowTx takes the amount from the buyer into the escrow account,
+// where it stays until the escrow is released to the seller or refunded.
+// The available balance of the buyer must cover it. Escrows can't be held
+// for review, so a payment the risk rules would review is blocked, returning
+// ErrTransferBlocked
+func (store *SQLStore) CreateEscrowTx(ctx context.Context, arg CreateEscrowTxParams) (CreateEscrowTxResult, error) {
+	var result CreateEscrowTxResult
+	var blocked bool
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		buyer, err := lockWallets(ctx, q, arg.BuyerWalletID, arg.SellerWalletID)
+		if err != nil {
+			return err
+		}
+
+		if buyer.AvailableBalance() < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		if arg.Limits != nil {
+			if err := checkTransferLimits(ctx, q, buyer, arg.Amount, *arg.Limits); err != nil {
+				return err
+			}
+		}
+
+		if arg.Risk != nil {
+			decision, err := assessRisk(ctx, q, TrasferTxParms{
+				FromWalletID: arg.BuyerWalletID,
+				ToWalletID:   arg.SellerWalletID,
+				Amount:       arg.Amount,
+				Risk:         arg.Risk,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.RiskDecision = &decision
+			if decision.Outcome != RiskAllow {
+				// commit the decision so it can be audited
+				blocked = true
+				return nil
+			}
+		}
+
+		result.Escrow, err = q.CreateEscrow(ctx, CreateEscrowParams{
+			BuyerWalletID:  arg.BuyerWalletID,
+			SellerWalletID: arg.SellerWalletID,
+			Amount:         arg.Amount,
+			Description:    arg.Description,
+			ReleaseAt:      arg.ReleaseAt,
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.BuyerEntry, err = writeEntry(ctx, q, CreateEntryParams{
+			WalletID: arg.BuyerWalletID,
+			Amount:   -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.BuyerWallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+			Amount: -arg.Amount,
+			ID:     arg.BuyerWalletID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = postSystemMovement(ctx, q, CreateJournalParams{
+			Kind:        JournalEscrowHold,
+			Description: fmt.Sprintf("escrow %d", result.Escrow.ID),
+		}, result.BuyerWallet, AccountEscrow, -arg.Amount)
+		return err
+	})
+
+	if err == nil && blocked {
+		err = ErrTransferBlocked
+	}
+
+	return result, err
+}
+
+type EscrowTxResult struct {
+	Escrow Escrow `json:"escrow"`
+	// Transfer is only set when the escrow was released to the seller
+	Transfer *Transfer `json:"transfer,omitempty"`
+	// Wallet and Entry are the side that got the amount, once settled
+	Wallet *Wallet `json:"wallet,omitempty"`
+	Entry  *Entry  `json:"entry,omitempty"`
+}
+
+// ConfirmEscrowTx releases the escrow to the seller once the buyer confirms
+// the sale, even when it was disputed
+func (store *SQLStore) ConfirmEscrowTx(ctx context.Context, id int64) (EscrowTxResult, error) {
+	var result EscrowTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		escrow, err := openEscrowForUpdate(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		result, err = payOutEscrow(ctx, q, escrow, SettleEscrowParams{Status: EscrowReleased})
+		return err
+	})
+
+	return result, err
+}
+
+type AgreeEscrowRefundTxParams struct {
+	ID int64 `json:"id"`
+	// Buyer tells which party agreed, the seller otherwise
+	Buyer bool `json:"buyer"`
+}
+
+// AgreeEscrowRefundTx records that a party agrees to refund the buyer. The
+// escrow is refunded once both parties agreed
+func (store *SQLStore) AgreeEscrowRefundTx(ctx context.Context, arg AgreeEscrowRefundTxParams) (EscrowTxResult, error) {
+	var result EscrowTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		escrow, err := openEscrowForUpdate(ctx, q, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Escrow, err = q.AgreeEscrowRefund(ctx, AgreeEscrowRefundParams{
+			Buyer:  arg.Buyer,
+			Seller: !arg.Buyer,
+			ID:     escrow.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !result.Escrow.BuyerAgreedRefund || !result.Escrow.SellerAgreedRefund {
+			return nil
+		}
+
+		result, err = payOutEscrow(ctx, q, result.Escrow, SettleEscrowParams{Status: EscrowRefunded})
+		return err
+	})
+
+	return result, err
+}
+
+type DisputeEscrowTxParams struct {
+	ID         int64  `json:"id"`
+	DisputedBy string `json:"disputed_by"`
+	Reason     string `json:"reason"`
+	// NotifyOwner is the other party of the escrow
+	NotifyOwner string `json:"-"`
+}
+
+// DisputeEscrowTx stops the escrow from being released on timeout, until the
+// parties settle it or an admin resolves it
+func (store *SQLStore) DisputeEscrowTx(ctx context.Context, arg DisputeEscrowTxParams) (Escrow, error) {
+	var escrow Escrow
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		escrow, err = q.GetEscrowForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if escrow.Status != EscrowHeld {
+			return ErrEscrowSettled
+		}
+
+		escrow, err = q.DisputeEscrow(ctx, DisputeEscrowParams{
+			ID:            escrow.ID,
+			DisputedBy:    sql.NullString{String: arg.DisputedBy, Valid: true},
+			DisputeReason: arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Escrow %d was disputed by %s.", escrow.ID, arg.DisputedBy)
+		return notify(ctx, q, arg.NotifyOwner, util.NotificationEscrowDisputed, message, escrow)
+	})
+
+	return escrow, err
+}
+
+type ResolveEscrowTxParams struct {
+	ID        int64  `json:"id"`
+	Release   bool   `json:"release"`
+	DecidedBy string `json:"decided_by"`
+	Note      string `json:"note"`
+}
+
+// ResolveEscrowTx releases or refunds the escrow on the decision of an admin
+func (store *SQLStore) ResolveEscrowTx(ctx context.Context, arg ResolveEscrowTxParams) (EscrowTxResult, error) {
+	var result EscrowTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		escrow, err := openEscrowForUpdate(ctx, q, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		settle := SettleEscrowParams{
+			Status:    EscrowRefunded,
+			DecidedBy: sql.NullString{String: arg.DecidedBy, Valid: true},
+			Note:      arg.Note,
+		}
+		if arg.Release {
+			settle.Status = EscrowReleased
+		}
+
+		result, err = payOutEscrow(ctx, q, escrow, settle)
+		return err
+	})
+
+	return result, err
+}
+
+type ReleaseDueEscrowsTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// ReleaseDueEscrowsTx releases a batch of undisputed escrows past their
+// release time to their sellers
+func (store *SQLStore) ReleaseDueEscrowsTx(ctx context.Context, arg ReleaseDueEscrowsTxParams) ([]Escrow, error) {
+	var released []Escrow
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		escrows, err := q.ClaimDueEscrows(ctx, ClaimDueEscrowsParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the wallets of the whole batch are locked in id order, instead of
+		// as each escrow is paid out in release order
+		walletIDs := make([]int64, 0, 2*len(escrows))
+		seen := make(map[int64]bool, 2*len(escrows))
+		for _, escrow := range escrows {
+			for _, id := range []int64{escrow.BuyerWalletID, escrow.SellerWalletID} {
+				if !seen[id] {
+					seen[id] = true
+					walletIDs = append(walletIDs, id)
+				}
+			}
+		}
+
+		if err := lockWalletIDs(ctx, q, walletIDs); err != nil {
+			return err
+		}
+
+		released = make([]Escrow, 0, len(escrows))
+		for _, escrow := range escrows {
+			result, err := payOutEscrow(ctx, q, escrow, SettleEscrowParams{
+				Status: EscrowReleased,
+				Note:   "released on timeout",
+			})
+			if err != nil {
+				return err
+			}
+			released = append(released, result.Escrow)
+		}
+
+		return nil
+	})
+
+	return released, err
+}
+
+// openEscrowForUpdate locks an escrow that is held or disputed
+func openEscrowForUpdate(ctx context.Context, q *Queries, id int64) (Escrow, error) {
+	escrow, err := q.GetEscrowForUpdate(ctx, id)
+	if err != nil {
+		return escrow, err
+	}
+
+	if escrow.Status != EscrowHeld && escrow.Status != EscrowDisputed {
+		return escrow, ErrEscrowSettled
+	}
+
+	return escrow, nil
+}
+
+// payOutEscrow moves the amount out of the escrow account, to the seller
+// with a transfer from the buyer when released, or back to the buyer
+func payOutEscrow(ctx context.Context, q *Queries, escrow Escrow, arg SettleEscrowParams) (EscrowTxResult, error) {
+	var result EscrowTxResult
+	var entry Entry
+	var wallet Wallet
+	var err error
+
+	arg.ID = escrow.ID
+
+	if arg.Status == EscrowReleased {
+		_, err = q.GetWalletForUpdate(ctx, escrow.SellerWalletID)
+		if err != nil {
+			return result, err
+		}
+
+		var transfer Transfer
+		transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromWalletID: escrow.BuyerWalletID,
+			ToWalletID:   escrow.SellerWalletID,
+			Amount:       escrow.Amount,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		entry, wallet, err = receiveFromAccount(ctx, q, transfer, CreateJournalParams{
+			Kind:        JournalEscrowRelease,
+			Description: fmt.Sprintf("escrow %d", escrow.ID),
+		}, AccountEscrow)
+		if err != nil {
+			return result, err
+		}
+
+		result.Transfer = &transfer
+		arg.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+	} else {
+		_, err = q.GetWalletForUpdate(ctx, escrow.BuyerWalletID)
+		if err != nil {
+			return result, err
+		}
+
+		entry, err = writeEntry(ctx, q, CreateEntryParams{
+			WalletID: escrow.BuyerWalletID,
+			Amount:   escrow.Amount,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		wallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+			Amount: escrow.Amount,
+			ID:     escrow.BuyerWalletID,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		_, err = postSystemMovement(ctx, q, CreateJournalParams{
+			Kind:        JournalEscrowRefund,
+			Description: fmt.Sprintf("escrow %d", escrow.ID),
+		}, wallet, AccountEscrow, escrow.Amount)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Escrow, err = q.SettleEscrow(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	result.Wallet = &wallet
+	result.Entry = &entry
+
+	message := fmt.Sprintf("Escrow %d was %s.", escrow.ID, result.Escrow.Status)
+	return result, notify(ctx, q, wallet.Owner, util.NotificationEscrowSettled, message, result.Escrow)
+}
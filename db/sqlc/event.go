@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// publishEvent writes the event to the webhook outbox and queues one delivery
+// for every enabled endpoint of the owner subscribed to its type. It must run
+// inside the transaction that caused the event, so only committed changes are
+// ever delivered
+func publishEvent(ctx context.Context, q *Queries, owner string, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	event, err := q.CreateWebhookEvent(ctx, CreateWebhookEventParams{
+		Owner:     owner,
+		EventType: eventType,
+		Data:      payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateWebhookDeliveries(ctx, CreateWebhookDeliveriesParams{
+		EventID:   event.ID,
+		Owner:     owner,
+		EventType: eventType,
+	})
+
+	return err
+}
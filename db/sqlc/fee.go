@@ -0,0 +1,338 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	EntryStandard = "standard"
+	EntryFee      = "fee"
+)
+
+// ErrRevenueWalletNotFound is returned when a fee is due in a currency the
+// platform has no wallet in
+var ErrRevenueWalletNotFound = errors.New("platform revenue wallet not found")
+
+// FeeParams turns fees on for a transfer
+type FeeParams struct {
+	Now time.Time
+	// RevenueOwner owns the wallets fees are credited to, and is never charged
+	RevenueOwner string
+}
+
+// NewFeeParams returns the params charging fees at now, or nil when there is
+// no revenue owner to credit them to
+func NewFeeParams(revenueOwner string, now time.Time) *FeeParams {
+	if revenueOwner == "" {
+		return nil
+	}
+
+	return &FeeParams{Now: now, RevenueOwner: revenueOwner}
+}
+
+// FeeQuote is a fee due on a transfer and the wallet paying it
+type FeeQuote struct {
+	Operation  string `json:"operation"`
+	ScheduleID int64  `json:"schedule_id"`
+	WalletID   int64  `json:"wallet_id"`
+	Amount     int64  `json:"amount"`
+}
+
+// FeeCharge is a fee charged on a transfer, with the entries that moved it
+type FeeCharge struct {
+	FeeQuote
+	Entry        Entry `json:"entry"`
+	RevenueEntry Entry `json:"revenue_entry"`
+}
+
+// HasMerchantRole reports whether an admin gave the user the merchant role.
+// Fees go by the role, the is_merchant flag is set by users themselves
+func (user User) HasMerchantRole() bool {
+	return user.Role == util.MerchantRole
+}
+
+// Rule returns how the schedule computes fees
+func (schedule FeeSchedule) Rule() (util.FeeRule, error) {
+	rule := util.FeeRule{
+		Kind:        schedule.Kind,
+		FixedAmount: schedule.FixedAmount,
+		BasisPoints: schedule.BasisPoints,
+		MinFee:      schedule.MinFee,
+		MaxFee:      schedule.MaxFee,
+	}
+
+	if len(schedule.Tiers) > 0 {
+		if err := json.Unmarshal(schedule.Tiers, &rule.Tiers); err != nil {
+			return rule, fmt.Errorf("fee schedule [%d] has invalid tiers: %w", schedule.ID, err)
+		}
+	}
+
+	return rule, nil
+}
+
+type QuoteTransferFeesParams struct {
+	FromWalletID int64 `json:"from_wallet_id"`
+	ToWalletID   int64 `json:"to_wallet_id"`
+	Amount       int64 `json:"amount"`
+	FeeParams
+}
+
+type QuoteTransferFeesResult struct {
+	Amount int64      `json:"amount"`
+	Fees   []FeeQuote `json:"fees"`
+	// TotalDebit is taken from the sender and NetCredit left to the receiver
+	TotalDebit int64 `json:"total_debit"`
+	NetCredit  int64 `json:"net_credit"`
+}
+
+// QuoteTransferFees returns the fees TransferTx would charge on the transfer
+func (store *SQLStore) QuoteTransferFees(ctx context.Context, arg QuoteTransferFeesParams) (QuoteTransferFeesResult, error) {
+	result := QuoteTransferFeesResult{
+		Amount:     arg.Amount,
+		TotalDebit: arg.Amount,
+		NetCredit:  arg.Amount,
+	}
+
+	from, err := store.GetWallet(ctx, arg.FromWalletID)
+	if err != nil {
+		return result, err
+	}
+
+	to, err := store.GetWallet(ctx, arg.ToWalletID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Fees, err = quoteTransferFees(ctx, store.Queries, from, to, arg.Amount, arg.FeeParams)
+	if err != nil {
+		return result, err
+	}
+
+	for _, fee := range result.Fees {
+		if fee.WalletID == from.ID {
+			result.TotalDebit += fee.Amount
+		} else {
+			result.NetCredit -= fee.Amount
+		}
+	}
+
+	return result, nil
+}
+
+// quoteTransferFees returns the transfer fee of the sender and, when the
+// receiver is a merchant, its merchant receipt fee. Fees are computed with
+// the schedules in effect at arg.Now
+func quoteTransferFees(ctx context.Context, q *Queries, from Wallet, to Wallet, amount int64, arg FeeParams) ([]FeeQuote, error) {
+	fees := []FeeQuote{}
+
+	sender, err := q.GetUser(ctx, from.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if sender.Username != arg.RevenueOwner {
+		fee, ok, err := quoteFee(ctx, q, util.FeeTransfer, util.FeeUserType(sender.HasMerchantRole()), from, amount, arg.Now)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fees = append(fees, fee)
+		}
+	}
+
+	receiver, err := q.GetUser(ctx, to.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if receiver.HasMerchantRole() && receiver.Username != arg.RevenueOwner {
+		fee, ok, err := quoteFee(ctx, q, util.FeeMerchantReceipt, util.FeeUserMerchant, to, amount, arg.Now)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fees = append(fees, fee)
+		}
+	}
+
+	return fees, nil
+}
+
+// quoteFee computes the fee the wallet pays for the operation, reporting
+// false when no schedule is in effect or the fee is zero
+func quoteFee(ctx context.Context, q *Queries, operation, userType string, wallet Wallet, amount int64, now time.Time) (FeeQuote, bool, error) {
+	schedule, err := q.GetActiveFeeSchedule(ctx, GetActiveFeeScheduleParams{
+		Operation: operation,
+		UserType:  userType,
+		Currency:  wallet.Currency,
+		Now:       now,
+	})
+	if err == sql.ErrNoRows {
+		return FeeQuote{}, false, nil
+	}
+	if err != nil {
+		return FeeQuote{}, false, err
+	}
+
+	rule, err := schedule.Rule()
+	if err != nil {
+		return FeeQuote{}, false, err
+	}
+
+	fee := FeeQuote{
+		Operation:  operation,
+		ScheduleID: schedule.ID,
+		WalletID:   wallet.ID,
+		Amount:     rule.Compute(amount),
+	}
+
+	return fee, fee.Amount > 0, nil
+}
+
+// lockTransferWallets locks both wallets of a transfer and, when fees are on,
+// the revenue wallet of its currency, all in ID order like any other
+// transfer. chargeFees locks the revenue wallet again, which no longer waits.
+// It returns the locked sender
+func lockTransferWallets(ctx context.Context, q *Queries, fromWalletID int64, toWalletID int64, fees *FeeParams) (Wallet, error) {
+	ids := []int64{fromWalletID, toWalletID}
+
+	if fees != nil {
+		revenueID, ok, err := revenueWalletID(ctx, q, fromWalletID, *fees)
+		if err != nil {
+			return Wallet{}, err
+		}
+		if ok {
+			ids = append(ids, revenueID)
+		}
+	}
+
+	if err := lockWalletIDs(ctx, q, ids); err != nil {
+		return Wallet{}, err
+	}
+
+	return q.GetWallet(ctx, fromWalletID)
+}
+
+// revenueWalletID returns the revenue wallet fees paid out of the wallet are
+// credited to. Wallets never change currency, so it is read before locking
+func revenueWalletID(ctx context.Context, q *Queries, walletID int64, arg FeeParams) (int64, bool, error) {
+	wallet, err := q.GetWallet(ctx, walletID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	revenue, err := q.GetWalletByOwnerAndCurrency(ctx, GetWalletByOwnerAndCurrencyParams{
+		Owner:    arg.RevenueOwner,
+		Currency: wallet.Currency,
+	})
+	if err == sql.ErrNoRows {
+		// chargeFees reports it if a fee is due
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return revenue.ID, true, nil
+}
+
+// chargeFees moves the fees of a transfer to the revenue wallet of its
+// currency, as fee entries linked to the transfer. Fees the recipient pays
+// come out of the receivable the transfer scheduled first
+func chargeFees(ctx context.Context, q *Queries, transfer Transfer, currency string, fees []FeeQuote, arg FeeParams) ([]FeeCharge, map[int64]Wallet, error) {
+	charges := make([]FeeCharge, 0, len(fees))
+	wallets := make(map[int64]Wallet, len(fees))
+
+	if len(fees) == 0 {
+		return charges, wallets, nil
+	}
+
+	revenue, err := q.GetWalletByOwnerAndCurrency(ctx, GetWalletByOwnerAndCurrencyParams{
+		Owner:    arg.RevenueOwner,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("%w: %s", ErrRevenueWalletNotFound, currency)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transferID := sql.NullInt64{Int64: transfer.ID, Valid: true}
+
+	for _, fee := range fees {
+		charge := FeeCharge{FeeQuote: fee}
+		scheduleID := sql.NullInt64{Int64: fee.ScheduleID, Valid: true}
+
+		_, err = lockWallets(ctx, q, fee.WalletID, revenue.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = postWalletTransfer(ctx, q, JournalFee, transfer.ID, fee.WalletID, revenue.ID, fee.Amount)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		charge.Entry, err = writeFeeEntry(ctx, q, CreateFeeEntryParams{
+			WalletID:      fee.WalletID,
+			Amount:        -fee.Amount,
+			TransferID:    transferID,
+			FeeScheduleID: scheduleID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		charge.RevenueEntry, err = writeFeeEntry(ctx, q, CreateFeeEntryParams{
+			WalletID:      revenue.ID,
+			Amount:        fee.Amount,
+			TransferID:    transferID,
+			FeeScheduleID: scheduleID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var payer Wallet
+		if fee.WalletID < revenue.ID {
+			payer, revenue, err = addMoney(ctx, q, fee.WalletID, -fee.Amount, revenue.ID, fee.Amount)
+		} else {
+			revenue, payer, err = addMoney(ctx, q, revenue.ID, fee.Amount, fee.WalletID, -fee.Amount)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if payer.ID == transfer.ToWalletID {
+			payer, err = deductReceivableFee(ctx, q, transfer, payer, fee.Amount)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		wallets[payer.ID] = payer
+		charges = append(charges, charge)
+	}
+
+	return charges, wallets, nil
+}
+
+// senderFees adds up the fees the sender of a transfer pays
+func senderFees(fees []FeeQuote, fromWalletID int64) int64 {
+	var total int64
+
+	for _, fee := range fees {
+		if fee.WalletID == fromWalletID {
+			total += fee.Amount
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,280 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomFeeSchedule(t *testing.T, operation, userType, currency string, rule util.FeeRule, effectiveFrom time.Time) FeeSchedule {
+	if rule.Tiers == nil {
+		rule.Tiers = []util.FeeTier{}
+	}
+
+	tiers, err := json.Marshal(rule.Tiers)
+	require.NoError(t, err)
+
+	schedule, err := testQueries.CreateFeeSchedule(context.Background(), CreateFeeScheduleParams{
+		Operation:     operation,
+		UserType:      userType,
+		Currency:      currency,
+		Kind:          rule.Kind,
+		FixedAmount:   rule.FixedAmount,
+		BasisPoints:   rule.BasisPoints,
+		MinFee:        rule.MinFee,
+		MaxFee:        rule.MaxFee,
+		Tiers:         tiers,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	return schedule
+}
+
+func createRandomMerchantWalletIn(t *testing.T, currency string) Wallet {
+	wallet := createRandomSelfDeclaredMerchantWalletIn(t, currency)
+
+	_, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: wallet.Owner,
+		Role:     util.MerchantRole,
+	})
+	require.NoError(t, err)
+
+	return wallet
+}
+
+// createRandomSelfDeclaredMerchantWalletIn creates a wallet whose owner set
+// is_merchant on their own, without being given the merchant role
+func createRandomSelfDeclaredMerchantWalletIn(t *testing.T, currency string) Wallet {
+	wallet := createRandomWalletIn(t, currency)
+
+	user, err := testQueries.GetUser(context.Background(), wallet.Owner)
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:          user.Username,
+		HashedPassword:    user.HashedPassword,
+		Email:             user.Email,
+		IsMerchant:        sql.NullBool{Bool: true, Valid: true},
+		PasswordChangedAt: user.PasswordChangedAt,
+	})
+	require.NoError(t, err)
+
+	return wallet
+}
+
+func TestGetActiveFeeSchedule(t *testing.T) {
+	operation := util.RandomString(8)
+	now := time.Now()
+	rule := util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}
+
+	createRandomFeeSchedule(t, operation, util.FeeUserCustomer, util.USD, rule, now.Add(-2*time.Hour))
+	current := createRandomFeeSchedule(t, operation, util.FeeUserCustomer, util.USD, rule, now.Add(-time.Hour))
+	future := createRandomFeeSchedule(t, operation, util.FeeUserCustomer, util.USD, rule, now.Add(time.Hour))
+
+	schedule, err := testQueries.GetActiveFeeSchedule(context.Background(), GetActiveFeeScheduleParams{
+		Operation: operation,
+		UserType:  util.FeeUserCustomer,
+		Currency:  util.USD,
+		Now:       now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, current.ID, schedule.ID)
+
+	schedule, err = testQueries.GetActiveFeeSchedule(context.Background(), GetActiveFeeScheduleParams{
+		Operation: operation,
+		UserType:  util.FeeUserCustomer,
+		Currency:  util.USD,
+		Now:       now.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, future.ID, schedule.ID)
+
+	_, err = testQueries.GetActiveFeeSchedule(context.Background(), GetActiveFeeScheduleParams{
+		Operation: operation,
+		UserType:  util.FeeUserMerchant,
+		Currency:  util.USD,
+		Now:       now,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleted, err := testQueries.DeleteFutureFeeSchedule(context.Background(), DeleteFutureFeeScheduleParams{ID: current.ID, Now: now})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	deleted, err = testQueries.DeleteFutureFeeSchedule(context.Background(), DeleteFutureFeeScheduleParams{ID: future.ID, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
+
+func TestTransferTxWithFees(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.EUR)
+	merchant := createRandomMerchantWalletIn(t, util.EUR)
+	revenue := createRandomWalletIn(t, util.EUR)
+
+	deposit, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID: from.ID,
+		Amount:   2000,
+		Account:  AccountBankSettlement,
+	})
+	require.NoError(t, err)
+	from = deposit.Wallet
+
+	effectiveFrom := time.Now()
+	transferFee := createRandomFeeSchedule(t, util.FeeTransfer, util.FeeUserCustomer, util.EUR,
+		util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}, effectiveFrom)
+	receiptFee := createRandomFeeSchedule(t, util.FeeMerchantReceipt, util.FeeUserMerchant, util.EUR,
+		util.FeeRule{Kind: util.FeePercentage, BasisPoints: 200, MaxFee: 1000}, effectiveFrom)
+
+	fees := &FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner}
+
+	quote, err := store.QuoteTransferFees(context.Background(), QuoteTransferFeesParams{
+		FromWalletID: from.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1000,
+		FeeParams:    *fees,
+	})
+	require.NoError(t, err)
+	require.Len(t, quote.Fees, 2)
+	require.Equal(t, int64(1010), quote.TotalDebit)
+	require.Equal(t, int64(980), quote.NetCredit)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: from.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1000,
+		Fees:         fees,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 2)
+
+	require.Equal(t, transferFee.ID, result.Fees[0].ScheduleID)
+	require.Equal(t, from.ID, result.Fees[0].WalletID)
+	require.Equal(t, int64(-10), result.Fees[0].Entry.Amount)
+	require.Equal(t, EntryFee, result.Fees[0].Entry.Kind)
+	require.Equal(t, result.Transfer.ID, result.Fees[0].Entry.TransferID.Int64)
+	require.Equal(t, revenue.ID, result.Fees[0].RevenueEntry.WalletID)
+
+	require.Equal(t, receiptFee.ID, result.Fees[1].ScheduleID)
+	require.Equal(t, merchant.ID, result.Fees[1].WalletID)
+	require.Equal(t, int64(20), result.Fees[1].Amount)
+
+	require.Equal(t, from.Balance-1010, result.FromWallet.Balance)
+	require.Equal(t, merchant.Balance+980, result.ToWallet.Balance)
+
+	updatedRevenue, err := store.GetWallet(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	require.Equal(t, revenue.Balance+30, updatedRevenue.Balance)
+
+	entries, err := store.ListTransferFeeEntries(context.Background(), sql.NullInt64{Int64: result.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+}
+
+func TestQuoteTransferFeesSelfDeclaredMerchant(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.EUR)
+	to := createRandomSelfDeclaredMerchantWalletIn(t, util.EUR)
+	revenue := createRandomWalletIn(t, util.EUR)
+
+	effectiveFrom := time.Now()
+	transferFee := createRandomFeeSchedule(t, util.FeeTransfer, util.FeeUserCustomer, util.EUR,
+		util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}, effectiveFrom)
+	createRandomFeeSchedule(t, util.FeeMerchantReceipt, util.FeeUserMerchant, util.EUR,
+		util.FeeRule{Kind: util.FeePercentage, BasisPoints: 200, MaxFee: 1000}, effectiveFrom)
+
+	// the flag alone isn't the merchant role, so no merchant receipt fee is charged
+	quote, err := store.QuoteTransferFees(context.Background(), QuoteTransferFeesParams{
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       1000,
+		FeeParams:    FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner},
+	})
+	require.NoError(t, err)
+	require.Len(t, quote.Fees, 1)
+	require.Equal(t, transferFee.ID, quote.Fees[0].ScheduleID)
+	require.Equal(t, int64(1000), quote.NetCredit)
+}
+
+func TestTransferTxFeesInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.EUR)
+	to := createRandomWalletIn(t, util.EUR)
+	revenue := createRandomWalletIn(t, util.EUR)
+
+	effectiveFrom := time.Now()
+	createRandomFeeSchedule(t, util.FeeTransfer, util.FeeUserCustomer, util.EUR,
+		util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}, effectiveFrom)
+
+	_, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       from.Balance,
+		Fees:         &FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedFrom, err := store.GetWallet(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, updatedFrom.Balance)
+}
+
+func TestTransferTxFeesDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	// the revenue wallet has the lowest id, so it is locked first
+	revenue := createRandomWalletIn(t, util.EUR)
+	from := createRandomWalletIn(t, util.EUR)
+	to := createRandomWalletIn(t, util.EUR)
+
+	for _, wallet := range []Wallet{revenue, from} {
+		_, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+			WalletID: wallet.ID,
+			Amount:   10000,
+			Account:  AccountBankSettlement,
+		})
+		require.NoError(t, err)
+	}
+
+	effectiveFrom := time.Now()
+	createRandomFeeSchedule(t, util.FeeTransfer, util.FeeUserCustomer, util.EUR,
+		util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}, effectiveFrom)
+	fees := &FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner}
+
+	n := 10
+	errs := make(chan error)
+
+	// transfers with fees lock the revenue wallet along with their own, while
+	// the revenue wallet pays the sender concurrently
+	for i := 0; i < n; i++ {
+		fromWalletID, toWalletID := from.ID, to.ID
+		if i%2 == 1 {
+			fromWalletID, toWalletID = revenue.ID, from.ID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TrasferTxParms{
+				FromWalletID: fromWalletID,
+				ToWalletID:   toWalletID,
+				Amount:       100,
+				Fees:         fees,
+			})
+
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+}
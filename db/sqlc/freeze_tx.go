@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+)
+
+type FreezeWalletTxParams struct {
+	WalletID int64 `json:"wallet_id"`
+	Frozen   bool  `json:"frozen"`
+}
+
+// FreezeWalletTx freezes or unfreezes a wallet, letting the owner know
+// through webhooks when the wallet gets frozen
+func (store *SQLStore) FreezeWalletTx(ctx context.Context, arg FreezeWalletTxParams) (Wallet, error) {
+	var wallet Wallet
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		wallet, err = q.UpdateWalletFrozen(ctx, UpdateWalletFrozenParams{
+			ID:       arg.WalletID,
+			IsFrozen: arg.Frozen,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !arg.Frozen {
+			return nil
+		}
+
+		return publishEvent(ctx, q, wallet.Owner, util.EventWalletFrozen, wallet)
+	})
+
+	return wallet, err
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, wallet, merchant Wallet, amount int64, expiresAt time.Time) Hold {
+	result, err := NewStore(testDB).CreateHoldTx(context.Background(), CreateHoldTxParams{
+		WalletID:         wallet.ID,
+		MerchantWalletID: merchant.ID,
+		Amount:           amount,
+		CreatedBy:        wallet.Owner,
+		ExpiresAt:        expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldAuthorized, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	require.Equal(t, wallet.HeldBalance+amount, result.Wallet.HeldBalance)
+
+	return result.Hold
+}
+
+// createFundedWallet creates a wallet holding exactly balance
+func createFundedWallet(t *testing.T, balance int64) Wallet {
+	wallet := createRandomWallet(t)
+
+	result, err := NewStore(testDB).AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   wallet.ID,
+		Amount:     balance,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	return result.Wallet
+}
+
+func TestCreateHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+
+	_, err := store.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		WalletID:         wallet.ID,
+		MerchantWalletID: merchant.ID,
+		Amount:           1001,
+		CreatedBy:        wallet.Owner,
+		ExpiresAt:        time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	createRandomHold(t, wallet, merchant, 800, time.Now().Add(time.Hour))
+
+	// the held amount can't be spent by a transfer
+	_, err = store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       201,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       200,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.FromWallet.AvailableBalance())
+}
+
+func TestTransferTxWithoutHolds(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+
+	// with nothing held the balance still has to cover the transfer
+	_, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1001,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1000,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.FromWallet.Balance)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+	hold := createRandomHold(t, wallet, merchant, 600, time.Now().Add(time.Hour))
+
+	_, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 601, Now: time.Now()})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 400, Now: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, result.Hold.Status)
+	require.Equal(t, int64(400), result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, int64(600), result.FromWallet.Balance)
+	require.Zero(t, result.FromWallet.HeldBalance)
+	require.Equal(t, merchant.Balance+400, result.ToWallet.Balance)
+
+	requireWalletLedgerBalance(t, result.FromWallet)
+	requireWalletLedgerBalance(t, result.ToWallet)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 100, Now: time.Now()})
+	require.ErrorIs(t, err, ErrHoldNotAuthorized)
+}
+
+func TestVoidHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+	hold := createRandomHold(t, wallet, merchant, 600, time.Now().Add(time.Hour))
+
+	voided, err := store.VoidHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, voided.Status)
+	require.True(t, voided.DecidedAt.Valid)
+
+	wallet, err = store.GetWallet(context.Background(), wallet.ID)
+	require.NoError(t, err)
+	require.Zero(t, wallet.HeldBalance)
+	require.Equal(t, int64(1000), wallet.Balance)
+
+	_, err = store.VoidHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotAuthorized)
+}
+
+func TestExpireHoldsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+	captured := createRandomHold(t, wallet, merchant, 100, time.Now().Add(-time.Minute))
+	expiring := createRandomHold(t, wallet, merchant, 200, time.Now().Add(-time.Minute))
+
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: captured.ID, Amount: 100, Now: time.Now()})
+	require.ErrorIs(t, err, ErrHoldExpired)
+	require.Equal(t, HoldExpired, result.Hold.Status)
+
+	for {
+		expired, err := store.ExpireHoldsTx(context.Background(), ExpireHoldsTxParams{Now: time.Now(), BatchSize: 10})
+		require.NoError(t, err)
+		if len(expired) < 10 {
+			break
+		}
+	}
+
+	expiring, err = store.GetHold(context.Background(), expiring.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldExpired, expiring.Status)
+
+	wallet, err = store.GetWallet(context.Background(), wallet.ID)
+	require.NoError(t, err)
+	require.Zero(t, wallet.HeldBalance)
+	require.Equal(t, int64(1000), wallet.Balance)
+}
+
+func TestExpireHoldsTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createFundedWallet(t, 10000)
+	wallet2 := createFundedWallet(t, 10000)
+	merchant := createRandomWallet(t)
+
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		// the hold of the wallet with the higher id expires first, so it is
+		// claimed before the other one
+		createRandomHold(t, wallet2, merchant, 10, time.Now().Add(-2*time.Minute))
+		createRandomHold(t, wallet1, merchant, 10, time.Now().Add(-time.Minute))
+
+		go func() {
+			_, err := store.ExpireHoldsTx(context.Background(), ExpireHoldsTxParams{Now: time.Now(), BatchSize: 100})
+			errs <- err
+		}()
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TrasferTxParms{
+				FromWalletID: wallet1.ID,
+				ToWalletID:   wallet2.ID,
+				Amount:       10,
+			})
+			errs <- err
+		}()
+
+		for j := 0; j < 2; j++ {
+			require.NoError(t, <-errs)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	HoldAuthorized = "authorized"
+	HoldCaptured   = "captured"
+	HoldVoided     = "voided"
+	HoldExpired    = "expired"
+)
+
+var (
+	// ErrHoldNotAuthorized is returned when capturing or voiding a hold that was already settled
+	ErrHoldNotAuthorized = errors.New("hold is no longer authorized")
+	// ErrHoldExpired is returned when capturing a hold past its expiry
+	ErrHoldExpired = errors.New("hold has expired")
+	// ErrCaptureExceedsHold is returned when capturing more than the held amount
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+)
+
+// AvailableBalance is the part of the balance that isn't held, waiting for
+// its receivables to settle nor withheld in the reserve
+func (wallet Wallet) AvailableBalance() int64 {
+	return wallet.Balance - wallet.HeldBalance - wallet.ReceivableBalance - wallet.ReserveBalance
+}
+
+type CreateHoldTxParams struct {
+	WalletID         int64     `json:"wallet_id"`
+	MerchantWalletID int64     `json:"merchant_wallet_id"`
+	Amount           int64     `json:"amount"`
+	CreatedBy        string    `json:"created_by"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type CreateHoldTxResult struct {
+	Hold   Hold   `json:"hold"`
+	Wallet Wallet `json:"wallet"`
+}
+
+// CreateHoldTx reserves the amount on the wallet for the merchant, the
+// available balance must cover it or ErrInsufficientFunds is returned. No
+// entries are written until the hold is captured
+func (store *SQLStore) CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error) {
+	var result CreateHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		wallet, err := lockWallets(ctx, q, arg.WalletID, arg.MerchantWalletID)
+		if err != nil {
+			return err
+		}
+
+		if wallet.AvailableBalance() < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			WalletID:         arg.WalletID,
+			MerchantWalletID: arg.MerchantWalletID,
+			Amount:           arg.Amount,
+			CreatedBy:        arg.CreatedBy,
+			ExpiresAt:        arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Wallet, err = q.AddWalletHeldBalance(ctx, AddWalletHeldBalanceParams{
+			Amount: arg.Amount,
+			ID:     arg.WalletID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CaptureHoldTxParams struct {
+	ID     int64     `json:"id"`
+	Amount int64     `json:"amount"`
+	Now    time.Time `json:"now"`
+	// Fees are charged on the capture transfer when set
+	Fees *FeeParams `json:"-"`
+}
+
+type CaptureHoldTxResult struct {
+	Hold Hold `json:"hold"`
+	TrasferTxResult
+}
+
+// CaptureHoldTx pays the merchant all or part of a hold and releases the
+// rest. An expired hold is released and marked as such, and ErrHoldExpired
+// is returned
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+	var failure error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldAuthorized {
+			return ErrHoldNotAuthorized
+		}
+
+		if !arg.Now.Before(hold.ExpiresAt) {
+			// commit the expiry so the amount is released
+			result.Hold, err = releaseHold(ctx, q, hold, HoldExpired)
+			failure = ErrHoldExpired
+			return err
+		}
+
+		if arg.Amount <= 0 || arg.Amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		if _, err = lockWallets(ctx, q, hold.WalletID, hold.MerchantWalletID); err != nil {
+			return err
+		}
+
+		// the whole hold is released, what isn't captured stays available
+		_, err = q.AddWalletHeldBalance(ctx, AddWalletHeldBalanceParams{
+			Amount: -hold.Amount,
+			ID:     hold.WalletID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the authorization was the customer's consent, so only fees apply
+		result.TrasferTxResult, _, err = transferTx(ctx, q, TrasferTxParms{
+			FromWalletID: hold.WalletID,
+			ToWalletID:   hold.MerchantWalletID,
+			Amount:       arg.Amount,
+			Fees:         arg.Fees,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.SettleHold(ctx, SettleHoldParams{
+			ID:             hold.ID,
+			Status:         HoldCaptured,
+			CapturedAmount: arg.Amount,
+			TransferID:     sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	if err == nil {
+		err = failure
+	}
+
+	return result, err
+}
+
+// VoidHoldTx releases the whole amount of an authorized hold
+func (store *SQLStore) VoidHoldTx(ctx context.Context, id int64) (Hold, error) {
+	var hold Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		hold, err = q.GetHoldForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldAuthorized {
+			return ErrHoldNotAuthorized
+		}
+
+		hold, err = releaseHold(ctx, q, hold, HoldVoided)
+		return err
+	})
+
+	return hold, err
+}
+
+type ExpireHoldsTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// ExpireHoldsTx releases a batch of authorized holds past their expiry
+func (store *SQLStore) ExpireHoldsTx(ctx context.Context, arg ExpireHoldsTxParams) ([]Hold, error) {
+	var expired []Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		holds, err := q.ClaimExpiredHolds(ctx, ClaimExpiredHoldsParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		// like captures, the holds are locked before their wallets, which are
+		// locked in id order rather than as each hold is released
+		walletIDs := make([]int64, 0, len(holds))
+		seen := make(map[int64]bool, len(holds))
+		for _, hold := range holds {
+			if !seen[hold.WalletID] {
+				seen[hold.WalletID] = true
+				walletIDs = append(walletIDs, hold.WalletID)
+			}
+		}
+
+		if err := lockWalletIDs(ctx, q, walletIDs); err != nil {
+			return err
+		}
+
+		expired = make([]Hold, 0, len(holds))
+		for _, hold := range holds {
+			hold, err = releaseHold(ctx, q, hold, HoldExpired)
+			if err != nil {
+				return err
+			}
+			expired = append(expired, hold)
+		}
+
+		return nil
+	})
+
+	return expired, err
+}
+
+// releaseHold gives the held amount back to the available balance of the
+// wallet and settles the hold with status
+func releaseHold(ctx context.Context, q *Queries, hold Hold, status string) (Hold, error) {
+	_, err := q.AddWalletHeldBalance(ctx, AddWalletHeldBalanceParams{
+		Amount: -hold.Amount,
+		ID:     hold.WalletID,
+	})
+	if err != nil {
+		return hold, err
+	}
+
+	return q.SettleHold(ctx, SettleHoldParams{
+		ID:     hold.ID,
+		Status: status,
+	})
+}
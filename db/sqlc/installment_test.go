@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectParams(now time.Time) CollectInstallmentTxParams {
+	return CollectInstallmentTxParams{
+		Now:           now,
+		MaxAttempts:   2,
+		RetryInterval: time.Hour,
+	}
+}
+
+func optInInstallmentFunding(t *testing.T, owner string) {
+	_, err := testQueries.UpsertInstallmentFundingOptIn(context.Background(), UpsertInstallmentFundingOptInParams{
+		Owner:     owner,
+		OptedInBy: owner,
+	})
+	require.NoError(t, err)
+}
+
+func TestInstallmentPlanLifecycle(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	purchasedAt := time.Date(1992, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	created, err := store.CreateInstallmentPlanTx(context.Background(), CreateInstallmentPlanTxParams{
+		CustomerWalletID: customer.ID,
+		MerchantWalletID: merchant.ID,
+		Amount:           900,
+		Count:            3,
+		CreatedBy:        customer.Owner,
+		Now:              purchasedAt,
+		Location:         time.UTC,
+	})
+	require.NoError(t, err)
+	require.Nil(t, created.Funding)
+	require.Equal(t, int64(900), created.Plan.TotalAmount)
+	require.Len(t, created.Installments, 3)
+	require.True(t, time.Date(1992, 2, 29, 12, 0, 0, 0, time.UTC).Equal(created.Installments[0].DueAt))
+	require.True(t, time.Date(1992, 3, 31, 12, 0, 0, 0, time.UTC).Equal(created.Installments[1].DueAt))
+
+	first, err := store.CollectInstallmentTx(context.Background(), collectParams(created.Installments[0].DueAt))
+	require.NoError(t, err)
+	require.Equal(t, created.Installments[0].ID, first.Installment.ID)
+	require.Equal(t, InstallmentPaid, first.Installment.Status)
+	require.NotNil(t, first.Transfer)
+	require.Equal(t, merchant.ID, first.Transfer.ToWallet.ID)
+	require.Equal(t, InstallmentPlanActive, first.Plan.Status)
+
+	_, err = store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   customer.ID,
+		Amount:     100,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	// the customer can't cover the second one, it is tried again later
+	now := created.Installments[1].DueAt
+	failed, err := store.CollectInstallmentTx(context.Background(), collectParams(now))
+	require.NoError(t, err)
+	require.Nil(t, failed.Transfer)
+	require.Equal(t, InstallmentPending, failed.Installment.Status)
+	require.True(t, failed.Installment.RetryAt.Valid)
+	require.Equal(t, InstallmentPlanDelinquent, failed.Plan.Status)
+
+	_, err = store.CollectInstallmentTx(context.Background(), collectParams(now))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	overdue, err := store.CollectInstallmentTx(context.Background(), collectParams(now.Add(2*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, InstallmentOverdue, overdue.Installment.Status)
+	require.Equal(t, int32(2), overdue.Installment.Attempts)
+
+	_, err = store.PrepayInstallmentPlanTx(context.Background(), PrepayInstallmentPlanTxParams{ID: created.Plan.ID, Now: now})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   customer.ID,
+		Amount:     1000,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	prepaid, err := store.PrepayInstallmentPlanTx(context.Background(), PrepayInstallmentPlanTxParams{ID: created.Plan.ID, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, int64(600), prepaid.Amount)
+	require.Len(t, prepaid.Installments, 2)
+	require.Equal(t, InstallmentPlanCompleted, prepaid.Plan.Status)
+	require.Equal(t, int64(400), prepaid.Transfer.FromWallet.Balance)
+
+	_, err = store.CancelInstallmentPlanTx(context.Background(), created.Plan.ID)
+	require.ErrorIs(t, err, ErrInstallmentPlanClosed)
+}
+
+func TestFundedInstallmentPlan(t *testing.T) {
+	store := NewStore(testDB)
+
+	funding := createFundedWallet(t, 100000)
+	customer := createRandomWalletIn(t, funding.Currency)
+	merchant := createRandomMerchantWalletIn(t, funding.Currency)
+	optInInstallmentFunding(t, merchant.Owner)
+
+	created, err := store.CreateInstallmentPlanTx(context.Background(), CreateInstallmentPlanTxParams{
+		CustomerWalletID: customer.ID,
+		MerchantWalletID: merchant.ID,
+		Amount:           100000,
+		Count:            3,
+		InterestRate:     200,
+		CreatedBy:        customer.Owner,
+		FundingOwner:     funding.Owner,
+		Now:              time.Now(),
+		Location:         time.UTC,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.Funding)
+	require.Equal(t, merchant.Balance+100000, created.Funding.ToWallet.Balance)
+	require.Equal(t, funding.ID, created.Plan.FundingWalletID.Int64)
+	require.Greater(t, created.Plan.TotalAmount, int64(100000))
+
+	cancelled, err := store.CancelInstallmentPlanTx(context.Background(), created.Plan.ID)
+	require.NoError(t, err)
+	require.Equal(t, InstallmentPlanCancelled, cancelled.Plan.Status)
+	require.Len(t, cancelled.Installments, 3)
+	require.NotNil(t, cancelled.Refund)
+	require.Equal(t, int64(100000), cancelled.Refund.Transfer.Amount)
+	require.Equal(t, funding.Balance, cancelled.Refund.ToWallet.Balance)
+
+	requireWalletLedgerBalance(t, cancelled.Refund.FromWallet)
+	requireWalletLedgerBalance(t, cancelled.Refund.ToWallet)
+}
+
+func TestFundedInstallmentPlanNotAllowed(t *testing.T) {
+	store := NewStore(testDB)
+
+	funding := createFundedWallet(t, 100000)
+	customer := createRandomWalletIn(t, funding.Currency)
+
+	// a second wallet of the customer, opted in like a merchant would
+	currency := util.USD
+	if customer.Currency == util.USD {
+		currency = util.EUR
+	}
+	ownWallet, err := testQueries.CreateWallet(context.Background(), CreateWalletParams{
+		Owner:    customer.Owner,
+		Currency: currency,
+	})
+	require.NoError(t, err)
+	optInInstallmentFunding(t, customer.Owner)
+
+	notOptedIn := createRandomMerchantWalletIn(t, funding.Currency)
+	selfDeclared := createRandomSelfDeclaredMerchantWalletIn(t, funding.Currency)
+	optInInstallmentFunding(t, selfDeclared.Owner)
+
+	for _, merchant := range []Wallet{ownWallet, notOptedIn, selfDeclared} {
+		_, err := store.CreateInstallmentPlanTx(context.Background(), CreateInstallmentPlanTxParams{
+			CustomerWalletID: customer.ID,
+			MerchantWalletID: merchant.ID,
+			Amount:           100000,
+			Count:            3,
+			InterestRate:     200,
+			CreatedBy:        customer.Owner,
+			FundingOwner:     funding.Owner,
+			Now:              time.Now(),
+			Location:         time.UTC,
+		})
+		require.ErrorIs(t, err, ErrInstallmentFundingNotAllowed)
+
+		updated, err := store.GetWallet(context.Background(), merchant.ID)
+		require.NoError(t, err)
+		require.Equal(t, merchant.Balance, updated.Balance)
+	}
+}
@@ -0,0 +1,477 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	InstallmentPlanActive     = "active"
+	InstallmentPlanDelinquent = "delinquent"
+	InstallmentPlanCompleted  = "completed"
+	InstallmentPlanCancelled  = "cancelled"
+)
+
+const (
+	InstallmentPending   = "pending"
+	InstallmentPaid      = "paid"
+	InstallmentOverdue   = "overdue"
+	InstallmentCancelled = "cancelled"
+)
+
+var (
+	// ErrFundingWalletNotFound is returned when funding a plan in a currency
+	// the platform funding owner has no wallet in
+	ErrFundingWalletNotFound = errors.New("installment funding wallet not found")
+	// ErrInstallmentPlanClosed is returned when acting on a plan that was
+	// completed or cancelled
+	ErrInstallmentPlanClosed = errors.New("installment plan is no longer open")
+	// ErrInstallmentFundingNotAllowed is returned when funding a plan paying a
+	// wallet whose owner isn't a merchant that opted in to upfront funding
+	ErrInstallmentFundingNotAllowed = errors.New("installment plans paying this wallet can't be funded upfront")
+)
+
+type CreateInstallmentPlanTxParams struct {
+	CustomerWalletID int64  `json:"customer_wallet_id"`
+	MerchantWalletID int64  `json:"merchant_wallet_id"`
+	Amount           int64  `json:"amount"`
+	Count            int32  `json:"count"`
+	InterestRate     int64  `json:"interest_rate"`
+	Description      string `json:"description"`
+	CreatedBy        string `json:"created_by"`
+	// FundingOwner pays the merchant the whole amount upfront and collects
+	// the installments in its place, the merchant collects them when empty
+	FundingOwner string    `json:"-"`
+	Now          time.Time `json:"-"`
+	// Location is where installments fall due on the day of the purchase
+	Location *time.Location `json:"-"`
+}
+
+type CreateInstallmentPlanTxResult struct {
+	Plan         InstallmentPlan `json:"plan"`
+	Installments []Installment   `json:"installments"`
+	// Funding is only set when the merchant was paid upfront
+	Funding *TrasferTxResult `json:"funding,omitempty"`
+}
+
+// CreateInstallmentPlanTx splits a purchase into monthly installments, the
+// first one due a month after Now. Nothing is taken from the customer until
+// the installments fall due. Only plans paying another user with the merchant
+// role, who opted in to funding, can be funded upfront
+func (store *SQLStore) CreateInstallmentPlanTx(ctx context.Context, arg CreateInstallmentPlanTxParams) (CreateInstallmentPlanTxResult, error) {
+	var result CreateInstallmentPlanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		plan := CreateInstallmentPlanParams{
+			CustomerWalletID: arg.CustomerWalletID,
+			MerchantWalletID: arg.MerchantWalletID,
+			Amount:           arg.Amount,
+			InterestRate:     arg.InterestRate,
+			InstallmentCount: arg.Count,
+			Description:      arg.Description,
+			CreatedBy:        arg.CreatedBy,
+		}
+
+		if arg.FundingOwner != "" {
+			merchant, err := q.GetWallet(ctx, arg.MerchantWalletID)
+			if err != nil {
+				return err
+			}
+
+			if err := checkInstallmentFunding(ctx, q, arg.CustomerWalletID, merchant); err != nil {
+				return err
+			}
+
+			funding, err := q.GetWalletByOwnerAndCurrency(ctx, GetWalletByOwnerAndCurrencyParams{
+				Owner:    arg.FundingOwner,
+				Currency: merchant.Currency,
+			})
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: %s", ErrFundingWalletNotFound, merchant.Currency)
+			}
+			if err != nil {
+				return err
+			}
+
+			transfer, err := checkedTransferTx(ctx, q, TrasferTxParms{
+				FromWalletID: funding.ID,
+				ToWalletID:   merchant.ID,
+				Amount:       arg.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Funding = &transfer
+			plan.FundingWalletID = sql.NullInt64{Int64: funding.ID, Valid: true}
+			plan.FundingTransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		}
+
+		amounts := util.InstallmentSchedule(arg.Amount, int(arg.Count), arg.InterestRate)
+		for _, amount := range amounts {
+			plan.TotalAmount += amount.Amount
+		}
+
+		var err error
+
+		result.Plan, err = q.CreateInstallmentPlan(ctx, plan)
+		if err != nil {
+			return err
+		}
+
+		dayOfMonth := arg.Now.In(arg.Location).Day()
+		dueAt := arg.Now
+
+		result.Installments = make([]Installment, len(amounts))
+		for i, amount := range amounts {
+			dueAt, _ = util.NextOccurrence(util.FrequencyMonthly, dayOfMonth, dueAt, arg.Location)
+
+			result.Installments[i], err = q.CreateInstallment(ctx, CreateInstallmentParams{
+				PlanID:    result.Plan.ID,
+				Number:    int32(i + 1),
+				Amount:    amount.Amount,
+				Principal: amount.Principal,
+				DueAt:     dueAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// checkInstallmentFunding makes sure the platform only pays upfront to
+// merchants that opted in, and never to the customer themselves, who could
+// take the money and skip the installments
+func checkInstallmentFunding(ctx context.Context, q *Queries, customerWalletID int64, merchant Wallet) error {
+	customer, err := q.GetWallet(ctx, customerWalletID)
+	if err != nil {
+		return err
+	}
+
+	if customer.Owner == merchant.Owner {
+		return ErrInstallmentFundingNotAllowed
+	}
+
+	owner, err := q.GetUser(ctx, merchant.Owner)
+	if err != nil {
+		return err
+	}
+
+	if !owner.HasMerchantRole() {
+		return ErrInstallmentFundingNotAllowed
+	}
+
+	_, err = q.GetInstallmentFundingOptIn(ctx, owner.Username)
+	if err == sql.ErrNoRows {
+		return ErrInstallmentFundingNotAllowed
+	}
+
+	return err
+}
+
+// installmentPayee returns the wallet the installments of the plan are paid to
+func installmentPayee(plan InstallmentPlan) int64 {
+	if plan.FundingWalletID.Valid {
+		return plan.FundingWalletID.Int64
+	}
+	return plan.MerchantWalletID
+}
+
+// openInstallmentPlan locks the open installments of a plan and then the
+// plan, in the order the collector locks them, and checks it is still open
+func openInstallmentPlan(ctx context.Context, q *Queries, id int64) (InstallmentPlan, []Installment, error) {
+	installments, err := q.LockOpenInstallments(ctx, id)
+	if err != nil {
+		return InstallmentPlan{}, nil, err
+	}
+
+	plan, err := q.GetInstallmentPlanForUpdate(ctx, id)
+	if err != nil {
+		return plan, nil, err
+	}
+
+	if plan.Status != InstallmentPlanActive && plan.Status != InstallmentPlanDelinquent {
+		return plan, nil, ErrInstallmentPlanClosed
+	}
+
+	return plan, installments, nil
+}
+
+// refreshInstallmentPlan completes the plan once nothing is left to pay,
+// and marks it delinquent while an installment is late
+func refreshInstallmentPlan(ctx context.Context, q *Queries, plan InstallmentPlan) (InstallmentPlan, error) {
+	count, err := q.CountLateInstallments(ctx, plan.ID)
+	if err != nil {
+		return plan, err
+	}
+
+	status := InstallmentPlanActive
+	switch {
+	case count.Open == 0:
+		status = InstallmentPlanCompleted
+	case count.Late > 0:
+		status = InstallmentPlanDelinquent
+	}
+
+	if status == plan.Status {
+		return plan, nil
+	}
+
+	return q.UpdateInstallmentPlanStatus(ctx, UpdateInstallmentPlanStatusParams{
+		ID:     plan.ID,
+		Status: status,
+	})
+}
+
+type CollectInstallmentTxParams struct {
+	Now time.Time
+	// MaxAttempts is how many times an installment is tried while funds are
+	// insufficient before it is overdue
+	MaxAttempts   int32
+	RetryInterval time.Duration
+}
+
+type CollectInstallmentTxResult struct {
+	Installment Installment     `json:"installment"`
+	Plan        InstallmentPlan `json:"plan"`
+	// Transfer is only set when the installment was paid
+	Transfer *TrasferTxResult `json:"transfer,omitempty"`
+}
+
+// CollectInstallmentTx claims one due installment and debits it from the
+// customer. Installments the customer can't cover are tried again until they
+// run out of attempts and are left overdue, the plan being delinquent
+// meanwhile. It returns sql.ErrNoRows when nothing is due
+func (store *SQLStore) CollectInstallmentTx(ctx context.Context, arg CollectInstallmentTxParams) (CollectInstallmentTxResult, error) {
+	var result CollectInstallmentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		installment, err := q.ClaimDueInstallment(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		plan, err := q.GetInstallmentPlanForUpdate(ctx, installment.PlanID)
+		if err != nil {
+			return err
+		}
+
+		transfer, err := checkedTransferTx(ctx, q, TrasferTxParms{
+			FromWalletID: plan.CustomerWalletID,
+			ToWalletID:   installmentPayee(plan),
+			Amount:       installment.Amount,
+		})
+
+		switch {
+		case err == nil:
+			result.Transfer = &transfer
+			result.Installment, err = q.PayInstallment(ctx, PayInstallmentParams{
+				ID:         installment.ID,
+				PaidAmount: installment.Amount,
+				TransferID: sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true},
+			})
+		case isTransferFailure(err):
+			result.Installment, err = recordInstallmentFailure(ctx, q, installment, err, arg)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Plan, err = refreshInstallmentPlan(ctx, q, plan)
+		if err != nil || result.Transfer != nil {
+			return err
+		}
+
+		return notifyInstallmentFailed(ctx, q, result.Plan, result.Installment)
+	})
+
+	return result, err
+}
+
+// recordInstallmentFailure records why the installment couldn't be paid, scheduling
+// it again when the customer may still cover it
+func recordInstallmentFailure(ctx context.Context, q *Queries, installment Installment, failure error, arg CollectInstallmentTxParams) (Installment, error) {
+	attempt := installment.Attempts + 1
+
+	fail := FailInstallmentParams{
+		ID:        installment.ID,
+		Status:    InstallmentOverdue,
+		Attempts:  attempt,
+		LastError: failure.Error(),
+	}
+
+	if errors.Is(failure, ErrInsufficientFunds) && attempt < arg.MaxAttempts {
+		fail.Status = InstallmentPending
+		fail.RetryAt = sql.NullTime{Time: arg.Now.Add(arg.RetryInterval), Valid: true}
+	}
+
+	return q.FailInstallment(ctx, fail)
+}
+
+// notifyInstallmentFailed tells the customer an installment failed, and the
+// payee too once it is overdue
+func notifyInstallmentFailed(ctx context.Context, q *Queries, plan InstallmentPlan, installment Installment) error {
+	data := struct {
+		Plan        InstallmentPlan `json:"plan"`
+		Installment Installment     `json:"installment"`
+	}{plan, installment}
+
+	customer, err := q.GetWallet(ctx, plan.CustomerWalletID)
+	if err != nil {
+		return err
+	}
+
+	if installment.Status == InstallmentPending {
+		message := fmt.Sprintf("Installment %d of %d could not be paid: %s. It will be tried again.", installment.Number, plan.InstallmentCount, installment.LastError)
+		return notify(ctx, q, customer.Owner, util.NotificationInstallmentFailed, message, data)
+	}
+
+	message := fmt.Sprintf("Installment %d of %d of plan %d is overdue: %s.", installment.Number, plan.InstallmentCount, plan.ID, installment.LastError)
+	if err := notify(ctx, q, customer.Owner, util.NotificationInstallmentOverdue, message, data); err != nil {
+		return err
+	}
+
+	payee, err := q.GetWallet(ctx, installmentPayee(plan))
+	if err != nil {
+		return err
+	}
+
+	return notify(ctx, q, payee.Owner, util.NotificationInstallmentOverdue, message, data)
+}
+
+type PrepayInstallmentPlanTxParams struct {
+	ID  int64     `json:"id"`
+	Now time.Time `json:"now"`
+}
+
+type PrepayInstallmentPlanTxResult struct {
+	Plan         InstallmentPlan `json:"plan"`
+	Installments []Installment   `json:"installments"`
+	// Amount is what the customer paid, less than the open installments
+	// when interest was waived
+	Amount   int64           `json:"amount"`
+	Transfer TrasferTxResult `json:"transfer"`
+}
+
+// prepaidAmount is what paying the installment at now costs. Installments
+// that aren't due yet are paid without their interest
+func prepaidAmount(installment Installment, now time.Time) int64 {
+	if installment.DueAt.After(now) {
+		return installment.Principal
+	}
+	return installment.Amount
+}
+
+// PrepayInstallmentPlanTx pays off every open installment of the plan in a
+// single transfer from the customer, completing the plan
+func (store *SQLStore) PrepayInstallmentPlanTx(ctx context.Context, arg PrepayInstallmentPlanTxParams) (PrepayInstallmentPlanTxResult, error) {
+	var result PrepayInstallmentPlanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		plan, installments, err := openInstallmentPlan(ctx, q, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, installment := range installments {
+			result.Amount += prepaidAmount(installment, arg.Now)
+		}
+
+		result.Transfer, err = checkedTransferTx(ctx, q, TrasferTxParms{
+			FromWalletID: plan.CustomerWalletID,
+			ToWalletID:   installmentPayee(plan),
+			Amount:       result.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Installments = make([]Installment, len(installments))
+		for i, installment := range installments {
+			result.Installments[i], err = q.PayInstallment(ctx, PayInstallmentParams{
+				ID:         installment.ID,
+				PaidAmount: prepaidAmount(installment, arg.Now),
+				TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Plan, err = refreshInstallmentPlan(ctx, q, plan)
+		return err
+	})
+
+	return result, err
+}
+
+type CancelInstallmentPlanTxResult struct {
+	Plan         InstallmentPlan `json:"plan"`
+	Installments []Installment   `json:"installments"`
+	// Refund is only set when the merchant was paid upfront and returned the
+	// principal left to collect
+	Refund *TrasferTxResult `json:"refund,omitempty"`
+}
+
+// CancelInstallmentPlanTx cancels the installments left on behalf of the
+// merchant. A merchant paid upfront returns the principal the funding wallet
+// won't collect anymore
+func (store *SQLStore) CancelInstallmentPlanTx(ctx context.Context, id int64) (CancelInstallmentPlanTxResult, error) {
+	var result CancelInstallmentPlanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		plan, installments, err := openInstallmentPlan(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		var principal int64
+		for _, installment := range installments {
+			principal += installment.Principal
+		}
+
+		if plan.FundingWalletID.Valid && principal > 0 {
+			refund, err := checkedTransferTx(ctx, q, TrasferTxParms{
+				FromWalletID: plan.MerchantWalletID,
+				ToWalletID:   plan.FundingWalletID.Int64,
+				Amount:       principal,
+			})
+			if err != nil {
+				return err
+			}
+			result.Refund = &refund
+		}
+
+		result.Installments, err = q.CancelOpenInstallments(ctx, plan.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Plan, err = q.UpdateInstallmentPlanStatus(ctx, UpdateInstallmentPlanStatusParams{
+			ID:     plan.ID,
+			Status: InstallmentPlanCancelled,
+		})
+		if err != nil {
+			return err
+		}
+
+		customer, err := q.GetWallet(ctx, plan.CustomerWalletID)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("The merchant cancelled installment plan %d, %d installments won't be charged.", plan.ID, len(result.Installments))
+		return notify(ctx, q, customer.Owner, util.NotificationInstallmentPlanCancelled, message, result)
+	})
+
+	return result, err
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomInvoice(t *testing.T, merchant Wallet, customer string, dueAt time.Time) InvoiceTxResult {
+	store := NewStore(testDB)
+
+	result, err := store.CreateInvoiceTx(context.Background(), CreateInvoiceTxParams{
+		Issuer:           merchant.Owner,
+		MerchantWalletID: merchant.ID,
+		Customer:         customer,
+		Currency:         merchant.Currency,
+		Memo:             "services",
+		DueAt:            dueAt,
+		Items: []InvoiceItemParams{
+			{Description: "hours", InvoiceLine: util.InvoiceLine{Quantity: 2, UnitAmount: 100, TaxName: "ISS", TaxRate: 500}},
+			{Description: "travel", InvoiceLine: util.InvoiceLine{Quantity: 1, UnitAmount: 110, Discount: 10}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, InvoiceDraft, result.Invoice.Status)
+	require.False(t, result.Invoice.Number.Valid)
+
+	return result
+}
+
+func TestCreateInvoiceTx(t *testing.T) {
+	customer := createRandomWallet(t)
+	merchant := createRandomWalletIn(t, customer.Currency)
+
+	result := createRandomInvoice(t, merchant, customer.Owner, time.Now().Add(time.Hour))
+
+	require.Equal(t, int64(310), result.Invoice.Subtotal)
+	require.Equal(t, int64(10), result.Invoice.Discount)
+	require.Equal(t, int64(10), result.Invoice.Tax)
+	require.Equal(t, int64(310), result.Invoice.Total)
+
+	require.Len(t, result.Items, 2)
+	require.Equal(t, int64(210), result.Items[0].Amount)
+	require.Equal(t, int64(100), result.Items[1].Amount)
+	require.Equal(t, []util.TaxLine{{Name: "ISS", Rate: 500, Base: 200, Amount: 10}}, result.Taxes)
+
+	store := NewStore(testDB)
+
+	updated, err := store.UpdateInvoiceTx(context.Background(), UpdateInvoiceTxParams{
+		ID:               result.Invoice.ID,
+		MerchantWalletID: merchant.ID,
+		Customer:         customer.Owner,
+		Currency:         merchant.Currency,
+		DueAt:            result.Invoice.DueAt,
+		Items: []InvoiceItemParams{
+			{Description: "hours", InvoiceLine: util.InvoiceLine{Quantity: 3, UnitAmount: 100}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(300), updated.Invoice.Total)
+	require.Len(t, updated.Items, 1)
+
+	items, err := store.ListInvoiceItems(context.Background(), result.Invoice.ID)
+	require.NoError(t, err)
+	require.Equal(t, updated.Items, items)
+}
+
+func TestFinalizeInvoiceTxNumbersInSequence(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createRandomWallet(t)
+	merchant := createRandomWalletIn(t, customer.Currency)
+
+	n := 5
+	errs := make(chan error)
+	numbers := make(chan int64, n)
+
+	for i := 0; i < n; i++ {
+		draft := createRandomInvoice(t, merchant, customer.Owner, time.Now().Add(time.Hour))
+
+		go func() {
+			result, err := store.FinalizeInvoiceTx(context.Background(), FinalizeInvoiceTxParams{
+				ID:  draft.Invoice.ID,
+				Now: time.Now(),
+			})
+			numbers <- result.Invoice.Number.Int64
+			errs <- err
+		}()
+	}
+
+	got := make([]int64, 0, n)
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		got = append(got, <-numbers)
+	}
+
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, got)
+
+	// a new issuer starts its own sequence
+	other := createRandomWalletIn(t, customer.Currency)
+	draft := createRandomInvoice(t, other, customer.Owner, time.Now().Add(time.Hour))
+
+	result, err := store.FinalizeInvoiceTx(context.Background(), FinalizeInvoiceTxParams{ID: draft.Invoice.ID, Now: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Invoice.Number.Int64)
+	require.Equal(t, InvoiceOpen, result.Invoice.Status)
+	require.Len(t, result.Taxes, 1)
+
+	_, err = store.FinalizeInvoiceTx(context.Background(), FinalizeInvoiceTxParams{ID: draft.Invoice.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrInvoiceNotDraft)
+}
+
+func TestPayInvoiceTxOnce(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	draft := createRandomInvoice(t, merchant, customer.Owner, time.Now().Add(time.Hour))
+
+	_, err := store.PayInvoiceTx(context.Background(), PayInvoiceTxParams{
+		ID:           draft.Invoice.ID,
+		FromWalletID: customer.ID,
+		Now:          time.Now(),
+	})
+	require.ErrorIs(t, err, ErrInvoiceNotOpen)
+
+	_, err = store.FinalizeInvoiceTx(context.Background(), FinalizeInvoiceTxParams{ID: draft.Invoice.ID, Now: time.Now()})
+	require.NoError(t, err)
+
+	n := 3
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.PayInvoiceTx(context.Background(), PayInvoiceTxParams{
+				ID:           draft.Invoice.ID,
+				FromWalletID: customer.ID,
+				Now:          time.Now(),
+			})
+			errs <- err
+		}()
+	}
+
+	paid := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			paid++
+			continue
+		}
+		require.ErrorIs(t, err, ErrInvoiceNotOpen)
+	}
+	require.Equal(t, 1, paid)
+
+	invoice, err := store.GetInvoice(context.Background(), draft.Invoice.ID)
+	require.NoError(t, err)
+	require.Equal(t, InvoicePaid, invoice.Status)
+	require.True(t, invoice.PaidAt.Valid)
+
+	transfer, err := store.GetTransfer(context.Background(), invoice.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, invoice.ID, transfer.InvoiceID.Int64)
+	require.Equal(t, invoice.Total, transfer.Amount)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1000-invoice.Total, updatedCustomer.Balance)
+
+	_, err = store.VoidInvoiceTx(context.Background(), VoidInvoiceTxParams{ID: invoice.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrInvoiceClosed)
+}
+
+func TestRemindOverdueInvoicesTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	// dates long past keep the invoices of other tests out of the reminders
+	dueAt := time.Date(1992, 3, 10, 12, 0, 0, 0, time.UTC)
+	now := dueAt.Add(time.Hour)
+
+	customer := createRandomWallet(t)
+	merchant := createRandomWalletIn(t, customer.Currency)
+
+	draft := createRandomInvoice(t, merchant, customer.Owner, dueAt)
+	_, err := store.FinalizeInvoiceTx(context.Background(), FinalizeInvoiceTxParams{ID: draft.Invoice.ID, Now: dueAt.Add(-time.Hour)})
+	require.NoError(t, err)
+
+	voided := createRandomInvoice(t, merchant, customer.Owner, dueAt)
+	_, err = store.VoidInvoiceTx(context.Background(), VoidInvoiceTxParams{ID: voided.Invoice.ID, Now: dueAt})
+	require.NoError(t, err)
+
+	remind := func(now time.Time) []int64 {
+		invoices, err := store.RemindOverdueInvoicesTx(context.Background(), RemindOverdueInvoicesTxParams{
+			Now:       now,
+			Interval:  72 * time.Hour,
+			BatchSize: 1000,
+		})
+		require.NoError(t, err)
+
+		ids := make([]int64, 0, len(invoices))
+		for _, invoice := range invoices {
+			ids = append(ids, invoice.ID)
+		}
+		return ids
+	}
+
+	require.Contains(t, remind(now), draft.Invoice.ID)
+	require.NotContains(t, remind(now.Add(time.Hour)), draft.Invoice.ID)
+
+	reminded := remind(now.Add(73 * time.Hour))
+	require.Contains(t, reminded, draft.Invoice.ID)
+	require.NotContains(t, reminded, voided.Invoice.ID)
+
+	invoice, err := store.GetInvoice(context.Background(), draft.Invoice.ID)
+	require.NoError(t, err)
+	require.Equal(t, InvoiceOverdue, invoice.Status)
+	require.Equal(t, int32(2), invoice.ReminderCount)
+}
+
+func TestRemindOverdueInvoicesTxInterval(t *testing.T) {
+	_, err := NewStore(testDB).RemindOverdueInvoicesTx(context.Background(), RemindOverdueInvoicesTxParams{
+		Now:       time.Now(),
+		BatchSize: 1000,
+	})
+	require.ErrorIs(t, err, ErrInvalidReminderInterval)
+}
@@ -0,0 +1,519 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	InvoiceDraft   = "draft"
+	InvoiceOpen    = "open"
+	InvoicePaid    = "paid"
+	InvoiceVoid    = "void"
+	InvoiceOverdue = "overdue"
+)
+
+var (
+	// ErrInvoiceNotDraft is returned when editing or finalizing an invoice
+	// that was already finalized
+	ErrInvoiceNotDraft = errors.New("invoice has already been finalized")
+	// ErrInvoiceNotOpen is returned when paying a draft, paid or void invoice
+	ErrInvoiceNotOpen = errors.New("invoice is not open for payment")
+	// ErrInvoiceClosed is returned when voiding a paid or void invoice
+	ErrInvoiceClosed = errors.New("invoice is already paid or void")
+	// ErrInvoicePaymentUnderReview is returned when paying or voiding an
+	// invoice whose payment is held for risk review
+	ErrInvoicePaymentUnderReview = errors.New("invoice payment is under review")
+	// ErrInvalidReminderInterval is returned when reminding overdue invoices
+	// with an interval that isn't positive, which would remind them forever
+	ErrInvalidReminderInterval = errors.New("reminder interval must be positive")
+)
+
+// InvoiceItemParams is an item of the invoice along with what it's for
+type InvoiceItemParams struct {
+	Description string `json:"description"`
+	util.InvoiceLine
+}
+
+type InvoiceTxResult struct {
+	Invoice Invoice        `json:"invoice"`
+	Items   []InvoiceItem  `json:"items"`
+	Taxes   []util.TaxLine `json:"taxes"`
+}
+
+type CreateInvoiceTxParams struct {
+	Issuer           string              `json:"issuer"`
+	MerchantWalletID int64               `json:"merchant_wallet_id"`
+	Customer         string              `json:"customer"`
+	Currency         string              `json:"currency"`
+	Memo             string              `json:"memo"`
+	DueAt            time.Time           `json:"due_at"`
+	Items            []InvoiceItemParams `json:"items"`
+}
+
+// CreateInvoiceTx drafts an invoice with its items. Drafts can be edited and
+// are only numbered and shown to the customer once finalized
+func (store *SQLStore) CreateInvoiceTx(ctx context.Context, arg CreateInvoiceTxParams) (InvoiceTxResult, error) {
+	var result InvoiceTxResult
+
+	totals, err := util.ComputeInvoice(invoiceLines(arg.Items))
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Invoice, err = q.CreateInvoice(ctx, CreateInvoiceParams{
+			Issuer:           arg.Issuer,
+			MerchantWalletID: arg.MerchantWalletID,
+			Customer:         arg.Customer,
+			Currency:         arg.Currency,
+			Memo:             arg.Memo,
+			DueAt:            arg.DueAt,
+			Subtotal:         totals.Subtotal,
+			Discount:         totals.Discount,
+			Tax:              totals.Tax,
+			Total:            totals.Total,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Items, err = createInvoiceItems(ctx, q, result.Invoice.ID, arg.Items, totals)
+		return err
+	})
+
+	result.Taxes = totals.Taxes
+
+	return result, err
+}
+
+type UpdateInvoiceTxParams struct {
+	ID               int64               `json:"id"`
+	MerchantWalletID int64               `json:"merchant_wallet_id"`
+	Customer         string              `json:"customer"`
+	Currency         string              `json:"currency"`
+	Memo             string              `json:"memo"`
+	DueAt            time.Time           `json:"due_at"`
+	Items            []InvoiceItemParams `json:"items"`
+}
+
+// UpdateInvoiceTx replaces the contents of a draft invoice
+func (store *SQLStore) UpdateInvoiceTx(ctx context.Context, arg UpdateInvoiceTxParams) (InvoiceTxResult, error) {
+	var result InvoiceTxResult
+
+	totals, err := util.ComputeInvoice(invoiceLines(arg.Items))
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		invoice, err := q.GetInvoiceForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if invoice.Status != InvoiceDraft {
+			return ErrInvoiceNotDraft
+		}
+
+		result.Invoice, err = q.UpdateInvoice(ctx, UpdateInvoiceParams{
+			ID:               invoice.ID,
+			MerchantWalletID: arg.MerchantWalletID,
+			Customer:         arg.Customer,
+			Currency:         arg.Currency,
+			Memo:             arg.Memo,
+			DueAt:            arg.DueAt,
+			Subtotal:         totals.Subtotal,
+			Discount:         totals.Discount,
+			Tax:              totals.Tax,
+			Total:            totals.Total,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteInvoiceItems(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Items, err = createInvoiceItems(ctx, q, invoice.ID, arg.Items, totals)
+		return err
+	})
+
+	result.Taxes = totals.Taxes
+
+	return result, err
+}
+
+type FinalizeInvoiceTxParams struct {
+	ID  int64     `json:"id"`
+	Now time.Time `json:"now"`
+}
+
+// FinalizeInvoiceTx numbers a draft invoice and sends it to the customer.
+// Numbers come from a counter per issuer that stays locked until the
+// transaction commits, so invoices finalized concurrently get consecutive
+// numbers and a rolled back finalization leaves no gap
+func (store *SQLStore) FinalizeInvoiceTx(ctx context.Context, arg FinalizeInvoiceTxParams) (InvoiceTxResult, error) {
+	var result InvoiceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		invoice, err := q.GetInvoiceForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if invoice.Status != InvoiceDraft {
+			return ErrInvoiceNotDraft
+		}
+
+		number, err := q.NextInvoiceNumber(ctx, invoice.Issuer)
+		if err != nil {
+			return err
+		}
+
+		result.Invoice, err = q.FinalizeInvoice(ctx, FinalizeInvoiceParams{
+			ID:          invoice.ID,
+			Number:      sql.NullInt64{Int64: number, Valid: true},
+			FinalizedAt: sql.NullTime{Time: arg.Now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Items, err = q.ListInvoiceItems(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("%s sent you invoice %s of %s %s, due %s.",
+			invoice.Issuer, invoiceNumber(result.Invoice), util.FormatAmount(invoice.Total), invoice.Currency, invoice.DueAt.Format("2006-01-02"))
+
+		return notify(ctx, q, invoice.Customer, util.NotificationInvoiceIssued, message, result.Invoice)
+	})
+
+	result.Taxes = InvoiceTaxes(result.Items)
+
+	return result, err
+}
+
+type VoidInvoiceTxParams struct {
+	ID  int64     `json:"id"`
+	Now time.Time `json:"now"`
+}
+
+// VoidInvoiceTx cancels an invoice that was not paid. The customer is told
+// when the invoice had been sent to them
+func (store *SQLStore) VoidInvoiceTx(ctx context.Context, arg VoidInvoiceTxParams) (Invoice, error) {
+	var invoice Invoice
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		invoice, err = q.GetInvoiceForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case invoice.Status == InvoicePaid || invoice.Status == InvoiceVoid:
+			return ErrInvoiceClosed
+		case invoice.ReviewID.Valid:
+			return ErrInvoicePaymentUnderReview
+		}
+
+		finalized := invoice.Status != InvoiceDraft
+
+		invoice, err = q.VoidInvoice(ctx, VoidInvoiceParams{
+			ID:       invoice.ID,
+			VoidedAt: sql.NullTime{Time: arg.Now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if !finalized {
+			return nil
+		}
+
+		message := fmt.Sprintf("Invoice %s from %s was voided, there is nothing to pay.", invoiceNumber(invoice), invoice.Issuer)
+		return notify(ctx, q, invoice.Customer, util.NotificationInvoiceVoided, message, invoice)
+	})
+
+	return invoice, err
+}
+
+type PayInvoiceTxParams struct {
+	ID           int64     `json:"id"`
+	FromWalletID int64     `json:"from_wallet_id"`
+	Now          time.Time `json:"now"`
+	// Limits, Risk, Fees and Cashback are applied to the transfer like in TransferTx
+	Limits   *TransferLimitsParams `json:"-"`
+	Risk     RiskEvaluator         `json:"-"`
+	Fees     *FeeParams            `json:"-"`
+	Cashback *CashbackParams       `json:"-"`
+}
+
+type PayInvoiceTxResult struct {
+	Invoice Invoice `json:"invoice"`
+	TrasferTxResult
+}
+
+// PayInvoiceTx pays the total of an open or overdue invoice into the merchant
+// wallet, linking the transfer to the invoice. The invoice is locked while it
+// is paid, so it is paid at most once. When the transfer is held for review,
+// the invoice stays open until the review is decided
+func (store *SQLStore) PayInvoiceTx(ctx context.Context, arg PayInvoiceTxParams) (PayInvoiceTxResult, error) {
+	var result PayInvoiceTxResult
+	var failure error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		invoice, err := q.GetInvoiceForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case invoice.Status != InvoiceOpen && invoice.Status != InvoiceOverdue:
+			return ErrInvoiceNotOpen
+		case invoice.ReviewID.Valid:
+			return ErrInvoicePaymentUnderReview
+		}
+
+		var blocked bool
+
+		result.TrasferTxResult, blocked, err = transferTx(ctx, q, TrasferTxParms{
+			FromWalletID: arg.FromWalletID,
+			ToWalletID:   invoice.MerchantWalletID,
+			Amount:       invoice.Total,
+			Limits:       arg.Limits,
+			Risk:         arg.Risk,
+			Fees:         arg.Fees,
+			Cashback:     arg.Cashback,
+		})
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			failure = ErrTransferBlocked
+			return nil
+		}
+
+		pay := PayInvoiceParams{
+			ID:           invoice.ID,
+			Status:       invoice.Status,
+			FromWalletID: sql.NullInt64{Int64: arg.FromWalletID, Valid: true},
+		}
+
+		if result.Review != nil {
+			pay.ReviewID = sql.NullInt64{Int64: result.Review.ID, Valid: true}
+		} else {
+			pay.Status = InvoicePaid
+			pay.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+			pay.PaidAt = sql.NullTime{Time: arg.Now, Valid: true}
+
+			result.Transfer, err = q.SetTransferInvoice(ctx, SetTransferInvoiceParams{
+				ID:        result.Transfer.ID,
+				InvoiceID: sql.NullInt64{Int64: invoice.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Invoice, err = q.PayInvoice(ctx, pay)
+		if err != nil {
+			return err
+		}
+
+		return notifyInvoicePayment(ctx, q, result.Invoice)
+	})
+
+	if err == nil {
+		err = failure
+	}
+
+	return result, err
+}
+
+// settleInvoiceReview marks the invoice paid by a reviewed transfer as paid,
+// or lets it be paid again when the review was rejected
+func settleInvoiceReview(ctx context.Context, q *Queries, review TransferReview, transfer *Transfer) error {
+	invoice, err := q.GetInvoiceByReview(ctx, sql.NullInt64{Int64: review.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	arg := PayInvoiceParams{
+		ID:     invoice.ID,
+		Status: invoice.Status,
+	}
+
+	if transfer != nil {
+		arg.Status = InvoicePaid
+		arg.FromWalletID = invoice.FromWalletID
+		arg.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+		arg.PaidAt = sql.NullTime{Time: transfer.CreatedAt, Valid: true}
+
+		*transfer, err = q.SetTransferInvoice(ctx, SetTransferInvoiceParams{
+			ID:        transfer.ID,
+			InvoiceID: sql.NullInt64{Int64: invoice.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	invoice, err = q.PayInvoice(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	return notifyInvoicePayment(ctx, q, invoice)
+}
+
+// notifyInvoicePayment tells the issuer the invoice was paid, or the customer
+// that their payment was rejected by risk review
+func notifyInvoicePayment(ctx context.Context, q *Queries, invoice Invoice) error {
+	switch {
+	case invoice.Status == InvoicePaid:
+		message := fmt.Sprintf("Invoice %s was paid by %s.", invoiceNumber(invoice), invoice.Customer)
+		return notify(ctx, q, invoice.Issuer, util.NotificationInvoicePaid, message, invoice)
+	case invoice.ReviewID.Valid:
+		// paid once the review is approved
+		return nil
+	default:
+		message := fmt.Sprintf("The payment of invoice %s was rejected by risk review.", invoiceNumber(invoice))
+		return notify(ctx, q, invoice.Customer, util.NotificationInvoicePaymentRejected, message, invoice)
+	}
+}
+
+type RemindOverdueInvoicesTxParams struct {
+	Now time.Time `json:"now"`
+	// Interval is the time between reminders of the same invoice
+	Interval  time.Duration `json:"interval"`
+	BatchSize int32         `json:"batch_size"`
+}
+
+// RemindOverdueInvoicesTx marks a batch of unpaid invoices past their due
+// date as overdue and reminds their customers, at most once per interval
+func (store *SQLStore) RemindOverdueInvoicesTx(ctx context.Context, arg RemindOverdueInvoicesTxParams) ([]Invoice, error) {
+	var invoices []Invoice
+
+	if arg.Interval <= 0 {
+		return nil, ErrInvalidReminderInterval
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		invoices, err = q.RemindOverdueInvoices(ctx, RemindOverdueInvoicesParams{
+			Now:          arg.Now,
+			RemindBefore: arg.Now.Add(-arg.Interval),
+			BatchSize:    arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, invoice := range invoices {
+			message := fmt.Sprintf("Invoice %s from %s of %s %s was due %s and is still unpaid.",
+				invoiceNumber(invoice), invoice.Issuer, util.FormatAmount(invoice.Total), invoice.Currency, invoice.DueAt.Format("2006-01-02"))
+
+			err = notify(ctx, q, invoice.Customer, util.NotificationInvoiceOverdue, message, invoice)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return invoices, err
+}
+
+// InvoiceTaxes returns the tax breakdown of the items, one line per tax name
+// and rate in the order they first appear
+func InvoiceTaxes(items []InvoiceItem) []util.TaxLine {
+	var taxes []util.TaxLine
+
+	for _, item := range items {
+		if item.TaxRate == 0 && item.TaxName == "" {
+			continue
+		}
+
+		found := false
+		for i := range taxes {
+			if taxes[i].Name == item.TaxName && taxes[i].Rate == item.TaxRate {
+				taxes[i].Base += item.Amount - item.Tax
+				taxes[i].Amount += item.Tax
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			taxes = append(taxes, util.TaxLine{
+				Name:   item.TaxName,
+				Rate:   item.TaxRate,
+				Base:   item.Amount - item.Tax,
+				Amount: item.Tax,
+			})
+		}
+	}
+
+	return taxes
+}
+
+func createInvoiceItems(ctx context.Context, q *Queries, invoiceID int64, items []InvoiceItemParams, totals util.InvoiceTotals) ([]InvoiceItem, error) {
+	created := make([]InvoiceItem, len(items))
+
+	for i, item := range items {
+		var err error
+
+		created[i], err = q.CreateInvoiceItem(ctx, CreateInvoiceItemParams{
+			InvoiceID:   invoiceID,
+			Position:    int32(i + 1),
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+			Discount:    item.Discount,
+			TaxName:     item.TaxName,
+			TaxRate:     item.TaxRate,
+			Tax:         totals.Lines[i].Tax,
+			Amount:      totals.Lines[i].Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return created, nil
+}
+
+func invoiceLines(items []InvoiceItemParams) []util.InvoiceLine {
+	lines := make([]util.InvoiceLine, len(items))
+	for i, item := range items {
+		lines[i] = item.InvoiceLine
+	}
+	return lines
+}
+
+// invoiceNumber is how the invoice is referred to in notifications
+func invoiceNumber(invoice Invoice) string {
+	if !invoice.Number.Valid {
+		return fmt.Sprintf("draft %d", invoice.ID)
+	}
+	return util.FormatInvoiceNumber(invoice.Number.Int64)
+}
@@ -0,0 +1,329 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Types of ledger accounts
+const (
+	AccountAsset     = "asset"
+	AccountLiability = "liability"
+	AccountEquity    = "equity"
+	AccountRevenue   = "revenue"
+	AccountExpense   = "expense"
+)
+
+// System accounts every currency has, besides the accounts of the wallets.
+// Fees are credited to the platform revenue wallet, so fee_revenue and
+// fx_position only move through manual adjustments for now
+const (
+	AccountBankSettlement = "bank_settlement"
+	AccountFeeRevenue     = "fee_revenue"
+	AccountFXPosition     = "fx_position"
+	AccountSuspense       = "suspense"
+	AccountEscrow         = "escrow"
+)
+
+// Kinds of journals
+const (
+	JournalOpening       = "opening"
+	JournalTransfer      = "transfer"
+	JournalFee           = "fee"
+	JournalReviewHold    = "review_hold"
+	JournalReviewReturn  = "review_return"
+	JournalAdjustment    = "adjustment"
+	JournalEscrowHold    = "escrow_hold"
+	JournalEscrowRelease = "escrow_release"
+	JournalEscrowRefund  = "escrow_refund"
+	JournalAnticipation  = "anticipation"
+)
+
+// ErrUnbalancedJournal is returned when the postings of a journal don't add up to zero
+var ErrUnbalancedJournal = errors.New("journal postings must add up to zero")
+
+// LedgerPosting is a debit, when positive, or a credit to an account
+type LedgerPosting struct {
+	AccountID int64
+	Amount    int64
+}
+
+// postJournal records a movement as a journal of balanced postings
+func postJournal(ctx context.Context, q *Queries, arg CreateJournalParams, postings ...LedgerPosting) (Journal, error) {
+	var total int64
+	for _, posting := range postings {
+		total += posting.Amount
+	}
+
+	if len(postings) < 2 || total != 0 {
+		return Journal{}, ErrUnbalancedJournal
+	}
+
+	journal, err := q.CreateJournal(ctx, arg)
+	if err != nil {
+		return journal, err
+	}
+
+	for _, posting := range postings {
+		_, err = q.CreatePosting(ctx, CreatePostingParams{
+			JournalID: journal.ID,
+			AccountID: posting.AccountID,
+			Amount:    posting.Amount,
+		})
+		if err != nil {
+			return journal, err
+		}
+	}
+
+	return journal, nil
+}
+
+// walletAccountID returns the liability account of the wallet
+func walletAccountID(ctx context.Context, q *Queries, walletID int64) (int64, error) {
+	account, err := q.GetWalletLedgerAccount(ctx, sql.NullInt64{Int64: walletID, Valid: true})
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("wallet [%d] has no ledger account", walletID)
+	}
+
+	return account.ID, err
+}
+
+// systemAccountID returns the system account of the currency
+func systemAccountID(ctx context.Context, q *Queries, code string, currency string) (int64, error) {
+	account, err := q.GetSystemLedgerAccount(ctx, GetSystemLedgerAccountParams{
+		Code:     code,
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no %s account in %s", code, currency)
+	}
+
+	return account.ID, err
+}
+
+// postWalletTransfer journals money moving from one wallet to another.
+// Wallets are liabilities, so the sender is debited and the receiver credited
+func postWalletTransfer(ctx context.Context, q *Queries, kind string, transferID int64, fromWalletID, toWalletID, amount int64) (Journal, error) {
+	fromAccount, err := walletAccountID(ctx, q, fromWalletID)
+	if err != nil {
+		return Journal{}, err
+	}
+
+	toAccount, err := walletAccountID(ctx, q, toWalletID)
+	if err != nil {
+		return Journal{}, err
+	}
+
+	return postJournal(ctx, q, CreateJournalParams{
+		Kind:       kind,
+		TransferID: sql.NullInt64{Int64: transferID, Valid: true},
+	},
+		LedgerPosting{AccountID: fromAccount, Amount: amount},
+		LedgerPosting{AccountID: toAccount, Amount: -amount},
+	)
+}
+
+// postSystemMovement journals money moving between a wallet and a system
+// account. A positive amount credits the wallet
+func postSystemMovement(ctx context.Context, q *Queries, arg CreateJournalParams, wallet Wallet, code string, amount int64) (Journal, error) {
+	walletAccount, err := walletAccountID(ctx, q, wallet.ID)
+	if err != nil {
+		return Journal{}, err
+	}
+
+	systemAccount, err := systemAccountID(ctx, q, code, wallet.Currency)
+	if err != nil {
+		return Journal{}, err
+	}
+
+	return postJournal(ctx, q, arg,
+		LedgerPosting{AccountID: systemAccount, Amount: amount},
+		LedgerPosting{AccountID: walletAccount, Amount: -amount},
+	)
+}
+
+type CreateWalletTxParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	// Balance is deposited from the bank settlement account when set
+	Balance int64 `json:"balance"`
+}
+
+// CreateWalletTx creates a wallet with its ledger account. Wallets start
+// empty, an opening balance is journaled as a deposit
+func (store *SQLStore) CreateWalletTx(ctx context.Context, arg CreateWalletTxParams) (Wallet, error) {
+	var wallet Wallet
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		wallet, err = q.CreateWallet(ctx, CreateWalletParams{
+			Owner:    arg.Owner,
+			Currency: arg.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateLedgerAccount(ctx, CreateLedgerAccountParams{
+			Code:     fmt.Sprintf("wallet:%d", wallet.ID),
+			Name:     fmt.Sprintf("Wallet %d", wallet.ID),
+			Type:     AccountLiability,
+			Currency: wallet.Currency,
+			WalletID: sql.NullInt64{Int64: wallet.ID, Valid: true},
+		})
+		if err != nil || arg.Balance == 0 {
+			return err
+		}
+
+		result, err := adjustWallet(ctx, q, wallet, arg.Balance, AccountBankSettlement, "opening balance")
+		wallet = result.Wallet
+		return err
+	})
+
+	return wallet, err
+}
+
+type AdjustWalletTxParams struct {
+	WalletID int64 `json:"wallet_id"`
+	// Amount is added to the balance, or replaces it when SetBalance is true
+	Amount     int64 `json:"amount"`
+	SetBalance bool  `json:"set_balance"`
+	// Account is the system account on the other side of the adjustment
+	Account     string `json:"account"`
+	Description string `json:"description"`
+}
+
+type AdjustWalletTxResult struct {
+	Wallet Wallet `json:"wallet"`
+	// Entry and Journal aren't set when the balance didn't change
+	Entry   *Entry   `json:"entry,omitempty"`
+	Journal *Journal `json:"journal,omitempty"`
+}
+
+// AdjustWalletTx changes a wallet balance against a system account, so the
+// money comes from somewhere the ledger knows about
+func (store *SQLStore) AdjustWalletTx(ctx context.Context, arg AdjustWalletTxParams) (AdjustWalletTxResult, error) {
+	var result AdjustWalletTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		wallet, err := q.GetWalletForUpdate(ctx, arg.WalletID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if arg.SetBalance {
+			amount -= wallet.Balance
+		}
+
+		result, err = adjustWallet(ctx, q, wallet, amount, arg.Account, arg.Description)
+		return err
+	})
+
+	return result, err
+}
+
+func adjustWallet(ctx context.Context, q *Queries, wallet Wallet, amount int64, account string, description string) (AdjustWalletTxResult, error) {
+	result := AdjustWalletTxResult{Wallet: wallet}
+
+	if amount == 0 {
+		return result, nil
+	}
+
+	journal, err := postSystemMovement(ctx, q, CreateJournalParams{
+		Kind:        JournalAdjustment,
+		Description: description,
+	}, wallet, account, amount)
+	if err != nil {
+		return result, err
+	}
+
+	entry, err := writeEntry(ctx, q, CreateEntryParams{
+		WalletID: wallet.ID,
+		Amount:   amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Wallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+		Amount: amount,
+		ID:     wallet.ID,
+	})
+	result.Entry = &entry
+	result.Journal = &journal
+
+	return result, err
+}
+
+// TrialBalanceCurrency adds up the accounts of a currency
+type TrialBalanceCurrency struct {
+	Currency string               `json:"currency"`
+	Accounts []GetTrialBalanceRow `json:"accounts"`
+	Debits   int64                `json:"debits"`
+	Credits  int64                `json:"credits"`
+}
+
+type TrialBalanceResult struct {
+	Currencies []TrialBalanceCurrency `json:"currencies"`
+	// UnbalancedJournals and WalletMismatches must be zero in a sound ledger
+	UnbalancedJournals int64 `json:"unbalanced_journals"`
+	WalletMismatches   int64 `json:"wallet_mismatches"`
+	Balanced           bool  `json:"balanced"`
+}
+
+// TrialBalance adds up the debits and credits of every account, with the
+// wallets grouped in one line per currency. It reads a single snapshot, so
+// movements made meanwhile can't unbalance the report
+func (store *SQLStore) TrialBalance(ctx context.Context) (TrialBalanceResult, error) {
+	result := TrialBalanceResult{Currencies: []TrialBalanceCurrency{}}
+
+	transaction, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return result, err
+	}
+	defer transaction.Rollback()
+
+	q := New(transaction)
+
+	rows, err := q.GetTrialBalance(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	result.UnbalancedJournals, err = q.CountUnbalancedJournals(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	result.WalletMismatches, err = q.CountWalletLedgerMismatches(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	result.Balanced = result.UnbalancedJournals == 0 && result.WalletMismatches == 0
+
+	for _, row := range rows {
+		last := len(result.Currencies) - 1
+		if last < 0 || result.Currencies[last].Currency != row.Currency {
+			result.Currencies = append(result.Currencies, TrialBalanceCurrency{Currency: row.Currency})
+			last++
+		}
+
+		currency := &result.Currencies[last]
+		currency.Accounts = append(currency.Accounts, row)
+		currency.Debits += row.Debits
+		currency.Credits += row.Credits
+	}
+
+	for _, currency := range result.Currencies {
+		if currency.Debits != currency.Credits {
+			result.Balanced = false
+		}
+	}
+
+	return result, transaction.Commit()
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireWalletLedgerBalance(t *testing.T, wallet Wallet) {
+	account, err := testQueries.GetWalletLedgerAccount(context.Background(), sql.NullInt64{Int64: wallet.ID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, AccountLiability, account.Type)
+
+	balance, err := testQueries.GetLedgerAccountBalance(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, -wallet.Balance, balance)
+}
+
+func TestCreateWalletTx(t *testing.T) {
+	wallet := createRandomWallet(t)
+	requireWalletLedgerBalance(t, wallet)
+}
+
+func TestTransferTxPostsJournal(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWallet(t)
+	to := createRandomWalletIn(t, from.Currency)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       10,
+	})
+	require.NoError(t, err)
+
+	requireWalletLedgerBalance(t, result.FromWallet)
+	requireWalletLedgerBalance(t, result.ToWallet)
+}
+
+func TestAdjustWalletTx(t *testing.T) {
+	store := NewStore(testDB)
+	wallet := createRandomWallet(t)
+
+	result, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:    wallet.ID,
+		Amount:      wallet.Balance + 500,
+		SetBalance:  true,
+		Account:     AccountSuspense,
+		Description: "correction",
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.Balance+500, result.Wallet.Balance)
+	require.Equal(t, int64(500), result.Entry.Amount)
+	require.Equal(t, JournalAdjustment, result.Journal.Kind)
+
+	postings, err := store.ListJournalPostings(context.Background(), result.Journal.ID)
+	require.NoError(t, err)
+	require.Len(t, postings, 2)
+	require.Zero(t, postings[0].Amount+postings[1].Amount)
+
+	requireWalletLedgerBalance(t, result.Wallet)
+
+	result, err = store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   wallet.ID,
+		Amount:     result.Wallet.Balance,
+		SetBalance: true,
+		Account:    AccountSuspense,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Journal)
+}
+
+func TestTrialBalance(t *testing.T) {
+	store := NewStore(testDB)
+	createRandomWallet(t)
+
+	report, err := store.TrialBalance(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Currencies)
+	require.Zero(t, report.UnbalancedJournals)
+
+	for _, currency := range report.Currencies {
+		require.Equal(t, currency.Debits, currency.Credits)
+	}
+}
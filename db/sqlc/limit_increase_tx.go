@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	LimitIncreasePending   = "pending"
+	LimitIncreaseApplied   = "applied"
+	LimitIncreaseRejected  = "rejected"
+	LimitIncreaseCancelled = "cancelled"
+)
+
+type ApplyLimitIncreasesTxParams struct {
+	Defaults  util.TransferLimits `json:"defaults"`
+	BatchSize int32               `json:"batch_size"`
+	// DecidedBy is recorded on the requests that get applied
+	DecidedBy string `json:"decided_by"`
+}
+
+// ApplyLimitIncreasesTx applies the increase requests whose cooling off
+// period is over. Requests being applied by another instance are skipped
+func (store *SQLStore) ApplyLimitIncreasesTx(ctx context.Context, arg ApplyLimitIncreasesTxParams) ([]LimitIncreaseRequest, error) {
+	var applied []LimitIncreaseRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		requests, err := q.ClaimDueLimitIncreaseRequests(ctx, arg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		applied = make([]LimitIncreaseRequest, 0, len(requests))
+		for _, request := range requests {
+			_, err := setTransferLimit(ctx, q, request.Owner, request.LimitName, request.RequestedValue, arg.Defaults, arg.DecidedBy)
+			if err != nil {
+				return err
+			}
+
+			request, err = q.DecideLimitIncreaseRequest(ctx, DecideLimitIncreaseRequestParams{
+				ID:        request.ID,
+				Status:    LimitIncreaseApplied,
+				DecidedBy: arg.DecidedBy,
+			})
+			if err != nil {
+				return err
+			}
+
+			applied = append(applied, request)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+type RequestLimitChangeTxParams struct {
+	Owner      string              `json:"owner"`
+	LimitName  string              `json:"limit_name"`
+	Value      int64               `json:"value"`
+	Defaults   util.TransferLimits `json:"defaults"`
+	CoolingOff time.Duration       `json:"cooling_off"`
+	Now        time.Time           `json:"now"`
+}
+
+// RequestLimitChangeTx records a change of one of the user limits. Increases
+// only take effect after the cooling off period, so a stolen account can't
+// raise its limits and drain the wallet right away. Decreases apply at once
+func (store *SQLStore) RequestLimitChangeTx(ctx context.Context, arg RequestLimitChangeTxParams) (LimitIncreaseRequest, error) {
+	var result LimitIncreaseRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		limits, err := userTransferLimits(ctx, q, arg.Owner, arg.Defaults)
+		if err != nil {
+			return err
+		}
+
+		effectiveAt := arg.Now
+		if limits.IsIncrease(arg.LimitName, arg.Value) {
+			effectiveAt = arg.Now.Add(arg.CoolingOff)
+		}
+
+		result, err = q.CreateLimitIncreaseRequest(ctx, CreateLimitIncreaseRequestParams{
+			Owner:          arg.Owner,
+			LimitName:      arg.LimitName,
+			RequestedValue: arg.Value,
+			Status:         LimitIncreasePending,
+			EffectiveAt:    effectiveAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if effectiveAt.After(arg.Now) {
+			return nil
+		}
+
+		_, err = setTransferLimit(ctx, q, arg.Owner, arg.LimitName, arg.Value, arg.Defaults, arg.Owner)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.DecideLimitIncreaseRequest(ctx, DecideLimitIncreaseRequestParams{
+			ID:        result.ID,
+			Status:    LimitIncreaseApplied,
+			DecidedBy: arg.Owner,
+		})
+
+		return err
+	})
+
+	return result, err
+}
+
+func setTransferLimit(
+	ctx context.Context,
+	q *Queries,
+	owner string,
+	name string,
+	value int64,
+	defaults util.TransferLimits,
+	updatedBy string,
+) (TransferLimit, error) {
+	limits, err := userTransferLimits(ctx, q, owner, defaults)
+	if err != nil {
+		return TransferLimit{}, err
+	}
+
+	limits.Set(name, value)
+
+	return q.UpsertTransferLimits(ctx, UpsertTransferLimitsParams{
+		Owner:            owner,
+		PerTransfer:      limits.PerTransfer,
+		Daily:            limits.Daily,
+		Monthly:          limits.Monthly,
+		DailyCount:       limits.DailyCount,
+		NightPerTransfer: limits.NightPerTransfer,
+		Nightly:          limits.Nightly,
+		UpdatedBy:        updatedBy,
+	})
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"picpay_simplificado/util"
+	"sort"
+	"time"
+)
+
+// LimitExceededError is returned when a transfer would go over one of the
+// limits of the sender
+type LimitExceededError struct {
+	Limit string
+	Max   int64
+}
+
+func (err *LimitExceededError) Error() string {
+	return fmt.Sprintf("transfer exceeds the %s limit of %d", err.Limit, err.Max)
+}
+
+// TransferLimitsParams turns on limit checks in TransferTx. Defaults apply to
+// users that have no override of their own
+type TransferLimitsParams struct {
+	Defaults util.TransferLimits
+	Schedule util.LimitSchedule
+	Now      time.Time
+}
+
+// NewTransferLimits builds the limits stored as an override
+func NewTransferLimits(override TransferLimit) util.TransferLimits {
+	return util.TransferLimits{
+		PerTransfer:      override.PerTransfer,
+		Daily:            override.Daily,
+		Monthly:          override.Monthly,
+		DailyCount:       override.DailyCount,
+		NightPerTransfer: override.NightPerTransfer,
+		Nightly:          override.Nightly,
+	}
+}
+
+// userTransferLimits returns the override of the user or the defaults
+func userTransferLimits(ctx context.Context, q *Queries, owner string, defaults util.TransferLimits) (util.TransferLimits, error) {
+	override, err := q.GetTransferLimits(ctx, owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return defaults, nil
+		}
+		return defaults, err
+	}
+
+	return NewTransferLimits(override), nil
+}
+
+// lockWallets locks both wallets of a transfer in id order, the same order
+// their balances are updated in, so concurrent transfers can't deadlock
+func lockWallets(ctx context.Context, q *Queries, walletID1 int64, walletID2 int64) (Wallet, error) {
+	firstID, secondID := walletID1, walletID2
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := q.GetWalletForUpdate(ctx, firstID)
+	if err != nil {
+		return first, err
+	}
+
+	second, err := q.GetWalletForUpdate(ctx, secondID)
+	if err != nil {
+		return second, err
+	}
+
+	if first.ID == walletID1 {
+		return first, nil
+	}
+	return second, nil
+}
+
+// lockWalletIDs locks many wallets in id order. Batches that claim rows of
+// several wallets lock them first, so they take locks in the same order as
+// transfers and anticipations, wallets before their rows
+func lockWalletIDs(ctx context.Context, q *Queries, ids []int64) error {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if _, err := q.GetWalletForUpdate(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkTransferLimits must run with the sender wallet locked, so the
+// aggregates can't change until the transfer is committed. Money counts once,
+// when it leaves the wallet: reviews, holds and escrows count when they are
+// opened rather than through their transfers, and refunds and dispute
+// reversals don't count since they give back money the wallet received
+func checkTransferLimits(ctx context.Context, q *Queries, from Wallet, amount int64, arg TransferLimitsParams) error {
+	limits, err := userTransferLimits(ctx, q, from.Owner, arg.Defaults)
+	if err != nil {
+		return err
+	}
+
+	night := arg.Schedule.IsNight(arg.Now)
+
+	if exceeds(amount, limits.PerTransfer) {
+		return &LimitExceededError{Limit: util.LimitPerTransfer, Max: limits.PerTransfer}
+	}
+
+	if night && exceeds(amount, limits.NightPerTransfer) {
+		return &LimitExceededError{Limit: util.LimitNightPerTransfer, Max: limits.NightPerTransfer}
+	}
+
+	if limits.Daily > 0 || limits.DailyCount > 0 {
+		today, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			WalletID: from.ID,
+			Since:    arg.Schedule.DayStart(arg.Now),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exceeds(today.TransferCount+1, limits.DailyCount) {
+			return &LimitExceededError{Limit: util.LimitDailyCount, Max: limits.DailyCount}
+		}
+
+		if exceeds(today.TotalAmount+amount, limits.Daily) {
+			return &LimitExceededError{Limit: util.LimitDaily, Max: limits.Daily}
+		}
+	}
+
+	if limits.Monthly > 0 {
+		month, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			WalletID: from.ID,
+			Since:    arg.Schedule.MonthStart(arg.Now),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exceeds(month.TotalAmount+amount, limits.Monthly) {
+			return &LimitExceededError{Limit: util.LimitMonthly, Max: limits.Monthly}
+		}
+	}
+
+	if night && limits.Nightly > 0 {
+		tonight, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			WalletID: from.ID,
+			Since:    arg.Schedule.NightStartOf(arg.Now),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exceeds(tonight.TotalAmount+amount, limits.Nightly) {
+			return &LimitExceededError{Limit: util.LimitNightly, Max: limits.Nightly}
+		}
+	}
+
+	return nil
+}
+
+func exceeds(value int64, limit int64) bool {
+	return limit > 0 && value > limit
+}
@@ -0,0 +1,345 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func limitsParams(defaults util.TransferLimits) *TransferLimitsParams {
+	return &TransferLimitsParams{
+		Defaults: defaults,
+		Schedule: util.LimitSchedule{Location: time.UTC, NightStart: 20, NightEnd: 6},
+		Now:      time.Now(),
+	}
+}
+
+func requireLimitExceeded(t *testing.T, err error, limit string) {
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr), "expected limit error, got %v", err)
+	require.Equal(t, limit, limitErr.Limit)
+}
+
+func TestTransferTxPerTransferLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	_, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       101,
+		Limits:       limitsParams(util.TransferLimits{PerTransfer: 100}),
+	})
+	requireLimitExceeded(t, err, util.LimitPerTransfer)
+
+	_, err = store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       100,
+		Limits:       limitsParams(util.TransferLimits{PerTransfer: 100}),
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxUserOverride(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	_, err := testQueries.UpsertTransferLimits(context.Background(), UpsertTransferLimitsParams{
+		Owner:       wallet1.Owner,
+		PerTransfer: 1000,
+		DailyCount:  1,
+		UpdatedBy:   util.RandomString(6),
+	})
+	require.NoError(t, err)
+
+	arg := TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       500,
+		Limits:       limitsParams(util.TransferLimits{PerTransfer: 100}),
+	}
+
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), arg)
+	requireLimitExceeded(t, err, util.LimitDailyCount)
+}
+
+func TestTransferTxConcurrentDailyLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	n := 10
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromWalletID, toWalletID := wallet1.ID, wallet2.ID
+		// transfers in the other direction check that locking can't deadlock
+		if i%2 == 1 {
+			fromWalletID, toWalletID = wallet2.ID, wallet1.ID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TrasferTxParms{
+				FromWalletID: fromWalletID,
+				ToWalletID:   toWalletID,
+				Amount:       10,
+				Limits:       limitsParams(util.TransferLimits{Daily: 30}),
+			})
+
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		requireLimitExceeded(t, err, util.LimitDaily)
+	}
+
+	// each wallet can only send 3 of its 5 transfers
+	require.Equal(t, 6, succeeded)
+}
+
+func TestTransferTxReviewReservesLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	for _, approve := range []bool{true, false} {
+		wallet1 := createFundedWallet(t, 1000)
+		wallet2 := createRandomWallet(t)
+
+		arg := TrasferTxParms{
+			FromWalletID: wallet1.ID,
+			ToWalletID:   wallet2.ID,
+			Amount:       80,
+			Limits:       limitsParams(util.TransferLimits{Daily: 100}),
+			Risk:         stubEvaluator{outcome: RiskReview},
+		}
+
+		held, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.NotNil(t, held.Review)
+
+		// the held amount already counts, so the limit can't be spent twice
+		arg.Amount = 30
+		arg.Risk = nil
+		_, err = store.TransferTx(context.Background(), arg)
+		requireLimitExceeded(t, err, util.LimitDaily)
+
+		_, err = store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+			ReviewID: held.Review.ID,
+			Approve:  approve,
+		})
+		require.NoError(t, err)
+
+		// an approved review keeps counting once, a rejected one frees the limit
+		_, err = store.TransferTx(context.Background(), arg)
+		if approve {
+			requireLimitExceeded(t, err, util.LimitDaily)
+
+			arg.Amount = 20
+			_, err = store.TransferTx(context.Background(), arg)
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestGetOutgoingTransferTotals(t *testing.T) {
+	store := NewStore(testDB)
+
+	since := time.Now().Add(-time.Second)
+	wallet := createFundedWallet(t, 10000)
+	merchant := createRandomWalletIn(t, wallet.Currency)
+
+	pay := func(amount int64, risk RiskEvaluator) TrasferTxResult {
+		result, err := store.TransferTx(context.Background(), TrasferTxParms{
+			FromWalletID: wallet.ID,
+			ToWalletID:   merchant.ID,
+			Amount:       amount,
+			Risk:         risk,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// counted: a transfer, a review that was approved and one still pending
+	pay(100, nil)
+	approved := pay(50, stubEvaluator{outcome: RiskReview})
+	_, err := store.DecideReviewTx(context.Background(), DecideReviewTxParams{ReviewID: approved.Review.ID, Approve: true})
+	require.NoError(t, err)
+	pay(60, stubEvaluator{outcome: RiskReview})
+
+	// not counted: a rejected review
+	rejected := pay(70, stubEvaluator{outcome: RiskReview})
+	_, err = store.DecideReviewTx(context.Background(), DecideReviewTxParams{ReviewID: rejected.Review.ID})
+	require.NoError(t, err)
+
+	// counted: what was captured of a hold
+	captured := createRandomHold(t, wallet, merchant, 30, time.Now().Add(time.Hour))
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: captured.ID, Amount: 20, Now: time.Now()})
+	require.NoError(t, err)
+
+	// not counted: a voided hold
+	voided := createRandomHold(t, wallet, merchant, 80, time.Now().Add(time.Hour))
+	_, err = store.VoidHoldTx(context.Background(), voided.ID)
+	require.NoError(t, err)
+
+	// counted: an escrow released to the seller
+	wallet, err = store.GetWallet(context.Background(), wallet.ID)
+	require.NoError(t, err)
+	released := createRandomEscrow(t, wallet, merchant, time.Now().Add(time.Hour)).Escrow
+	_, err = store.ConfirmEscrowTx(context.Background(), released.ID)
+	require.NoError(t, err)
+
+	// not counted: a refunded escrow
+	wallet, err = store.GetWallet(context.Background(), wallet.ID)
+	require.NoError(t, err)
+	refunded := createRandomEscrow(t, wallet, merchant, time.Now().Add(time.Hour)).Escrow
+	for _, buyer := range []bool{true, false} {
+		_, err = store.AgreeEscrowRefundTx(context.Background(), AgreeEscrowRefundTxParams{ID: refunded.ID, Buyer: buyer})
+		require.NoError(t, err)
+	}
+
+	// counted: a hold still authorized
+	createRandomHold(t, wallet, merchant, 15, time.Now().Add(time.Hour))
+
+	totals, err := store.GetOutgoingTransferTotals(context.Background(), GetOutgoingTransferTotalsParams{
+		WalletID: wallet.ID,
+		Since:    since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100+50+60+20+15+300), totals.TotalAmount)
+	require.Equal(t, int64(6), totals.TransferCount)
+}
+
+func TestGetOutgoingTransferTotalsGiveBacks(t *testing.T) {
+	store := NewStore(testDB)
+
+	since := time.Now().Add(-time.Second)
+	dispute, payer, merchant := createDisputedTransfer(t)
+
+	_, err := store.RespondDisputeTx(context.Background(), RespondDisputeTxParams{ID: dispute.ID, Now: time.Now()})
+	require.NoError(t, err)
+
+	_, err = store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{ID: dispute.ID, PayerWins: true})
+	require.NoError(t, err)
+
+	paid, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       100,
+	})
+	require.NoError(t, err)
+
+	_, err = store.RefundTx(context.Background(), RefundTxParams{TransferID: paid.Transfer.ID, Amount: 50, CreatedBy: "admin"})
+	require.NoError(t, err)
+
+	// neither the dispute reversal nor the refund count against the merchant
+	totals, err := store.GetOutgoingTransferTotals(context.Background(), GetOutgoingTransferTotalsParams{
+		WalletID: merchant.ID,
+		Since:    since,
+	})
+	require.NoError(t, err)
+	require.Zero(t, totals.TotalAmount)
+	require.Zero(t, totals.TransferCount)
+}
+
+func TestRequestLimitChangeTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	defaults := util.TransferLimits{PerTransfer: 1000, Daily: 5000}
+	now := time.Now()
+
+	decrease, err := store.RequestLimitChangeTx(context.Background(), RequestLimitChangeTxParams{
+		Owner:      user.Username,
+		LimitName:  util.LimitDaily,
+		Value:      2000,
+		Defaults:   defaults,
+		CoolingOff: 24 * time.Hour,
+		Now:        now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, LimitIncreaseApplied, decrease.Status)
+
+	limits, err := testQueries.GetTransferLimits(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(2000), limits.Daily)
+	require.Equal(t, defaults.PerTransfer, limits.PerTransfer)
+
+	increase, err := store.RequestLimitChangeTx(context.Background(), RequestLimitChangeTxParams{
+		Owner:      user.Username,
+		LimitName:  util.LimitPerTransfer,
+		Value:      3000,
+		Defaults:   defaults,
+		CoolingOff: 24 * time.Hour,
+		Now:        now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, LimitIncreasePending, increase.Status)
+	require.WithinDuration(t, now.Add(24*time.Hour), increase.EffectiveAt, time.Second)
+
+	limits, err = testQueries.GetTransferLimits(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, defaults.PerTransfer, limits.PerTransfer)
+}
+
+func TestApplyLimitIncreasesTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	defaults := util.TransferLimits{PerTransfer: 1000}
+
+	due, err := testQueries.CreateLimitIncreaseRequest(context.Background(), CreateLimitIncreaseRequestParams{
+		Owner:          user.Username,
+		LimitName:      util.LimitPerTransfer,
+		RequestedValue: 4000,
+		Status:         LimitIncreasePending,
+		EffectiveAt:    time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	notDue, err := testQueries.CreateLimitIncreaseRequest(context.Background(), CreateLimitIncreaseRequestParams{
+		Owner:          user.Username,
+		LimitName:      util.LimitDaily,
+		RequestedValue: 9000,
+		Status:         LimitIncreasePending,
+		EffectiveAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.ApplyLimitIncreasesTx(context.Background(), ApplyLimitIncreasesTxParams{
+		Defaults:  defaults,
+		BatchSize: 1000,
+		DecidedBy: "system",
+	})
+	require.NoError(t, err)
+
+	due, err = testQueries.GetLimitIncreaseRequest(context.Background(), due.ID)
+	require.NoError(t, err)
+	require.Equal(t, LimitIncreaseApplied, due.Status)
+	require.True(t, due.DecidedAt.Valid)
+
+	notDue, err = testQueries.GetLimitIncreaseRequest(context.Background(), notDue.ID)
+	require.NoError(t, err)
+	require.Equal(t, LimitIncreasePending, notDue.Status)
+
+	limits, err := testQueries.GetTransferLimits(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(4000), limits.PerTransfer)
+	require.Zero(t, limits.Daily)
+}
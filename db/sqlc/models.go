@@ -6,15 +6,643 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type ApiKey struct {
+	ID     int64  `json:"id"`
+	Owner  string `json:"owner"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	// hex encoded sha256 of the key, also used as the HMAC signing secret
+	HashedKey  string       `json:"hashed_key"`
+	Scopes     []string     `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+type ApiKeyNonce struct {
+	ApiKeyID  int64     `json:"api_key_id"`
+	Nonce     string    `json:"nonce"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CashbackCampaign struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+	// basis points of the payment given back
+	Rate int64 `json:"rate"`
+	// cap of the cashback of one payment, zero meaning no cap
+	MaxCashback int64 `json:"max_cashback"`
+	MinAmount   int64 `json:"min_amount"`
+	// category of the merchants paid, empty for any merchant
+	MerchantCategory string    `json:"merchant_category"`
+	StartsAt         time.Time `json:"starts_at"`
+	EndsAt           time.Time `json:"ends_at"`
+	Budget           int64     `json:"budget"`
+	// sum of the cashback granted and not clawed back, never above the budget
+	Spent int64 `json:"spent"`
+	// cashback one user can get from the campaign, zero meaning no limit
+	PerUserLimit int64 `json:"per_user_limit"`
+	// days before the cashback is paid, zero paying it with the payment
+	SettleDays int32     `json:"settle_days"`
+	Active     bool      `json:"active"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CashbackGrant struct {
+	ID         int64 `json:"id"`
+	CampaignID int64 `json:"campaign_id"`
+	// payment the cashback was granted for
+	TransferID int64  `json:"transfer_id"`
+	WalletID   int64  `json:"wallet_id"`
+	Owner      string `json:"owner"`
+	Amount     int64  `json:"amount"`
+	// part of the amount taken back after refunds of the payment
+	ClawedBack int64 `json:"clawed_back"`
+	// pending, paid or clawed_back
+	Status   string    `json:"status"`
+	SettleAt time.Time `json:"settle_at"`
+	// marketing wallet the cashback was paid from, and clawed back to
+	FundingWalletID  sql.NullInt64 `json:"funding_wallet_id"`
+	CreditTransferID sql.NullInt64 `json:"credit_transfer_id"`
+	LastError        string        `json:"last_error"`
+	PaidAt           sql.NullTime  `json:"paid_at"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+type ChainCheckpoint struct {
+	ID int64 `json:"id"`
+	// entries after this id, up to to_entry_id, are covered
+	FromEntryID int64  `json:"from_entry_id"`
+	ToEntryID   int64  `json:"to_entry_id"`
+	EntryCount  int64  `json:"entry_count"`
+	PrevDigest  string `json:"prev_digest"`
+	// sha256 over prev_digest and the hashes of the covered entries
+	Digest    string `json:"digest"`
+	PublicKey string `json:"public_key"`
+	// ed25519 signature of the digest
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CheckoutSession struct {
+	ID               int64           `json:"id"`
+	Token            string          `json:"token"`
+	MerchantWalletID int64           `json:"merchant_wallet_id"`
+	PaymentLinkID    sql.NullInt64   `json:"payment_link_id"`
+	Amount           int64           `json:"amount"`
+	Currency         string          `json:"currency"`
+	Description      string          `json:"description"`
+	SuccessUrl       string          `json:"success_url"`
+	CancelUrl        string          `json:"cancel_url"`
+	Metadata         json.RawMessage `json:"metadata"`
+	// open, processing while held for risk review, paid or expired
+	Status       string         `json:"status"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+	PaidBy       sql.NullString `json:"paid_by"`
+	FromWalletID sql.NullInt64  `json:"from_wallet_id"`
+	TransferID   sql.NullInt64  `json:"transfer_id"`
+	// set while the payment is held for risk review
+	ReviewID  sql.NullInt64 `json:"review_id"`
+	PaidAt    sql.NullTime  `json:"paid_at"`
+	CreatedBy string        `json:"created_by"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Dispute struct {
+	ID         int64 `json:"id"`
+	TransferID int64 `json:"transfer_id"`
+	// owner of the wallet that paid the transfer
+	OpenedBy string `json:"opened_by"`
+	// owner of the wallet that received the transfer
+	Merchant string `json:"merchant"`
+	Amount   int64  `json:"amount"`
+	Reason   string `json:"reason"`
+	// open, under_review, payer_won or merchant_won
+	Status string `json:"status"`
+	// open disputes go to review without a response from then on
+	RespondBy        time.Time      `json:"respond_by"`
+	MerchantResponse string         `json:"merchant_response"`
+	RespondedAt      sql.NullTime   `json:"responded_at"`
+	DecidedBy        sql.NullString `json:"decided_by"`
+	DecisionNote     string         `json:"decision_note"`
+	DecidedAt        sql.NullTime   `json:"decided_at"`
+	// transfer giving the amount back to the payer, set when the payer won
+	ReversalTransferID sql.NullInt64 `json:"reversal_transfer_id"`
+	CreatedAt          time.Time     `json:"created_at"`
+}
+
+type DisputeEvidence struct {
+	ID          int64  `json:"id"`
+	DisputeID   int64  `json:"dispute_id"`
+	UploadedBy  string `json:"uploaded_by"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// where the file is kept in the blob store
+	BlobKey   string    `json:"blob_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Entry struct {
 	ID       int64 `json:"id"`
 	WalletID int64 `json:"wallet_id"`
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	Kind      string    `json:"kind"`
+	// transfer a fee entry was charged for
+	TransferID    sql.NullInt64 `json:"transfer_id"`
+	FeeScheduleID sql.NullInt64 `json:"fee_schedule_id"`
+	// hash of the previous entry of the wallet, empty for the first one
+	PrevHash string `json:"prev_hash"`
+	// sha256 over the entry and prev_hash
+	Hash string `json:"hash"`
+}
+
+type Escrow struct {
+	ID             int64  `json:"id"`
+	BuyerWalletID  int64  `json:"buyer_wallet_id"`
+	SellerWalletID int64  `json:"seller_wallet_id"`
+	Amount         int64  `json:"amount"`
+	Description    string `json:"description"`
+	// held, disputed, released or refunded
+	Status string `json:"status"`
+	// held escrows are released to the seller from then on, disputed ones wait for an admin
+	ReleaseAt          time.Time      `json:"release_at"`
+	BuyerAgreedRefund  bool           `json:"buyer_agreed_refund"`
+	SellerAgreedRefund bool           `json:"seller_agreed_refund"`
+	DisputedBy         sql.NullString `json:"disputed_by"`
+	DisputeReason      string         `json:"dispute_reason"`
+	DisputedAt         sql.NullTime   `json:"disputed_at"`
+	// transfer to the seller, set once released
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// admin who resolved the dispute, null when the parties settled it
+	DecidedBy sql.NullString `json:"decided_by"`
+	Note      string         `json:"note"`
+	DecidedAt sql.NullTime   `json:"decided_at"`
+	CreatedBy string         `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type FeeSchedule struct {
+	ID          int64  `json:"id"`
+	Operation   string `json:"operation"`
+	UserType    string `json:"user_type"`
+	Currency    string `json:"currency"`
+	Kind        string `json:"kind"`
+	FixedAmount int64  `json:"fixed_amount"`
+	// percentage of the amount, 100 is 1%
+	BasisPoints int64 `json:"basis_points"`
+	MinFee      int64 `json:"min_fee"`
+	// zero means the fee is not capped
+	MaxFee int64           `json:"max_fee"`
+	Tiers  json.RawMessage `json:"tiers"`
+	// replaces the previous schedule of the operation from then on
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Hold struct {
+	ID               int64 `json:"id"`
+	WalletID         int64 `json:"wallet_id"`
+	MerchantWalletID int64 `json:"merchant_wallet_id"`
+	Amount           int64 `json:"amount"`
+	// at most amount, the rest is released on capture
+	CapturedAmount int64 `json:"captured_amount"`
+	// authorized, captured, voided or expired
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedBy  string        `json:"created_by"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	DecidedAt  sql.NullTime  `json:"decided_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type Installment struct {
+	ID     int64 `json:"id"`
+	PlanID int64 `json:"plan_id"`
+	Number int32 `json:"number"`
+	Amount int64 `json:"amount"`
+	// part of the amount that pays down the purchase, the rest is interest
+	Principal int64     `json:"principal"`
+	DueAt     time.Time `json:"due_at"`
+	// pending, paid, overdue or cancelled
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// when the installment is tried again after the customer could not cover it
+	RetryAt   sql.NullTime `json:"retry_at"`
+	LastError string       `json:"last_error"`
+	// less than the amount when the installment was prepaid without its interest
+	PaidAmount int64         `json:"paid_amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	PaidAt     sql.NullTime  `json:"paid_at"`
+}
+
+type InstallmentFundingOptIn struct {
+	Owner     string    `json:"owner"`
+	OptedInBy string    `json:"opted_in_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InstallmentPlan struct {
+	ID               int64 `json:"id"`
+	CustomerWalletID int64 `json:"customer_wallet_id"`
+	MerchantWalletID int64 `json:"merchant_wallet_id"`
+	// price of the purchase, before interest
+	Amount int64 `json:"amount"`
+	// basis points of the outstanding amount charged every month
+	InterestRate     int64 `json:"interest_rate"`
+	TotalAmount      int64 `json:"total_amount"`
+	InstallmentCount int32 `json:"installment_count"`
+	// platform wallet that paid the merchant upfront and collects the installments, null when the merchant collects them
+	FundingWalletID   sql.NullInt64 `json:"funding_wallet_id"`
+	FundingTransferID sql.NullInt64 `json:"funding_transfer_id"`
+	Description       string        `json:"description"`
+	// active, delinquent, completed or cancelled
+	Status    string    `json:"status"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Invoice struct {
+	ID int64 `json:"id"`
+	// merchant the invoice is numbered for
+	Issuer           string `json:"issuer"`
+	MerchantWalletID int64  `json:"merchant_wallet_id"`
+	Customer         string `json:"customer"`
+	// sequential for the issuer, set when the invoice is finalized
+	Number   sql.NullInt64 `json:"number"`
+	Currency string        `json:"currency"`
+	Memo     string        `json:"memo"`
+	DueAt    time.Time     `json:"due_at"`
+	// draft, open, paid, void or overdue
+	Status   string `json:"status"`
+	Subtotal int64  `json:"subtotal"`
+	// sum of the item discounts, taken off the subtotal before taxes
+	Discount int64 `json:"discount"`
+	Tax      int64 `json:"tax"`
+	Total    int64 `json:"total"`
+	// set while the payment is held for risk review
+	ReviewID      sql.NullInt64 `json:"review_id"`
+	FromWalletID  sql.NullInt64 `json:"from_wallet_id"`
+	TransferID    sql.NullInt64 `json:"transfer_id"`
+	FinalizedAt   sql.NullTime  `json:"finalized_at"`
+	PaidAt        sql.NullTime  `json:"paid_at"`
+	VoidedAt      sql.NullTime  `json:"voided_at"`
+	RemindedAt    sql.NullTime  `json:"reminded_at"`
+	ReminderCount int32         `json:"reminder_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type InvoiceItem struct {
+	ID          int64  `json:"id"`
+	InvoiceID   int64  `json:"invoice_id"`
+	Position    int32  `json:"position"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitAmount  int64  `json:"unit_amount"`
+	// taken off quantity times unit amount before the tax
+	Discount int64  `json:"discount"`
+	TaxName  string `json:"tax_name"`
+	// basis points of the discounted amount
+	TaxRate int64 `json:"tax_rate"`
+	Tax     int64 `json:"tax"`
+	// discounted amount plus tax
+	Amount int64 `json:"amount"`
+}
+
+type InvoiceSequence struct {
+	Issuer string `json:"issuer"`
+	// number of the last invoice finalized by the issuer
+	LastNumber int64 `json:"last_number"`
+}
+
+type Journal struct {
+	ID          int64         `json:"id"`
+	Kind        string        `json:"kind"`
+	Description string        `json:"description"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID   int64  `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// asset, liability, equity, revenue or expense
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	// set on the liability account of a wallet
+	WalletID  sql.NullInt64 `json:"wallet_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type LimitIncreaseRequest struct {
+	ID             int64  `json:"id"`
+	Owner          string `json:"owner"`
+	LimitName      string `json:"limit_name"`
+	RequestedValue int64  `json:"requested_value"`
+	// pending, applied, rejected or cancelled
+	Status string `json:"status"`
+	// end of the cooling off period
+	EffectiveAt time.Time    `json:"effective_at"`
+	DecidedBy   string       `json:"decided_by"`
+	DecidedAt   sql.NullTime `json:"decided_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type Notification struct {
+	ID        int64           `json:"id"`
+	Owner     string          `json:"owner"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    sql.NullTime    `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type PaymentLink struct {
+	ID               int64  `json:"id"`
+	Token            string `json:"token"`
+	MerchantWalletID int64  `json:"merchant_wallet_id"`
+	// charged by every session of the link, the customer chooses the amount when 0
+	Amount    int64 `json:"amount"`
+	MinAmount int64 `json:"min_amount"`
+	// highest amount a customer may choose, no limit when 0
+	MaxAmount   int64           `json:"max_amount"`
+	Currency    string          `json:"currency"`
+	Description string          `json:"description"`
+	SuccessUrl  string          `json:"success_url"`
+	CancelUrl   string          `json:"cancel_url"`
+	Metadata    json.RawMessage `json:"metadata"`
+	Active      bool            `json:"active"`
+	CreatedBy   string          `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID         int64  `json:"id"`
+	Requester  string `json:"requester"`
+	ToWalletID int64  `json:"to_wallet_id"`
+	// anyone may pay the request when null
+	Payer       sql.NullString `json:"payer"`
+	Amount      int64          `json:"amount"`
+	Currency    string         `json:"currency"`
+	Description string         `json:"description"`
+	// pending, accepted, declined, cancelled, expired or rejected by risk review
+	Status       string         `json:"status"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+	PaidBy       sql.NullString `json:"paid_by"`
+	FromWalletID sql.NullInt64  `json:"from_wallet_id"`
+	TransferID   sql.NullInt64  `json:"transfer_id"`
+	// set while the payment is held for risk review
+	ReviewID  sql.NullInt64 `json:"review_id"`
+	DecidedAt sql.NullTime  `json:"decided_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type PayoutBatch struct {
+	ID           int64  `json:"id"`
+	Owner        string `json:"owner"`
+	FromWalletID int64  `json:"from_wallet_id"`
+	Currency     string `json:"currency"`
+	Description  string `json:"description"`
+	// queued, processing, paused, cancelled or completed
+	Status          string       `json:"status"`
+	RowCount        int32        `json:"row_count"`
+	TotalAmount     int64        `json:"total_amount"`
+	SucceededCount  int32        `json:"succeeded_count"`
+	SucceededAmount int64        `json:"succeeded_amount"`
+	ReviewCount     int32        `json:"review_count"`
+	FailedCount     int32        `json:"failed_count"`
+	CancelledCount  int32        `json:"cancelled_count"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	CompletedAt     sql.NullTime `json:"completed_at"`
+}
+
+type PayoutRow struct {
+	ID           int64  `json:"id"`
+	BatchID      int64  `json:"batch_id"`
+	RowNumber    int32  `json:"row_number"`
+	RecipientKey string `json:"recipient_key"`
+	// resolved from the recipient key when the batch is uploaded
+	ToWalletID int64  `json:"to_wallet_id"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	Reference  string `json:"reference"`
+	// pending, succeeded, review, failed or cancelled
+	Status      string        `json:"status"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	Error       string        `json:"error"`
+	ProcessedAt sql.NullTime  `json:"processed_at"`
+	// set when the payout was held for risk review
+	ReviewID sql.NullInt64 `json:"review_id"`
+}
+
+type Posting struct {
+	ID        int64 `json:"id"`
+	JournalID int64 `json:"journal_id"`
+	AccountID int64 `json:"account_id"`
+	// debits are positive and credits negative, the postings of a journal add up to zero
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RateLimitBucket struct {
+	Key string `json:"key"`
+	// tokens left after the last request
+	Tokens float64 `json:"tokens"`
+	// whether the last request was allowed
+	Allowed   bool      `json:"allowed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Receivable struct {
+	ID         int64 `json:"id"`
+	WalletID   int64 `json:"wallet_id"`
+	TransferID int64 `json:"transfer_id"`
+	Amount     int64 `json:"amount"`
+	// pending receivables become available from then on
+	SettleAt time.Time `json:"settle_at"`
+	// pending, settled or anticipated
+	Status string `json:"status"`
+	// charged when the receivable was anticipated
+	AnticipationFee int64        `json:"anticipation_fee"`
+	SettledAt       sql.NullTime `json:"settled_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+type Refund struct {
+	ID               int64 `json:"id"`
+	TransferID       int64 `json:"transfer_id"`
+	RefundTransferID int64 `json:"refund_transfer_id"`
+	// must be positive
+	Amount    int64     `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Reserve struct {
+	ID         int64 `json:"id"`
+	WalletID   int64 `json:"wallet_id"`
+	TransferID int64 `json:"transfer_id"`
+	Amount     int64 `json:"amount"`
+	// taken from the reserve to cover chargebacks, never released
+	UsedAmount int64 `json:"used_amount"`
+	// held or released
+	Status     string       `json:"status"`
+	ReleaseAt  time.Time    `json:"release_at"`
+	ReleasedAt sql.NullTime `json:"released_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ReserveRule struct {
+	Owner string `json:"owner"`
+	// basis points of each payment received that are withheld
+	Rate int64 `json:"rate"`
+	// days a withheld amount stays in the reserve
+	HoldDays  int32     `json:"hold_days"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RiskDecision struct {
+	ID           int64 `json:"id"`
+	FromWalletID int64 `json:"from_wallet_id"`
+	ToWalletID   int64 `json:"to_wallet_id"`
+	Amount       int64 `json:"amount"`
+	// allow, review or block
+	Outcome string `json:"outcome"`
+	Score   int32  `json:"score"`
+	// names of the rules that matched
+	Rules        []string      `json:"rules"`
+	RulesVersion string        `json:"rules_version"`
+	TransferID   sql.NullInt64 `json:"transfer_id"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type ScheduledTransfer struct {
+	ID           int64  `json:"id"`
+	Owner        string `json:"owner"`
+	FromWalletID int64  `json:"from_wallet_id"`
+	ToWalletID   int64  `json:"to_wallet_id"`
+	Amount       int64  `json:"amount"`
+	// once, weekly or monthly
+	Frequency string `json:"frequency"`
+	// monthly runs fall on the last day of shorter months
+	DayOfMonth int32 `json:"day_of_month"`
+	// next occurrence, kept while it is retried
+	NextRunAt time.Time `json:"next_run_at"`
+	// when the failed occurrence is tried again
+	RetryAt sql.NullTime `json:"retry_at"`
+	EndAt   sql.NullTime `json:"end_at"`
+	// active, completed, failed or cancelled
+	Status    string    `json:"status"`
+	Attempts  int32     `json:"attempts"`
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	Attempt             int32     `json:"attempt"`
+	// succeeded, review or failed
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	Error      string        `json:"error"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// set when the run was held for risk review
+	ReviewID sql.NullInt64 `json:"review_id"`
+}
+
+type SettlementPlan struct {
+	Owner string `json:"owner"`
+	// days after a transfer its receivable settles, 0 settles right away
+	SettleDays int32 `json:"settle_days"`
+	// basis points charged per 30 days a receivable is settled early
+	AnticipationRate int64     `json:"anticipation_rate"`
+	UpdatedBy        string    `json:"updated_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type SplitTransfer struct {
+	ID           int64 `json:"id"`
+	FromWalletID int64 `json:"from_wallet_id"`
+	// sum of the transfers of the split
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Subscription struct {
+	ID               int64 `json:"id"`
+	PlanID           int64 `json:"plan_id"`
+	CustomerWalletID int64 `json:"customer_wallet_id"`
+	MerchantWalletID int64 `json:"merchant_wallet_id"`
+	// trialing, active, past_due or cancelled
+	Status string `json:"status"`
+	// customer who allowed the merchant to charge the wallet every period
+	MandateApprovedBy string    `json:"mandate_approved_by"`
+	MandateApprovedAt time.Time `json:"mandate_approved_at"`
+	// monthly periods end on this day, or on the last day of shorter months
+	DayOfMonth         int32     `json:"day_of_month"`
+	CurrentPeriodStart time.Time `json:"current_period_start"`
+	// when the next period is charged
+	CurrentPeriodEnd time.Time    `json:"current_period_end"`
+	TrialEndsAt      sql.NullTime `json:"trial_ends_at"`
+	// unused amount left by plan changes, taken off the next charges
+	Credit   int64 `json:"credit"`
+	Attempts int32 `json:"attempts"`
+	// when the charge is tried again after the customer could not cover it
+	RetryAt           sql.NullTime `json:"retry_at"`
+	LastError         string       `json:"last_error"`
+	CancelAtPeriodEnd bool         `json:"cancel_at_period_end"`
+	CancelledAt       sql.NullTime `json:"cancelled_at"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+type SubscriptionCharge struct {
+	ID             int64 `json:"id"`
+	SubscriptionID int64 `json:"subscription_id"`
+	PlanID         int64 `json:"plan_id"`
+	// period or proration
+	Reason      string    `json:"reason"`
+	Amount      int64     `json:"amount"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// null when the credit covered the whole charge
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type SubscriptionPlan struct {
+	ID               int64  `json:"id"`
+	MerchantWalletID int64  `json:"merchant_wallet_id"`
+	Name             string `json:"name"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	// weekly or monthly
+	Frequency string    `json:"frequency"`
+	TrialDays int32     `json:"trial_days"`
+	Active    bool      `json:"active"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Transfer struct {
@@ -22,8 +650,40 @@ type Transfer struct {
 	FromWalletID int64 `json:"from_wallet_id"`
 	ToWalletID   int64 `json:"to_wallet_id"`
 	// must be positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	Amount           int64         `json:"amount"`
+	CreatedAt        time.Time     `json:"created_at"`
+	PaymentRequestID sql.NullInt64 `json:"payment_request_id"`
+	SplitTransferID  sql.NullInt64 `json:"split_transfer_id"`
+	InvoiceID        sql.NullInt64 `json:"invoice_id"`
+}
+
+type TransferLimit struct {
+	Owner string `json:"owner"`
+	// overrides the default limits, zero means unlimited
+	PerTransfer      int64     `json:"per_transfer"`
+	Daily            int64     `json:"daily"`
+	Monthly          int64     `json:"monthly"`
+	DailyCount       int64     `json:"daily_count"`
+	NightPerTransfer int64     `json:"night_per_transfer"`
+	Nightly          int64     `json:"nightly"`
+	UpdatedBy        string    `json:"updated_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type TransferReview struct {
+	ID           int64 `json:"id"`
+	DecisionID   int64 `json:"decision_id"`
+	FromWalletID int64 `json:"from_wallet_id"`
+	ToWalletID   int64 `json:"to_wallet_id"`
+	// held from the sender until the review is decided
+	Amount int64 `json:"amount"`
+	// pending, approved or rejected
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ReviewedBy string        `json:"reviewed_by"`
+	Note       string        `json:"note"`
+	ReviewedAt sql.NullTime  `json:"reviewed_at"`
+	CreatedAt  time.Time     `json:"created_at"`
 }
 
 type User struct {
@@ -36,6 +696,10 @@ type User struct {
 	IsMerchant        sql.NullBool `json:"is_merchant"`
 	CreatedAt         time.Time    `json:"created_at"`
 	LastUpdated       sql.NullTime `json:"last_updated"`
+	// customer, merchant, support or admin
+	Role string `json:"role"`
+	// what the merchant sells, campaigns can be limited to a category
+	MerchantCategory string `json:"merchant_category"`
 }
 
 type Wallet struct {
@@ -45,4 +709,54 @@ type Wallet struct {
 	Currency    string        `json:"currency"`
 	CreatedAt   sql.NullTime  `json:"created_at"`
 	CountryCode sql.NullInt32 `json:"country_code"`
+	IsFrozen    bool          `json:"is_frozen"`
+	// sum of the authorized holds, the available balance is balance - held_balance
+	HeldBalance int64 `json:"held_balance"`
+	// sum of the pending receivables, which are part of the balance but not available
+	ReceivableBalance int64 `json:"receivable_balance"`
+	// sum of what is left of the held reserves, which is part of the balance but not available
+	ReserveBalance int64 `json:"reserve_balance"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EndpointID int64 `json:"endpoint_id"`
+	EventID    int64 `json:"event_id"`
+	// pending, delivered or failed
+	Status        string       `json:"status"`
+	Attempts      int32        `json:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	LastError     string       `json:"last_error"`
+	DeliveredAt   sql.NullTime `json:"delivered_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         int64 `json:"id"`
+	DeliveryID int64 `json:"delivery_id"`
+	// 0 when no response was received
+	ResponseStatus int32     `json:"response_status"`
+	Error          string    `json:"error"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type WebhookEndpoint struct {
+	ID                  int64        `json:"id"`
+	Owner               string       `json:"owner"`
+	Url                 string       `json:"url"`
+	Secret              string       `json:"secret"`
+	EventTypes          []string     `json:"event_types"`
+	Enabled             bool         `json:"enabled"`
+	ConsecutiveFailures int32        `json:"consecutive_failures"`
+	DisabledAt          sql.NullTime `json:"disabled_at"`
+	CreatedAt           time.Time    `json:"created_at"`
+}
+
+type WebhookEvent struct {
+	ID        int64           `json:"id"`
+	Owner     string          `json:"owner"`
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
 }
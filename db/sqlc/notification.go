@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// notify leaves a notification in the inbox of the owner. Like publishEvent,
+// it runs inside the transaction that caused it
+func notify(ctx context.Context, q *Queries, owner string, notificationType string, message string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateNotification(ctx, CreateNotificationParams{
+		Owner:   owner,
+		Type:    notificationType,
+		Message: message,
+		Data:    payload,
+	})
+
+	return err
+}
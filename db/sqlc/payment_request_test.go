@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentRequest(t *testing.T, to Wallet, payer string, expiresAt time.Time) PaymentRequest {
+	request, err := testQueries.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   to.Owner,
+		ToWalletID:  to.ID,
+		Payer:       sql.NullString{String: payer, Valid: payer != ""},
+		Amount:      10,
+		Currency:    to.Currency,
+		Description: "lunch",
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, request.Status)
+
+	return request
+}
+
+func TestAcceptPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWallet(t)
+	to := createRandomWallet(t)
+	request := createRandomPaymentRequest(t, to, from.Owner, time.Now().Add(time.Hour))
+
+	result, err := store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:           request.ID,
+		Payer:        from.Owner,
+		FromWalletID: from.ID,
+		Now:          time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestAccepted, result.PaymentRequest.Status)
+	require.Equal(t, result.Transfer.ID, result.PaymentRequest.TransferID.Int64)
+	require.Equal(t, request.ID, result.Transfer.PaymentRequestID.Int64)
+	require.Equal(t, from.Balance-10, result.FromWallet.Balance)
+	require.Equal(t, to.Balance+10, result.ToWallet.Balance)
+
+	_, err = store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:           request.ID,
+		Payer:        from.Owner,
+		FromWalletID: from.ID,
+		Now:          time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+
+	_, err = store.DecidePaymentRequestTx(context.Background(), DecidePaymentRequestTxParams{
+		ID:     request.ID,
+		Status: PaymentRequestCancelled,
+		Now:    time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+}
+
+func TestAcceptExpiredPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWallet(t)
+	to := createRandomWallet(t)
+	request := createRandomPaymentRequest(t, to, "", time.Now().Add(time.Minute))
+
+	_, err := store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:           request.ID,
+		Payer:        from.Owner,
+		FromWalletID: from.ID,
+		Now:          time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestExpired)
+
+	request, err = store.GetPaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestExpired, request.Status)
+
+	updatedFrom, err := store.GetWallet(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, updatedFrom.Balance)
+}
+
+func TestDeclinePaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createRandomUser(t)
+	to := createRandomWallet(t)
+	request := createRandomPaymentRequest(t, to, payer.Username, time.Now().Add(time.Hour))
+
+	declined, err := store.DecidePaymentRequestTx(context.Background(), DecidePaymentRequestTxParams{
+		ID:     request.ID,
+		Status: PaymentRequestDeclined,
+		Now:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, declined.Status)
+	require.True(t, declined.DecidedAt.Valid)
+
+	notifications, err := store.ListNotifications(context.Background(), ListNotificationsParams{
+		Owner: to.Owner,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestAccepted  = "accepted"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+	PaymentRequestRejected  = "rejected"
+)
+
+var (
+	// ErrPaymentRequestNotPending is returned when acting on a request that was already decided
+	ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
+	// ErrPaymentRequestExpired is returned when accepting a request past its expiry
+	ErrPaymentRequestExpired = errors.New("payment request has expired")
+)
+
+type AcceptPaymentRequestTxParams struct {
+	ID           int64     `json:"id"`
+	Payer        string    `json:"payer"`
+	FromWalletID int64     `json:"from_wallet_id"`
+	Now          time.Time `json:"now"`
+	// Limits, Risk, Fees and Cashback are applied to the transfer like in TransferTx
+	Limits   *TransferLimitsParams `json:"-"`
+	Risk     RiskEvaluator         `json:"-"`
+	Fees     *FeeParams            `json:"-"`
+	Cashback *CashbackParams       `json:"-"`
+}
+
+type AcceptPaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest `json:"payment_request"`
+	TrasferTxResult
+}
+
+// AcceptPaymentRequestTx pays a pending request from the payer wallet. An
+// expired request is marked as such and ErrPaymentRequestExpired is returned.
+// When the transfer is held for review, the request is accepted and gets its
+// transfer once the review is approved
+func (store *SQLStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	var result AcceptPaymentRequestTxResult
+	var failure error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetPaymentRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if request.Status != PaymentRequestPending {
+			return ErrPaymentRequestNotPending
+		}
+
+		if !arg.Now.Before(request.ExpiresAt) {
+			// commit the expiry so the request stops showing as pending
+			result.PaymentRequest, err = q.ExpirePaymentRequest(ctx, request.ID)
+			failure = ErrPaymentRequestExpired
+			return err
+		}
+
+		var blocked bool
+
+		result.TrasferTxResult, blocked, err = transferTx(ctx, q, TrasferTxParms{
+			FromWalletID: arg.FromWalletID,
+			ToWalletID:   request.ToWalletID,
+			Amount:       request.Amount,
+			Limits:       arg.Limits,
+			Risk:         arg.Risk,
+			Fees:         arg.Fees,
+			Cashback:     arg.Cashback,
+		})
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			failure = ErrTransferBlocked
+			return nil
+		}
+
+		pay := PayPaymentRequestParams{
+			ID:           request.ID,
+			PaidBy:       sql.NullString{String: arg.Payer, Valid: true},
+			FromWalletID: sql.NullInt64{Int64: arg.FromWalletID, Valid: true},
+		}
+
+		if result.Review != nil {
+			pay.ReviewID = sql.NullInt64{Int64: result.Review.ID, Valid: true}
+		} else {
+			pay.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+			result.Transfer, err = q.SetTransferPaymentRequest(ctx, SetTransferPaymentRequestParams{
+				ID:               result.Transfer.ID,
+				PaymentRequestID: sql.NullInt64{Int64: request.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.PaymentRequest, err = q.PayPaymentRequest(ctx, pay)
+		if err != nil {
+			return err
+		}
+
+		return notifyPaymentRequest(ctx, q, result.PaymentRequest)
+	})
+
+	if err == nil {
+		err = failure
+	}
+
+	return result, err
+}
+
+type DecidePaymentRequestTxParams struct {
+	ID     int64     `json:"id"`
+	Status string    `json:"status"`
+	Now    time.Time `json:"now"`
+}
+
+// DecidePaymentRequestTx declines or cancels a pending request that has not expired
+func (store *SQLStore) DecidePaymentRequestTx(ctx context.Context, arg DecidePaymentRequestTxParams) (PaymentRequest, error) {
+	var request PaymentRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		request, err = q.DecidePaymentRequest(ctx, DecidePaymentRequestParams{
+			ID:     arg.ID,
+			Status: arg.Status,
+			Now:    arg.Now,
+		})
+		if err == sql.ErrNoRows {
+			return ErrPaymentRequestNotPending
+		}
+		if err != nil {
+			return err
+		}
+
+		if request.Status != PaymentRequestDeclined {
+			return nil
+		}
+
+		return notifyPaymentRequest(ctx, q, request)
+	})
+
+	return request, err
+}
+
+// settlePaymentRequestReview links the request paid by a reviewed transfer
+// to the transfer, or rejects it when the review was rejected
+func settlePaymentRequestReview(ctx context.Context, q *Queries, review TransferReview, transfer *Transfer) error {
+	request, err := q.GetPaymentRequestByReview(ctx, sql.NullInt64{Int64: review.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	arg := SetPaymentRequestTransferParams{
+		ID:     request.ID,
+		Status: PaymentRequestRejected,
+	}
+
+	if transfer != nil {
+		arg.Status = PaymentRequestAccepted
+		arg.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+
+		*transfer, err = q.SetTransferPaymentRequest(ctx, SetTransferPaymentRequestParams{
+			ID:               transfer.ID,
+			PaymentRequestID: sql.NullInt64{Int64: request.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err = q.SetPaymentRequestTransfer(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	return notifyPaymentRequest(ctx, q, request)
+}
+
+// notifyPaymentRequest tells the requester what happened to the request
+func notifyPaymentRequest(ctx context.Context, q *Queries, request PaymentRequest) error {
+	var notificationType, message string
+
+	switch {
+	case request.Status == PaymentRequestAccepted && request.TransferID.Valid:
+		notificationType = util.NotificationPaymentRequestPaid
+		message = fmt.Sprintf("Payment request %d was paid by %s.", request.ID, request.PaidBy.String)
+	case request.Status == PaymentRequestAccepted:
+		// paid once the review is approved
+		return nil
+	case request.Status == PaymentRequestDeclined:
+		notificationType = util.NotificationPaymentRequestDeclined
+		message = fmt.Sprintf("Payment request %d was declined by %s.", request.ID, request.Payer.String)
+	case request.Status == PaymentRequestRejected:
+		notificationType = util.NotificationPaymentRequestDeclined
+		message = fmt.Sprintf("The payment of request %d was rejected by risk review.", request.ID)
+	default:
+		return nil
+	}
+
+	return notify(ctx, q, request.Requester, notificationType, message, request)
+}
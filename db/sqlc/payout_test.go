@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPayoutBatch(t *testing.T, from Wallet, amounts ...int64) CreatePayoutBatchTxResult {
+	store := NewStore(testDB)
+
+	var rows []CreatePayoutRowParams
+	for _, amount := range amounts {
+		to := createRandomWalletIn(t, from.Currency)
+		rows = append(rows, CreatePayoutRowParams{
+			RecipientKey: to.Owner,
+			ToWalletID:   to.ID,
+			Amount:       amount,
+			Currency:     from.Currency,
+		})
+	}
+
+	result, err := store.CreatePayoutBatchTx(context.Background(), CreatePayoutBatchTxParams{
+		Owner:        from.Owner,
+		FromWalletID: from.ID,
+		Currency:     from.Currency,
+		Rows:         rows,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PayoutBatchQueued, result.Batch.Status)
+	require.Equal(t, int32(len(amounts)), result.Batch.RowCount)
+	require.Len(t, result.Rows, len(amounts))
+
+	return result
+}
+
+// processPayoutBatch executes rows until the batch is finished, other
+// batches in the database may be claimed on the way
+func processPayoutBatch(t *testing.T, batchID int64) PayoutBatch {
+	store := NewStore(testDB)
+
+	for i := 0; i < 100; i++ {
+		_, execErr := store.ExecutePayoutRowTx(context.Background(), ExecutePayoutRowTxParams{Now: time.Now()})
+		if execErr != sql.ErrNoRows {
+			require.NoError(t, execErr)
+		}
+
+		batch, err := store.GetPayoutBatch(context.Background(), batchID)
+		require.NoError(t, err)
+		if batch.Status == PayoutBatchCompleted || execErr == sql.ErrNoRows {
+			return batch
+		}
+	}
+
+	t.Fatal("payout batch wasn't completed")
+	return PayoutBatch{}
+}
+
+func TestExecutePayoutRowTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.BRL)
+	created := createRandomPayoutBatch(t, from, 1, from.Balance*2, 2)
+
+	batch := processPayoutBatch(t, created.Batch.ID)
+	require.Equal(t, PayoutBatchCompleted, batch.Status)
+	require.Equal(t, int32(2), batch.SucceededCount)
+	require.Equal(t, int64(3), batch.SucceededAmount)
+	require.Equal(t, int32(1), batch.FailedCount)
+	require.True(t, batch.CompletedAt.Valid)
+
+	rows, err := store.ListAllPayoutRows(context.Background(), batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayoutRowSucceeded, rows[0].Status)
+	require.True(t, rows[0].TransferID.Valid)
+	require.Equal(t, PayoutRowFailed, rows[1].Status)
+	require.Equal(t, ErrInsufficientFunds.Error(), rows[1].Error)
+	require.Equal(t, PayoutRowSucceeded, rows[2].Status)
+
+	updated, err := store.GetWallet(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-3, updated.Balance)
+}
+
+func TestUpdatePayoutBatchTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.BRL)
+	created := createRandomPayoutBatch(t, from, 1, 2)
+
+	batch, err := store.UpdatePayoutBatchTx(context.Background(), UpdatePayoutBatchTxParams{
+		ID:     created.Batch.ID,
+		Status: PayoutBatchPaused,
+		Now:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PayoutBatchPaused, batch.Status)
+
+	batch, err = store.UpdatePayoutBatchTx(context.Background(), UpdatePayoutBatchTxParams{
+		ID:     created.Batch.ID,
+		Status: PayoutBatchCancelled,
+		Now:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PayoutBatchCancelled, batch.Status)
+	require.Equal(t, int32(2), batch.CancelledCount)
+
+	rows, err := store.ListAllPayoutRows(context.Background(), batch.ID)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.Equal(t, PayoutRowCancelled, row.Status)
+	}
+
+	_, err = store.UpdatePayoutBatchTx(context.Background(), UpdatePayoutBatchTxParams{
+		ID:     created.Batch.ID,
+		Status: PayoutBatchProcessing,
+		Now:    time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPayoutBatchFinished)
+}
+
+func TestPayoutRowReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createFundedWallet(t, 1000)
+	created := createRandomPayoutBatch(t, from, 100, 200)
+
+	// other batches in the database may be claimed on the way
+	var batch PayoutBatch
+	for i := 0; i < 100 && batch.Status != PayoutBatchCompleted; i++ {
+		_, err := store.ExecutePayoutRowTx(context.Background(), ExecutePayoutRowTxParams{
+			Now:  time.Now(),
+			Risk: stubEvaluator{outcome: RiskReview},
+		})
+		require.NoError(t, err)
+
+		batch, err = store.GetPayoutBatch(context.Background(), created.Batch.ID)
+		require.NoError(t, err)
+	}
+	require.Equal(t, PayoutBatchCompleted, batch.Status)
+	require.Equal(t, int32(2), batch.ReviewCount)
+
+	rows, err := store.ListAllPayoutRows(context.Background(), batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayoutRowReview, rows[0].Status)
+	require.True(t, rows[0].ReviewID.Valid)
+
+	approved, err := store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+		ReviewID:   rows[0].ReviewID.Int64,
+		Approve:    true,
+		ReviewedBy: "analyst",
+	})
+	require.NoError(t, err)
+
+	_, err = store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+		ReviewID:   rows[1].ReviewID.Int64,
+		ReviewedBy: "analyst",
+	})
+	require.NoError(t, err)
+
+	batch, err = store.GetPayoutBatch(context.Background(), batch.ID)
+	require.NoError(t, err)
+	require.Zero(t, batch.ReviewCount)
+	require.Equal(t, int32(1), batch.SucceededCount)
+	require.Equal(t, int64(100), batch.SucceededAmount)
+	require.Equal(t, int32(1), batch.FailedCount)
+
+	rows, err = store.ListAllPayoutRows(context.Background(), batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, PayoutRowSucceeded, rows[0].Status)
+	require.Equal(t, approved.Transfer.ID, rows[0].TransferID.Int64)
+	require.Equal(t, PayoutRowFailed, rows[1].Status)
+	require.False(t, rows[1].TransferID.Valid)
+
+	updated, err := store.GetWallet(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(900), updated.Balance)
+}
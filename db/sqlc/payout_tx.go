@@ -0,0 +1,315 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	PayoutBatchQueued     = "queued"
+	PayoutBatchProcessing = "processing"
+	PayoutBatchPaused     = "paused"
+	PayoutBatchCancelled  = "cancelled"
+	PayoutBatchCompleted  = "completed"
+)
+
+const (
+	PayoutRowPending   = "pending"
+	PayoutRowSucceeded = "succeeded"
+	PayoutRowReview    = "review"
+	PayoutRowFailed    = "failed"
+	PayoutRowCancelled = "cancelled"
+)
+
+// ErrPayoutBatchFinished is returned when a cancelled or completed batch is changed
+var ErrPayoutBatchFinished = errors.New("payout batch is finished")
+
+type CreatePayoutBatchTxParams struct {
+	Owner        string `json:"owner"`
+	FromWalletID int64  `json:"from_wallet_id"`
+	Currency     string `json:"currency"`
+	Description  string `json:"description"`
+	// Rows are numbered in order, BatchID and RowNumber are filled in
+	Rows []CreatePayoutRowParams `json:"rows"`
+}
+
+type CreatePayoutBatchTxResult struct {
+	Batch PayoutBatch `json:"batch"`
+	Rows  []PayoutRow `json:"rows"`
+}
+
+// CreatePayoutBatchTx queues a batch of payouts with all its rows
+func (store *SQLStore) CreatePayoutBatchTx(ctx context.Context, arg CreatePayoutBatchTxParams) (CreatePayoutBatchTxResult, error) {
+	var result CreatePayoutBatchTxResult
+
+	var total int64
+	for _, row := range arg.Rows {
+		total += row.Amount
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Batch, err = q.CreatePayoutBatch(ctx, CreatePayoutBatchParams{
+			Owner:        arg.Owner,
+			FromWalletID: arg.FromWalletID,
+			Currency:     arg.Currency,
+			Description:  arg.Description,
+			RowCount:     int32(len(arg.Rows)),
+			TotalAmount:  total,
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, row := range arg.Rows {
+			row.BatchID = result.Batch.ID
+			row.RowNumber = int32(i + 1)
+
+			created, err := q.CreatePayoutRow(ctx, row)
+			if err != nil {
+				return err
+			}
+
+			result.Rows = append(result.Rows, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type ExecutePayoutRowTxParams struct {
+	Now    time.Time
+	Limits *TransferLimitsParams
+	Risk   RiskEvaluator
+	Fees   *FeeParams
+}
+
+type ExecutePayoutRowTxResult struct {
+	Batch PayoutBatch `json:"batch"`
+	Row   PayoutRow   `json:"row"`
+	TrasferTxResult
+}
+
+// ExecutePayoutRowTx claims the oldest active batch and pays its next row.
+// Batches locked by other instances are skipped, and the batch stays locked
+// until the row is recorded, so pausing or cancelling waits for the row in
+// flight. It returns sql.ErrNoRows when no batch has rows left to pay
+func (store *SQLStore) ExecutePayoutRowTx(ctx context.Context, arg ExecutePayoutRowTxParams) (ExecutePayoutRowTxResult, error) {
+	var result ExecutePayoutRowTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		batch, err := q.ClaimPayoutBatch(ctx)
+		if err != nil {
+			return err
+		}
+
+		row, err := q.GetNextPayoutRow(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+
+		update := UpdatePayoutRowParams{
+			ID:          row.ID,
+			Status:      PayoutRowSucceeded,
+			ProcessedAt: sql.NullTime{Time: arg.Now, Valid: true},
+		}
+		outcome := RecordPayoutRowOutcomeParams{ID: batch.ID}
+
+		result.TrasferTxResult, err = checkedTransferTx(ctx, q, TrasferTxParms{
+			FromWalletID: batch.FromWalletID,
+			ToWalletID:   row.ToWalletID,
+			Amount:       row.Amount,
+			Limits:       arg.Limits,
+			Risk:         arg.Risk,
+			Fees:         arg.Fees,
+		})
+
+		switch {
+		case err == nil && result.Review != nil:
+			update.Status = PayoutRowReview
+			update.ReviewID = sql.NullInt64{Int64: result.Review.ID, Valid: true}
+			outcome.Review = 1
+		case err == nil:
+			update.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+			outcome.Succeeded = 1
+			outcome.SucceededAmount = row.Amount
+		case isTransferFailure(err):
+			update.Status = PayoutRowFailed
+			update.Error = err.Error()
+			outcome.Failed = 1
+		default:
+			return err
+		}
+
+		result.Row, err = q.UpdatePayoutRow(ctx, update)
+		if err != nil {
+			return err
+		}
+
+		result.Batch, err = q.RecordPayoutRowOutcome(ctx, outcome)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetNextPayoutRow(ctx, batch.ID)
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		result.Batch, err = q.CompletePayoutBatch(ctx, CompletePayoutBatchParams{
+			ID:  batch.ID,
+			Now: arg.Now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return notifyPayoutBatchCompleted(ctx, q, result.Batch)
+	})
+
+	return result, err
+}
+
+// lockPayoutRowReview locks the payout row held by the review and its batch,
+// if any. Reviews lock them before the wallets, the same order the processor
+// uses
+func lockPayoutRowReview(ctx context.Context, q *Queries, review TransferReview) (*PayoutRow, error) {
+	row, err := q.GetPayoutRowByReview(ctx, sql.NullInt64{Int64: review.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.GetPayoutBatchForUpdate(ctx, row.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// settlePayoutRowReview records the payout made when its review was
+// approved, or failed when it was rejected, and moves it out of the review
+// count of its batch. The owner is told once the last review of a completed
+// batch is decided
+func settlePayoutRowReview(ctx context.Context, q *Queries, row PayoutRow, transfer *Transfer) error {
+	update := UpdatePayoutRowParams{
+		ID:          row.ID,
+		Status:      PayoutRowFailed,
+		Error:       "rejected in risk review",
+		ProcessedAt: row.ProcessedAt,
+		ReviewID:    row.ReviewID,
+	}
+	outcome := RecordPayoutReviewOutcomeParams{ID: row.BatchID, Failed: 1}
+
+	if transfer != nil {
+		update.Status = PayoutRowSucceeded
+		update.Error = ""
+		update.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+		outcome = RecordPayoutReviewOutcomeParams{ID: row.BatchID, Succeeded: 1, SucceededAmount: row.Amount}
+	}
+
+	_, err := q.UpdatePayoutRow(ctx, update)
+	if err != nil {
+		return err
+	}
+
+	batch, err := q.RecordPayoutReviewOutcome(ctx, outcome)
+	if err != nil {
+		return err
+	}
+
+	if batch.Status != PayoutBatchCompleted || batch.ReviewCount > 0 {
+		return nil
+	}
+
+	return notifyPayoutBatchCompleted(ctx, q, batch)
+}
+
+type UpdatePayoutBatchTxParams struct {
+	ID     int64     `json:"id"`
+	Status string    `json:"status"`
+	Now    time.Time `json:"now"`
+}
+
+// UpdatePayoutBatchTx pauses, resumes or cancels a batch. Cancelling marks
+// the rows that weren't paid yet as cancelled. Finished batches return
+// ErrPayoutBatchFinished
+func (store *SQLStore) UpdatePayoutBatchTx(ctx context.Context, arg UpdatePayoutBatchTxParams) (PayoutBatch, error) {
+	var batch PayoutBatch
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		batch, err = q.GetPayoutBatchForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if batch.Status == PayoutBatchCancelled || batch.Status == PayoutBatchCompleted {
+			return ErrPayoutBatchFinished
+		}
+
+		switch arg.Status {
+		case PayoutBatchPaused:
+			batch, err = q.UpdatePayoutBatchStatus(ctx, UpdatePayoutBatchStatusParams{
+				ID:           arg.ID,
+				Status:       PayoutBatchPaused,
+				FromStatuses: []string{PayoutBatchQueued, PayoutBatchProcessing},
+			})
+			if err == sql.ErrNoRows {
+				// already paused
+				return nil
+			}
+			return err
+		case PayoutBatchProcessing:
+			batch, err = q.UpdatePayoutBatchStatus(ctx, UpdatePayoutBatchStatusParams{
+				ID:           arg.ID,
+				Status:       PayoutBatchProcessing,
+				FromStatuses: []string{PayoutBatchPaused},
+			})
+			if err == sql.ErrNoRows {
+				// not paused
+				return nil
+			}
+			return err
+		case PayoutBatchCancelled:
+			cancelled, err := q.CancelPendingPayoutRows(ctx, CancelPendingPayoutRowsParams{
+				BatchID: arg.ID,
+				Now:     arg.Now,
+			})
+			if err != nil {
+				return err
+			}
+
+			batch, err = q.CancelPayoutBatch(ctx, CancelPayoutBatchParams{
+				ID:        arg.ID,
+				Cancelled: int32(cancelled),
+				Now:       arg.Now,
+			})
+			return err
+		}
+
+		return fmt.Errorf("payout batches can't be updated to %s", arg.Status)
+	})
+
+	return batch, err
+}
+
+func notifyPayoutBatchCompleted(ctx context.Context, q *Queries, batch PayoutBatch) error {
+	message := fmt.Sprintf("Payout batch %d completed: %d of %d payouts succeeded.", batch.ID, batch.SucceededCount, batch.RowCount)
+	if batch.ReviewCount > 0 {
+		message += fmt.Sprintf(" %d are under review.", batch.ReviewCount)
+	}
+
+	return notify(ctx, q, batch.Owner, util.NotificationPayoutBatchCompleted, message, batch)
+}
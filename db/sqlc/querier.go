@@ -6,27 +6,295 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddCashbackCampaignSpent(ctx context.Context, arg AddCashbackCampaignSpentParams) (CashbackCampaign, error)
 	AddWalletBalance(ctx context.Context, arg AddWalletBalanceParams) (Wallet, error)
+	AddWalletHeldBalance(ctx context.Context, arg AddWalletHeldBalanceParams) (Wallet, error)
+	AddWalletReceivableBalance(ctx context.Context, arg AddWalletReceivableBalanceParams) (Wallet, error)
+	AddWalletReserveBalance(ctx context.Context, arg AddWalletReserveBalanceParams) (Wallet, error)
+	AdvanceScheduledTransfer(ctx context.Context, arg AdvanceScheduledTransferParams) (ScheduledTransfer, error)
+	AgreeEscrowRefund(ctx context.Context, arg AgreeEscrowRefundParams) (Escrow, error)
+	AnticipateReceivable(ctx context.Context, arg AnticipateReceivableParams) (Receivable, error)
+	CancelOpenInstallments(ctx context.Context, planID int64) ([]Installment, error)
+	CancelPayoutBatch(ctx context.Context, arg CancelPayoutBatchParams) (PayoutBatch, error)
+	CancelPendingPayoutRows(ctx context.Context, arg CancelPendingPayoutRowsParams) (int64, error)
+	CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	CancelSubscription(ctx context.Context, arg CancelSubscriptionParams) (Subscription, error)
+	ChangeSubscriptionPlan(ctx context.Context, arg ChangeSubscriptionPlanParams) (Subscription, error)
+	ClaimDueCashbackGrant(ctx context.Context, now time.Time) (CashbackGrant, error)
+	ClaimDueEscrows(ctx context.Context, arg ClaimDueEscrowsParams) ([]Escrow, error)
+	ClaimDueInstallment(ctx context.Context, now time.Time) (Installment, error)
+	ClaimDueLimitIncreaseRequests(ctx context.Context, limit int32) ([]LimitIncreaseRequest, error)
+	ClaimDueReceivables(ctx context.Context, arg ClaimDueReceivablesParams) ([]Receivable, error)
+	ClaimDueReserves(ctx context.Context, arg ClaimDueReservesParams) ([]Reserve, error)
+	ClaimDueScheduledTransfer(ctx context.Context, now time.Time) (ScheduledTransfer, error)
+	ClaimDueSubscription(ctx context.Context, now time.Time) (Subscription, error)
+	ClaimExpiredHolds(ctx context.Context, arg ClaimExpiredHoldsParams) ([]Hold, error)
+	ClaimOverdueDisputes(ctx context.Context, arg ClaimOverdueDisputesParams) ([]Dispute, error)
+	ClaimPayoutBatch(ctx context.Context) (PayoutBatch, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClawBackCashbackGrant(ctx context.Context, arg ClawBackCashbackGrantParams) (CashbackGrant, error)
+	CompletePayoutBatch(ctx context.Context, arg CompletePayoutBatchParams) (PayoutBatch, error)
+	CountLateInstallments(ctx context.Context, planID int64) (CountLateInstallmentsRow, error)
+	CountMerchantTransfersSince(ctx context.Context, arg CountMerchantTransfersSinceParams) (int64, error)
+	CountOtherRecipients(ctx context.Context, arg CountOtherRecipientsParams) (int64, error)
+	CountRoundTransfers(ctx context.Context, arg CountRoundTransfersParams) (int64, error)
+	CountTransfersBetween(ctx context.Context, arg CountTransfersBetweenParams) (int64, error)
+	CountUnbalancedJournals(ctx context.Context) (int64, error)
+	CountWalletLedgerMismatches(ctx context.Context) (int64, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateApiKeyNonce(ctx context.Context, arg CreateApiKeyNonceParams) (int64, error)
+	CreateCashbackCampaign(ctx context.Context, arg CreateCashbackCampaignParams) (CashbackCampaign, error)
+	CreateCashbackGrant(ctx context.Context, arg CreateCashbackGrantParams) (CashbackGrant, error)
+	CreateChainCheckpoint(ctx context.Context, arg CreateChainCheckpointParams) (ChainCheckpoint, error)
+	CreateCheckoutSession(ctx context.Context, arg CreateCheckoutSessionParams) (CheckoutSession, error)
+	CreateDispute(ctx context.Context, arg CreateDisputeParams) (Dispute, error)
+	CreateDisputeEvidence(ctx context.Context, arg CreateDisputeEvidenceParams) (DisputeEvidence, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateEscrow(ctx context.Context, arg CreateEscrowParams) (Escrow, error)
+	CreateFeeEntry(ctx context.Context, arg CreateFeeEntryParams) (Entry, error)
+	CreateFeeSchedule(ctx context.Context, arg CreateFeeScheduleParams) (FeeSchedule, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateInstallment(ctx context.Context, arg CreateInstallmentParams) (Installment, error)
+	CreateInstallmentPlan(ctx context.Context, arg CreateInstallmentPlanParams) (InstallmentPlan, error)
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateInvoiceItem(ctx context.Context, arg CreateInvoiceItemParams) (InvoiceItem, error)
+	CreateJournal(ctx context.Context, arg CreateJournalParams) (Journal, error)
+	CreateLedgerAccount(ctx context.Context, arg CreateLedgerAccountParams) (LedgerAccount, error)
+	CreateLimitIncreaseRequest(ctx context.Context, arg CreateLimitIncreaseRequestParams) (LimitIncreaseRequest, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (PaymentLink, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreatePayoutBatch(ctx context.Context, arg CreatePayoutBatchParams) (PayoutBatch, error)
+	CreatePayoutRow(ctx context.Context, arg CreatePayoutRowParams) (PayoutRow, error)
+	CreatePosting(ctx context.Context, arg CreatePostingParams) (Posting, error)
+	CreateReceivable(ctx context.Context, arg CreateReceivableParams) (Receivable, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	CreateReserve(ctx context.Context, arg CreateReserveParams) (Reserve, error)
+	CreateRiskDecision(ctx context.Context, arg CreateRiskDecisionParams) (RiskDecision, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
+	CreateSplitTransfer(ctx context.Context, arg CreateSplitTransferParams) (SplitTransfer, error)
+	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	CreateSubscriptionCharge(ctx context.Context, arg CreateSubscriptionChargeParams) (SubscriptionCharge, error)
+	CreateSubscriptionPlan(ctx context.Context, arg CreateSubscriptionPlanParams) (SubscriptionPlan, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateWallet(ctx context.Context, arg CreateWalletParams) (Wallet, error)
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) (int64, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	CreateWebhookEvent(ctx context.Context, arg CreateWebhookEventParams) (WebhookEvent, error)
+	DeactivateCashbackCampaign(ctx context.Context, id int64) (CashbackCampaign, error)
+	DeactivatePaymentLink(ctx context.Context, id int64) (PaymentLink, error)
+	DeactivateSubscriptionPlan(ctx context.Context, id int64) (SubscriptionPlan, error)
+	DecideDispute(ctx context.Context, arg DecideDisputeParams) (Dispute, error)
+	DecideLimitIncreaseRequest(ctx context.Context, arg DecideLimitIncreaseRequestParams) (LimitIncreaseRequest, error)
+	DecidePaymentRequest(ctx context.Context, arg DecidePaymentRequestParams) (PaymentRequest, error)
+	DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (TransferReview, error)
+	DeductReceivable(ctx context.Context, arg DeductReceivableParams) (Receivable, error)
+	DeleteExpiredApiKeyNonces(ctx context.Context, arg DeleteExpiredApiKeyNoncesParams) error
+	DeleteFutureFeeSchedule(ctx context.Context, arg DeleteFutureFeeScheduleParams) (int64, error)
+	DeleteInstallmentFundingOptIn(ctx context.Context, owner string) error
+	DeleteInvoiceItems(ctx context.Context, invoiceID int64) error
+	DeleteReserveRule(ctx context.Context, owner string) error
+	DeleteSettlementPlan(ctx context.Context, owner string) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt time.Time) error
+	DeleteTransferLimits(ctx context.Context, owner string) error
 	DeleteUser(ctx context.Context, username string) error
 	DeleteWallet(ctx context.Context, id int64) error
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	DisableWebhookEndpoint(ctx context.Context, id int64) error
+	DisputeEscrow(ctx context.Context, arg DisputeEscrowParams) (Escrow, error)
+	EnableWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ExpireCheckoutSession(ctx context.Context, id int64) (CheckoutSession, error)
+	ExpireDueCheckoutSessions(ctx context.Context, arg ExpireDueCheckoutSessionsParams) ([]CheckoutSession, error)
+	ExpirePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	FailInstallment(ctx context.Context, arg FailInstallmentParams) (Installment, error)
+	FailSubscriptionCharge(ctx context.Context, arg FailSubscriptionChargeParams) (Subscription, error)
+	FinalizeInvoice(ctx context.Context, arg FinalizeInvoiceParams) (Invoice, error)
+	GetActiveFeeSchedule(ctx context.Context, arg GetActiveFeeScheduleParams) (FeeSchedule, error)
+	GetApiKey(ctx context.Context, id int64) (ApiKey, error)
+	GetApiKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetCashbackCampaign(ctx context.Context, id int64) (CashbackCampaign, error)
+	GetCashbackCampaignForUpdate(ctx context.Context, id int64) (CashbackCampaign, error)
+	GetCashbackGrant(ctx context.Context, id int64) (CashbackGrant, error)
+	GetCashbackGrantByTransferForUpdate(ctx context.Context, transferID int64) (CashbackGrant, error)
+	GetCashbackUserTotal(ctx context.Context, arg GetCashbackUserTotalParams) (int64, error)
+	GetCheckoutSession(ctx context.Context, id int64) (CheckoutSession, error)
+	GetCheckoutSessionByReview(ctx context.Context, reviewID sql.NullInt64) (CheckoutSession, error)
+	GetCheckoutSessionByToken(ctx context.Context, token string) (CheckoutSession, error)
+	GetCheckoutSessionByTokenForUpdate(ctx context.Context, token string) (CheckoutSession, error)
+	GetDispute(ctx context.Context, id int64) (Dispute, error)
+	GetDisputeByTransfer(ctx context.Context, transferID int64) (Dispute, error)
+	GetDisputeEvidence(ctx context.Context, arg GetDisputeEvidenceParams) (DisputeEvidence, error)
+	GetDisputeForUpdate(ctx context.Context, id int64) (Dispute, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetEscrow(ctx context.Context, id int64) (Escrow, error)
+	GetEscrowForUpdate(ctx context.Context, id int64) (Escrow, error)
+	GetFeeSchedule(ctx context.Context, id int64) (FeeSchedule, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetInstallmentFundingOptIn(ctx context.Context, owner string) (InstallmentFundingOptIn, error)
+	GetInstallmentPlan(ctx context.Context, id int64) (InstallmentPlan, error)
+	GetInstallmentPlanForUpdate(ctx context.Context, id int64) (InstallmentPlan, error)
+	GetInvoice(ctx context.Context, id int64) (Invoice, error)
+	GetInvoiceByReview(ctx context.Context, reviewID sql.NullInt64) (Invoice, error)
+	GetInvoiceForUpdate(ctx context.Context, id int64) (Invoice, error)
+	GetJournal(ctx context.Context, id int64) (Journal, error)
+	GetLastChainCheckpoint(ctx context.Context) (ChainCheckpoint, error)
+	GetLastEntryID(ctx context.Context, walletID int64) (int64, error)
+	GetLastEntryIDBefore(ctx context.Context, before time.Time) (int64, error)
+	GetLedgerAccountBalance(ctx context.Context, accountID int64) (int64, error)
+	GetLimitIncreaseRequest(ctx context.Context, id int64) (LimitIncreaseRequest, error)
+	GetMerchantDisputeCounts(ctx context.Context, arg GetMerchantDisputeCountsParams) (GetMerchantDisputeCountsRow, error)
+	GetNextPayoutRow(ctx context.Context, batchID int64) (PayoutRow, error)
+	GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error)
+	GetPaymentLinkByToken(ctx context.Context, token string) (PaymentLink, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestByReview(ctx context.Context, reviewID sql.NullInt64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPayoutBatch(ctx context.Context, id int64) (PayoutBatch, error)
+	GetPayoutBatchForUpdate(ctx context.Context, id int64) (PayoutBatch, error)
+	GetPayoutRowByReview(ctx context.Context, reviewID sql.NullInt64) (PayoutRow, error)
+	GetPreviousEntryHash(ctx context.Context, arg GetPreviousEntryHashParams) (string, error)
+	GetRefund(ctx context.Context, id int64) (Refund, error)
+	GetRefundedAmount(ctx context.Context, transferID int64) (int64, error)
+	GetReserveRule(ctx context.Context, owner string) (ReserveRule, error)
+	GetReserveTotals(ctx context.Context, walletID int64) (GetReserveTotalsRow, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferRunByReview(ctx context.Context, reviewID sql.NullInt64) (ScheduledTransferRun, error)
+	GetSettlementPlan(ctx context.Context, owner string) (SettlementPlan, error)
+	GetSplitTransfer(ctx context.Context, id int64) (SplitTransfer, error)
+	GetSubscription(ctx context.Context, id int64) (Subscription, error)
+	GetSubscriptionForUpdate(ctx context.Context, id int64) (Subscription, error)
+	GetSubscriptionPlan(ctx context.Context, id int64) (SubscriptionPlan, error)
+	GetSystemLedgerAccount(ctx context.Context, arg GetSystemLedgerAccountParams) (LedgerAccount, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferLimits(ctx context.Context, owner string) (TransferLimit, error)
+	GetTransferReceivableForUpdate(ctx context.Context, arg GetTransferReceivableForUpdateParams) (Receivable, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error)
+	GetTrialBalance(ctx context.Context) ([]GetTrialBalanceRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByPixKey(ctx context.Context, key string) (User, error)
 	GetWallet(ctx context.Context, id int64) (Wallet, error)
+	GetWalletByOwnerAndCurrency(ctx context.Context, arg GetWalletByOwnerAndCurrencyParams) (Wallet, error)
 	GetWalletForUpdate(ctx context.Context, id int64) (Wallet, error)
+	GetWalletLedgerAccount(ctx context.Context, walletID sql.NullInt64) (LedgerAccount, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	GetWebhookEvent(ctx context.Context, id int64) (WebhookEvent, error)
+	IncrementWebhookEndpointFailures(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ListAllPayoutRows(ctx context.Context, batchID int64) ([]PayoutRow, error)
+	ListAnticipatableReceivables(ctx context.Context, arg ListAnticipatableReceivablesParams) ([]Receivable, error)
+	ListApiKeys(ctx context.Context, owner string) ([]ApiKey, error)
+	ListCashbackCampaigns(ctx context.Context, arg ListCashbackCampaignsParams) ([]CashbackCampaign, error)
+	ListChainCheckpoints(ctx context.Context, arg ListChainCheckpointsParams) ([]ChainCheckpoint, error)
+	ListChainEntries(ctx context.Context, arg ListChainEntriesParams) ([]Entry, error)
+	ListDisputeEvidence(ctx context.Context, disputeID int64) ([]DisputeEvidence, error)
+	ListDisputesByStatus(ctx context.Context, arg ListDisputesByStatusParams) ([]Dispute, error)
+	ListDueReceivableWallets(ctx context.Context, arg ListDueReceivableWalletsParams) ([]int64, error)
+	ListDueReserveWallets(ctx context.Context, arg ListDueReserveWalletsParams) ([]int64, error)
+	ListEligibleCashbackCampaigns(ctx context.Context, arg ListEligibleCashbackCampaignsParams) ([]CashbackCampaign, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error)
+	ListEntriesInRange(ctx context.Context, arg ListEntriesInRangeParams) ([]Entry, error)
+	ListFeeSchedules(ctx context.Context, arg ListFeeSchedulesParams) ([]FeeSchedule, error)
+	ListIncomingInvoices(ctx context.Context, arg ListIncomingInvoicesParams) ([]Invoice, error)
+	ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListInvoiceItems(ctx context.Context, invoiceID int64) ([]InvoiceItem, error)
+	ListJournalPostings(ctx context.Context, journalID int64) ([]Posting, error)
+	ListLimitIncreaseRequests(ctx context.Context, arg ListLimitIncreaseRequestsParams) ([]LimitIncreaseRequest, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOutgoingInvoices(ctx context.Context, arg ListOutgoingInvoicesParams) ([]Invoice, error)
+	ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListPayoutBatches(ctx context.Context, arg ListPayoutBatchesParams) ([]PayoutBatch, error)
+	ListPayoutRows(ctx context.Context, arg ListPayoutRowsParams) ([]PayoutRow, error)
+	ListPlanInstallments(ctx context.Context, planID int64) ([]Installment, error)
+	ListReceivableSchedule(ctx context.Context, walletID int64) ([]ListReceivableScheduleRow, error)
+	ListRecentChainCheckpoints(ctx context.Context, arg ListRecentChainCheckpointsParams) ([]ChainCheckpoint, error)
+	ListRefunds(ctx context.Context, transferID int64) ([]Refund, error)
+	ListRiskDecisions(ctx context.Context, arg ListRiskDecisionsParams) ([]RiskDecision, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListSplitTransferTransfers(ctx context.Context, splitTransferID sql.NullInt64) ([]Transfer, error)
+	ListSubscriptionCharges(ctx context.Context, subscriptionID int64) ([]SubscriptionCharge, error)
+	ListSystemLedgerAccounts(ctx context.Context) ([]LedgerAccount, error)
+	ListTransferFeeEntries(ctx context.Context, transferID sql.NullInt64) ([]Entry, error)
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUserDisputes(ctx context.Context, arg ListUserDisputesParams) ([]Dispute, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListWalletCashbackGrants(ctx context.Context, arg ListWalletCashbackGrantsParams) ([]CashbackGrant, error)
+	ListWalletCheckoutSessions(ctx context.Context, arg ListWalletCheckoutSessionsParams) ([]CheckoutSession, error)
+	ListWalletEscrows(ctx context.Context, arg ListWalletEscrowsParams) ([]Escrow, error)
+	ListWalletHolds(ctx context.Context, arg ListWalletHoldsParams) ([]Hold, error)
+	ListWalletInstallmentPlans(ctx context.Context, arg ListWalletInstallmentPlansParams) ([]InstallmentPlan, error)
+	ListWalletPaymentLinks(ctx context.Context, arg ListWalletPaymentLinksParams) ([]PaymentLink, error)
+	ListWalletReserves(ctx context.Context, arg ListWalletReservesParams) ([]Reserve, error)
+	ListWalletSubscriptionPlans(ctx context.Context, arg ListWalletSubscriptionPlansParams) ([]SubscriptionPlan, error)
+	ListWalletSubscriptions(ctx context.Context, arg ListWalletSubscriptionsParams) ([]Subscription, error)
 	ListWallets(ctx context.Context, arg ListWalletsParams) ([]Wallet, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhookDeliveryAttempt, error)
+	ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoint, error)
+	LockAnticipatableReceivables(ctx context.Context, arg LockAnticipatableReceivablesParams) ([]Receivable, error)
+	LockHeldReserves(ctx context.Context, walletID int64) ([]Reserve, error)
+	LockOpenInstallments(ctx context.Context, planID int64) ([]Installment, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) (WebhookDelivery, error)
+	MarkWebhookDeliverySucceeded(ctx context.Context, id int64) (WebhookDelivery, error)
+	NextInvoiceNumber(ctx context.Context, issuer string) (int64, error)
+	PayCashbackGrant(ctx context.Context, arg PayCashbackGrantParams) (CashbackGrant, error)
+	PayCheckoutSession(ctx context.Context, arg PayCheckoutSessionParams) (CheckoutSession, error)
+	PayInstallment(ctx context.Context, arg PayInstallmentParams) (Installment, error)
+	PayInvoice(ctx context.Context, arg PayInvoiceParams) (Invoice, error)
+	PayPaymentRequest(ctx context.Context, arg PayPaymentRequestParams) (PaymentRequest, error)
+	RecordPayoutReviewOutcome(ctx context.Context, arg RecordPayoutReviewOutcomeParams) (PayoutBatch, error)
+	RecordPayoutRowOutcome(ctx context.Context, arg RecordPayoutRowOutcomeParams) (PayoutBatch, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	RemindOverdueInvoices(ctx context.Context, arg RemindOverdueInvoicesParams) ([]Invoice, error)
+	RenewSubscription(ctx context.Context, arg RenewSubscriptionParams) (Subscription, error)
+	ResetWebhookEndpointFailures(ctx context.Context, id int64) error
+	RespondDispute(ctx context.Context, arg RespondDisputeParams) (Dispute, error)
+	RetryCashbackGrant(ctx context.Context, arg RetryCashbackGrantParams) (CashbackGrant, error)
+	RevokeApiKey(ctx context.Context, id int64) (ApiKey, error)
+	RotateApiKey(ctx context.Context, arg RotateApiKeyParams) (ApiKey, error)
+	SetEntryHash(ctx context.Context, arg SetEntryHashParams) (Entry, error)
+	SetPaymentRequestTransfer(ctx context.Context, arg SetPaymentRequestTransferParams) (PaymentRequest, error)
+	SetRiskDecisionTransfer(ctx context.Context, arg SetRiskDecisionTransferParams) error
+	SetSubscriptionCancelAtPeriodEnd(ctx context.Context, arg SetSubscriptionCancelAtPeriodEndParams) (Subscription, error)
+	SetTransferInvoice(ctx context.Context, arg SetTransferInvoiceParams) (Transfer, error)
+	SetTransferPaymentRequest(ctx context.Context, arg SetTransferPaymentRequestParams) (Transfer, error)
+	SetTransferSplitTransfer(ctx context.Context, arg SetTransferSplitTransferParams) (Transfer, error)
+	SettleEscrow(ctx context.Context, arg SettleEscrowParams) (Escrow, error)
+	SettleHold(ctx context.Context, arg SettleHoldParams) (Hold, error)
+	SettleScheduledTransferRun(ctx context.Context, arg SettleScheduledTransferRunParams) (ScheduledTransferRun, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (RateLimitBucket, error)
+	UpdateApiKeyLastUsed(ctx context.Context, id int64) error
+	UpdateInstallmentPlanStatus(ctx context.Context, arg UpdateInstallmentPlanStatusParams) (InstallmentPlan, error)
+	UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (Invoice, error)
+	UpdatePayoutBatchStatus(ctx context.Context, arg UpdatePayoutBatchStatusParams) (PayoutBatch, error)
+	UpdatePayoutRow(ctx context.Context, arg UpdatePayoutRowParams) (PayoutRow, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserMerchantCategory(ctx context.Context, arg UpdateUserMerchantCategoryParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateWallet(ctx context.Context, arg UpdateWalletParams) (Wallet, error)
+	UpdateWalletFrozen(ctx context.Context, arg UpdateWalletFrozenParams) (Wallet, error)
+	UpsertInstallmentFundingOptIn(ctx context.Context, arg UpsertInstallmentFundingOptInParams) (InstallmentFundingOptIn, error)
+	UpsertReserveRule(ctx context.Context, arg UpsertReserveRuleParams) (ReserveRule, error)
+	UpsertSettlementPlan(ctx context.Context, arg UpsertSettlementPlanParams) (SettlementPlan, error)
+	UpsertTransferLimits(ctx context.Context, arg UpsertTransferLimitsParams) (TransferLimit, error)
+	UseReserve(ctx context.Context, arg UseReserveParams) (Reserve, error)
+	VoidInvoice(ctx context.Context, arg VoidInvoiceParams) (Invoice, error)
 }
 
 var _ Querier = (*Queries)(nil)
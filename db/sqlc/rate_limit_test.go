@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeRateLimitToken(t *testing.T) {
+	arg := TakeRateLimitTokenParams{
+		Key:   util.RandomString(12),
+		Burst: 2,
+		// slow enough that nothing is refilled during the test
+		Rate: 0.0001,
+	}
+
+	bucket, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, bucket.Allowed)
+	require.InDelta(t, 1, bucket.Tokens, 0.01)
+
+	bucket, err = testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, bucket.Allowed)
+	require.InDelta(t, 0, bucket.Tokens, 0.01)
+
+	bucket, err = testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, bucket.Allowed)
+	require.InDelta(t, 0, bucket.Tokens, 0.01)
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createMerchantOnPlan creates a wallet whose owner settles settleDays after
+// each transfer received
+func createMerchantOnPlan(t *testing.T, currency string, settleDays int32, rate int64) Wallet {
+	merchant := createRandomWalletIn(t, currency)
+
+	plan, err := testQueries.UpsertSettlementPlan(context.Background(), UpsertSettlementPlanParams{
+		Owner:            merchant.Owner,
+		SettleDays:       settleDays,
+		AnticipationRate: rate,
+		UpdatedBy:        merchant.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, settleDays, plan.SettleDays)
+
+	return merchant
+}
+
+func TestTransferTxCreatesReceivable(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 1000)
+	merchant := createMerchantOnPlan(t, payer.Currency, 1, 0)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       400,
+	})
+	require.NoError(t, err)
+	require.Equal(t, merchant.Balance+400, result.ToWallet.Balance)
+	require.Equal(t, int64(400), result.ToWallet.ReceivableBalance)
+	require.Equal(t, merchant.Balance, result.ToWallet.AvailableBalance())
+
+	schedule, err := store.ListReceivableSchedule(context.Background(), merchant.ID)
+	require.NoError(t, err)
+	require.Len(t, schedule, 1)
+	require.Equal(t, int64(400), schedule[0].Amount)
+	require.Equal(t, settleAt(result.Transfer.CreatedAt, 1), schedule[0].SettleAt.UTC())
+
+	settled, err := store.SettleReceivablesTx(context.Background(), SettleReceivablesTxParams{
+		Now:       time.Now().Add(48 * time.Hour),
+		BatchSize: 1000,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, settled)
+
+	wallet, err := store.GetWallet(context.Background(), merchant.ID)
+	require.NoError(t, err)
+	require.Zero(t, wallet.ReceivableBalance)
+	require.Equal(t, result.ToWallet.Balance, wallet.AvailableBalance())
+}
+
+func TestTransferTxReceivableNetOfFees(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 1000)
+	merchant := createMerchantOnPlan(t, payer.Currency, 1, 0)
+	revenue := createRandomWalletIn(t, payer.Currency)
+
+	_, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: merchant.Owner,
+		Role:     util.MerchantRole,
+	})
+	require.NoError(t, err)
+
+	effectiveFrom := time.Now()
+	createRandomFeeSchedule(t, util.FeeMerchantReceipt, util.FeeUserMerchant, payer.Currency,
+		util.FeeRule{Kind: util.FeePercentage, BasisPoints: 200}, effectiveFrom)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1000,
+		Fees:         &FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner},
+	})
+	require.NoError(t, err)
+
+	// only what is left after the fee settles, the available balance is untouched
+	require.Equal(t, merchant.Balance+980, result.ToWallet.Balance)
+	require.Equal(t, int64(980), result.ToWallet.ReceivableBalance)
+	require.Equal(t, merchant.Balance, result.ToWallet.AvailableBalance())
+
+	schedule, err := store.ListReceivableSchedule(context.Background(), merchant.ID)
+	require.NoError(t, err)
+	require.Len(t, schedule, 1)
+	require.Equal(t, int64(980), schedule[0].Amount)
+}
+
+func TestAnticipateReceivablesTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 100000)
+	merchant := createMerchantOnPlan(t, payer.Currency, 30, 300)
+	revenue := createRandomWalletIn(t, payer.Currency)
+
+	for i := 0; i < 2; i++ {
+		_, err := store.TransferTx(context.Background(), TrasferTxParms{
+			FromWalletID: payer.ID,
+			ToWalletID:   merchant.ID,
+			Amount:       10000,
+		})
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	params := AnticipationParams{WalletID: merchant.ID, Until: now.AddDate(0, 2, 0), Now: now}
+
+	quote, err := store.QuoteAnticipation(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, quote.Receivables, 2)
+	require.Equal(t, int64(20000), quote.Amount)
+	require.Positive(t, quote.Fee)
+	require.LessOrEqual(t, quote.Fee, int64(600))
+	require.Equal(t, quote.Amount-quote.Fee, quote.Net)
+
+	result, err := store.AnticipateReceivablesTx(context.Background(), AnticipateReceivablesTxParams{
+		AnticipationParams: params,
+		RevenueOwner:       revenue.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, quote.Fee, result.Fee)
+	for _, receivable := range result.Receivables {
+		require.Equal(t, ReceivableAnticipated, receivable.Status)
+		require.True(t, receivable.SettledAt.Valid)
+	}
+	require.NotNil(t, result.FeeEntry)
+	require.Equal(t, -quote.Fee, result.FeeEntry.Amount)
+	require.Zero(t, result.Wallet.ReceivableBalance)
+	require.Equal(t, merchant.Balance+quote.Net, result.Wallet.AvailableBalance())
+
+	requireWalletLedgerBalance(t, result.Wallet)
+
+	revenue, err = store.GetWallet(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	requireWalletLedgerBalance(t, revenue)
+
+	_, err = store.AnticipateReceivablesTx(context.Background(), AnticipateReceivablesTxParams{AnticipationParams: params})
+	require.ErrorIs(t, err, ErrNoReceivables)
+}
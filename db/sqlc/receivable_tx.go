@@ -0,0 +1,377 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ReceivablePending     = "pending"
+	ReceivableSettled     = "settled"
+	ReceivableAnticipated = "anticipated"
+)
+
+// anticipationPeriod is the period the anticipation rate of a plan is charged over
+const anticipationPeriod = 30 * 24 * time.Hour
+
+// ErrNoReceivables is returned when anticipating a wallet with no pending
+// receivables up to the date asked for
+var ErrNoReceivables = errors.New("no pending receivables to anticipate")
+
+// settleAt returns when a receivable created at now settles on a plan.
+// Days start at midnight UTC
+func settleAt(now time.Time, settleDays int32) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, int(settleDays))
+}
+
+// scheduleSettlement turns the amount a wallet received into a receivable
+// when its owner is on a settlement plan that doesn't settle right away. The
+// amount stays in the balance but isn't available until it settles
+func scheduleSettlement(ctx context.Context, q *Queries, transfer Transfer, wallet Wallet, amount int64) (Wallet, error) {
+	if amount <= 0 {
+		return wallet, nil
+	}
+
+	plan, err := q.GetSettlementPlan(ctx, wallet.Owner)
+	if err == sql.ErrNoRows {
+		return wallet, nil
+	}
+	if err != nil || plan.SettleDays <= 0 {
+		return wallet, err
+	}
+
+	_, err = q.CreateReceivable(ctx, CreateReceivableParams{
+		WalletID:   wallet.ID,
+		TransferID: transfer.ID,
+		Amount:     amount,
+		SettleAt:   settleAt(transfer.CreatedAt, plan.SettleDays),
+	})
+	if err != nil {
+		return wallet, err
+	}
+
+	return q.AddWalletReceivableBalance(ctx, AddWalletReceivableBalanceParams{
+		Amount: amount,
+		ID:     wallet.ID,
+	})
+}
+
+// deductReceivableFee takes a fee the recipient of a transfer paid out of the
+// receivable the transfer scheduled, so only what is left after the fee
+// settles and the receivable balance never exceeds the balance it is part of.
+// The wallet must be locked already
+func deductReceivableFee(ctx context.Context, q *Queries, transfer Transfer, wallet Wallet, fee int64) (Wallet, error) {
+	receivable, err := q.GetTransferReceivableForUpdate(ctx, GetTransferReceivableForUpdateParams{
+		WalletID:   wallet.ID,
+		TransferID: transfer.ID,
+	})
+	if err == sql.ErrNoRows {
+		return wallet, nil
+	}
+	if err != nil {
+		return wallet, err
+	}
+
+	// what the receivable can't cover comes out of the available balance
+	if fee > receivable.Amount {
+		fee = receivable.Amount
+	}
+
+	_, err = q.DeductReceivable(ctx, DeductReceivableParams{
+		ID:     receivable.ID,
+		Amount: fee,
+	})
+	if err != nil {
+		return wallet, err
+	}
+
+	return q.AddWalletReceivableBalance(ctx, AddWalletReceivableBalanceParams{
+		Amount: -fee,
+		ID:     wallet.ID,
+	})
+}
+
+type SettleReceivablesTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// SettleReceivablesTx makes a batch of matured receivables available
+func (store *SQLStore) SettleReceivablesTx(ctx context.Context, arg SettleReceivablesTxParams) ([]Receivable, error) {
+	var receivables []Receivable
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		walletIDs, err := q.ListDueReceivableWallets(ctx, ListDueReceivableWalletsParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil || len(walletIDs) == 0 {
+			return err
+		}
+
+		if err := lockWalletIDs(ctx, q, walletIDs); err != nil {
+			return err
+		}
+
+		receivables, err = q.ClaimDueReceivables(ctx, ClaimDueReceivablesParams{
+			Now:       arg.Now,
+			WalletIds: walletIDs,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		settled := make(map[int64]int64)
+		for _, receivable := range receivables {
+			settled[receivable.WalletID] += receivable.Amount
+		}
+
+		for _, walletID := range walletIDs {
+			if settled[walletID] == 0 {
+				continue
+			}
+
+			_, err = q.AddWalletReceivableBalance(ctx, AddWalletReceivableBalanceParams{
+				Amount: -settled[walletID],
+				ID:     walletID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return receivables, err
+}
+
+type AnticipationParams struct {
+	WalletID int64     `json:"wallet_id"`
+	Until    time.Time `json:"until"`
+	Now      time.Time `json:"now"`
+}
+
+type AnticipationQuote struct {
+	Receivables []Receivable `json:"receivables"`
+	Amount      int64        `json:"amount"`
+	Fee         int64        `json:"fee"`
+	// Net is what becomes available, the amount minus the fee
+	Net int64 `json:"net"`
+}
+
+// anticipationFee charges the rate of the plan pro rata over the time left
+// until the receivable settles, counting started days as whole ones
+func anticipationFee(receivable Receivable, rate int64, now time.Time) int64 {
+	days := int64((receivable.SettleAt.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+	periodDays := int64(anticipationPeriod / (24 * time.Hour))
+
+	return receivable.Amount * rate * days / (periodDays * 10000)
+}
+
+// anticipationRate returns the anticipation rate of the plan of the owner,
+// owners on no plan are charged nothing
+func anticipationRate(ctx context.Context, q *Queries, owner string) (int64, error) {
+	plan, err := q.GetSettlementPlan(ctx, owner)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return plan.AnticipationRate, err
+}
+
+// quoteAnticipation adds up the receivables and the fees of settling them at now
+func quoteAnticipation(receivables []Receivable, rate int64, now time.Time) AnticipationQuote {
+	quote := AnticipationQuote{Receivables: receivables}
+
+	for i, receivable := range receivables {
+		fee := anticipationFee(receivable, rate, now)
+		quote.Receivables[i].AnticipationFee = fee
+		quote.Amount += receivable.Amount
+		quote.Fee += fee
+	}
+	quote.Net = quote.Amount - quote.Fee
+
+	return quote
+}
+
+// QuoteAnticipation returns what AnticipateReceivablesTx would settle and charge
+func (store *SQLStore) QuoteAnticipation(ctx context.Context, arg AnticipationParams) (AnticipationQuote, error) {
+	wallet, err := store.GetWallet(ctx, arg.WalletID)
+	if err != nil {
+		return AnticipationQuote{}, err
+	}
+
+	receivables, err := store.ListAnticipatableReceivables(ctx, ListAnticipatableReceivablesParams{
+		WalletID: wallet.ID,
+		Now:      arg.Now,
+		Until:    arg.Until,
+	})
+	if err != nil {
+		return AnticipationQuote{}, err
+	}
+
+	rate, err := anticipationRate(ctx, store.Queries, wallet.Owner)
+	if err != nil {
+		return AnticipationQuote{}, err
+	}
+
+	return quoteAnticipation(receivables, rate, arg.Now), nil
+}
+
+type AnticipateReceivablesTxParams struct {
+	AnticipationParams
+	// RevenueOwner owns the wallets anticipation fees are credited to, no
+	// fees are charged when empty
+	RevenueOwner string `json:"-"`
+}
+
+type AnticipateReceivablesTxResult struct {
+	AnticipationQuote
+	Wallet Wallet `json:"wallet"`
+	// FeeEntry is only set when a fee was charged
+	FeeEntry *Entry `json:"fee_entry,omitempty"`
+}
+
+// AnticipateReceivablesTx settles the pending receivables of a wallet up to
+// Until right away, charging the anticipation fee of its plan
+func (store *SQLStore) AnticipateReceivablesTx(ctx context.Context, arg AnticipateReceivablesTxParams) (AnticipateReceivablesTxResult, error) {
+	var result AnticipateReceivablesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		wallet, err := q.GetWallet(ctx, arg.WalletID)
+		if err != nil {
+			return err
+		}
+
+		var revenue Wallet
+		if arg.RevenueOwner != "" {
+			revenue, err = q.GetWalletByOwnerAndCurrency(ctx, GetWalletByOwnerAndCurrencyParams{
+				Owner:    arg.RevenueOwner,
+				Currency: wallet.Currency,
+			})
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: %s", ErrRevenueWalletNotFound, wallet.Currency)
+			}
+			if err != nil {
+				return err
+			}
+
+			_, err = lockWallets(ctx, q, wallet.ID, revenue.ID)
+		} else {
+			_, err = q.GetWalletForUpdate(ctx, wallet.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		receivables, err := q.LockAnticipatableReceivables(ctx, LockAnticipatableReceivablesParams{
+			WalletID: wallet.ID,
+			Now:      arg.Now,
+			Until:    arg.Until,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(receivables) == 0 {
+			return ErrNoReceivables
+		}
+
+		var rate int64
+		if arg.RevenueOwner != "" {
+			rate, err = anticipationRate(ctx, q, wallet.Owner)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.AnticipationQuote = quoteAnticipation(receivables, rate, arg.Now)
+
+		for i, receivable := range result.Receivables {
+			result.Receivables[i], err = q.AnticipateReceivable(ctx, AnticipateReceivableParams{
+				ID:              receivable.ID,
+				AnticipationFee: receivable.AnticipationFee,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Wallet, err = q.AddWalletReceivableBalance(ctx, AddWalletReceivableBalanceParams{
+			Amount: -result.Amount,
+			ID:     wallet.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Fee == 0 {
+			return nil
+		}
+
+		entry, err := chargeAnticipationFee(ctx, q, result.Wallet, revenue, result.Fee)
+		if err != nil {
+			return err
+		}
+
+		result.FeeEntry = &entry
+		result.Wallet, err = q.GetWallet(ctx, wallet.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// chargeAnticipationFee moves the fee from the wallet to the revenue wallet
+// as fee entries, both wallets must be locked already
+func chargeAnticipationFee(ctx context.Context, q *Queries, wallet Wallet, revenue Wallet, fee int64) (Entry, error) {
+	walletAccount, err := walletAccountID(ctx, q, wallet.ID)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	revenueAccount, err := walletAccountID(ctx, q, revenue.ID)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	_, err = postJournal(ctx, q, CreateJournalParams{
+		Kind:        JournalAnticipation,
+		Description: "receivables anticipation fee",
+	},
+		LedgerPosting{AccountID: walletAccount, Amount: fee},
+		LedgerPosting{AccountID: revenueAccount, Amount: -fee},
+	)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry, err := writeFeeEntry(ctx, q, CreateFeeEntryParams{
+		WalletID: wallet.ID,
+		Amount:   -fee,
+	})
+	if err != nil {
+		return entry, err
+	}
+
+	_, err = writeFeeEntry(ctx, q, CreateFeeEntryParams{
+		WalletID: revenue.ID,
+		Amount:   fee,
+	})
+	if err != nil {
+		return entry, err
+	}
+
+	if wallet.ID < revenue.ID {
+		_, _, err = addMoney(ctx, q, wallet.ID, -fee, revenue.ID, fee)
+	} else {
+		_, _, err = addMoney(ctx, q, revenue.ID, fee, wallet.ID, -fee)
+	}
+
+	return entry, err
+}
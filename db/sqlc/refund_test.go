@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+	admin := createRandomUser(t)
+
+	amount := int64(10 * 100)
+
+	transfer, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       amount,
+	})
+	require.NoError(t, err)
+
+	result, err := store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     amount / 2,
+		Reason:     "partial refund",
+		CreatedBy:  admin.Username,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, transfer.Transfer.ID, result.Refund.TransferID)
+	require.Equal(t, result.Transfer.ID, result.Refund.RefundTransferID)
+	require.Equal(t, amount/2, result.Refund.Amount)
+	require.Equal(t, wallet2.ID, result.Transfer.FromWalletID)
+	require.Equal(t, wallet1.ID, result.Transfer.ToWalletID)
+	require.Equal(t, -amount/2, result.FromEntry.Amount)
+	require.Equal(t, amount/2, result.ToEntry.Amount)
+
+	_, err = store.RefundTx(context.Background(), RefundTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     amount/2 + 1,
+		Reason:     "too much",
+		CreatedBy:  admin.Username,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	refunds, err := store.ListRefunds(context.Background(), transfer.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+
+	updatedWallet1, err := store.GetWallet(context.Background(), wallet1.ID)
+	require.NoError(t, err)
+	require.Equal(t, wallet1.Balance-amount/2, updatedWallet1.Balance)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"picpay_simplificado/util"
+)
+
+// ErrRefundExceedsTransfer is returned when a refund would give back more
+// than what is left of the original transfer
+var ErrRefundExceedsTransfer = errors.New("refund amount exceeds the refundable amount of the transfer")
+
+type RefundTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	Amount     int64  `json:"amount"`
+	Reason     string `json:"reason"`
+	CreatedBy  string `json:"created_by"`
+}
+
+type RefundTxResult struct {
+	Refund Refund `json:"refund"`
+	TrasferTxResult
+}
+
+// RefundTx gives back all or part of a transfer by moving the amount from
+// the recipient to the sender, in the same transaction that records the
+// refund. What a dispute gave back can't be refunded again, and transfers
+// with a dispute waiting for a decision can't be refunded at all. The
+// cashback the transfer earned is clawed back in proportion
+func (store *SQLStore) RefundTx(ctx context.Context, arg RefundTxParams) (RefundTxResult, error) {
+	var result RefundTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		refunded, err := q.GetRefundedAmount(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		disputed, err := disputedAmount(ctx, q, original.ID)
+		if err != nil {
+			return err
+		}
+
+		if arg.Amount <= 0 || refunded+disputed+arg.Amount > original.Amount {
+			return ErrRefundExceedsTransfer
+		}
+
+		grant, err := lockCashbackGrant(ctx, q, original.ID)
+		if err != nil {
+			return err
+		}
+
+		result.TrasferTxResult, err = transfer(ctx, q, TrasferTxParms{
+			FromWalletID: original.ToWalletID,
+			ToWalletID:   original.FromWalletID,
+			Amount:       arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Refund, err = q.CreateRefund(ctx, CreateRefundParams{
+			TransferID:       original.ID,
+			RefundTransferID: result.Transfer.ID,
+			Amount:           arg.Amount,
+			Reason:           arg.Reason,
+			CreatedBy:        arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		if grant != nil {
+			// what a dispute gave back was clawed back already
+			err = clawBackCashback(ctx, q, *grant, original, refunded+disputed+arg.Amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		return publishEvent(ctx, q, result.FromWallet.Owner, util.EventRefundCreated, result.Refund)
+	})
+
+	return result, err
+}
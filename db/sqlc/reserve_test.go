@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createMerchantWithReserve creates a wallet whose owner has rate withheld
+// from each transfer received for holdDays
+func createMerchantWithReserve(t *testing.T, currency string, rate int64, holdDays int32) Wallet {
+	merchant := createRandomWalletIn(t, currency)
+
+	rule, err := testQueries.UpsertReserveRule(context.Background(), UpsertReserveRuleParams{
+		Owner:     merchant.Owner,
+		Rate:      rate,
+		HoldDays:  holdDays,
+		UpdatedBy: "admin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, rate, rule.Rate)
+
+	return merchant
+}
+
+func TestTransferTxWithholdsReserve(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 1000)
+	merchant := createMerchantWithReserve(t, payer.Currency, 1000, 7)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       400,
+	})
+	require.NoError(t, err)
+	require.Equal(t, merchant.Balance+400, result.ToWallet.Balance)
+	require.Equal(t, int64(40), result.ToWallet.ReserveBalance)
+	require.Equal(t, merchant.Balance+360, result.ToWallet.AvailableBalance())
+
+	statement, err := store.ReserveStatement(context.Background(), ReserveStatementParams{
+		WalletID: merchant.ID,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(40), statement.Held)
+	require.Zero(t, statement.Released)
+	require.Len(t, statement.Reserves, 1)
+	require.Equal(t, ReserveHeld, statement.Reserves[0].Status)
+	require.WithinDuration(t, result.Transfer.CreatedAt.AddDate(0, 0, 7), statement.Reserves[0].ReleaseAt, time.Second)
+
+	released, err := store.ReleaseReservesTx(context.Background(), ReleaseReservesTxParams{
+		Now:       time.Now().AddDate(0, 0, 8),
+		BatchSize: 1000,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, released)
+
+	wallet, err := store.GetWallet(context.Background(), merchant.ID)
+	require.NoError(t, err)
+	require.Zero(t, wallet.ReserveBalance)
+	require.Equal(t, wallet.Balance, wallet.AvailableBalance())
+
+	statement, err = store.ReserveStatement(context.Background(), ReserveStatementParams{
+		WalletID: merchant.ID,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Zero(t, statement.Held)
+	require.Equal(t, int64(40), statement.Released)
+	require.Equal(t, ReserveReleased, statement.Reserves[0].Status)
+	require.True(t, statement.Reserves[0].ReleasedAt.Valid)
+}
+
+func TestDisputeReversalUsesReserve(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createFundedWallet(t, 1000)
+	merchant := createMerchantWithReserve(t, payer.Currency, 5000, 30)
+
+	_, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   merchant.ID,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+
+	paid, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: payer.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       800,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(400), paid.ToWallet.ReserveBalance)
+
+	dispute, err := store.OpenDisputeTx(context.Background(), OpenDisputeTxParams{
+		TransferID: paid.Transfer.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     600,
+		Reason:     "product never delivered",
+		RespondBy:  time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.RespondDisputeTx(context.Background(), RespondDisputeTxParams{
+		ID:       dispute.ID,
+		Response: "it was delivered",
+		Now:      time.Now(),
+	})
+	require.NoError(t, err)
+
+	// only the 400 left available and the 400 reserved cover the reversal
+	result, err := store.DecideDisputeTx(context.Background(), DecideDisputeTxParams{
+		ID:        dispute.ID,
+		PayerWins: true,
+		DecidedBy: "analyst",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(200), result.Reversal.FromWallet.Balance)
+	require.Zero(t, result.Reversal.FromWallet.ReserveBalance)
+
+	statement, err := store.ReserveStatement(context.Background(), ReserveStatementParams{
+		WalletID: merchant.ID,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(400), statement.Used)
+	require.Zero(t, statement.Held)
+}
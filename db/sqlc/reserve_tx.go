@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	ReserveHeld     = "held"
+	ReserveReleased = "released"
+)
+
+// withholdReserve keeps the rate of the reserve rule of the owner out of the
+// amount a wallet received, until the rule hold days are over. It returns how
+// much was withheld
+func withholdReserve(ctx context.Context, q *Queries, transfer Transfer, wallet Wallet) (Wallet, int64, error) {
+	rule, err := q.GetReserveRule(ctx, wallet.Owner)
+	if err == sql.ErrNoRows {
+		return wallet, 0, nil
+	}
+	if err != nil {
+		return wallet, 0, err
+	}
+
+	amount := transfer.Amount * rule.Rate / 10000
+	if amount <= 0 {
+		return wallet, 0, nil
+	}
+
+	_, err = q.CreateReserve(ctx, CreateReserveParams{
+		WalletID:   wallet.ID,
+		TransferID: transfer.ID,
+		Amount:     amount,
+		ReleaseAt:  transfer.CreatedAt.AddDate(0, 0, int(rule.HoldDays)),
+	})
+	if err != nil {
+		return wallet, 0, err
+	}
+
+	wallet, err = q.AddWalletReserveBalance(ctx, AddWalletReserveBalanceParams{
+		Amount: amount,
+		ID:     wallet.ID,
+	})
+
+	return wallet, amount, err
+}
+
+// useReserves takes up to amount from the held reserves of a locked wallet,
+// the ones released first are used first. It returns how much was used
+func useReserves(ctx context.Context, q *Queries, wallet Wallet, amount int64) (int64, error) {
+	if wallet.ReserveBalance <= 0 || amount <= 0 {
+		return 0, nil
+	}
+
+	reserves, err := q.LockHeldReserves(ctx, wallet.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var used int64
+	for _, reserve := range reserves {
+		if used == amount {
+			break
+		}
+
+		use := reserve.Amount - reserve.UsedAmount
+		if use > amount-used {
+			use = amount - used
+		}
+
+		_, err = q.UseReserve(ctx, UseReserveParams{
+			Amount: use,
+			ID:     reserve.ID,
+		})
+		if err != nil {
+			return used, err
+		}
+		used += use
+	}
+
+	_, err = q.AddWalletReserveBalance(ctx, AddWalletReserveBalanceParams{
+		Amount: -used,
+		ID:     wallet.ID,
+	})
+
+	return used, err
+}
+
+type ReleaseReservesTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// ReleaseReservesTx makes what is left of a batch of due reserves available
+func (store *SQLStore) ReleaseReservesTx(ctx context.Context, arg ReleaseReservesTxParams) ([]Reserve, error) {
+	var reserves []Reserve
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		walletIDs, err := q.ListDueReserveWallets(ctx, ListDueReserveWalletsParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil || len(walletIDs) == 0 {
+			return err
+		}
+
+		if err := lockWalletIDs(ctx, q, walletIDs); err != nil {
+			return err
+		}
+
+		reserves, err = q.ClaimDueReserves(ctx, ClaimDueReservesParams{
+			Now:       arg.Now,
+			WalletIds: walletIDs,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		released := make(map[int64]int64)
+		for _, reserve := range reserves {
+			released[reserve.WalletID] += reserve.Amount - reserve.UsedAmount
+		}
+
+		for _, walletID := range walletIDs {
+			if released[walletID] == 0 {
+				continue
+			}
+
+			_, err = q.AddWalletReserveBalance(ctx, AddWalletReserveBalanceParams{
+				Amount: -released[walletID],
+				ID:     walletID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return reserves, err
+}
+
+type ReserveStatementParams struct {
+	WalletID int64 `json:"wallet_id"`
+	Limit    int32 `json:"limit"`
+	Offset   int32 `json:"offset"`
+}
+
+type ReserveStatement struct {
+	WalletID int64 `json:"wallet_id"`
+	// Held is still withheld, Released went back to the available balance and
+	// Used covered chargebacks
+	Held     int64     `json:"held"`
+	Released int64     `json:"released"`
+	Used     int64     `json:"used"`
+	Reserves []Reserve `json:"reserves"`
+}
+
+// ReserveStatement returns the reserve totals of a wallet along with a page
+// of its reserves, newest first
+func (store *SQLStore) ReserveStatement(ctx context.Context, arg ReserveStatementParams) (ReserveStatement, error) {
+	statement := ReserveStatement{WalletID: arg.WalletID}
+
+	totals, err := store.GetReserveTotals(ctx, arg.WalletID)
+	if err != nil {
+		return statement, err
+	}
+
+	statement.Held = totals.Held
+	statement.Released = totals.Released
+	statement.Used = totals.Used
+
+	statement.Reserves, err = store.ListWalletReserves(ctx, ListWalletReservesParams{
+		WalletID: arg.WalletID,
+		Limit:    arg.Limit,
+		Offset:   arg.Offset,
+	})
+
+	return statement, err
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// ErrReviewNotPending is returned when deciding a review that was already decided
+var ErrReviewNotPending = errors.New("review was already decided")
+
+type DecideReviewTxParams struct {
+	ReviewID   int64  `json:"review_id"`
+	Approve    bool   `json:"approve"`
+	ReviewedBy string `json:"reviewed_by"`
+	Note       string `json:"note"`
+	// Fees and Cashback are applied to approved transfers like in TransferTx,
+	// as of when the review is decided
+	Fees     *FeeParams      `json:"-"`
+	Cashback *CashbackParams `json:"-"`
+}
+
+type DecideReviewTxResult struct {
+	Review TransferReview `json:"review"`
+	TrasferTxResult
+}
+
+// DecideReviewTx releases the amount held by a review. Approving pays the
+// recipient and creates the transfer, which is charged its fees and earns
+// its cashback like any other. The sender must still cover the fees, or
+// ErrInsufficientFunds is returned. Rejecting gives the amount back to the sender
+func (store *SQLStore) DecideReviewTx(ctx context.Context, arg DecideReviewTxParams) (DecideReviewTxResult, error) {
+	var result DecideReviewTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		review, err := q.GetTransferReviewForUpdate(ctx, arg.ReviewID)
+		if err != nil {
+			return err
+		}
+
+		if review.Status != ReviewPending {
+			return ErrReviewNotPending
+		}
+
+		payoutRow, err := lockPayoutRowReview(ctx, q, review)
+		if err != nil {
+			return err
+		}
+
+		decide := DecideTransferReviewParams{
+			ID:         review.ID,
+			Status:     ReviewRejected,
+			ReviewedBy: arg.ReviewedBy,
+			Note:       arg.Note,
+		}
+
+		if arg.Approve {
+			result.TrasferTxResult, err = releaseToRecipient(ctx, q, review, arg)
+			if err != nil {
+				return err
+			}
+
+			decide.Status = ReviewApproved
+			decide.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+			err = settlePaymentRequestReview(ctx, q, review, &result.Transfer)
+			if err != nil {
+				return err
+			}
+
+			err = settleCheckoutSessionReview(ctx, q, review, &result.Transfer)
+			if err != nil {
+				return err
+			}
+
+			err = settleInvoiceReview(ctx, q, review, &result.Transfer)
+			if err != nil {
+				return err
+			}
+
+			err = settleScheduledRunReview(ctx, q, review, &result.Transfer)
+			if err != nil {
+				return err
+			}
+
+			if payoutRow != nil {
+				err = settlePayoutRowReview(ctx, q, *payoutRow, &result.Transfer)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			result.FromEntry, err = writeEntry(ctx, q, CreateEntryParams{
+				WalletID: review.FromWalletID,
+				Amount:   review.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.FromWallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+				Amount: review.Amount,
+				ID:     review.FromWalletID,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = postSystemMovement(ctx, q, CreateJournalParams{
+				Kind:        JournalReviewReturn,
+				Description: fmt.Sprintf("review %d", review.ID),
+			}, result.FromWallet, AccountSuspense, review.Amount)
+			if err != nil {
+				return err
+			}
+
+			err = settlePaymentRequestReview(ctx, q, review, nil)
+			if err != nil {
+				return err
+			}
+
+			err = settleCheckoutSessionReview(ctx, q, review, nil)
+			if err != nil {
+				return err
+			}
+
+			err = settleInvoiceReview(ctx, q, review, nil)
+			if err != nil {
+				return err
+			}
+
+			err = settleScheduledRunReview(ctx, q, review, nil)
+			if err != nil {
+				return err
+			}
+
+			if payoutRow != nil {
+				err = settlePayoutRowReview(ctx, q, *payoutRow, nil)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		result.Review, err = q.DecideTransferReview(ctx, decide)
+
+		return err
+	})
+
+	return result, err
+}
+
+// releaseToRecipient completes a held transfer, the sender side was already
+// taken when the review was opened. The recipient side goes through the same
+// steps as any transfer received
+func releaseToRecipient(ctx context.Context, q *Queries, review TransferReview, arg DecideReviewTxParams) (TrasferTxResult, error) {
+	var result TrasferTxResult
+	var fees []FeeQuote
+
+	from, err := lockTransferWallets(ctx, q, review.FromWalletID, review.ToWalletID, arg.Fees)
+	if err != nil {
+		return result, err
+	}
+
+	params := TrasferTxParms{
+		FromWalletID: review.FromWalletID,
+		ToWalletID:   review.ToWalletID,
+		Amount:       review.Amount,
+		Fees:         arg.Fees,
+		Cashback:     arg.Cashback,
+	}
+
+	if arg.Fees != nil {
+		to, err := q.GetWallet(ctx, review.ToWalletID)
+		if err != nil {
+			return result, err
+		}
+
+		fees, err = quoteTransferFees(ctx, q, from, to, review.Amount, *arg.Fees)
+		if err != nil {
+			return result, err
+		}
+
+		// the amount is already out of the balance, only the fees are left to cover
+		if from.AvailableBalance() < senderFees(fees, from.ID) {
+			return result, ErrInsufficientFunds
+		}
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromWalletID: review.FromWalletID,
+		ToWalletID:   review.ToWalletID,
+		Amount:       review.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, result.ToWallet, err = receiveFromAccount(ctx, q, result.Transfer, CreateJournalParams{
+		Kind:        JournalTransfer,
+		Description: fmt.Sprintf("review %d", review.ID),
+	}, AccountSuspense)
+	if err != nil {
+		return result, err
+	}
+
+	result.FromWallet, err = q.GetWallet(ctx, review.FromWalletID)
+	if err != nil {
+		return result, err
+	}
+
+	err = q.SetRiskDecisionTransfer(ctx, SetRiskDecisionTransferParams{
+		ID:         review.DecisionID,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = completeTransfer(ctx, q, &result, params, fees)
+
+	return result, err
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Risk outcomes, from the least to the most severe
+const (
+	RiskAllow  = "allow"
+	RiskReview = "review"
+	RiskBlock  = "block"
+)
+
+// ErrTransferBlocked is returned when the risk rules block a transfer
+var ErrTransferBlocked = errors.New("transfer blocked by risk rules")
+
+// RiskAssessment is the verdict of a RiskEvaluator on a transfer
+type RiskAssessment struct {
+	Outcome string
+	Score   int32
+	Rules   []string
+	Version string
+}
+
+// RiskEvaluator scores transfers before they are made. It runs inside the
+// transfer transaction with both wallets locked, so what it reads can't
+// change before the transfer commits
+type RiskEvaluator interface {
+	EvaluateTransfer(ctx context.Context, q Querier, arg TrasferTxParms) (RiskAssessment, error)
+}
+
+// assessRisk evaluates the transfer and records the decision for auditing
+func assessRisk(ctx context.Context, q *Queries, arg TrasferTxParms) (RiskDecision, error) {
+	assessment, err := arg.Risk.EvaluateTransfer(ctx, q, arg)
+	if err != nil {
+		return RiskDecision{}, err
+	}
+
+	rules := assessment.Rules
+	if rules == nil {
+		rules = []string{}
+	}
+
+	return q.CreateRiskDecision(ctx, CreateRiskDecisionParams{
+		FromWalletID: arg.FromWalletID,
+		ToWalletID:   arg.ToWalletID,
+		Amount:       arg.Amount,
+		Outcome:      assessment.Outcome,
+		Score:        assessment.Score,
+		Rules:        rules,
+		RulesVersion: assessment.Version,
+	})
+}
+
+// holdForReview takes the amount from the sender without paying the
+// recipient, until an analyst decides the review. The limits were checked
+// before the transfer was held, and the review counts toward them from then
+// on, so approving it doesn't check them again
+func holdForReview(ctx context.Context, q *Queries, arg TrasferTxParms, decision RiskDecision) (TrasferTxResult, TransferReview, error) {
+	var result TrasferTxResult
+
+	review, err := q.CreateTransferReview(ctx, CreateTransferReviewParams{
+		DecisionID:   decision.ID,
+		FromWalletID: arg.FromWalletID,
+		ToWalletID:   arg.ToWalletID,
+		Amount:       arg.Amount,
+	})
+	if err != nil {
+		return result, review, err
+	}
+
+	result.FromEntry, err = writeEntry(ctx, q, CreateEntryParams{
+		WalletID: arg.FromWalletID,
+		Amount:   -arg.Amount,
+	})
+	if err != nil {
+		return result, review, err
+	}
+
+	result.FromWallet, err = q.AddWalletBalance(ctx, AddWalletBalanceParams{
+		Amount: -arg.Amount,
+		ID:     arg.FromWalletID,
+	})
+	if err != nil {
+		return result, review, err
+	}
+
+	// the amount waits in suspense until the review is decided
+	_, err = postSystemMovement(ctx, q, CreateJournalParams{
+		Kind:        JournalReviewHold,
+		Description: fmt.Sprintf("review %d", review.ID),
+	}, result.FromWallet, AccountSuspense, -arg.Amount)
+
+	return result, review, err
+}
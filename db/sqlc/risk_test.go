@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubEvaluator struct {
+	outcome string
+}
+
+func (evaluator stubEvaluator) EvaluateTransfer(ctx context.Context, q Querier, arg TrasferTxParms) (RiskAssessment, error) {
+	return RiskAssessment{
+		Outcome: evaluator.outcome,
+		Score:   10,
+		Rules:   []string{"stub"},
+		Version: "test",
+	}, nil
+}
+
+func TestTransferTxRiskAllow(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	result, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       10,
+		Risk:         stubEvaluator{outcome: RiskAllow},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, result.Transfer.ID)
+	require.NotNil(t, result.RiskDecision)
+	require.Nil(t, result.Review)
+	require.Equal(t, RiskAllow, result.RiskDecision.Outcome)
+	require.Equal(t, result.Transfer.ID, result.RiskDecision.TransferID.Int64)
+	require.Equal(t, []string{"stub"}, result.RiskDecision.Rules)
+}
+
+func TestTransferTxRiskBlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	_, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       10,
+		Risk:         stubEvaluator{outcome: RiskBlock},
+	})
+	require.ErrorIs(t, err, ErrTransferBlocked)
+
+	// the decision is kept even though the transfer was not made
+	decisions, err := store.ListRiskDecisions(context.Background(), ListRiskDecisionsParams{
+		FromWalletID: wallet1.ID,
+		Limit:        5,
+	})
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.Equal(t, RiskBlock, decisions[0].Outcome)
+	require.False(t, decisions[0].TransferID.Valid)
+
+	updatedWallet1, err := store.GetWallet(context.Background(), wallet1.ID)
+	require.NoError(t, err)
+	require.Equal(t, wallet1.Balance, updatedWallet1.Balance)
+}
+
+func TestTransferTxRiskReview(t *testing.T) {
+	store := NewStore(testDB)
+
+	for _, approve := range []bool{true, false} {
+		wallet1 := createRandomWallet(t)
+		wallet2 := createRandomWallet(t)
+
+		result, err := store.TransferTx(context.Background(), TrasferTxParms{
+			FromWalletID: wallet1.ID,
+			ToWalletID:   wallet2.ID,
+			Amount:       10,
+			Risk:         stubEvaluator{outcome: RiskReview},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, result.Review)
+		require.Zero(t, result.Transfer.ID)
+		require.Equal(t, ReviewPending, result.Review.Status)
+		require.Equal(t, wallet1.Balance-10, result.FromWallet.Balance)
+
+		decided, err := store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+			ReviewID:   result.Review.ID,
+			Approve:    approve,
+			ReviewedBy: "analyst",
+		})
+		require.NoError(t, err)
+
+		updatedWallet1, err := store.GetWallet(context.Background(), wallet1.ID)
+		require.NoError(t, err)
+		updatedWallet2, err := store.GetWallet(context.Background(), wallet2.ID)
+		require.NoError(t, err)
+
+		if approve {
+			require.Equal(t, ReviewApproved, decided.Review.Status)
+			require.Equal(t, decided.Transfer.ID, decided.Review.TransferID.Int64)
+			require.Equal(t, wallet1.Balance-10, updatedWallet1.Balance)
+			require.Equal(t, wallet2.Balance+10, updatedWallet2.Balance)
+		} else {
+			require.Equal(t, ReviewRejected, decided.Review.Status)
+			require.Equal(t, wallet1.Balance, updatedWallet1.Balance)
+			require.Equal(t, wallet2.Balance, updatedWallet2.Balance)
+		}
+
+		_, err = store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+			ReviewID: result.Review.ID,
+			Approve:  approve,
+		})
+		require.ErrorIs(t, err, ErrReviewNotPending)
+	}
+}
+
+func TestDecideReviewTxChargesFees(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.EUR)
+	merchant := createRandomMerchantWalletIn(t, util.EUR)
+	revenue := createRandomWalletIn(t, util.EUR)
+
+	deposit, err := store.AdjustWalletTx(context.Background(), AdjustWalletTxParams{
+		WalletID:   from.ID,
+		Amount:     2000,
+		SetBalance: true,
+		Account:    AccountBankSettlement,
+	})
+	require.NoError(t, err)
+	from = deposit.Wallet
+
+	effectiveFrom := time.Now()
+	createRandomFeeSchedule(t, util.FeeTransfer, util.FeeUserCustomer, util.EUR,
+		util.FeeRule{Kind: util.FeeFixed, FixedAmount: 10}, effectiveFrom)
+	createRandomFeeSchedule(t, util.FeeMerchantReceipt, util.FeeUserMerchant, util.EUR,
+		util.FeeRule{Kind: util.FeePercentage, BasisPoints: 200, MaxFee: 1000}, effectiveFrom)
+
+	fees := &FeeParams{Now: effectiveFrom.Add(time.Second), RevenueOwner: revenue.Owner}
+
+	held, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: from.ID,
+		ToWalletID:   merchant.ID,
+		Amount:       1000,
+		Risk:         stubEvaluator{outcome: RiskReview},
+		Fees:         fees,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, held.Review)
+	require.Empty(t, held.Fees)
+
+	decided, err := store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+		ReviewID:   held.Review.ID,
+		Approve:    true,
+		ReviewedBy: "analyst",
+		Fees:       fees,
+	})
+	require.NoError(t, err)
+	require.Len(t, decided.Fees, 2)
+
+	// an approved transfer costs the same as one that was never reviewed
+	require.Equal(t, int64(2000-1010), decided.FromWallet.Balance)
+	require.Equal(t, merchant.Balance+980, decided.ToWallet.Balance)
+
+	updatedRevenue, err := store.GetWallet(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	require.Equal(t, revenue.Balance+30, updatedRevenue.Balance)
+
+	requireWalletLedgerBalance(t, decided.FromWallet)
+	requireWalletLedgerBalance(t, decided.ToWallet)
+}
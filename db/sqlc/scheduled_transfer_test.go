@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, from, to Wallet, amount int64, frequency string, nextRunAt time.Time) ScheduledTransfer {
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:        from.Owner,
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       amount,
+		Frequency:    frequency,
+		DayOfMonth:   int32(nextRunAt.Day()),
+		NextRunAt:    nextRunAt,
+	})
+	require.NoError(t, err)
+
+	return scheduled
+}
+
+func executeParams(now time.Time) ExecuteScheduledTransferTxParams {
+	return ExecuteScheduledTransferTxParams{
+		Now:           now,
+		Location:      time.UTC,
+		MaxAttempts:   2,
+		RetryInterval: time.Hour,
+	}
+}
+
+func TestExecuteScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	nextRunAt := time.Date(1990, 1, 31, 9, 0, 0, 0, time.UTC)
+	scheduled := createRandomScheduledTransfer(t, wallet1, wallet2, 10, util.FrequencyMonthly, nextRunAt)
+	now := nextRunAt.Add(time.Minute)
+
+	result, err := store.ExecuteScheduledTransferTx(context.Background(), executeParams(now))
+	require.NoError(t, err)
+	require.Equal(t, scheduled.ID, result.ScheduledTransfer.ID)
+	require.Equal(t, ScheduledRunSucceeded, result.Run.Status)
+	require.Equal(t, result.Transfer.ID, result.Run.TransferID.Int64)
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.True(t, time.Date(1990, 2, 28, 9, 0, 0, 0, time.UTC).Equal(result.ScheduledTransfer.NextRunAt))
+
+	// the occurrence was done, nothing else is due
+	_, err = store.ExecuteScheduledTransferTx(context.Background(), executeParams(now))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+}
+
+func TestExecuteScheduledTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	nextRunAt := time.Date(1991, 3, 10, 9, 0, 0, 0, time.UTC)
+	createRandomScheduledTransfer(t, wallet1, wallet2, 10, util.FrequencyOnce, nextRunAt)
+
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ExecuteScheduledTransferTx(context.Background(), executeParams(nextRunAt))
+			errs <- err
+		}()
+	}
+
+	executed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			executed++
+			continue
+		}
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	require.Equal(t, 1, executed)
+
+	updatedWallet1, err := store.GetWallet(context.Background(), wallet1.ID)
+	require.NoError(t, err)
+	require.Equal(t, wallet1.Balance-10, updatedWallet1.Balance)
+}
+
+func TestExecuteScheduledTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	nextRunAt := time.Date(1992, 6, 1, 9, 0, 0, 0, time.UTC)
+	scheduled := createRandomScheduledTransfer(t, wallet1, wallet2, wallet1.Balance+1, util.FrequencyOnce, nextRunAt)
+
+	result, err := store.ExecuteScheduledTransferTx(context.Background(), executeParams(nextRunAt))
+	require.NoError(t, err)
+	require.Equal(t, scheduled.ID, result.ScheduledTransfer.ID)
+	require.Equal(t, ScheduledRunFailed, result.Run.Status)
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.Equal(t, int32(1), result.ScheduledTransfer.Attempts)
+	require.True(t, result.ScheduledTransfer.RetryAt.Valid)
+
+	// not due until the retry
+	_, err = store.ExecuteScheduledTransferTx(context.Background(), executeParams(nextRunAt))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err = store.ExecuteScheduledTransferTx(context.Background(), executeParams(nextRunAt.Add(time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, ScheduledRunFailed, result.Run.Status)
+	require.Equal(t, int32(2), result.Run.Attempt)
+	require.Equal(t, ScheduledTransferFailed, result.ScheduledTransfer.Status)
+
+	notifications, err := store.ListNotifications(context.Background(), ListNotificationsParams{
+		Owner: wallet1.Owner,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	require.Equal(t, util.NotificationScheduledTransferFailed, notifications[0].Type)
+}
+
+func TestScheduledRunReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	for _, approve := range []bool{true, false} {
+		wallet1 := createRandomWallet(t)
+		wallet2 := createRandomWallet(t)
+
+		nextRunAt := time.Date(1990, 3, 10, 9, 0, 0, 0, time.UTC)
+		scheduled := createRandomScheduledTransfer(t, wallet1, wallet2, 10, util.FrequencyOnce, nextRunAt)
+
+		arg := executeParams(nextRunAt.Add(time.Minute))
+		arg.Risk = stubEvaluator{outcome: RiskReview}
+
+		result, err := store.ExecuteScheduledTransferTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, scheduled.ID, result.ScheduledTransfer.ID)
+		require.Equal(t, ScheduledRunReview, result.Run.Status)
+		require.Equal(t, result.Review.ID, result.Run.ReviewID.Int64)
+
+		decided, err := store.DecideReviewTx(context.Background(), DecideReviewTxParams{
+			ReviewID:   result.Review.ID,
+			Approve:    approve,
+			ReviewedBy: "analyst",
+		})
+		require.NoError(t, err)
+
+		runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+			ScheduledTransferID: scheduled.ID,
+			Limit:               5,
+		})
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+
+		if approve {
+			require.Equal(t, ScheduledRunSucceeded, runs[0].Status)
+			require.Equal(t, decided.Transfer.ID, runs[0].TransferID.Int64)
+		} else {
+			require.Equal(t, ScheduledRunFailed, runs[0].Status)
+			require.False(t, runs[0].TransferID.Valid)
+			require.NotEmpty(t, runs[0].Error)
+		}
+	}
+}
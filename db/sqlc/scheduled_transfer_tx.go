@@ -0,0 +1,263 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferCompleted = "completed"
+	ScheduledTransferFailed    = "failed"
+	ScheduledTransferCancelled = "cancelled"
+)
+
+const (
+	ScheduledRunSucceeded = "succeeded"
+	ScheduledRunReview    = "review"
+	ScheduledRunFailed    = "failed"
+)
+
+var (
+	// ErrInsufficientFunds is returned when the sender balance can't cover the transfer
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrWalletFrozen is returned when one of the wallets of a transfer is frozen
+	ErrWalletFrozen = errors.New("wallet is frozen")
+	// errWalletNotFound is recorded when a wallet of a deferred transfer was deleted
+	errWalletNotFound = errors.New("wallet not found")
+)
+
+type ExecuteScheduledTransferTxParams struct {
+	Now time.Time
+	// Location is where monthly occurrences are computed
+	Location *time.Location
+	// MaxAttempts is how many times an occurrence is tried while funds are insufficient
+	MaxAttempts   int32
+	RetryInterval time.Duration
+	Limits        *TransferLimitsParams
+	Risk          RiskEvaluator
+	Fees          *FeeParams
+}
+
+type ExecuteScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+	TrasferTxResult
+}
+
+// ExecuteScheduledTransferTx claims one due scheduled transfer and runs it.
+// The claim skips rows locked by other instances, and the transfer, the run
+// and the next occurrence are committed together, so every occurrence is
+// transferred once. It returns sql.ErrNoRows when nothing is due
+func (store *SQLStore) ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error) {
+	var result ExecuteScheduledTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		scheduled, err := q.ClaimDueScheduledTransfer(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		attempt := scheduled.Attempts + 1
+
+		run := CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor:        scheduled.NextRunAt,
+			Attempt:             attempt,
+			Status:              ScheduledRunSucceeded,
+		}
+
+		result.TrasferTxResult, err = executeScheduledTransfer(ctx, q, scheduled, arg)
+		failure := err
+
+		switch {
+		case err == nil:
+			if result.Review != nil {
+				run.Status = ScheduledRunReview
+				run.ReviewID = sql.NullInt64{Int64: result.Review.ID, Valid: true}
+			} else {
+				run.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+			}
+		case isTransferFailure(err):
+			run.Status = ScheduledRunFailed
+			run.Error = err.Error()
+		default:
+			return err
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, run)
+		if err != nil {
+			return err
+		}
+
+		next := nextScheduledRun(scheduled, arg)
+
+		if run.Status == ScheduledRunFailed {
+			if errors.Is(failure, ErrInsufficientFunds) && attempt < arg.MaxAttempts {
+				next = AdvanceScheduledTransferParams{
+					ID:        scheduled.ID,
+					NextRunAt: scheduled.NextRunAt,
+					RetryAt:   sql.NullTime{Time: arg.Now.Add(arg.RetryInterval), Valid: true},
+					Status:    ScheduledTransferActive,
+					Attempts:  attempt,
+				}
+			} else if next.Status == ScheduledTransferCompleted {
+				next.Status = ScheduledTransferFailed
+			}
+			next.LastError = run.Error
+		}
+
+		result.ScheduledTransfer, err = q.AdvanceScheduledTransfer(ctx, next)
+		if err != nil {
+			return err
+		}
+
+		if run.Status != ScheduledRunFailed {
+			return nil
+		}
+
+		return notifyScheduledTransferFailed(ctx, q, result.ScheduledTransfer, result.Run)
+	})
+
+	return result, err
+}
+
+// executeScheduledTransfer makes the transfer once the wallets are locked
+// and can cover it
+func executeScheduledTransfer(ctx context.Context, q *Queries, scheduled ScheduledTransfer, arg ExecuteScheduledTransferTxParams) (TrasferTxResult, error) {
+	return checkedTransferTx(ctx, q, TrasferTxParms{
+		FromWalletID: scheduled.FromWalletID,
+		ToWalletID:   scheduled.ToWalletID,
+		Amount:       scheduled.Amount,
+		Limits:       arg.Limits,
+		Risk:         arg.Risk,
+		Fees:         arg.Fees,
+	})
+}
+
+// checkedTransferTx makes transfers nobody is waiting on, checking the
+// wallets exist, aren't frozen and can cover the amount, so the reason a
+// transfer failed can be recorded with isTransferFailure
+func checkedTransferTx(ctx context.Context, q *Queries, arg TrasferTxParms) (TrasferTxResult, error) {
+	var result TrasferTxResult
+
+	from, err := lockWallets(ctx, q, arg.FromWalletID, arg.ToWalletID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return result, errWalletNotFound
+		}
+		return result, err
+	}
+
+	to, err := q.GetWallet(ctx, arg.ToWalletID)
+	if err != nil {
+		return result, err
+	}
+
+	if from.IsFrozen || to.IsFrozen {
+		return result, ErrWalletFrozen
+	}
+
+	if from.AvailableBalance() < arg.Amount {
+		return result, ErrInsufficientFunds
+	}
+
+	result, blocked, err := transferTx(ctx, q, arg)
+	if err == nil && blocked {
+		err = ErrTransferBlocked
+	}
+
+	return result, err
+}
+
+// isTransferFailure reports whether the error is a reason for the transfer
+// to fail, instead of a problem running the transaction
+func isTransferFailure(err error) bool {
+	var limitErr *LimitExceededError
+
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrWalletFrozen) ||
+		errors.Is(err, ErrTransferBlocked) ||
+		errors.Is(err, errWalletNotFound) ||
+		errors.As(err, &limitErr)
+}
+
+// nextScheduledRun moves to the first occurrence after now, skipping the
+// ones that were missed, and completes the transfer when there is none left
+func nextScheduledRun(scheduled ScheduledTransfer, arg ExecuteScheduledTransferTxParams) AdvanceScheduledTransferParams {
+	next := AdvanceScheduledTransferParams{
+		ID:        scheduled.ID,
+		NextRunAt: scheduled.NextRunAt,
+		Status:    ScheduledTransferCompleted,
+	}
+
+	occurrence := scheduled.NextRunAt
+	for {
+		var repeats bool
+
+		occurrence, repeats = util.NextOccurrence(scheduled.Frequency, int(scheduled.DayOfMonth), occurrence, arg.Location)
+		if !repeats || (scheduled.EndAt.Valid && occurrence.After(scheduled.EndAt.Time)) {
+			return next
+		}
+
+		if occurrence.After(arg.Now) {
+			next.NextRunAt = occurrence
+			next.Status = ScheduledTransferActive
+			return next
+		}
+	}
+}
+
+// settleScheduledRunReview records the transfer of a run held for review
+// once approved, or the run as failed when rejected, letting the owner know
+func settleScheduledRunReview(ctx context.Context, q *Queries, review TransferReview, transfer *Transfer) error {
+	run, err := q.GetScheduledTransferRunByReview(ctx, sql.NullInt64{Int64: review.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	arg := SettleScheduledTransferRunParams{
+		ID:     run.ID,
+		Status: ScheduledRunFailed,
+		Error:  "rejected in risk review",
+	}
+
+	if transfer != nil {
+		arg = SettleScheduledTransferRunParams{
+			ID:         run.ID,
+			Status:     ScheduledRunSucceeded,
+			TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+		}
+	}
+
+	run, err = q.SettleScheduledTransferRun(ctx, arg)
+	if err != nil || run.Status != ScheduledRunFailed {
+		return err
+	}
+
+	scheduled, err := q.GetScheduledTransfer(ctx, run.ScheduledTransferID)
+	if err != nil {
+		return err
+	}
+
+	return notifyScheduledTransferFailed(ctx, q, scheduled, run)
+}
+
+func notifyScheduledTransferFailed(ctx context.Context, q *Queries, scheduled ScheduledTransfer, run ScheduledTransferRun) error {
+	message := fmt.Sprintf("Scheduled transfer %d failed: %s.", scheduled.ID, run.Error)
+	if scheduled.RetryAt.Valid {
+		message += " It will be tried again."
+	}
+
+	return notify(ctx, q, scheduled.Owner, util.NotificationScheduledTransferFailed, message, struct {
+		ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+		Run               ScheduledTransferRun `json:"run"`
+	}{scheduled, run})
+}
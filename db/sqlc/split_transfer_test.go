@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWalletIn(t *testing.T, currency string) Wallet {
+	wallet, err := NewStore(testDB).CreateWalletTx(context.Background(), CreateWalletTxParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  util.RandomMoney(),
+		Currency: currency,
+	})
+	require.NoError(t, err)
+
+	return wallet
+}
+
+func TestSplitTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.BRL)
+	seller := createRandomWalletIn(t, util.BRL)
+	platform := createRandomWalletIn(t, util.BRL)
+
+	amount := from.Balance / 2
+
+	result, err := store.SplitTransferTx(context.Background(), SplitTransferTxParams{
+		FromWalletID: from.ID,
+		Amount:       amount,
+		Description:  "order 42",
+		Recipients: []SplitRecipient{
+			{WalletID: seller.ID, SplitShare: util.SplitShare{BasisPoints: 9000}},
+			{WalletID: platform.ID, SplitShare: util.SplitShare{BasisPoints: 1000}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, amount, result.SplitTransfer.Amount)
+	require.Equal(t, from.Balance-amount, result.FromWallet.Balance)
+	require.Len(t, result.Transfers, 2)
+
+	var total int64
+	for _, leg := range result.Transfers {
+		require.Equal(t, result.SplitTransfer.ID, leg.Transfer.SplitTransferID.Int64)
+		total += leg.Transfer.Amount
+	}
+	require.Equal(t, amount, total)
+
+	transfers, err := store.ListSplitTransferTransfers(context.Background(), result.Transfers[0].Transfer.SplitTransferID)
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+}
+
+func TestSplitTransferTxAllOrNothing(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createRandomWalletIn(t, util.BRL)
+	seller := createRandomWalletIn(t, util.BRL)
+	foreign := createRandomWalletIn(t, util.USD)
+
+	_, err := store.SplitTransferTx(context.Background(), SplitTransferTxParams{
+		FromWalletID: from.ID,
+		Amount:       10,
+		Recipients: []SplitRecipient{
+			{WalletID: seller.ID, SplitShare: util.SplitShare{Amount: 5}},
+			{WalletID: foreign.ID, SplitShare: util.SplitShare{Amount: 5}},
+		},
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = store.SplitTransferTx(context.Background(), SplitTransferTxParams{
+		FromWalletID: from.ID,
+		Amount:       from.Balance + 1,
+		Recipients: []SplitRecipient{
+			{WalletID: seller.ID, SplitShare: util.SplitShare{Amount: 1}},
+			{WalletID: seller.ID, SplitShare: util.SplitShare{Amount: from.Balance}},
+		},
+	})
+	require.ErrorIs(t, err, util.ErrInvalidSplit)
+
+	unchanged, err := store.GetWallet(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, unchanged.Balance)
+}
+
+func TestSplitTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallets := []Wallet{
+		createRandomWalletIn(t, util.EUR),
+		createRandomWalletIn(t, util.EUR),
+		createRandomWalletIn(t, util.EUR),
+	}
+
+	n := 9
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		from := wallets[i%3]
+		to1 := wallets[(i+1)%3]
+		to2 := wallets[(i+2)%3]
+
+		go func() {
+			_, err := store.SplitTransferTx(context.Background(), SplitTransferTxParams{
+				FromWalletID: from.ID,
+				Amount:       2,
+				Recipients: []SplitRecipient{
+					{WalletID: to1.ID, SplitShare: util.SplitShare{Amount: 1}},
+					{WalletID: to2.ID, SplitShare: util.SplitShare{Amount: 1}},
+				},
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	// every wallet sent 3 splits of 2 and received 6 transfers of 1
+	for _, wallet := range wallets {
+		updated, err := store.GetWallet(context.Background(), wallet.ID)
+		require.NoError(t, err)
+		require.Equal(t, wallet.Balance, updated.Balance)
+	}
+}
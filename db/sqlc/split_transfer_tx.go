@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+)
+
+// ErrCurrencyMismatch is returned when the wallets of a split don't share the currency
+var ErrCurrencyMismatch = errors.New("wallets must share the currency")
+
+// SplitRecipient is a wallet paid by a split transfer and its share
+type SplitRecipient struct {
+	WalletID int64 `json:"wallet_id"`
+	util.SplitShare
+}
+
+type SplitTransferTxParams struct {
+	FromWalletID int64            `json:"from_wallet_id"`
+	Amount       int64            `json:"amount"`
+	Description  string           `json:"description"`
+	Recipients   []SplitRecipient `json:"recipients"`
+	// Limits are checked against the total of the split when set
+	Limits *TransferLimitsParams `json:"-"`
+	// Risk evaluates the transfer to every recipient when set
+	Risk RiskEvaluator `json:"-"`
+	// Fees are charged on the transfer to every recipient when set
+	Fees *FeeParams `json:"-"`
+}
+
+type SplitTransferTxResult struct {
+	SplitTransfer SplitTransfer     `json:"split_transfer"`
+	FromWallet    Wallet            `json:"from_wallet"`
+	Transfers     []TrasferTxResult `json:"transfers"`
+	// RiskDecisions are only set when the transfers were evaluated
+	RiskDecisions []RiskDecision `json:"risk_decisions,omitempty"`
+}
+
+// SplitTransferTx pays several recipients from one wallet, all or nothing.
+// The amount is allocated with util.AllocateSplit and every recipient gets
+// its own transfer, linked to the split. Splits can't be held for review, so
+// a transfer the risk rules would review blocks the whole split, returning
+// ErrTransferBlocked
+func (store *SQLStore) SplitTransferTx(ctx context.Context, arg SplitTransferTxParams) (SplitTransferTxResult, error) {
+	var result SplitTransferTxResult
+	var blocked bool
+
+	shares := make([]util.SplitShare, len(arg.Recipients))
+	for i, recipient := range arg.Recipients {
+		shares[i] = recipient.SplitShare
+	}
+
+	amounts, err := util.AllocateSplit(arg.Amount, shares)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		wallets, err := lockSplitWallets(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		from := wallets[arg.FromWalletID]
+
+		fees := make([][]FeeQuote, len(arg.Recipients))
+		var totalFees int64
+
+		if arg.Fees != nil {
+			for i, recipient := range arg.Recipients {
+				fees[i], err = quoteTransferFees(ctx, q, from, wallets[recipient.WalletID], amounts[i], *arg.Fees)
+				if err != nil {
+					return err
+				}
+
+				totalFees += senderFees(fees[i], from.ID)
+			}
+		}
+
+		if from.AvailableBalance() < arg.Amount+totalFees {
+			return ErrInsufficientFunds
+		}
+
+		if arg.Limits != nil {
+			if err := checkTransferLimits(ctx, q, from, arg.Amount, *arg.Limits); err != nil {
+				return err
+			}
+		}
+
+		legs := make([]TrasferTxParms, len(arg.Recipients))
+		for i, recipient := range arg.Recipients {
+			legs[i] = TrasferTxParms{
+				FromWalletID: arg.FromWalletID,
+				ToWalletID:   recipient.WalletID,
+				Amount:       amounts[i],
+			}
+		}
+
+		// every leg is evaluated before any money moves
+		if arg.Risk != nil {
+			for _, leg := range legs {
+				leg.Risk = arg.Risk
+
+				decision, err := assessRisk(ctx, q, leg)
+				if err != nil {
+					return err
+				}
+
+				result.RiskDecisions = append(result.RiskDecisions, decision)
+				if decision.Outcome != RiskAllow {
+					blocked = true
+				}
+			}
+
+			if blocked {
+				return nil
+			}
+		}
+
+		result.SplitTransfer, err = q.CreateSplitTransfer(ctx, CreateSplitTransferParams{
+			FromWalletID: arg.FromWalletID,
+			Amount:       arg.Amount,
+			Description:  arg.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		splitID := sql.NullInt64{Int64: result.SplitTransfer.ID, Valid: true}
+
+		for i, leg := range legs {
+			transferResult, err := transfer(ctx, q, leg)
+			if err != nil {
+				return err
+			}
+
+			if len(fees[i]) > 0 {
+				charges, charged, err := chargeFees(ctx, q, transferResult.Transfer, from.Currency, fees[i], *arg.Fees)
+				if err != nil {
+					return err
+				}
+
+				if wallet, ok := charged[leg.FromWalletID]; ok {
+					transferResult.FromWallet = wallet
+				}
+				if wallet, ok := charged[leg.ToWalletID]; ok {
+					transferResult.ToWallet = wallet
+				}
+				transferResult.Fees = charges
+			}
+
+			transferResult.Transfer, err = q.SetTransferSplitTransfer(ctx, SetTransferSplitTransferParams{
+				ID:              transferResult.Transfer.ID,
+				SplitTransferID: splitID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if result.RiskDecisions != nil {
+				decision := &result.RiskDecisions[i]
+				decision.TransferID = sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true}
+
+				err = q.SetRiskDecisionTransfer(ctx, SetRiskDecisionTransferParams{
+					ID:         decision.ID,
+					TransferID: decision.TransferID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			result.FromWallet = transferResult.FromWallet
+			result.Transfers = append(result.Transfers, transferResult)
+		}
+
+		return nil
+	})
+
+	if err == nil && blocked {
+		err = ErrTransferBlocked
+	}
+
+	return result, err
+}
+
+// lockSplitWallets locks every wallet of the split in ID order, the same
+// order TransferTx uses, so concurrent transfers can't deadlock
+func lockSplitWallets(ctx context.Context, q *Queries, arg SplitTransferTxParams) (map[int64]Wallet, error) {
+	ids := []int64{arg.FromWalletID}
+	for _, recipient := range arg.Recipients {
+		ids = append(ids, recipient.WalletID)
+	}
+
+	wallets := make(map[int64]Wallet, len(ids))
+	for _, id := range ids {
+		if _, ok := wallets[id]; ok {
+			return nil, fmt.Errorf("%w: wallet [%d] appears more than once", util.ErrInvalidSplit, id)
+		}
+		wallets[id] = Wallet{}
+	}
+
+	// lockWalletIDs sorts the ids it is given, so it gets a copy
+	lockIDs := append([]int64{}, ids...)
+	if arg.Fees != nil {
+		revenueID, ok, err := revenueWalletID(ctx, q, arg.FromWalletID, *arg.Fees)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			lockIDs = append(lockIDs, revenueID)
+		}
+	}
+
+	// the revenue wallet fees go to is locked in the same pass, in ID order
+	if err := lockWalletIDs(ctx, q, lockIDs); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		wallet, err := q.GetWallet(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		wallets[id] = wallet
+	}
+
+	currency := wallets[arg.FromWalletID].Currency
+	for _, wallet := range wallets {
+		if wallet.IsFrozen {
+			return nil, fmt.Errorf("%w: wallet [%d]", ErrWalletFrozen, wallet.ID)
+		}
+		if wallet.Currency != currency {
+			return nil, fmt.Errorf("%w: wallet [%d] is in %s", ErrCurrencyMismatch, wallet.ID, wallet.Currency)
+		}
+	}
+
+	return wallets, nil
+}
@@ -4,12 +4,69 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"picpay_simplificado/util"
+	"time"
 )
 
 // Store provides all db queries and transctions
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TrasferTxParms) (TrasferTxResult, error)
+	RefundTx(ctx context.Context, arg RefundTxParams) (RefundTxResult, error)
+	FreezeWalletTx(ctx context.Context, arg FreezeWalletTxParams) (Wallet, error)
+	RequestLimitChangeTx(ctx context.Context, arg RequestLimitChangeTxParams) (LimitIncreaseRequest, error)
+	ApplyLimitIncreasesTx(ctx context.Context, arg ApplyLimitIncreasesTxParams) ([]LimitIncreaseRequest, error)
+	DecideReviewTx(ctx context.Context, arg DecideReviewTxParams) (DecideReviewTxResult, error)
+	ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error)
+	AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error)
+	DecidePaymentRequestTx(ctx context.Context, arg DecidePaymentRequestTxParams) (PaymentRequest, error)
+	SplitTransferTx(ctx context.Context, arg SplitTransferTxParams) (SplitTransferTxResult, error)
+	CreatePayoutBatchTx(ctx context.Context, arg CreatePayoutBatchTxParams) (CreatePayoutBatchTxResult, error)
+	ExecutePayoutRowTx(ctx context.Context, arg ExecutePayoutRowTxParams) (ExecutePayoutRowTxResult, error)
+	UpdatePayoutBatchTx(ctx context.Context, arg UpdatePayoutBatchTxParams) (PayoutBatch, error)
+	QuoteTransferFees(ctx context.Context, arg QuoteTransferFeesParams) (QuoteTransferFeesResult, error)
+	CreateWalletTx(ctx context.Context, arg CreateWalletTxParams) (Wallet, error)
+	AdjustWalletTx(ctx context.Context, arg AdjustWalletTxParams) (AdjustWalletTxResult, error)
+	TrialBalance(ctx context.Context) (TrialBalanceResult, error)
+	CreateChainCheckpointTx(ctx context.Context, arg CreateChainCheckpointTxParams) (ChainCheckpoint, error)
+	VerifyChain(ctx context.Context, arg VerifyChainParams) (VerifyChainResult, error)
+	CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	VoidHoldTx(ctx context.Context, id int64) (Hold, error)
+	ExpireHoldsTx(ctx context.Context, arg ExpireHoldsTxParams) ([]Hold, error)
+	CreateEscrowTx(ctx context.Context, arg CreateEscrowTxParams) (CreateEscrowTxResult, error)
+	ConfirmEscrowTx(ctx context.Context, id int64) (EscrowTxResult, error)
+	AgreeEscrowRefundTx(ctx context.Context, arg AgreeEscrowRefundTxParams) (EscrowTxResult, error)
+	DisputeEscrowTx(ctx context.Context, arg DisputeEscrowTxParams) (Escrow, error)
+	ResolveEscrowTx(ctx context.Context, arg ResolveEscrowTxParams) (EscrowTxResult, error)
+	ReleaseDueEscrowsTx(ctx context.Context, arg ReleaseDueEscrowsTxParams) ([]Escrow, error)
+	OpenDisputeTx(ctx context.Context, arg OpenDisputeTxParams) (Dispute, error)
+	RespondDisputeTx(ctx context.Context, arg RespondDisputeTxParams) (Dispute, error)
+	EscalateOverdueDisputesTx(ctx context.Context, arg EscalateOverdueDisputesTxParams) ([]Dispute, error)
+	DecideDisputeTx(ctx context.Context, arg DecideDisputeTxParams) (DecideDisputeTxResult, error)
+	MerchantDisputeRate(ctx context.Context, merchant string, since time.Time) (MerchantDisputeRate, error)
+	SettleReceivablesTx(ctx context.Context, arg SettleReceivablesTxParams) ([]Receivable, error)
+	QuoteAnticipation(ctx context.Context, arg AnticipationParams) (AnticipationQuote, error)
+	AnticipateReceivablesTx(ctx context.Context, arg AnticipateReceivablesTxParams) (AnticipateReceivablesTxResult, error)
+	ReleaseReservesTx(ctx context.Context, arg ReleaseReservesTxParams) ([]Reserve, error)
+	ReserveStatement(ctx context.Context, arg ReserveStatementParams) (ReserveStatement, error)
+	CreateInstallmentPlanTx(ctx context.Context, arg CreateInstallmentPlanTxParams) (CreateInstallmentPlanTxResult, error)
+	CollectInstallmentTx(ctx context.Context, arg CollectInstallmentTxParams) (CollectInstallmentTxResult, error)
+	PrepayInstallmentPlanTx(ctx context.Context, arg PrepayInstallmentPlanTxParams) (PrepayInstallmentPlanTxResult, error)
+	CancelInstallmentPlanTx(ctx context.Context, id int64) (CancelInstallmentPlanTxResult, error)
+	PayCheckoutSessionTx(ctx context.Context, arg PayCheckoutSessionTxParams) (PayCheckoutSessionTxResult, error)
+	ExpireCheckoutSessionsTx(ctx context.Context, arg ExpireCheckoutSessionsTxParams) ([]CheckoutSession, error)
+	CreateSubscriptionTx(ctx context.Context, arg CreateSubscriptionTxParams) (CreateSubscriptionTxResult, error)
+	RenewSubscriptionTx(ctx context.Context, arg RenewSubscriptionTxParams) (RenewSubscriptionTxResult, error)
+	ChangeSubscriptionPlanTx(ctx context.Context, arg ChangeSubscriptionPlanTxParams) (ChangeSubscriptionPlanTxResult, error)
+	CancelSubscriptionTx(ctx context.Context, arg CancelSubscriptionTxParams) (Subscription, error)
+	CreateInvoiceTx(ctx context.Context, arg CreateInvoiceTxParams) (InvoiceTxResult, error)
+	UpdateInvoiceTx(ctx context.Context, arg UpdateInvoiceTxParams) (InvoiceTxResult, error)
+	FinalizeInvoiceTx(ctx context.Context, arg FinalizeInvoiceTxParams) (InvoiceTxResult, error)
+	VoidInvoiceTx(ctx context.Context, arg VoidInvoiceTxParams) (Invoice, error)
+	PayInvoiceTx(ctx context.Context, arg PayInvoiceTxParams) (PayInvoiceTxResult, error)
+	RemindOverdueInvoicesTx(ctx context.Context, arg RemindOverdueInvoicesTxParams) ([]Invoice, error)
+	SettleCashbackGrantTx(ctx context.Context, arg SettleCashbackGrantTxParams) (CashbackGrant, error)
 }
 
 // SQLStore provides all SQL queries and transctions
@@ -50,6 +107,14 @@ type TrasferTxParms struct {
 	FromWalletID int64 `json:"from_wallet_id"`
 	ToWalletID   int64 `json:"to_wallet_id"`
 	Amount       int64 `json:"amount"`
+	// Limits are checked against the sender when set
+	Limits *TransferLimitsParams `json:"-"`
+	// Risk evaluates the transfer before it is made when set
+	Risk RiskEvaluator `json:"-"`
+	// Fees are charged on the transfer when set
+	Fees *FeeParams `json:"-"`
+	// Cashback is granted on the transfer when set
+	Cashback *CashbackParams `json:"-"`
 }
 
 type TrasferTxResult struct {
@@ -58,50 +123,259 @@ type TrasferTxResult struct {
 	ToWallet   Wallet   `json:"to_wallet"`
 	FromEntry  Entry    `json:"from_entry"`
 	ToEntry    Entry    `json:"to_entry"`
+	// RiskDecision and Review are only set when the transfer was evaluated
+	RiskDecision *RiskDecision   `json:"risk_decision,omitempty"`
+	Review       *TransferReview `json:"review,omitempty"`
+	// Fees are only set when fees were charged on the transfer
+	Fees []FeeCharge `json:"fees,omitempty"`
+	// Cashback is only set when the transfer earned cashback
+	Cashback *CashbackGrant `json:"cashback,omitempty"`
 }
 
+// TransferTx moves money between two wallets. When risk evaluation is on, a
+// blocked transfer returns ErrTransferBlocked and a transfer sent to review
+// only takes the amount from the sender, with Review set in the result. When
+// fees are on, they are charged once the transfer is made. The available
+// balance of the sender must cover the amount and its fees, or
+// ErrInsufficientFunds is returned
 func (store *SQLStore) TransferTx(ctx context.Context, arg TrasferTxParms) (TrasferTxResult, error) {
 	var result TrasferTxResult
+	var blocked bool
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, blocked, err = transferTx(ctx, q, arg)
+		return err
+	})
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromWalletID: arg.FromWalletID,
-			ToWalletID:   arg.ToWalletID,
-			Amount:       arg.Amount,
-		})
-		if err != nil {
-			return err
+	if err == nil && blocked {
+		err = ErrTransferBlocked
+	}
+
+	return result, err
+}
+
+// transferTx checks the limits and the risk of the transfer before making it.
+// A blocked transfer is reported without an error, so the risk decision can
+// still be committed
+func transferTx(ctx context.Context, q *Queries, arg TrasferTxParms) (result TrasferTxResult, blocked bool, err error) {
+	var fees []FeeQuote
+
+	from, err := lockTransferWallets(ctx, q, arg.FromWalletID, arg.ToWalletID, arg.Fees)
+	if err != nil {
+		return result, false, err
+	}
+
+	if arg.Limits != nil {
+		if err := checkTransferLimits(ctx, q, from, arg.Amount, *arg.Limits); err != nil {
+			return result, false, err
 		}
+	}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			WalletID: arg.FromWalletID,
-			Amount:   -arg.Amount,
-		})
+	if arg.Fees != nil {
+		fees, err = quoteFeesFrom(ctx, q, from, arg)
 		if err != nil {
-			return err
+			return result, false, err
 		}
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			WalletID: arg.ToWalletID,
-			Amount:   arg.Amount,
-		})
+	}
+
+	if from.AvailableBalance() < arg.Amount+senderFees(fees, from.ID) {
+		return result, false, ErrInsufficientFunds
+	}
+
+	if arg.Risk == nil {
+		result, err = transferWithFees(ctx, q, arg, fees)
+		return result, false, err
+	}
+
+	decision, err := assessRisk(ctx, q, arg)
+	if err != nil {
+		return result, false, err
+	}
+
+	switch decision.Outcome {
+	case RiskBlock:
+		result.RiskDecision = &decision
+		return result, true, nil
+	case RiskReview:
+		var review TransferReview
+
+		result, review, err = holdForReview(ctx, q, arg, decision)
+		result.RiskDecision = &decision
+		result.Review = &review
+		return result, false, err
+	}
+
+	result, err = transferWithFees(ctx, q, arg, fees)
+	if err != nil {
+		return result, false, err
+	}
+
+	decision.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+	result.RiskDecision = &decision
+
+	err = q.SetRiskDecisionTransfer(ctx, SetRiskDecisionTransferParams{
+		ID:         decision.ID,
+		TransferID: decision.TransferID,
+	})
+
+	return result, false, err
+}
+
+// quoteFeesFrom quotes the fees of the transfer once the sender is locked
+func quoteFeesFrom(ctx context.Context, q *Queries, from Wallet, arg TrasferTxParms) ([]FeeQuote, error) {
+	to, err := q.GetWallet(ctx, arg.ToWalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	return quoteTransferFees(ctx, q, from, to, arg.Amount, *arg.Fees)
+}
+
+// transferWithFees makes the transfer, charges its fees and grants its
+// cashback
+func transferWithFees(ctx context.Context, q *Queries, arg TrasferTxParms, fees []FeeQuote) (TrasferTxResult, error) {
+	result, err := transfer(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
+
+	err = completeTransfer(ctx, q, &result, arg, fees)
+
+	return result, err
+}
+
+// completeTransfer charges the fees of a transfer already made and grants
+// its cashback. Transfers held for review go through it once approved
+func completeTransfer(ctx context.Context, q *Queries, result *TrasferTxResult, arg TrasferTxParms, fees []FeeQuote) error {
+	if len(fees) > 0 {
+		charges, wallets, err := chargeFees(ctx, q, result.Transfer, result.FromWallet.Currency, fees, *arg.Fees)
 		if err != nil {
 			return err
 		}
 
-		if arg.FromWalletID < arg.ToWalletID {
-			result.FromWallet, result.ToWallet, err = addMoney(ctx, q, arg.FromWalletID, -arg.Amount, arg.ToWalletID, arg.Amount)
-		} else {
-			result.ToWallet, result.FromWallet, err = addMoney(ctx, q, arg.ToWalletID, arg.Amount, arg.FromWalletID, -arg.Amount)
+		if wallet, ok := wallets[result.FromWallet.ID]; ok {
+			result.FromWallet = wallet
+		}
+		if wallet, ok := wallets[result.ToWallet.ID]; ok {
+			result.ToWallet = wallet
 		}
+		result.Fees = charges
+	}
 
-		return nil
+	if arg.Cashback != nil {
+		return grantCashback(ctx, q, result, *arg.Cashback)
+	}
+
+	return nil
+}
+
+// transfer moves money between two wallets using the given queries, so it
+// can be composed into bigger transactions
+func transfer(ctx context.Context, q *Queries, arg TrasferTxParms) (TrasferTxResult, error) {
+	var result TrasferTxResult
+
+	// both wallets are locked in ID order before their entries are chained
+	_, err := lockWallets(ctx, q, arg.FromWalletID, arg.ToWalletID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromWalletID: arg.FromWalletID,
+		ToWalletID:   arg.ToWalletID,
+		Amount:       arg.Amount,
 	})
+	if err != nil {
+		return result, err
+	}
+
+	_, err = postWalletTransfer(ctx, q, JournalTransfer, result.Transfer.ID, arg.FromWalletID, arg.ToWalletID, arg.Amount)
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, err = writeEntry(ctx, q, CreateEntryParams{
+		WalletID: arg.FromWalletID,
+		Amount:   -arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ToEntry, err = writeEntry(ctx, q, CreateEntryParams{
+		WalletID: arg.ToWalletID,
+		Amount:   arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if arg.FromWalletID < arg.ToWalletID {
+		result.FromWallet, result.ToWallet, err = addMoney(ctx, q, arg.FromWalletID, -arg.Amount, arg.ToWalletID, arg.Amount)
+	} else {
+		result.ToWallet, result.FromWallet, err = addMoney(ctx, q, arg.ToWalletID, arg.Amount, arg.FromWalletID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.ToWallet, err = receiveTransfer(ctx, q, result.Transfer, result.ToWallet)
 
 	return result, err
 }
 
+// receiveTransfer withholds the rolling reserve of the recipient and
+// schedules the settlement of the rest, once the amount of the transfer was
+// credited to its wallet. Every transfer received goes through it
+func receiveTransfer(ctx context.Context, q *Queries, transfer Transfer, wallet Wallet) (Wallet, error) {
+	wallet, reserved, err := withholdReserve(ctx, q, transfer, wallet)
+	if err != nil {
+		return wallet, err
+	}
+
+	// the reserved part is released by the reserve, not by the settlement plan
+	wallet, err = scheduleSettlement(ctx, q, transfer, wallet, transfer.Amount-reserved)
+	if err != nil {
+		return wallet, err
+	}
+
+	err = publishEvent(ctx, q, wallet.Owner, util.EventTransferReceived, transfer)
+
+	return wallet, err
+}
+
+// receiveFromAccount pays a transfer to its recipient out of the system
+// account its amount was moved to, like the suspense account of reviews or
+// the escrow account, and runs it through receiveTransfer like any other
+// transfer received. The recipient wallet must be locked already
+func receiveFromAccount(ctx context.Context, q *Queries, transfer Transfer, journal CreateJournalParams, account string) (Entry, Wallet, error) {
+	entry, err := writeEntry(ctx, q, CreateEntryParams{
+		WalletID: transfer.ToWalletID,
+		Amount:   transfer.Amount,
+	})
+	if err != nil {
+		return entry, Wallet{}, err
+	}
+
+	wallet, err := q.AddWalletBalance(ctx, AddWalletBalanceParams{
+		Amount: transfer.Amount,
+		ID:     transfer.ToWalletID,
+	})
+	if err != nil {
+		return entry, wallet, err
+	}
+
+	journal.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+	_, err = postSystemMovement(ctx, q, journal, wallet, account, transfer.Amount)
+	if err != nil {
+		return entry, wallet, err
+	}
+
+	wallet, err = receiveTransfer(ctx, q, transfer, wallet)
+
+	return entry, wallet, err
+}
+
 func addMoney(
 	ctx context.Context,
 	q *Queries,
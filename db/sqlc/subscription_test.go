@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSubscriptionPlan(t *testing.T, merchant Wallet, amount int64, trialDays int32) SubscriptionPlan {
+	plan, err := testQueries.CreateSubscriptionPlan(context.Background(), CreateSubscriptionPlanParams{
+		MerchantWalletID: merchant.ID,
+		Name:             util.RandomString(8),
+		Amount:           amount,
+		Currency:         merchant.Currency,
+		Frequency:        util.FrequencyMonthly,
+		TrialDays:        trialDays,
+		CreatedBy:        merchant.Owner,
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Active)
+
+	return plan
+}
+
+func renewParams(now time.Time) RenewSubscriptionTxParams {
+	return RenewSubscriptionTxParams{
+		Now:           now,
+		Location:      time.UTC,
+		MaxAttempts:   2,
+		RetryInterval: time.Hour,
+	}
+}
+
+func TestSubscriptionProrationAndDunning(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	basic := createRandomSubscriptionPlan(t, merchant, 300, 0)
+	premium := createRandomSubscriptionPlan(t, merchant, 600, 0)
+	subscribedAt := time.Date(1993, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	created, err := store.CreateSubscriptionTx(context.Background(), CreateSubscriptionTxParams{
+		PlanID:           basic.ID,
+		CustomerWalletID: customer.ID,
+		ApprovedBy:       customer.Owner,
+		Now:              subscribedAt,
+		Location:         time.UTC,
+	})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionActive, created.Subscription.Status)
+	require.Equal(t, int32(31), created.Subscription.DayOfMonth)
+	require.True(t, time.Date(1993, 2, 28, 12, 0, 0, 0, time.UTC).Equal(created.Subscription.CurrentPeriodEnd))
+	require.NotNil(t, created.Charge)
+	require.Equal(t, int64(300), created.Charge.Charge.Amount)
+	require.Equal(t, int64(700), created.Charge.Transfer.FromWallet.Balance)
+
+	// half of the period is left, half of each price is credited and charged
+	changed, err := store.ChangeSubscriptionPlanTx(context.Background(), ChangeSubscriptionPlanTxParams{
+		ID:     created.Subscription.ID,
+		PlanID: premium.ID,
+		Now:    time.Date(1993, 2, 14, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Equal(t, premium.ID, changed.Subscription.PlanID)
+	require.Zero(t, changed.Subscription.Credit)
+	require.NotNil(t, changed.Charge)
+	require.Equal(t, SubscriptionChargeProration, changed.Charge.Charge.Reason)
+	require.Equal(t, int64(150), changed.Charge.Charge.Amount)
+	require.Equal(t, int64(550), changed.Charge.Transfer.FromWallet.Balance)
+
+	// the customer can't cover the next period, it is tried again later
+	now := changed.Subscription.CurrentPeriodEnd
+	failed, err := store.RenewSubscriptionTx(context.Background(), renewParams(now))
+	require.NoError(t, err)
+	require.Equal(t, created.Subscription.ID, failed.Subscription.ID)
+	require.Nil(t, failed.Charge)
+	require.Equal(t, SubscriptionPastDue, failed.Subscription.Status)
+	require.True(t, failed.Subscription.RetryAt.Valid)
+
+	_, err = store.RenewSubscriptionTx(context.Background(), renewParams(now))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.ChangeSubscriptionPlanTx(context.Background(), ChangeSubscriptionPlanTxParams{
+		ID:     created.Subscription.ID,
+		PlanID: basic.ID,
+		Now:    now,
+	})
+	require.ErrorIs(t, err, ErrSubscriptionPastDue)
+
+	cancelled, err := store.RenewSubscriptionTx(context.Background(), renewParams(now.Add(2*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionCancelled, cancelled.Subscription.Status)
+	require.Equal(t, int32(2), cancelled.Subscription.Attempts)
+	require.True(t, cancelled.Subscription.CancelledAt.Valid)
+
+	charges, err := store.ListSubscriptionCharges(context.Background(), created.Subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, charges, 2)
+}
+
+func TestSubscriptionTrialAndCancelAtPeriodEnd(t *testing.T) {
+	store := NewStore(testDB)
+
+	customer := createFundedWallet(t, 1000)
+	merchant := createRandomWalletIn(t, customer.Currency)
+	plan := createRandomSubscriptionPlan(t, merchant, 400, 14)
+	subscribedAt := time.Date(1993, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	created, err := store.CreateSubscriptionTx(context.Background(), CreateSubscriptionTxParams{
+		PlanID:           plan.ID,
+		CustomerWalletID: customer.ID,
+		ApprovedBy:       customer.Owner,
+		Now:              subscribedAt,
+		Location:         time.UTC,
+	})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionTrialing, created.Subscription.Status)
+	require.Nil(t, created.Charge)
+	require.True(t, created.Subscription.TrialEndsAt.Valid)
+	require.Equal(t, int32(15), created.Subscription.DayOfMonth)
+
+	_, err = store.CreateSubscriptionTx(context.Background(), CreateSubscriptionTxParams{
+		PlanID:           plan.ID,
+		CustomerWalletID: customer.ID,
+		ApprovedBy:       customer.Owner,
+		Now:              subscribedAt,
+		Location:         time.UTC,
+	})
+	require.Error(t, err)
+
+	renewed, err := store.RenewSubscriptionTx(context.Background(), renewParams(created.Subscription.CurrentPeriodEnd))
+	require.NoError(t, err)
+	require.Equal(t, created.Subscription.ID, renewed.Subscription.ID)
+	require.Equal(t, SubscriptionActive, renewed.Subscription.Status)
+	require.NotNil(t, renewed.Charge)
+	require.Equal(t, int64(600), renewed.Charge.Transfer.FromWallet.Balance)
+	require.True(t, time.Date(1993, 4, 15, 12, 0, 0, 0, time.UTC).Equal(renewed.Subscription.CurrentPeriodEnd))
+
+	scheduled, err := store.CancelSubscriptionTx(context.Background(), CancelSubscriptionTxParams{
+		ID:          created.Subscription.ID,
+		AtPeriodEnd: true,
+		Now:         time.Date(1993, 4, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionActive, scheduled.Status)
+	require.True(t, scheduled.CancelAtPeriodEnd)
+
+	ended, err := store.RenewSubscriptionTx(context.Background(), renewParams(scheduled.CurrentPeriodEnd))
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionCancelled, ended.Subscription.Status)
+	require.Nil(t, ended.Charge)
+
+	_, err = store.CancelSubscriptionTx(context.Background(), CancelSubscriptionTxParams{ID: created.Subscription.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrSubscriptionClosed)
+
+	updatedCustomer, err := store.GetWallet(context.Background(), customer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(600), updatedCustomer.Balance)
+}
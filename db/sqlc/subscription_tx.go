@@ -0,0 +1,457 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	SubscriptionTrialing  = "trialing"
+	SubscriptionActive    = "active"
+	SubscriptionPastDue   = "past_due"
+	SubscriptionCancelled = "cancelled"
+)
+
+const (
+	SubscriptionChargePeriod    = "period"
+	SubscriptionChargeProration = "proration"
+)
+
+var (
+	// ErrSubscriptionPlanInactive is returned when subscribing or switching to
+	// a plan the merchant no longer offers
+	ErrSubscriptionPlanInactive = errors.New("subscription plan is no longer active")
+	// ErrSubscriptionPlanMismatch is returned when switching to a plan of
+	// another merchant wallet
+	ErrSubscriptionPlanMismatch = errors.New("subscription plan belongs to another merchant wallet")
+	// ErrSubscriptionClosed is returned when acting on a cancelled subscription
+	ErrSubscriptionClosed = errors.New("subscription is cancelled")
+	// ErrSubscriptionPastDue is returned when changing the plan of a
+	// subscription whose last charge is still unpaid
+	ErrSubscriptionPastDue = errors.New("subscription has a charge past due")
+)
+
+// SubscriptionChargeResult is a charge taken from the customer along with
+// its transfer, which is only set when the credit didn't cover the charge
+type SubscriptionChargeResult struct {
+	Charge   SubscriptionCharge `json:"charge"`
+	Transfer *TrasferTxResult   `json:"transfer,omitempty"`
+}
+
+type CreateSubscriptionTxParams struct {
+	PlanID           int64 `json:"plan_id"`
+	CustomerWalletID int64 `json:"customer_wallet_id"`
+	// ApprovedBy is the customer allowing the merchant to charge the wallet
+	// every period from now on
+	ApprovedBy string    `json:"approved_by"`
+	Now        time.Time `json:"-"`
+	// Location is where monthly periods end on the day they started
+	Location *time.Location `json:"-"`
+}
+
+type CreateSubscriptionTxResult struct {
+	Subscription Subscription `json:"subscription"`
+	// Charge is only set when the plan has no trial and the first period was charged
+	Charge *SubscriptionChargeResult `json:"charge,omitempty"`
+}
+
+// CreateSubscriptionTx subscribes the customer wallet to the plan. Plans
+// with a trial charge nothing until it ends, the others charge the first
+// period at once
+func (store *SQLStore) CreateSubscriptionTx(ctx context.Context, arg CreateSubscriptionTxParams) (CreateSubscriptionTxResult, error) {
+	var result CreateSubscriptionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		plan, err := q.GetSubscriptionPlan(ctx, arg.PlanID)
+		if err != nil {
+			return err
+		}
+
+		if !plan.Active {
+			return ErrSubscriptionPlanInactive
+		}
+
+		subscription := CreateSubscriptionParams{
+			PlanID:             plan.ID,
+			CustomerWalletID:   arg.CustomerWalletID,
+			MerchantWalletID:   plan.MerchantWalletID,
+			Status:             SubscriptionActive,
+			MandateApprovedBy:  arg.ApprovedBy,
+			MandateApprovedAt:  arg.Now,
+			CurrentPeriodStart: arg.Now,
+		}
+
+		if plan.TrialDays > 0 {
+			// paid periods start when the trial ends
+			subscription.Status = SubscriptionTrialing
+			subscription.CurrentPeriodEnd = arg.Now.AddDate(0, 0, int(plan.TrialDays))
+			subscription.TrialEndsAt = sql.NullTime{Time: subscription.CurrentPeriodEnd, Valid: true}
+			subscription.DayOfMonth = int32(subscription.CurrentPeriodEnd.In(arg.Location).Day())
+		} else {
+			subscription.DayOfMonth = int32(arg.Now.In(arg.Location).Day())
+			subscription.CurrentPeriodEnd, _ = util.NextOccurrence(plan.Frequency, int(subscription.DayOfMonth), arg.Now, arg.Location)
+		}
+
+		result.Subscription, err = q.CreateSubscription(ctx, subscription)
+		if err != nil {
+			return err
+		}
+
+		if plan.TrialDays == 0 {
+			charge, _, err := chargeSubscription(ctx, q, result.Subscription, CreateSubscriptionChargeParams{
+				SubscriptionID: result.Subscription.ID,
+				PlanID:         plan.ID,
+				Reason:         SubscriptionChargePeriod,
+				Amount:         plan.Amount,
+				PeriodStart:    result.Subscription.CurrentPeriodStart,
+				PeriodEnd:      result.Subscription.CurrentPeriodEnd,
+			})
+			if err != nil {
+				return err
+			}
+			result.Charge = &charge
+		}
+
+		return publishSubscriptionEvent(ctx, q, util.EventSubscriptionCreated, result.Subscription)
+	})
+
+	return result, err
+}
+
+// chargeSubscription takes the charge from the customer, using the credit of
+// the subscription first. It records what was actually transferred and
+// returns the credit left
+func chargeSubscription(ctx context.Context, q *Queries, subscription Subscription, charge CreateSubscriptionChargeParams) (SubscriptionChargeResult, int64, error) {
+	var result SubscriptionChargeResult
+
+	credit := subscription.Credit
+	if credit > charge.Amount {
+		credit = charge.Amount
+	}
+	charge.Amount -= credit
+
+	if charge.Amount > 0 {
+		transfer, err := checkedTransferTx(ctx, q, TrasferTxParms{
+			FromWalletID: subscription.CustomerWalletID,
+			ToWalletID:   subscription.MerchantWalletID,
+			Amount:       charge.Amount,
+		})
+		if err != nil {
+			return result, subscription.Credit, err
+		}
+
+		result.Transfer = &transfer
+		charge.TransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+	}
+
+	var err error
+	result.Charge, err = q.CreateSubscriptionCharge(ctx, charge)
+
+	return result, subscription.Credit - credit, err
+}
+
+type RenewSubscriptionTxParams struct {
+	Now      time.Time
+	Location *time.Location
+	// MaxAttempts is how many times a period is charged while funds are
+	// insufficient before the subscription is cancelled
+	MaxAttempts   int32
+	RetryInterval time.Duration
+}
+
+type RenewSubscriptionTxResult struct {
+	Subscription Subscription `json:"subscription"`
+	// Charge is only set when the period was paid
+	Charge *SubscriptionChargeResult `json:"charge,omitempty"`
+}
+
+// RenewSubscriptionTx claims one subscription whose period ended and charges
+// the next one. Charges the customer can't cover are tried again, the
+// subscription being past due meanwhile, until they run out of attempts and
+// the subscription is cancelled. Subscriptions cancelled at the end of the
+// period are cancelled instead. It returns sql.ErrNoRows when nothing is due
+func (store *SQLStore) RenewSubscriptionTx(ctx context.Context, arg RenewSubscriptionTxParams) (RenewSubscriptionTxResult, error) {
+	var result RenewSubscriptionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		subscription, err := q.ClaimDueSubscription(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		if subscription.CancelAtPeriodEnd {
+			result.Subscription, err = q.CancelSubscription(ctx, CancelSubscriptionParams{
+				ID:          subscription.ID,
+				CancelledAt: sql.NullTime{Time: arg.Now, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			return publishSubscriptionEvent(ctx, q, util.EventSubscriptionCancelled, result.Subscription)
+		}
+
+		plan, err := q.GetSubscriptionPlan(ctx, subscription.PlanID)
+		if err != nil {
+			return err
+		}
+
+		periodStart := subscription.CurrentPeriodEnd
+		periodEnd, _ := util.NextOccurrence(plan.Frequency, int(subscription.DayOfMonth), periodStart, arg.Location)
+
+		charge, credit, err := chargeSubscription(ctx, q, subscription, CreateSubscriptionChargeParams{
+			SubscriptionID: subscription.ID,
+			PlanID:         plan.ID,
+			Reason:         SubscriptionChargePeriod,
+			Amount:         plan.Amount,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+		})
+
+		if isTransferFailure(err) {
+			result.Subscription, err = recordSubscriptionFailure(ctx, q, subscription, err, arg)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Charge = &charge
+		result.Subscription, err = q.RenewSubscription(ctx, RenewSubscriptionParams{
+			ID:                 subscription.ID,
+			CurrentPeriodStart: periodStart,
+			CurrentPeriodEnd:   periodEnd,
+			Credit:             credit,
+		})
+		if err != nil {
+			return err
+		}
+
+		return publishSubscriptionEvent(ctx, q, util.EventSubscriptionRenewed, result.Subscription)
+	})
+
+	return result, err
+}
+
+// recordSubscriptionFailure records why the period couldn't be charged,
+// trying it again later when the customer may still cover it and
+// cancelling the subscription otherwise
+func recordSubscriptionFailure(ctx context.Context, q *Queries, subscription Subscription, failure error, arg RenewSubscriptionTxParams) (Subscription, error) {
+	attempt := subscription.Attempts + 1
+
+	fail := FailSubscriptionChargeParams{
+		ID:        subscription.ID,
+		Status:    SubscriptionCancelled,
+		Attempts:  attempt,
+		LastError: failure.Error(),
+	}
+
+	if errors.Is(failure, ErrInsufficientFunds) && attempt < arg.MaxAttempts {
+		fail.Status = SubscriptionPastDue
+		fail.RetryAt = sql.NullTime{Time: arg.Now.Add(arg.RetryInterval), Valid: true}
+	}
+
+	subscription, err := q.FailSubscriptionCharge(ctx, fail)
+	if err != nil {
+		return subscription, err
+	}
+
+	if err := publishSubscriptionEvent(ctx, q, util.EventSubscriptionPaymentFailed, subscription); err != nil {
+		return subscription, err
+	}
+
+	customer, err := q.GetWallet(ctx, subscription.CustomerWalletID)
+	if err != nil {
+		return subscription, err
+	}
+
+	if subscription.Status == SubscriptionPastDue {
+		message := fmt.Sprintf("Subscription %d could not be charged: %s. It will be tried again.", subscription.ID, subscription.LastError)
+		return subscription, notify(ctx, q, customer.Owner, util.NotificationSubscriptionFailed, message, subscription)
+	}
+
+	subscription, err = q.CancelSubscription(ctx, CancelSubscriptionParams{
+		ID:          subscription.ID,
+		CancelledAt: sql.NullTime{Time: arg.Now, Valid: true},
+	})
+	if err != nil {
+		return subscription, err
+	}
+
+	if err := publishSubscriptionEvent(ctx, q, util.EventSubscriptionCancelled, subscription); err != nil {
+		return subscription, err
+	}
+
+	message := fmt.Sprintf("Subscription %d was cancelled, it could not be charged: %s.", subscription.ID, subscription.LastError)
+	return subscription, notify(ctx, q, customer.Owner, util.NotificationSubscriptionCancelled, message, subscription)
+}
+
+type ChangeSubscriptionPlanTxParams struct {
+	ID     int64     `json:"id"`
+	PlanID int64     `json:"plan_id"`
+	Now    time.Time `json:"now"`
+}
+
+type ChangeSubscriptionPlanTxResult struct {
+	Subscription Subscription `json:"subscription"`
+	// Charge is only set when the new plan costs more for the rest of the period
+	Charge *SubscriptionChargeResult `json:"charge,omitempty"`
+}
+
+// ChangeSubscriptionPlanTx moves the subscription to another plan of the
+// same merchant wallet. What is left of the period is credited at the old
+// price and charged at the new one right away, a credit left over is taken
+// off the next charges. Trials just change plans
+func (store *SQLStore) ChangeSubscriptionPlanTx(ctx context.Context, arg ChangeSubscriptionPlanTxParams) (ChangeSubscriptionPlanTxResult, error) {
+	var result ChangeSubscriptionPlanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		subscription, err := q.GetSubscriptionForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		switch subscription.Status {
+		case SubscriptionCancelled:
+			return ErrSubscriptionClosed
+		case SubscriptionPastDue:
+			return ErrSubscriptionPastDue
+		}
+
+		plan, err := q.GetSubscriptionPlan(ctx, arg.PlanID)
+		if err != nil {
+			return err
+		}
+
+		if plan.MerchantWalletID != subscription.MerchantWalletID {
+			return ErrSubscriptionPlanMismatch
+		}
+		if !plan.Active {
+			return ErrSubscriptionPlanInactive
+		}
+
+		credit := subscription.Credit
+
+		if subscription.Status == SubscriptionActive {
+			current, err := q.GetSubscriptionPlan(ctx, subscription.PlanID)
+			if err != nil {
+				return err
+			}
+
+			subscription.Credit += util.Prorate(current.Amount, subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd, arg.Now)
+
+			charge, left, err := chargeSubscription(ctx, q, subscription, CreateSubscriptionChargeParams{
+				SubscriptionID: subscription.ID,
+				PlanID:         plan.ID,
+				Reason:         SubscriptionChargeProration,
+				Amount:         util.Prorate(plan.Amount, subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd, arg.Now),
+				PeriodStart:    arg.Now,
+				PeriodEnd:      subscription.CurrentPeriodEnd,
+			})
+			if err != nil {
+				return err
+			}
+
+			credit = left
+			if charge.Transfer != nil {
+				result.Charge = &charge
+			}
+		}
+
+		result.Subscription, err = q.ChangeSubscriptionPlan(ctx, ChangeSubscriptionPlanParams{
+			ID:     subscription.ID,
+			PlanID: plan.ID,
+			Credit: credit,
+		})
+		if err != nil {
+			return err
+		}
+
+		return publishSubscriptionEvent(ctx, q, util.EventSubscriptionUpdated, result.Subscription)
+	})
+
+	return result, err
+}
+
+type CancelSubscriptionTxParams struct {
+	ID int64 `json:"id"`
+	// AtPeriodEnd lets the customer use what was paid for, subscriptions past
+	// due are always cancelled at once
+	AtPeriodEnd bool `json:"at_period_end"`
+	// ByMerchant lets the customer know the merchant cancelled the subscription
+	ByMerchant bool      `json:"by_merchant"`
+	Now        time.Time `json:"now"`
+}
+
+// CancelSubscriptionTx stops charging the subscription, now or when its
+// period ends. Nothing already charged is refunded
+func (store *SQLStore) CancelSubscriptionTx(ctx context.Context, arg CancelSubscriptionTxParams) (Subscription, error) {
+	var result Subscription
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		subscription, err := q.GetSubscriptionForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if subscription.Status == SubscriptionCancelled {
+			return ErrSubscriptionClosed
+		}
+
+		if arg.AtPeriodEnd && subscription.Status != SubscriptionPastDue {
+			result, err = q.SetSubscriptionCancelAtPeriodEnd(ctx, SetSubscriptionCancelAtPeriodEndParams{
+				ID:                subscription.ID,
+				CancelAtPeriodEnd: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := publishSubscriptionEvent(ctx, q, util.EventSubscriptionUpdated, result); err != nil {
+				return err
+			}
+		} else {
+			result, err = q.CancelSubscription(ctx, CancelSubscriptionParams{
+				ID:          subscription.ID,
+				CancelledAt: sql.NullTime{Time: arg.Now, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := publishSubscriptionEvent(ctx, q, util.EventSubscriptionCancelled, result); err != nil {
+				return err
+			}
+		}
+
+		if !arg.ByMerchant {
+			return nil
+		}
+
+		customer, err := q.GetWallet(ctx, result.CustomerWalletID)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("The merchant cancelled subscription %d.", result.ID)
+		if result.CancelAtPeriodEnd && result.Status != SubscriptionCancelled {
+			message = fmt.Sprintf("The merchant cancelled subscription %d, it ends on %s.", result.ID, result.CurrentPeriodEnd.Format("2006-01-02"))
+		}
+		return notify(ctx, q, customer.Owner, util.NotificationSubscriptionCancelled, message, result)
+	})
+
+	return result, err
+}
+
+// publishSubscriptionEvent tells the merchant the subscription changed
+func publishSubscriptionEvent(ctx context.Context, q *Queries, eventType string, subscription Subscription) error {
+	merchant, err := q.GetWallet(ctx, subscription.MerchantWalletID)
+	if err != nil {
+		return err
+	}
+
+	return publishEvent(ctx, q, merchant.Owner, eventType, subscription)
+}
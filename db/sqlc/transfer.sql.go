@@ -16,7 +16,7 @@ INSERT INTO transfers (
   amount
 ) VALUES (
   $1, $2, $3
-) RETURNING id, from_wallet_id, to_wallet_id, amount, created_at
+) RETURNING id, from_wallet_id, to_wallet_id, amount, created_at, payment_request_id, split_transfer_id, invoice_id
 `
 
 type CreateTransferParams struct {
@@ -34,12 +34,15 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToWalletID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.PaymentRequestID,
+		&i.SplitTransferID,
+		&i.InvoiceID,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_wallet_id, to_wallet_id, amount, created_at FROM transfers
+SELECT id, from_wallet_id, to_wallet_id, amount, created_at, payment_request_id, split_transfer_id, invoice_id FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +55,38 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToWalletID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.PaymentRequestID,
+		&i.SplitTransferID,
+		&i.InvoiceID,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_wallet_id, to_wallet_id, amount, created_at, payment_request_id, split_transfer_id, invoice_id FROM transfers
+WHERE id = $1 
+LIMIT 1 
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromWalletID,
+		&i.ToWalletID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.PaymentRequestID,
+		&i.SplitTransferID,
+		&i.InvoiceID,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_wallet_id, to_wallet_id, amount, created_at FROM transfers
+SELECT id, from_wallet_id, to_wallet_id, amount, created_at, payment_request_id, split_transfer_id, invoice_id FROM transfers
 WHERE 
     from_wallet_id = $1 OR
     to_wallet_id = $2
@@ -93,6 +122,9 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToWalletID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.PaymentRequestID,
+			&i.SplitTransferID,
+			&i.InvoiceID,
 		); err != nil {
 			return nil, err
 		}
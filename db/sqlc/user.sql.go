@@ -21,7 +21,7 @@ INSERT INTO users (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated
+RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category
 `
 
 type CreateUserParams struct {
@@ -51,6 +51,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsMerchant,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
 	)
 	return i, err
 }
@@ -65,7 +67,7 @@ func (q *Queries) DeleteUser(ctx context.Context, username string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated FROM users
+SELECT username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -82,12 +84,39 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.IsMerchant,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
+	)
+	return i, err
+}
+
+const getUserByPixKey = `-- name: GetUserByPixKey :one
+SELECT username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category FROM users
+WHERE cpf_cnpj = $1 OR email = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByPixKey(ctx context.Context, key string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByPixKey, key)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.CpfCnpj,
+		&i.Email,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.IsMerchant,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated FROM users
+SELECT username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category FROM users
 ORDER BY username
 LIMIT $1
 OFFSET $2
@@ -117,6 +146,8 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.IsMerchant,
 			&i.CreatedAt,
 			&i.LastUpdated,
+			&i.Role,
+			&i.MerchantCategory,
 		); err != nil {
 			return nil, err
 		}
@@ -140,7 +171,7 @@ SET
     password_changed_at = $5,
     last_updated = now()
 WHERE username = $1
-RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated
+RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category
 `
 
 type UpdateUserParams struct {
@@ -170,6 +201,74 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.IsMerchant,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
+	)
+	return i, err
+}
+
+const updateUserMerchantCategory = `-- name: UpdateUserMerchantCategory :one
+UPDATE users
+SET 
+    merchant_category = $2,
+    last_updated = now()
+WHERE username = $1
+RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category
+`
+
+type UpdateUserMerchantCategoryParams struct {
+	Username         string `json:"username"`
+	MerchantCategory string `json:"merchant_category"`
+}
+
+func (q *Queries) UpdateUserMerchantCategory(ctx context.Context, arg UpdateUserMerchantCategoryParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserMerchantCategory, arg.Username, arg.MerchantCategory)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.CpfCnpj,
+		&i.Email,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.IsMerchant,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET 
+    role = $2,
+    last_updated = now()
+WHERE username = $1
+RETURNING username, full_name, cpf_cnpj, email, hashed_password, password_changed_at, is_merchant, created_at, last_updated, role, merchant_category
+`
+
+type UpdateUserRoleParams struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.CpfCnpj,
+		&i.Email,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.IsMerchant,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Role,
+		&i.MerchantCategory,
 	)
 	return i, err
 }
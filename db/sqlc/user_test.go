@@ -31,6 +31,8 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, userParams.Email, user.Email)
 	require.Equal(t, userParams.HashedPassword, user.HashedPassword)
 
+	require.Equal(t, util.CustomerRole, user.Role)
+
 	require.NotZero(t, user.Username)
 	require.NotZero(t, user.CreatedAt)
 
@@ -116,3 +118,29 @@ func TestListUsers(t *testing.T) {
 		require.NotEmpty(t, user)
 	}
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     util.SupportRole,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, util.SupportRole, user2.Role)
+}
+
+func TestGetUserByPixKey(t *testing.T) {
+	user := createRandomUser(t)
+
+	for _, key := range []string{user.CpfCnpj, user.Email} {
+		found, err := testQueries.GetUserByPixKey(context.Background(), key)
+		require.NoError(t, err)
+		require.Equal(t, user.Username, found.Username)
+	}
+
+	_, err := testQueries.GetUserByPixKey(context.Background(), util.RandomString(12))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -13,7 +13,7 @@ const addWalletBalance = `-- name: AddWalletBalance :one
 UPDATE wallets
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at, country_code
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
 `
 
 type AddWalletBalanceParams struct {
@@ -31,6 +31,100 @@ func (q *Queries) AddWalletBalance(ctx context.Context, arg AddWalletBalancePara
 		&i.Currency,
 		&i.CreatedAt,
 		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
+	)
+	return i, err
+}
+
+const addWalletHeldBalance = `-- name: AddWalletHeldBalance :one
+UPDATE wallets
+SET held_balance = held_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
+`
+
+type AddWalletHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddWalletHeldBalance(ctx context.Context, arg AddWalletHeldBalanceParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, addWalletHeldBalance, arg.Amount, arg.ID)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
+	)
+	return i, err
+}
+
+const addWalletReceivableBalance = `-- name: AddWalletReceivableBalance :one
+UPDATE wallets
+SET receivable_balance = receivable_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
+`
+
+type AddWalletReceivableBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddWalletReceivableBalance(ctx context.Context, arg AddWalletReceivableBalanceParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, addWalletReceivableBalance, arg.Amount, arg.ID)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
+	)
+	return i, err
+}
+
+const addWalletReserveBalance = `-- name: AddWalletReserveBalance :one
+UPDATE wallets
+SET reserve_balance = reserve_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
+`
+
+type AddWalletReserveBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddWalletReserveBalance(ctx context.Context, arg AddWalletReserveBalanceParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, addWalletReserveBalance, arg.Amount, arg.ID)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
 	)
 	return i, err
 }
@@ -43,7 +137,7 @@ INSERT INTO wallets (
 ) VALUES (
     $1, $2, $3
 )
-RETURNING id, owner, balance, currency, created_at, country_code
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
 `
 
 type CreateWalletParams struct {
@@ -62,6 +156,10 @@ func (q *Queries) CreateWallet(ctx context.Context, arg CreateWalletParams) (Wal
 		&i.Currency,
 		&i.CreatedAt,
 		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
 	)
 	return i, err
 }
@@ -76,7 +174,7 @@ func (q *Queries) DeleteWallet(ctx context.Context, id int64) error {
 }
 
 const getWallet = `-- name: GetWallet :one
-SELECT id, owner, balance, currency, created_at, country_code FROM wallets
+SELECT id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance FROM wallets
 WHERE id = $1 LIMIT 1
 `
 
@@ -90,12 +188,45 @@ func (q *Queries) GetWallet(ctx context.Context, id int64) (Wallet, error) {
 		&i.Currency,
 		&i.CreatedAt,
 		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
+	)
+	return i, err
+}
+
+const getWalletByOwnerAndCurrency = `-- name: GetWalletByOwnerAndCurrency :one
+SELECT id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance FROM wallets
+WHERE owner = $1 AND currency = $2
+LIMIT 1
+`
+
+type GetWalletByOwnerAndCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetWalletByOwnerAndCurrency(ctx context.Context, arg GetWalletByOwnerAndCurrencyParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, getWalletByOwnerAndCurrency, arg.Owner, arg.Currency)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
 	)
 	return i, err
 }
 
 const getWalletForUpdate = `-- name: GetWalletForUpdate :one
-SELECT id, owner, balance, currency, created_at, country_code FROM wallets
+SELECT id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance FROM wallets
 WHERE id = $1 
 LIMIT 1 
 FOR NO KEY UPDATE
@@ -111,12 +242,16 @@ func (q *Queries) GetWalletForUpdate(ctx context.Context, id int64) (Wallet, err
 		&i.Currency,
 		&i.CreatedAt,
 		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
 	)
 	return i, err
 }
 
 const listWallets = `-- name: ListWallets :many
-SELECT id, owner, balance, currency, created_at, country_code FROM wallets
+SELECT id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance FROM wallets
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -143,6 +278,10 @@ func (q *Queries) ListWallets(ctx context.Context, arg ListWalletsParams) ([]Wal
 			&i.Currency,
 			&i.CreatedAt,
 			&i.CountryCode,
+			&i.IsFrozen,
+			&i.HeldBalance,
+			&i.ReceivableBalance,
+			&i.ReserveBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -161,7 +300,7 @@ const updateWallet = `-- name: UpdateWallet :one
 UPDATE wallets
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at, country_code
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
 `
 
 type UpdateWalletParams struct {
@@ -179,6 +318,40 @@ func (q *Queries) UpdateWallet(ctx context.Context, arg UpdateWalletParams) (Wal
 		&i.Currency,
 		&i.CreatedAt,
 		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
+	)
+	return i, err
+}
+
+const updateWalletFrozen = `-- name: UpdateWalletFrozen :one
+UPDATE wallets
+SET is_frozen = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, country_code, is_frozen, held_balance, receivable_balance, reserve_balance
+`
+
+type UpdateWalletFrozenParams struct {
+	ID       int64 `json:"id"`
+	IsFrozen bool  `json:"is_frozen"`
+}
+
+func (q *Queries) UpdateWalletFrozen(ctx context.Context, arg UpdateWalletFrozenParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, updateWalletFrozen, arg.ID, arg.IsFrozen)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.CountryCode,
+		&i.IsFrozen,
+		&i.HeldBalance,
+		&i.ReceivableBalance,
+		&i.ReserveBalance,
 	)
 	return i, err
 }
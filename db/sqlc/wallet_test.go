@@ -13,13 +13,13 @@ import (
 func createRandomWallet(t *testing.T) Wallet {
 	UID := createRandomUser(t).Username
 
-	walletParams := CreateWalletParams{
+	walletParams := CreateWalletTxParams{
 		Owner:    UID,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
 	}
 
-	wallet, err := testQueries.CreateWallet(context.Background(), walletParams)
+	wallet, err := NewStore(testDB).CreateWalletTx(context.Background(), walletParams)
 
 	require.NoError(t, err)
 	require.NotEmpty(t, wallet)
@@ -107,3 +107,28 @@ func TestListWallets(t *testing.T) {
 		require.NotEmpty(t, wallet)
 	}
 }
+
+func TestUpdateWalletFrozen(t *testing.T) {
+	wallet1 := createRandomWallet(t)
+	require.False(t, wallet1.IsFrozen)
+
+	wallet2, err := testQueries.UpdateWalletFrozen(context.Background(), UpdateWalletFrozenParams{
+		ID:       wallet1.ID,
+		IsFrozen: true,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, wallet1.ID, wallet2.ID)
+	require.True(t, wallet2.IsFrozen)
+}
+
+func TestGetWalletByOwnerAndCurrency(t *testing.T) {
+	wallet := createRandomWallet(t)
+
+	found, err := testQueries.GetWalletByOwnerAndCurrency(context.Background(), GetWalletByOwnerAndCurrencyParams{
+		Owner:    wallet.Owner,
+		Currency: wallet.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.ID, found.ID)
+}
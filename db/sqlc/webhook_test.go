@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, owner string, eventTypes []string) WebhookEndpoint {
+	arg := CreateWebhookEndpointParams{
+		Owner:      owner,
+		Url:        "https://example.com/" + util.RandomString(8),
+		Secret:     util.RandomString(32),
+		EventTypes: eventTypes,
+	}
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.NotZero(t, endpoint.ID)
+	require.Equal(t, arg.Owner, endpoint.Owner)
+	require.Equal(t, arg.Url, endpoint.Url)
+	require.Equal(t, arg.EventTypes, endpoint.EventTypes)
+	require.True(t, endpoint.Enabled)
+	require.Zero(t, endpoint.ConsecutiveFailures)
+
+	return endpoint
+}
+
+func TestCreateWebhookEndpoint(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomWebhookEndpoint(t, user.Username, []string{util.EventTransferReceived})
+}
+
+func TestDisableWebhookEndpoint(t *testing.T) {
+	user := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, user.Username, []string{util.EventTransferReceived})
+
+	err := testQueries.DisableWebhookEndpoint(context.Background(), endpoint.ID)
+	require.NoError(t, err)
+
+	disabled, err := testQueries.GetWebhookEndpoint(context.Background(), endpoint.ID)
+	require.NoError(t, err)
+	require.False(t, disabled.Enabled)
+	require.True(t, disabled.DisabledAt.Valid)
+
+	enabled, err := testQueries.EnableWebhookEndpoint(context.Background(), endpoint.ID)
+	require.NoError(t, err)
+	require.True(t, enabled.Enabled)
+	require.False(t, enabled.DisabledAt.Valid)
+	require.Zero(t, enabled.ConsecutiveFailures)
+}
+
+func TestTransferTxQueuesWebhookDelivery(t *testing.T) {
+	store := NewStore(testDB)
+
+	wallet1 := createRandomWallet(t)
+	wallet2 := createRandomWallet(t)
+
+	subscribed := createRandomWebhookEndpoint(t, wallet2.Owner, []string{util.EventTransferReceived})
+	other := createRandomWebhookEndpoint(t, wallet2.Owner, []string{util.EventRefundCreated})
+
+	_, err := store.TransferTx(context.Background(), TrasferTxParms{
+		FromWalletID: wallet1.ID,
+		ToWalletID:   wallet2.ID,
+		Amount:       10,
+	})
+	require.NoError(t, err)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: subscribed.ID,
+		Limit:      5,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "pending", deliveries[0].Status)
+	require.WithinDuration(t, time.Now(), deliveries[0].NextAttemptAt, time.Minute)
+
+	event, err := testQueries.GetWebhookEvent(context.Background(), deliveries[0].EventID)
+	require.NoError(t, err)
+	require.Equal(t, util.EventTransferReceived, event.EventType)
+	require.Equal(t, wallet2.Owner, event.Owner)
+
+	deliveries, err = testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: other.ID,
+		Limit:      5,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/json"
 	"log"
+	"os"
 	"picpay_simplificado/api"
 	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/risk"
+	"picpay_simplificado/stream"
 	"picpay_simplificado/util"
+	"picpay_simplificado/worker"
+	"strconv"
+	_ "time/tzdata"
 
 	_ "github.com/lib/pq"
 )
@@ -22,10 +31,117 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
-	server := api.NewServer(store)
+
+	if len(os.Args) > 1 && os.Args[1] == "verify-chain" {
+		verifyChain(store, config, os.Args[2:])
+		return
+	}
+
+	dispatcher := worker.NewWebhookDispatcher(store, config)
+	go dispatcher.Run(context.Background(), config.WorkerInterval)
+
+	limitApplier := worker.NewLimitIncreaseApplier(store, config)
+	go limitApplier.Run(context.Background(), config.WorkerInterval)
+
+	broker := stream.NewBroker()
+	go func() {
+		if err := stream.Listen(context.Background(), config.DBSource, broker); err != nil {
+			log.Fatal("cannot listen to wallet events:", err)
+		}
+	}()
+
+	riskEngine := risk.NewEngine()
+	if err := riskEngine.LoadFile(config.RiskRulesPath); err != nil {
+		log.Fatal("cannot load risk rules:", err)
+	}
+	go riskEngine.Watch(context.Background(), config.RiskRulesPath, config.RiskReloadInterval)
+
+	limitSchedule, err := config.LimitSchedule()
+	if err != nil {
+		log.Fatal("cannot load limit schedule:", err)
+	}
+
+	scheduler := worker.NewTransferScheduler(store, config, limitSchedule, riskEngine)
+	go scheduler.Run(context.Background(), config.WorkerInterval)
+
+	payoutProcessor := worker.NewPayoutProcessor(store, config, limitSchedule, riskEngine)
+	go payoutProcessor.Run(context.Background(), config.WorkerInterval)
+
+	holdExpirer := worker.NewHoldExpirer(store)
+	go holdExpirer.Run(context.Background(), config.WorkerInterval)
+
+	escrowReleaser := worker.NewEscrowReleaser(store)
+	go escrowReleaser.Run(context.Background(), config.WorkerInterval)
+
+	disputeEscalator := worker.NewDisputeEscalator(store)
+	go disputeEscalator.Run(context.Background(), config.WorkerInterval)
+
+	receivableSettler := worker.NewReceivableSettler(store)
+	go receivableSettler.Run(context.Background(), config.WorkerInterval)
+
+	reserveReleaser := worker.NewReserveReleaser(store)
+	go reserveReleaser.Run(context.Background(), config.WorkerInterval)
+
+	installmentCollector := worker.NewInstallmentCollector(store, config)
+	go installmentCollector.Run(context.Background(), config.WorkerInterval)
+
+	checkoutExpirer := worker.NewCheckoutExpirer(store)
+	go checkoutExpirer.Run(context.Background(), config.WorkerInterval)
+
+	subscriptionBiller := worker.NewSubscriptionBiller(store, config, limitSchedule)
+	go subscriptionBiller.Run(context.Background(), config.WorkerInterval)
+
+	invoiceReminder, err := worker.NewInvoiceReminder(store, config)
+	if err != nil {
+		log.Fatal("cannot create invoice reminder:", err)
+	}
+	go invoiceReminder.Run(context.Background(), config.WorkerInterval)
+
+	cashbackSettler := worker.NewCashbackSettler(store, config)
+	go cashbackSettler.Run(context.Background(), config.WorkerInterval)
+
+	chainAnchor := worker.NewChainAnchor(store, config)
+	go chainAnchor.Run(context.Background(), config.ChainCheckpointInterval)
+
+	server, err := api.NewServer(config, store, broker, riskEngine)
+	if err != nil {
+		log.Fatal("cannot create server:", err)
+	}
 
 	err = server.StartServer(config.ServerAddress)
 	if err != nil {
 		log.Fatal("cannot start server:", err)
 	}
 }
+
+// verifyChain checks the entry chain of every wallet, or of the wallet given
+// as argument, and exits with an error status when a link is broken
+func verifyChain(store db.Store, config util.Config, args []string) {
+	arg := db.VerifyChainParams{
+		PublicKey: config.ChainKey().Public().(ed25519.PublicKey),
+		BatchSize: 1000,
+	}
+
+	if len(args) > 0 {
+		walletID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatal("invalid wallet id:", err)
+		}
+		arg.WalletID = walletID
+	}
+
+	result, err := store.VerifyChain(context.Background(), arg)
+	if err != nil {
+		log.Fatal("cannot verify the entry chain:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatal("cannot print the verification:", err)
+	}
+
+	if !result.Valid {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket holding up to Requests tokens that refills
+// completely every Period. Every request takes one token
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses limits written as "<requests>/<period>", like "5/1m"
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit requests %q", s)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit period %q", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// IsZero reports whether the limit is unset, meaning no limiting
+func (limit Limit) IsZero() bool {
+	return limit.Requests == 0
+}
+
+// rate is how many tokens are added back per second
+func (limit Limit) rate() float64 {
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+// Result describes the state of a bucket after a request
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, zero when allowed
+	RetryAfter time.Duration
+}
+
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.rate()
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(limit.Requests) - tokens) / rate * float64(time.Second)),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+
+	return result
+}
+
+// Limiter takes tokens from the bucket identified by key
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("5/1m")
+	require.NoError(t, err)
+	require.Equal(t, Limit{Requests: 5, Period: time.Minute}, limit)
+
+	for _, s := range []string{"", "5", "0/1m", "-1/1m", "5/0s", "five/1m", "5/minute"} {
+		_, err := ParseLimit(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	limit := Limit{Requests: 10, Period: 10 * time.Second}
+
+	result := newResult(limit, 4.5, true)
+	require.True(t, result.Allowed)
+	require.Equal(t, 10, result.Limit)
+	require.Equal(t, 4, result.Remaining)
+	require.Equal(t, 5500*time.Millisecond, result.Reset)
+	require.Zero(t, result.RetryAfter)
+
+	result = newResult(limit, 0.25, false)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+	require.Equal(t, 750*time.Millisecond, result.RetryAfter)
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := limiter.Allow(context.Background(), "a", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(context.Background(), "a", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	// keys do not share tokens
+	result, err = limiter.Allow(context.Background(), "b", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// one token is back after a third of the period
+	now = now.Add(time.Second)
+	result, err = limiter.Allow(context.Background(), "a", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	_, err := limiter.Allow(context.Background(), "a", Limit{Requests: 1, Period: time.Second})
+	require.NoError(t, err)
+	require.Len(t, limiter.buckets, 1)
+
+	now = now.Add(sweepInterval)
+	_, err = limiter.Allow(context.Background(), "b", Limit{Requests: 1, Period: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, limiter.buckets, 1)
+	require.Contains(t, limiter.buckets, "b")
+}
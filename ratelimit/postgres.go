@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"sync"
+	"time"
+)
+
+// staleBucketAge is how long an idle bucket is kept. It must be longer than
+// any configured period, so dropping it is the same as refilling it
+const staleBucketAge = 24 * time.Hour
+
+// PostgresLimiter keeps buckets in Postgres so every instance shares them.
+// Each request takes its token with a single atomic upsert
+type PostgresLimiter struct {
+	store     db.Querier
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresLimiter creates a new PostgresLimiter
+func NewPostgresLimiter(store db.Querier) *PostgresLimiter {
+	return &PostgresLimiter{
+		store:     store,
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket if there is one
+func (limiter *PostgresLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := limiter.sweep(ctx); err != nil {
+		return Result{}, err
+	}
+
+	bucket, err := limiter.store.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: float64(limit.Requests),
+		Rate:  limit.rate(),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(limit, bucket.Tokens, bucket.Allowed), nil
+}
+
+func (limiter *PostgresLimiter) sweep(ctx context.Context) error {
+	limiter.mu.Lock()
+	now := time.Now()
+	due := now.Sub(limiter.lastSweep) >= sweepInterval
+	if due {
+		limiter.lastSweep = now
+	}
+	limiter.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+
+	return limiter.store.DeleteStaleRateLimitBuckets(ctx, now.Add(-staleBucketAge))
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().DeleteStaleRateLimitBuckets(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(db.TakeRateLimitTokenParams{
+			Key:   "login:ip:10.0.0.1",
+			Burst: 2,
+			Rate:  limit.rate(),
+		})).
+		Times(1).
+		Return(db.RateLimitBucket{Key: "login:ip:10.0.0.1", Tokens: 0.5, Allowed: false}, nil)
+
+	limiter := NewPostgresLimiter(store)
+
+	result, err := limiter.Allow(context.Background(), "login:ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+	require.Equal(t, 15*time.Second, result.RetryAfter)
+
+	// stale buckets are removed once per sweep interval
+	limiter.lastSweep = time.Now().Add(-sweepInterval)
+	store.EXPECT().DeleteStaleRateLimitBuckets(gomock.Any(), gomock.Any()).Times(1)
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.RateLimitBucket{Tokens: 1, Allowed: true}, nil)
+
+	result, err = limiter.Allow(context.Background(), "login:ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 1, result.Remaining)
+}
@@ -0,0 +1,214 @@
+package risk
+
+import (
+	"context"
+	"log"
+	"os"
+	db "picpay_simplificado/db/sqlc"
+	"sync"
+	"time"
+)
+
+// Engine evaluates transfers against the current rule set, which can be
+// swapped while the server is running
+type Engine struct {
+	mu    sync.RWMutex
+	rules RuleSet
+	now   func() time.Time
+}
+
+// NewEngine creates an engine with no rules, allowing every transfer
+func NewEngine() *Engine {
+	return &Engine{now: time.Now}
+}
+
+// Load replaces the rules with the parsed content of data. The current
+// rules are kept when data is invalid
+func (engine *Engine) Load(data []byte) error {
+	rules, err := ParseRules(data)
+	if err != nil {
+		return err
+	}
+
+	engine.mu.Lock()
+	engine.rules = rules
+	engine.mu.Unlock()
+
+	return nil
+}
+
+// LoadFile replaces the rules with the content of the file at path
+func (engine *Engine) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return engine.Load(data)
+}
+
+// Rules returns the rules currently in use
+func (engine *Engine) Rules() RuleSet {
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+
+	return engine.rules
+}
+
+// Watch reloads the rules file whenever it changes, until ctx is done
+func (engine *Engine) Watch(ctx context.Context, path string, interval time.Duration) {
+	var modTime time.Time
+
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+
+			modTime = info.ModTime()
+
+			if err := engine.LoadFile(path); err != nil {
+				log.Println("cannot reload risk rules:", err)
+				continue
+			}
+
+			log.Println("risk rules reloaded, version", engine.Rules().Version)
+		}
+	}
+}
+
+// EvaluateTransfer scores the transfer with every rule. The outcome is the
+// most severe between the actions of the matched rules and the score thresholds
+func (engine *Engine) EvaluateTransfer(ctx context.Context, q db.Querier, arg db.TrasferTxParms) (db.RiskAssessment, error) {
+	rules := engine.Rules()
+
+	assessment := db.RiskAssessment{
+		Outcome: db.RiskAllow,
+		Rules:   []string{},
+		Version: rules.Version,
+	}
+
+	now := engine.now()
+
+	for _, rule := range rules.Rules {
+		matched, err := engine.match(ctx, q, rule, arg, now)
+		if err != nil {
+			return assessment, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		assessment.Score += rule.Score
+		assessment.Rules = append(assessment.Rules, rule.Name)
+		assessment.Outcome = mostSevere(assessment.Outcome, rule.Action)
+	}
+
+	if rules.ReviewScore > 0 && assessment.Score >= rules.ReviewScore {
+		assessment.Outcome = mostSevere(assessment.Outcome, db.RiskReview)
+	}
+
+	if rules.BlockScore > 0 && assessment.Score >= rules.BlockScore {
+		assessment.Outcome = db.RiskBlock
+	}
+
+	return assessment, nil
+}
+
+func (engine *Engine) match(ctx context.Context, q db.Querier, rule Rule, arg db.TrasferTxParms, now time.Time) (bool, error) {
+	switch rule.Type {
+	case RuleVelocity:
+		totals, err := q.GetOutgoingTransferTotals(ctx, db.GetOutgoingTransferTotalsParams{
+			WalletID: arg.FromWalletID,
+			Since:    now.Add(-rule.Window),
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return totals.TransferCount >= rule.MaxTransfers, nil
+
+	case RuleNewRecipient:
+		if arg.Amount < rule.MinAmount {
+			return false, nil
+		}
+
+		count, err := q.CountTransfersBetween(ctx, db.CountTransfersBetweenParams{
+			FromWalletID: arg.FromWalletID,
+			ToWalletID:   arg.ToWalletID,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return count == 0, nil
+
+	case RuleNewAccount:
+		wallet, err := q.GetWallet(ctx, arg.FromWalletID)
+		if err != nil {
+			return false, err
+		}
+
+		user, err := q.GetUser(ctx, wallet.Owner)
+		if err != nil {
+			return false, err
+		}
+
+		return now.Sub(user.CreatedAt) < rule.MaxAge, nil
+
+	case RuleRoundAmountBurst:
+		if arg.Amount%rule.MultipleOf != 0 {
+			return false, nil
+		}
+
+		count, err := q.CountRoundTransfers(ctx, db.CountRoundTransfersParams{
+			WalletID:   arg.FromWalletID,
+			Since:      now.Add(-rule.Window),
+			MultipleOf: rule.MultipleOf,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return count+1 >= rule.Count, nil
+
+	case RuleFanOut:
+		count, err := q.CountOtherRecipients(ctx, db.CountOtherRecipientsParams{
+			WalletID:   arg.FromWalletID,
+			ToWalletID: arg.ToWalletID,
+			Since:      now.Add(-rule.Window),
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return count+1 > rule.MaxRecipients, nil
+	}
+
+	return false, nil
+}
+
+var severity = map[string]int{
+	db.RiskAllow:  0,
+	db.RiskReview: 1,
+	db.RiskBlock:  2,
+}
+
+func mostSevere(a, b string) string {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
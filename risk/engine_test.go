@@ -0,0 +1,215 @@
+package risk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `
+review_score: 50
+block_score: 100
+rules:
+  - name: burst
+    type: velocity
+    max_transfers: 5
+    window: 10m
+    score: 40
+  - name: first_big_transfer
+    type: new_recipient
+    min_amount: 100000
+    score: 30
+  - name: fresh_account
+    type: new_account
+    max_age: 24h
+    score: 20
+  - name: round_amounts
+    type: round_amount_burst
+    multiple_of: 10000
+    count: 3
+    window: 1h
+    score: 10
+  - name: mule
+    type: fan_out
+    max_recipients: 10
+    window: 1h
+    score: 0
+    action: block
+`
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(testRules))
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 5)
+	require.Equal(t, int32(50), rules.ReviewScore)
+	require.Equal(t, 10*time.Minute, rules.Rules[0].Window)
+	require.NotEmpty(t, rules.Version)
+
+	json, err := ParseRules([]byte(`{"review_score": 10, "rules": [{"name": "a", "type": "fan_out", "max_recipients": 3, "window": "1h", "score": 10}]}`))
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, json.Rules[0].Window)
+	require.NotEqual(t, rules.Version, json.Version)
+
+	invalid := []string{
+		`rules: [{name: a, type: unknown}]`,
+		`rules: [{type: velocity, max_transfers: 1, window: 1m}]`,
+		`rules: [{name: a, type: velocity}]`,
+		`rules: [{name: a, type: new_account, max_age: 1h, action: hold}]`,
+		`rules: [{name: a, type: new_account, max_age: 1h}, {name: a, type: new_account, max_age: 2h}]`,
+		`rules: [`,
+	}
+
+	for _, data := range invalid {
+		_, err := ParseRules([]byte(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestEvaluateTransfer(t *testing.T) {
+	now := time.Now()
+	from := util.RandomInt(1, 100)
+	arg := db.TrasferTxParms{FromWalletID: from, ToWalletID: from + 100, Amount: 200000}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		outcome    string
+		score      int32
+		rules      []string
+	}{
+		{
+			name: "Allow",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubQueries(store, now, 0, 1, 72*time.Hour, 0, 0)
+			},
+			outcome: db.RiskAllow,
+			score:   0,
+			rules:   []string{},
+		},
+		{
+			name: "ReviewByScore",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubQueries(store, now, 5, 0, 72*time.Hour, 0, 0)
+			},
+			outcome: db.RiskReview,
+			score:   70,
+			rules:   []string{"burst", "first_big_transfer"},
+		},
+		{
+			name: "BlockByScore",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubQueries(store, now, 5, 0, time.Hour, 2, 0)
+			},
+			outcome: db.RiskBlock,
+			score:   100,
+			rules:   []string{"burst", "first_big_transfer", "fresh_account", "round_amounts"},
+		},
+		{
+			name: "BlockByAction",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubQueries(store, now, 0, 1, 72*time.Hour, 0, 10)
+			},
+			outcome: db.RiskBlock,
+			score:   0,
+			rules:   []string{"mule"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			engine := NewEngine()
+			engine.now = func() time.Time { return now }
+			require.NoError(t, engine.Load([]byte(testRules)))
+
+			assessment, err := engine.EvaluateTransfer(context.Background(), store, arg)
+			require.NoError(t, err)
+			require.Equal(t, tc.outcome, assessment.Outcome)
+			require.Equal(t, tc.score, assessment.Score)
+			require.Equal(t, tc.rules, assessment.Rules)
+			require.Equal(t, engine.Rules().Version, assessment.Version)
+		})
+	}
+}
+
+func TestEvaluateTransferNoRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	assessment, err := NewEngine().EvaluateTransfer(context.Background(), mockdb.NewMockStore(ctrl), db.TrasferTxParms{Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, db.RiskAllow, assessment.Outcome)
+	require.Empty(t, assessment.Rules)
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRules), 0o600))
+
+	engine := NewEngine()
+	require.NoError(t, engine.LoadFile(path))
+	version := engine.Rules().Version
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Watch(ctx, path, 10*time.Millisecond)
+
+	// an invalid file keeps the current rules
+	require.NoError(t, os.WriteFile(path, []byte("rules: ["), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, version, engine.Rules().Version)
+
+	require.NoError(t, os.WriteFile(path, []byte("review_score: 10\nrules: []\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+
+	require.Eventually(t, func() bool {
+		return engine.Rules().Version != version
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(10), engine.Rules().ReviewScore)
+	require.Empty(t, engine.Rules().Rules)
+}
+
+func stubQueries(store *mockdb.MockStore, now time.Time, recent, sent int64, age time.Duration, round, recipients int64) {
+	owner := util.RandomString(6)
+
+	store.EXPECT().
+		GetOutgoingTransferTotals(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetOutgoingTransferTotalsRow{TransferCount: recent}, nil)
+	store.EXPECT().
+		CountTransfersBetween(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(sent, nil)
+	store.EXPECT().
+		GetWallet(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.Wallet{Owner: owner}, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), owner).
+		Times(1).
+		Return(db.User{Username: owner, CreatedAt: now.Add(-age)}, nil)
+	store.EXPECT().
+		CountRoundTransfers(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(round, nil)
+	store.EXPECT().
+		CountOtherRecipients(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(recipients, nil)
+}
@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule types
+const (
+	// RuleVelocity matches when the sender already made MaxTransfers within Window
+	RuleVelocity = "velocity"
+	// RuleNewRecipient matches the first transfer to a recipient of at least MinAmount
+	RuleNewRecipient = "new_recipient"
+	// RuleNewAccount matches senders whose user was created less than MaxAge ago
+	RuleNewAccount = "new_account"
+	// RuleRoundAmountBurst matches when this transfer makes Count transfers
+	// that are a multiple of MultipleOf within Window
+	RuleRoundAmountBurst = "round_amount_burst"
+	// RuleFanOut matches when this transfer makes more than MaxRecipients
+	// distinct recipients within Window
+	RuleFanOut = "fan_out"
+)
+
+// Rule is a single check on a transfer. Matching rules add their score
+// to the transfer and may force an outcome with Action
+type Rule struct {
+	Name   string `yaml:"name" json:"name"`
+	Type   string `yaml:"type" json:"type"`
+	Score  int32  `yaml:"score" json:"score"`
+	Action string `yaml:"action,omitempty" json:"action,omitempty"`
+
+	MaxTransfers  int64         `yaml:"max_transfers,omitempty" json:"max_transfers,omitempty"`
+	Window        time.Duration `yaml:"window,omitempty" json:"window,omitempty"`
+	MinAmount     int64         `yaml:"min_amount,omitempty" json:"min_amount,omitempty"`
+	MaxAge        time.Duration `yaml:"max_age,omitempty" json:"max_age,omitempty"`
+	MultipleOf    int64         `yaml:"multiple_of,omitempty" json:"multiple_of,omitempty"`
+	Count         int64         `yaml:"count,omitempty" json:"count,omitempty"`
+	MaxRecipients int64         `yaml:"max_recipients,omitempty" json:"max_recipients,omitempty"`
+}
+
+// RuleSet is the content of a rules file. Transfers scoring at least
+// ReviewScore go to review and at least BlockScore are blocked, a zero
+// threshold is disabled
+type RuleSet struct {
+	Version     string `yaml:"-" json:"version"`
+	ReviewScore int32  `yaml:"review_score" json:"review_score"`
+	BlockScore  int32  `yaml:"block_score" json:"block_score"`
+	Rules       []Rule `yaml:"rules" json:"rules"`
+}
+
+// ParseRules parses a rule set written in YAML or JSON. The version is
+// derived from the content so every decision can be traced to its rules
+func ParseRules(data []byte) (RuleSet, error) {
+	var rules RuleSet
+
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return RuleSet{}, fmt.Errorf("cannot parse risk rules: %w", err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return RuleSet{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	rules.Version = hex.EncodeToString(sum[:6])
+
+	return rules, nil
+}
+
+func (rules RuleSet) validate() error {
+	names := make(map[string]bool)
+
+	for _, rule := range rules.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("risk rule of type %q has no name", rule.Type)
+		}
+
+		if names[rule.Name] {
+			return fmt.Errorf("duplicated risk rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+
+		switch rule.Action {
+		case "", db.RiskAllow, db.RiskReview, db.RiskBlock:
+		default:
+			return fmt.Errorf("risk rule %q has invalid action %q", rule.Name, rule.Action)
+		}
+
+		var valid bool
+
+		switch rule.Type {
+		case RuleVelocity:
+			valid = rule.MaxTransfers > 0 && rule.Window > 0
+		case RuleNewRecipient:
+			valid = rule.MinAmount >= 0
+		case RuleNewAccount:
+			valid = rule.MaxAge > 0
+		case RuleRoundAmountBurst:
+			valid = rule.MultipleOf > 0 && rule.Count > 0 && rule.Window > 0
+		case RuleFanOut:
+			valid = rule.MaxRecipients > 0 && rule.Window > 0
+		default:
+			return fmt.Errorf("risk rule %q has unknown type %q", rule.Name, rule.Type)
+		}
+
+		if !valid {
+			return fmt.Errorf("risk rule %q has invalid parameters for %s", rule.Name, rule.Type)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package stream
+
+import "sync"
+
+// Broker fans out wallet change signals to the streams watching that wallet.
+// A signal carries no data: subscribers reload what changed from the database,
+// so signals can be coalesced and a slow subscriber never blocks the others
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+}
+
+// NewBroker creates a new Broker
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int64]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers interest in a wallet. The returned function must be
+// called to release the subscription
+func (broker *Broker) Subscribe(walletID int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	broker.mu.Lock()
+	if broker.subscribers[walletID] == nil {
+		broker.subscribers[walletID] = make(map[chan struct{}]struct{})
+	}
+	broker.subscribers[walletID][ch] = struct{}{}
+	broker.mu.Unlock()
+
+	unsubscribe := func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+
+		delete(broker.subscribers[walletID], ch)
+		if len(broker.subscribers[walletID]) == 0 {
+			delete(broker.subscribers, walletID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish signals every subscriber of the wallet that it changed
+func (broker *Broker) Publish(walletID int64) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for ch := range broker.subscribers[walletID] {
+		signal(ch)
+	}
+}
+
+// PublishAll signals every subscriber, used when notifications may have been lost
+func (broker *Broker) PublishAll() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for _, subscribers := range broker.subscribers {
+		for ch := range subscribers {
+			signal(ch)
+		}
+	}
+}
+
+// signal does not block: a pending signal already tells the subscriber to reload
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,31 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker()
+
+	changes1, unsubscribe1 := broker.Subscribe(1)
+	changes2, unsubscribe2 := broker.Subscribe(2)
+	defer unsubscribe2()
+
+	broker.Publish(1)
+	broker.Publish(1)
+
+	require.Len(t, changes1, 1)
+	require.Len(t, changes2, 0)
+
+	<-changes1
+	unsubscribe1()
+
+	broker.Publish(1)
+	require.Len(t, changes1, 0)
+	require.NotContains(t, broker.subscribers, int64(1))
+
+	broker.PublishAll()
+	require.Len(t, changes2, 1)
+}
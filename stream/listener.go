@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WalletEventsChannel is the Postgres channel notified with the wallet id
+// whenever an entry is created or a balance changes
+const WalletEventsChannel = "wallet_events"
+
+const listenerPingInterval = time.Minute
+
+// Listen relays Postgres notifications on WalletEventsChannel to the broker
+// until the context is done
+func Listen(ctx context.Context, dataSource string, broker *Broker) error {
+	listener := pq.NewListener(dataSource, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("wallet events listener:", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(WalletEventsChannel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// a nil notification means the connection was re-established
+			// and notifications sent meanwhile were lost
+			if notification == nil {
+				broker.PublishAll()
+				continue
+			}
+
+			walletID, err := strconv.ParseInt(notification.Extra, 10, 64)
+			if err != nil {
+				log.Println("invalid wallet event:", notification.Extra)
+				continue
+			}
+			broker.Publish(walletID)
+		case <-ticker.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SSEWriter writes events in the text/event-stream format
+type SSEWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewSSEWriter sends the event stream headers and creates a new SSEWriter
+func NewSSEWriter(w http.ResponseWriter) (*SSEWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &SSEWriter{w: w, flusher: flusher}, nil
+}
+
+// WriteEvent writes a single event and flushes it to the client
+func (writer *SSEWriter) WriteEvent(event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(writer.w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(writer.w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+
+	writer.flusher.Flush()
+	return nil
+}
+
+// WriteHeartbeat writes a comment line so proxies keep the connection open
+func (writer *SSEWriter) WriteHeartbeat() error {
+	if _, err := fmt.Fprint(writer.w, ": ping\n\n"); err != nil {
+		return err
+	}
+
+	writer.flusher.Flush()
+	return nil
+}
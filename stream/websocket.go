@@ -0,0 +1,160 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxClientMessageSize = 1 << 16
+	websocketWriteLimit  = 10 * time.Second
+)
+
+// ErrOriginNotAllowed is returned when a browser opens the socket from an
+// origin that is neither the API itself nor one of the allowed ones
+var ErrOriginNotAllowed = errors.New("websocket origin not allowed")
+
+// IsWebSocketUpgrade reports whether the request asks to switch to the WebSocket protocol
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+// WebSocketWriter is a server side WebSocket connection that only pushes
+// events. Messages sent by the client are discarded, control frames are
+// answered by the connection
+type WebSocketWriter struct {
+	conn   *websocket.Conn
+	mu     sync.Mutex
+	closed chan struct{}
+	once   sync.Once
+}
+
+// UpgradeWebSocket checks the origin, performs the opening handshake and
+// takes over the connection. Requests without an Origin header come from
+// clients other than browsers and are always accepted
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request, allowedOrigins []string) (*WebSocketWriter, error) {
+	if !originAllowed(r, allowedOrigins) {
+		return nil, ErrOriginNotAllowed
+	}
+
+	upgrader := websocket.Upgrader{
+		// the origin was checked above
+		CheckOrigin: func(r *http.Request) bool { return true },
+		// failures are reported to the caller, which writes the response
+		Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {},
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(maxClientMessageSize)
+
+	writer := &WebSocketWriter{
+		conn:   conn,
+		closed: make(chan struct{}),
+	}
+	go writer.readLoop()
+
+	return writer, nil
+}
+
+// Closed is done once the client closes the connection or it breaks
+func (writer *WebSocketWriter) Closed() <-chan struct{} {
+	return writer.closed
+}
+
+// WriteEvent sends the event as a JSON text message
+func (writer *WebSocketWriter) WriteEvent(event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	message, err := json.Marshal(wireEvent{
+		ID:    event.ID,
+		Event: event.Type,
+		Data:  data,
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if err := writer.conn.SetWriteDeadline(time.Now().Add(websocketWriteLimit)); err != nil {
+		return err
+	}
+	return writer.conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// WriteHeartbeat sends a ping so proxies keep the connection open
+func (writer *WebSocketWriter) WriteHeartbeat() error {
+	return writer.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteLimit))
+}
+
+// Close sends a close frame and releases the connection. A close frame the
+// client already sent was answered by the connection, so that case isn't an error
+func (writer *WebSocketWriter) Close() error {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+
+	err := writer.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(websocketWriteLimit))
+	if errors.Is(err, websocket.ErrCloseSent) {
+		err = nil
+	}
+
+	writer.markClosed()
+
+	if closeErr := writer.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (writer *WebSocketWriter) markClosed() {
+	writer.once.Do(func() {
+		close(writer.closed)
+	})
+}
+
+func (writer *WebSocketWriter) readLoop() {
+	for {
+		// the connection answers pings and close frames while reading, and
+		// fails on protocol errors like oversized or fragmented control frames
+		if _, _, err := writer.conn.NextReader(); err != nil {
+			break
+		}
+	}
+
+	// only signal the writer, Close releases the connection
+	writer.markClosed()
+}
+
+// originAllowed accepts requests without an Origin header, from the host the
+// request was sent to, or from one of the allowed origins
+func originAllowed(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
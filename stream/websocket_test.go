@@ -0,0 +1,145 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebSocketServer(t *testing.T, allowedOrigins []string, handle func(writer *WebSocketWriter)) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer, err := UpgradeWebSocket(w, r, allowedOrigins)
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		handle(writer)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWebSocketWriter(t *testing.T) {
+	closeErr := make(chan error, 1)
+
+	server := newWebSocketServer(t, nil, func(writer *WebSocketWriter) {
+		err := writer.WriteEvent(Event{ID: "7", Type: "entry", Data: map[string]int{"amount": 10}})
+		require.NoError(t, err)
+
+		<-writer.Closed()
+		closeErr <- writer.Close()
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	messageType, payload, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.TextMessage, messageType)
+
+	var event wireEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, "7", event.ID)
+	require.Equal(t, "entry", event.Event)
+	require.JSONEq(t, `{"amount":10}`, string(event.Data))
+
+	err = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+	// the client's close frame was already answered
+	require.NoError(t, <-closeErr)
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	server := newWebSocketServer(t, []string{"https://app.example.com"}, func(writer *WebSocketWriter) {
+		writer.Close()
+	})
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	testCases := []struct {
+		name   string
+		origin string
+		status int
+	}{
+		{name: "NoOrigin", status: http.StatusSwitchingProtocols},
+		{name: "SameHost", origin: server.URL, status: http.StatusSwitchingProtocols},
+		{name: "Allowed", origin: "https://app.example.com", status: http.StatusSwitchingProtocols},
+		{name: "OtherOrigin", origin: "https://evil.example.com", status: http.StatusForbidden},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+
+			conn, response, err := websocket.DefaultDialer.Dial(url, header)
+			if err == nil {
+				conn.Close()
+			}
+			require.NotNil(t, response)
+			require.Equal(t, tc.status, response.StatusCode)
+		})
+	}
+}
+
+func TestWebSocketRejectsOversizedControlFrame(t *testing.T) {
+	server := newWebSocketServer(t, nil, func(writer *WebSocketWriter) {
+		<-writer.Closed()
+		writer.Close()
+	})
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+
+	// a masked ping with a 200 byte payload, control frames may carry at most 125
+	frame := []byte{0x89, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, 200)
+	frame = append(frame, 1, 2, 3, 4)
+	frame = append(frame, bytes.Repeat([]byte("a"), 200)...)
+	_, err = conn.Write(frame)
+	require.NoError(t, err)
+
+	// the server answers with a protocol error close frame
+	header := make([]byte, 4)
+	_, err = io.ReadFull(reader, header)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x88), header[0])
+	require.Equal(t, uint16(websocket.CloseProtocolError), binary.BigEndian.Uint16(header[2:]))
+}
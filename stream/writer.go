@@ -0,0 +1,23 @@
+package stream
+
+import "encoding/json"
+
+// Event is a single message sent to a stream client
+type Event struct {
+	// ID is empty for events that cannot be resumed from
+	ID   string
+	Type string
+	Data any
+}
+
+// Writer sends events to a connected client
+type Writer interface {
+	WriteEvent(event Event) error
+	WriteHeartbeat() error
+}
+
+type wireEvent struct {
+	ID    string          `json:"id,omitempty"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
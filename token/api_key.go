@@ -0,0 +1,107 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix marks bearer credentials that are API keys instead of user tokens
+const APIKeyPrefix = "sk_"
+
+// APIKey is a freshly generated key. Key is only shown once to its owner,
+// only Prefix and HashedKey are meant to be stored
+type APIKey struct {
+	Key       string
+	Prefix    string
+	HashedKey string
+}
+
+// NewAPIKey generates a random API key in the format sk_<prefix>_<secret>
+func NewAPIKey() (APIKey, error) {
+	prefix := make([]byte, 6)
+	if _, err := rand.Read(prefix); err != nil {
+		return APIKey{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, err
+	}
+
+	apiKey := APIKey{Prefix: hex.EncodeToString(prefix)}
+	apiKey.Key = APIKeyPrefix + apiKey.Prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey.HashedKey = HashAPIKey(apiKey.Key)
+
+	return apiKey, nil
+}
+
+// HashAPIKey returns the hex encoded sha256 of the key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAPIKey extracts the lookup prefix of an API key
+func ParseAPIKey(key string) (string, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return "", ErrInvalidToken
+	}
+
+	prefix, secret, found := strings.Cut(strings.TrimPrefix(key, APIKeyPrefix), "_")
+	if !found || prefix == "" || secret == "" {
+		return "", ErrInvalidToken
+	}
+
+	return prefix, nil
+}
+
+// APIKeySigner derives the secrets API keys sign requests with. The secret is
+// an HMAC of the hashed key under a server side key, so the stored hash alone
+// is not enough to forge a signature
+type APIKeySigner struct {
+	key []byte
+}
+
+// NewAPIKeySigner creates a new APIKeySigner
+func NewAPIKeySigner(key string) (*APIKeySigner, error) {
+	if len(key) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	return &APIKeySigner{key: []byte(key)}, nil
+}
+
+// SigningSecret returns the hex encoded signing secret of a hashed key
+func (signer *APIKeySigner) SigningSecret(hashedKey string) string {
+	mac := hmac.New(sha256.New, signer.key)
+	mac.Write([]byte(hashedKey))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest computes the hex encoded HMAC-SHA256 of a request. The signed
+// message is the method, path, timestamp, nonce and body hash joined by new lines
+func SignRequest(secret string, method string, path string, timestamp string, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	message := strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequestSignature checks the signature in constant time
+func VerifyRequestSignature(secret string, signature string, method string, path string, timestamp string, nonce string, body []byte) bool {
+	expected := SignRequest(secret, method, path, timestamp, nonce, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
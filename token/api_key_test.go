@@ -0,0 +1,64 @@
+package token
+
+import (
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	apiKey, err := NewAPIKey()
+	require.NoError(t, err)
+	require.NotEmpty(t, apiKey.Key)
+	require.Equal(t, HashAPIKey(apiKey.Key), apiKey.HashedKey)
+
+	prefix, err := ParseAPIKey(apiKey.Key)
+	require.NoError(t, err)
+	require.Equal(t, apiKey.Prefix, prefix)
+
+	other, err := NewAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, apiKey.Key, other.Key)
+	require.NotEqual(t, apiKey.Prefix, other.Prefix)
+
+	_, err = ParseAPIKey(util.RandomString(20))
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	_, err = ParseAPIKey(APIKeyPrefix + "abc")
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestSignRequest(t *testing.T) {
+	apiKey, err := NewAPIKey()
+	require.NoError(t, err)
+
+	body := []byte(`{"amount":100}`)
+	signature := SignRequest(apiKey.HashedKey, "POST", "/transfers", "1700000000", "nonce", body)
+	require.Len(t, signature, 64)
+
+	require.True(t, VerifyRequestSignature(apiKey.HashedKey, signature, "post", "/transfers", "1700000000", "nonce", body))
+	require.False(t, VerifyRequestSignature(apiKey.HashedKey, signature, "POST", "/transfers", "1700000001", "nonce", body))
+	require.False(t, VerifyRequestSignature(apiKey.HashedKey, signature, "POST", "/transfers", "1700000000", "nonce", []byte(`{"amount":999}`)))
+	require.False(t, VerifyRequestSignature(HashAPIKey("other"), signature, "POST", "/transfers", "1700000000", "nonce", body))
+}
+
+func TestAPIKeySigner(t *testing.T) {
+	_, err := NewAPIKeySigner(util.RandomString(31))
+	require.Error(t, err)
+
+	signer, err := NewAPIKeySigner(util.RandomString(32))
+	require.NoError(t, err)
+
+	apiKey, err := NewAPIKey()
+	require.NoError(t, err)
+
+	secret := signer.SigningSecret(apiKey.HashedKey)
+	require.Len(t, secret, 64)
+	require.NotEqual(t, apiKey.HashedKey, secret)
+	require.Equal(t, secret, signer.SigningSecret(apiKey.HashedKey))
+
+	other, err := NewAPIKeySigner(util.RandomString(32))
+	require.NoError(t, err)
+	require.NotEqual(t, secret, other.SigningSecret(apiKey.HashedKey))
+}
@@ -0,0 +1,11 @@
+package util
+
+// Cashback returns the rate, in basis points, of the amount paid rounded
+// down, capped at max unless max is zero
+func Cashback(amount, rate, max int64) int64 {
+	cashback := amount * rate / FullShare
+	if max > 0 && cashback > max {
+		return max
+	}
+	return cashback
+}
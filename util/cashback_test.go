@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCashback(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   int64
+		rate     int64
+		max      int64
+		cashback int64
+	}{
+		{name: "Rate", amount: 10000, rate: 500, cashback: 500},
+		{name: "RoundedDown", amount: 999, rate: 500, cashback: 49},
+		{name: "Capped", amount: 100000, rate: 500, max: 2000, cashback: 2000},
+		{name: "BelowCap", amount: 10000, rate: 500, max: 2000, cashback: 500},
+		{name: "TooSmall", amount: 19, rate: 500, cashback: 0},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.cashback, Cashback(tc.amount, tc.rate, tc.max))
+		})
+	}
+}
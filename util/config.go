@@ -1,12 +1,107 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 // Config struct have all configurations of the application
 type Config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBDriver            string        `mapstructure:"DB_DRIVER"`
+	DBSource            string        `mapstructure:"DB_SOURCE"`
+	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	APIKeySigningKey    string        `mapstructure:"API_KEY_SIGNING_KEY"`
+	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	WorkerInterval      time.Duration `mapstructure:"WORKER_INTERVAL"`
+
+	WebhookMaxAttempts          int32 `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookDisableAfterFailures int32 `mapstructure:"WEBHOOK_DISABLE_AFTER_FAILURES"`
+
+	RateLimitBackend   string `mapstructure:"RATE_LIMIT_BACKEND"`
+	RateLimitDefault   string `mapstructure:"RATE_LIMIT_DEFAULT"`
+	RateLimitLogin     string `mapstructure:"RATE_LIMIT_LOGIN"`
+	RateLimitTransfers string `mapstructure:"RATE_LIMIT_TRANSFERS"`
+	RateLimitAuth      string `mapstructure:"RATE_LIMIT_AUTH"`
+	// TrustedProxies lists the proxies whose X-Forwarded-For is honored; empty trusts none
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	// StreamAllowedOrigins lists the browser origins, besides the API's own, that may open wallet WebSockets
+	StreamAllowedOrigins []string `mapstructure:"STREAM_ALLOWED_ORIGINS"`
+
+	LimitPerTransfer        int64         `mapstructure:"LIMIT_PER_TRANSFER"`
+	LimitDaily              int64         `mapstructure:"LIMIT_DAILY"`
+	LimitMonthly            int64         `mapstructure:"LIMIT_MONTHLY"`
+	LimitDailyCount         int64         `mapstructure:"LIMIT_DAILY_COUNT"`
+	LimitNightPerTransfer   int64         `mapstructure:"LIMIT_NIGHT_PER_TRANSFER"`
+	LimitNightly            int64         `mapstructure:"LIMIT_NIGHTLY"`
+	LimitTimezone           string        `mapstructure:"LIMIT_TIMEZONE"`
+	LimitNightStart         int           `mapstructure:"LIMIT_NIGHT_START"`
+	LimitNightEnd           int           `mapstructure:"LIMIT_NIGHT_END"`
+	LimitIncreaseCoolingOff time.Duration `mapstructure:"LIMIT_INCREASE_COOLING_OFF"`
+
+	RiskRulesPath      string        `mapstructure:"RISK_RULES_PATH"`
+	RiskReloadInterval time.Duration `mapstructure:"RISK_RELOAD_INTERVAL"`
+
+	ScheduledTransferMaxAttempts   int32         `mapstructure:"SCHEDULED_TRANSFER_MAX_ATTEMPTS"`
+	ScheduledTransferRetryInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_RETRY_INTERVAL"`
+
+	BRCodeMerchantCity string `mapstructure:"BRCODE_MERCHANT_CITY"`
+	BRCodeLocationURL  string `mapstructure:"BRCODE_LOCATION_URL"`
+
+	// PlatformRevenueOwner owns the wallets fees are credited to, no fees are charged when empty
+	PlatformRevenueOwner string `mapstructure:"PLATFORM_REVENUE_OWNER"`
+
+	// EscrowReleaseAfter is how long escrows wait for the buyer before they are released to the seller
+	EscrowReleaseAfter time.Duration `mapstructure:"ESCROW_RELEASE_AFTER"`
+
+	// BlobBackend stores dispute evidence, only local is supported
+	BlobBackend  string `mapstructure:"BLOB_BACKEND"`
+	BlobLocalDir string `mapstructure:"BLOB_LOCAL_DIR"`
+
+	// DisputeOpenWindow is how long after a transfer its payer may dispute it
+	DisputeOpenWindow time.Duration `mapstructure:"DISPUTE_OPEN_WINDOW"`
+	// DisputeResponseWindow is how long merchants have to respond before the dispute goes to review
+	DisputeResponseWindow time.Duration `mapstructure:"DISPUTE_RESPONSE_WINDOW"`
+	// DisputeNegativeReserve is how far below zero a reversal may take a merchant balance
+	DisputeNegativeReserve int64 `mapstructure:"DISPUTE_NEGATIVE_RESERVE"`
+	DisputeEvidenceMaxSize int64 `mapstructure:"DISPUTE_EVIDENCE_MAX_SIZE"`
+
+	// InstallmentFundingOwner owns the wallets that pay merchants upfront for
+	// installment plans, plans can't be funded when empty
+	InstallmentFundingOwner string `mapstructure:"INSTALLMENT_FUNDING_OWNER"`
+	// InstallmentMaxAttempts is how many times an installment is tried before it is overdue
+	InstallmentMaxAttempts   int32         `mapstructure:"INSTALLMENT_MAX_ATTEMPTS"`
+	InstallmentRetryInterval time.Duration `mapstructure:"INSTALLMENT_RETRY_INTERVAL"`
+
+	// SubscriptionMaxAttempts is how many times a period is charged before the subscription is cancelled
+	SubscriptionMaxAttempts   int32         `mapstructure:"SUBSCRIPTION_MAX_ATTEMPTS"`
+	SubscriptionRetryInterval time.Duration `mapstructure:"SUBSCRIPTION_RETRY_INTERVAL"`
+
+	// CheckoutURL and PaymentLinkURL are prefixed to the tokens of checkout
+	// sessions and payment links to make the URLs customers pay at
+	CheckoutURL    string `mapstructure:"CHECKOUT_URL"`
+	PaymentLinkURL string `mapstructure:"PAYMENT_LINK_URL"`
+	// CheckoutSessionTTL is how long sessions stay open when merchants don't set an expiry
+	CheckoutSessionTTL time.Duration `mapstructure:"CHECKOUT_SESSION_TTL"`
+
+	// InvoiceReminderInterval is the time between reminders of an overdue invoice
+	InvoiceReminderInterval time.Duration `mapstructure:"INVOICE_REMINDER_INTERVAL"`
+
+	// CashbackFundingOwner owns the marketing wallets cashback is paid from,
+	// no cashback is granted when empty
+	CashbackFundingOwner string `mapstructure:"CASHBACK_FUNDING_OWNER"`
+	// CashbackRetryInterval is how long cashback that could not be paid waits to be tried again
+	CashbackRetryInterval time.Duration `mapstructure:"CASHBACK_RETRY_INTERVAL"`
+
+	// ChainSigningKey is the secret the entry chain checkpoints are signed with
+	ChainSigningKey         string        `mapstructure:"CHAIN_SIGNING_KEY"`
+	ChainCheckpointInterval time.Duration `mapstructure:"CHAIN_CHECKPOINT_INTERVAL"`
+	// ChainCheckpointDelay leaves the newest entries out of a checkpoint,
+	// their transactions may not have committed yet
+	ChainCheckpointDelay time.Duration `mapstructure:"CHAIN_CHECKPOINT_DELAY"`
 }
 
 // LoadConfig reads the configurations in app.env
@@ -25,3 +120,35 @@ func LoadConfig(path string) (config Config, err error) {
 	err = viper.Unmarshal(&config)
 	return
 }
+
+// TransferLimits returns the default limits of every user
+func (config Config) TransferLimits() TransferLimits {
+	return TransferLimits{
+		PerTransfer:      config.LimitPerTransfer,
+		Daily:            config.LimitDaily,
+		Monthly:          config.LimitMonthly,
+		DailyCount:       config.LimitDailyCount,
+		NightPerTransfer: config.LimitNightPerTransfer,
+		Nightly:          config.LimitNightly,
+	}
+}
+
+// LimitSchedule returns when days, months and nights start for the limits
+func (config Config) LimitSchedule() (LimitSchedule, error) {
+	location, err := time.LoadLocation(config.LimitTimezone)
+	if err != nil {
+		return LimitSchedule{}, err
+	}
+
+	return LimitSchedule{
+		Location:   location,
+		NightStart: config.LimitNightStart,
+		NightEnd:   config.LimitNightEnd,
+	}, nil
+}
+
+// ChainKey returns the ed25519 key derived from ChainSigningKey
+func (config Config) ChainKey() ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(config.ChainSigningKey))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
@@ -1,5 +1,7 @@
 package util
 
+import "fmt"
+
 const (
 	USD = "USD"
 	BRL = "BRL"
@@ -16,3 +18,38 @@ func IsSupportedCurrency(currency string) bool {
 
 	return false
 }
+
+var numericCodes = map[string]string{
+	USD: "840",
+	BRL: "986",
+	EUR: "978",
+}
+
+// CurrencyNumericCode returns the ISO 4217 numeric code of a supported currency
+func CurrencyNumericCode(currency string) (string, bool) {
+	code, ok := numericCodes[currency]
+	return code, ok
+}
+
+// CurrencyFromNumericCode returns the supported currency with the ISO 4217 numeric code
+func CurrencyFromNumericCode(code string) (string, bool) {
+	for currency, numeric := range numericCodes {
+		if numeric == code {
+			return currency, true
+		}
+	}
+
+	return "", false
+}
+
+// FormatAmount returns an amount in cents with two decimals, the way it is
+// shown to users and written in BR Codes
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "163.67", FormatAmount(16367))
+	require.Equal(t, "0.05", FormatAmount(5))
+	require.Equal(t, "20.00", FormatAmount(2000))
+	require.Equal(t, "0.00", FormatAmount(0))
+	require.Equal(t, "-1.50", FormatAmount(-150))
+	require.Equal(t, "-0.05", FormatAmount(-5))
+}
@@ -0,0 +1,29 @@
+package util
+
+// Event types pushed to merchants through webhooks
+const (
+	EventTransferReceived  = "transfer.received"
+	EventRefundCreated     = "refund.created"
+	EventWalletFrozen      = "wallet.frozen"
+	EventCheckoutCompleted = "checkout.completed"
+	EventCheckoutExpired   = "checkout.expired"
+
+	EventSubscriptionCreated       = "subscription.created"
+	EventSubscriptionRenewed       = "subscription.renewed"
+	EventSubscriptionPaymentFailed = "subscription.payment_failed"
+	EventSubscriptionUpdated       = "subscription.updated"
+	EventSubscriptionCancelled     = "subscription.cancelled"
+)
+
+// IsSupportedEventType returns true if webhooks can subscribe to the event type
+func IsSupportedEventType(eventType string) bool {
+
+	switch eventType {
+	case EventTransferReceived, EventRefundCreated, EventWalletFrozen, EventCheckoutCompleted, EventCheckoutExpired:
+		return true
+	case EventSubscriptionCreated, EventSubscriptionRenewed, EventSubscriptionPaymentFailed, EventSubscriptionUpdated, EventSubscriptionCancelled:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Operations fees are charged for. Withdrawals and FX conversions aren't
+// operations of the API yet, so schedules for them are rejected
+const (
+	FeeTransfer        = "transfer"
+	FeeMerchantReceipt = "merchant_receipt"
+)
+
+// IsSupportedFeeOperation returns true if fees can be charged for the operation
+func IsSupportedFeeOperation(operation string) bool {
+
+	switch operation {
+	case FeeTransfer, FeeMerchantReceipt:
+		return true
+	}
+
+	return false
+}
+
+// How fees are computed
+const (
+	FeeFixed      = "fixed"
+	FeePercentage = "percentage"
+	FeeTiered     = "tiered"
+)
+
+// User types fee schedules apply to
+const (
+	FeeUserCustomer = "customer"
+	FeeUserMerchant = "merchant"
+)
+
+// FeeUserType returns the fee user type of a user
+func FeeUserType(isMerchant bool) string {
+	if isMerchant {
+		return FeeUserMerchant
+	}
+	return FeeUserCustomer
+}
+
+// FeeTier applies to amounts up to UpTo, the last tier has no upper bound
+type FeeTier struct {
+	UpTo        int64 `json:"up_to,omitempty"`
+	FixedAmount int64 `json:"fixed_amount"`
+	BasisPoints int64 `json:"basis_points"`
+}
+
+// FeeRule is how the fee of an operation is computed. Percentages are in
+// basis points, MinFee is a floor and MaxFee a cap, zero meaning no cap
+type FeeRule struct {
+	Kind        string    `json:"kind"`
+	FixedAmount int64     `json:"fixed_amount"`
+	BasisPoints int64     `json:"basis_points"`
+	Tiers       []FeeTier `json:"tiers,omitempty"`
+	MinFee      int64     `json:"min_fee"`
+	MaxFee      int64     `json:"max_fee"`
+}
+
+// ErrInvalidFeeRule is returned by FeeRule.Validate
+var ErrInvalidFeeRule = errors.New("invalid fee rule")
+
+// Validate checks the rule can compute fees
+func (rule FeeRule) Validate() error {
+	if rule.FixedAmount < 0 || rule.BasisPoints < 0 || rule.MinFee < 0 || rule.MaxFee < 0 {
+		return fmt.Errorf("%w: amounts must not be negative", ErrInvalidFeeRule)
+	}
+	if rule.MaxFee > 0 && rule.MinFee > rule.MaxFee {
+		return fmt.Errorf("%w: min_fee is above max_fee", ErrInvalidFeeRule)
+	}
+
+	switch rule.Kind {
+	case FeeFixed, FeePercentage:
+		if len(rule.Tiers) > 0 {
+			return fmt.Errorf("%w: only tiered fees have tiers", ErrInvalidFeeRule)
+		}
+	case FeeTiered:
+		if len(rule.Tiers) == 0 {
+			return fmt.Errorf("%w: tiered fees need tiers", ErrInvalidFeeRule)
+		}
+
+		var previous int64
+		for i, tier := range rule.Tiers {
+			last := i == len(rule.Tiers)-1
+			if tier.FixedAmount < 0 || tier.BasisPoints < 0 {
+				return fmt.Errorf("%w: tier %d has negative amounts", ErrInvalidFeeRule, i+1)
+			}
+			if !last && tier.UpTo <= previous {
+				return fmt.Errorf("%w: tiers must have increasing up_to", ErrInvalidFeeRule)
+			}
+			if last && tier.UpTo != 0 {
+				return fmt.Errorf("%w: the last tier must not have up_to", ErrInvalidFeeRule)
+			}
+			previous = tier.UpTo
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %s", ErrInvalidFeeRule, rule.Kind)
+	}
+
+	return nil
+}
+
+// Compute returns the fee for the amount. Percentages are rounded half up to
+// the cent, and the fee is never more than the amount
+func (rule FeeRule) Compute(amount int64) int64 {
+	var fee int64
+
+	switch rule.Kind {
+	case FeeFixed:
+		fee = rule.FixedAmount
+	case FeePercentage:
+		fee = rule.FixedAmount + percentOf(amount, rule.BasisPoints)
+	case FeeTiered:
+		for _, tier := range rule.Tiers {
+			if tier.UpTo == 0 || amount <= tier.UpTo {
+				fee = tier.FixedAmount + percentOf(amount, tier.BasisPoints)
+				break
+			}
+		}
+	}
+
+	if rule.MaxFee > 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+	if fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if fee > amount {
+		fee = amount
+	}
+
+	return fee
+}
+
+func percentOf(amount int64, basisPoints int64) int64 {
+	return (amount*basisPoints + FullShare/2) / FullShare
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFee(t *testing.T) {
+	tiers := []FeeTier{
+		{UpTo: 10000, FixedAmount: 50},
+		{UpTo: 100000, BasisPoints: 150},
+		{BasisPoints: 100},
+	}
+
+	testCases := []struct {
+		name   string
+		rule   FeeRule
+		amount int64
+		fee    int64
+	}{
+		{name: "Fixed", rule: FeeRule{Kind: FeeFixed, FixedAmount: 99}, amount: 5000, fee: 99},
+		{name: "FixedAboveAmount", rule: FeeRule{Kind: FeeFixed, FixedAmount: 99}, amount: 50, fee: 50},
+		{name: "Percentage", rule: FeeRule{Kind: FeePercentage, BasisPoints: 199}, amount: 10000, fee: 199},
+		{name: "RoundsHalfUp", rule: FeeRule{Kind: FeePercentage, BasisPoints: 250}, amount: 1020, fee: 26},
+		{name: "RoundsDown", rule: FeeRule{Kind: FeePercentage, BasisPoints: 250}, amount: 1019, fee: 25},
+		{name: "PercentagePlusFixed", rule: FeeRule{Kind: FeePercentage, FixedAmount: 30, BasisPoints: 100}, amount: 10000, fee: 130},
+		{name: "Capped", rule: FeeRule{Kind: FeePercentage, BasisPoints: 100, MaxFee: 500}, amount: 1000000, fee: 500},
+		{name: "Floor", rule: FeeRule{Kind: FeePercentage, BasisPoints: 100, MinFee: 25}, amount: 1000, fee: 25},
+		{name: "FirstTier", rule: FeeRule{Kind: FeeTiered, Tiers: tiers}, amount: 10000, fee: 50},
+		{name: "SecondTier", rule: FeeRule{Kind: FeeTiered, Tiers: tiers}, amount: 10001, fee: 150},
+		{name: "LastTier", rule: FeeRule{Kind: FeeTiered, Tiers: tiers}, amount: 200000, fee: 2000},
+		{name: "TieredCapped", rule: FeeRule{Kind: FeeTiered, Tiers: tiers, MaxFee: 1000}, amount: 200000, fee: 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.rule.Validate())
+			require.Equal(t, tc.fee, tc.rule.Compute(tc.amount))
+		})
+	}
+}
+
+func TestValidateFeeRule(t *testing.T) {
+	invalid := []FeeRule{
+		{Kind: "flat"},
+		{Kind: FeeFixed, FixedAmount: -1},
+		{Kind: FeePercentage, BasisPoints: 100, MinFee: 50, MaxFee: 10},
+		{Kind: FeeFixed, Tiers: []FeeTier{{FixedAmount: 1}}},
+		{Kind: FeeTiered},
+		{Kind: FeeTiered, Tiers: []FeeTier{{UpTo: 100}, {UpTo: 50}, {}}},
+		{Kind: FeeTiered, Tiers: []FeeTier{{UpTo: 100}, {UpTo: 200}}},
+		{Kind: FeeTiered, Tiers: []FeeTier{{UpTo: 100, BasisPoints: -1}, {}}},
+	}
+
+	for _, rule := range invalid {
+		require.ErrorIs(t, rule.Validate(), ErrInvalidFeeRule, "%+v", rule)
+	}
+}
@@ -0,0 +1,49 @@
+package util
+
+import "math"
+
+// InstallmentAmount is what one installment charges and how much of it pays
+// down the purchase, the rest being interest
+type InstallmentAmount struct {
+	Amount    int64 `json:"amount"`
+	Principal int64 `json:"principal"`
+}
+
+// InstallmentSchedule splits the principal into count monthly installments
+// charging rate, in basis points, of the outstanding principal as interest
+// every month. Installments with interest are all the same amount (the Price
+// table) except the last one, which pays off what rounding left. Without
+// interest the cents left by the division go one each to the first
+// installments
+func InstallmentSchedule(principal int64, count int, rate int64) []InstallmentAmount {
+	installments := make([]InstallmentAmount, count)
+
+	if rate == 0 {
+		for i := range installments {
+			amount := principal / int64(count)
+			if int64(i) < principal%int64(count) {
+				amount++
+			}
+			installments[i] = InstallmentAmount{Amount: amount, Principal: amount}
+		}
+		return installments
+	}
+
+	monthly := float64(rate) / FullShare
+	payment := int64(math.Round(float64(principal) * monthly / (1 - math.Pow(1+monthly, -float64(count)))))
+
+	outstanding := principal
+	for i := range installments {
+		interest := int64(math.Round(float64(outstanding) * monthly))
+
+		amortization := payment - interest
+		if i == count-1 || amortization > outstanding {
+			amortization = outstanding
+		}
+
+		installments[i] = InstallmentAmount{Amount: amortization + interest, Principal: amortization}
+		outstanding -= amortization
+	}
+
+	return installments
+}
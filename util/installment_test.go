@@ -0,0 +1,67 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallmentSchedule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		principal int64
+		count     int
+		rate      int64
+		amounts   []int64
+	}{
+		{
+			name:      "NoInterest",
+			principal: 1000,
+			count:     4,
+			amounts:   []int64{250, 250, 250, 250},
+		},
+		{
+			name:      "NoInterestRemainder",
+			principal: 1001,
+			count:     3,
+			amounts:   []int64{334, 334, 333},
+		},
+		{
+			name:      "PriceTable",
+			principal: 100000,
+			count:     3,
+			rate:      200,
+			amounts:   []int64{34675, 34675, 34677},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			installments := InstallmentSchedule(tc.principal, tc.count, tc.rate)
+			require.Len(t, installments, tc.count)
+
+			var principal int64
+			for i, installment := range installments {
+				require.Equal(t, tc.amounts[i], installment.Amount)
+				require.GreaterOrEqual(t, installment.Amount, installment.Principal)
+				principal += installment.Principal
+			}
+			require.Equal(t, tc.principal, principal)
+		})
+	}
+}
+
+func TestInstallmentScheduleRounding(t *testing.T) {
+	for principal := int64(1); principal <= 20000; principal += 13 {
+		for _, count := range []int{2, 7, 12} {
+			installments := InstallmentSchedule(principal, count, 299)
+
+			var sum int64
+			for _, installment := range installments {
+				require.GreaterOrEqual(t, installment.Principal, int64(0))
+				sum += installment.Principal
+			}
+			require.Equal(t, principal, sum)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidInvoice is returned when the items of an invoice can't be totalled
+var ErrInvalidInvoice = errors.New("invalid invoice")
+
+// InvoiceLine is an item of an invoice. Discount is taken off quantity times
+// the unit amount and TaxRate, in basis points, applies to what's left
+type InvoiceLine struct {
+	Quantity   int64  `json:"quantity"`
+	UnitAmount int64  `json:"unit_amount"`
+	Discount   int64  `json:"discount"`
+	TaxName    string `json:"tax_name"`
+	TaxRate    int64  `json:"tax_rate"`
+}
+
+// InvoiceLineTotal is what an item adds up to
+type InvoiceLineTotal struct {
+	Tax    int64 `json:"tax"`
+	Amount int64 `json:"amount"`
+}
+
+// TaxLine is the tax charged at one rate over the whole invoice
+type TaxLine struct {
+	Name   string `json:"name"`
+	Rate   int64  `json:"rate"`
+	Base   int64  `json:"base"`
+	Amount int64  `json:"amount"`
+}
+
+// InvoiceTotals is what the invoice adds up to. Total is the subtotal less
+// the discount plus the tax
+type InvoiceTotals struct {
+	Lines    []InvoiceLineTotal `json:"lines"`
+	Subtotal int64              `json:"subtotal"`
+	Discount int64              `json:"discount"`
+	Tax      int64              `json:"tax"`
+	Total    int64              `json:"total"`
+	Taxes    []TaxLine          `json:"taxes"`
+}
+
+// ComputeInvoice totals the items of an invoice. The tax of each item is
+// rounded half up to the cent and the breakdown has one line per tax name and
+// rate, in the order they first appear
+func ComputeInvoice(lines []InvoiceLine) (InvoiceTotals, error) {
+	var totals InvoiceTotals
+
+	if len(lines) == 0 {
+		return totals, fmt.Errorf("%w: invoice has no items", ErrInvalidInvoice)
+	}
+
+	taxes := make(map[TaxLine]int)
+
+	for i, line := range lines {
+		if line.Quantity <= 0 || line.UnitAmount <= 0 {
+			return totals, fmt.Errorf("%w: item %d must have a positive quantity and unit amount", ErrInvalidInvoice, i+1)
+		}
+
+		gross := line.Quantity * line.UnitAmount
+		if line.Discount < 0 || line.Discount > gross {
+			return totals, fmt.Errorf("%w: discount of item %d exceeds its amount", ErrInvalidInvoice, i+1)
+		}
+		if line.TaxRate < 0 || line.TaxRate > FullShare {
+			return totals, fmt.Errorf("%w: tax rate of item %d must be between 0 and %d", ErrInvalidInvoice, i+1, FullShare)
+		}
+
+		base := gross - line.Discount
+		tax := (base*line.TaxRate + FullShare/2) / FullShare
+
+		totals.Lines = append(totals.Lines, InvoiceLineTotal{Tax: tax, Amount: base + tax})
+		totals.Subtotal += gross
+		totals.Discount += line.Discount
+		totals.Tax += tax
+
+		if line.TaxRate == 0 && line.TaxName == "" {
+			continue
+		}
+
+		key := TaxLine{Name: line.TaxName, Rate: line.TaxRate}
+		index, ok := taxes[key]
+		if !ok {
+			index = len(totals.Taxes)
+			taxes[key] = index
+			totals.Taxes = append(totals.Taxes, key)
+		}
+		totals.Taxes[index].Base += base
+		totals.Taxes[index].Amount += tax
+	}
+
+	totals.Total = totals.Subtotal - totals.Discount + totals.Tax
+
+	if totals.Total <= 0 {
+		return totals, fmt.Errorf("%w: total must be positive", ErrInvalidInvoice)
+	}
+
+	return totals, nil
+}
+
+// FormatInvoiceNumber returns the number printed on an invoice
+func FormatInvoiceNumber(number int64) string {
+	return fmt.Sprintf("INV-%06d", number)
+}
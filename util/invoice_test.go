@@ -0,0 +1,65 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeInvoice(t *testing.T) {
+	lines := []InvoiceLine{
+		{Quantity: 2, UnitAmount: 1999, TaxName: "ISS", TaxRate: 500},
+		{Quantity: 1, UnitAmount: 10000, Discount: 1000, TaxName: "ICMS", TaxRate: 1800},
+		{Quantity: 3, UnitAmount: 333, TaxName: "ISS", TaxRate: 500},
+		{Quantity: 1, UnitAmount: 500},
+	}
+
+	totals, err := ComputeInvoice(lines)
+	require.NoError(t, err)
+
+	// 3998 at 5% is 199.9, 9000 at 18% is 1620 and 999 at 5% is 49.95
+	require.Equal(t, []InvoiceLineTotal{
+		{Tax: 200, Amount: 4198},
+		{Tax: 1620, Amount: 10620},
+		{Tax: 50, Amount: 1049},
+		{Tax: 0, Amount: 500},
+	}, totals.Lines)
+	require.Equal(t, []TaxLine{
+		{Name: "ISS", Rate: 500, Base: 4997, Amount: 250},
+		{Name: "ICMS", Rate: 1800, Base: 9000, Amount: 1620},
+	}, totals.Taxes)
+
+	require.Equal(t, int64(15497), totals.Subtotal)
+	require.Equal(t, int64(1000), totals.Discount)
+	require.Equal(t, int64(1870), totals.Tax)
+	require.Equal(t, int64(16367), totals.Total)
+}
+
+func TestComputeInvoiceInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lines []InvoiceLine
+	}{
+		{name: "NoItems"},
+		{name: "ZeroQuantity", lines: []InvoiceLine{{Quantity: 0, UnitAmount: 100}}},
+		{name: "NegativeUnitAmount", lines: []InvoiceLine{{Quantity: 1, UnitAmount: -100}}},
+		{name: "DiscountAboveAmount", lines: []InvoiceLine{{Quantity: 2, UnitAmount: 100, Discount: 201}}},
+		{name: "NegativeDiscount", lines: []InvoiceLine{{Quantity: 1, UnitAmount: 100, Discount: -1}}},
+		{name: "TaxRateTooHigh", lines: []InvoiceLine{{Quantity: 1, UnitAmount: 100, TaxRate: FullShare + 1}}},
+		{name: "NothingToPay", lines: []InvoiceLine{{Quantity: 1, UnitAmount: 100, Discount: 100}}},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ComputeInvoice(tc.lines)
+			require.ErrorIs(t, err, ErrInvalidInvoice)
+		})
+	}
+}
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	require.Equal(t, "INV-000042", FormatInvoiceNumber(42))
+	require.Equal(t, "INV-1234567", FormatInvoiceNumber(1234567))
+}
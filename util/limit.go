@@ -0,0 +1,137 @@
+package util
+
+import "time"
+
+// Names of the transfer limits, used to request an increase of one of them
+const (
+	LimitPerTransfer      = "per_transfer"
+	LimitDaily            = "daily"
+	LimitMonthly          = "monthly"
+	LimitDailyCount       = "daily_count"
+	LimitNightPerTransfer = "night_per_transfer"
+	LimitNightly          = "nightly"
+)
+
+// IsSupportedLimit returns true if the name is one of the transfer limits
+func IsSupportedLimit(name string) bool {
+
+	switch name {
+	case LimitPerTransfer, LimitDaily, LimitMonthly, LimitDailyCount, LimitNightPerTransfer, LimitNightly:
+		return true
+	}
+
+	return false
+}
+
+// TransferLimits caps the outgoing transfers of a wallet. Amounts are in the
+// wallet currency and zero means unlimited
+type TransferLimits struct {
+	PerTransfer      int64 `json:"per_transfer"`
+	Daily            int64 `json:"daily"`
+	Monthly          int64 `json:"monthly"`
+	DailyCount       int64 `json:"daily_count"`
+	NightPerTransfer int64 `json:"night_per_transfer"`
+	Nightly          int64 `json:"nightly"`
+}
+
+// Get returns the limit with the given name
+func (limits TransferLimits) Get(name string) int64 {
+	switch name {
+	case LimitPerTransfer:
+		return limits.PerTransfer
+	case LimitDaily:
+		return limits.Daily
+	case LimitMonthly:
+		return limits.Monthly
+	case LimitDailyCount:
+		return limits.DailyCount
+	case LimitNightPerTransfer:
+		return limits.NightPerTransfer
+	case LimitNightly:
+		return limits.Nightly
+	}
+
+	return 0
+}
+
+// Set changes the limit with the given name
+func (limits *TransferLimits) Set(name string, value int64) {
+	switch name {
+	case LimitPerTransfer:
+		limits.PerTransfer = value
+	case LimitDaily:
+		limits.Daily = value
+	case LimitMonthly:
+		limits.Monthly = value
+	case LimitDailyCount:
+		limits.DailyCount = value
+	case LimitNightPerTransfer:
+		limits.NightPerTransfer = value
+	case LimitNightly:
+		limits.Nightly = value
+	}
+}
+
+// IsIncrease reports whether changing the limit to value loosens it
+func (limits TransferLimits) IsIncrease(name string, value int64) bool {
+	current := limits.Get(name)
+	if current == 0 {
+		return false
+	}
+
+	return value == 0 || value > current
+}
+
+// LimitSchedule tells which local day, month and night a moment belongs to.
+// The night starts at NightStart hours and ends at NightEnd hours of the next day
+type LimitSchedule struct {
+	Location   *time.Location
+	NightStart int
+	NightEnd   int
+}
+
+func (schedule LimitSchedule) local(t time.Time) time.Time {
+	if schedule.Location == nil {
+		return t.UTC()
+	}
+
+	return t.In(schedule.Location)
+}
+
+// IsNight reports whether t falls in the nighttime window
+func (schedule LimitSchedule) IsNight(t time.Time) bool {
+	if schedule.NightStart == schedule.NightEnd {
+		return false
+	}
+
+	hour := schedule.local(t).Hour()
+	if schedule.NightStart < schedule.NightEnd {
+		return hour >= schedule.NightStart && hour < schedule.NightEnd
+	}
+
+	return hour >= schedule.NightStart || hour < schedule.NightEnd
+}
+
+// DayStart returns the local midnight that starts the day of t
+func (schedule LimitSchedule) DayStart(t time.Time) time.Time {
+	local := schedule.local(t)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// MonthStart returns the local midnight that starts the month of t
+func (schedule LimitSchedule) MonthStart(t time.Time) time.Time {
+	local := schedule.local(t)
+	return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+}
+
+// NightStartOf returns when the night t falls in started. Only meaningful when IsNight(t)
+func (schedule LimitSchedule) NightStartOf(t time.Time) time.Time {
+	local := schedule.local(t)
+	start := time.Date(local.Year(), local.Month(), local.Day(), schedule.NightStart, 0, 0, 0, local.Location())
+
+	if start.After(local) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	return start
+}
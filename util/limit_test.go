@@ -0,0 +1,78 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitSchedule(t *testing.T) {
+	location := time.FixedZone("BRT", -3*60*60)
+	schedule := LimitSchedule{Location: location, NightStart: 20, NightEnd: 6}
+
+	testCases := []struct {
+		name       string
+		now        time.Time
+		night      bool
+		nightStart time.Time
+	}{
+		{
+			name:  "Afternoon",
+			now:   time.Date(2024, 3, 10, 15, 0, 0, 0, location),
+			night: false,
+		},
+		{
+			name:       "Evening",
+			now:        time.Date(2024, 3, 10, 20, 0, 0, 0, location),
+			night:      true,
+			nightStart: time.Date(2024, 3, 10, 20, 0, 0, 0, location),
+		},
+		{
+			name:       "AfterMidnight",
+			now:        time.Date(2024, 3, 11, 5, 59, 0, 0, location),
+			night:      true,
+			nightStart: time.Date(2024, 3, 10, 20, 0, 0, 0, location),
+		},
+		{
+			name:  "Morning",
+			now:   time.Date(2024, 3, 11, 6, 0, 0, 0, location),
+			night: false,
+		},
+		{
+			// 01:00 UTC is still the previous evening in local time
+			name:       "UTCInput",
+			now:        time.Date(2024, 3, 11, 1, 0, 0, 0, time.UTC),
+			night:      true,
+			nightStart: time.Date(2024, 3, 10, 20, 0, 0, 0, location),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.night, schedule.IsNight(tc.now))
+			if tc.night {
+				require.True(t, tc.nightStart.Equal(schedule.NightStartOf(tc.now)))
+			}
+		})
+	}
+
+	now := time.Date(2024, 3, 11, 1, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2024, 3, 10, 0, 0, 0, 0, location).Equal(schedule.DayStart(now)))
+	require.True(t, time.Date(2024, 3, 1, 0, 0, 0, 0, location).Equal(schedule.MonthStart(now)))
+
+	require.False(t, LimitSchedule{}.IsNight(now))
+}
+
+func TestTransferLimits(t *testing.T) {
+	limits := TransferLimits{PerTransfer: 1000, Daily: 5000}
+
+	limits.Set(LimitDaily, 8000)
+	require.Equal(t, int64(8000), limits.Get(LimitDaily))
+
+	require.True(t, limits.IsIncrease(LimitPerTransfer, 2000))
+	require.True(t, limits.IsIncrease(LimitPerTransfer, 0))
+	require.False(t, limits.IsIncrease(LimitPerTransfer, 500))
+	// an unlimited limit cannot be increased any further
+	require.False(t, limits.IsIncrease(LimitMonthly, 500))
+}
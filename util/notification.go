@@ -0,0 +1,26 @@
+package util
+
+// Notification types shown to users in their inbox
+const (
+	NotificationScheduledTransferFailed  = "scheduled_transfer.failed"
+	NotificationPaymentRequestPaid       = "payment_request.paid"
+	NotificationPaymentRequestDeclined   = "payment_request.declined"
+	NotificationPayoutBatchCompleted     = "payout_batch.completed"
+	NotificationEscrowDisputed           = "escrow.disputed"
+	NotificationEscrowSettled            = "escrow.settled"
+	NotificationDisputeOpened            = "dispute.opened"
+	NotificationDisputeEscalated         = "dispute.escalated"
+	NotificationDisputeDecided           = "dispute.decided"
+	NotificationInstallmentFailed        = "installment.failed"
+	NotificationInstallmentOverdue       = "installment.overdue"
+	NotificationInstallmentPlanCancelled = "installment_plan.cancelled"
+	NotificationSubscriptionFailed       = "subscription.payment_failed"
+	NotificationSubscriptionCancelled    = "subscription.cancelled"
+	NotificationInvoiceIssued            = "invoice.issued"
+	NotificationInvoicePaid              = "invoice.paid"
+	NotificationInvoicePaymentRejected   = "invoice.payment_rejected"
+	NotificationInvoiceVoided            = "invoice.voided"
+	NotificationInvoiceOverdue           = "invoice.overdue"
+	NotificationCashbackCredited         = "cashback.credited"
+	NotificationCashbackClawedBack       = "cashback.clawed_back"
+)
@@ -0,0 +1,19 @@
+package util
+
+const (
+	CustomerRole = "customer"
+	MerchantRole = "merchant"
+	SupportRole  = "support"
+	AdminRole    = "admin"
+)
+
+// IsSupportedRole returns true if the role is one of the known user roles
+func IsSupportedRole(role string) bool {
+
+	switch role {
+	case CustomerRole, MerchantRole, SupportRole, AdminRole:
+		return true
+	}
+
+	return false
+}
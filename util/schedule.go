@@ -0,0 +1,51 @@
+package util
+
+import "time"
+
+// Frequencies of scheduled transfers
+const (
+	FrequencyOnce    = "once"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// IsSupportedFrequency returns true if transfers can be scheduled with the frequency
+func IsSupportedFrequency(frequency string) bool {
+
+	switch frequency {
+	case FrequencyOnce, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+
+	return false
+}
+
+// NextOccurrence returns the occurrence that follows the given one, keeping
+// its time of day in the location. Monthly occurrences fall on dayOfMonth or
+// on the last day of months that are too short. It returns false for
+// transfers that don't repeat
+func NextOccurrence(frequency string, dayOfMonth int, occurrence time.Time, location *time.Location) (time.Time, bool) {
+	t := occurrence.In(location)
+
+	switch frequency {
+	case FrequencyWeekly:
+		return t.AddDate(0, 0, 7), true
+	case FrequencyMonthly:
+		year, month, _ := t.Date()
+		month++
+
+		day := dayOfMonth
+		if last := daysIn(year, month, location); day > last {
+			day = last
+		}
+
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location), true
+	}
+
+	return time.Time{}, false
+}
+
+// daysIn returns the number of days of the month, which may be past December
+func daysIn(year int, month time.Month, location *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day()
+}
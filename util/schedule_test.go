@@ -0,0 +1,87 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	location := time.FixedZone("BRT", -3*60*60)
+
+	testCases := []struct {
+		name       string
+		frequency  string
+		dayOfMonth int
+		occurrence time.Time
+		next       time.Time
+		repeats    bool
+	}{
+		{
+			name:       "Once",
+			frequency:  FrequencyOnce,
+			occurrence: time.Date(2024, 1, 10, 9, 0, 0, 0, location),
+			repeats:    false,
+		},
+		{
+			name:       "Weekly",
+			frequency:  FrequencyWeekly,
+			occurrence: time.Date(2024, 2, 26, 9, 0, 0, 0, location),
+			next:       time.Date(2024, 3, 4, 9, 0, 0, 0, location),
+			repeats:    true,
+		},
+		{
+			name:       "Monthly",
+			frequency:  FrequencyMonthly,
+			dayOfMonth: 10,
+			occurrence: time.Date(2024, 1, 10, 9, 0, 0, 0, location),
+			next:       time.Date(2024, 2, 10, 9, 0, 0, 0, location),
+			repeats:    true,
+		},
+		{
+			name:       "EndOfShortMonth",
+			frequency:  FrequencyMonthly,
+			dayOfMonth: 31,
+			occurrence: time.Date(2024, 1, 31, 9, 0, 0, 0, location),
+			next:       time.Date(2024, 2, 29, 9, 0, 0, 0, location),
+			repeats:    true,
+		},
+		{
+			name:       "BackToDayOfMonth",
+			frequency:  FrequencyMonthly,
+			dayOfMonth: 31,
+			occurrence: time.Date(2024, 2, 29, 9, 0, 0, 0, location),
+			next:       time.Date(2024, 3, 31, 9, 0, 0, 0, location),
+			repeats:    true,
+		},
+		{
+			name:       "NextYear",
+			frequency:  FrequencyMonthly,
+			dayOfMonth: 30,
+			occurrence: time.Date(2024, 12, 30, 9, 0, 0, 0, location),
+			next:       time.Date(2025, 1, 30, 9, 0, 0, 0, location),
+			repeats:    true,
+		},
+		{
+			name:       "InLocation",
+			frequency:  FrequencyMonthly,
+			dayOfMonth: 1,
+			occurrence: time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC),
+			next:       time.Date(2024, 5, 1, 23, 0, 0, 0, location),
+			repeats:    true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			next, repeats := NextOccurrence(tc.frequency, tc.dayOfMonth, tc.occurrence, location)
+			require.Equal(t, tc.repeats, repeats)
+			if repeats {
+				require.True(t, tc.next.Equal(next), "expected %v, got %v", tc.next, next)
+			}
+		})
+	}
+}
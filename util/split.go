@@ -0,0 +1,91 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FullShare is 100% in basis points
+const FullShare = 10000
+
+// ErrInvalidSplit is returned when the shares of a split can't be allocated
+var ErrInvalidSplit = errors.New("invalid split")
+
+// SplitShare is the part of a split taken by one recipient: either a fixed
+// amount or, in basis points, a share of what's left after the fixed amounts
+type SplitShare struct {
+	Amount      int64 `json:"amount,omitempty"`
+	BasisPoints int64 `json:"basis_points,omitempty"`
+}
+
+// AllocateSplit divides the total among the shares. Fixed amounts are taken
+// first and the rest is divided by the percentages, rounding down. The cents
+// left by the rounding go one each to the shares with the largest remainders,
+// the earliest share winning ties, so the same split always allocates the
+// same amounts
+func AllocateSplit(total int64, shares []SplitShare) ([]int64, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidSplit)
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("%w: no recipients", ErrInvalidSplit)
+	}
+
+	rest := total
+	var basisPoints int64
+
+	for i, share := range shares {
+		if (share.Amount > 0) == (share.BasisPoints > 0) || share.Amount < 0 || share.BasisPoints < 0 {
+			return nil, fmt.Errorf("%w: recipient %d needs either an amount or a percentage", ErrInvalidSplit, i)
+		}
+
+		rest -= share.Amount
+		basisPoints += share.BasisPoints
+	}
+
+	if rest < 0 {
+		return nil, fmt.Errorf("%w: fixed amounts exceed the total", ErrInvalidSplit)
+	}
+	if basisPoints == 0 && rest != 0 {
+		return nil, fmt.Errorf("%w: fixed amounts must add up to the total", ErrInvalidSplit)
+	}
+	if basisPoints != 0 && basisPoints != FullShare {
+		return nil, fmt.Errorf("%w: percentages must add up to 100%%", ErrInvalidSplit)
+	}
+
+	amounts := make([]int64, len(shares))
+	remainders := make([]int64, len(shares))
+	left := rest
+
+	for i, share := range shares {
+		if share.BasisPoints == 0 {
+			amounts[i] = share.Amount
+			continue
+		}
+
+		amounts[i] = rest * share.BasisPoints / FullShare
+		remainders[i] = rest * share.BasisPoints % FullShare
+		left -= amounts[i]
+	}
+
+	// at most one cent per percentage share is left
+	for ; left > 0; left-- {
+		largest := -1
+		for i, share := range shares {
+			if share.BasisPoints > 0 && (largest < 0 || remainders[i] > remainders[largest]) {
+				largest = i
+			}
+		}
+
+		amounts[largest]++
+		remainders[largest] = -1
+	}
+
+	for i, amount := range amounts {
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: recipient %d would receive nothing", ErrInvalidSplit, i)
+		}
+	}
+
+	return amounts, nil
+}
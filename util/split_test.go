@@ -0,0 +1,115 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocateSplit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		total   int64
+		shares  []SplitShare
+		amounts []int64
+	}{
+		{
+			name:    "Fixed",
+			total:   1000,
+			shares:  []SplitShare{{Amount: 900}, {Amount: 100}},
+			amounts: []int64{900, 100},
+		},
+		{
+			name:    "Percentages",
+			total:   1000,
+			shares:  []SplitShare{{BasisPoints: 9000}, {BasisPoints: 1000}},
+			amounts: []int64{900, 100},
+		},
+		{
+			name:    "ThirdsGoToTheFirst",
+			total:   100,
+			shares:  []SplitShare{{BasisPoints: 3333}, {BasisPoints: 3333}, {BasisPoints: 3334}},
+			amounts: []int64{33, 33, 34},
+		},
+		{
+			name:    "LargestRemainder",
+			total:   101,
+			shares:  []SplitShare{{BasisPoints: 5000}, {BasisPoints: 2500}, {BasisPoints: 2500}},
+			amounts: []int64{51, 25, 25},
+		},
+		{
+			name:    "EvenRemainders",
+			total:   10,
+			shares:  []SplitShare{{BasisPoints: 3333}, {BasisPoints: 3333}, {BasisPoints: 3334}},
+			amounts: []int64{3, 3, 4},
+		},
+		{
+			name:    "TiesToTheEarliest",
+			total:   5,
+			shares:  []SplitShare{{BasisPoints: 2500}, {BasisPoints: 2500}, {BasisPoints: 2500}, {BasisPoints: 2500}},
+			amounts: []int64{2, 1, 1, 1},
+		},
+		{
+			name:    "Mixed",
+			total:   1099,
+			shares:  []SplitShare{{Amount: 99}, {BasisPoints: 5000}, {BasisPoints: 5000}},
+			amounts: []int64{99, 500, 500},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			amounts, err := AllocateSplit(tc.total, tc.shares)
+			require.NoError(t, err)
+			require.Equal(t, tc.amounts, amounts)
+
+			var sum int64
+			for _, amount := range amounts {
+				sum += amount
+			}
+			require.Equal(t, tc.total, sum)
+		})
+	}
+}
+
+func TestAllocateSplitRounding(t *testing.T) {
+	shares := []SplitShare{{BasisPoints: 1}, {BasisPoints: 4999}, {BasisPoints: 5000}}
+
+	for total := int64(1); total <= 20000; total += 7 {
+		amounts, err := AllocateSplit(total, shares)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidSplit)
+			continue
+		}
+
+		require.Equal(t, total, amounts[0]+amounts[1]+amounts[2])
+		again, err := AllocateSplit(total, shares)
+		require.NoError(t, err)
+		require.Equal(t, amounts, again)
+	}
+}
+
+func TestAllocateSplitInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		total  int64
+		shares []SplitShare
+	}{
+		{name: "NoRecipients", total: 100},
+		{name: "NoAmount", total: 0, shares: []SplitShare{{Amount: 1}}},
+		{name: "BothKinds", total: 100, shares: []SplitShare{{Amount: 50, BasisPoints: 5000}, {Amount: 50}}},
+		{name: "Neither", total: 100, shares: []SplitShare{{}, {Amount: 100}}},
+		{name: "FixedShort", total: 100, shares: []SplitShare{{Amount: 40}, {Amount: 50}}},
+		{name: "FixedOver", total: 100, shares: []SplitShare{{Amount: 60}, {Amount: 50}}},
+		{name: "PercentagesShort", total: 100, shares: []SplitShare{{BasisPoints: 5000}, {BasisPoints: 4000}}},
+		{name: "RecipientGetsNothing", total: 2, shares: []SplitShare{{BasisPoints: 3334}, {BasisPoints: 3333}, {BasisPoints: 3333}}},
+		{name: "NothingLeft", total: 100, shares: []SplitShare{{Amount: 100}, {BasisPoints: 10000}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := AllocateSplit(tc.total, tc.shares)
+			require.ErrorIs(t, err, ErrInvalidSplit)
+		})
+	}
+}
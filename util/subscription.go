@@ -0,0 +1,20 @@
+package util
+
+import "time"
+
+// Prorate returns the part of amount that pays for what is left of the
+// period from start to end at the given time, rounded down
+func Prorate(amount int64, start, end, at time.Time) int64 {
+	// seconds keep the product within int64 for any realistic amount
+	period := int64(end.Sub(start) / time.Second)
+	if period <= 0 || !at.Before(end) {
+		return 0
+	}
+
+	if !at.After(start) {
+		return amount
+	}
+
+	left := int64(end.Sub(at) / time.Second)
+	return amount * left / period
+}
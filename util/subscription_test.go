@@ -0,0 +1,56 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProrate(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		at     time.Time
+		amount int64
+	}{
+		{
+			name:   "BeforeStart",
+			at:     start.Add(-time.Hour),
+			amount: 3000,
+		},
+		{
+			name:   "AtStart",
+			at:     start,
+			amount: 3000,
+		},
+		{
+			name:   "Halfway",
+			at:     time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC),
+			amount: 1500,
+		},
+		{
+			name:   "RoundedDown",
+			at:     time.Date(2024, 4, 21, 0, 0, 0, 0, time.UTC),
+			amount: 1000,
+		},
+		{
+			name:   "AtEnd",
+			at:     end,
+			amount: 0,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.amount, Prorate(3000, start, end, tc.at))
+		})
+	}
+
+	require.Zero(t, Prorate(3000, end, start, start))
+	require.Equal(t, int64(333), Prorate(1000, start, end, time.Date(2024, 4, 21, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// batchWorker is what the workers that drain due rows on a ticker share: the
+// clock they stamp each run with, which tests replace, and the ticker loop
+type batchWorker struct {
+	// job names the work in the logs, as in "cannot <job>"
+	job string
+	now func() time.Time
+}
+
+func newBatchWorker(job string) batchWorker {
+	return batchWorker{
+		job: job,
+		now: time.Now,
+	}
+}
+
+// run calls work every interval until the context is done. Errors are only
+// logged, the next tick tries again
+func (worker batchWorker) run(ctx context.Context, interval time.Duration, work func(ctx context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := work(ctx); err != nil {
+				log.Printf("cannot %s: %v", worker.job, err)
+			}
+		}
+	}
+}
+
+// drainBatches calls next until it returns fewer rows than batchSize, one
+// transaction per batch. It returns how many rows all the batches had
+func drainBatches[T any](ctx context.Context, batchSize int, next func(ctx context.Context) ([]T, error)) (int, error) {
+	total := 0
+
+	for {
+		batch, err := next(ctx)
+		if err != nil {
+			return total, err
+		}
+
+		total += len(batch)
+		if len(batch) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// drainRows calls next until there is no row left to claim, for work done one
+// row per transaction. It returns how many rows were claimed
+func drainRows[T any](ctx context.Context, next func(ctx context.Context) (T, error)) (int, error) {
+	total := 0
+
+	for {
+		_, err := next(ctx)
+		if err == sql.ErrNoRows {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		total++
+	}
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainBatches(t *testing.T) {
+	batches := [][]int{make([]int, 10), make([]int, 10), make([]int, 4), make([]int, 10)}
+
+	calls := 0
+	total, err := drainBatches(context.Background(), 10, func(ctx context.Context) ([]int, error) {
+		calls++
+		return batches[calls-1], nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 24, total)
+	require.Equal(t, 3, calls)
+
+	// an empty first batch ends the run right away
+	total, err = drainBatches(context.Background(), 10, func(ctx context.Context) ([]int, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Zero(t, total)
+
+	calls = 0
+	total, err = drainBatches(context.Background(), 10, func(ctx context.Context) ([]int, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("connection refused")
+		}
+		return make([]int, 10), nil
+	})
+	require.Error(t, err)
+	require.Equal(t, 10, total)
+}
+
+func TestDrainRows(t *testing.T) {
+	calls := 0
+	total, err := drainRows(context.Background(), func(ctx context.Context) (int, error) {
+		calls++
+		if calls > 3 {
+			return 0, sql.ErrNoRows
+		}
+		return calls, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+
+	calls = 0
+	total, err = drainRows(context.Background(), func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 2 {
+			return 0, errors.New("connection refused")
+		}
+		return calls, nil
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, total)
+}
+
+func TestBatchWorkerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := newBatchWorker("test")
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		worker.run(ctx, time.Millisecond, func(ctx context.Context) (int, error) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			// errors don't stop the ticker
+			return 0, errors.New("connection refused")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run didn't stop once the context was done")
+	}
+	// a tick may win the race with the cancellation once
+	require.GreaterOrEqual(t, calls, 3)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+// CashbackSettler pays the cashback of campaigns with settle days from the
+// marketing wallets once the grants are due. Grants that can't be paid are
+// tried again after the retry interval
+type CashbackSettler struct {
+	batchWorker
+	store         db.Store
+	fundingOwner  string
+	retryInterval time.Duration
+}
+
+// NewCashbackSettler creates a new CashbackSettler
+func NewCashbackSettler(store db.Store, config util.Config) *CashbackSettler {
+	return &CashbackSettler{
+		batchWorker:   newBatchWorker("settle cashback"),
+		store:         store,
+		fundingOwner:  config.CashbackFundingOwner,
+		retryInterval: config.CashbackRetryInterval,
+	}
+}
+
+// Run settles due cashback every interval until the context is done
+func (settler *CashbackSettler) Run(ctx context.Context, interval time.Duration) {
+	settler.run(ctx, interval, settler.SettleDue)
+}
+
+// SettleDue settles every due cashback grant, one per transaction
+func (settler *CashbackSettler) SettleDue(ctx context.Context) (int, error) {
+	return drainRows(ctx, func(ctx context.Context) (db.CashbackGrant, error) {
+		return settler.store.SettleCashbackGrantTx(ctx, db.SettleCashbackGrantTxParams{
+			Now:           settler.now(),
+			FundingOwner:  settler.fundingOwner,
+			RetryInterval: settler.retryInterval,
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettleDueCashback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	config := util.Config{
+		CashbackFundingOwner:  "marketing",
+		CashbackRetryInterval: time.Hour,
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			SettleCashbackGrantTx(gomock.Any(), gomock.Eq(db.SettleCashbackGrantTxParams{
+				Now:           now,
+				FundingOwner:  "marketing",
+				RetryInterval: time.Hour,
+			})).
+			Times(2).
+			Return(db.CashbackGrant{Status: db.CashbackPaid}, nil),
+		store.EXPECT().
+			SettleCashbackGrantTx(gomock.Any(), gomock.Any()).
+			Return(db.CashbackGrant{}, sql.ErrNoRows),
+	)
+
+	settler := NewCashbackSettler(store, config)
+	settler.now = func() time.Time { return now }
+
+	settled, err := settler.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, settled)
+}
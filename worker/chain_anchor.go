@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"log"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const chainAnchorBatchSize = 1000
+
+// ChainAnchor periodically signs a checkpoint over the entries chained since
+// the last one, so rewriting the chain also requires the signing key
+type ChainAnchor struct {
+	store db.Store
+	key   ed25519.PrivateKey
+	delay time.Duration
+	now   func() time.Time
+}
+
+// NewChainAnchor creates a new ChainAnchor
+func NewChainAnchor(store db.Store, config util.Config) *ChainAnchor {
+	return &ChainAnchor{
+		store: store,
+		key:   config.ChainKey(),
+		delay: config.ChainCheckpointDelay,
+		now:   time.Now,
+	}
+}
+
+// Run anchors the chain every interval until the context is done
+func (anchor *ChainAnchor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := anchor.Anchor(ctx); err != nil {
+				log.Println("cannot anchor the entry chain:", err)
+			}
+		}
+	}
+}
+
+// Anchor creates a checkpoint, it reports false when there was nothing new to anchor
+func (anchor *ChainAnchor) Anchor(ctx context.Context) (db.ChainCheckpoint, bool, error) {
+	checkpoint, err := anchor.store.CreateChainCheckpointTx(ctx, db.CreateChainCheckpointTxParams{
+		Before:    anchor.now().Add(-anchor.delay),
+		Key:       anchor.key,
+		BatchSize: chainAnchorBatchSize,
+	})
+	if err == sql.ErrNoRows {
+		return checkpoint, false, nil
+	}
+	if err != nil {
+		return checkpoint, false, err
+	}
+
+	return checkpoint, true, nil
+}
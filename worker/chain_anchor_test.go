@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := util.Config{ChainSigningKey: "secret", ChainCheckpointDelay: time.Minute}
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	checkpoint := db.ChainCheckpoint{ID: 1, FromEntryID: 0, ToEntryID: 10, EntryCount: 10}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			CreateChainCheckpointTx(gomock.Any(), gomock.Eq(db.CreateChainCheckpointTxParams{
+				Before:    now.Add(-time.Minute),
+				Key:       config.ChainKey(),
+				BatchSize: chainAnchorBatchSize,
+			})).
+			Return(checkpoint, nil),
+		store.EXPECT().
+			CreateChainCheckpointTx(gomock.Any(), gomock.Any()).
+			Return(db.ChainCheckpoint{}, sql.ErrNoRows),
+		store.EXPECT().
+			CreateChainCheckpointTx(gomock.Any(), gomock.Any()).
+			Return(db.ChainCheckpoint{}, errors.New("connection refused")),
+	)
+
+	anchor := NewChainAnchor(store, config)
+	anchor.now = func() time.Time { return now }
+
+	created, ok, err := anchor.Anchor(context.Background())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, checkpoint, created)
+
+	_, ok, err = anchor.Anchor(context.Background())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = anchor.Anchor(context.Background())
+	require.Error(t, err)
+}
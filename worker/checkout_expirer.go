@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const checkoutExpireBatchSize = 100
+
+// CheckoutExpirer expires the checkout sessions nobody paid in time
+type CheckoutExpirer struct {
+	batchWorker
+	store db.Store
+}
+
+// NewCheckoutExpirer creates a new CheckoutExpirer
+func NewCheckoutExpirer(store db.Store) *CheckoutExpirer {
+	return &CheckoutExpirer{
+		batchWorker: newBatchWorker("expire checkout sessions"),
+		store:       store,
+	}
+}
+
+// Run expires due checkout sessions every interval until the context is done
+func (expirer *CheckoutExpirer) Run(ctx context.Context, interval time.Duration) {
+	expirer.run(ctx, interval, expirer.ExpireDue)
+}
+
+// ExpireDue expires every due checkout session, one batch per transaction
+func (expirer *CheckoutExpirer) ExpireDue(ctx context.Context) (int, error) {
+	now := expirer.now()
+
+	return drainBatches(ctx, checkoutExpireBatchSize, func(ctx context.Context) ([]db.CheckoutSession, error) {
+		return expirer.store.ExpireCheckoutSessionsTx(ctx, db.ExpireCheckoutSessionsTxParams{
+			Now:       now,
+			BatchSize: checkoutExpireBatchSize,
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireDueCheckoutSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExpireCheckoutSessionsTx(gomock.Any(), gomock.Eq(db.ExpireCheckoutSessionsTxParams{
+			Now:       now,
+			BatchSize: checkoutExpireBatchSize,
+		})).
+		Return(make([]db.CheckoutSession, 2), nil)
+
+	expirer := NewCheckoutExpirer(store)
+	expirer.now = func() time.Time { return now }
+
+	expired, err := expirer.ExpireDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, expired)
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const disputeEscalationBatchSize = 100
+
+// DisputeEscalator sends the disputes merchants didn't respond to in time to
+// review, so analysts can decide them without the merchant side
+type DisputeEscalator struct {
+	batchWorker
+	store db.Store
+}
+
+// NewDisputeEscalator creates a new DisputeEscalator
+func NewDisputeEscalator(store db.Store) *DisputeEscalator {
+	return &DisputeEscalator{
+		batchWorker: newBatchWorker("escalate disputes"),
+		store:       store,
+	}
+}
+
+// Run escalates overdue disputes every interval until the context is done
+func (escalator *DisputeEscalator) Run(ctx context.Context, interval time.Duration) {
+	escalator.run(ctx, interval, escalator.EscalateOverdue)
+}
+
+// EscalateOverdue escalates every overdue dispute, one batch per transaction
+func (escalator *DisputeEscalator) EscalateOverdue(ctx context.Context) (int, error) {
+	now := escalator.now()
+
+	return drainBatches(ctx, disputeEscalationBatchSize, func(ctx context.Context) ([]db.Dispute, error) {
+		return escalator.store.EscalateOverdueDisputesTx(ctx, db.EscalateOverdueDisputesTxParams{
+			Now:       now,
+			BatchSize: disputeEscalationBatchSize,
+		})
+	})
+}
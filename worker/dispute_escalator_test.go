@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalateOverdueDisputes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		EscalateOverdueDisputesTx(gomock.Any(), gomock.Eq(db.EscalateOverdueDisputesTxParams{
+			Now:       now,
+			BatchSize: disputeEscalationBatchSize,
+		})).
+		Return(make([]db.Dispute, 2), nil)
+
+	escalator := NewDisputeEscalator(store)
+	escalator.now = func() time.Time { return now }
+
+	escalated, err := escalator.EscalateOverdue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, escalated)
+}
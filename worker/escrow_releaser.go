@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const escrowReleaseBatchSize = 100
+
+// EscrowReleaser pays the sellers of the undisputed escrows the buyers
+// didn't confirm before their release time
+type EscrowReleaser struct {
+	batchWorker
+	store db.Store
+}
+
+// NewEscrowReleaser creates a new EscrowReleaser
+func NewEscrowReleaser(store db.Store) *EscrowReleaser {
+	return &EscrowReleaser{
+		batchWorker: newBatchWorker("release escrows"),
+		store:       store,
+	}
+}
+
+// Run releases due escrows every interval until the context is done
+func (releaser *EscrowReleaser) Run(ctx context.Context, interval time.Duration) {
+	releaser.run(ctx, interval, releaser.ReleaseDue)
+}
+
+// ReleaseDue releases every due escrow, one batch per transaction
+func (releaser *EscrowReleaser) ReleaseDue(ctx context.Context) (int, error) {
+	now := releaser.now()
+
+	return drainBatches(ctx, escrowReleaseBatchSize, func(ctx context.Context) ([]db.Escrow, error) {
+		return releaser.store.ReleaseDueEscrowsTx(ctx, db.ReleaseDueEscrowsTxParams{
+			Now:       now,
+			BatchSize: escrowReleaseBatchSize,
+		})
+	})
+}
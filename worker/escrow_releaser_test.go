@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseDueEscrows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ReleaseDueEscrowsTx(gomock.Any(), gomock.Eq(db.ReleaseDueEscrowsTxParams{
+			Now:       now,
+			BatchSize: escrowReleaseBatchSize,
+		})).
+		Return(make([]db.Escrow, 2), nil)
+
+	releaser := NewEscrowReleaser(store)
+	releaser.now = func() time.Time { return now }
+
+	released, err := releaser.ReleaseDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, released)
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const holdExpiryBatchSize = 100
+
+// HoldExpirer releases the holds that were neither captured nor voided
+// before their expiry
+type HoldExpirer struct {
+	batchWorker
+	store db.Store
+}
+
+// NewHoldExpirer creates a new HoldExpirer
+func NewHoldExpirer(store db.Store) *HoldExpirer {
+	return &HoldExpirer{
+		batchWorker: newBatchWorker("expire holds"),
+		store:       store,
+	}
+}
+
+// Run expires due holds every interval until the context is done
+func (expirer *HoldExpirer) Run(ctx context.Context, interval time.Duration) {
+	expirer.run(ctx, interval, expirer.ExpireDue)
+}
+
+// ExpireDue expires every due hold, one batch per transaction
+func (expirer *HoldExpirer) ExpireDue(ctx context.Context) (int, error) {
+	now := expirer.now()
+
+	return drainBatches(ctx, holdExpiryBatchSize, func(ctx context.Context) ([]db.Hold, error) {
+		return expirer.store.ExpireHoldsTx(ctx, db.ExpireHoldsTxParams{
+			Now:       now,
+			BatchSize: holdExpiryBatchSize,
+		})
+	})
+}
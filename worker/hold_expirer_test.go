@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireDueHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExpireHoldsTx(gomock.Any(), gomock.Eq(db.ExpireHoldsTxParams{
+			Now:       now,
+			BatchSize: holdExpiryBatchSize,
+		})).
+		Return(make([]db.Hold, 2), nil)
+
+	expirer := NewHoldExpirer(store)
+	expirer.now = func() time.Time { return now }
+
+	expired, err := expirer.ExpireDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, expired)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+// InstallmentCollector debits the installments from the customers when they
+// fall due. Many collectors may run at once, each due installment is claimed
+// by only one of them
+type InstallmentCollector struct {
+	batchWorker
+	store         db.Store
+	maxAttempts   int32
+	retryInterval time.Duration
+}
+
+// NewInstallmentCollector creates a new InstallmentCollector
+func NewInstallmentCollector(store db.Store, config util.Config) *InstallmentCollector {
+	return &InstallmentCollector{
+		batchWorker:   newBatchWorker("collect installments"),
+		store:         store,
+		maxAttempts:   config.InstallmentMaxAttempts,
+		retryInterval: config.InstallmentRetryInterval,
+	}
+}
+
+// Run collects due installments every interval until the context is done
+func (collector *InstallmentCollector) Run(ctx context.Context, interval time.Duration) {
+	collector.run(ctx, interval, collector.CollectDue)
+}
+
+// CollectDue collects every due installment, one per transaction
+func (collector *InstallmentCollector) CollectDue(ctx context.Context) (int, error) {
+	return drainRows(ctx, func(ctx context.Context) (db.CollectInstallmentTxResult, error) {
+		return collector.store.CollectInstallmentTx(ctx, db.CollectInstallmentTxParams{
+			Now:           collector.now(),
+			MaxAttempts:   collector.maxAttempts,
+			RetryInterval: collector.retryInterval,
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectDueInstallments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	config := util.Config{
+		InstallmentMaxAttempts:   3,
+		InstallmentRetryInterval: 24 * time.Hour,
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			CollectInstallmentTx(gomock.Any(), gomock.Eq(db.CollectInstallmentTxParams{
+				Now:           now,
+				MaxAttempts:   3,
+				RetryInterval: 24 * time.Hour,
+			})).
+			Times(2).
+			Return(db.CollectInstallmentTxResult{}, nil),
+		store.EXPECT().
+			CollectInstallmentTx(gomock.Any(), gomock.Any()).
+			Return(db.CollectInstallmentTxResult{}, sql.ErrNoRows),
+	)
+
+	collector := NewInstallmentCollector(store, config)
+	collector.now = func() time.Time { return now }
+
+	collected, err := collector.CollectDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, collected)
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const invoiceRemindBatchSize = 100
+
+// InvoiceReminder marks unpaid invoices past their due date as overdue and
+// reminds their customers until they are paid or voided
+type InvoiceReminder struct {
+	batchWorker
+	store    db.Store
+	interval time.Duration
+}
+
+// NewInvoiceReminder creates a new InvoiceReminder, the reminder interval
+// must be positive
+func NewInvoiceReminder(store db.Store, config util.Config) (*InvoiceReminder, error) {
+	if config.InvoiceReminderInterval <= 0 {
+		return nil, fmt.Errorf("invalid invoice reminder interval %s: %w", config.InvoiceReminderInterval, db.ErrInvalidReminderInterval)
+	}
+
+	return &InvoiceReminder{
+		batchWorker: newBatchWorker("remind overdue invoices"),
+		store:       store,
+		interval:    config.InvoiceReminderInterval,
+	}, nil
+}
+
+// Run reminds customers of overdue invoices every interval until the context is done
+func (reminder *InvoiceReminder) Run(ctx context.Context, interval time.Duration) {
+	reminder.run(ctx, interval, reminder.RemindOverdue)
+}
+
+// RemindOverdue reminds the customers of every overdue invoice not reminded
+// within the interval, one batch per transaction
+func (reminder *InvoiceReminder) RemindOverdue(ctx context.Context) (int, error) {
+	now := reminder.now()
+
+	return drainBatches(ctx, invoiceRemindBatchSize, func(ctx context.Context) ([]db.Invoice, error) {
+		return reminder.store.RemindOverdueInvoicesTx(ctx, db.RemindOverdueInvoicesTxParams{
+			Now:       now,
+			Interval:  reminder.interval,
+			BatchSize: invoiceRemindBatchSize,
+		})
+	})
+}
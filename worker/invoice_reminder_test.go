@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemindOverdueInvoices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		RemindOverdueInvoicesTx(gomock.Any(), gomock.Eq(db.RemindOverdueInvoicesTxParams{
+			Now:       now,
+			Interval:  72 * time.Hour,
+			BatchSize: invoiceRemindBatchSize,
+		})).
+		Return(make([]db.Invoice, 5), nil)
+
+	reminder, err := NewInvoiceReminder(store, util.Config{InvoiceReminderInterval: 72 * time.Hour})
+	require.NoError(t, err)
+	reminder.now = func() time.Time { return now }
+
+	reminded, err := reminder.RemindOverdue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5, reminded)
+}
+
+func TestInvoiceReminderInterval(t *testing.T) {
+	store := mockdb.NewMockStore(gomock.NewController(t))
+
+	_, err := NewInvoiceReminder(store, util.Config{})
+	require.ErrorIs(t, err, db.ErrInvalidReminderInterval)
+
+	_, err = NewInvoiceReminder(store, util.Config{InvoiceReminderInterval: -time.Hour})
+	require.ErrorIs(t, err, db.ErrInvalidReminderInterval)
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+const (
+	limitIncreaseBatchSize = 100
+	// limitIncreaseDecidedBy is recorded on the requests applied by the worker
+	limitIncreaseDecidedBy = "system"
+)
+
+// LimitIncreaseApplier raises the limits of the users whose increase
+// requests are past their cooling off period
+type LimitIncreaseApplier struct {
+	batchWorker
+	store    db.Store
+	defaults util.TransferLimits
+}
+
+// NewLimitIncreaseApplier creates a new LimitIncreaseApplier
+func NewLimitIncreaseApplier(store db.Store, config util.Config) *LimitIncreaseApplier {
+	return &LimitIncreaseApplier{
+		batchWorker: newBatchWorker("apply limit increases"),
+		store:       store,
+		defaults:    config.TransferLimits(),
+	}
+}
+
+// Run applies due requests every interval until the context is done
+func (applier *LimitIncreaseApplier) Run(ctx context.Context, interval time.Duration) {
+	applier.run(ctx, interval, applier.ApplyDue)
+}
+
+// ApplyDue applies every due request, one batch per transaction
+func (applier *LimitIncreaseApplier) ApplyDue(ctx context.Context) (int, error) {
+	return drainBatches(ctx, limitIncreaseBatchSize, func(ctx context.Context) ([]db.LimitIncreaseRequest, error) {
+		return applier.store.ApplyLimitIncreasesTx(ctx, db.ApplyLimitIncreasesTxParams{
+			Defaults:  applier.defaults,
+			BatchSize: limitIncreaseBatchSize,
+			DecidedBy: limitIncreaseDecidedBy,
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDueLimitIncreases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := util.Config{LimitPerTransfer: 1000, LimitDaily: 5000}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ApplyLimitIncreasesTx(gomock.Any(), gomock.Eq(db.ApplyLimitIncreasesTxParams{
+			Defaults:  config.TransferLimits(),
+			BatchSize: limitIncreaseBatchSize,
+			DecidedBy: limitIncreaseDecidedBy,
+		})).
+		Return(make([]db.LimitIncreaseRequest, 3), nil)
+
+	applier := NewLimitIncreaseApplier(store, config)
+
+	applied, err := applier.ApplyDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, applied)
+}
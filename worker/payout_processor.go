@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+// PayoutProcessor pays the rows of the queued payout batches. Many
+// processors may run at once, each batch is worked on by one of them at a time
+type PayoutProcessor struct {
+	store        db.Store
+	risk         db.RiskEvaluator
+	defaults     util.TransferLimits
+	schedule     util.LimitSchedule
+	revenueOwner string
+	now          func() time.Time
+}
+
+// NewPayoutProcessor creates a new PayoutProcessor. Every row is a transfer
+// under the same limits, risk rules and fees as the ones made through the API
+func NewPayoutProcessor(store db.Store, config util.Config, schedule util.LimitSchedule, risk db.RiskEvaluator) *PayoutProcessor {
+	return &PayoutProcessor{
+		store:        store,
+		risk:         risk,
+		defaults:     config.TransferLimits(),
+		schedule:     schedule,
+		revenueOwner: config.PlatformRevenueOwner,
+		now:          time.Now,
+	}
+}
+
+// Run pays pending rows every interval until the context is done
+func (processor *PayoutProcessor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.ProcessPending(ctx); err != nil {
+				log.Println("cannot process payout batches:", err)
+			}
+		}
+	}
+}
+
+// ProcessPending pays every pending row of the active batches, one per transaction
+func (processor *PayoutProcessor) ProcessPending(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		now := processor.now()
+
+		_, err := processor.store.ExecutePayoutRowTx(ctx, db.ExecutePayoutRowTxParams{
+			Now: now,
+			Limits: &db.TransferLimitsParams{
+				Defaults: processor.defaults,
+				Schedule: processor.schedule,
+				Now:      now,
+			},
+			Risk: processor.risk,
+			Fees: db.NewFeeParams(processor.revenueOwner, now),
+		})
+		if err == sql.ErrNoRows {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		total++
+	}
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessPendingPayouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	config := util.Config{LimitPerTransfer: 1000}
+	schedule := util.LimitSchedule{Location: time.UTC}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			ExecutePayoutRowTx(gomock.Any(), gomock.Any()).
+			Times(3).
+			DoAndReturn(func(_ context.Context, arg db.ExecutePayoutRowTxParams) (db.ExecutePayoutRowTxResult, error) {
+				require.Equal(t, now, arg.Now)
+				require.Equal(t, int64(1000), arg.Limits.Defaults.PerTransfer)
+				require.Equal(t, now, arg.Limits.Now)
+				return db.ExecutePayoutRowTxResult{}, nil
+			}),
+		store.EXPECT().
+			ExecutePayoutRowTx(gomock.Any(), gomock.Any()).
+			Return(db.ExecutePayoutRowTxResult{}, sql.ErrNoRows),
+	)
+
+	processor := NewPayoutProcessor(store, config, schedule, nil)
+	processor.now = func() time.Time { return now }
+
+	processed, err := processor.ProcessPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, processed)
+
+	store.EXPECT().
+		ExecutePayoutRowTx(gomock.Any(), gomock.Any()).
+		Return(db.ExecutePayoutRowTxResult{}, errors.New("connection refused"))
+
+	_, err = processor.ProcessPending(context.Background())
+	require.Error(t, err)
+}
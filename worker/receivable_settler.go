@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const receivableSettlementBatchSize = 100
+
+// ReceivableSettler makes the receivables of merchants available once they
+// reach their settlement date
+type ReceivableSettler struct {
+	batchWorker
+	store db.Store
+}
+
+// NewReceivableSettler creates a new ReceivableSettler
+func NewReceivableSettler(store db.Store) *ReceivableSettler {
+	return &ReceivableSettler{
+		batchWorker: newBatchWorker("settle receivables"),
+		store:       store,
+	}
+}
+
+// Run settles matured receivables every interval until the context is done
+func (settler *ReceivableSettler) Run(ctx context.Context, interval time.Duration) {
+	settler.run(ctx, interval, settler.SettleDue)
+}
+
+// SettleDue settles every matured receivable, one batch per transaction
+func (settler *ReceivableSettler) SettleDue(ctx context.Context) (int, error) {
+	now := settler.now()
+
+	return drainBatches(ctx, receivableSettlementBatchSize, func(ctx context.Context) ([]db.Receivable, error) {
+		return settler.store.SettleReceivablesTx(ctx, db.SettleReceivablesTxParams{
+			Now:       now,
+			BatchSize: receivableSettlementBatchSize,
+		})
+	})
+}
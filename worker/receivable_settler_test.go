@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettleDueReceivables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		SettleReceivablesTx(gomock.Any(), gomock.Eq(db.SettleReceivablesTxParams{
+			Now:       now,
+			BatchSize: receivableSettlementBatchSize,
+		})).
+		Return(make([]db.Receivable, 2), nil)
+
+	settler := NewReceivableSettler(store)
+	settler.now = func() time.Time { return now }
+
+	settled, err := settler.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, settled)
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "picpay_simplificado/db/sqlc"
+	"time"
+)
+
+const reserveReleaseBatchSize = 100
+
+// ReserveReleaser gives merchants back what is left of their reserves once
+// their hold days are over
+type ReserveReleaser struct {
+	batchWorker
+	store db.Store
+}
+
+// NewReserveReleaser creates a new ReserveReleaser
+func NewReserveReleaser(store db.Store) *ReserveReleaser {
+	return &ReserveReleaser{
+		batchWorker: newBatchWorker("release reserves"),
+		store:       store,
+	}
+}
+
+// Run releases due reserves every interval until the context is done
+func (releaser *ReserveReleaser) Run(ctx context.Context, interval time.Duration) {
+	releaser.run(ctx, interval, releaser.ReleaseDue)
+}
+
+// ReleaseDue releases every due reserve, one batch per transaction
+func (releaser *ReserveReleaser) ReleaseDue(ctx context.Context) (int, error) {
+	now := releaser.now()
+
+	return drainBatches(ctx, reserveReleaseBatchSize, func(ctx context.Context) ([]db.Reserve, error) {
+		return releaser.store.ReleaseReservesTx(ctx, db.ReleaseReservesTxParams{
+			Now:       now,
+			BatchSize: reserveReleaseBatchSize,
+		})
+	})
+}
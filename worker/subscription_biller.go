@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+// SubscriptionBiller charges the subscriptions whose period ended for the
+// next one. Many billers may run at once, each due subscription is claimed
+// by only one of them
+type SubscriptionBiller struct {
+	store         db.Store
+	location      *time.Location
+	maxAttempts   int32
+	retryInterval time.Duration
+	now           func() time.Time
+}
+
+// NewSubscriptionBiller creates a new SubscriptionBiller. Monthly periods
+// end in the timezone of the limit schedule
+func NewSubscriptionBiller(store db.Store, config util.Config, schedule util.LimitSchedule) *SubscriptionBiller {
+	return &SubscriptionBiller{
+		store:         store,
+		location:      schedule.Location,
+		maxAttempts:   config.SubscriptionMaxAttempts,
+		retryInterval: config.SubscriptionRetryInterval,
+		now:           time.Now,
+	}
+}
+
+// Run renews due subscriptions every interval until the context is done
+func (biller *SubscriptionBiller) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := biller.RenewDue(ctx); err != nil {
+				log.Println("cannot renew subscriptions:", err)
+			}
+		}
+	}
+}
+
+// RenewDue renews every due subscription, one per transaction
+func (biller *SubscriptionBiller) RenewDue(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		_, err := biller.store.RenewSubscriptionTx(ctx, db.RenewSubscriptionTxParams{
+			Now:           biller.now(),
+			Location:      biller.location,
+			MaxAttempts:   biller.maxAttempts,
+			RetryInterval: biller.retryInterval,
+		})
+		if err == sql.ErrNoRows {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		total++
+	}
+}
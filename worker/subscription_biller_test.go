@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewDueSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	config := util.Config{
+		SubscriptionMaxAttempts:   4,
+		SubscriptionRetryInterval: 48 * time.Hour,
+	}
+	schedule := util.LimitSchedule{Location: time.UTC}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			RenewSubscriptionTx(gomock.Any(), gomock.Eq(db.RenewSubscriptionTxParams{
+				Now:           now,
+				Location:      time.UTC,
+				MaxAttempts:   4,
+				RetryInterval: 48 * time.Hour,
+			})).
+			Times(3).
+			Return(db.RenewSubscriptionTxResult{}, nil),
+		store.EXPECT().
+			RenewSubscriptionTx(gomock.Any(), gomock.Any()).
+			Return(db.RenewSubscriptionTxResult{}, sql.ErrNoRows),
+	)
+
+	biller := NewSubscriptionBiller(store, config, schedule)
+	biller.now = func() time.Time { return now }
+
+	renewed, err := biller.RenewDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, renewed)
+
+	store.EXPECT().
+		RenewSubscriptionTx(gomock.Any(), gomock.Any()).
+		Return(db.RenewSubscriptionTxResult{}, errors.New("connection refused"))
+
+	_, err = biller.RenewDue(context.Background())
+	require.Error(t, err)
+}
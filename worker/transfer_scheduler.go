@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"time"
+)
+
+// TransferScheduler runs the scheduled transfers when they are due. Many
+// schedulers may run at once, each due transfer is claimed by only one of them
+type TransferScheduler struct {
+	store         db.Store
+	risk          db.RiskEvaluator
+	defaults      util.TransferLimits
+	schedule      util.LimitSchedule
+	revenueOwner  string
+	maxAttempts   int32
+	retryInterval time.Duration
+	now           func() time.Time
+}
+
+// NewTransferScheduler creates a new TransferScheduler. Scheduled transfers
+// go through the same limits, risk rules and fees as the ones made through the API
+func NewTransferScheduler(store db.Store, config util.Config, schedule util.LimitSchedule, risk db.RiskEvaluator) *TransferScheduler {
+	return &TransferScheduler{
+		store:         store,
+		risk:          risk,
+		defaults:      config.TransferLimits(),
+		schedule:      schedule,
+		revenueOwner:  config.PlatformRevenueOwner,
+		maxAttempts:   config.ScheduledTransferMaxAttempts,
+		retryInterval: config.ScheduledTransferRetryInterval,
+		now:           time.Now,
+	}
+}
+
+// Run executes due transfers every interval until the context is done
+func (scheduler *TransferScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := scheduler.ExecuteDue(ctx); err != nil {
+				log.Println("cannot execute scheduled transfers:", err)
+			}
+		}
+	}
+}
+
+// ExecuteDue executes every due transfer, one per transaction
+func (scheduler *TransferScheduler) ExecuteDue(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		now := scheduler.now()
+
+		_, err := scheduler.store.ExecuteScheduledTransferTx(ctx, db.ExecuteScheduledTransferTxParams{
+			Now:           now,
+			Location:      scheduler.schedule.Location,
+			MaxAttempts:   scheduler.maxAttempts,
+			RetryInterval: scheduler.retryInterval,
+			Limits: &db.TransferLimitsParams{
+				Defaults: scheduler.defaults,
+				Schedule: scheduler.schedule,
+				Now:      now,
+			},
+			Risk: scheduler.risk,
+			Fees: db.NewFeeParams(scheduler.revenueOwner, now),
+		})
+		if err == sql.ErrNoRows {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		total++
+	}
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteDueScheduledTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	config := util.Config{
+		LimitDaily:                     5000,
+		ScheduledTransferMaxAttempts:   3,
+		ScheduledTransferRetryInterval: time.Hour,
+	}
+	schedule := util.LimitSchedule{Location: time.UTC}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Any()).
+			Times(2).
+			DoAndReturn(func(_ context.Context, arg db.ExecuteScheduledTransferTxParams) (db.ExecuteScheduledTransferTxResult, error) {
+				require.Equal(t, now, arg.Now)
+				require.Equal(t, time.UTC, arg.Location)
+				require.Equal(t, int32(3), arg.MaxAttempts)
+				require.Equal(t, time.Hour, arg.RetryInterval)
+				require.Equal(t, int64(5000), arg.Limits.Defaults.Daily)
+				return db.ExecuteScheduledTransferTxResult{}, nil
+			}),
+		store.EXPECT().
+			ExecuteScheduledTransferTx(gomock.Any(), gomock.Any()).
+			Return(db.ExecuteScheduledTransferTxResult{}, sql.ErrNoRows),
+	)
+
+	scheduler := NewTransferScheduler(store, config, schedule, nil)
+	scheduler.now = func() time.Time { return now }
+
+	executed, err := scheduler.ExecuteDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, executed)
+
+	store.EXPECT().
+		ExecuteScheduledTransferTx(gomock.Any(), gomock.Any()).
+		Return(db.ExecuteScheduledTransferTxResult{}, errors.New("connection refused"))
+
+	_, err = scheduler.ExecuteDue(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"strconv"
+	"time"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	deliveryStatusPending = "pending"
+	deliveryStatusFailed  = "failed"
+
+	webhookBatchSize   = 50
+	webhookLease       = 2 * time.Minute
+	webhookTimeout     = 10 * time.Second
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 12 * time.Hour
+)
+
+// WebhookDispatcher delivers the webhook outbox to merchant endpoints. Every
+// delivery is retried with exponential backoff until it succeeds or runs out of
+// attempts, so events are delivered at least once
+type WebhookDispatcher struct {
+	store                db.Store
+	client               *http.Client
+	maxAttempts          int32
+	disableAfterFailures int32
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher
+func NewWebhookDispatcher(store db.Store, config util.Config) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		store:                store,
+		client:               &http.Client{Timeout: webhookTimeout},
+		maxAttempts:          config.WebhookMaxAttempts,
+		disableAfterFailures: config.WebhookDisableAfterFailures,
+	}
+}
+
+// Run dispatches due deliveries every interval until the context is done
+func (dispatcher *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := dispatcher.DispatchDue(ctx); err != nil {
+				log.Println("cannot dispatch webhooks:", err)
+			}
+		}
+	}
+}
+
+// DispatchDue claims the pending deliveries that are due and sends them. The
+// claim leases the rows, so other instances skip them while they are in flight
+func (dispatcher *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := dispatcher.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(webhookLease),
+		BatchSize:  webhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := dispatcher.Deliver(ctx, delivery); err != nil {
+			return 0, fmt.Errorf("delivery [%d]: %w", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+type webhookPayload struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Deliver sends a single delivery, logs the attempt and schedules a retry on failure
+func (dispatcher *WebhookDispatcher) Deliver(ctx context.Context, delivery db.WebhookDelivery) error {
+	endpoint, err := dispatcher.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	if !endpoint.Enabled {
+		_, err = dispatcher.store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+			ID:            delivery.ID,
+			Status:        deliveryStatusFailed,
+			NextAttemptAt: delivery.NextAttemptAt,
+			LastError:     "endpoint is disabled",
+		})
+		return err
+	}
+
+	event, err := dispatcher.store.GetWebhookEvent(ctx, delivery.EventID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	responseStatus, sendErr := dispatcher.send(ctx, endpoint, event, delivery, body)
+
+	attempt := db.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:     delivery.ID,
+		ResponseStatus: int32(responseStatus),
+		DurationMs:     time.Since(start).Milliseconds(),
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+
+	if _, err := dispatcher.store.CreateWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		return err
+	}
+
+	if sendErr == nil {
+		if _, err := dispatcher.store.MarkWebhookDeliverySucceeded(ctx, delivery.ID); err != nil {
+			return err
+		}
+		return dispatcher.store.ResetWebhookEndpointFailures(ctx, endpoint.ID)
+	}
+
+	attempts := delivery.Attempts + 1
+	status := deliveryStatusPending
+	if attempts >= dispatcher.maxAttempts {
+		status = deliveryStatusFailed
+	}
+
+	_, err = dispatcher.store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		ID:            delivery.ID,
+		Status:        status,
+		NextAttemptAt: time.Now().Add(Backoff(attempts)),
+		LastError:     sendErr.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint, err = dispatcher.store.IncrementWebhookEndpointFailures(ctx, endpoint.ID)
+	if err != nil {
+		return err
+	}
+
+	if endpoint.ConsecutiveFailures >= dispatcher.disableAfterFailures {
+		return dispatcher.store.DisableWebhookEndpoint(ctx, endpoint.ID)
+	}
+
+	return nil
+}
+
+func (dispatcher *WebhookDispatcher) send(
+	ctx context.Context,
+	endpoint db.WebhookEndpoint,
+	event db.WebhookEvent,
+	delivery db.WebhookDelivery,
+	body []byte,
+) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhookEventHeader, event.EventType)
+	request.Header.Set(webhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(webhookSignatureHeader, fmt.Sprintf("t=%s,v1=%s", timestamp, SignWebhookPayload(endpoint.Secret, timestamp, body)))
+
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, errors.New(response.Status)
+	}
+
+	return response.StatusCode, nil
+}
+
+// SignWebhookPayload computes the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+// that merchants use to check a webhook came from us
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns how long to wait before the next try after the given
+// number of failed attempts, doubling every time up to a limit
+func Backoff(attempts int32) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+
+	return backoff
+}
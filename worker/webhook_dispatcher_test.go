@@ -0,0 +1,206 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "picpay_simplificado/db/mock"
+	db "picpay_simplificado/db/sqlc"
+	"picpay_simplificado/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverWebhook(t *testing.T) {
+	event := db.WebhookEvent{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     util.RandomString(6),
+		EventType: util.EventTransferReceived,
+		Data:      json.RawMessage(`{"amount":10}`),
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		statusCode    int
+		attempts      int32
+		failures      int32
+		buildStubs    func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery)
+		checkRequests func(t *testing.T, requests int)
+	}{
+		{
+			name:       "Delivered",
+			statusCode: http.StatusOK,
+			buildStubs: func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+				store.EXPECT().CreateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryAttemptParams) (db.WebhookDeliveryAttempt, error) {
+						require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+						require.Empty(t, arg.Error)
+						return db.WebhookDeliveryAttempt{}, nil
+					})
+				store.EXPECT().MarkWebhookDeliverySucceeded(gomock.Any(), gomock.Eq(delivery.ID)).Times(1)
+				store.EXPECT().ResetWebhookEndpointFailures(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkRequests: func(t *testing.T, requests int) {
+				require.Equal(t, 1, requests)
+			},
+		},
+		{
+			name:       "ScheduleRetry",
+			statusCode: http.StatusInternalServerError,
+			attempts:   2,
+			buildStubs: func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+				store.EXPECT().CreateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().MarkWebhookDeliverySucceeded(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+						require.Equal(t, deliveryStatusPending, arg.Status)
+						require.WithinDuration(t, time.Now().Add(Backoff(3)), arg.NextAttemptAt, time.Second)
+						require.NotEmpty(t, arg.LastError)
+						return delivery, nil
+					})
+				endpoint.ConsecutiveFailures++
+				store.EXPECT().IncrementWebhookEndpointFailures(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkRequests: func(t *testing.T, requests int) {
+				require.Equal(t, 1, requests)
+			},
+		},
+		{
+			name:       "GiveUpAfterMaxAttempts",
+			statusCode: http.StatusBadRequest,
+			attempts:   4,
+			buildStubs: func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+				store.EXPECT().CreateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+						require.Equal(t, deliveryStatusFailed, arg.Status)
+						return delivery, nil
+					})
+				endpoint.ConsecutiveFailures++
+				store.EXPECT().IncrementWebhookEndpointFailures(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkRequests: func(t *testing.T, requests int) {
+				require.Equal(t, 1, requests)
+			},
+		},
+		{
+			name:       "DisableEndpoint",
+			statusCode: http.StatusServiceUnavailable,
+			failures:   2,
+			buildStubs: func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+				store.EXPECT().CreateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(1)
+				endpoint.ConsecutiveFailures++
+				store.EXPECT().IncrementWebhookEndpointFailures(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1)
+			},
+			checkRequests: func(t *testing.T, requests int) {
+				require.Equal(t, 1, requests)
+			},
+		},
+		{
+			name: "EndpointDisabled",
+			buildStubs: func(store *mockdb.MockStore, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) {
+				endpoint.Enabled = false
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+						require.Equal(t, deliveryStatusFailed, arg.Status)
+						return delivery, nil
+					})
+			},
+			checkRequests: func(t *testing.T, requests int) {
+				require.Zero(t, requests)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			secret := util.RandomString(32)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, event.EventType, r.Header.Get(webhookEventHeader))
+
+				parts := strings.Split(r.Header.Get(webhookSignatureHeader), ",")
+				require.Len(t, parts, 2)
+				timestamp := strings.TrimPrefix(parts[0], "t=")
+				require.Equal(t, "v1="+SignWebhookPayload(secret, timestamp, body), parts[1])
+
+				var payload webhookPayload
+				require.NoError(t, json.Unmarshal(body, &payload))
+				require.Equal(t, event.ID, payload.ID)
+				require.JSONEq(t, string(event.Data), string(payload.Data))
+
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			endpoint := db.WebhookEndpoint{
+				ID:                  util.RandomInt(1, 1000),
+				Owner:               event.Owner,
+				Url:                 server.URL,
+				Secret:              secret,
+				EventTypes:          []string{event.EventType},
+				Enabled:             true,
+				ConsecutiveFailures: tc.failures,
+			}
+			delivery := db.WebhookDelivery{
+				ID:         util.RandomInt(1, 1000),
+				EndpointID: endpoint.ID,
+				EventID:    event.ID,
+				Status:     deliveryStatusPending,
+				Attempts:   tc.attempts,
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, endpoint, delivery)
+
+			dispatcher := NewWebhookDispatcher(store, util.Config{
+				WebhookMaxAttempts:          5,
+				WebhookDisableAfterFailures: 3,
+			})
+
+			err := dispatcher.Deliver(context.Background(), delivery)
+			require.NoError(t, err)
+			tc.checkRequests(t, requests)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, webhookBaseBackoff, Backoff(1))
+	require.Equal(t, 2*webhookBaseBackoff, Backoff(2))
+	require.Equal(t, 8*webhookBaseBackoff, Backoff(4))
+	require.Equal(t, webhookMaxBackoff, Backoff(100))
+}